@@ -0,0 +1,1169 @@
+// gRPC management API for the UFO MCP server. It offers the operations of the
+// MCP tools of the same names and runs them through the same implementation,
+// so policy rules, read-only mode and the audit log apply alike.
+//
+// Regenerate the Go code after changing this file:
+//
+//   protoc --go_out=. --go_opt=paths=source_relative \
+//     --go-grpc_out=. --go-grpc_opt=paths=source_relative api/ufo/v1/ufo.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: api/ufo/v1/ufo.proto
+
+package ufov1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Morph fades a ring in and out
+type Morph struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BrightnessMs int32 `protobuf:"varint,1,opt,name=brightness_ms,json=brightnessMs,proto3" json:"brightness_ms,omitempty"` // time at full brightness
+	FadeMs       int32 `protobuf:"varint,2,opt,name=fade_ms,json=fadeMs,proto3" json:"fade_ms,omitempty"`                   // fade transition, 100-10000
+}
+
+func (x *Morph) Reset() {
+	*x = Morph{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_ufo_v1_ufo_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Morph) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Morph) ProtoMessage() {}
+
+func (x *Morph) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ufo_v1_ufo_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Morph.ProtoReflect.Descriptor instead.
+func (*Morph) Descriptor() ([]byte, []int) {
+	return file_api_ufo_v1_ufo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Morph) GetBrightnessMs() int32 {
+	if x != nil {
+		return x.BrightnessMs
+	}
+	return 0
+}
+
+func (x *Morph) GetFadeMs() int32 {
+	if x != nil {
+		return x.FadeMs
+	}
+	return 0
+}
+
+// Gradient blends smoothly from one color to another, one color per LED
+type Gradient struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From   string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To     string `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Start  *int32 `protobuf:"varint,3,opt,name=start,proto3,oneof" json:"start,omitempty"`   // first LED, default 0
+	Length *int32 `protobuf:"varint,4,opt,name=length,proto3,oneof" json:"length,omitempty"` // LEDs spanned, 2-15, default 15
+	Easing string `protobuf:"bytes,5,opt,name=easing,proto3" json:"easing,omitempty"`        // linear, easeIn, easeOut or easeInOut
+}
+
+func (x *Gradient) Reset() {
+	*x = Gradient{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_ufo_v1_ufo_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Gradient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Gradient) ProtoMessage() {}
+
+func (x *Gradient) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ufo_v1_ufo_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Gradient.ProtoReflect.Descriptor instead.
+func (*Gradient) Descriptor() ([]byte, []int) {
+	return file_api_ufo_v1_ufo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Gradient) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *Gradient) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *Gradient) GetStart() int32 {
+	if x != nil && x.Start != nil {
+		return *x.Start
+	}
+	return 0
+}
+
+func (x *Gradient) GetLength() int32 {
+	if x != nil && x.Length != nil {
+		return *x.Length
+	}
+	return 0
+}
+
+func (x *Gradient) GetEasing() string {
+	if x != nil {
+		return x.Easing
+	}
+	return ""
+}
+
+// RingConfig configures one ring; see the configureLighting tool
+type RingConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Segments         []string  `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"`     // "position|length|color"
+	Palette          string    `protobuf:"bytes,2,opt,name=palette,proto3" json:"palette,omitempty"`       // saved palette filling the ring
+	Background       string    `protobuf:"bytes,3,opt,name=background,proto3" json:"background,omitempty"` // color of unlit LEDs
+	Whirl            *int32    `protobuf:"varint,4,opt,name=whirl,proto3,oneof" json:"whirl,omitempty"`    // rotation speed in ms, 0-510
+	CounterClockwise bool      `protobuf:"varint,5,opt,name=counter_clockwise,json=counterClockwise,proto3" json:"counter_clockwise,omitempty"`
+	Morph            *Morph    `protobuf:"bytes,6,opt,name=morph,proto3" json:"morph,omitempty"`
+	Gradient         *Gradient `protobuf:"bytes,7,opt,name=gradient,proto3" json:"gradient,omitempty"`            // instead of segments or palette
+	Brightness       *int32    `protobuf:"varint,8,opt,name=brightness,proto3,oneof" json:"brightness,omitempty"` // ring brightness in percent, 1-100
+}
+
+func (x *RingConfig) Reset() {
+	*x = RingConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_ufo_v1_ufo_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RingConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RingConfig) ProtoMessage() {}
+
+func (x *RingConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ufo_v1_ufo_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RingConfig.ProtoReflect.Descriptor instead.
+func (*RingConfig) Descriptor() ([]byte, []int) {
+	return file_api_ufo_v1_ufo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RingConfig) GetSegments() []string {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+func (x *RingConfig) GetPalette() string {
+	if x != nil {
+		return x.Palette
+	}
+	return ""
+}
+
+func (x *RingConfig) GetBackground() string {
+	if x != nil {
+		return x.Background
+	}
+	return ""
+}
+
+func (x *RingConfig) GetWhirl() int32 {
+	if x != nil && x.Whirl != nil {
+		return *x.Whirl
+	}
+	return 0
+}
+
+func (x *RingConfig) GetCounterClockwise() bool {
+	if x != nil {
+		return x.CounterClockwise
+	}
+	return false
+}
+
+func (x *RingConfig) GetMorph() *Morph {
+	if x != nil {
+		return x.Morph
+	}
+	return nil
+}
+
+func (x *RingConfig) GetGradient() *Gradient {
+	if x != nil {
+		return x.Gradient
+	}
+	return nil
+}
+
+func (x *RingConfig) GetBrightness() int32 {
+	if x != nil && x.Brightness != nil {
+		return *x.Brightness
+	}
+	return 0
+}
+
+// LogoConfig configures the logo
+type LogoConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State     string `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"` // "on" or "off"
+	Color1    string `protobuf:"bytes,2,opt,name=color1,proto3" json:"color1,omitempty"`
+	Color2    string `protobuf:"bytes,3,opt,name=color2,proto3" json:"color2,omitempty"`
+	Animation string `protobuf:"bytes,4,opt,name=animation,proto3" json:"animation,omitempty"` // blink, pulse, alternate or none
+	PeriodMs  *int32 `protobuf:"varint,5,opt,name=period_ms,json=periodMs,proto3,oneof" json:"period_ms,omitempty"`
+}
+
+func (x *LogoConfig) Reset() {
+	*x = LogoConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_ufo_v1_ufo_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogoConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoConfig) ProtoMessage() {}
+
+func (x *LogoConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ufo_v1_ufo_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoConfig.ProtoReflect.Descriptor instead.
+func (*LogoConfig) Descriptor() ([]byte, []int) {
+	return file_api_ufo_v1_ufo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LogoConfig) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *LogoConfig) GetColor1() string {
+	if x != nil {
+		return x.Color1
+	}
+	return ""
+}
+
+func (x *LogoConfig) GetColor2() string {
+	if x != nil {
+		return x.Color2
+	}
+	return ""
+}
+
+func (x *LogoConfig) GetAnimation() string {
+	if x != nil {
+		return x.Animation
+	}
+	return ""
+}
+
+func (x *LogoConfig) GetPeriodMs() int32 {
+	if x != nil && x.PeriodMs != nil {
+		return *x.PeriodMs
+	}
+	return 0
+}
+
+type ConfigureLightingRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Top            *RingConfig `protobuf:"bytes,1,opt,name=top,proto3" json:"top,omitempty"`
+	Bottom         *RingConfig `protobuf:"bytes,2,opt,name=bottom,proto3" json:"bottom,omitempty"`
+	Both           *RingConfig `protobuf:"bytes,3,opt,name=both,proto3" json:"both,omitempty"` // both rings; not with top or bottom
+	MirrorBottom   bool        `protobuf:"varint,4,opt,name=mirror_bottom,json=mirrorBottom,proto3" json:"mirror_bottom,omitempty"`
+	Logo           *LogoConfig `protobuf:"bytes,5,opt,name=logo,proto3" json:"logo,omitempty"`
+	Brightness     *int32      `protobuf:"varint,6,opt,name=brightness,proto3,oneof" json:"brightness,omitempty"` // 0-255
+	ApplyAndVerify bool        `protobuf:"varint,7,opt,name=apply_and_verify,json=applyAndVerify,proto3" json:"apply_and_verify,omitempty"`
+	Passthrough    bool        `protobuf:"varint,8,opt,name=passthrough,proto3" json:"passthrough,omitempty"`
+}
+
+func (x *ConfigureLightingRequest) Reset() {
+	*x = ConfigureLightingRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_ufo_v1_ufo_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfigureLightingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigureLightingRequest) ProtoMessage() {}
+
+func (x *ConfigureLightingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ufo_v1_ufo_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigureLightingRequest.ProtoReflect.Descriptor instead.
+func (*ConfigureLightingRequest) Descriptor() ([]byte, []int) {
+	return file_api_ufo_v1_ufo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ConfigureLightingRequest) GetTop() *RingConfig {
+	if x != nil {
+		return x.Top
+	}
+	return nil
+}
+
+func (x *ConfigureLightingRequest) GetBottom() *RingConfig {
+	if x != nil {
+		return x.Bottom
+	}
+	return nil
+}
+
+func (x *ConfigureLightingRequest) GetBoth() *RingConfig {
+	if x != nil {
+		return x.Both
+	}
+	return nil
+}
+
+func (x *ConfigureLightingRequest) GetMirrorBottom() bool {
+	if x != nil {
+		return x.MirrorBottom
+	}
+	return false
+}
+
+func (x *ConfigureLightingRequest) GetLogo() *LogoConfig {
+	if x != nil {
+		return x.Logo
+	}
+	return nil
+}
+
+func (x *ConfigureLightingRequest) GetBrightness() int32 {
+	if x != nil && x.Brightness != nil {
+		return *x.Brightness
+	}
+	return 0
+}
+
+func (x *ConfigureLightingRequest) GetApplyAndVerify() bool {
+	if x != nil {
+		return x.ApplyAndVerify
+	}
+	return false
+}
+
+func (x *ConfigureLightingRequest) GetPassthrough() bool {
+	if x != nil {
+		return x.Passthrough
+	}
+	return false
+}
+
+type PlayEffectRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name       string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	DurationMs *int64            `protobuf:"varint,2,opt,name=duration_ms,json=durationMs,proto3,oneof" json:"duration_ms,omitempty"`                                                        // overrides the effect's duration
+	Params     map[string]string `protobuf:"bytes,3,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // values of a template effect's parameters
+	Background bool              `protobuf:"varint,4,opt,name=background,proto3" json:"background,omitempty"`                                                                                // insert beneath the current effect
+}
+
+func (x *PlayEffectRequest) Reset() {
+	*x = PlayEffectRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_ufo_v1_ufo_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayEffectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayEffectRequest) ProtoMessage() {}
+
+func (x *PlayEffectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ufo_v1_ufo_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayEffectRequest.ProtoReflect.Descriptor instead.
+func (*PlayEffectRequest) Descriptor() ([]byte, []int) {
+	return file_api_ufo_v1_ufo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PlayEffectRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PlayEffectRequest) GetDurationMs() int64 {
+	if x != nil && x.DurationMs != nil {
+		return *x.DurationMs
+	}
+	return 0
+}
+
+func (x *PlayEffectRequest) GetParams() map[string]string {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *PlayEffectRequest) GetBackground() bool {
+	if x != nil {
+		return x.Background
+	}
+	return false
+}
+
+type StopEffectRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceId string `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"` // stack entry to stop; empty stops the current effect
+}
+
+func (x *StopEffectRequest) Reset() {
+	*x = StopEffectRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_ufo_v1_ufo_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StopEffectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopEffectRequest) ProtoMessage() {}
+
+func (x *StopEffectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ufo_v1_ufo_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopEffectRequest.ProtoReflect.Descriptor instead.
+func (*StopEffectRequest) Descriptor() ([]byte, []int) {
+	return file_api_ufo_v1_ufo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StopEffectRequest) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+type CallToolRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string           `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Arguments *structpb.Struct `protobuf:"bytes,2,opt,name=arguments,proto3" json:"arguments,omitempty"`
+}
+
+func (x *CallToolRequest) Reset() {
+	*x = CallToolRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_ufo_v1_ufo_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CallToolRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CallToolRequest) ProtoMessage() {}
+
+func (x *CallToolRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ufo_v1_ufo_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CallToolRequest.ProtoReflect.Descriptor instead.
+func (*CallToolRequest) Descriptor() ([]byte, []int) {
+	return file_api_ufo_v1_ufo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CallToolRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CallToolRequest) GetArguments() *structpb.Struct {
+	if x != nil {
+		return x.Arguments
+	}
+	return nil
+}
+
+// ToolResult is what the tool reported. A tool that fails reports is_error
+// with the reason in text; the call itself still succeeds.
+type ToolResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text    string `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	IsError bool   `protobuf:"varint,2,opt,name=is_error,json=isError,proto3" json:"is_error,omitempty"`
+}
+
+func (x *ToolResult) Reset() {
+	*x = ToolResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_ufo_v1_ufo_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ToolResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolResult) ProtoMessage() {}
+
+func (x *ToolResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ufo_v1_ufo_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolResult.ProtoReflect.Descriptor instead.
+func (*ToolResult) Descriptor() ([]byte, []int) {
+	return file_api_ufo_v1_ufo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ToolResult) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ToolResult) GetIsError() bool {
+	if x != nil {
+		return x.IsError
+	}
+	return false
+}
+
+type StreamEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Types []string `protobuf:"bytes,1,rep,name=types,proto3" json:"types,omitempty"` // event types to stream; empty streams all
+}
+
+func (x *StreamEventsRequest) Reset() {
+	*x = StreamEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_ufo_v1_ufo_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsRequest) ProtoMessage() {}
+
+func (x *StreamEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ufo_v1_ufo_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsRequest.ProtoReflect.Descriptor instead.
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return file_api_ufo_v1_ufo_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StreamEventsRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+// Event is a state change published by the server
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type      string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Data      *structpb.Struct       `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	RequestId string                 `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"` // tool call that caused the event, if any
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_ufo_v1_ufo_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ufo_v1_ufo_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_api_ufo_v1_ufo_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Event) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Event) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+var File_api_ufo_v1_ufo_proto protoreflect.FileDescriptor
+
+var file_api_ufo_v1_ufo_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x61, 0x70, 0x69, 0x2f, 0x75, 0x66, 0x6f, 0x2f, 0x76, 0x31, 0x2f, 0x75, 0x66, 0x6f,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x75, 0x66, 0x6f, 0x2e, 0x76, 0x31, 0x1a, 0x1c,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
+	0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x45, 0x0a,
+	0x05, 0x4d, 0x6f, 0x72, 0x70, 0x68, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x72, 0x69, 0x67, 0x68, 0x74,
+	0x6e, 0x65, 0x73, 0x73, 0x5f, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x62,
+	0x72, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x4d, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x66,
+	0x61, 0x64, 0x65, 0x5f, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x66, 0x61,
+	0x64, 0x65, 0x4d, 0x73, 0x22, 0x93, 0x01, 0x0a, 0x08, 0x47, 0x72, 0x61, 0x64, 0x69, 0x65, 0x6e,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x19, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x88, 0x01, 0x01,
+	0x12, 0x1b, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x48, 0x01, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x16, 0x0a,
+	0x06, 0x65, 0x61, 0x73, 0x69, 0x6e, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x65,
+	0x61, 0x73, 0x69, 0x6e, 0x67, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x22, 0xbb, 0x02, 0x0a, 0x0a, 0x52,
+	0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x67,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x67,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x6c, 0x65, 0x74, 0x74, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x6c, 0x65, 0x74, 0x74, 0x65, 0x12,
+	0x1e, 0x0a, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x67, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x67, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x12,
+	0x19, 0x0a, 0x05, 0x77, 0x68, 0x69, 0x72, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00,
+	0x52, 0x05, 0x77, 0x68, 0x69, 0x72, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x65, 0x72, 0x5f, 0x63, 0x6c, 0x6f, 0x63, 0x6b, 0x77, 0x69, 0x73, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x43, 0x6c,
+	0x6f, 0x63, 0x6b, 0x77, 0x69, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x05, 0x6d, 0x6f, 0x72, 0x70, 0x68,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x75, 0x66, 0x6f, 0x2e, 0x76, 0x31, 0x2e,
+	0x4d, 0x6f, 0x72, 0x70, 0x68, 0x52, 0x05, 0x6d, 0x6f, 0x72, 0x70, 0x68, 0x12, 0x2c, 0x0a, 0x08,
+	0x67, 0x72, 0x61, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x75, 0x66, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x61, 0x64, 0x69, 0x65, 0x6e, 0x74,
+	0x52, 0x08, 0x67, 0x72, 0x61, 0x64, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x23, 0x0a, 0x0a, 0x62, 0x72,
+	0x69, 0x67, 0x68, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01,
+	0x52, 0x0a, 0x62, 0x72, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x88, 0x01, 0x01, 0x42,
+	0x08, 0x0a, 0x06, 0x5f, 0x77, 0x68, 0x69, 0x72, 0x6c, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x62, 0x72,
+	0x69, 0x67, 0x68, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x22, 0xa0, 0x01, 0x0a, 0x0a, 0x4c, 0x6f, 0x67,
+	0x6f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x31, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63,
+	0x6f, 0x6c, 0x6f, 0x72, 0x31, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x32, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x32, 0x12, 0x1c, 0x0a,
+	0x09, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x09, 0x70,
+	0x65, 0x72, 0x69, 0x6f, 0x64, 0x5f, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00,
+	0x52, 0x08, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a,
+	0x0a, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x5f, 0x6d, 0x73, 0x22, 0xe1, 0x02, 0x0a, 0x18,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x65, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x69, 0x6e,
+	0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x03, 0x74, 0x6f, 0x70, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x75, 0x66, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x03, 0x74, 0x6f, 0x70, 0x12, 0x2a,
+	0x0a, 0x06, 0x62, 0x6f, 0x74, 0x74, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x75, 0x66, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x52, 0x06, 0x62, 0x6f, 0x74, 0x74, 0x6f, 0x6d, 0x12, 0x26, 0x0a, 0x04, 0x62, 0x6f,
+	0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x75, 0x66, 0x6f, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x04, 0x62, 0x6f,
+	0x74, 0x68, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x62, 0x6f, 0x74,
+	0x74, 0x6f, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x6d, 0x69, 0x72, 0x72, 0x6f,
+	0x72, 0x42, 0x6f, 0x74, 0x74, 0x6f, 0x6d, 0x12, 0x26, 0x0a, 0x04, 0x6c, 0x6f, 0x67, 0x6f, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x75, 0x66, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x6f, 0x67, 0x6f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x04, 0x6c, 0x6f, 0x67, 0x6f, 0x12,
+	0x23, 0x0a, 0x0a, 0x62, 0x72, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x0a, 0x62, 0x72, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x65, 0x73,
+	0x73, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x10, 0x61, 0x70, 0x70, 0x6c, 0x79, 0x5f, 0x61, 0x6e,
+	0x64, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e,
+	0x61, 0x70, 0x70, 0x6c, 0x79, 0x41, 0x6e, 0x64, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x12, 0x20,
+	0x0a, 0x0b, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0b, 0x70, 0x61, 0x73, 0x73, 0x74, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68,
+	0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x62, 0x72, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x22,
+	0xf7, 0x01, 0x0a, 0x11, 0x50, 0x6c, 0x61, 0x79, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x0b, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00,
+	0x52, 0x0a, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x12,
+	0x3d, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x25, 0x2e, 0x75, 0x66, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x45, 0x66, 0x66,
+	0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x1e,
+	0x0a, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x67, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x67, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x1a, 0x39,
+	0x0a, 0x0b, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x73, 0x22, 0x34, 0x0a, 0x11, 0x53, 0x74, 0x6f,
+	0x70, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f,
+	0x0a, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x22,
+	0x5c, 0x0a, 0x0f, 0x43, 0x61, 0x6c, 0x6c, 0x54, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x09, 0x61, 0x72, 0x67, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75,
+	0x63, 0x74, 0x52, 0x09, 0x61, 0x72, 0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x3b, 0x0a,
+	0x0a, 0x54, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x69, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x2b, 0x0a, 0x13, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x22, 0xa1, 0x01, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12,
+	0x2b, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1d, 0x0a, 0x0a,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x32, 0xc8, 0x02, 0x0a, 0x0a,
+	0x55, 0x66, 0x6f, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x49, 0x0a, 0x11, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x65, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x69, 0x6e, 0x67, 0x12,
+	0x20, 0x2e, 0x75, 0x66, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
+	0x72, 0x65, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x12, 0x2e, 0x75, 0x66, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x6f, 0x6c, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x3b, 0x0a, 0x0a, 0x50, 0x6c, 0x61, 0x79, 0x45, 0x66, 0x66,
+	0x65, 0x63, 0x74, 0x12, 0x19, 0x2e, 0x75, 0x66, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61,
+	0x79, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12,
+	0x2e, 0x75, 0x66, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x3b, 0x0a, 0x0a, 0x53, 0x74, 0x6f, 0x70, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74,
+	0x12, 0x19, 0x2e, 0x75, 0x66, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x45, 0x66,
+	0x66, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x75, 0x66,
+	0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12,
+	0x37, 0x0a, 0x08, 0x43, 0x61, 0x6c, 0x6c, 0x54, 0x6f, 0x6f, 0x6c, 0x12, 0x17, 0x2e, 0x75, 0x66,
+	0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x6c, 0x6c, 0x54, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x75, 0x66, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f,
+	0x6f, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x3c, 0x0a, 0x0c, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1b, 0x2e, 0x75, 0x66, 0x6f, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0d, 0x2e, 0x75, 0x66, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x74, 0x61, 0x72, 0x73, 0x70, 0x61, 0x63, 0x65, 0x34, 0x36,
+	0x2f, 0x75, 0x66, 0x6f, 0x2d, 0x6d, 0x63, 0x70, 0x2d, 0x67, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f,
+	0x75, 0x66, 0x6f, 0x2f, 0x76, 0x31, 0x3b, 0x75, 0x66, 0x6f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_ufo_v1_ufo_proto_rawDescOnce sync.Once
+	file_api_ufo_v1_ufo_proto_rawDescData = file_api_ufo_v1_ufo_proto_rawDesc
+)
+
+func file_api_ufo_v1_ufo_proto_rawDescGZIP() []byte {
+	file_api_ufo_v1_ufo_proto_rawDescOnce.Do(func() {
+		file_api_ufo_v1_ufo_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_ufo_v1_ufo_proto_rawDescData)
+	})
+	return file_api_ufo_v1_ufo_proto_rawDescData
+}
+
+var file_api_ufo_v1_ufo_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_api_ufo_v1_ufo_proto_goTypes = []any{
+	(*Morph)(nil),                    // 0: ufo.v1.Morph
+	(*Gradient)(nil),                 // 1: ufo.v1.Gradient
+	(*RingConfig)(nil),               // 2: ufo.v1.RingConfig
+	(*LogoConfig)(nil),               // 3: ufo.v1.LogoConfig
+	(*ConfigureLightingRequest)(nil), // 4: ufo.v1.ConfigureLightingRequest
+	(*PlayEffectRequest)(nil),        // 5: ufo.v1.PlayEffectRequest
+	(*StopEffectRequest)(nil),        // 6: ufo.v1.StopEffectRequest
+	(*CallToolRequest)(nil),          // 7: ufo.v1.CallToolRequest
+	(*ToolResult)(nil),               // 8: ufo.v1.ToolResult
+	(*StreamEventsRequest)(nil),      // 9: ufo.v1.StreamEventsRequest
+	(*Event)(nil),                    // 10: ufo.v1.Event
+	nil,                              // 11: ufo.v1.PlayEffectRequest.ParamsEntry
+	(*structpb.Struct)(nil),          // 12: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil),    // 13: google.protobuf.Timestamp
+}
+var file_api_ufo_v1_ufo_proto_depIdxs = []int32{
+	0,  // 0: ufo.v1.RingConfig.morph:type_name -> ufo.v1.Morph
+	1,  // 1: ufo.v1.RingConfig.gradient:type_name -> ufo.v1.Gradient
+	2,  // 2: ufo.v1.ConfigureLightingRequest.top:type_name -> ufo.v1.RingConfig
+	2,  // 3: ufo.v1.ConfigureLightingRequest.bottom:type_name -> ufo.v1.RingConfig
+	2,  // 4: ufo.v1.ConfigureLightingRequest.both:type_name -> ufo.v1.RingConfig
+	3,  // 5: ufo.v1.ConfigureLightingRequest.logo:type_name -> ufo.v1.LogoConfig
+	11, // 6: ufo.v1.PlayEffectRequest.params:type_name -> ufo.v1.PlayEffectRequest.ParamsEntry
+	12, // 7: ufo.v1.CallToolRequest.arguments:type_name -> google.protobuf.Struct
+	13, // 8: ufo.v1.Event.timestamp:type_name -> google.protobuf.Timestamp
+	12, // 9: ufo.v1.Event.data:type_name -> google.protobuf.Struct
+	4,  // 10: ufo.v1.UfoService.ConfigureLighting:input_type -> ufo.v1.ConfigureLightingRequest
+	5,  // 11: ufo.v1.UfoService.PlayEffect:input_type -> ufo.v1.PlayEffectRequest
+	6,  // 12: ufo.v1.UfoService.StopEffect:input_type -> ufo.v1.StopEffectRequest
+	7,  // 13: ufo.v1.UfoService.CallTool:input_type -> ufo.v1.CallToolRequest
+	9,  // 14: ufo.v1.UfoService.StreamEvents:input_type -> ufo.v1.StreamEventsRequest
+	8,  // 15: ufo.v1.UfoService.ConfigureLighting:output_type -> ufo.v1.ToolResult
+	8,  // 16: ufo.v1.UfoService.PlayEffect:output_type -> ufo.v1.ToolResult
+	8,  // 17: ufo.v1.UfoService.StopEffect:output_type -> ufo.v1.ToolResult
+	8,  // 18: ufo.v1.UfoService.CallTool:output_type -> ufo.v1.ToolResult
+	10, // 19: ufo.v1.UfoService.StreamEvents:output_type -> ufo.v1.Event
+	15, // [15:20] is the sub-list for method output_type
+	10, // [10:15] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_api_ufo_v1_ufo_proto_init() }
+func file_api_ufo_v1_ufo_proto_init() {
+	if File_api_ufo_v1_ufo_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_ufo_v1_ufo_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Morph); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_ufo_v1_ufo_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*Gradient); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_ufo_v1_ufo_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*RingConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_ufo_v1_ufo_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*LogoConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_ufo_v1_ufo_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*ConfigureLightingRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_ufo_v1_ufo_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*PlayEffectRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_ufo_v1_ufo_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*StopEffectRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_ufo_v1_ufo_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*CallToolRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_ufo_v1_ufo_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*ToolResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_ufo_v1_ufo_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*StreamEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_ufo_v1_ufo_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_api_ufo_v1_ufo_proto_msgTypes[1].OneofWrappers = []any{}
+	file_api_ufo_v1_ufo_proto_msgTypes[2].OneofWrappers = []any{}
+	file_api_ufo_v1_ufo_proto_msgTypes[3].OneofWrappers = []any{}
+	file_api_ufo_v1_ufo_proto_msgTypes[4].OneofWrappers = []any{}
+	file_api_ufo_v1_ufo_proto_msgTypes[5].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_ufo_v1_ufo_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_ufo_v1_ufo_proto_goTypes,
+		DependencyIndexes: file_api_ufo_v1_ufo_proto_depIdxs,
+		MessageInfos:      file_api_ufo_v1_ufo_proto_msgTypes,
+	}.Build()
+	File_api_ufo_v1_ufo_proto = out.File
+	file_api_ufo_v1_ufo_proto_rawDesc = nil
+	file_api_ufo_v1_ufo_proto_goTypes = nil
+	file_api_ufo_v1_ufo_proto_depIdxs = nil
+}
@@ -0,0 +1,321 @@
+// gRPC management API for the UFO MCP server. It offers the operations of the
+// MCP tools of the same names and runs them through the same implementation,
+// so policy rules, read-only mode and the audit log apply alike.
+//
+// Regenerate the Go code after changing this file:
+//
+//   protoc --go_out=. --go_opt=paths=source_relative \
+//     --go-grpc_out=. --go-grpc_opt=paths=source_relative api/ufo/v1/ufo.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: api/ufo/v1/ufo.proto
+
+package ufov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	UfoService_ConfigureLighting_FullMethodName = "/ufo.v1.UfoService/ConfigureLighting"
+	UfoService_PlayEffect_FullMethodName        = "/ufo.v1.UfoService/PlayEffect"
+	UfoService_StopEffect_FullMethodName        = "/ufo.v1.UfoService/StopEffect"
+	UfoService_CallTool_FullMethodName          = "/ufo.v1.UfoService/CallTool"
+	UfoService_StreamEvents_FullMethodName      = "/ufo.v1.UfoService/StreamEvents"
+)
+
+// UfoServiceClient is the client API for UfoService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UfoService controls the UFO
+type UfoServiceClient interface {
+	// ConfigureLighting sets the rings, logo and brightness in one command, as
+	// the configureLighting tool does
+	ConfigureLighting(ctx context.Context, in *ConfigureLightingRequest, opts ...grpc.CallOption) (*ToolResult, error)
+	// PlayEffect plays a stored effect by name, as the playEffect tool does
+	PlayEffect(ctx context.Context, in *PlayEffectRequest, opts ...grpc.CallOption) (*ToolResult, error)
+	// StopEffect stops the current effect, or the stack entry with the given
+	// instance ID, as the stopEffect tool does
+	StopEffect(ctx context.Context, in *StopEffectRequest, opts ...grpc.CallOption) (*ToolResult, error)
+	// CallTool calls any MCP tool the server offers with JSON-style arguments,
+	// for operations without a dedicated method
+	CallTool(ctx context.Context, in *CallToolRequest, opts ...grpc.CallOption) (*ToolResult, error)
+	// StreamEvents streams the server's events as they are published, those
+	// the ufo://events/recent resource lists, until the client cancels
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (UfoService_StreamEventsClient, error)
+}
+
+type ufoServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUfoServiceClient(cc grpc.ClientConnInterface) UfoServiceClient {
+	return &ufoServiceClient{cc}
+}
+
+func (c *ufoServiceClient) ConfigureLighting(ctx context.Context, in *ConfigureLightingRequest, opts ...grpc.CallOption) (*ToolResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ToolResult)
+	err := c.cc.Invoke(ctx, UfoService_ConfigureLighting_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ufoServiceClient) PlayEffect(ctx context.Context, in *PlayEffectRequest, opts ...grpc.CallOption) (*ToolResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ToolResult)
+	err := c.cc.Invoke(ctx, UfoService_PlayEffect_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ufoServiceClient) StopEffect(ctx context.Context, in *StopEffectRequest, opts ...grpc.CallOption) (*ToolResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ToolResult)
+	err := c.cc.Invoke(ctx, UfoService_StopEffect_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ufoServiceClient) CallTool(ctx context.Context, in *CallToolRequest, opts ...grpc.CallOption) (*ToolResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ToolResult)
+	err := c.cc.Invoke(ctx, UfoService_CallTool_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ufoServiceClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (UfoService_StreamEventsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UfoService_ServiceDesc.Streams[0], UfoService_StreamEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ufoServiceStreamEventsClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type UfoService_StreamEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type ufoServiceStreamEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *ufoServiceStreamEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UfoServiceServer is the server API for UfoService service.
+// All implementations must embed UnimplementedUfoServiceServer
+// for forward compatibility
+//
+// UfoService controls the UFO
+type UfoServiceServer interface {
+	// ConfigureLighting sets the rings, logo and brightness in one command, as
+	// the configureLighting tool does
+	ConfigureLighting(context.Context, *ConfigureLightingRequest) (*ToolResult, error)
+	// PlayEffect plays a stored effect by name, as the playEffect tool does
+	PlayEffect(context.Context, *PlayEffectRequest) (*ToolResult, error)
+	// StopEffect stops the current effect, or the stack entry with the given
+	// instance ID, as the stopEffect tool does
+	StopEffect(context.Context, *StopEffectRequest) (*ToolResult, error)
+	// CallTool calls any MCP tool the server offers with JSON-style arguments,
+	// for operations without a dedicated method
+	CallTool(context.Context, *CallToolRequest) (*ToolResult, error)
+	// StreamEvents streams the server's events as they are published, those
+	// the ufo://events/recent resource lists, until the client cancels
+	StreamEvents(*StreamEventsRequest, UfoService_StreamEventsServer) error
+	mustEmbedUnimplementedUfoServiceServer()
+}
+
+// UnimplementedUfoServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedUfoServiceServer struct {
+}
+
+func (UnimplementedUfoServiceServer) ConfigureLighting(context.Context, *ConfigureLightingRequest) (*ToolResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfigureLighting not implemented")
+}
+func (UnimplementedUfoServiceServer) PlayEffect(context.Context, *PlayEffectRequest) (*ToolResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlayEffect not implemented")
+}
+func (UnimplementedUfoServiceServer) StopEffect(context.Context, *StopEffectRequest) (*ToolResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopEffect not implemented")
+}
+func (UnimplementedUfoServiceServer) CallTool(context.Context, *CallToolRequest) (*ToolResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CallTool not implemented")
+}
+func (UnimplementedUfoServiceServer) StreamEvents(*StreamEventsRequest, UfoService_StreamEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedUfoServiceServer) mustEmbedUnimplementedUfoServiceServer() {}
+
+// UnsafeUfoServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UfoServiceServer will
+// result in compilation errors.
+type UnsafeUfoServiceServer interface {
+	mustEmbedUnimplementedUfoServiceServer()
+}
+
+func RegisterUfoServiceServer(s grpc.ServiceRegistrar, srv UfoServiceServer) {
+	s.RegisterService(&UfoService_ServiceDesc, srv)
+}
+
+func _UfoService_ConfigureLighting_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfigureLightingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UfoServiceServer).ConfigureLighting(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UfoService_ConfigureLighting_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UfoServiceServer).ConfigureLighting(ctx, req.(*ConfigureLightingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UfoService_PlayEffect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayEffectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UfoServiceServer).PlayEffect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UfoService_PlayEffect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UfoServiceServer).PlayEffect(ctx, req.(*PlayEffectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UfoService_StopEffect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopEffectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UfoServiceServer).StopEffect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UfoService_StopEffect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UfoServiceServer).StopEffect(ctx, req.(*StopEffectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UfoService_CallTool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CallToolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UfoServiceServer).CallTool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UfoService_CallTool_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UfoServiceServer).CallTool(ctx, req.(*CallToolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UfoService_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UfoServiceServer).StreamEvents(m, &ufoServiceStreamEventsServer{ServerStream: stream})
+}
+
+type UfoService_StreamEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type ufoServiceStreamEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *ufoServiceStreamEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// UfoService_ServiceDesc is the grpc.ServiceDesc for UfoService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UfoService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ufo.v1.UfoService",
+	HandlerType: (*UfoServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ConfigureLighting",
+			Handler:    _UfoService_ConfigureLighting_Handler,
+		},
+		{
+			MethodName: "PlayEffect",
+			Handler:    _UfoService_PlayEffect_Handler,
+		},
+		{
+			MethodName: "StopEffect",
+			Handler:    _UfoService_StopEffect_Handler,
+		},
+		{
+			MethodName: "CallTool",
+			Handler:    _UfoService_CallTool_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _UfoService_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/ufo/v1/ufo.proto",
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/devices"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/logging"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/starspace46/ufo-mcp-go/internal/tools"
+)
+
+// commands are the subcommands run in place of the server, each returning
+// the process exit code
+var commands = map[string]func(args []string, stdout, stderr io.Writer) int{
+	"config": runConfigCommand,
+	"send":   runSendCommand,
+	"play":   runPlayCommand,
+	"state":  runStateCommand,
+}
+
+// parseCommand parses a subcommand's flags, which are the server's flags
+// plus those extra defines, and sets up logging to stderr so output can be
+// piped. It returns the options and the remaining arguments, or false after
+// reporting a problem on stderr.
+func parseCommand(name, usage string, args []string, stderr io.Writer, extra func(fs *flag.FlagSet)) (*options, []string, bool) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: ufo-mcp %s %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+	o := &options{}
+	o.define(fs)
+	if extra != nil {
+		extra(fs)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, false
+	}
+	if err := o.validate(); err != nil {
+		fmt.Fprintf(stderr, "Invalid configuration: %v\n", err)
+		return nil, nil, false
+	}
+	logger, err := logging.New(stderr, o.logLevel, o.logFormat)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return nil, nil, false
+	}
+	slog.SetDefault(logger)
+	return o, fs.Args(), true
+}
+
+// commandClient connects to the UFO the options select, as the server does
+func (o *options) commandClient() *device.Client {
+	if o.devicesFile == "" {
+		o.devicesFile = filepath.Join(filepath.Dir(o.effectsFile), "devices.json")
+	}
+	registry := devices.NewRegistry(o.devicesFile)
+	if err := registry.Load(); err != nil {
+		slog.Warn("Failed to load UFO nicknames", "file", o.devicesFile, "error", err)
+	}
+	o.selectUFO(registry)
+	return o.newDeviceClient(registry)
+}
+
+// commandContext is cancelled on SIGINT or SIGTERM
+func commandContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+}
+
+// runSendCommand runs "send QUERY": it sends a raw query to the UFO and
+// prints the reply
+func runSendCommand(args []string, stdout, stderr io.Writer) int {
+	o, args, ok := parseCommand("send", "[flags] QUERY", args, stderr, nil)
+	if !ok {
+		return 2
+	}
+	if len(args) != 1 {
+		fmt.Fprintln(stderr, "usage: ufo-mcp send [flags] QUERY, e.g. ufo-mcp send \"top=0|15|FF0000\"")
+		return 2
+	}
+	deviceClient := o.commandClient()
+
+	ctx, cancel := commandContext()
+	defer cancel()
+	reply, err := deviceClient.SendRawQuery(ctx, args[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to send query: %v\n", err)
+		return 1
+	}
+	warning, err := device.CheckReply(reply)
+	fmt.Fprintln(stdout, reply)
+	if err != nil {
+		fmt.Fprintf(stderr, "The UFO rejected the query: %v\n", err)
+		return 1
+	}
+	if warning != "" {
+		fmt.Fprintf(stderr, "Warning: %s\n", warning)
+	}
+	return 0
+}
+
+// runStateCommand runs "state": it prints the LED state the UFO reports as
+// JSON
+func runStateCommand(args []string, stdout, stderr io.Writer) int {
+	o, args, ok := parseCommand("state", "[flags]", args, stderr, nil)
+	if !ok {
+		return 2
+	}
+	if len(args) != 0 {
+		fmt.Fprintln(stderr, "usage: ufo-mcp state [flags]")
+		return 2
+	}
+	deviceClient := o.commandClient()
+
+	ctx, cancel := commandContext()
+	defer cancel()
+	status, err := deviceClient.FetchStatus(ctx)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to read the UFO state: %v\n", err)
+		return 1
+	}
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintln(stdout, string(data))
+	return 0
+}
+
+// runPlayCommand runs "play NAME [PARAM=VALUE...]": it plays a stored effect
+// as the playEffect tool does. An effect that animates or ends after a
+// duration needs the process, so the command stays in the foreground until
+// the effect ends, or until interrupted for one that runs until stopped.
+func runPlayCommand(args []string, stdout, stderr io.Writer) int {
+	var duration int
+	o, args, ok := parseCommand("play", "[flags] NAME [PARAM=VALUE...]", args, stderr, func(fs *flag.FlagSet) {
+		fs.IntVar(&duration, "duration", -1, "Duration in milliseconds, overriding the effect's own (0 runs until stopped)")
+	})
+	if !ok {
+		return 2
+	}
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: ufo-mcp play [flags] NAME [PARAM=VALUE...], e.g. ufo-mcp play rainbow")
+		return 2
+	}
+
+	arguments := map[string]interface{}{"name": args[0]}
+	if duration >= 0 {
+		arguments["duration"] = duration
+	}
+	if len(args) > 1 {
+		params := map[string]interface{}{}
+		for _, arg := range args[1:] {
+			name, value, found := strings.Cut(arg, "=")
+			if !found || name == "" {
+				fmt.Fprintf(stderr, "Invalid parameter %q: use NAME=VALUE\n", arg)
+				return 2
+			}
+			params[name] = value
+		}
+		arguments["params"] = params
+	}
+
+	deviceClient := o.commandClient()
+	effectsStore := effects.NewStore(o.effectsFile)
+	if err := effectsStore.Load(); err != nil {
+		fmt.Fprintf(stderr, "Failed to load effects: %v\n", err)
+		return 1
+	}
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	deviceClient.SetRingBrightness(stateManager.RingBrightness)
+	engine := effects.NewEngine(deviceClient)
+	engine.SetCallTimeout(o.callTimeout)
+
+	ctx, cancel := commandContext()
+	defer cancel()
+	result, err := tools.NewPlayEffectTool(deviceClient, broadcaster, effectsStore, stateManager, engine, tools.NewRestores(engine, broadcaster, stateManager)).Execute(ctx, arguments)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if result.IsError {
+		fmt.Fprintln(stderr, text)
+		return 1
+	}
+	fmt.Fprintln(stdout, text)
+
+	// Wait for the timer that ends the effect, and keep animating until
+	// interrupted
+	for engine.Timers() > 0 && effects.Sleep(ctx, 100*time.Millisecond) {
+	}
+	if engine.Running() != "" {
+		<-ctx.Done()
+	}
+	shutdown, done := context.WithTimeout(context.Background(), time.Second)
+	defer done()
+	engine.Shutdown(shutdown)
+	return 0
+}
+
+// exitCommand runs the subcommand named by the first argument, if any, and
+// exits with its code
+func exitCommand(args []string) {
+	if len(args) == 0 {
+		return
+	}
+	if run, ok := commands[args[0]]; ok {
+		os.Exit(run(args[1:], os.Stdout, os.Stderr))
+	}
+}
@@ -0,0 +1,603 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/auth"
+	"github.com/starspace46/ufo-mcp-go/internal/cors"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/devices"
+	"github.com/starspace46/ufo-mcp-go/internal/discovery"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/homeassistant"
+	"github.com/starspace46/ufo-mcp-go/internal/hooks"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+	"github.com/starspace46/ufo-mcp-go/internal/logging"
+	"github.com/starspace46/ufo-mcp-go/internal/policy"
+	"github.com/starspace46/ufo-mcp-go/internal/redact"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+	"github.com/starspace46/ufo-mcp-go/internal/tlscert"
+	"github.com/starspace46/ufo-mcp-go/internal/vumeter"
+	"github.com/starspace46/ufo-mcp-go/internal/webhook"
+)
+
+// options are the server's settings. Each is taken from its command line
+// flag, else from its environment variables (see optionEnv), else from its
+// default, so a container can be configured through the environment alone.
+type options struct {
+	transport           string
+	port                string
+	grpcPort            string
+	tlsCert             string
+	tlsKey              string
+	tlsSelfSigned       bool
+	corsOrigins         string
+	corsHeaders         string
+	corsMethods         string
+	ufoIP               string
+	effectsFile         string
+	devicesFile         string
+	palettesFile        string
+	macrosFile          string
+	scenesFile          string
+	stateHistoryFile    string
+	stackFilePath       string
+	maxStackDepth       int
+	stackOverflow       string
+	effectRevisionsFile string
+	pollInterval        time.Duration
+	buttonAction        string
+	hooksFile           string
+	channelsFile        string
+	auditLogFile        string
+	auditLogMaxSize     int
+	auditLogKeep        int
+	policyFile          string
+	integrationsFile    string
+	dynatraceConfig     string
+	webhookFile         string
+	mqttConfig          string
+	enableVUMeter       bool
+	vuMeterTimeout      time.Duration
+	enableDynatrace     bool
+	retryAttempts       int
+	retryBackoff        time.Duration
+	callTimeout         time.Duration
+	offlineAfter        int
+	maxConcurrent       int
+	requestsPerSecond   float64
+	authTokens          string
+	enableEffectCRUD    bool
+	readOnly            bool
+	redactParams        string
+	showVersion         bool
+	discover            bool
+	simulate            bool
+	logLevel            string
+	logFormat           string
+	timeZone            string
+	onShutdown          string
+	configuredUFOs      []string // --ufo-ip as given, before selectUFO picks one
+}
+
+// optionEnv lists the environment variables each flag falls back to, in
+// order of precedence
+var optionEnv = map[string][]string{
+	"transport":               {"UFO_TRANSPORT"},
+	"port":                    {"UFO_PORT"},
+	"grpc-port":               {"UFO_GRPC_PORT"},
+	"tls-cert":                {"UFO_TLS_CERT"},
+	"tls-key":                 {"UFO_TLS_KEY"},
+	"tls-self-signed":         {"UFO_TLS_SELF_SIGNED"},
+	"cors-origins":            {"UFO_CORS_ORIGINS"},
+	"cors-headers":            {"UFO_CORS_HEADERS"},
+	"cors-methods":            {"UFO_CORS_METHODS"},
+	"ufo-ip":                  {"UFO_IP"},
+	"simulate":                {"UFO_SIMULATE"},
+	"effects-file":            {"UFO_EFFECTS_FILE"},
+	"devices-file":            {"UFO_DEVICES_FILE"},
+	"palettes-file":           {"UFO_PALETTES_FILE"},
+	"macros-file":             {"UFO_MACROS_FILE"},
+	"effect-revisions-file":   {"UFO_EFFECT_REVISIONS_FILE"},
+	"state-history-file":      {"UFO_STATE_HISTORY_FILE"},
+	"scenes-file":             {"UFO_SCENES_FILE"},
+	"stack-file":              {"UFO_STACK_FILE"},
+	"max-stack-depth":         {"UFO_MAX_STACK_DEPTH"},
+	"stack-overflow":          {"UFO_STACK_OVERFLOW"},
+	"poll-interval":           {"UFO_POLL_INTERVAL"},
+	"button-action":           {"UFO_BUTTON_ACTION"},
+	"hooks-file":              {"UFO_HOOKS_FILE"},
+	"channels-file":           {"UFO_CHANNELS_FILE"},
+	"audit-log":               {"UFO_AUDIT_LOG"},
+	"audit-log-max-size":      {"UFO_AUDIT_LOG_MAX_SIZE"},
+	"audit-log-keep":          {"UFO_AUDIT_LOG_KEEP"},
+	"policy-file":             {"UFO_POLICY_FILE"},
+	"integrations-file":       {"UFO_INTEGRATIONS_FILE"},
+	"dynatrace-config":        {"UFO_DYNATRACE_CONFIG"},
+	"webhook-file":            {"UFO_WEBHOOK_FILE"},
+	"mqtt-config":             {"UFO_MQTT_CONFIG"},
+	"vu-meter":                {"UFO_VU_METER"},
+	"vu-meter-timeout":        {"UFO_VU_METER_TIMEOUT"},
+	"enable-dynatrace":        {"UFO_ENABLE_DYNATRACE"},
+	"retry-attempts":          {"UFO_RETRY_ATTEMPTS"},
+	"retry-backoff":           {"UFO_RETRY_BACKOFF"},
+	"device-call-timeout":     {"UFO_DEVICE_CALL_TIMEOUT"},
+	"offline-after":           {"UFO_OFFLINE_AFTER"},
+	"max-concurrent-requests": {"UFO_MAX_CONCURRENT_REQUESTS"},
+	"max-requests-per-second": {"UFO_MAX_REQUESTS_PER_SECOND"},
+	"auth-token":              {"UFO_AUTH_TOKEN"},
+	"enable-effect-crud":      {"UFO_ENABLE_EFFECT_CRUD"},
+	"read-only":               {"UFO_READ_ONLY"},
+	"redact-params":           {"UFO_REDACT_PARAMS"},
+	"discover":                {"UFO_DISCOVER"},
+	"log-level":               {"UFO_LOG_LEVEL", "LOG_LEVEL"},
+	"timezone":                {"UFO_TIMEZONE"},
+	"log-format":              {"UFO_LOG_FORMAT"},
+	"on-shutdown":             {"UFO_ON_SHUTDOWN"},
+}
+
+// env returns the value of the first environment variable flag falls back
+// to that is set, or def
+func env(flag string, def string) string {
+	for _, key := range optionEnv[flag] {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return def
+}
+
+// define registers the server's flags on fs, with defaults taken from the
+// environment
+func (o *options) define(fs *flag.FlagSet) {
+	fs.StringVar(&o.transport, "t", env("transport", "stdio"), "Transport type (stdio or http)")
+	fs.StringVar(&o.transport, "transport", env("transport", "stdio"), "Transport type (stdio or http)")
+	fs.StringVar(&o.port, "port", env("port", "8080"), "HTTP port when using http transport")
+	fs.StringVar(&o.grpcPort, "grpc-port", env("grpc-port", ""), "Port for the gRPC management API defined in api/ufo/v1/ufo.proto, served with either transport (empty disables)")
+	fs.StringVar(&o.tlsCert, "tls-cert", env("tls-cert", ""), "PEM certificate file to serve the HTTP transport over TLS with; needs tls-key")
+	fs.StringVar(&o.tlsKey, "tls-key", env("tls-key", ""), "PEM private key file of tls-cert")
+	fs.BoolVar(&o.tlsSelfSigned, "tls-self-signed", envBool("UFO_TLS_SELF_SIGNED", false), "Serve the HTTP transport over TLS with a certificate generated at startup for this machine, when tls-cert is not set")
+	fs.StringVar(&o.corsOrigins, "cors-origins", env("cors-origins", ""), "Comma-separated browser origins allowed to call the HTTP endpoints, e.g. https://dash.example.com, https://*.example.com or * (empty disables CORS)")
+	fs.StringVar(&o.corsHeaders, "cors-headers", env("cors-headers", cors.DefaultHeaders), "Comma-separated request headers allowed from cors-origins")
+	fs.StringVar(&o.corsMethods, "cors-methods", env("cors-methods", cors.DefaultMethods), "Comma-separated methods allowed from cors-origins")
+	fs.StringVar(&o.ufoIP, "ufo-ip", env("ufo-ip", ""), "UFO device IP address or nickname; comma-separate several to use the first that answers")
+	fs.BoolVar(&o.simulate, "simulate", envBool("UFO_SIMULATE", false), "Drive an in-memory virtual UFO instead of real hardware, for demos, tests and effect development")
+	fs.StringVar(&o.effectsFile, "effects-file", env("effects-file", "/data/effects.json"), "Path to effects JSON file")
+	fs.StringVar(&o.devicesFile, "devices-file", env("devices-file", ""), "Path to JSON file of UFO nicknames and metadata (default: devices.json next to the effects file)")
+	fs.StringVar(&o.palettesFile, "palettes-file", env("palettes-file", ""), "Path to JSON file of saved color palettes (default: palettes.json next to the effects file)")
+	fs.StringVar(&o.macrosFile, "macros-file", env("macros-file", ""), "Path to JSON file of saved tool macros (default: macros.json next to the effects file)")
+	fs.StringVar(&o.effectRevisionsFile, "effect-revisions-file", env("effect-revisions-file", ""), "Path to JSON file keeping earlier versions of stored effects for rollbackEffect (default: effect-revisions.json next to the effects file)")
+	fs.StringVar(&o.stateHistoryFile, "state-history-file", env("state-history-file", ""), "Path to JSON file recording earlier UFO states for diffStates (default: state-history.json next to the effects file)")
+	fs.StringVar(&o.scenesFile, "scenes-file", env("scenes-file", ""), "Path to JSON file of saved lighting scenes (default: scenes.json next to the effects file)")
+	fs.StringVar(&o.stackFilePath, "stack-file", env("stack-file", ""), "Path to JSON file saving the effect stack so running effects resume after a restart (default: effect-stack.json next to the effects file)")
+	fs.IntVar(&o.maxStackDepth, "max-stack-depth", envInt("UFO_MAX_STACK_DEPTH", 100), "Most entries on the effect stack (0 means no limit)")
+	fs.StringVar(&o.stackOverflow, "stack-overflow", env("stack-overflow", state.OverflowDropOldest), "What playing an effect onto a full stack does: drop-oldest, reject or collapse-synthetic")
+	fs.DurationVar(&o.pollInterval, "poll-interval", envDuration("UFO_POLL_INTERVAL", 0), "Interval for polling the UFO to reconcile shadow state (0 disables)")
+	fs.StringVar(&o.buttonAction, "button-action", env("button-action", ""), "Tool called when the UFO's button is pressed, optionally followed by its JSON arguments, e.g. stopAllEffects or 'playEffect {\"name\":\"rainbow\"}'; presses are noticed by poll-interval (empty only publishes button_press)")
+	fs.StringVar(&o.hooksFile, "hooks-file", env("hooks-file", ""), "Path to JSON file defining external command hooks run on events")
+	fs.StringVar(&o.channelsFile, "channels-file", env("channels-file", ""), "Path to JSON file defining the status board channels shown with setChannelStatus")
+	fs.StringVar(&o.auditLogFile, "audit-log", env("audit-log", ""), "Path to append-only audit log (JSON lines); empty logs to stderr")
+	fs.IntVar(&o.auditLogMaxSize, "audit-log-max-size", envInt("UFO_AUDIT_LOG_MAX_SIZE", 10), "Size in MB at which the audit log is rotated (0 never rotates)")
+	fs.IntVar(&o.auditLogKeep, "audit-log-keep", envInt("UFO_AUDIT_LOG_KEEP", 5), "Rotated audit log files kept")
+	fs.StringVar(&o.policyFile, "policy-file", env("policy-file", ""), "Path to JSON file of CEL policy rules evaluated for every mutating tool call")
+	fs.StringVar(&o.integrationsFile, "integrations-file", env("integrations-file", ""), "Path to JSON file configuring alerting integrations (HTTP transport only)")
+	fs.StringVar(&o.dynatraceConfig, "dynatrace-config", env("dynatrace-config", ""), "Path to JSON file configuring the Dynatrace problems integration")
+	fs.StringVar(&o.webhookFile, "webhook-file", env("webhook-file", ""), "Path to JSON file mapping /webhook payloads to effects (HTTP transport only)")
+	fs.StringVar(&o.mqttConfig, "mqtt-config", env("mqtt-config", ""), "Path to JSON file configuring the MQTT broker the UFO joins Home Assistant through as a light entity")
+	fs.BoolVar(&o.enableVUMeter, "vu-meter", envBool("UFO_VU_METER", false), "Accept audio levels on /vumeter and show them as a VU meter on the rings (HTTP transport only)")
+	fs.DurationVar(&o.vuMeterTimeout, "vu-meter-timeout", envDuration("UFO_VU_METER_TIMEOUT", vumeter.DefaultIdleTimeout), "Time without audio levels after which the VU meter stops and the UFO shows what it showed before")
+	fs.BoolVar(&o.enableDynatrace, "enable-dynatrace", envBool("UFO_ENABLE_DYNATRACE", false), "Poll open Dynatrace problems and show them on the UFO (needs --dynatrace-config)")
+	fs.IntVar(&o.retryAttempts, "retry-attempts", envInt("UFO_RETRY_ATTEMPTS", 3), "Attempts per UFO request before giving up (1 disables retries)")
+	fs.DurationVar(&o.retryBackoff, "retry-backoff", envDuration("UFO_RETRY_BACKOFF", 100*time.Millisecond), "Delay before the first retry of a failed UFO request, doubled for each further retry")
+	fs.DurationVar(&o.callTimeout, "device-call-timeout", envDuration("UFO_DEVICE_CALL_TIMEOUT", effects.DefaultCallTimeout), "Longest a command to the UFO may take, retries and queueing included, before it is abandoned (0 means no limit)")
+	fs.IntVar(&o.offlineAfter, "offline-after", envInt("UFO_OFFLINE_AFTER", 5), "Consecutive failed UFO requests before the UFO is marked offline (0 disables)")
+	fs.IntVar(&o.maxConcurrent, "max-concurrent-requests", envInt("UFO_MAX_CONCURRENT_REQUESTS", device.DefaultMaxConcurrent), "Requests allowed in flight to the UFO at once; raise to 2-3 for firmware that handles parallel requests")
+	fs.Float64Var(&o.requestsPerSecond, "max-requests-per-second", envFloat("UFO_MAX_REQUESTS_PER_SECOND", device.DefaultRequestsPerSecond), "Writes sent to the UFO per second at most; bursts are queued and redundant consecutive writes merged (0 disables)")
+	fs.StringVar(&o.authTokens, "auth-token", env("auth-token", ""), "Bearer token or API key required on every HTTP endpoint except /healthz and /readyz, and on gRPC calls; comma-separate several to rotate (empty disables)")
+	fs.BoolVar(&o.enableEffectCRUD, "enable-effect-crud", envBool("UFO_ENABLE_EFFECT_CRUD", false), "Expose the addEffect, updateEffect, deleteEffect, importEffects, listEffectRevisions and rollbackEffect tools to MCP clients")
+	fs.BoolVar(&o.readOnly, "read-only", envBool("UFO_READ_ONLY", false), "Reject every tool call that could change the UFO, effects or integrations")
+	fs.StringVar(&o.redactParams, "redact-params", env("redact-params", ""), "Comma-separated regular expressions for extra query parameter names whose values are masked in logs, events and audit records")
+	fs.BoolVar(&o.showVersion, "version", false, "Print build information and exit")
+	fs.BoolVar(&o.discover, "discover", envBool("UFO_DISCOVER", true), "Scan the local network for a UFO at startup when no UFO IP is configured")
+	fs.StringVar(&o.logLevel, "log-level", env("log-level", "info"), "Minimum log level (debug, info, warn or error); debug logs every UFO request")
+	fs.StringVar(&o.timeZone, "timezone", env("timezone", ""), "IANA time zone for policy schedules and timestamps, e.g. Europe/Vienna (default: the server's local zone)")
+	fs.StringVar(&o.logFormat, "log-format", env("log-format", "text"), "Log output format (text or json)")
+	fs.StringVar(&o.onShutdown, "on-shutdown", env("on-shutdown", "leave"), "What the UFO shows once the server exits: leave (as it is), clear (rings off) or base (the bottom effect of the stack)")
+}
+
+// validate reports every setting the server cannot start with, including
+// environment values that are not valid for their type
+func (o *options) validate() error {
+	errs := append([]error(nil), invalidEnv...)
+	if o.transport != "stdio" && o.transport != "http" {
+		errs = append(errs, fmt.Errorf("invalid transport %q: use stdio or http", o.transport))
+	}
+	if err := validPort(o.port); err != nil {
+		errs = append(errs, fmt.Errorf("invalid port: %w", err))
+	}
+	if o.grpcPort != "" {
+		if err := validPort(o.grpcPort); err != nil {
+			errs = append(errs, fmt.Errorf("invalid gRPC port: %w", err))
+		} else if o.transport == "http" && o.grpcPort == o.port {
+			errs = append(errs, fmt.Errorf("the gRPC port %s is also the HTTP port", o.grpcPort))
+		}
+	}
+	if (o.tlsCert == "") != (o.tlsKey == "") {
+		errs = append(errs, fmt.Errorf("tls-cert and tls-key must be set together"))
+	} else if o.tlsCert != "" && o.tlsSelfSigned {
+		errs = append(errs, fmt.Errorf("tls-self-signed cannot be used with tls-cert"))
+	}
+	if _, err := o.corsPolicy(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid cors-origins: %w", err))
+	}
+	if _, err := logging.New(io.Discard, o.logLevel, o.logFormat); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := timezone.Load(o.timeZone); err != nil {
+		errs = append(errs, fmt.Errorf("invalid timezone: %w", err))
+	}
+	if _, err := redact.New(redact.ParsePatterns(o.redactParams)); err != nil {
+		errs = append(errs, fmt.Errorf("invalid redact-params: %w", err))
+	}
+	if o.onShutdown != "leave" && o.onShutdown != "clear" && o.onShutdown != "base" {
+		errs = append(errs, fmt.Errorf("invalid on-shutdown %q: use leave, clear or base", o.onShutdown))
+	}
+	if o.retryAttempts < 1 {
+		errs = append(errs, fmt.Errorf("retry-attempts must be at least 1, got %d", o.retryAttempts))
+	}
+	if o.retryBackoff < 0 || o.pollInterval < 0 || o.vuMeterTimeout < 0 || o.callTimeout < 0 {
+		errs = append(errs, fmt.Errorf("retry-backoff, poll-interval, vu-meter-timeout and device-call-timeout cannot be negative"))
+	}
+	if o.offlineAfter < 0 {
+		errs = append(errs, fmt.Errorf("offline-after cannot be negative, got %d", o.offlineAfter))
+	}
+	if o.auditLogMaxSize < 0 || o.auditLogKeep < 1 {
+		errs = append(errs, fmt.Errorf("audit-log-max-size cannot be negative and audit-log-keep must be at least 1"))
+	}
+	if o.maxStackDepth < 0 {
+		errs = append(errs, fmt.Errorf("max-stack-depth cannot be negative, got %d", o.maxStackDepth))
+	}
+	if !slices.Contains(state.OverflowPolicies, o.stackOverflow) {
+		errs = append(errs, fmt.Errorf("invalid stack-overflow %q: use %s", o.stackOverflow, strings.Join(state.OverflowPolicies, ", ")))
+	}
+	if o.maxConcurrent < 1 {
+		errs = append(errs, fmt.Errorf("max-concurrent-requests must be at least 1, got %d", o.maxConcurrent))
+	}
+	if o.requestsPerSecond < 0 {
+		errs = append(errs, fmt.Errorf("max-requests-per-second cannot be negative, got %v", o.requestsPerSecond))
+	}
+	if _, _, err := o.parseButtonAction(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid button-action: %w", err))
+	}
+	if o.enableDynatrace && o.dynatraceConfig == "" {
+		errs = append(errs, fmt.Errorf("enable-dynatrace needs dynatrace-config"))
+	}
+	return errors.Join(errs...)
+}
+
+// parseButtonAction splits --button-action into the tool to call and its
+// arguments; name is empty when no action is set
+func (o *options) parseButtonAction() (name string, arguments map[string]interface{}, err error) {
+	name, rest, _ := strings.Cut(strings.TrimSpace(o.buttonAction), " ")
+	arguments = map[string]interface{}{}
+	if rest = strings.TrimSpace(rest); rest != "" {
+		if err := json.Unmarshal([]byte(rest), &arguments); err != nil {
+			return "", nil, fmt.Errorf("arguments of %s must be a JSON object: %w", name, err)
+		}
+	}
+	return name, arguments, nil
+}
+
+// validPort checks that port is a TCP port number
+func validPort(port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("%q is not a port number between 1 and 65535", port)
+	}
+	return nil
+}
+
+// ufoAddresses splits the --ufo-ip list
+func (o *options) ufoAddresses() []string {
+	var addresses []string
+	for _, address := range strings.Split(o.ufoIP, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// dataFile returns the path of a data file: path when set, else name next
+// to the effects file
+func (o *options) dataFile(path, name string) string {
+	if path != "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(o.effectsFile), name)
+}
+
+// checkFiles loads the configuration files the options name, returning the
+// problems that would leave features out at startup and warnings about
+// settings that will have no effect
+func (o *options) checkFiles() (problems, warnings []string) {
+	problem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	warn := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	// Data files are created on first save, so they only need to be valid
+	// JSON when they exist
+	if _, err := os.Stat(filepath.Dir(o.effectsFile)); err != nil {
+		problem("effects directory: %v", err)
+	}
+	for _, file := range []struct{ name, path string }{
+		{"effects-file", o.effectsFile},
+		{"devices-file", o.dataFile(o.devicesFile, "devices.json")},
+		{"palettes-file", o.dataFile(o.palettesFile, "palettes.json")},
+		{"macros-file", o.dataFile(o.macrosFile, "macros.json")},
+		{"scenes-file", o.dataFile(o.scenesFile, "scenes.json")},
+		{"state-history-file", o.dataFile(o.stateHistoryFile, "state-history.json")},
+		{"stack-file", o.dataFile(o.stackFilePath, "effect-stack.json")},
+		{"effect-revisions-file", o.dataFile(o.effectRevisionsFile, "effect-revisions.json")},
+	} {
+		data, err := os.ReadFile(file.path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			problem("%s: %v", file.name, err)
+		} else if !json.Valid(data) {
+			problem("%s: %s is not valid JSON", file.name, file.path)
+		}
+	}
+
+	// Configuration files must load as they would at startup
+	loaders := []struct {
+		name, path string
+		load       func(path string) error
+	}{
+		{"hooks-file", o.hooksFile, func(path string) error { _, err := hooks.Load(path); return err }},
+		{"policy-file", o.policyFile, func(path string) error { _, err := policy.Load(path, nil); return err }},
+		{"integrations-file", o.integrationsFile, func(path string) error { _, err := integrations.LoadConfig(path); return err }},
+		{"dynatrace-config", o.dynatraceConfig, func(path string) error { _, err := integrations.LoadDynatraceConfig(path); return err }},
+		{"webhook-file", o.webhookFile, func(path string) error { _, err := webhook.Load(path); return err }},
+		{"mqtt-config", o.mqttConfig, func(path string) error { _, err := homeassistant.Load(path); return err }},
+		{"channels-file", o.channelsFile, func(path string) error {
+			cfg, err := integrations.LoadBoardConfig(path)
+			if err != nil {
+				return err
+			}
+			return cfg.Validate()
+		}},
+	}
+	for _, loader := range loaders {
+		if loader.path == "" {
+			continue
+		}
+		if err := loader.load(loader.path); err != nil {
+			problem("%s: %v", loader.name, err)
+		}
+	}
+	if o.auditLogFile != "" {
+		if _, err := os.Stat(filepath.Dir(o.auditLogFile)); err != nil {
+			problem("audit-log directory: %v", err)
+		}
+	}
+	if o.tlsCert != "" && o.tlsKey != "" {
+		if _, err := tlscert.Config(o.tlsCert, o.tlsKey); err != nil {
+			problem("tls-cert: %v", err)
+		}
+	}
+
+	if o.transport != "http" {
+		for _, option := range []struct {
+			name string
+			set  bool
+		}{
+			{"integrations-file", o.integrationsFile != ""},
+			{"webhook-file", o.webhookFile != ""},
+			{"vu-meter", o.enableVUMeter},
+			{"tls-cert", o.tlsCert != ""},
+			{"tls-self-signed", o.tlsSelfSigned},
+			{"cors-origins", o.corsOrigins != ""},
+		} {
+			if option.set {
+				warn("%s is only served with the http transport", option.name)
+			}
+		}
+		if o.authTokens != "" && o.grpcPort == "" {
+			warn("auth-token protects HTTP and gRPC endpoints, and neither is served")
+		}
+	} else if o.authTokens == "" {
+		warn("HTTP authentication is disabled; set auth-token to require a token")
+	}
+	if o.buttonAction != "" && o.pollInterval == 0 {
+		warn("button-action is set but poll-interval is not, so button presses are not noticed")
+	}
+	if o.dynatraceConfig != "" && !o.enableDynatrace {
+		warn("dynatrace-config is set but enable-dynatrace is not")
+	}
+	if o.simulate && o.ufoIP != "" {
+		warn("ufo-ip is ignored while simulating")
+	}
+	return problems, warnings
+}
+
+// corsPolicy returns the CORS policy of the HTTP endpoints, nil when no
+// origin is allowed
+func (o *options) corsPolicy() (*cors.Policy, error) {
+	return cors.New(cors.ParseList(o.corsOrigins), cors.ParseList(o.corsHeaders), cors.ParseList(o.corsMethods))
+}
+
+// selectUFO sets ufoIP to the UFO to use: the simulator, or else as
+// pickUFO says. The device client reads it from UFO_IP.
+func (o *options) selectUFO(registry *devices.Registry) {
+	o.configuredUFOs = o.ufoAddresses()
+	if o.simulate {
+		o.ufoIP = device.SimulatorAddress
+	} else {
+		o.ufoIP = o.pickUFO(registry)
+	}
+	os.Setenv("UFO_IP", o.ufoIP)
+}
+
+// pickUFO returns the first UFO listed in --ufo-ip that answers, with
+// nicknames resolved through registry, else one found on the network, else
+// the default host name. A SIGHUP picks again.
+func (o *options) pickUFO(registry *devices.Registry) string {
+	addresses := slices.Clone(o.configuredUFOs)
+	for i, name := range addresses {
+		if address, ok := registry.Resolve(name); ok && address != name {
+			slog.Info("Using UFO by nickname", "nickname", name, "address", address)
+			addresses[i] = address
+		}
+	}
+
+	address := chooseUFO(addresses)
+	if address == "" && o.discover {
+		address = discoverUFO()
+	}
+	if address == "" {
+		address = "ufo"
+	}
+	return address
+}
+
+// newDeviceClient creates a client for the selected UFO with the configured
+// retries, concurrency and rate limit, and the color correction registry
+// records for it
+func (o *options) newDeviceClient(registry *devices.Registry) *device.Client {
+	deviceClient := device.NewClient()
+	if o.simulate {
+		slog.Warn("Simulating a virtual UFO; no hardware will be contacted")
+		deviceClient = device.NewSimulatedClient(device.NewSimulator())
+	}
+	retryPolicy := device.DefaultRetryPolicy()
+	retryPolicy.MaxAttempts = o.retryAttempts
+	retryPolicy.BaseDelay = o.retryBackoff
+	retryPolicy.OfflineAfter = o.offlineAfter
+	deviceClient.SetRetryPolicy(retryPolicy)
+	deviceClient.SetMaxConcurrent(o.maxConcurrent)
+	deviceClient.SetRateLimit(o.requestsPerSecond)
+	if info, ok := registry.Get(o.ufoIP); ok && info.ColorCorrection != nil {
+		slog.Info("Applying color correction", "gamma", info.ColorCorrection.Gamma)
+		deviceClient.SetColorCorrection(info.ColorCorrection)
+	}
+	return deviceClient
+}
+
+// chooseUFO picks the UFO to use from the --ufo-ip list: the first address
+// that answers, or the first address when none does
+func chooseUFO(addresses []string) string {
+	if len(addresses) < 2 {
+		return strings.Join(addresses, "")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), healthProbeTimeout)
+	defer cancel()
+	if found := discovery.NewScanner().Probe(ctx, addresses); len(found) > 0 {
+		slog.Info("Using the first listed UFO that answers", "ip", found[0].IP)
+		return found[0].IP
+	}
+	slog.Warn("None of the listed UFOs answers; using the first", "ip", addresses[0])
+	return addresses[0]
+}
+
+// runConfigCommand runs "config check": it validates the configuration the
+// same flags and environment would start the server with, prints each
+// option with its value and where that came from, and returns the exit code
+func runConfigCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "check" {
+		fmt.Fprintln(stderr, "usage: ufo-mcp config check [flags]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("config check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var o options
+	o.define(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	explicit := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+	if explicit["t"] {
+		explicit["transport"] = true
+	}
+
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Name != "t" && f.Name != "version" {
+			names = append(names, f.Name)
+		}
+	})
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "OPTION\tVALUE\tSOURCE")
+	for _, name := range names {
+		value := fs.Lookup(name).Value.String()
+		if name == "auth-token" && value != "" {
+			value = fmt.Sprintf("(%d tokens)", len(auth.ParseTokens(value)))
+		}
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, value, optionSource(name, explicit[name]))
+	}
+	w.Flush()
+
+	var problems []string
+	if err := o.validate(); err != nil {
+		problems = strings.Split(err.Error(), "\n")
+	}
+	fileProblems, warnings := o.checkFiles()
+	problems = append(problems, fileProblems...)
+
+	fmt.Fprintln(stdout)
+	for _, warning := range warnings {
+		fmt.Fprintf(stdout, "warning: %s\n", warning)
+	}
+	for _, problem := range problems {
+		fmt.Fprintf(stdout, "error: %s\n", problem)
+	}
+	if len(problems) > 0 {
+		fmt.Fprintf(stdout, "Configuration has %d error(s)\n", len(problems))
+		return 1
+	}
+	fmt.Fprintln(stdout, "Configuration OK")
+	return 0
+}
+
+// optionSource describes where an option's value came from
+func optionSource(name string, explicit bool) string {
+	if explicit {
+		return "flag --" + name
+	}
+	for _, key := range optionEnv[name] {
+		if os.Getenv(key) != "" {
+			return "env " + key
+		}
+	}
+	return "default"
+}
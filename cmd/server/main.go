@@ -1,71 +1,266 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"syscall"
 	"time"
+	_ "time/tzdata" // time zones for --timezone in containers without zoneinfo
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/starspace46/ufo-mcp-go/internal/audit"
+	"github.com/starspace46/ufo-mcp-go/internal/auth"
+	"github.com/starspace46/ufo-mcp-go/internal/cors"
 	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/devices"
+	"github.com/starspace46/ufo-mcp-go/internal/discovery"
 	"github.com/starspace46/ufo-mcp-go/internal/effects"
 	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/features"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+	"github.com/starspace46/ufo-mcp-go/internal/grpcapi"
+	"github.com/starspace46/ufo-mcp-go/internal/homeassistant"
+	"github.com/starspace46/ufo-mcp-go/internal/hooks"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+	"github.com/starspace46/ufo-mcp-go/internal/logging"
+	"github.com/starspace46/ufo-mcp-go/internal/macros"
+	"github.com/starspace46/ufo-mcp-go/internal/metrics"
+	"github.com/starspace46/ufo-mcp-go/internal/palettes"
+	"github.com/starspace46/ufo-mcp-go/internal/policy"
+	"github.com/starspace46/ufo-mcp-go/internal/prompts"
+	"github.com/starspace46/ufo-mcp-go/internal/redact"
+	"github.com/starspace46/ufo-mcp-go/internal/scenes"
 	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+	"github.com/starspace46/ufo-mcp-go/internal/tlscert"
 	"github.com/starspace46/ufo-mcp-go/internal/tools"
 	"github.com/starspace46/ufo-mcp-go/internal/version"
+	"github.com/starspace46/ufo-mcp-go/internal/vumeter"
+	"github.com/starspace46/ufo-mcp-go/internal/webhook"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 )
 
 const (
-	ServerName    = "dynatrace-ufo"
-	ServerVersion = "1.0.0"
+	ServerName = "dynatrace-ufo"
 )
 
 func main() {
-	var transport string
-	var port string
-	var ufoIP string
-	var effectsFile string
-
-	flag.StringVar(&transport, "t", "stdio", "Transport type (stdio or http)")
-	flag.StringVar(&transport, "transport", "stdio", "Transport type (stdio or http)")
-	flag.StringVar(&port, "port", "8080", "HTTP port when using http transport")
-	flag.StringVar(&ufoIP, "ufo-ip", os.Getenv("UFO_IP"), "UFO device IP address")
-	flag.StringVar(&effectsFile, "effects-file", "/data/effects.json", "Path to effects JSON file")
+	// Subcommands such as "ufo-mcp send" run once instead of serving
+	exitCommand(os.Args[1:])
+
+	var opts options
+	opts.define(flag.CommandLine)
 	flag.Parse()
 
-	// Default UFO IP if not set
-	if ufoIP == "" {
-		ufoIP = "ufo"
-		os.Setenv("UFO_IP", ufoIP)
+	if opts.showVersion {
+		fmt.Println(version.String())
+		return
+	}
+
+	// Mask sensitive query values everywhere they are written
+	redactor, err := redact.New(redact.ParsePatterns(opts.redactParams))
+	if err != nil {
+		logging.Fatal("Invalid --redact-params", "error", err)
+	}
+	logger, err := logging.New(redactor.Writer(os.Stderr), opts.logLevel, opts.logFormat)
+	if err != nil {
+		logging.Fatal("Invalid logging configuration", "error", err)
+	}
+	slog.SetDefault(logger)
+
+	// Use the configured time zone for schedules and human-facing times
+	location, err := timezone.Load(opts.timeZone)
+	if err != nil {
+		logging.Fatal("Invalid --timezone", "error", err)
 	}
+	timezone.Set(location)
 
-	log.Printf("Starting MCP UFO Server")
-	log.Printf("UFO IP: %s", ufoIP)
-	log.Printf("Effects file: %s", effectsFile)
-	log.Printf("Transport: %s", transport)
+	if err := opts.validate(); err != nil {
+		logging.Fatal("Invalid configuration; run \"ufo-mcp config check\" for details", "error", err)
+	}
+
+	// Optional features whose data fails to load are left out, not fatal
+	featureRegistry := newFeatureRegistry()
+
+	// Load UFO nicknames, which --ufo-ip may refer to
+	if opts.devicesFile == "" {
+		opts.devicesFile = filepath.Join(filepath.Dir(opts.effectsFile), "devices.json")
+	}
+	deviceRegistry := devices.NewRegistry(opts.devicesFile)
+	devicesErr := loadFeature(featureRegistry, features.Devices, deviceRegistry.Load)
+	opts.selectUFO(deviceRegistry)
+
+	slog.Info("Starting MCP UFO Server",
+		"version", version.Version,
+		"commit", version.GitCommit,
+		"built", version.BuildTime,
+		"ufoIP", opts.ufoIP,
+		"effectsFile", opts.effectsFile,
+		"timezone", location.String(),
+		"transport", opts.transport)
 
 	// Initialize core components
-	deviceClient := device.NewClient()
+	deviceClient := opts.newDeviceClient(deviceRegistry)
 	broadcaster := events.NewBroadcaster()
-	effectsStore := effects.NewStore(effectsFile)
+	broadcaster.SetRedactor(redactor)
+	broadcaster.SetDevice(opts.ufoIP)
+	deviceClient.OnAvailabilityChange(func(online bool, err error) {
+		if online {
+			slog.Info("UFO is back online")
+		} else {
+			slog.Warn("UFO marked offline", "consecutiveFailures", opts.offlineAfter, "error", err)
+		}
+		broadcaster.PublishDeviceAvailability(online, err)
+		if online {
+			featureRegistry.Set(features.Device, features.Available, "")
+		} else {
+			featureRegistry.Set(features.Device, features.Unavailable, fmt.Sprintf("UFO offline: %v", err))
+		}
+	})
+	featureRegistry.OnChange(func(feature features.Feature) {
+		slog.Info("Feature status changed", "feature", feature.Name, "status", feature.Status, "reason", feature.Reason)
+		broadcaster.Publish(events.NewEvent(events.FeatureChanged{
+			Feature: feature.Name,
+			Status:  feature.Status,
+			Reason:  feature.Reason,
+		}))
+	})
+	effectsStore := effects.NewStore(opts.effectsFile)
 	stateManager := state.NewManager(broadcaster)
+	if err := stateManager.SetStackLimit(opts.maxStackDepth, opts.stackOverflow); err != nil {
+		logging.Fatal("Invalid effect stack limit", "error", err)
+	}
+	// Scale ring colors to each ring's brightness whatever sends them
+	deviceClient.SetRingBrightness(stateManager.RingBrightness)
+	effectEngine := effects.NewEngine(deviceClient)
+	effectEngine.SetCallTimeout(opts.callTimeout)
+	// Ends timed effects and alerts as they run out
+	restores := tools.NewRestores(effectEngine, broadcaster, stateManager)
 
 	// Load effects (creates seed effects if file doesn't exist)
 	if err := effectsStore.Load(); err != nil {
-		log.Fatalf("Failed to load effects: %v", err)
+		logging.Fatal("Failed to load effects", "error", err)
+	}
+
+	// Keep earlier versions of stored effects so the CRUD tools can roll back
+	// a clobbered effect
+	var revisionsErr error
+	if opts.enableEffectCRUD {
+		if opts.effectRevisionsFile == "" {
+			opts.effectRevisionsFile = filepath.Join(filepath.Dir(opts.effectsFile), "effect-revisions.json")
+		}
+		effectsStore.SetRevisionsFile(opts.effectRevisionsFile)
+		featureRegistry.Register(features.EffectRevisions, "Earlier versions of stored effects", "listEffectRevisions", "rollbackEffect")
+		revisionsErr = loadFeature(featureRegistry, features.EffectRevisions, effectsStore.LoadRevisions)
+	}
+
+	// Load saved color palettes that lighting tools can refer to by name
+	if opts.palettesFile == "" {
+		opts.palettesFile = filepath.Join(filepath.Dir(opts.effectsFile), "palettes.json")
+	}
+	paletteStore := palettes.NewStore(opts.palettesFile)
+	palettesErr := loadFeature(featureRegistry, features.Palettes, paletteStore.Load)
+
+	// Load macros of tool calls that runMacro replays
+	if opts.macrosFile == "" {
+		opts.macrosFile = filepath.Join(filepath.Dir(opts.effectsFile), "macros.json")
+	}
+	macroStore := macros.NewStore(opts.macrosFile)
+	macrosErr := loadFeature(featureRegistry, features.Macros, macroStore.Load)
+
+	// Load scenes that applyScene brings back
+	if opts.scenesFile == "" {
+		opts.scenesFile = filepath.Join(filepath.Dir(opts.effectsFile), "scenes.json")
+	}
+	sceneStore := scenes.NewStore(opts.scenesFile)
+	scenesErr := loadFeature(featureRegistry, features.Scenes, sceneStore.Load)
+
+	// Load earlier states so diffStates can compare across restarts
+	if opts.stateHistoryFile == "" {
+		opts.stateHistoryFile = filepath.Join(filepath.Dir(opts.effectsFile), "state-history.json")
+	}
+	stateHistory := state.NewHistory(opts.stateHistoryFile, state.DefaultHistorySize)
+	stateHistoryErr := loadFeature(featureRegistry, features.StateHistory, stateHistory.Load)
+
+	// Load the effect stack saved before the last restart
+	if opts.stackFilePath == "" {
+		opts.stackFilePath = filepath.Join(filepath.Dir(opts.effectsFile), "effect-stack.json")
+	}
+	stackFile := state.NewStackFile(opts.stackFilePath)
+	var savedStack []state.EffectStackItem
+	loadStack := func() (err error) {
+		savedStack, err = stackFile.Load()
+		return err
+	}
+	stackErr := loadFeature(featureRegistry, features.EffectStack, loadStack)
+
+	auditLogger, err := audit.NewLogger(opts.auditLogFile)
+	if err != nil {
+		logging.Fatal("Failed to open audit log", "error", err)
+	}
+	defer auditLogger.Close()
+	auditLogger.SetRedactor(redactor)
+	auditLogger.SetRotation(int64(opts.auditLogMaxSize)<<20, opts.auditLogKeep)
+
+	// Record every command sent to the UFO with the tool call or HTTP
+	// request that caused it; without a file they would flood the log
+	if opts.auditLogFile != "" {
+		deviceClient.OnCommand(func(ctx context.Context, command device.Command) {
+			data := audit.OriginFrom(ctx).Fields()
+			if requestID := logging.RequestID(ctx); requestID != "" {
+				data["requestId"] = requestID
+			}
+			data["latencyMs"] = command.Latency.Milliseconds()
+			data["attempts"] = command.Attempts
+			result := "OK"
+			if command.Err != nil {
+				result = "ERROR: " + command.Err.Error()
+			}
+			auditLogger.Record(audit.Entry{Kind: "device", Action: command.Query, Result: result, Data: data})
+		})
+	}
+
+	// Load the policy engine for mutating tool calls
+	serverOptions := []server.ServerOption{server.WithToolHandlerMiddleware(requestLoggingMiddleware())}
+	if opts.readOnly {
+		slog.Info("Read-only mode: mutating tool calls are rejected")
+		serverOptions = append(serverOptions, server.WithToolHandlerMiddleware(readOnlyMiddleware()))
+	}
+	if opts.policyFile != "" {
+		policyEngine, err := policy.Load(opts.policyFile, auditLogger)
+		if err != nil {
+			logging.Fatal("Failed to load policy", "error", err)
+		}
+		slog.Info("Policy rules loaded", "file", opts.policyFile)
+		serverOptions = append(serverOptions, server.WithToolHandlerMiddleware(policyMiddleware(policyEngine)))
 	}
 
 	// Create MCP server
-	mcpServer := createMCPServer(deviceClient, broadcaster, effectsStore, stateManager)
+	mcpServer := createMCPServer(deviceClient, broadcaster, effectsStore, stateManager, effectEngine, restores, paletteStore, serverOptions...)
+	registerServerInfoTool(mcpServer, featureRegistry, deviceClient)
+	reconfigureDeviceTool := tools.NewReconfigureDeviceTool(deviceClient, deviceRegistry, broadcaster, stateManager, effectEngine)
+	mcpServer.AddTool(reconfigureDeviceTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return reconfigureDeviceTool.Execute(ctx, request.GetArguments())
+	})
+	registerAuditTools(mcpServer, auditLogger)
+	if opts.enableEffectCRUD {
+		slog.Info("Effect CRUD tools enabled")
+		registerEffectCRUDTools(mcpServer, effectsStore)
+	}
 
 	// Handle graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -75,181 +270,1526 @@ func main() {
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 		<-sigChan
-		log.Println("Shutting down server...")
+		slog.Info("Shutting down server")
+		stopEffectTimers(effectEngine, restores)
 		broadcaster.Close()
 		cancel()
 	}()
 
+	// SIGHUP reloads the devices file and picks the UFO from --ufo-ip again,
+	// following a nickname or host name that now points elsewhere
+	go func() {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		for range hup {
+			reloadUFO(ctx, &opts, deviceRegistry, reconfigureDeviceTool)
+		}
+	}()
+
+	// Offer the tools of stored data that loaded, and of the rest once it
+	// loads on a later try
+	enableFeature(ctx, featureRegistry, features.Devices, devicesErr, deviceRegistry.Load, func() {
+		registerDeviceTools(mcpServer, deviceRegistry, deviceClient)
+	})
+	enableFeature(ctx, featureRegistry, features.Palettes, palettesErr, paletteStore.Load, func() {
+		registerPaletteTools(mcpServer, paletteStore)
+	})
+	enableFeature(ctx, featureRegistry, features.Macros, macrosErr, macroStore.Load, func() {
+		registerMacroTools(mcpServer, macroStore)
+	})
+	enableFeature(ctx, featureRegistry, features.Scenes, scenesErr, sceneStore.Load, func() {
+		registerSceneTools(mcpServer, sceneStore, effectEngine, broadcaster, stateManager)
+	})
+	if opts.enableEffectCRUD {
+		enableFeature(ctx, featureRegistry, features.EffectRevisions, revisionsErr, effectsStore.LoadRevisions, func() {
+			registerEffectRevisionTools(mcpServer, effectsStore)
+		})
+	}
+
+	// Record each change to the shadow state
+	enableFeature(ctx, featureRegistry, features.StateHistory, stateHistoryErr, stateHistory.Load, func() {
+		stateHistory.Start(ctx, broadcaster, stateManager, func(err error) {
+			slog.Warn("Failed to save state history", "error", err)
+		})
+		registerHistoryTools(mcpServer, stateManager, stateHistory)
+	})
+
+	// Save the effect stack as it changes, then resume the effects that were
+	// running before the restart. A stack that only loads later is not
+	// resumed; saving starts from the stack as it is then.
+	saveStack := func() {
+		stackFile.Start(ctx, broadcaster, stateManager, func(err error) {
+			slog.Warn("Failed to save effect stack", "error", err)
+		})
+	}
+	enableFeature(ctx, featureRegistry, features.EffectStack, stackErr, loadStack, saveStack)
+	if stackErr == nil {
+		report, err := tools.ResumeEffects(ctx, savedStack, effectEngine, broadcaster, stateManager, restores)
+		if err != nil {
+			slog.Warn("Failed to resume effects", "error", err)
+		} else if len(report.Resumed) > 0 || len(report.Expired) > 0 {
+			slog.Info("Resumed effect stack", "resumed", report.Resumed, "expired", report.Expired, "timers", report.Timers)
+		}
+	}
+
+	// Run external command hooks on configured events
+	if opts.hooksFile != "" {
+		var hookList []hooks.Hook
+		loadHooks := func() (err error) {
+			hookList, err = hooks.Load(opts.hooksFile)
+			return err
+		}
+		featureRegistry.Register(features.Hooks, "External commands run on events")
+		hooksErr := loadFeature(featureRegistry, features.Hooks, loadHooks)
+		enableFeature(ctx, featureRegistry, features.Hooks, hooksErr, loadHooks, func() {
+			slog.Info("Loaded event hooks", "count", len(hookList), "file", opts.hooksFile)
+			hooks.NewRunner(hookList, broadcaster, auditLogger).Start(ctx)
+		})
+	}
+
+	// The UFO comes back dark after a restart, so show its state again
+	deviceClient.OnReboot(func(reboot device.Reboot) {
+		slog.Warn("UFO restarted, showing its state again", "bootedAt", reboot.BootedAt, "uptime", reboot.Uptime)
+		broadcaster.PublishDeviceRebooted(reboot.BootedAt, reboot.Uptime)
+		effectEngine.Go(ctx, func(ctx context.Context) {
+			if err := tools.ReapplyState(ctx, effectEngine, broadcaster, stateManager); err != nil {
+				slog.Warn("Failed to show state after UFO restart", "error", err)
+			}
+		})
+	})
+
+	// Poll the device to detect drift caused by direct use of the UFO web UI,
+	// restarts from its uptime and presses of its button
+	if opts.pollInterval > 0 {
+		slog.Info("Polling UFO state", "interval", opts.pollInterval)
+		poller := device.NewPoller(deviceClient, opts.pollInterval, func(status *device.Status) {
+			reconcileDeviceStatus(stateManager, status)
+		})
+		poller.OnButtonPress(buttonPressHandler(ctx, &opts, serverToolCaller{server: mcpServer}, broadcaster))
+		poller.Start(ctx)
+	}
+
+	// Set up webhook integrations served alongside the MCP endpoint
+	handlers := map[string]http.Handler{}
+	display := integrations.NewDisplay(broadcaster, effectsStore, stateManager, effectEngine)
+	bindings := integrations.NewBindings(display, auditLogger)
+	registry := integrations.NewRegistry(effectEngine)
+	registry.Register("bindings", bindings)
+	var integrationsConfig *integrations.Config
+	if opts.integrationsFile != "" {
+		featureRegistry.Register(features.Integrations, "Alerts from Grafana, PagerDuty, Jenkins and weather", "ackIncidentLight")
+		var err error
+		integrationsConfig, err = integrations.LoadConfig(opts.integrationsFile)
+		if err != nil {
+			// Webhook endpoints are mounted at startup, so this takes a restart
+			slog.Error("Integrations unavailable until the file is fixed and the server restarted", "file", opts.integrationsFile, "error", err)
+			featureRegistry.Set(features.Integrations, features.Unavailable, err.Error())
+		}
+	}
+	if cfg := integrationsConfig; cfg != nil {
+		if cfg.Rollup != nil {
+			if err := display.SetRollup(*cfg.Rollup); err != nil {
+				logging.Fatal("Failed to configure integrations", "error", err)
+			}
+		}
+		if cfg.Grafana != nil {
+			grafana, err := integrations.NewGrafana(*cfg.Grafana, display, auditLogger)
+			if err != nil {
+				logging.Fatal("Failed to configure integrations", "error", err)
+			}
+			handlers["/integrations/grafana"] = grafana
+			registry.Register("grafana", grafana)
+		}
+		if cfg.PagerDuty != nil {
+			pagerDuty, err := integrations.NewPagerDuty(*cfg.PagerDuty, display, auditLogger)
+			if err != nil {
+				logging.Fatal("Failed to configure integrations", "error", err)
+			}
+			handlers["/integrations/pagerduty"] = pagerDuty
+			registry.Register("pagerduty", pagerDuty)
+			pagerDuty.Start(ctx)
+			if pagerDuty.CanAcknowledge() {
+				registerPagerDutyTools(mcpServer, pagerDuty)
+			}
+		}
+		if cfg.Jenkins != nil {
+			jenkins, err := integrations.NewJenkins(*cfg.Jenkins, display, auditLogger)
+			if err != nil {
+				logging.Fatal("Failed to configure integrations", "error", err)
+			}
+			jenkins.Start(ctx)
+			registry.Register("jenkins", jenkins)
+		}
+		if cfg.Weather != nil {
+			weather, err := integrations.NewWeather(*cfg.Weather, display, auditLogger)
+			if err != nil {
+				logging.Fatal("Failed to configure integrations", "error", err)
+			}
+			weather.Start(ctx)
+			registry.Register("weather", weather)
+		}
+		for _, binding := range cfg.Bindings {
+			if err := bindings.Add(binding); err != nil {
+				logging.Fatal("Failed to configure integrations", "error", err)
+			}
+		}
+	}
+	if opts.enableDynatrace {
+		if opts.dynatraceConfig == "" {
+			logging.Fatal("--enable-dynatrace needs --dynatrace-config")
+		}
+		var loaded *integrations.DynatraceConfig
+		loadDynatrace := func() (err error) {
+			loaded, err = integrations.LoadDynatraceConfig(opts.dynatraceConfig)
+			return err
+		}
+		featureRegistry.Register(features.Dynatrace, "Dynatrace problem polling")
+		dynatraceErr := loadFeature(featureRegistry, features.Dynatrace, loadDynatrace)
+		enableFeature(ctx, featureRegistry, features.Dynatrace, dynatraceErr, loadDynatrace, func() {
+			dynatrace, err := integrations.NewDynatrace(*loaded, display, auditLogger)
+			if err != nil {
+				slog.Error("Failed to configure Dynatrace", "error", err)
+				featureRegistry.Set(features.Dynatrace, features.Unavailable, err.Error())
+				return
+			}
+			slog.Info("Polling Dynatrace problems", "environment", loaded.EnvironmentURL)
+			dynatrace.Start(ctx)
+			registry.Register("dynatrace", dynatrace)
+		})
+	} else if opts.dynatraceConfig != "" {
+		slog.Info("Dynatrace integration is configured but disabled; set --enable-dynatrace to poll problems")
+	}
+	var webhookMapping *webhook.Config
+	if opts.webhookFile != "" {
+		featureRegistry.Register(features.Webhook, "Effects played from the /webhook endpoint")
+		var err error
+		webhookMapping, err = webhook.Load(opts.webhookFile)
+		if err != nil {
+			// The endpoint is mounted at startup, so this takes a restart
+			slog.Error("Webhook unavailable until the mapping is fixed and the server restarted", "file", opts.webhookFile, "error", err)
+			featureRegistry.Set(features.Webhook, features.Unavailable, err.Error())
+		}
+	}
+	if cfg := webhookMapping; cfg != nil {
+		player := tools.NewPlayEffectTool(deviceClient, broadcaster, effectsStore, stateManager, effectEngine, restores)
+		handler, err := webhook.NewHandler(*cfg, player, auditLogger)
+		if err != nil {
+			logging.Fatal("Failed to configure webhook", "error", err)
+		}
+		if !handler.Signed() {
+			slog.Warn("Webhook signatures are not checked; set a secret in the webhook file or UFO_WEBHOOK_SECRET")
+		}
+		slog.Info("Loaded webhook mapping", "rules", len(cfg.Rules), "file", opts.webhookFile)
+		handlers["/webhook"] = handler
+	}
+	if opts.enableVUMeter && opts.transport != "http" {
+		slog.Warn("The VU meter needs the HTTP transport; /vumeter is not served")
+	} else if opts.enableVUMeter {
+		// Draw no faster than the UFO accepts writes, so every frame shows
+		vuConfig := vumeter.Config{IdleTimeout: opts.vuMeterTimeout}
+		if opts.requestsPerSecond > 0 {
+			vuConfig.FrameInterval = max(vumeter.DefaultFrameInterval, time.Duration(float64(time.Second)/opts.requestsPerSecond))
+		}
+		meter := vumeter.New(vuConfig, effectEngine, broadcaster, func(ctx context.Context) error {
+			return tools.ReapplyState(ctx, effectEngine, broadcaster, stateManager)
+		}, func() bool {
+			top := stateManager.GetCurrentEffect()
+			return top != nil && top.Priority() > 0
+		})
+		meter.Start(ctx)
+		slog.Info("Accepting audio levels for the VU meter", "idleTimeout", opts.vuMeterTimeout)
+		handlers["/vumeter"] = meter
+	}
+	bindings.Start(ctx)
+	registerBindingTools(mcpServer, bindings)
+	registerIntegrationTools(mcpServer, registry)
+	registerIntegrationResources(mcpServer, display)
+	if opts.channelsFile != "" {
+		featureRegistry.Register(features.Channels, "Status board channels shown with setChannelStatus", "setChannelStatus")
+		boardConfig, err := integrations.LoadBoardConfig(opts.channelsFile)
+		if err != nil {
+			slog.Error("Status board unavailable until the channels file is fixed and the server restarted", "file", opts.channelsFile, "error", err)
+			featureRegistry.Set(features.Channels, features.Unavailable, err.Error())
+		} else {
+			board, err := integrations.NewBoard(*boardConfig, display)
+			if err != nil {
+				logging.Fatal("Failed to configure the status board", "error", err)
+			}
+			slog.Info("Loaded status board", "channels", len(boardConfig.Channels), "file", opts.channelsFile)
+			registerBoard(mcpServer, board)
+		}
+	}
+
+	if opts.mqttConfig != "" {
+		var mqttConfig *homeassistant.Config
+		loadMQTT := func() (err error) {
+			mqttConfig, err = homeassistant.Load(opts.mqttConfig)
+			return err
+		}
+		featureRegistry.Register(features.HomeAssistant, "The UFO as a Home Assistant light entity over MQTT")
+		mqttErr := loadFeature(featureRegistry, features.HomeAssistant, loadMQTT)
+		enableFeature(ctx, featureRegistry, features.HomeAssistant, mqttErr, loadMQTT, func() {
+			slog.Info("Joining Home Assistant over MQTT", "broker", mqttConfig.Broker)
+			homeassistant.NewBridge(*mqttConfig, serverToolCaller{server: mcpServer}, broadcaster, stateManager).Start(ctx)
+		})
+	}
+
+	// Export Prometheus metrics alongside the MCP endpoint
+	if opts.transport == "http" {
+		collector := metrics.NewCollector(deviceClient, broadcaster, stateManager)
+		collector.Start(ctx)
+		handlers["/metrics"] = collector
+	}
+
+	// Serve the gRPC management API alongside MCP
+	if opts.grpcPort != "" {
+		startGRPCServer(ctx, opts.grpcPort, mcpServer, broadcaster, auth.New(auth.ParseTokens(opts.authTokens)))
+	}
+
 	// Start server based on transport type
-	if transport == "http" {
-		startHTTPServer(mcpServer, port, ctx)
+	if opts.transport == "http" {
+		authenticator := auth.New(auth.ParseTokens(opts.authTokens))
+		if authenticator == nil {
+			slog.Warn("HTTP authentication is disabled; set --auth-token to require a token")
+		}
+		tlsConfig, err := loadTLSConfig(opts)
+		if err != nil {
+			logging.Fatal("Failed to set up TLS", "error", err)
+		}
+		corsPolicy, err := opts.corsPolicy()
+		if err != nil {
+			logging.Fatal("Invalid CORS settings", "error", err)
+		}
+		if corsPolicy != nil {
+			slog.Info("Allowing browser clients", "origins", opts.corsOrigins)
+		}
+		startHTTPServer(mcpServer, opts.port, ctx, handlers, authenticator, tlsConfig, corsPolicy, func(ctx context.Context, health map[string]interface{}) {
+			healthDetail(ctx, health, deviceClient, stateManager, effectsStore, featureRegistry, redactor)
+		}, func(ctx context.Context) (map[string]interface{}, string) {
+			return probeDevice(ctx, deviceClient)
+		})
 	} else {
 		startStdioServer(mcpServer)
 	}
+	stopEffectTimers(effectEngine, restores)
+	leaveUFO(effectEngine, stateManager, opts.onShutdown)
 }
 
-func createMCPServer(deviceClient *device.Client, broadcaster *events.Broadcaster, effectsStore *effects.Store, stateManager *state.Manager) *server.MCPServer {
+// reloadUFO picks the UFO from --ufo-ip again and switches to it once it
+// answers, keeping the current one otherwise
+func reloadUFO(ctx context.Context, opts *options, registry *devices.Registry, reconfigure *tools.ReconfigureDeviceTool) {
+	if opts.simulate {
+		slog.Info("Ignoring SIGHUP while simulating the UFO")
+		return
+	}
+	if err := registry.Load(); err != nil {
+		slog.Warn("Failed to reload the devices file", "error", err)
+	}
+	address := opts.pickUFO(registry)
+	slog.Info("Reloading the UFO address on SIGHUP", "address", address)
+	if _, err := reconfigure.Switch(ctx, address, "SIGHUP"); err != nil {
+		slog.Warn("Keeping the current UFO", "error", err)
+	}
+}
+
+// stopEffectTimers cancels the timers of timed effects, alerts and
+// sequences, crossfades and integration test holds so none of them changes
+// the UFO or the saved stack while the server exits
+func stopEffectTimers(engine *effects.Engine, restores *tools.Restores) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	timers := engine.Timers()
+	restores.Stop()
+	if err := engine.Shutdown(ctx); err != nil {
+		slog.Warn("Effect timers did not stop in time", "error", err)
+	} else if timers > 0 {
+		slog.Info("Stopped effect timers", "timers", timers)
+	}
+}
+
+// leaveUFO sets the UFO as --on-shutdown asks once effect timers have
+// stopped
+func leaveUFO(engine *effects.Engine, stateManager *state.Manager, onShutdown string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := "top_init=1&bottom_init=1"
+	switch onShutdown {
+	case "leave":
+		return
+	case "base":
+		if stack := stateManager.GetEffectStack(); len(stack) > 0 {
+			query = stack[0].Pattern
+			slog.Info("Showing base effect before exit", "effect", stack[0].Name)
+		}
+	}
+	if err := engine.Apply(ctx, "", query, nil); err != nil {
+		slog.Warn("Failed to set the UFO before exit", "onShutdown", onShutdown, "error", err)
+	}
+}
+
+func createMCPServer(deviceClient *device.Client, broadcaster *events.Broadcaster, effectsStore *effects.Store, stateManager *state.Manager, effectEngine *effects.Engine, restores *tools.Restores, paletteStore *palettes.Store, extraOptions ...server.ServerOption) *server.MCPServer {
 	// Create server with capabilities
-	mcpServer := server.NewMCPServer(
-		ServerName,
-		ServerVersion,
+	options := []server.ServerOption{
 		server.WithToolCapabilities(true), // Tools can change
 		server.WithResourceCapabilities(true, false), // Resources, no subscription yet
+		server.WithPromptCapabilities(false),
 		server.WithLogging(),
 		server.WithInstructions(`This MCP server provides control over a Dynatrace UFO lighting device. 
 
-Available capabilities:
-- Send raw API commands to the UFO
-- Control lighting effects and patterns  
-- Manage brightness and logo
-- Store and manage custom lighting effects
-- Real-time event streaming for state changes
+Available capabilities:
+- Send raw API commands to the UFO
+- Control lighting effects and patterns  
+- Manage brightness and logo
+- Store and manage custom lighting effects
+- Real-time event streaming for state changes
+
+Resources:
+- ufo://status - Get UFO device status: firmware, network, reported LED state and connectivity
+- ufo://ledstate - Get current LED colors, brightness, logo state, and running effect (shadow state)
+- ufo://api-reference - Raw query parameters, formats and valid ranges for sendRawApi
+
+Use sendRawApi for direct UFO control or the high-level tools for common operations.
+Before composing a raw query, read the ufo://api-reference resource.
+To check current LED colors, read the ufo://ledstate resource.
+
+Prompts:
+- setMoodLighting, showBuildStatus, incidentAlert - guided tool calls for common tasks`),
+	}
+	mcpServer := server.NewMCPServer(ServerName, version.Version, append(options, extraOptions...)...)
+
+	// Register tools
+	registerTools(mcpServer, deviceClient, broadcaster, effectsStore, stateManager, effectEngine, restores, paletteStore)
+
+	// Register resources
+	registerResources(mcpServer, deviceClient, broadcaster, stateManager)
+
+	// Register prompts
+	registerPrompts(mcpServer, effectsStore)
+
+	return mcpServer
+}
+
+func registerTools(mcpServer *server.MCPServer, deviceClient *device.Client, broadcaster *events.Broadcaster, effectsStore *effects.Store, stateManager *state.Manager, effectEngine *effects.Engine, restores *tools.Restores, paletteStore *palettes.Store) {
+	// sendRawApi tool
+	sendRawApiTool := tools.NewSendRawApiTool(deviceClient, broadcaster, stateManager)
+	mcpServer.AddTool(sendRawApiTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return sendRawApiTool.Execute(ctx, request.GetArguments())
+	})
+
+	// setLogo tool
+	setLogoTool := tools.NewSetLogoTool(deviceClient, broadcaster, stateManager, effectEngine)
+	mcpServer.AddTool(setLogoTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return setLogoTool.Execute(ctx, request.GetArguments())
+	})
+
+	// setBrightness tool - supports optional fade ramping
+	setBrightnessTool := tools.NewSetBrightnessTool(deviceClient, broadcaster, stateManager)
+	mcpServer.AddTool(setBrightnessTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return setBrightnessTool.Execute(ctx, request.GetArguments())
+	})
+
+	// setRingPattern tool
+	setRingPatternTool := tools.NewSetRingPatternTool(deviceClient, broadcaster, stateManager)
+	mcpServer.AddTool(setRingPatternTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return setRingPatternTool.Execute(ctx, request.GetArguments())
+	})
+
+	// setPixels tool - draw the rings LED by LED
+	setPixelsTool := tools.NewSetPixelsTool(deviceClient, broadcaster, stateManager)
+	mcpServer.AddTool(setPixelsTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return setPixelsTool.Execute(ctx, request.GetArguments())
+	})
+
+	// composeRing tool
+	composeRingTool := tools.NewComposeRingTool(deviceClient, broadcaster, stateManager, paletteStore)
+	mcpServer.AddTool(composeRingTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return composeRingTool.Execute(ctx, request.GetArguments())
+	})
+
+	// getLedState tool
+	getLedStateTool := tools.NewGetLedStateTool(stateManager)
+	mcpServer.AddTool(getLedStateTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return getLedStateTool.Execute(ctx, request.GetArguments())
+	})
+
+	// getDeviceInfo tool
+	getDeviceInfoTool := tools.NewGetDeviceInfoTool(deviceClient)
+	mcpServer.AddTool(getDeviceInfoTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return getDeviceInfoTool.Execute(ctx, request.GetArguments())
+	})
+
+	// testDevice tool - diagnostic sequence checking connectivity and pixels
+	testDeviceTool := tools.NewTestDeviceTool(deviceClient, broadcaster, stateManager, effectEngine)
+	mcpServer.AddTool(testDeviceTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return testDeviceTool.Execute(ctx, request.GetArguments())
+	})
+
+	// getFirmwareVersion tool - installed firmware and available updates
+	getFirmwareVersionTool := tools.NewGetFirmwareVersionTool(deviceClient)
+	mcpServer.AddTool(getFirmwareVersionTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return getFirmwareVersionTool.Execute(ctx, request.GetArguments())
+	})
+
+	// triggerFirmwareUpdate tool - install an update over the air
+	triggerFirmwareUpdateTool := tools.NewTriggerFirmwareUpdateTool(deviceClient, broadcaster, stateManager, effectEngine)
+	mcpServer.AddTool(triggerFirmwareUpdateTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return triggerFirmwareUpdateTool.Execute(ctx, request.GetArguments())
+	})
+
+	// listEffects tool
+	listEffectsTool := tools.NewListEffectsTool(effectsStore)
+	mcpServer.AddTool(listEffectsTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return listEffectsTool.Execute(ctx, request.GetArguments())
+	})
+
+	// exportEffects tool - bundle stored effects to share or check in
+	exportEffectsTool := tools.NewExportEffectsTool(effectsStore)
+	mcpServer.AddTool(exportEffectsTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return exportEffectsTool.Execute(ctx, request.GetArguments())
+	})
+
+	// previewEffect tool - render an effect without touching the UFO
+	previewEffectTool := tools.NewPreviewEffectTool(effectsStore)
+	mcpServer.AddTool(previewEffectTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return previewEffectTool.Execute(ctx, request.GetArguments())
+	})
+
+	// buildPattern tool - compile a pattern description without sending it
+	buildPatternTool := tools.NewBuildPatternTool()
+	mcpServer.AddTool(buildPatternTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return buildPatternTool.Execute(ctx, request.GetArguments())
+	})
+
+	// validatePattern tool - check a pattern against the UFO query grammar
+	validatePatternTool := tools.NewValidatePatternTool()
+	mcpServer.AddTool(validatePatternTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return validatePatternTool.Execute(ctx, request.GetArguments())
+	})
+
+	// Effects CRUD tools are registered by registerEffectCRUDTools only
+	// when --enable-effect-crud is set
+
+	// playEffect tool
+	playEffectTool := tools.NewPlayEffectTool(deviceClient, broadcaster, effectsStore, stateManager, effectEngine, restores)
+	mcpServer.AddTool(playEffectTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return playEffectTool.Execute(ctx, request.GetArguments())
+	})
+
+	// configureLighting tool - unified lighting control
+	configureLightingTool := tools.NewConfigureLightingTool(deviceClient, broadcaster, stateManager, effectEngine, paletteStore)
+	mcpServer.AddTool(configureLightingTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return configureLightingTool.Execute(ctx, request.GetArguments())
+	})
+
+	// transitionTo tool - crossfade to a lighting configuration
+	transitionToTool := tools.NewTransitionToTool(deviceClient, broadcaster, stateManager, effectEngine, paletteStore)
+	mcpServer.AddTool(transitionToTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return transitionToTool.Execute(ctx, request.GetArguments())
+	})
+
+	// runSequence tool - play several lighting steps in one call
+	runSequenceTool := tools.NewRunSequenceTool(deviceClient, broadcaster, effectsStore, stateManager, effectEngine, paletteStore)
+	mcpServer.AddTool(runSequenceTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return runSequenceTool.Execute(ctx, request.GetArguments())
+	})
+
+	// stopEffect tool - pops the current effect from the stack and resumes the previous one
+	stopEffectTool := tools.NewStopEffectTool(deviceClient, broadcaster, stateManager, effectEngine)
+	mcpServer.AddTool(stopEffectTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return stopEffectTool.Execute(ctx, request.GetArguments())
+	})
+
+	// removeEffectById tool - takes one entry, alerts included, out of the stack wherever it is
+	removeEffectByIDTool := tools.NewRemoveEffectByIDTool(broadcaster, stateManager, effectEngine)
+	mcpServer.AddTool(removeEffectByIDTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return removeEffectByIDTool.Execute(ctx, request.GetArguments())
+	})
+
+	// stopAllEffects tool - unwinds the whole stack, or down to a given depth
+	stopAllEffectsTool := tools.NewStopAllEffectsTool(broadcaster, stateManager, effectEngine)
+	mcpServer.AddTool(stopAllEffectsTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return stopAllEffectsTool.Execute(ctx, request.GetArguments())
+	})
+
+	// replaceEffect tool - swaps the current effect for another without restoring the previous one
+	replaceEffectTool := tools.NewReplaceEffectTool(broadcaster, effectsStore, stateManager, effectEngine, restores)
+	mcpServer.AddTool(replaceEffectTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return replaceEffectTool.Execute(ctx, request.GetArguments())
+	})
+
+	// alternateEffects tool - show two effects in turn as one stack entry
+	alternateEffectsTool := tools.NewAlternateEffectsTool(broadcaster, effectsStore, stateManager, effectEngine, restores)
+	mcpServer.AddTool(alternateEffectsTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return alternateEffectsTool.Execute(ctx, request.GetArguments())
+	})
+
+	// displayText / displayNumber tools - spell out codes and numbers on the rings
+	displayTextTool := tools.NewDisplayTextTool(broadcaster, stateManager, effectEngine, restores)
+	mcpServer.AddTool(displayTextTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return displayTextTool.Execute(ctx, request.GetArguments())
+	})
+	displayNumberTool := tools.NewDisplayNumberTool(broadcaster, stateManager, effectEngine, restores)
+	mcpServer.AddTool(displayNumberTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return displayNumberTool.Execute(ctx, request.GetArguments())
+	})
+
+	// pauseEffect / resumeEffect tools - suspend the current effect's countdown
+	pauseEffectTool := tools.NewPauseEffectTool(broadcaster, stateManager, effectEngine)
+	mcpServer.AddTool(pauseEffectTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return pauseEffectTool.Execute(ctx, request.GetArguments())
+	})
+	resumeEffectTool := tools.NewResumeEffectTool(broadcaster, stateManager, effectEngine)
+	mcpServer.AddTool(resumeEffectTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return resumeEffectTool.Execute(ctx, request.GetArguments())
+	})
+
+	// getEffectStack tool - inspect every layer of the effect stack
+	getEffectStackTool := tools.NewGetEffectStackTool(stateManager)
+	mcpServer.AddTool(getEffectStackTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return getEffectStackTool.Execute(ctx, request.GetArguments())
+	})
+
+	// getRecentEvents tool - events published before the client connected
+	getRecentEventsTool := tools.NewGetRecentEventsTool(broadcaster)
+	mcpServer.AddTool(getRecentEventsTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return getRecentEventsTool.Execute(ctx, request.GetArguments())
+	})
+
+	// raiseAlert / clearAlert tools - priority alerts that preempt other effects
+	raiseAlertTool := tools.NewRaiseAlertTool(broadcaster, effectsStore, stateManager, effectEngine, restores)
+	mcpServer.AddTool(raiseAlertTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return raiseAlertTool.Execute(ctx, request.GetArguments())
+	})
+	clearAlertTool := tools.NewClearAlertTool(broadcaster, stateManager, effectEngine)
+	mcpServer.AddTool(clearAlertTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return clearAlertTool.Execute(ctx, request.GetArguments())
+	})
+
+	// castVote tool - collect votes and show the tally on the top ring
+	castVoteTool := tools.NewCastVoteTool(broadcaster, stateManager, effectEngine)
+	mcpServer.AddTool(castVoteTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return castVoteTool.Execute(ctx, request.GetArguments())
+	})
+}
+
+func registerEffectCRUDTools(mcpServer *server.MCPServer, effectsStore *effects.Store) {
+	// addEffect, updateEffect, deleteEffect and importEffects tools - manage
+	// stored effects
+	addEffectTool := tools.NewAddEffectTool(effectsStore)
+	mcpServer.AddTool(addEffectTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return addEffectTool.Execute(ctx, request.GetArguments())
+	})
+	updateEffectTool := tools.NewUpdateEffectTool(effectsStore)
+	mcpServer.AddTool(updateEffectTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return updateEffectTool.Execute(ctx, request.GetArguments())
+	})
+	deleteEffectTool := tools.NewDeleteEffectTool(effectsStore)
+	mcpServer.AddTool(deleteEffectTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return deleteEffectTool.Execute(ctx, request.GetArguments())
+	})
+	composeEffectTool := tools.NewComposeEffectTool(effectsStore)
+	mcpServer.AddTool(composeEffectTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return composeEffectTool.Execute(ctx, request.GetArguments())
+	})
+	importEffectsTool := tools.NewImportEffectsTool(effectsStore)
+	mcpServer.AddTool(importEffectsTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return importEffectsTool.Execute(ctx, request.GetArguments())
+	})
+}
+
+// registerEffectRevisionTools registers the tools that list and roll back
+// to earlier versions of stored effects
+func registerEffectRevisionTools(mcpServer *server.MCPServer, effectsStore *effects.Store) {
+	listEffectRevisionsTool := tools.NewListEffectRevisionsTool(effectsStore)
+	mcpServer.AddTool(listEffectRevisionsTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return listEffectRevisionsTool.Execute(ctx, request.GetArguments())
+	})
+
+	rollbackEffectTool := tools.NewRollbackEffectTool(effectsStore)
+	mcpServer.AddTool(rollbackEffectTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return rollbackEffectTool.Execute(ctx, request.GetArguments())
+	})
+}
+
+// registerDeviceTools registers the tools that find UFOs and manage their
+// nicknames and metadata
+func registerDeviceTools(mcpServer *server.MCPServer, registry *devices.Registry, deviceClient *device.Client) {
+	discoverUfosTool := tools.NewDiscoverUfosTool(discovery.NewScanner(), registry)
+	mcpServer.AddTool(discoverUfosTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return discoverUfosTool.Execute(ctx, request.GetArguments())
+	})
+
+	listDevicesTool := tools.NewListDevicesTool(registry)
+	mcpServer.AddTool(listDevicesTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return listDevicesTool.Execute(ctx, request.GetArguments())
+	})
+
+	setDeviceInfoTool := tools.NewSetDeviceInfoTool(registry, deviceClient)
+	mcpServer.AddTool(setDeviceInfoTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return setDeviceInfoTool.Execute(ctx, request.GetArguments())
+	})
+}
+
+// registerPaletteTools registers the tools that manage saved color palettes
+func registerPaletteTools(mcpServer *server.MCPServer, store *palettes.Store) {
+	savePaletteTool := tools.NewSavePaletteTool(store)
+	mcpServer.AddTool(savePaletteTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return savePaletteTool.Execute(ctx, request.GetArguments())
+	})
+
+	listPalettesTool := tools.NewListPalettesTool(store)
+	mcpServer.AddTool(listPalettesTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return listPalettesTool.Execute(ctx, request.GetArguments())
+	})
+
+	deletePaletteTool := tools.NewDeletePaletteTool(store)
+	mcpServer.AddTool(deletePaletteTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return deletePaletteTool.Execute(ctx, request.GetArguments())
+	})
+}
+
+// registerSceneTools registers the tools that save and show lighting scenes
+func registerSceneTools(mcpServer *server.MCPServer, store *scenes.Store, effectEngine *effects.Engine, broadcaster *events.Broadcaster, stateManager *state.Manager) {
+	saveSceneTool := tools.NewSaveSceneTool(store, stateManager)
+	mcpServer.AddTool(saveSceneTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return saveSceneTool.Execute(ctx, request.GetArguments())
+	})
+
+	applySceneTool := tools.NewApplySceneTool(store, effectEngine, broadcaster, stateManager)
+	mcpServer.AddTool(applySceneTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return applySceneTool.Execute(ctx, request.GetArguments())
+	})
+
+	listScenesTool := tools.NewListScenesTool(store)
+	mcpServer.AddTool(listScenesTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return listScenesTool.Execute(ctx, request.GetArguments())
+	})
+
+	deleteSceneTool := tools.NewDeleteSceneTool(store)
+	mcpServer.AddTool(deleteSceneTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return deleteSceneTool.Execute(ctx, request.GetArguments())
+	})
+}
+
+// registerMacroTools registers the tools that save and run macros of tool
+// calls. Macro steps go through the server, so middleware such as read-only
+// mode and policy applies to each of them.
+func registerMacroTools(mcpServer *server.MCPServer, store *macros.Store) {
+	caller := serverToolCaller{server: mcpServer}
+
+	defineMacroTool := tools.NewDefineMacroTool(store, caller)
+	mcpServer.AddTool(defineMacroTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return defineMacroTool.Execute(ctx, request.GetArguments())
+	})
+
+	runMacroTool := tools.NewRunMacroTool(store, caller)
+	mcpServer.AddTool(runMacroTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return runMacroTool.Execute(ctx, request.GetArguments())
+	})
+
+	listMacrosTool := tools.NewListMacrosTool(store)
+	mcpServer.AddTool(listMacrosTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return listMacrosTool.Execute(ctx, request.GetArguments())
+	})
+
+	deleteMacroTool := tools.NewDeleteMacroTool(store)
+	mcpServer.AddTool(deleteMacroTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return deleteMacroTool.Execute(ctx, request.GetArguments())
+	})
+}
+
+// buttonPressHandler publishes a button_press event for presses of the
+// UFO's button and calls the --button-action tool, through the server so
+// policy and the audit log apply as for any other call
+func buttonPressHandler(ctx context.Context, opts *options, caller serverToolCaller, broadcaster *events.Broadcaster) func(presses int) {
+	// validate has checked the action
+	action, arguments, _ := opts.parseButtonAction()
+	return func(presses int) {
+		slog.Info("UFO button pressed", "presses", presses, "action", action)
+		broadcaster.PublishButtonPress(presses, action)
+		if action == "" {
+			return
+		}
+		result, err := caller.CallTool(ctx, action, arguments)
+		switch {
+		case err != nil:
+			slog.Warn("Button action failed", "tool", action, "error", err)
+		case result.IsError:
+			toolErr, _ := tools.ErrorOf(result)
+			slog.Warn("Button action failed", "tool", action, "code", toolErr.Code, "error", toolErr.Message)
+		}
+	}
+}
+
+// serverToolCaller calls tools through the MCP server as a client's
+// tools/call request would, middleware included
+type serverToolCaller struct {
+	server *server.MCPServer
+}
+
+// HasTool reports whether the server lists a tool named name
+func (c serverToolCaller) HasTool(name string) bool {
+	response, ok := c.server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)).(mcp.JSONRPCResponse)
+	if !ok {
+		return false
+	}
+	result, ok := response.Result.(mcp.ListToolsResult)
+	if !ok {
+		return false
+	}
+	for _, tool := range result.Tools {
+		if tool.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CallTool calls the named tool with arguments
+func (c serverToolCaller) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	message, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]interface{}{"name": name, "arguments": arguments},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding call to %s: %w", name, err)
+	}
+	switch response := c.server.HandleMessage(ctx, message).(type) {
+	case mcp.JSONRPCResponse:
+		result, ok := response.Result.(mcp.CallToolResult)
+		if !ok {
+			return nil, fmt.Errorf("unexpected result from %s", name)
+		}
+		return &result, nil
+	case mcp.JSONRPCError:
+		return nil, errors.New(response.Error.Message)
+	default:
+		return nil, fmt.Errorf("unexpected response from %s", name)
+	}
+}
+
+// registerHistoryTools registers the tools that compare recorded UFO states
+func registerHistoryTools(mcpServer *server.MCPServer, stateManager *state.Manager, history *state.History) {
+	diffStatesTool := tools.NewDiffStatesTool(stateManager, history)
+	mcpServer.AddTool(diffStatesTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return diffStatesTool.Execute(ctx, request.GetArguments())
+	})
+}
+
+// registerAuditTools registers the tool that searches the audit log
+func registerAuditTools(mcpServer *server.MCPServer, logger *audit.Logger) {
+	getAuditLogTool := tools.NewGetAuditLogTool(logger)
+	mcpServer.AddTool(getAuditLogTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return getAuditLogTool.Execute(ctx, request.GetArguments())
+	})
+}
+
+// registerServerInfoTool registers the tool that reports the server's
+// version and feature availability
+func registerServerInfoTool(mcpServer *server.MCPServer, registry *features.Registry, deviceClient *device.Client) {
+	getServerInfoTool := tools.NewGetServerInfoTool(registry, deviceClient, startTime)
+	mcpServer.AddTool(getServerInfoTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return getServerInfoTool.Execute(ctx, request.GetArguments())
+	})
+}
+
+// newFeatureRegistry creates the registry of optional features with those
+// every server has; configured integrations are added as they load
+func newFeatureRegistry() *features.Registry {
+	registry := features.NewRegistry()
+	registry.Register(features.Device, "The UFO itself")
+	registry.Register(features.Devices, "Known UFOs with nicknames and color correction", "discoverUfos", "listDevices", "setDeviceInfo")
+	registry.Register(features.Palettes, "Saved color palettes", "savePalette", "listPalettes", "deletePalette")
+	registry.Register(features.Macros, "Saved macros of tool calls", "defineMacro", "runMacro", "listMacros", "deleteMacro")
+	registry.Register(features.Scenes, "Saved lighting scenes", "saveScene", "applyScene", "listScenes", "deleteScene")
+	registry.Register(features.StateHistory, "Recorded states to compare against", "diffStates")
+	registry.Register(features.EffectStack, "The effect stack saved across restarts")
+	return registry
+}
+
+// loadFeature loads an optional feature's data. A failure marks the feature
+// unavailable instead of stopping the server, and is returned for
+// enableFeature.
+func loadFeature(registry *features.Registry, name string, load func() error) error {
+	if err := load(); err != nil {
+		slog.Warn("Feature unavailable; retrying in the background", "feature", name, "error", err, "retryInterval", features.RetryInterval)
+		registry.Set(name, features.Unavailable, err.Error())
+		return err
+	}
+	return nil
+}
+
+// enableFeature runs enable, which offers a feature's tools or starts its
+// work, right away when its data loaded, or else once a retry loads it
+func enableFeature(ctx context.Context, registry *features.Registry, name string, loadErr error, load func() error, enable func()) {
+	if loadErr == nil {
+		enable()
+		return
+	}
+	registry.Retry(ctx, name, features.RetryInterval, load, enable)
+}
+
+func registerPagerDutyTools(mcpServer *server.MCPServer, pagerDuty *integrations.PagerDuty) {
+	// ackIncidentLight tool - acknowledge the incident shown on the UFO
+	ackIncidentLightTool := tools.NewAckIncidentLightTool(pagerDuty)
+	mcpServer.AddTool(ackIncidentLightTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return ackIncidentLightTool.Execute(ctx, request.GetArguments())
+	})
+}
+
+func registerBindingTools(mcpServer *server.MCPServer, bindings *integrations.Bindings) {
+	// listBindings, addBinding and removeBinding tools - manage generic poller bindings
+	listBindingsTool := tools.NewListBindingsTool(bindings)
+	mcpServer.AddTool(listBindingsTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return listBindingsTool.Execute(ctx, request.GetArguments())
+	})
+	addBindingTool := tools.NewAddBindingTool(bindings)
+	mcpServer.AddTool(addBindingTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return addBindingTool.Execute(ctx, request.GetArguments())
+	})
+	removeBindingTool := tools.NewRemoveBindingTool(bindings)
+	mcpServer.AddTool(removeBindingTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return removeBindingTool.Execute(ctx, request.GetArguments())
+	})
+}
+
+func registerResources(mcpServer *server.MCPServer, deviceClient *device.Client, broadcaster *events.Broadcaster, stateManager *state.Manager) {
+	// getStatus resource
+	mcpServer.AddResource(
+		mcp.Resource{
+			URI:         "ufo://status",
+			Name:        "UFO Status",
+			Description: "Current status and configuration of the UFO device, with its connectivity: last successful contact, latency and consecutive failures",
+			MIMEType:    "application/json",
+		},
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			// Report what the UFO says about itself and its LEDs; either part
+			// may be missing on older firmware, and both while it is
+			// unreachable, which connectivity then shows
+			status := map[string]interface{}{
+				"timestamp": timezone.ISO(time.Now()),
+				"ufo_ip":    deviceClient.Address(),
+				"online":    deviceClient.Online(),
+			}
+			info, infoErr := deviceClient.FetchInfo(ctx)
+			if infoErr == nil {
+				status["info"] = info
+			} else {
+				status["infoError"] = infoErr.Error()
+			}
+			ledsStart := time.Now()
+			leds, ledsErr := deviceClient.FetchStatus(ctx)
+			status["connectivity"], _ = deviceConnectivity(deviceClient.Health(), ledsErr, time.Since(ledsStart))
+			if ledsErr == nil {
+				status["leds"] = leds
+			} else {
+				status["ledsError"] = ledsErr.Error()
+			}
+
+			statusJSON, err := json.MarshalIndent(status, "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("failed to serialize UFO status: %w", err)
+			}
+
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{
+					URI:      request.Params.URI,
+					MIMEType: "application/json",
+					Text:     string(statusJSON),
+				},
+			}, nil
+		},
+	)
+
+	// getLedState resource
+	mcpServer.AddResource(
+		mcp.Resource{
+			URI:         "ufo://ledstate",
+			Name:        "UFO LED State",
+			Description: "Current LED state (shadow copy) showing colors, brightness, logo state, and running effect",
+			MIMEType:    "application/json",
+		},
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			// Get the current LED state
+			ledStateJSON, err := stateManager.ToJSON()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get LED state: %w", err)
+			}
+
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{
+					URI:      request.Params.URI,
+					MIMEType: "application/json",
+					Text:     ledStateJSON,
+				},
+			}, nil
+		},
+	)
 
-Resources:
-- ufo://status - Get UFO device status
-- ufo://ledstate - Get current LED colors, brightness, logo state, and running effect (shadow state)
+	// active effect resource - timing of the running effect for progress bars
+	mcpServer.AddResource(
+		mcp.Resource{
+			URI:         "ufo://effects/active",
+			Name:        "Active Effect",
+			Description: "The currently running effect with elapsed and remaining time, progress, whether it is perpetual, and its position in the effect stack",
+			MIMEType:    "application/json",
+		},
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			activeJSON, err := json.MarshalIndent(tools.DescribeActiveEffect(stateManager.GetEffectStack(), time.Now()), "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("failed to serialize active effect: %w", err)
+			}
 
-Use sendRawApi for direct UFO control or the high-level tools for common operations.
-To check current LED colors, read the ufo://ledstate resource.`),
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{
+					URI:      request.Params.URI,
+					MIMEType: "application/json",
+					Text:     string(activeJSON),
+				},
+			}, nil
+		},
 	)
 
-	// Register tools
-	registerTools(mcpServer, deviceClient, broadcaster, effectsStore, stateManager)
+	// recent events resource - the event history for clients that connect late
+	mcpServer.AddResource(
+		mcp.Resource{
+			URI:         "ufo://events/recent",
+			Name:        "Recent Events",
+			Description: fmt.Sprintf("The last %d events the server published, oldest first; use getRecentEvents to filter them by type and time", events.DefaultHistorySize),
+			MIMEType:    "application/json",
+		},
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			eventsJSON, err := json.MarshalIndent(broadcaster.Recent(events.HistoryFilter{}), "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("failed to serialize events: %w", err)
+			}
 
-	// Register resources
-	registerResources(mcpServer, deviceClient, stateManager)
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{
+					URI:      request.Params.URI,
+					MIMEType: "application/json",
+					Text:     string(eventsJSON),
+				},
+			}, nil
+		},
+	)
 
-	return mcpServer
-}
+	// event schema resource - every event type with the fields of its data
+	mcpServer.AddResource(
+		mcp.Resource{
+			URI:         "ufo://events/schema",
+			Name:        "Event Schema",
+			Description: "Every event type the server publishes, with the fields of its data, their JSON types and whether they can be left out",
+			MIMEType:    "application/json",
+		},
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			schemaJSON, err := json.MarshalIndent(events.EventSchema(), "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("failed to serialize event schema: %w", err)
+			}
 
-func registerTools(mcpServer *server.MCPServer, deviceClient *device.Client, broadcaster *events.Broadcaster, effectsStore *effects.Store, stateManager *state.Manager) {
-	// sendRawApi tool
-	sendRawApiTool := tools.NewSendRawApiTool(deviceClient, broadcaster)
-	mcpServer.AddTool(sendRawApiTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		return sendRawApiTool.Execute(ctx, request.GetArguments())
-	})
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{
+					URI:      request.Params.URI,
+					MIMEType: "application/json",
+					Text:     string(schemaJSON),
+				},
+			}, nil
+		},
+	)
 
-	// setLogo tool
-	setLogoTool := tools.NewSetLogoTool(deviceClient, broadcaster, stateManager)
-	mcpServer.AddTool(setLogoTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		return setLogoTool.Execute(ctx, request.GetArguments())
-	})
+	// api reference resource - the UFO's raw query parameters for composing sendRawApi calls
+	mcpServer.AddResource(
+		mcp.Resource{
+			URI:         "ufo://api-reference",
+			Name:        "UFO API Reference",
+			Description: "The UFO's raw /api query parameters (top/bottom segments, init, bg, whirl, morph, dim, logo) with formats, valid ranges and example queries, for composing sendRawApi calls",
+			MIMEType:    "application/json",
+		},
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			var buf bytes.Buffer
+			encoder := json.NewEncoder(&buf)
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(device.Reference()); err != nil {
+				return nil, fmt.Errorf("failed to serialize API reference: %w", err)
+			}
 
-	// setBrightness tool - implemented but not exposed via MCP
-	// Brightness can be controlled via dim parameter in patterns
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{
+					URI:      request.Params.URI,
+					MIMEType: "application/json",
+					Text:     buf.String(),
+				},
+			}, nil
+		},
+	)
+}
 
-	// setRingPattern tool
-	setRingPatternTool := tools.NewSetRingPatternTool(deviceClient, broadcaster, stateManager)
-	mcpServer.AddTool(setRingPatternTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		return setRingPatternTool.Execute(ctx, request.GetArguments())
-	})
+func registerPrompts(mcpServer *server.MCPServer, effectsStore *effects.Store) {
+	for _, prompt := range prompts.List(effectsStore) {
+		mcpServer.AddPrompt(prompt.Definition, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return prompt.Get(ctx, request.Params.Arguments)
+		})
+	}
+}
 
-	// getLedState tool
-	getLedStateTool := tools.NewGetLedStateTool(stateManager)
-	mcpServer.AddTool(getLedStateTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		return getLedStateTool.Execute(ctx, request.GetArguments())
+func registerIntegrationTools(mcpServer *server.MCPServer, registry *integrations.Registry) {
+	// testIntegration tool - run a synthetic event through an integration
+	testIntegrationTool := tools.NewTestIntegrationTool(registry)
+	mcpServer.AddTool(testIntegrationTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return testIntegrationTool.Execute(ctx, request.GetArguments())
 	})
 
-	// listEffects tool
-	listEffectsTool := tools.NewListEffectsTool(effectsStore)
-	mcpServer.AddTool(listEffectsTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		return listEffectsTool.Execute(ctx, request.GetArguments())
+	// listIntegrations tool - integration health
+	listIntegrationsTool := tools.NewListIntegrationsTool(registry)
+	mcpServer.AddTool(listIntegrationsTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return listIntegrationsTool.Execute(ctx, request.GetArguments())
 	})
 
-	// Effects CRUD tools are implemented but not exposed via MCP
-	// They remain available for internal use or future activation
-	// - addEffect
-	// - updateEffect  
-	// - deleteEffect
-
-	// playEffect tool
-	playEffectTool := tools.NewPlayEffectTool(deviceClient, broadcaster, effectsStore, stateManager)
-	mcpServer.AddTool(playEffectTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		return playEffectTool.Execute(ctx, request.GetArguments())
+	// enableIntegration tool - resume a disabled integration
+	enableIntegrationTool := tools.NewEnableIntegrationTool(registry)
+	mcpServer.AddTool(enableIntegrationTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return enableIntegrationTool.Execute(ctx, request.GetArguments())
 	})
 
-	// configureLighting tool - unified lighting control
-	configureLightingTool := tools.NewConfigureLightingTool(deviceClient, broadcaster, stateManager)
-	mcpServer.AddTool(configureLightingTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		return configureLightingTool.Execute(ctx, request.GetArguments())
+	// disableIntegration tool - pause an integration and clear its alerts
+	disableIntegrationTool := tools.NewDisableIntegrationTool(registry)
+	mcpServer.AddTool(disableIntegrationTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return disableIntegrationTool.Execute(ctx, request.GetArguments())
 	})
+}
 
-	// stopEffect tool - pops the current effect from the stack and resumes the previous one
-	stopEffectTool := tools.NewStopEffectTool(deviceClient, broadcaster, stateManager)
-	mcpServer.AddTool(stopEffectTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		return stopEffectTool.Execute(ctx, request.GetArguments())
+func registerBoard(mcpServer *server.MCPServer, board *integrations.Board) {
+	// setChannelStatus tool - show a system's status on its board channel
+	setChannelStatusTool := tools.NewSetChannelStatusTool(board)
+	mcpServer.AddTool(setChannelStatusTool.Definition(), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return setChannelStatusTool.Execute(ctx, request.GetArguments())
 	})
-}
 
-func registerResources(mcpServer *server.MCPServer, deviceClient *device.Client, stateManager *state.Manager) {
-	// getStatus resource
+	// channels resource - the status board
 	mcpServer.AddResource(
 		mcp.Resource{
-			URI:         "ufo://status",
-			Name:        "UFO Status",
-			Description: "Current status and configuration of the UFO device",
+			URI:         "ufo://channels",
+			Name:        "Status Board",
+			Description: "Channels of the status board with the ring LEDs they own and the status each shows",
 			MIMEType:    "application/json",
 		},
 		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-			status, err := deviceClient.GetStatus(ctx)
+			channelsJSON, err := json.MarshalIndent(board.Channels(), "", "  ")
 			if err != nil {
-				return nil, fmt.Errorf("failed to get UFO status: %w", err)
+				return nil, fmt.Errorf("failed to serialize channels: %w", err)
 			}
 
-			// Convert status to JSON string
-			statusJSON := fmt.Sprintf(`{
-  "timestamp": %v,
-  "ufo_response": %q,
-  "ufo_ip": %q
-}`, status["timestamp"], status["response"], os.Getenv("UFO_IP"))
-
 			return []mcp.ResourceContents{
 				mcp.TextResourceContents{
 					URI:      request.Params.URI,
 					MIMEType: "application/json",
-					Text:     statusJSON,
+					Text:     string(channelsJSON),
 				},
 			}, nil
 		},
 	)
+}
 
-	// getLedState resource
+func registerIntegrationResources(mcpServer *server.MCPServer, display *integrations.Display) {
+	// sources resource - per-source view of active integration alerts
 	mcpServer.AddResource(
 		mcp.Resource{
-			URI:         "ufo://ledstate",
-			Name:        "UFO LED State",
-			Description: "Current LED state (shadow copy) showing colors, brightness, logo state, and running effect",
+			URI:         "ufo://sources",
+			Name:        "Alert Sources",
+			Description: "Active integration alerts grouped by source, with their severity, rollup score and the alert currently shown",
 			MIMEType:    "application/json",
 		},
 		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-			// Get the current LED state
-			ledStateJSON, err := stateManager.ToJSON()
+			sourcesJSON, err := json.MarshalIndent(display.Sources(), "", "  ")
 			if err != nil {
-				return nil, fmt.Errorf("failed to get LED state: %w", err)
+				return nil, fmt.Errorf("failed to serialize sources: %w", err)
 			}
 
 			return []mcp.ResourceContents{
 				mcp.TextResourceContents{
 					URI:      request.Params.URI,
 					MIMEType: "application/json",
-					Text:     ledStateJSON,
+					Text:     string(sourcesJSON),
 				},
 			}, nil
 		},
 	)
 }
 
+// readOnlyTools lists tools that never change device state; they bypass policy
+// evaluation and are the only tools allowed in read-only mode
+var readOnlyTools = map[string]bool{
+	"getLedState":         true,
+	"listEffects":         true,
+	"exportEffects":       true,
+	"listEffectRevisions": true,
+	"previewEffect":       true,
+	"buildPattern":        true,
+	"validatePattern":     true,
+	"getEffectStack":      true,
+	"listBindings":        true,
+	"listIntegrations":    true,
+	"discoverUfos":        true,
+	"listDevices":         true,
+	"diffStates":          true,
+	"getDeviceInfo":       true,
+	"listMacros":          true,
+	"listPalettes":        true,
+	"listScenes":          true,
+	"getFirmwareVersion":  true,
+	"getRecentEvents":     true,
+	"getAuditLog":         true,
+	"getServerInfo":       true,
+}
+
+// policyMiddleware evaluates mutating tool calls against the policy engine,
+// rejecting denied calls and applying argument modifications
+func policyMiddleware(engine *policy.Engine) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if readOnlyTools[request.Params.Name] {
+				return next(ctx, request)
+			}
+
+			decision, err := engine.Evaluate(policy.Request{
+				Tool:   request.Params.Name,
+				Args:   request.GetArguments(),
+				Client: clientIdentity(ctx),
+				Time:   timezone.Now(),
+			})
+			if err != nil {
+				return tools.ToolError{Code: tools.CodeInternal, Message: fmt.Sprintf("Policy evaluation failed: %v", err)}.Result(), nil
+			}
+			if !decision.Allowed {
+				message := fmt.Sprintf("Denied by policy rule '%s'", decision.Rule)
+				if decision.Message != "" {
+					message += ": " + decision.Message
+				}
+				return tools.ToolError{Code: tools.CodeForbidden, Message: message}.Result(), nil
+			}
+
+			request.Params.Arguments = decision.Arguments
+			return next(ctx, request)
+		}
+	}
+}
+
+// requestLoggingMiddleware gives every tool call a correlation ID, carried
+// in its context to device requests, events and log records, and logs the
+// call's outcome
+func requestLoggingMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx = logging.WithRequestID(ctx, logging.NewRequestID())
+			tool := request.Params.Name
+			ctx = audit.WithOrigin(ctx, audit.Origin{Tool: tool, Arguments: request.GetArguments(), Client: clientIdentity(ctx)})
+			slog.DebugContext(ctx, "Tool call started", "tool", tool, "client", clientIdentity(ctx))
+
+			start := time.Now()
+			result, err := next(ctx, request)
+			duration := time.Since(start)
+			switch {
+			case err != nil:
+				slog.ErrorContext(ctx, "Tool call failed", "tool", tool, "duration", duration, "error", err)
+			case result != nil && result.IsError:
+				toolErr, _ := tools.ErrorOf(result)
+				slog.WarnContext(ctx, "Tool call returned an error", "tool", tool, "duration", duration, "code", toolErr.Code, "parameter", toolErr.Parameter)
+			default:
+				slog.InfoContext(ctx, "Tool call completed", "tool", tool, "duration", duration)
+			}
+			return result, err
+		}
+	}
+}
+
+// readOnlyMiddleware rejects every tool call that is not in readOnlyTools
+func readOnlyMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if !readOnlyTools[request.Params.Name] {
+				return tools.ToolError{
+					Code:    tools.CodeForbidden,
+					Message: fmt.Sprintf("Server is in read-only mode; '%s' is not allowed", request.Params.Name),
+					Hint:    "Only tools that read state can be called; restart the server without --read-only to make changes",
+				}.Result(), nil
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// clientIdentity describes the calling MCP client for policy and audit purposes
+func clientIdentity(ctx context.Context) string {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return "unknown"
+	}
+	if withInfo, ok := session.(server.SessionWithClientInfo); ok {
+		if name := withInfo.GetClientInfo().Name; name != "" {
+			return name
+		}
+	}
+	return session.SessionID()
+}
+
+// discoveryTimeout bounds the startup scan for a UFO
+const discoveryTimeout = 15 * time.Second
+
+// discoverUFO scans the local network for a UFO and returns the address of
+// the first one found, or "" if none answers
+func discoverUFO() string {
+	slog.Info("No UFO IP configured; scanning the local network")
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+
+	devices, err := discovery.NewScanner().Scan(ctx, nil)
+	if err != nil {
+		slog.Warn("UFO discovery failed", "error", err)
+		return ""
+	}
+	if len(devices) == 0 {
+		slog.Warn("No UFO found on the local network")
+		return ""
+	}
+	for _, other := range devices[1:] {
+		slog.Info("Also found a UFO; set --ufo-ip to use it instead", "ip", other.IP)
+	}
+	slog.Info("Discovered UFO", "ip", devices[0].IP, "method", devices[0].Method)
+	return devices[0].IP
+}
+
+// reconcileDeviceStatus feeds the device-reported state into the shadow state
+func reconcileDeviceStatus(stateManager *state.Manager, status *device.Status) {
+	drifted := stateManager.Reconcile(state.Observed{
+		Top:    status.Top,
+		Bottom: status.Bottom,
+		Dim:    status.Dim,
+		LogoOn: status.LogoOn,
+	})
+	if len(drifted) > 0 {
+		slog.Info("Shadow state drift detected and reconciled", "fields", drifted)
+	}
+}
+
+// invalidEnv collects environment values the env helpers ignored, which
+// the configuration check reports
+var invalidEnv []error
+
+// ignoreEnv warns about an invalid environment value and records it
+func ignoreEnv(key, value string) {
+	slog.Warn("Ignoring invalid environment value", "key", key, "value", value)
+	invalidEnv = append(invalidEnv, fmt.Errorf("invalid %s %q", key, value))
+}
+
+// envDuration reads a duration from the environment, falling back to def
+func envDuration(key string, def time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+		ignoreEnv(key, value)
+	}
+	return def
+}
+
+// envInt reads an integer from the environment, falling back to def
+func envInt(key string, def int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+		ignoreEnv(key, value)
+	}
+	return def
+}
+
+// envFloat reads a number from the environment, falling back to def
+func envFloat(key string, def float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+		ignoreEnv(key, value)
+	}
+	return def
+}
+
+// envBool reads a boolean from the environment, falling back to def
+func envBool(key string, def bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+		ignoreEnv(key, value)
+	}
+	return def
+}
+
 var startTime = time.Now()
 
-func startHTTPServer(mcpServer *server.MCPServer, port string, ctx context.Context) {
+// healthProbeTimeout bounds the UFO status request made by /healthz?detail=1
+// and /readyz
+const healthProbeTimeout = 2 * time.Second
+
+// slowDeviceLatency is the probe latency above which the UFO is reported
+// degraded: it answers, but commands will lag
+const slowDeviceLatency = time.Second
+
+// Device readiness, as reported by /readyz, /healthz?detail=1 and ufo://status
+const (
+	deviceReady       = "ready"
+	deviceDegraded    = "degraded"
+	deviceUnavailable = "unavailable"
+)
+
+// deviceConnectivity describes the UFO's connectivity from a status request
+// that took latency and failed with probeErr, or nil when it answered, and
+// returns it with the device readiness
+func deviceConnectivity(health device.Health, probeErr error, latency time.Duration) (map[string]interface{}, string) {
+	status := deviceReady
+	switch {
+	case probeErr != nil:
+		status = deviceUnavailable
+	case latency > slowDeviceLatency:
+		status = deviceDegraded
+	}
+	connectivity := map[string]interface{}{
+		"status":              status,
+		"reachable":           probeErr == nil,
+		"online":              health.Online,
+		"consecutiveFailures": health.ConsecutiveFailures,
+		"latencyMs":           latency.Milliseconds(),
+	}
+	if !health.LastSuccessAt.IsZero() {
+		connectivity["lastContactAt"] = timezone.ISO(health.LastSuccessAt)
+		connectivity["sinceLastContact"] = format.Duration(time.Since(health.LastSuccessAt))
+	}
+	return connectivity, status
+}
+
+// probeDevice asks the UFO for its status and describes its connectivity
+func probeDevice(ctx context.Context, deviceClient *device.Client) (map[string]interface{}, string) {
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+
+	probeStart := time.Now()
+	_, probeErr := deviceClient.FetchStatus(ctx)
+	return deviceConnectivity(deviceClient.Health(), probeErr, time.Since(probeStart))
+}
+
+// healthDetail adds device reachability, the effect stack, storage
+// writability and optional features to a health response, marking it
+// degraded when the UFO cannot be reached, effects cannot be saved or a
+// feature is not fully available
+func healthDetail(ctx context.Context, health map[string]interface{}, deviceClient *device.Client, stateManager *state.Manager, effectsStore *effects.Store, featureRegistry *features.Registry, redactor *redact.Redactor) {
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+
+	probeStart := time.Now()
+	_, probeErr := deviceClient.FetchStatus(ctx)
+	deviceHealth := deviceClient.Health()
+	deviceDetail, deviceStatus := deviceConnectivity(deviceHealth, probeErr, time.Since(probeStart))
+	deviceDetail["concurrency"] = deviceClient.Concurrency()
+	deviceDetail["writeQueue"] = deviceClient.WriteQueue()
+	if probeErr != nil {
+		deviceDetail["error"] = redactor.String(probeErr.Error())
+	}
+	if deviceHealth.LastError != "" {
+		deviceDetail["lastError"] = redactor.String(deviceHealth.LastError)
+		deviceDetail["lastErrorAt"] = timezone.ISO(deviceHealth.LastErrorAt)
+	}
+	health["device"] = deviceDetail
+
+	now := time.Now()
+	stack := stateManager.GetEffectStack()
+	effectNames := make([]string, 0, len(stack))
+	for i := len(stack) - 1; i >= 0; i-- {
+		effectNames = append(effectNames, stack[i].Name)
+	}
+	stackDetail := map[string]interface{}{
+		"depth":   len(stack),
+		"effects": effectNames, // top first
+	}
+	if len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stackDetail["current"] = top.Name
+		stackDetail["paused"] = top.Paused()
+		if remaining, timed := top.Remaining(now); timed {
+			stackDetail["remainingMs"] = remaining.Milliseconds()
+		}
+	}
+	health["effectStack"] = stackDetail
+
+	storageErr := effectsStore.CheckWritable()
+	storageDetail := map[string]interface{}{"writable": storageErr == nil}
+	if storageErr != nil {
+		storageDetail["error"] = storageErr.Error()
+	}
+	health["storage"] = storageDetail
+
+	impaired := featureRegistry.Impaired()
+	for i := range impaired {
+		impaired[i].Reason = redactor.String(impaired[i].Reason)
+	}
+	health["features"] = map[string]interface{}{
+		"total":    len(featureRegistry.List()),
+		"impaired": impaired,
+	}
+
+	if deviceStatus != deviceReady || storageErr != nil || len(impaired) > 0 {
+		health["status"] = "degraded"
+	}
+}
+
+// loadTLSConfig returns the TLS configuration the HTTP transport is served
+// with, or nil to serve it in plain text
+func loadTLSConfig(opts options) (*tls.Config, error) {
+	switch {
+	case opts.tlsCert != "":
+		config, err := tlscert.Config(opts.tlsCert, opts.tlsKey)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("Serving HTTPS", "cert", opts.tlsCert, "fingerprint", tlscert.Fingerprint(config))
+		return config, nil
+	case opts.tlsSelfSigned:
+		config, err := tlscert.SelfSignedConfig()
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("Serving HTTPS with a self-signed certificate; clients must trust it explicitly",
+			"hosts", config.Certificates[0].Leaf.DNSNames, "ips", config.Certificates[0].Leaf.IPAddresses,
+			"fingerprint", tlscert.Fingerprint(config), "validFor", tlscert.SelfSignedValidity)
+		return config, nil
+	}
+	return nil, nil
+}
+
+func startHTTPServer(mcpServer *server.MCPServer, port string, ctx context.Context, handlers map[string]http.Handler, authenticator *auth.Authenticator, tlsConfig *tls.Config, corsPolicy *cors.Policy, detail func(ctx context.Context, health map[string]interface{}), readiness func(ctx context.Context) (map[string]interface{}, string)) {
 	// Create the MCP streamable HTTP server
-	mcpHandler := server.NewStreamableHTTPServer(mcpServer)
+	mcpHandler := server.NewStreamableHTTPServer(mcpServer, server.WithHTTPContextFunc(func(ctx context.Context, r *http.Request) context.Context {
+		return audit.WithOrigin(ctx, audit.Origin{RemoteAddr: r.RemoteAddr, UserAgent: r.UserAgent()})
+	}))
 	
 	// Create a mux to handle both MCP and health check
 	mux := http.NewServeMux()
 	
+	// Everything but the health check requires a token when one is configured
+	protect := func(handler http.Handler) http.Handler {
+		if authenticator == nil {
+			return handler
+		}
+		return authenticator.Middleware(handler)
+	}
+
 	// Mount MCP handler at /mcp
-	mux.Handle("/mcp", mcpHandler)
+	mux.Handle("/mcp", protect(mcpHandler))
 	
 	// Add health check endpoint
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
@@ -266,30 +1806,88 @@ func startHTTPServer(mcpServer *server.MCPServer, port string, ctx context.Conte
 			"specVersion": version.SpecVersion,
 			"uptime":      time.Since(startTime).String(),
 		}
+
+		// Detail mode reveals device errors and effect names, so it needs
+		// the same token as the other endpoints
+		if wantDetail, _ := strconv.ParseBool(r.URL.Query().Get("detail")); wantDetail {
+			if authenticator != nil && !authenticator.Authenticate(r) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="ufo-mcp"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			detail(r.Context(), health)
+		}
 		
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(health)
 	})
+
+	// Add readiness endpoint: unlike /healthz it probes the UFO, and answers
+	// 503 while the UFO cannot be reached
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		connectivity, status := readiness(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if status == deviceUnavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": status,
+			"device": connectivity,
+		})
+	})
 	
+	// Mount integration webhooks and metrics. Signed webhooks authenticate
+	// senders by their signature, since CI systems cannot send a token.
+	for path, handler := range handlers {
+		if signed, ok := handler.(interface{ Signed() bool }); ok && signed.Signed() {
+			mux.Handle(path, handler)
+			continue
+		}
+		mux.Handle(path, protect(handler))
+	}
+
+	// Browsers send preflights without credentials, so CORS goes in front
+	// of authentication
+	var handler http.Handler = mux
+	if corsPolicy != nil {
+		handler = corsPolicy.Middleware(mux)
+	}
+
 	// Create HTTP/2 server
 	h2s := &http2.Server{}
 	
 	// Create server with HTTP/2 support
 	httpServer := &http.Server{
 		Addr:         ":" + port,
-		Handler:      h2c.NewHandler(mux, h2s),
+		Handler:      h2c.NewHandler(handler, h2s),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 0, // No timeout for streaming
 		IdleTimeout:  120 * time.Second,
+		TLSConfig:    tlsConfig,
 	}
 	
 	// Start server with graceful shutdown
 	go func() {
-		log.Printf("HTTP server listening on %s", httpServer.Addr)
-		log.Printf("  MCP endpoint: http://localhost%s/mcp", httpServer.Addr)
-		log.Printf("  Health check: http://localhost%s/healthz", httpServer.Addr)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("HTTP server error: %v", err)
+		endpoints := []string{"/mcp", "/healthz", "/readyz"}
+		for path := range handlers {
+			endpoints = append(endpoints, path)
+		}
+		var err error
+		if tlsConfig != nil {
+			// HTTP/2 is negotiated through ALPN over TLS
+			slog.Info("HTTPS server listening", "addr", httpServer.Addr, "endpoints", endpoints)
+			err = httpServer.ListenAndServeTLS("", "")
+		} else {
+			slog.Info("HTTP server listening", "addr", httpServer.Addr, "endpoints", endpoints)
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			logging.Fatal("HTTP server error", "error", err)
 		}
 	}()
 
@@ -301,14 +1899,35 @@ func startHTTPServer(mcpServer *server.MCPServer, port string, ctx context.Conte
 	defer cancel()
 	
 	if err := httpServer.Shutdown(shutdownCtx); err != nil {
-		log.Printf("HTTP server shutdown error: %v", err)
+		slog.Error("HTTP server shutdown error", "error", err)
+	}
+	slog.Info("HTTP server stopped")
+}
+
+// startGRPCServer serves the gRPC management API on port until ctx is
+// cancelled. Its calls run the MCP tools through the server, middleware
+// included.
+func startGRPCServer(ctx context.Context, port string, mcpServer *server.MCPServer, broadcaster *events.Broadcaster, authenticator *auth.Authenticator) {
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		logging.Fatal("Failed to listen for gRPC", "port", port, "error", err)
 	}
-	log.Println("HTTP server stopped")
+	if authenticator == nil {
+		slog.Warn("gRPC authentication is disabled; set --auth-token to require a token")
+	}
+	service := grpcapi.NewServer(serverToolCaller{server: mcpServer}, broadcaster)
+	go func() {
+		slog.Info("gRPC server listening", "addr", listener.Addr().String())
+		if err := grpcapi.Serve(ctx, listener, service, authenticator); err != nil {
+			logging.Fatal("gRPC server error", "error", err)
+		}
+	}()
 }
 
 func startStdioServer(mcpServer *server.MCPServer) {
-	log.Printf("Starting stdio server...")
-	if err := server.ServeStdio(mcpServer); err != nil {
-		log.Fatalf("Stdio server error: %v", err)
+	slog.Info("Starting stdio server")
+	// A signal ends the server by cancelling its context
+	if err := server.ServeStdio(mcpServer); err != nil && !errors.Is(err, context.Canceled) {
+		logging.Fatal("Stdio server error", "error", err)
 	}
 }
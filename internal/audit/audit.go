@@ -0,0 +1,164 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/redact"
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+)
+
+// Entry represents a single audit record
+type Entry struct {
+	Time   time.Time              `json:"time"`
+	Kind   string                 `json:"kind"`             // subsystem that produced the entry (hook, policy, ...)
+	Action string                 `json:"action"`           // what was done
+	Result string                 `json:"result,omitempty"` // outcome summary
+	Data   map[string]interface{} `json:"data,omitempty"`   // additional details
+}
+
+// Logger appends audit entries as JSON lines. With rotation set, a file
+// that would grow past its size limit is renamed to path.1 (path.1 to
+// path.2 and so on) and a new file started.
+type Logger struct {
+	mu       sync.Mutex
+	path     string
+	file     *os.File
+	size     int64 // bytes in the current file
+	maxBytes int64 // rotate before exceeding this size; 0 never rotates
+	keep     int   // rotated files kept
+	redactor *redact.Redactor
+}
+
+// NewLogger creates an audit logger writing to path. An empty path writes
+// entries to the standard logger instead.
+func NewLogger(path string) (*Logger, error) {
+	if path == "" {
+		return &Logger{}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating audit log directory: %w", err)
+	}
+
+	logger := &Logger{path: path}
+	if err := logger.open(); err != nil {
+		return nil, err
+	}
+	return logger, nil
+}
+
+// open opens the log file for appending
+func (l *Logger) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	l.file, l.size = file, info.Size()
+	return nil
+}
+
+// SetRotation rotates the log file before it grows past maxBytes, keeping
+// keep rotated files. maxBytes of 0 or less disables rotation.
+func (l *Logger) SetRotation(maxBytes int64, keep int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxBytes = maxBytes
+	l.keep = max(keep, 1)
+}
+
+// rotate renames the current file to path.1, shifting older ones and
+// dropping the oldest, and starts a new file
+func (l *Logger) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	l.file = nil
+	os.Remove(rotatedPath(l.path, l.keep))
+	for n := l.keep - 1; n >= 1; n-- {
+		os.Rename(rotatedPath(l.path, n), rotatedPath(l.path, n+1))
+	}
+	if err := os.Rename(l.path, rotatedPath(l.path, 1)); err != nil {
+		// Keep appending to the current file
+		if openErr := l.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("rotating audit log: %w", err)
+	}
+	return l.open()
+}
+
+// rotatedPath returns the name of the nth rotated file
+func rotatedPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+// SetRedactor masks sensitive values in every entry recorded from now on
+func (l *Logger) SetRedactor(r *redact.Redactor) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.redactor = r
+}
+
+// Record appends an entry to the audit log
+func (l *Logger) Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = timezone.Now()
+	}
+
+	l.mu.Lock()
+	redactor := l.redactor
+	l.mu.Unlock()
+	entry.Action = redactor.String(entry.Action)
+	entry.Result = redactor.String(entry.Result)
+	entry.Data = redactor.Map(entry.Data)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshaling audit entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		slog.Info("AUDIT", "entry", json.RawMessage(data))
+		return nil
+	}
+
+	line := append(data, '\n')
+	if l.maxBytes > 0 && l.size > 0 && l.size+int64(len(line)) > l.maxBytes {
+		if err := l.rotate(); err != nil {
+			slog.Error("Failed to rotate audit log", "error", err)
+			if l.file == nil {
+				return err
+			}
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	return err
+}
+
+// Close closes the underlying audit file
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -0,0 +1,171 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/redact"
+)
+
+func TestLogger_Record(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+
+	logger, err := NewLogger(path)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	if err := logger.Record(Entry{Kind: "hook", Action: "notify", Result: "OK"}); err != nil {
+		t.Fatalf("failed to record entry: %v", err)
+	}
+	if err := logger.Record(Entry{Kind: "hook", Action: "notify", Result: "ERROR"}); err != nil {
+		t.Fatalf("failed to record entry: %v", err)
+	}
+	logger.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 audit lines, got %d", len(lines))
+	}
+
+	var entry Entry
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("failed to parse audit line: %v", err)
+	}
+	if entry.Result != "ERROR" || entry.Time.IsZero() {
+		t.Errorf("unexpected audit entry: %+v", entry)
+	}
+}
+
+func TestLogger_NoFile(t *testing.T) {
+	logger, err := NewLogger("")
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	if err := logger.Record(Entry{Kind: "test", Action: "noop"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLogger_Redacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := NewLogger(path)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	redactor, _ := redact.New(nil)
+	logger.SetRedactor(redactor)
+
+	logger.Record(Entry{Kind: "policy", Action: "sendRawApi", Data: map[string]interface{}{"query": "dim=10&token=abc"}})
+	logger.Close()
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "abc") || !strings.Contains(string(data), "token=[redacted]") {
+		t.Errorf("expected token redacted, got %s", data)
+	}
+}
+
+func TestLogger_Rotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := NewLogger(path)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.SetRotation(300, 2)
+
+	for i := 0; i < 10; i++ {
+		logger.Record(Entry{Kind: "device", Action: fmt.Sprintf("dim=%d", i), Result: "OK"})
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("expected %s: %v", name, err)
+		}
+		if info.Size() > 300 {
+			t.Errorf("expected %s to stay under the limit, got %d bytes", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Error("expected only 2 rotated files to be kept")
+	}
+
+	entries, err := logger.Query(Filter{Kind: "device"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 || entries[len(entries)-1].Action != "dim=9" {
+		t.Fatalf("expected the newest entry last, got %+v", entries)
+	}
+	for i := 1; i < len(entries); i++ {
+		if entries[i].Time.Before(entries[i-1].Time) {
+			t.Errorf("expected entries oldest first, got %+v", entries)
+		}
+	}
+}
+
+func TestLogger_Query(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := NewLogger(path)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Record(Entry{Kind: "device", Action: "dim=10", Result: "OK", Data: map[string]interface{}{"tool": "setBrightness", "client": "claude"}})
+	logger.Record(Entry{Kind: "device", Action: "top=0|15|FF0000", Result: "ERROR: offline", Data: map[string]interface{}{"tool": "setRingPattern", "client": "cron"}})
+	logger.Record(Entry{Kind: "policy", Action: "sendRawApi", Result: "denied"})
+	logger.file.Write([]byte("not json\n"))
+
+	tests := []struct {
+		filter   Filter
+		expected int
+	}{
+		{Filter{}, 3},
+		{Filter{Kind: "device"}, 2},
+		{Filter{Tool: "setBrightness"}, 1},
+		{Filter{Client: "cron", ErrorsOnly: true}, 1},
+		{Filter{Action: "TOP="}, 1},
+		{Filter{Limit: 1}, 1},
+		{Filter{Since: time.Now().Add(time.Hour)}, 0},
+	}
+	for _, tt := range tests {
+		entries, err := logger.Query(tt.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != tt.expected {
+			t.Errorf("filter %+v: expected %d entries, got %+v", tt.filter, tt.expected, entries)
+		}
+	}
+
+	stderrLogger, _ := NewLogger("")
+	if _, err := stderrLogger.Query(Filter{}); err != ErrNoFile {
+		t.Errorf("expected ErrNoFile without a file, got %v", err)
+	}
+}
+
+func TestWithOrigin(t *testing.T) {
+	ctx := WithOrigin(context.Background(), Origin{RemoteAddr: "10.0.0.7:5123", UserAgent: "curl/8"})
+	ctx = WithOrigin(ctx, Origin{Tool: "setBrightness", Arguments: map[string]interface{}{"level": 10.0}, Client: "claude"})
+
+	fields := OriginFrom(ctx).Fields()
+	if fields["tool"] != "setBrightness" || fields["client"] != "claude" || fields["remoteAddr"] != "10.0.0.7:5123" || fields["userAgent"] != "curl/8" {
+		t.Errorf("expected both origins merged, got %v", fields)
+	}
+	if len(OriginFrom(context.Background()).Fields()) != 0 {
+		t.Error("expected no fields without an origin")
+	}
+}
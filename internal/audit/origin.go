@@ -0,0 +1,60 @@
+package audit
+
+import "context"
+
+// Origin describes what caused an action: the tool call and, on the HTTP
+// transport, the request that carried it
+type Origin struct {
+	Tool       string                 // tool that was called
+	Arguments  map[string]interface{} // its arguments
+	Client     string                 // MCP client name or session ID
+	RemoteAddr string                 // HTTP client address
+	UserAgent  string                 // HTTP client user agent
+}
+
+type originKey struct{}
+
+// WithOrigin returns a context carrying origin. Fields origin leaves empty
+// keep the values of an origin ctx already carries, so the HTTP request and
+// the tool call can each add theirs.
+func WithOrigin(ctx context.Context, origin Origin) context.Context {
+	outer := OriginFrom(ctx)
+	if origin.Tool == "" {
+		origin.Tool, origin.Arguments = outer.Tool, outer.Arguments
+	}
+	if origin.Client == "" {
+		origin.Client = outer.Client
+	}
+	if origin.RemoteAddr == "" {
+		origin.RemoteAddr = outer.RemoteAddr
+	}
+	if origin.UserAgent == "" {
+		origin.UserAgent = outer.UserAgent
+	}
+	return context.WithValue(ctx, originKey{}, origin)
+}
+
+// OriginFrom returns the origin ctx carries, empty if none
+func OriginFrom(ctx context.Context) Origin {
+	origin, _ := ctx.Value(originKey{}).(Origin)
+	return origin
+}
+
+// Fields returns the origin's non-empty fields as audit entry data
+func (o Origin) Fields() map[string]interface{} {
+	fields := map[string]interface{}{}
+	for name, value := range map[string]string{
+		"tool":       o.Tool,
+		"client":     o.Client,
+		"remoteAddr": o.RemoteAddr,
+		"userAgent":  o.UserAgent,
+	} {
+		if value != "" {
+			fields[name] = value
+		}
+	}
+	if len(o.Arguments) > 0 {
+		fields["arguments"] = o.Arguments
+	}
+	return fields
+}
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrNoFile is returned by Query when entries are logged instead of
+// written to a file
+var ErrNoFile = errors.New("the audit log is not written to a file")
+
+// Filter selects audit entries. Empty fields match every entry.
+type Filter struct {
+	Kind       string    // entry kind, e.g. "device" or "policy"
+	Action     string    // text the action contains, ignoring case
+	Tool       string    // tool that caused the entry
+	Client     string    // client that called the tool
+	ErrorsOnly bool      // only entries whose result is an error
+	Since      time.Time // earliest time, inclusive
+	Until      time.Time // latest time, inclusive
+	Limit      int       // keep only the newest Limit matches
+}
+
+// matches reports whether an entry passes the filter
+func (f Filter) matches(entry Entry) bool {
+	if f.Kind != "" && entry.Kind != f.Kind {
+		return false
+	}
+	if f.Action != "" && !strings.Contains(strings.ToLower(entry.Action), strings.ToLower(f.Action)) {
+		return false
+	}
+	if f.Tool != "" && entry.Data["tool"] != f.Tool {
+		return false
+	}
+	if f.Client != "" && entry.Data["client"] != f.Client {
+		return false
+	}
+	if f.ErrorsOnly && !strings.HasPrefix(entry.Result, "ERROR") {
+		return false
+	}
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && entry.Time.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Query reads the entries matching filter from the log file and the files
+// rotated out of it, oldest first. Lines that are not entries are skipped.
+func (l *Logger) Query(filter Filter) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.path == "" {
+		return nil, ErrNoFile
+	}
+
+	var paths []string
+	for n := l.keep; n >= 1; n-- {
+		paths = append(paths, rotatedPath(l.path, n))
+	}
+	paths = append(paths, l.path)
+
+	var entries []Entry
+	for _, path := range paths {
+		matched, err := readEntries(path, filter)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, matched...)
+		if filter.Limit > 0 && len(entries) > filter.Limit {
+			entries = entries[len(entries)-filter.Limit:]
+		}
+	}
+	return entries, nil
+}
+
+// readEntries reads the entries of one file that match filter; a missing
+// file has none
+func readEntries(path string, filter Filter) ([]Entry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+			continue
+		}
+		if filter.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// APIKeyHeader is the header checked for an API key when no bearer token is sent
+const APIKeyHeader = "X-API-Key"
+
+// Authenticator checks HTTP requests for a bearer token or API key
+type Authenticator struct {
+	digests [][sha256.Size]byte // digests of accepted tokens
+}
+
+// New creates an authenticator accepting any of the given tokens. Empty
+// tokens are ignored; it returns nil when no token remains, meaning
+// authentication is disabled.
+func New(tokens []string) *Authenticator {
+	var digests [][sha256.Size]byte
+	for _, token := range tokens {
+		token = strings.TrimSpace(token)
+		if token != "" {
+			digests = append(digests, sha256.Sum256([]byte(token)))
+		}
+	}
+	if len(digests) == 0 {
+		return nil
+	}
+	return &Authenticator{digests: digests}
+}
+
+// ParseTokens splits a comma-separated token list, so a new token can be
+// rolled out before the old one is removed
+func ParseTokens(value string) []string {
+	var tokens []string
+	for _, token := range strings.Split(value, ",") {
+		if token = strings.TrimSpace(token); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// Authenticate reports whether the request carries an accepted token, either
+// as "Authorization: Bearer <token>" or in the X-API-Key header
+func (a *Authenticator) Authenticate(r *http.Request) bool {
+	return a.AuthenticateCredentials(r.Header.Get("Authorization"), r.Header.Get(APIKeyHeader))
+}
+
+// AuthenticateCredentials reports whether an Authorization header value or
+// an API key carries an accepted token, for callers without an HTTP request
+// such as the gRPC server
+func (a *Authenticator) AuthenticateCredentials(authorization, apiKey string) bool {
+	token := apiKey
+	if authorization != "" {
+		scheme, credentials, found := strings.Cut(authorization, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") {
+			return false
+		}
+		token = strings.TrimSpace(credentials)
+	}
+	if token == "" {
+		return false
+	}
+
+	// Compare digests in constant time so response timing leaks nothing
+	digest := sha256.Sum256([]byte(token))
+	matched := 0
+	for _, accepted := range a.digests {
+		matched |= subtle.ConstantTimeCompare(digest[:], accepted[:])
+	}
+	return matched == 1
+}
+
+// Middleware rejects unauthenticated requests with 401 Unauthorized
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.Authenticate(r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="ufo-mcp"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
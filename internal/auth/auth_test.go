@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNew_DisabledWithoutTokens(t *testing.T) {
+	if New(nil) != nil {
+		t.Error("expected nil authenticator without tokens")
+	}
+	if New([]string{"", "  "}) != nil {
+		t.Error("expected nil authenticator with only empty tokens")
+	}
+}
+
+func TestParseTokens(t *testing.T) {
+	tokens := ParseTokens(" old , new,,")
+	if len(tokens) != 2 || tokens[0] != "old" || tokens[1] != "new" {
+		t.Errorf("unexpected tokens: %q", tokens)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	authenticator := New([]string{"old-token", "new-token"})
+	handler := authenticator.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"no credentials", nil, http.StatusUnauthorized},
+		{"bearer token", map[string]string{"Authorization": "Bearer new-token"}, http.StatusOK},
+		{"lowercase scheme", map[string]string{"Authorization": "bearer old-token"}, http.StatusOK},
+		{"api key", map[string]string{APIKeyHeader: "old-token"}, http.StatusOK},
+		{"wrong token", map[string]string{"Authorization": "Bearer nope"}, http.StatusUnauthorized},
+		{"basic scheme", map[string]string{"Authorization": "Basic new-token"}, http.StatusUnauthorized},
+		{"bad bearer with valid api key", map[string]string{"Authorization": "Bearer nope", APIKeyHeader: "new-token"}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		for name, value := range tt.headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, rec.Code)
+		}
+		if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: expected WWW-Authenticate header", tt.name)
+		}
+	}
+}
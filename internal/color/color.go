@@ -0,0 +1,84 @@
+package color
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse converts a color specification into the 6-character hex format used
+// by the UFO firmware. Accepted forms are:
+//   - 6-character hex, with or without a leading '#' ("FF0000", "#ff0000")
+//   - 3-character shorthand hex ("#F00", "f00")
+//   - rgb(r,g,b) with components 0-255 ("rgb(255, 0, 0)")
+//   - CSS named colors ("red", "dodgerblue")
+//
+// Plain 6-character hex is returned unchanged; all other forms are returned
+// as lowercase hex.
+func Parse(spec string) (string, error) {
+	s := strings.TrimSpace(spec)
+	if s == "" {
+		return "", fmt.Errorf("color must not be empty")
+	}
+
+	if hex, ok := named[strings.ToLower(s)]; ok {
+		return hex, nil
+	}
+
+	lower := strings.ToLower(s)
+	if strings.HasPrefix(lower, "rgb(") && strings.HasSuffix(lower, ")") {
+		return parseRGB(lower[4 : len(lower)-1])
+	}
+
+	hex := strings.TrimPrefix(s, "#")
+	if !isHex(hex) {
+		return "", fmt.Errorf("invalid color %q: use 6-char hex (FF0000), #RGB shorthand, rgb(r,g,b) or a CSS color name", spec)
+	}
+
+	switch len(hex) {
+	case 6:
+		return hex, nil
+	case 3:
+		hex = strings.ToLower(hex)
+		return string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]}), nil
+	}
+
+	return "", fmt.Errorf("invalid color %q: hex colors must have 3 or 6 digits", spec)
+}
+
+// IsValid reports whether spec can be parsed as a color
+func IsValid(spec string) bool {
+	_, err := Parse(spec)
+	return err == nil
+}
+
+// parseRGB parses the "r,g,b" body of an rgb() color
+func parseRGB(body string) (string, error) {
+	parts := strings.Split(body, ",")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid rgb color: expected 3 components, got %d", len(parts))
+	}
+
+	var hex strings.Builder
+	for _, part := range parts {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || value < 0 || value > 255 {
+			return "", fmt.Errorf("invalid rgb component %q: must be an integer 0-255", strings.TrimSpace(part))
+		}
+		fmt.Fprintf(&hex, "%02x", value)
+	}
+	return hex.String(), nil
+}
+
+// isHex reports whether s contains only hex digits
+func isHex(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !((c >= '0' && c <= '9') || (c >= 'A' && c <= 'F') || (c >= 'a' && c <= 'f')) {
+			return false
+		}
+	}
+	return true
+}
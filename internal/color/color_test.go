@@ -0,0 +1,45 @@
+package color
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain hex keeps case", "FF00aa", "FF00aa"},
+		{"hex with hash", "#00FF00", "00FF00"},
+		{"shorthand hex", "#F0a", "ff00aa"},
+		{"shorthand without hash", "abc", "aabbcc"},
+		{"named color", "red", "ff0000"},
+		{"named color mixed case", "DodgerBlue", "1e90ff"},
+		{"rgb", "rgb(255,128,0)", "ff8000"},
+		{"rgb with spaces", " RGB( 1, 2, 3 ) ", "010203"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	inputs := []string{"", "notacolor", "GGGGGG", "#12345", "rgb(256,0,0)", "rgb(1,2)", "rgb(a,b,c)"}
+
+	for _, input := range inputs {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+		if IsValid(input) {
+			t.Errorf("expected %q to be invalid", input)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package color
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Easing curves for Gradient
+const (
+	EaseLinear    = "linear"    // even steps
+	EaseIn        = "easeIn"    // changes slowly at first
+	EaseOut       = "easeOut"   // changes slowly at the end
+	EaseInOut     = "easeInOut" // changes slowly at both ends
+	DefaultEasing = EaseLinear
+)
+
+// Easings lists the easing curves Gradient accepts
+var Easings = []string{EaseLinear, EaseIn, EaseOut, EaseInOut}
+
+// Blend mixes two colors, each in any form Parse accepts: t=0 gives from,
+// t=1 gives to, values between interpolate each RGB channel
+func Blend(from, to string, t float64) (string, error) {
+	a, err := channels(from)
+	if err != nil {
+		return "", err
+	}
+	b, err := channels(to)
+	if err != nil {
+		return "", err
+	}
+	t = math.Max(0, math.Min(1, t))
+	var mixed [3]int
+	for i := range mixed {
+		mixed[i] = int(math.Round(float64(a[i]) + (float64(b[i])-float64(a[i]))*t))
+	}
+	return fmt.Sprintf("%02x%02x%02x", mixed[0], mixed[1], mixed[2]), nil
+}
+
+// Gradient returns steps colors running from from to to, both included, and
+// spaced along the easing curve
+func Gradient(from, to string, steps int, easing string) ([]string, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("a gradient needs at least 1 step, got %d", steps)
+	}
+	ease, err := easingCurve(easing)
+	if err != nil {
+		return nil, err
+	}
+	colors := make([]string, steps)
+	for i := range colors {
+		t := 0.0
+		if steps > 1 {
+			t = float64(i) / float64(steps-1)
+		}
+		if colors[i], err = Blend(from, to, ease(t)); err != nil {
+			return nil, err
+		}
+	}
+	return colors, nil
+}
+
+// easingCurve returns the curve named easing, mapping 0-1 onto 0-1
+func easingCurve(easing string) (func(float64) float64, error) {
+	switch easing {
+	case "", EaseLinear:
+		return func(t float64) float64 { return t }, nil
+	case EaseIn:
+		return func(t float64) float64 { return t * t }, nil
+	case EaseOut:
+		return func(t float64) float64 { return 1 - (1-t)*(1-t) }, nil
+	case EaseInOut:
+		return func(t float64) float64 {
+			if t < 0.5 {
+				return 2 * t * t
+			}
+			return 1 - 2*(1-t)*(1-t)
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown easing %q: use %s, %s, %s or %s", easing, EaseLinear, EaseIn, EaseOut, EaseInOut)
+}
+
+// channels parses a color into its red, green and blue values
+func channels(spec string) ([3]int, error) {
+	var rgb [3]int
+	hex, err := Parse(spec)
+	if err != nil {
+		return rgb, err
+	}
+	for i := range rgb {
+		value, err := strconv.ParseUint(hex[2*i:2*i+2], 16, 8)
+		if err != nil {
+			return rgb, fmt.Errorf("invalid color %q: %v", spec, err)
+		}
+		rgb[i] = int(value)
+	}
+	return rgb, nil
+}
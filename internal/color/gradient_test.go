@@ -0,0 +1,59 @@
+package color
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBlend(t *testing.T) {
+	tests := []struct {
+		from, to string
+		t        float64
+		expected string
+	}{
+		{"red", "blue", 0, "ff0000"},
+		{"red", "blue", 1, "0000ff"},
+		{"000000", "FFFFFF", 0.5, "808080"},
+		{"#F00", "rgb(0,255,0)", 0.25, "bf4000"},
+		{"black", "white", 2, "ffffff"},
+	}
+	for _, tt := range tests {
+		got, err := Blend(tt.from, tt.to, tt.t)
+		if err != nil {
+			t.Fatalf("Blend(%s, %s, %v): %v", tt.from, tt.to, tt.t, err)
+		}
+		if got != tt.expected {
+			t.Errorf("Blend(%s, %s, %v) = %s, expected %s", tt.from, tt.to, tt.t, got, tt.expected)
+		}
+	}
+	if _, err := Blend("red", "notacolor", 0.5); err == nil {
+		t.Error("expected an error for an invalid color")
+	}
+}
+
+func TestGradient(t *testing.T) {
+	linear, err := Gradient("000000", "ffffff", 5, EaseLinear)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"000000", "404040", "808080", "bfbfbf", "ffffff"}; !reflect.DeepEqual(linear, expected) {
+		t.Errorf("expected %v, got %v", expected, linear)
+	}
+
+	easeIn, _ := Gradient("000000", "ffffff", 5, EaseIn)
+	easeOut, _ := Gradient("000000", "ffffff", 5, EaseOut)
+	easeInOut, _ := Gradient("000000", "ffffff", 5, EaseInOut)
+	if easeIn[1] != "101010" || easeOut[1] != "707070" || easeInOut[2] != "808080" {
+		t.Errorf("unexpected eased steps: in %v, out %v, in-out %v", easeIn, easeOut, easeInOut)
+	}
+
+	if single, _ := Gradient("red", "blue", 1, ""); !reflect.DeepEqual(single, []string{"ff0000"}) {
+		t.Errorf("expected a single step to be the start color, got %v", single)
+	}
+	if _, err := Gradient("red", "blue", 5, "bounce"); err == nil {
+		t.Error("expected an error for an unknown easing")
+	}
+	if _, err := Gradient("red", "blue", 0, ""); err == nil {
+		t.Error("expected an error for no steps")
+	}
+}
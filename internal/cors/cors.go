@@ -0,0 +1,163 @@
+package cors
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultHeaders are the request headers browsers may send by default: the
+// credentials of the auth package and the headers of the streamable HTTP
+// transport
+const DefaultHeaders = "Authorization, Content-Type, X-API-Key, Mcp-Session-Id, Mcp-Protocol-Version, Last-Event-ID"
+
+// DefaultMethods are the methods browsers may use by default
+const DefaultMethods = "GET, POST, DELETE"
+
+// exposedHeaders are the response headers scripts may read; MCP clients
+// need the session ID to continue a session
+const exposedHeaders = "Mcp-Session-Id, WWW-Authenticate"
+
+// maxAge is how long browsers may cache a preflight answer
+const maxAge = 10 * time.Minute
+
+// Policy decides which browser origins may call the HTTP endpoints
+type Policy struct {
+	anyOrigin bool
+	origins   map[string]bool // exact origins, lowercased
+	wildcards []wildcard      // origins of any subdomain
+	headers   string
+	methods   string
+}
+
+// wildcard matches the origins of the subdomains of a domain, port included
+type wildcard struct {
+	scheme string
+	suffix string // "." + domain
+}
+
+// New creates a policy letting the given origins call with the given request
+// headers and methods. An origin is "*" for any, an exact origin such as
+// "https://dash.example.com", or a wildcard such as "https://*.example.com"
+// for its subdomains. New returns nil when no origin remains, meaning
+// cross-origin requests are not answered with CORS headers.
+func New(origins, headers, methods []string) (*Policy, error) {
+	p := &Policy{origins: map[string]bool{}}
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimRight(strings.TrimSpace(origin), "/"))
+		switch {
+		case origin == "":
+			continue
+		case origin == "*":
+			p.anyOrigin = true
+			continue
+		}
+		scheme, host, found := strings.Cut(origin, "://")
+		if !found || scheme == "" || host == "" || strings.ContainsAny(host, "/?#") {
+			return nil, fmt.Errorf("invalid origin %q: use scheme://host[:port], e.g. https://dash.example.com", origin)
+		}
+		if domain, ok := strings.CutPrefix(host, "*."); ok {
+			if domain == "" || strings.Contains(domain, "*") {
+				return nil, fmt.Errorf("invalid origin %q: a wildcard must be followed by a domain", origin)
+			}
+			p.wildcards = append(p.wildcards, wildcard{scheme: scheme, suffix: "." + domain})
+			continue
+		}
+		if strings.Contains(host, "*") {
+			return nil, fmt.Errorf("invalid origin %q: a wildcard may only replace the leftmost label", origin)
+		}
+		if _, err := url.Parse(origin); err != nil {
+			return nil, fmt.Errorf("invalid origin %q: %w", origin, err)
+		}
+		p.origins[origin] = true
+	}
+	if !p.anyOrigin && len(p.origins) == 0 && len(p.wildcards) == 0 {
+		return nil, nil
+	}
+	p.headers = strings.Join(orDefault(headers, DefaultHeaders), ", ")
+	p.methods = strings.Join(orDefault(methods, DefaultMethods), ", ")
+	return p, nil
+}
+
+// ParseList splits a comma-separated list, dropping empty entries
+func ParseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// orDefault returns items, or the items of the comma-separated fallback
+// when there are none
+func orDefault(items []string, fallback string) []string {
+	if len(items) == 0 {
+		return ParseList(fallback)
+	}
+	return items
+}
+
+// Allowed reports whether scripts from origin may call the endpoints
+func (p *Policy) Allowed(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if p.origins[origin] {
+		return true
+	}
+	for _, w := range p.wildcards {
+		host, found := strings.CutPrefix(origin, w.scheme+"://")
+		if found && strings.HasSuffix(host, w.suffix) && len(host) > len(w.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware answers preflight requests and adds CORS headers to the
+// responses for allowed origins. It must wrap authentication, since browsers
+// send preflights without credentials.
+func (p *Policy) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if !p.Allowed(origin) {
+			if preflight {
+				http.Error(w, "Origin not allowed", http.StatusForbidden)
+				return
+			}
+			// Without CORS headers the browser withholds the response
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if p.anyOrigin {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			header.Set("Access-Control-Allow-Methods", p.methods)
+			header.Set("Access-Control-Allow-Headers", p.headers)
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(maxAge.Seconds())))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		header.Set("Access-Control-Expose-Headers", exposedHeaders)
+		next.ServeHTTP(w, r)
+	})
+}
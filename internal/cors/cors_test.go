@@ -0,0 +1,109 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	policy, err := New(nil, nil, nil)
+	if policy != nil || err != nil {
+		t.Errorf("expected no policy without origins, got %v, %v", policy, err)
+	}
+	for _, origin := range []string{"dash.example.com", "https://", "https://a.*.example.com", "https://*.", "https://dash.example.com/path"} {
+		if _, err := New([]string{origin}, nil, nil); err == nil {
+			t.Errorf("expected an error for origin %q", origin)
+		}
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	policy, err := New([]string{"https://Dash.example.com/", "https://*.lab.example.com", "http://localhost:3000"}, nil, nil)
+	if err != nil {
+		t.Fatalf("creating policy: %v", err)
+	}
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://dash.example.com", true},
+		{"https://DASH.example.com", true},
+		{"http://dash.example.com", false},
+		{"https://ufo.lab.example.com", true},
+		{"https://a.b.lab.example.com", true},
+		{"https://lab.example.com", false},
+		{"https://evil-lab.example.com", false},
+		{"http://localhost:3000", true},
+		{"http://localhost:3001", false},
+		{"null", false},
+	}
+	for _, tt := range tests {
+		if got := policy.Allowed(tt.origin); got != tt.want {
+			t.Errorf("Allowed(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+
+	open, _ := New([]string{"*"}, nil, nil)
+	if !open.Allowed("https://anywhere.example") {
+		t.Error("expected * to allow any origin")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	policy, err := New([]string{"https://dash.example.com"}, nil, []string{"GET", "POST"})
+	if err != nil {
+		t.Fatalf("creating policy: %v", err)
+	}
+	called := 0
+	handler := policy.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called++
+		w.Write([]byte("ok"))
+	}))
+	serve := func(method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/mcp", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Preflights are answered without reaching the handler
+	rec := serve(http.MethodOptions, "https://dash.example.com", map[string]string{"Access-Control-Request-Method": "POST"})
+	if rec.Code != http.StatusNoContent || called != 0 {
+		t.Errorf("expected 204 without calling the handler, got %d after %d calls", rec.Code, called)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://dash.example.com" {
+		t.Errorf("expected the origin allowed, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST" {
+		t.Errorf("expected the configured methods, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); got != DefaultHeaders {
+		t.Errorf("expected the default headers, got %q", got)
+	}
+
+	rec = serve(http.MethodOptions, "https://evil.example.com", map[string]string{"Access-Control-Request-Method": "POST"})
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a preflight from another origin, got %d", rec.Code)
+	}
+
+	// Requests reach the handler, with CORS headers only for allowed origins
+	rec = serve(http.MethodPost, "https://dash.example.com", nil)
+	if called != 1 || rec.Header().Get("Access-Control-Expose-Headers") == "" {
+		t.Errorf("expected the handler called with exposed headers, got %d calls and %v", called, rec.Header())
+	}
+	rec = serve(http.MethodPost, "https://evil.example.com", nil)
+	if called != 2 || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("expected no CORS headers for another origin, got %v", rec.Header())
+	}
+	rec = serve(http.MethodGet, "", nil)
+	if called != 3 || rec.Header().Get("Vary") != "" {
+		t.Errorf("expected requests without an origin left alone, got %v", rec.Header())
+	}
+}
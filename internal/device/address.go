@@ -0,0 +1,49 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// url returns the URL of path on the UFO
+func (c *Client) url(path string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.baseURL + path
+}
+
+// SetAddress points the client at the UFO at address, a host name or IP
+// address with an optional port, from now on. Idle connections to the
+// previous UFO are closed, so a host name is resolved again, and the
+// previous UFO's failures and boot time are forgotten. An offline UFO is
+// probed with the next request.
+func (c *Client) SetAddress(address string) {
+	c.mu.Lock()
+	c.baseURL = "http://" + address
+	c.mu.Unlock()
+	c.httpClient.CloseIdleConnections()
+
+	c.breaker.mu.Lock()
+	c.breaker.failures = 0
+	c.breaker.lastProbe = time.Time{}
+	c.breaker.mu.Unlock()
+
+	c.boot.mu.Lock()
+	c.boot.bootedAt = time.Time{}
+	c.boot.mu.Unlock()
+}
+
+// CheckAddress asks the UFO at address for its status once, without
+// switching to it, so a new address can be verified before SetAddress
+func (c *Client) CheckAddress(ctx context.Context, address string) (*Status, error) {
+	body, err := c.get(ctx, "http://"+address+"/api")
+	if err != nil {
+		return nil, err
+	}
+	status, err := ParseStatus(body)
+	if err != nil || (status.Top == nil && status.Bottom == nil && status.Dim == nil && status.LogoOn == nil) {
+		return nil, fmt.Errorf("%s does not answer like a UFO", address)
+	}
+	return status, nil
+}
@@ -0,0 +1,49 @@
+package device
+
+import "sync"
+
+// buttonTracker counts presses of the UFO's button from the press counter
+// the firmware reports with its status
+type buttonTracker struct {
+	mu      sync.Mutex
+	count   *int // counter seen last, nil before the first status
+	onPress func(presses int)
+}
+
+// observe compares a reported press counter with the one seen before and
+// calls the press function with the presses made in between. The first
+// counter seen only sets the baseline, so presses from before the server
+// started are not acted on; a counter going back means the UFO restarted,
+// and every press it counted since is new.
+func (b *buttonTracker) observe(count *int) {
+	if count == nil {
+		return
+	}
+
+	b.mu.Lock()
+	previous := b.count
+	b.count = count
+	onPress := b.onPress
+	b.mu.Unlock()
+
+	if previous == nil || onPress == nil {
+		return
+	}
+	presses := *count - *previous
+	if presses < 0 {
+		presses = *count
+	}
+	if presses > 0 {
+		onPress(presses)
+	}
+}
+
+// OnButtonPress registers a function called when a poll finds the UFO's
+// button was pressed since the poll before, with the number of presses.
+// Presses are only noticed as often as the poller runs, and only on
+// firmware that reports a "button" counter in its status.
+func (p *Poller) OnButtonPress(fn func(presses int)) {
+	p.button.mu.Lock()
+	p.button.onPress = fn
+	p.button.mu.Unlock()
+}
@@ -3,9 +3,10 @@ package device
 import (
 	"context"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -13,6 +14,20 @@ import (
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+	stats      requestStats
+	breaker    breaker
+	limiter    limiter
+	queue      writeQueue
+	gate       writeGate
+	boot       bootTracker
+
+	mu         sync.Mutex // guards baseURL and the fields below
+	retry      RetryPolicy
+	correction *ColorCorrection // applied to queries before sending, nil for none
+	// ringBrightness reports the percentage each ring's colors are scaled
+	// to before sending, nil for none
+	ringBrightness func() (top, bottom int)
+	onCommand      func(ctx context.Context, command Command)
 }
 
 // NewClient creates a new UFO device client
@@ -27,56 +42,42 @@ func NewClient() *Client {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		retry: DefaultRetryPolicy(),
 	}
 }
 
-// SendRawQuery sends a raw query string to the UFO /api endpoint
-func (c *Client) SendRawQuery(ctx context.Context, query string) (string, error) {
-	// Ensure query doesn't start with ? or /
-	if query != "" && (query[0] == '?' || query[0] == '/') {
-		query = query[1:]
-	}
-
-	url := fmt.Sprintf("%s/api?%s", c.baseURL, query)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("UFO request failed: %w", err)
-	}
-	defer resp.Body.Close()
+// Address returns the UFO's host name or IP address, as configured
+func (c *Client) Address() string {
+	return strings.TrimPrefix(c.url(""), "http://")
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("reading response: %w", err)
+// SendRawQuery sends a raw query string to the UFO /api endpoint. Transient
+// failures are retried according to the client's RetryPolicy. With a rate
+// limit set, writes wait their turn in the client's write queue. Colors are
+// corrected first unless ctx asks for passthrough.
+func (c *Client) SendRawQuery(ctx context.Context, query string) (string, error) {
+	query = c.correct(ctx, query)
+	if query != "" && c.inTransaction(ctx) {
+		// Queued writes wait for the transaction, so its own are paced instead
+		if err := c.queue.pace(ctx); err != nil {
+			return "", err
+		}
+		return c.sendWithRetry(ctx, query)
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("UFO returned status %d: %s", resp.StatusCode, string(body))
+	if query != "" && c.queue.enabled() {
+		return c.queue.submit(ctx, query, c.sendWithRetry)
 	}
-
-	return string(body), nil
+	return c.sendWithRetry(ctx, query)
 }
 
-// GetStatus retrieves the current UFO status
-func (c *Client) GetStatus(ctx context.Context) (map[string]interface{}, error) {
-	// The UFO typically returns JSON status on /api with no parameters
-	resp, err := c.SendRawQuery(ctx, "")
-	if err != nil {
-		return nil, err
-	}
-
-	// For now, return a simple map - in a real implementation we'd parse JSON
-	status := map[string]interface{}{
-		"response":  resp,
-		"timestamp": time.Now().Unix(),
+// sendRawQuery performs a single request to the UFO
+func (c *Client) sendRawQuery(ctx context.Context, query string) (string, error) {
+	// Ensure query doesn't start with ? or /
+	if query != "" && (query[0] == '?' || query[0] == '/') {
+		query = query[1:]
 	}
 
-	return status, nil
+	return c.fetch(ctx, c.url("/api?"+query))
 }
 
 // SetRingPattern sends a ring pattern command to the UFO
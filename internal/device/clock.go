@@ -0,0 +1,126 @@
+package device
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecentBootWindow is how soon after booting the UFO is reported as having
+// restarted recently
+const RecentBootWindow = 10 * time.Minute
+
+// rebootTolerance absorbs whole-second uptimes and request latency when
+// comparing boot times, so only a real restart counts as one
+const rebootTolerance = 30 * time.Second
+
+// clockSetAfter separates a clock that was set, by NTP or otherwise, from
+// one still counting from 1970 since the UFO booted
+var clockSetAfter = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Reboot describes a restart of the UFO, noticed because its uptime went back
+type Reboot struct {
+	BootedAt     time.Time     // when the UFO booted, estimated from its uptime
+	PreviousBoot time.Time     // when it had booted before
+	Uptime       time.Duration // uptime it reported
+}
+
+// bootTracker remembers when the UFO booted to notice restarts
+type bootTracker struct {
+	mu       sync.Mutex
+	bootedAt time.Time
+	onReboot func(Reboot)
+}
+
+// observe records a boot time worked out from a reported uptime and calls
+// the reboot function when it is later than the one seen before
+func (b *bootTracker) observe(bootedAt time.Time, uptime time.Duration) {
+	b.mu.Lock()
+	previous := b.bootedAt
+	rebooted := !previous.IsZero() && bootedAt.Sub(previous) > rebootTolerance
+	if previous.IsZero() || rebooted {
+		b.bootedAt = bootedAt
+	}
+	onReboot := b.onReboot
+	b.mu.Unlock()
+
+	if rebooted && onReboot != nil {
+		onReboot(Reboot{BootedAt: bootedAt, PreviousBoot: previous, Uptime: uptime})
+	}
+}
+
+// OnReboot registers a function called when the UFO's uptime shows it
+// restarted since it was last asked. Uptime is read by FetchInfo, which the
+// status poller calls as well.
+func (c *Client) OnReboot(fn func(Reboot)) {
+	c.boot.mu.Lock()
+	c.boot.onReboot = fn
+	c.boot.mu.Unlock()
+}
+
+// observeClock fills in the fields of info that depend on when it was read:
+// the boot time and the clock's drift from the server's. at is when the UFO
+// answered.
+func (c *Client) observeClock(info *Info, at time.Time) {
+	if info.UptimeSeconds != nil {
+		bootedAt := at.Add(-info.Uptime()).Truncate(time.Second)
+		info.BootedAt = &bootedAt
+		c.boot.observe(bootedAt, info.Uptime())
+	}
+	if info.Clock != nil && info.Clock.After(clockSetAfter) {
+		drift := info.Clock.Sub(at).Milliseconds()
+		info.ClockDriftMs = &drift
+	}
+}
+
+// parseClock reads the UFO's wall clock, reported as Unix seconds or
+// milliseconds or as an RFC 3339 time
+func parseClock(value interface{}) (time.Time, bool) {
+	if text, ok := value.(string); ok {
+		if t, err := time.Parse(time.RFC3339, strings.TrimSpace(text)); err == nil {
+			return t, true
+		}
+	}
+	n, ok := infoNumber(value)
+	if !ok || n < 0 {
+		return time.Time{}, false
+	}
+	if n > 1e11 {
+		return time.UnixMilli(n).UTC(), true
+	}
+	return time.Unix(n, 0).UTC(), true
+}
+
+// parseFlag reads a yes/no field reported as a JSON boolean, a number or a
+// string
+func parseFlag(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case float64:
+		return v != 0, true
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "yes", "on", "1", "synced":
+			return true, true
+		case "false", "no", "off", "0":
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// RecentlyRebooted reports whether the UFO booted less than RecentBootWindow
+// before it answered; false when it does not report its uptime
+func (i *Info) RecentlyRebooted() bool {
+	return i.UptimeSeconds != nil && i.Uptime() < RecentBootWindow
+}
+
+// ClockDrift returns how far the UFO's clock is ahead of the server's, and
+// whether it could be worked out
+func (i *Info) ClockDrift() (time.Duration, bool) {
+	if i.ClockDriftMs == nil {
+		return 0, false
+	}
+	return time.Duration(*i.ClockDriftMs) * time.Millisecond, true
+}
@@ -0,0 +1,122 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseInfo_Clock(t *testing.T) {
+	tests := []struct {
+		body   string
+		clock  time.Time
+		synced *bool
+	}{
+		{`{"time":1760000000,"ntp":true}`, time.Unix(1760000000, 0), boolPtr(true)},
+		{`{"epoch":1760000000123,"ntpSynced":"false"}`, time.UnixMilli(1760000000123), boolPtr(false)},
+		{`{"localTime":"2025-10-09T08:53:20Z"}`, time.Unix(1760000000, 0), nil},
+		{`{"time":3600}`, time.Unix(3600, 0), boolPtr(false)}, // never set
+	}
+	for _, tt := range tests {
+		info, err := ParseInfo(tt.body)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.body, err)
+		}
+		if info.Clock == nil || !info.Clock.Equal(tt.clock) {
+			t.Errorf("%s: expected clock %v, got %v", tt.body, tt.clock, info.Clock)
+		}
+		if (tt.synced == nil) != (info.ClockSynced == nil) || (tt.synced != nil && *tt.synced != *info.ClockSynced) {
+			t.Errorf("%s: expected synced %v, got %v", tt.body, tt.synced, info.ClockSynced)
+		}
+		if len(info.Extra) != 0 {
+			t.Errorf("%s: clock fields left in extra: %v", tt.body, info.Extra)
+		}
+	}
+}
+
+func TestClient_FetchInfoClockDrift(t *testing.T) {
+	var clock atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"uptime":7200,"time":%d}`, clock.Load())
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+	client := NewClient()
+
+	clock.Store(time.Now().Add(-90 * time.Second).Unix())
+	info, err := client.FetchInfo(context.Background())
+	if err != nil {
+		t.Fatalf("FetchInfo failed: %v", err)
+	}
+	drift, ok := info.ClockDrift()
+	if !ok || drift > -88*time.Second || drift < -92*time.Second {
+		t.Errorf("expected the clock about 90s behind, got %v (%v)", drift, ok)
+	}
+	if info.BootedAt == nil || time.Since(*info.BootedAt) < 2*time.Hour-time.Minute {
+		t.Errorf("expected a boot time 2h ago, got %v", info.BootedAt)
+	}
+	if info.RecentlyRebooted() {
+		t.Error("expected no recent restart after 2h of uptime")
+	}
+
+	// A clock that was never set has no meaningful drift
+	clock.Store(7200)
+	info, err = client.FetchInfo(context.Background())
+	if err != nil {
+		t.Fatalf("FetchInfo failed: %v", err)
+	}
+	if _, ok := info.ClockDrift(); ok {
+		t.Error("expected no drift for an unset clock")
+	}
+}
+
+func TestClient_OnReboot(t *testing.T) {
+	var uptime atomic.Int64
+	uptime.Store(3600)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"uptime":%d}`, uptime.Load())
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+	client := NewClient()
+
+	var mu sync.Mutex
+	var reboots []Reboot
+	client.OnReboot(func(reboot Reboot) {
+		mu.Lock()
+		reboots = append(reboots, reboot)
+		mu.Unlock()
+	})
+
+	for _, seconds := range []int64{3600, 3601, 5, 20} {
+		uptime.Store(seconds)
+		info, err := client.FetchInfo(context.Background())
+		if err != nil {
+			t.Fatalf("FetchInfo failed: %v", err)
+		}
+		if seconds == 5 && !info.RecentlyRebooted() {
+			t.Error("expected a recent restart at 5s of uptime")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reboots) != 1 {
+		t.Fatalf("expected one restart, got %v", reboots)
+	}
+	if reboots[0].Uptime != 5*time.Second {
+		t.Errorf("expected the restart seen at 5s of uptime, got %v", reboots[0].Uptime)
+	}
+	if gap := reboots[0].BootedAt.Sub(reboots[0].PreviousBoot); gap < time.Hour-time.Minute {
+		t.Errorf("expected the boots about an hour apart, got %v", gap)
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
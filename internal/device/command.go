@@ -0,0 +1,37 @@
+package device
+
+import (
+	"context"
+	"time"
+)
+
+// Command is a query sent to the UFO, as reported to the OnCommand callback
+type Command struct {
+	Query    string        // query as sent, after color correction
+	Reply    string        // the UFO's reply, empty when the request failed
+	Err      error         // why the request failed, nil on success
+	Attempts int           // requests made, 0 when skipped while offline
+	Latency  time.Duration // time spent on all attempts, retry delays included
+}
+
+// OnCommand registers fn to be called after every query sent to the UFO,
+// with the context of the caller that sent it. Status reads are not
+// reported.
+func (c *Client) OnCommand(fn func(ctx context.Context, command Command)) {
+	c.mu.Lock()
+	c.onCommand = fn
+	c.mu.Unlock()
+}
+
+// reportCommand passes a sent query to the OnCommand callback, if any
+func (c *Client) reportCommand(ctx context.Context, command Command) {
+	if command.Query == "" {
+		return
+	}
+	c.mu.Lock()
+	fn := c.onCommand
+	c.mu.Unlock()
+	if fn != nil {
+		fn(ctx, command)
+	}
+}
@@ -0,0 +1,46 @@
+package device
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOnCommand(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := NewClient()
+	client.SetRetryPolicy(fastRetry)
+	var commands []Command
+	type key struct{}
+	client.OnCommand(func(ctx context.Context, command Command) {
+		if ctx.Value(key{}) != "caller" {
+			t.Error("expected the caller's context")
+		}
+		commands = append(commands, command)
+	})
+
+	ctx := context.WithValue(context.Background(), key{}, "caller")
+	if _, err := client.SendRawQuery(ctx, "dim=100"); err != nil {
+		t.Fatal(err)
+	}
+	client.FetchStatus(ctx) // status reads are not reported
+
+	if len(commands) != 1 {
+		t.Fatalf("expected only the write to be reported, got %+v", commands)
+	}
+	if c := commands[0]; c.Query != "dim=100" || c.Reply != "OK" || c.Err != nil || c.Attempts != 2 || c.Latency <= 0 {
+		t.Errorf("unexpected command %+v", c)
+	}
+}
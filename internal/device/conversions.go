@@ -7,6 +7,12 @@ import (
 	"strings"
 )
 
+// RingLEDs is the number of LEDs on each ring
+const RingLEDs = 15
+
+// MaxWhirlMs is the slowest whirl speed the UFO accepts
+const MaxWhirlMs = 510
+
 // MorphConfig represents morph settings in milliseconds
 type MorphConfig struct {
 	BrightnessMs int `json:"brightnessMs"`
@@ -74,4 +80,21 @@ func ConvertDurationToMs(seconds int) int {
 // ConvertDurationFromMs converts milliseconds to seconds
 func ConvertDurationFromMs(milliseconds int) int {
 	return milliseconds / 1000
-}
\ No newline at end of file
+}
+
+// ConvertRotationPeriodToWhirl converts the time for a pattern to travel
+// once around the ring into a whirl speed. The UFO moves the pattern one LED
+// every whirl milliseconds, so a full turn takes RingLEDs steps.
+func ConvertRotationPeriodToWhirl(periodMs int) (int, error) {
+	whirl := int(math.Round(float64(periodMs) / RingLEDs))
+	if whirl < 1 || whirl > MaxWhirlMs {
+		return 0, fmt.Errorf("rotation period must be between %dms and %dms", RingLEDs, RingLEDs*MaxWhirlMs)
+	}
+	return whirl, nil
+}
+
+// ConvertWhirlToRotationPeriod converts a whirl speed into the time in
+// milliseconds for the pattern to travel once around the ring
+func ConvertWhirlToRotationPeriod(whirlMs int) int {
+	return whirlMs * RingLEDs
+}
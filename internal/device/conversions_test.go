@@ -167,4 +167,20 @@ func abs(x int) int {
 		return -x
 	}
 	return x
-}
\ No newline at end of file
+}
+
+func TestConvertRotationPeriodToWhirl(t *testing.T) {
+	whirl, err := ConvertRotationPeriodToWhirl(3000)
+	if err != nil || whirl != 200 {
+		t.Errorf("expected whirl 200 for a 3s turn, got %d (%v)", whirl, err)
+	}
+	if period := ConvertWhirlToRotationPeriod(whirl); period != 3000 {
+		t.Errorf("expected 3000ms period, got %d", period)
+	}
+
+	for _, periodMs := range []int{0, 5, 8000} {
+		if _, err := ConvertRotationPeriodToWhirl(periodMs); err == nil {
+			t.Errorf("expected error for period %dms", periodMs)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ColorCorrection adjusts colors and brightness before they are sent to the
+// UFO, so that colors chosen on a screen look right on its WS2812 LEDs,
+// whose brightness is not linear. Zero fields leave their part unchanged.
+type ColorCorrection struct {
+	Gamma           float64 `json:"gamma,omitempty"`           // exponent applied to each color channel, e.g. 2.2
+	Red             float64 `json:"red,omitempty"`             // white balance scale for red, 0-1
+	Green           float64 `json:"green,omitempty"`           // white balance scale for green, 0-1
+	Blue            float64 `json:"blue,omitempty"`            // white balance scale for blue, 0-1
+	BrightnessGamma float64 `json:"brightnessGamma,omitempty"` // exponent applied to dim levels
+}
+
+// maxGamma bounds the correction exponents
+const maxGamma = 5
+
+// Validate checks that the exponents are between 0.1 and 5 and the white
+// balance scales between 0 and 1
+func (c ColorCorrection) Validate() error {
+	for name, gamma := range map[string]float64{"gamma": c.Gamma, "brightnessGamma": c.BrightnessGamma} {
+		if gamma != 0 && (gamma < 0.1 || gamma > maxGamma) {
+			return fmt.Errorf("%s must be between 0.1 and %d, got %g", name, maxGamma, gamma)
+		}
+	}
+	for name, scale := range map[string]float64{"red": c.Red, "green": c.Green, "blue": c.Blue} {
+		if scale < 0 || scale > 1 {
+			return fmt.Errorf("%s must be between 0 and 1, got %g", name, scale)
+		}
+	}
+	return nil
+}
+
+// IsZero reports whether the correction changes nothing
+func (c ColorCorrection) IsZero() bool {
+	return c == ColorCorrection{}
+}
+
+// Color returns the corrected form of a 6-digit hex color, in lowercase.
+// Anything else is returned unchanged.
+func (c ColorCorrection) Color(hex string) string {
+	if !isHexColor(hex) {
+		return hex
+	}
+	rgb := parseRGB(hex)
+	for i, scale := range []float64{c.Red, c.Green, c.Blue} {
+		rgb[i] = correctLevel(rgb[i], scale, c.Gamma)
+	}
+	return fmt.Sprintf("%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
+
+// Dim returns the corrected brightness level
+func (c ColorCorrection) Dim(level int) int {
+	return correctLevel(level, 0, c.BrightnessGamma)
+}
+
+// correctLevel scales a 0-255 level and applies gamma to it. A scale or
+// gamma of 0 is left out.
+func correctLevel(level int, scale, gamma float64) int {
+	x := float64(level) / 255
+	if scale > 0 {
+		x *= scale
+	}
+	if gamma > 0 {
+		x = math.Pow(x, gamma)
+	}
+	return int(math.Round(x * 255))
+}
+
+// Query returns query with every color and brightness level it sets
+// corrected: ring backgrounds and segments, logo colors and dim. Other
+// parameters are left as they are.
+func (c ColorCorrection) Query(query string) string {
+	parts := strings.Split(query, "&")
+	for i, part := range parts {
+		name, value, found := strings.Cut(part, "=")
+		if !found {
+			continue
+		}
+		switch {
+		case name == "dim":
+			if level, err := strconv.Atoi(value); err == nil && level >= 0 && level <= 255 {
+				value = strconv.Itoa(c.Dim(level))
+			}
+		case name == "top_bg" || name == "bottom_bg":
+			value = c.Color(value)
+		case name == "top" || name == "bottom":
+			fields := strings.Split(value, "|")
+			for j := 2; j < len(fields); j += 3 {
+				fields[j] = c.Color(fields[j])
+			}
+			value = strings.Join(fields, "|")
+		case name == "logo":
+			fields := strings.Split(value, "|")
+			for j := range fields {
+				fields[j] = c.Color(fields[j])
+			}
+			value = strings.Join(fields, "|")
+		default:
+			continue
+		}
+		parts[i] = name + "=" + value
+	}
+	return strings.Join(parts, "&")
+}
+
+// passthroughKey marks contexts whose queries skip color correction
+type passthroughKey struct{}
+
+// WithPassthrough returns a context whose queries are sent with the exact
+// colors given, skipping the client's color correction
+func WithPassthrough(ctx context.Context) context.Context {
+	return context.WithValue(ctx, passthroughKey{}, true)
+}
+
+// Passthrough reports whether queries sent with ctx skip color correction
+func Passthrough(ctx context.Context) bool {
+	passthrough, _ := ctx.Value(passthroughKey{}).(bool)
+	return passthrough
+}
+
+// SetColorCorrection sets the correction applied to every query sent to the
+// UFO; nil or a zero correction sends colors as given
+func (c *Client) SetColorCorrection(correction *ColorCorrection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if correction == nil || correction.IsZero() {
+		c.correction = nil
+		return
+	}
+	copied := *correction
+	c.correction = &copied
+}
+
+// ColorCorrection returns the correction applied to queries, or nil
+func (c *Client) ColorCorrection() *ColorCorrection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.correction == nil {
+		return nil
+	}
+	copied := *c.correction
+	return &copied
+}
+
+// correct returns query as it is sent to the UFO with ctx: ring colors
+// scaled to the rings' brightness, then color corrected unless ctx asks for
+// passthrough
+func (c *Client) correct(ctx context.Context, query string) string {
+	if query == "" {
+		return query
+	}
+	if brightness := c.RingBrightness(); brightness != nil {
+		top, bottom := brightness()
+		query = ScaleRings(query, top, bottom)
+	}
+	if Passthrough(ctx) {
+		return query
+	}
+	correction := c.ColorCorrection()
+	if correction == nil {
+		return query
+	}
+	return correction.Query(query)
+}
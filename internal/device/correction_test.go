@@ -0,0 +1,57 @@
+package device
+
+import (
+	"context"
+	"testing"
+)
+
+func TestColorCorrection_Query(t *testing.T) {
+	correction := ColorCorrection{Gamma: 2, Blue: 0.5, BrightnessGamma: 2}
+	got := correction.Query("top_init=1&top_bg=808080&top=0|5|FF0000|5|3|0000ff&top_whirl=200&logo=on|ffffff&dim=128&effect=rainbow")
+	want := "top_init=1&top_bg=404010&top=0|5|ff0000|5|3|000040&top_whirl=200&logo=on|ffff40&dim=64&effect=rainbow"
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	// Zero fields leave their part alone
+	if got := (ColorCorrection{}).Query("top_bg=123456&dim=10"); got != "top_bg=123456&dim=10" {
+		t.Errorf("expected an empty correction to change nothing, got %s", got)
+	}
+}
+
+func TestColorCorrection_Validate(t *testing.T) {
+	valid := ColorCorrection{Gamma: 2.2, Red: 1, Green: 0.9, Blue: 0.8, BrightnessGamma: 1.5}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, invalid := range []ColorCorrection{{Gamma: 6}, {Gamma: -1}, {Red: 1.5}, {Blue: -0.1}, {BrightnessGamma: 0.01}} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("expected %+v to be rejected", invalid)
+		}
+	}
+}
+
+func TestClient_ColorCorrection(t *testing.T) {
+	simulator := NewSimulator()
+	client := NewSimulatedClient(simulator)
+	client.SetColorCorrection(&ColorCorrection{Gamma: 2})
+	ctx := context.Background()
+
+	client.SendRawQuery(ctx, "top_init=1&top_bg=808080")
+	if got := simulator.rings["top"].LEDs[0]; got != "404040" {
+		t.Errorf("expected the corrected color, got %s", got)
+	}
+	if v, err := client.Verify(ctx, "top_init=1&top_bg=808080", 0); err != nil || !v.Verified {
+		t.Errorf("expected the corrected query to verify, got %+v (%v)", v, err)
+	}
+
+	client.SendRawQuery(WithPassthrough(ctx), "top_init=1&top_bg=808080")
+	if got := simulator.rings["top"].LEDs[0]; got != "808080" {
+		t.Errorf("expected passthrough to send the exact color, got %s", got)
+	}
+
+	client.SetColorCorrection(&ColorCorrection{})
+	if client.ColorCorrection() != nil {
+		t.Error("expected a zero correction to clear it")
+	}
+}
@@ -0,0 +1,134 @@
+package device
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Firmware update states reported in FirmwareStatus.State
+const (
+	FirmwareIdle        = "idle"
+	FirmwareDownloading = "downloading"
+	FirmwareFlashing    = "flashing"
+	FirmwareRebooting   = "rebooting"
+	FirmwareDone        = "done"
+	FirmwareFailed      = "failed"
+)
+
+// FirmwareStatus is what the UFO reports on its firmware and over-the-air
+// updates. Firmware builds without update support serve no /firmware
+// endpoint; only their version is known then.
+type FirmwareStatus struct {
+	Supported       bool   `json:"supported"`           // the firmware can check for and install updates
+	Current         string `json:"current,omitempty"`   // installed version
+	Available       string `json:"available,omitempty"` // newest version offered by the update server
+	UpdateAvailable bool   `json:"updateAvailable"`
+	State           string `json:"state,omitempty"`    // one of the Firmware* states
+	Progress        *int   `json:"progress,omitempty"` // percent of the running update done
+	Message         string `json:"message,omitempty"`  // the firmware's explanation, e.g. of a failure
+	Raw             string `json:"-"`                  // unparsed firmware response
+}
+
+// firmwareKeys lists the keys each FirmwareStatus field is read from, in
+// order of preference
+var firmwareKeys = map[string][]string{
+	"current":   {"version", "current", "firmware", "fwVersion"},
+	"available": {"available", "latest", "newVersion", "updateVersion"},
+	"update":    {"updateAvailable", "update"},
+	"state":     {"state", "status", "otaState"},
+	"progress":  {"progress", "percent", "otaProgress"},
+	"message":   {"message", "error"},
+}
+
+// ParseFirmwareStatus parses the JSON document served on /firmware
+func ParseFirmwareStatus(body string) (*FirmwareStatus, error) {
+	status := &FirmwareStatus{Supported: true, Raw: body}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return status, fmt.Errorf("parsing UFO firmware status: %w", err)
+	}
+	text := func(field string) string {
+		for _, key := range firmwareKeys[field] {
+			if value, ok := doc[key].(string); ok && value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	status.Current = text("current")
+	status.Available = text("available")
+	status.State = strings.ToLower(text("state"))
+	status.Message = text("message")
+	for _, key := range firmwareKeys["progress"] {
+		if n, ok := infoNumber(doc[key]); ok {
+			percent := int(n)
+			status.Progress = &percent
+			break
+		}
+	}
+	status.UpdateAvailable = status.Available != "" && status.Available != status.Current
+	for _, key := range firmwareKeys["update"] {
+		if value, ok := doc[key].(bool); ok {
+			status.UpdateAvailable = value
+			break
+		}
+	}
+	if status.State == "" {
+		status.State = FirmwareIdle
+	}
+	return status, nil
+}
+
+// Updating reports whether an update is under way
+func (s *FirmwareStatus) Updating() bool {
+	switch s.State {
+	case FirmwareDownloading, FirmwareFlashing, FirmwareRebooting:
+		return true
+	}
+	return false
+}
+
+// FetchFirmware asks the UFO for its firmware version and update state.
+// Firmware without a /firmware endpoint is reported as not supporting
+// updates, with the version from its info document.
+func (c *Client) FetchFirmware(ctx context.Context) (*FirmwareStatus, error) {
+	body, err := c.get(ctx, c.url("/firmware"))
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound {
+		info, err := c.FetchInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &FirmwareStatus{Current: info.Firmware, State: FirmwareIdle}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseFirmwareStatus(body)
+}
+
+// StartFirmwareUpdate asks the UFO to download and install a firmware
+// update, from imageURL when given and otherwise from its update server.
+// The request is not retried: the UFO may already be installing it. It
+// returns as soon as the UFO accepts; FetchFirmware reports the progress.
+func (c *Client) StartFirmwareUpdate(ctx context.Context, imageURL string) error {
+	target := c.url("/firmware?update=1")
+	if imageURL != "" {
+		target += "&url=" + url.QueryEscape(imageURL)
+	}
+	reply, err := c.get(ctx, target)
+	if err != nil {
+		return err
+	}
+	if _, err := CheckReply(reply); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package device
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseFirmwareStatus(t *testing.T) {
+	status, err := ParseFirmwareStatus(`{"fwVersion":"1.4","latest":"1.5","otaState":"Flashing","percent":"60"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Current != "1.4" || status.Available != "1.5" || !status.UpdateAvailable || !status.Updating() {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	if status.Progress == nil || *status.Progress != 60 {
+		t.Errorf("expected 60%% progress, got %v", status.Progress)
+	}
+
+	// An explicit flag wins over comparing versions
+	status, _ = ParseFirmwareStatus(`{"version":"1.5","available":"1.6-beta","updateAvailable":false}`)
+	if status.UpdateAvailable || status.State != FirmwareIdle {
+		t.Errorf("expected no update and an idle state, got %+v", status)
+	}
+
+	if _, err := ParseFirmwareStatus("UFO"); err == nil {
+		t.Error("expected a non-JSON reply to fail")
+	}
+}
+
+func TestClient_FetchFirmware_WithoutUpdateSupport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/info" {
+			w.Write([]byte(`{"version":"1.2"}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	status, err := NewClient().FetchFirmware(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Supported || status.Current != "1.2" {
+		t.Errorf("expected version 1.2 without update support, got %+v", status)
+	}
+}
+
+func TestClient_StartFirmwareUpdate(t *testing.T) {
+	simulator := NewSimulator()
+	client := NewSimulatedClient(simulator)
+	if err := client.StartFirmwareUpdate(context.Background(), ""); err == nil {
+		t.Error("expected an update to be refused when none is offered")
+	}
+
+	simulator.OfferFirmware("2.0")
+	if err := client.StartFirmwareUpdate(context.Background(), ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status, err := client.FetchFirmware(context.Background())
+	if err != nil || status.State != FirmwareDownloading {
+		t.Errorf("expected the update to be downloading, got %+v (%v)", status, err)
+	}
+}
@@ -0,0 +1,148 @@
+package device
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Frame is the color of every LED of both rings at one moment
+type Frame struct {
+	Top    [RingLEDs]string `json:"top"`
+	Bottom [RingLEDs]string `json:"bottom"`
+}
+
+// ring returns a pointer to the named ring's LEDs
+func (f *Frame) ring(name string) *[RingLEDs]string {
+	if name == "top" {
+		return &f.Top
+	}
+	return &f.Bottom
+}
+
+// Apply returns the frame the UFO shows after receiving query while showing
+// f, as the firmware draws it: init, background, then segments. Whirl and
+// morph animate the LEDs over time and do not change the frame.
+func (f Frame) Apply(query string) (Frame, error) {
+	rings, _, _, err := parseAPIQuery(query)
+	if err != nil {
+		return f, err
+	}
+	for name, q := range rings {
+		ring := simulatedRing{LEDs: *f.ring(name)}
+		for i, led := range ring.LEDs {
+			if !isHexColor(led) {
+				ring.LEDs[i] = "000000"
+			}
+		}
+		if err := ring.apply(q); err != nil {
+			return f, fmt.Errorf("%s: %w", name, err)
+		}
+		*f.ring(name) = ring.LEDs
+	}
+	return f, nil
+}
+
+// Blend returns the frame a fraction t of the way from f to to, mixing each
+// LED's red, green and blue linearly. t is clamped to 0-1.
+func (f Frame) Blend(to Frame, t float64) Frame {
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	var blended Frame
+	for _, name := range []string{"top", "bottom"} {
+		from, target, out := f.ring(name), to.ring(name), blended.ring(name)
+		for i := range out {
+			out[i] = MixColor(from[i], target[i], t)
+		}
+	}
+	return blended
+}
+
+// Query returns the query that draws the rings of f that differ from
+// current, or "" when nothing changes
+func (f Frame) Query(current Frame) (string, error) {
+	var parts []string
+	for _, name := range []string{"top", "bottom"} {
+		if *f.ring(name) == *current.ring(name) {
+			continue
+		}
+		background, segments, err := CompressRing(f.ring(name)[:])
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", name, err)
+		}
+		part := fmt.Sprintf("%s_init=1&%s_bg=%s", name, name, background)
+		if len(segments) > 0 {
+			part += fmt.Sprintf("&%s=%s", name, strings.Join(segments, "|"))
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "&"), nil
+}
+
+// Modes for Frame.Layer
+const (
+	LayerOverwrite = "overwrite" // lit LEDs of the upper frame replace those below
+	LayerAdd       = "add"       // red, green and blue are added, up to full
+	LayerMax       = "max"       // the brighter red, green and blue of either
+)
+
+// LayerModes lists the modes Frame.Layer accepts
+var LayerModes = []string{LayerOverwrite, LayerAdd, LayerMax}
+
+// Layer returns the frame showing over on top of f. Off LEDs of over are
+// transparent in every mode.
+func (f Frame) Layer(over Frame, mode string) (Frame, error) {
+	var layered Frame
+	for _, name := range []string{"top", "bottom"} {
+		under, upper, out := f.ring(name), over.ring(name), layered.ring(name)
+		for i := range out {
+			a, b := parseRGB(under[i]), parseRGB(upper[i])
+			var mixed [3]int
+			switch mode {
+			case LayerOverwrite:
+				mixed = a
+				if b != [3]int{} {
+					mixed = b
+				}
+			case LayerAdd:
+				for c := range mixed {
+					mixed[c] = min(a[c]+b[c], 255)
+				}
+			case LayerMax:
+				for c := range mixed {
+					mixed[c] = max(a[c], b[c])
+				}
+			default:
+				return f, fmt.Errorf("unknown layer mode %q: use %s", mode, strings.Join(LayerModes, ", "))
+			}
+			out[i] = fmt.Sprintf("%02x%02x%02x", mixed[0], mixed[1], mixed[2])
+		}
+	}
+	return layered, nil
+}
+
+// MixColor mixes two hex colors, treating unparseable colors as off. Like
+// the simulator it writes lowercase hex.
+func MixColor(from, to string, t float64) string {
+	a, b := parseRGB(from), parseRGB(to)
+	var mixed [3]int
+	for i := range mixed {
+		mixed[i] = int(math.Round(float64(a[i]) + float64(b[i]-a[i])*t))
+	}
+	return fmt.Sprintf("%02x%02x%02x", mixed[0], mixed[1], mixed[2])
+}
+
+// parseRGB splits a hex color into red, green and blue
+func parseRGB(color string) [3]int {
+	var rgb [3]int
+	if !isHexColor(color) {
+		return rgb
+	}
+	n, _ := strconv.ParseUint(color, 16, 32)
+	rgb[0], rgb[1], rgb[2] = int(n>>16&0xFF), int(n>>8&0xFF), int(n&0xFF)
+	return rgb
+}
@@ -0,0 +1,127 @@
+package device
+
+import (
+	"strings"
+	"testing"
+)
+
+// frame builds a frame from compact ring descriptions, see ring, in the
+// lowercase hex the simulator writes
+func frame(top, bottom string) Frame {
+	var f Frame
+	for i, led := range ring(top) {
+		f.Top[i] = strings.ToLower(led)
+	}
+	for i, led := range ring(bottom) {
+		f.Bottom[i] = strings.ToLower(led)
+	}
+	return f
+}
+
+func TestFrame_Apply(t *testing.T) {
+	start := frame("rrrrrrrrrrrrrrr", "...............")
+
+	got, err := start.Apply("top_init=1&top_bg=0000FF&top=13|4|00FF00&top_whirl=200&dim=50")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := frame("ggbbbbbbbbbbbgg", "..............."); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Rings the query does not mention keep their LEDs
+	got, err = start.Apply("bottom=0|1|0000FF")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := frame("rrrrrrrrrrrrrrr", "b.............."); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := start.Apply("top=0|99"); err == nil {
+		t.Error("expected an error for a malformed segment")
+	}
+}
+
+func TestFrame_Blend(t *testing.T) {
+	from := frame("...............", "bbbbbbbbbbbbbbb")
+	to := frame("rrrrrrrrrrrrrrr", "bbbbbbbbbbbbbbb")
+
+	if got := from.Blend(to, 0); got != from {
+		t.Errorf("Blend(0) = %v, want the start frame", got)
+	}
+	if got := from.Blend(to, 1); got != to {
+		t.Errorf("Blend(1) = %v, want the target frame", got)
+	}
+	half := from.Blend(to, 0.5)
+	if half.Top[0] != "800000" || half.Bottom[0] != "0000ff" {
+		t.Errorf("Blend(0.5) = %s/%s, want 800000/0000ff", half.Top[0], half.Bottom[0])
+	}
+	if got := from.Blend(to, 3); got != to {
+		t.Errorf("Blend(3) = %v, want it clamped to the target frame", got)
+	}
+}
+
+func TestFrame_Query(t *testing.T) {
+	current := frame("...............", "bbbbbbbbbbbbbbb")
+
+	if query, err := current.Query(current); err != nil || query != "" {
+		t.Errorf("Query of an unchanged frame = %q, %v; want empty", query, err)
+	}
+
+	next := frame("rrr............", "bbbbbbbbbbbbbbb")
+	query, err := next.Query(current)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "top_init=1&top_bg=000000&top=0|3|FF0000"; query != want {
+		t.Errorf("Query = %q, want %q", query, want)
+	}
+	if strings.Contains(query, "bottom") {
+		t.Error("unchanged bottom ring should not be sent")
+	}
+
+	// The query must draw the frame
+	drawn, err := current.Apply(query)
+	if err != nil {
+		t.Fatalf("frame query rejected: %v", err)
+	}
+	if drawn != next {
+		t.Errorf("query draws %v, want %v", drawn, next)
+	}
+}
+
+func TestFrame_Layer(t *testing.T) {
+	under := frame("rrrrr..........", "bbbbbbbbbbbbbbb")
+	over := frame("..ggggg........", "r..............")
+
+	got, err := under.Layer(over, LayerOverwrite)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := frame("rrggggg........", "rbbbbbbbbbbbbbb"); got != want {
+		t.Errorf("overwrite = %v, want %v", got, want)
+	}
+
+	got, err = under.Layer(over, LayerAdd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Top[2] != "ffff00" || got.Top[0] != "ff0000" || got.Bottom[0] != "ff00ff" {
+		t.Errorf("add = %v, want the colors summed", got)
+	}
+
+	under.Top[0] = "804020"
+	over.Top[0] = "2060a0"
+	got, err = under.Layer(over, LayerMax)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Top[0] != "8060a0" || got.Top[2] != "ffff00" {
+		t.Errorf("max = %s/%s, want 8060a0/ffff00", got.Top[0], got.Top[2])
+	}
+
+	if _, err := under.Layer(over, "multiply"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
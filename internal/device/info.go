@@ -0,0 +1,205 @@
+package device
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Info describes the UFO itself as reported by its firmware. Firmware builds
+// name their fields differently, so each field is read from the first of
+// several known keys; fields the firmware does not report are left empty.
+type Info struct {
+	Firmware      string                 `json:"firmware,omitempty"`      // firmware version
+	IP            string                 `json:"ip,omitempty"`            // address on the WiFi network
+	SSID          string                 `json:"ssid,omitempty"`          // WiFi network the UFO joined
+	Hostname      string                 `json:"hostname,omitempty"`      // network name or UFO ID
+	MAC           string                 `json:"mac,omitempty"`           // WiFi MAC address
+	UptimeSeconds *int64                 `json:"uptimeSeconds,omitempty"` // time since the UFO booted
+	FreeHeap      *int64                 `json:"freeHeap,omitempty"`      // free memory in bytes
+	BootedAt      *time.Time             `json:"bootedAt,omitempty"`      // when the UFO booted, from its uptime
+	Clock         *time.Time             `json:"clock,omitempty"`         // the UFO's own wall clock
+	ClockSynced   *bool                  `json:"clockSynced,omitempty"`   // whether the clock was set over NTP
+	ClockDriftMs  *int64                 `json:"clockDriftMs,omitempty"`  // how far the clock is ahead of the server's
+	Extra         map[string]interface{} `json:"extra,omitempty"`         // reported fields not recognized above
+	Raw           string                 `json:"-"`                       // unparsed firmware response
+}
+
+// infoKeys lists the keys each Info field is read from, in order of preference
+var infoKeys = map[string][]string{
+	"firmware": {"version", "firmware", "fwVersion", "firmwareVersion", "git"},
+	"ip":       {"ip", "ipAddress", "ipaddress", "localIP", "localIp"},
+	"ssid":     {"ssid", "wifiSsid", "wifi"},
+	"hostname": {"hostname", "ufoid", "ufoId", "name"},
+	"mac":      {"mac", "macAddress", "macaddress"},
+	"uptime":   {"uptime", "uptimeSeconds", "uptime_s"},
+	"uptimeMs": {"uptimeMs", "millis"},
+	"heap":     {"freeHeap", "heap", "free_heap"},
+	"clock":    {"time", "epoch", "unixTime", "timestamp", "localTime"},
+	"ntp":      {"ntpSynced", "ntp", "timeSynced", "ntpSync"},
+}
+
+// ParseInfo parses the JSON info document returned by the UFO firmware
+func ParseInfo(body string) (*Info, error) {
+	info := &Info{Raw: body}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return info, fmt.Errorf("parsing UFO info: %w", err)
+	}
+
+	used := map[string]bool{}
+	text := func(field string) string {
+		for _, key := range infoKeys[field] {
+			if value, ok := doc[key].(string); ok && value != "" {
+				used[key] = true
+				return value
+			}
+		}
+		return ""
+	}
+	number := func(field string) (int64, bool) {
+		for _, key := range infoKeys[field] {
+			if n, ok := infoNumber(doc[key]); ok {
+				used[key] = true
+				return n, true
+			}
+		}
+		return 0, false
+	}
+
+	info.Firmware = text("firmware")
+	info.IP = text("ip")
+	info.SSID = text("ssid")
+	info.Hostname = text("hostname")
+	info.MAC = text("mac")
+	if seconds, ok := number("uptime"); ok {
+		info.UptimeSeconds = &seconds
+	} else if ms, ok := number("uptimeMs"); ok {
+		seconds := ms / 1000
+		info.UptimeSeconds = &seconds
+	}
+	if heap, ok := number("heap"); ok {
+		info.FreeHeap = &heap
+	}
+	for _, key := range infoKeys["clock"] {
+		if clock, ok := parseClock(doc[key]); ok {
+			used[key] = true
+			info.Clock = &clock
+			break
+		}
+	}
+	for _, key := range infoKeys["ntp"] {
+		if synced, ok := parseFlag(doc[key]); ok {
+			used[key] = true
+			info.ClockSynced = &synced
+			break
+		}
+	}
+	if info.ClockSynced == nil && info.Clock != nil && !info.Clock.After(clockSetAfter) {
+		// Still counting from 1970: the clock was never set
+		synced := false
+		info.ClockSynced = &synced
+	}
+
+	for key, value := range doc {
+		if used[key] {
+			continue
+		}
+		if info.Extra == nil {
+			info.Extra = map[string]interface{}{}
+		}
+		info.Extra[key] = value
+	}
+	return info, nil
+}
+
+// infoNumber reads a whole number reported as a JSON number or a string
+func infoNumber(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int64(v), true
+	case string:
+		n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// Uptime returns the reported uptime, or 0 if the firmware did not report it
+func (i *Info) Uptime() time.Duration {
+	if i.UptimeSeconds == nil {
+		return 0
+	}
+	return time.Duration(*i.UptimeSeconds) * time.Second
+}
+
+// FetchInfo queries the UFO's /info endpoint and parses it. Firmware without
+// /info is asked for its /api status document instead, which some builds
+// extend with the same fields. The uptime is also used to notice restarts
+// (see OnReboot).
+func (c *Client) FetchInfo(ctx context.Context) (*Info, error) {
+	start := time.Now()
+	body, err := c.get(ctx, c.url("/info"))
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound {
+		start = time.Now()
+		body, err = c.SendRawQuery(ctx, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The UFO read its clock about halfway through the request
+	answered := start.Add(time.Since(start) / 2)
+	info, err := ParseInfo(body)
+	if err != nil {
+		return info, err
+	}
+	c.observeClock(info, answered)
+	return info, nil
+}
+
+// get fetches a URL from the UFO once, within the concurrency limit
+func (c *Client) get(ctx context.Context, url string) (string, error) {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	start := time.Now()
+	body, err := c.fetch(ctx, url)
+	c.stats.record(time.Since(start), err, false)
+	return body, err
+}
+
+// fetch performs a single GET request to the UFO
+func (c *Client) fetch(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("UFO request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", &StatusError{Code: resp.StatusCode, Body: string(body)}
+	}
+
+	return string(body), nil
+}
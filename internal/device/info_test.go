@@ -0,0 +1,81 @@
+package device
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseInfo(t *testing.T) {
+	info, err := ParseInfo(`{"version":"1.4.2","ipAddress":"192.168.1.50","ssid":"office","ufoid":"ufo-a1b2c3","mac":"AA:BB:CC:DD:EE:FF","uptime":"3725","freeHeap":21000,"apmode":false}`)
+	if err != nil {
+		t.Fatalf("failed to parse info: %v", err)
+	}
+	if info.Firmware != "1.4.2" || info.IP != "192.168.1.50" || info.SSID != "office" || info.Hostname != "ufo-a1b2c3" || info.MAC != "AA:BB:CC:DD:EE:FF" {
+		t.Errorf("unexpected fields: %+v", info)
+	}
+	if info.UptimeSeconds == nil || *info.UptimeSeconds != 3725 || info.Uptime().String() != "1h2m5s" {
+		t.Errorf("unexpected uptime: %v", info.Uptime())
+	}
+	if info.FreeHeap == nil || *info.FreeHeap != 21000 {
+		t.Errorf("unexpected free heap: %v", info.FreeHeap)
+	}
+	if len(info.Extra) != 1 || info.Extra["apmode"] != false {
+		t.Errorf("unrecognized fields should be kept in Extra: %v", info.Extra)
+	}
+
+	// Uptime in milliseconds and missing fields
+	info, err = ParseInfo(`{"firmware":"2.0","millis":90500}`)
+	if err != nil {
+		t.Fatalf("failed to parse info: %v", err)
+	}
+	if info.UptimeSeconds == nil || *info.UptimeSeconds != 90 {
+		t.Errorf("expected 90 seconds uptime, got %v", info.UptimeSeconds)
+	}
+	if info.IP != "" || info.FreeHeap != nil || info.Extra != nil {
+		t.Errorf("unreported fields should be empty: %+v", info)
+	}
+
+	if _, err := ParseInfo("not json"); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestClient_FetchInfo(t *testing.T) {
+	withInfo := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/info" && withInfo:
+			w.Write([]byte(`{"version":"1.4.2","ip":"10.0.0.7"}`))
+		case r.URL.Path == "/api":
+			w.Write([]byte(`{"top":[],"dim":100,"ssid":"lab"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := NewClient()
+	info, err := client.FetchInfo(context.Background())
+	if err != nil {
+		t.Fatalf("failed to fetch info: %v", err)
+	}
+	if info.Firmware != "1.4.2" || info.IP != "10.0.0.7" {
+		t.Errorf("unexpected info: %+v", info)
+	}
+
+	// Firmware without /info falls back to the status document
+	withInfo = false
+	info, err = client.FetchInfo(context.Background())
+	if err != nil {
+		t.Fatalf("failed to fetch info: %v", err)
+	}
+	if info.SSID != "lab" || info.Firmware != "" {
+		t.Errorf("unexpected fallback info: %+v", info)
+	}
+	if client.Address() != server.URL[7:] {
+		t.Errorf("unexpected address %q", client.Address())
+	}
+}
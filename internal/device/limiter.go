@@ -0,0 +1,97 @@
+package device
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultMaxConcurrent is how many requests are sent to the UFO at once by
+// default. The stock firmware handles one request at a time reliably; some
+// builds cope with two or three, which helps animation throughput.
+const DefaultMaxConcurrent = 1
+
+// Concurrency describes the in-flight request limit and its current use
+type Concurrency struct {
+	Max      int `json:"max"`      // requests allowed in flight at once
+	InFlight int `json:"inFlight"` // requests currently in flight
+	Waiting  int `json:"waiting"`  // requests waiting for a free slot
+	Peak     int `json:"peak"`     // most requests ever in flight at once
+}
+
+// limiter is a counting semaphore bounding requests in flight to the UFO
+type limiter struct {
+	mu       sync.Mutex
+	slots    chan struct{}
+	inFlight int
+	waiting  int
+	peak     int
+}
+
+// setMax replaces the limit. Requests already in flight finish against the
+// old limit and release their slots there.
+func (l *limiter) setMax(n int) {
+	if n < 1 {
+		n = 1
+	}
+	l.mu.Lock()
+	l.slots = make(chan struct{}, n)
+	l.mu.Unlock()
+}
+
+// acquire waits for a free slot and returns the function that releases it
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	l.mu.Lock()
+	if l.slots == nil {
+		l.slots = make(chan struct{}, DefaultMaxConcurrent)
+	}
+	slots := l.slots
+	l.waiting++
+	l.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.waiting--
+		l.mu.Unlock()
+		return nil, ctx.Err()
+	}
+
+	l.mu.Lock()
+	l.waiting--
+	l.inFlight++
+	if l.inFlight > l.peak {
+		l.peak = l.inFlight
+	}
+	l.mu.Unlock()
+
+	return func() {
+		l.mu.Lock()
+		l.inFlight--
+		l.mu.Unlock()
+		<-slots
+	}, nil
+}
+
+// SetMaxConcurrent sets how many requests may be in flight to the UFO at
+// once; values below 1 are treated as 1
+func (c *Client) SetMaxConcurrent(n int) {
+	c.limiter.setMax(n)
+}
+
+// Concurrency returns the in-flight request limit and its current use
+func (c *Client) Concurrency() Concurrency {
+	c.limiter.mu.Lock()
+	defer c.limiter.mu.Unlock()
+
+	max := DefaultMaxConcurrent
+	if c.limiter.slots != nil {
+		max = cap(c.limiter.slots)
+	}
+	return Concurrency{
+		Max:      max,
+		InFlight: c.limiter.inFlight,
+		Waiting:  c.limiter.waiting,
+		Peak:     c.limiter.peak,
+	}
+}
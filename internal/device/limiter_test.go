@@ -0,0 +1,93 @@
+package device
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_LimitsConcurrentRequests(t *testing.T) {
+	var current, peak atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := current.Add(1)
+		defer current.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := NewClient()
+	if got := client.Concurrency().Max; got != DefaultMaxConcurrent {
+		t.Errorf("expected default limit %d, got %d", DefaultMaxConcurrent, got)
+	}
+	client.SetMaxConcurrent(2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.SendRawQuery(context.Background(), "dim=100")
+		}()
+	}
+
+	// InFlight counts a request before it reaches the UFO, so wait until
+	// the server itself holds two
+	deadline := time.Now().Add(time.Second)
+	for current.Load() != 2 || client.Concurrency().Waiting != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the UFO to hold 2 requests with 1 waiting, got %d and %+v", current.Load(), client.Concurrency())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if peak.Load() != 2 {
+		t.Errorf("expected the UFO to see at most 2 requests at once, saw %d", peak.Load())
+	}
+	usage := client.Concurrency()
+	if usage.Max != 2 || usage.InFlight != 0 || usage.Waiting != 0 || usage.Peak != 2 {
+		t.Errorf("unexpected usage after completion: %+v", usage)
+	}
+}
+
+func TestClient_WaitingRequestHonoursContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := NewClient()
+	go client.SendRawQuery(context.Background(), "dim=1")
+	for client.Concurrency().InFlight == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.SendRawQuery(ctx, "dim=2"); err == nil {
+		t.Fatal("expected the waiting request to give up when its context ends")
+	}
+	if usage := client.Concurrency(); usage.Waiting != 0 {
+		t.Errorf("expected no waiting requests, got %+v", usage)
+	}
+	if !client.Online() {
+		t.Error("expected a request that never reached the UFO not to count as a failure")
+	}
+}
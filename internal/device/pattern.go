@@ -0,0 +1,205 @@
+package device
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pattern is a UFO /api query broken into its parameters
+type Pattern struct {
+	Top     *RingPattern `json:"top,omitempty"`
+	Bottom  *RingPattern `json:"bottom,omitempty"`
+	Dim     *int         `json:"dim,omitempty"`
+	Logo    string       `json:"logo,omitempty"`    // "on", "off", 1-4 colors separated by | or "" when not set
+	Unknown []string     `json:"unknown,omitempty"` // parameters the firmware ignores
+}
+
+// RingPattern is what a query sets on one ring
+type RingPattern struct {
+	Init       bool         `json:"init,omitempty"`
+	Background string       `json:"background,omitempty"`
+	Segments   []Segment    `json:"segments,omitempty"`
+	Whirl      *Whirl       `json:"whirl,omitempty"`
+	Morph      *MorphConfig `json:"morph,omitempty"`
+}
+
+// Segment is a run of LEDs in one color, drawn from Start and wrapping
+// around the ring
+type Segment struct {
+	Start int    `json:"start"`
+	Count int    `json:"count"`
+	Color string `json:"color"`
+}
+
+// Whirl is a ring's rotation: the pattern moves one LED every Ms
+// milliseconds, and 0 stops it
+type Whirl struct {
+	Ms               int  `json:"ms"`
+	CounterClockwise bool `json:"counterClockwise,omitempty"`
+}
+
+// PatternError is a problem with one parameter of a pattern
+type PatternError struct {
+	Param   string // e.g. top_whirl
+	Value   string
+	Problem string
+}
+
+func (e *PatternError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("%s: %s", e.Param, e.Problem)
+	}
+	return fmt.Sprintf("%s=%s: %s", e.Param, e.Value, e.Problem)
+}
+
+// patternParams are the query parameters the firmware knows
+var patternParams = map[string]bool{
+	"top": true, "top_init": true, "top_bg": true, "top_whirl": true, "top_morph": true,
+	"bottom": true, "bottom_init": true, "bottom_bg": true, "bottom_whirl": true, "bottom_morph": true,
+	"dim": true, "logo": true,
+}
+
+// IsPatternParam reports whether the firmware knows a query parameter
+func IsPatternParam(name string) bool {
+	return patternParams[name]
+}
+
+// ParsePattern reads an /api query, with or without a leading "?", checking
+// every known parameter's value. Parameters the firmware does not know are
+// listed in Unknown rather than rejected, since the firmware ignores them;
+// ValidatePattern rejects them too. Segments may be given several times and
+// are drawn in order; other parameters may be given once.
+func ParsePattern(query string) (*Pattern, error) {
+	pattern := &Pattern{}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(strings.TrimLeft(query, "?/"), "&") {
+		if part == "" {
+			continue
+		}
+		name, value, hasValue := strings.Cut(part, "=")
+		if !IsPatternParam(name) {
+			pattern.Unknown = append(pattern.Unknown, name)
+			continue
+		}
+		if !hasValue {
+			return nil, &PatternError{Param: name, Problem: "needs a value"}
+		}
+		if seen[name] && name != "top" && name != "bottom" {
+			return nil, &PatternError{Param: name, Value: value, Problem: "given more than once"}
+		}
+		seen[name] = true
+		if err := pattern.set(name, value); err != nil {
+			return nil, &PatternError{Param: name, Value: value, Problem: err.Error()}
+		}
+	}
+	if pattern.Top == nil && pattern.Bottom == nil && pattern.Dim == nil && pattern.Logo == "" && len(pattern.Unknown) == 0 {
+		return nil, fmt.Errorf("the pattern sets nothing")
+	}
+	return pattern, nil
+}
+
+// ValidatePattern checks that a query only uses parameters the firmware
+// knows, with values it accepts
+func ValidatePattern(query string) error {
+	pattern, err := ParsePattern(query)
+	if err != nil {
+		return err
+	}
+	if len(pattern.Unknown) > 0 {
+		return &PatternError{
+			Param:   pattern.Unknown[0],
+			Problem: "not a UFO parameter; use top, bottom, top_init, top_bg, top_whirl, top_morph (and the bottom_ ones), dim or logo",
+		}
+	}
+	return nil
+}
+
+// set checks and records one known parameter
+func (p *Pattern) set(name, value string) error {
+	switch name {
+	case "dim":
+		level, err := strconv.Atoi(value)
+		if err != nil || level < 0 || level > 255 {
+			return fmt.Errorf("brightness must be a whole number from 0 to 255")
+		}
+		p.Dim = &level
+		return nil
+	case "logo":
+		if _, err := parseLogo(value); err != nil {
+			return fmt.Errorf("logo must be 'on', 'off' or 1-4 six digit hex colors separated by |")
+		}
+		p.Logo = value
+		return nil
+	}
+
+	ringName, param, _ := strings.Cut(name, "_")
+	ring := &p.Top
+	if ringName == "bottom" {
+		ring = &p.Bottom
+	}
+	if *ring == nil {
+		*ring = &RingPattern{}
+	}
+	r := *ring
+	switch param {
+	case "":
+		segments, err := parseSegments(value)
+		if err != nil {
+			return err
+		}
+		r.Segments = append(r.Segments, segments...)
+	case "init":
+		if value != "1" {
+			return fmt.Errorf("init must be 1")
+		}
+		r.Init = true
+	case "bg":
+		if !isHexColor(value) {
+			return fmt.Errorf("background must be a six digit hex color such as ff0000")
+		}
+		r.Background = strings.ToLower(value)
+	case "whirl":
+		speed, direction, hasDirection := strings.Cut(value, "|")
+		ms, err := strconv.Atoi(speed)
+		if err != nil || ms < 0 || ms > MaxWhirlMs {
+			return fmt.Errorf("whirl must be a whole number of milliseconds from 0 to %d, optionally followed by |ccw", MaxWhirlMs)
+		}
+		if hasDirection && direction != "ccw" {
+			return fmt.Errorf("the only whirl direction is ccw, got %q", direction)
+		}
+		r.Whirl = &Whirl{Ms: ms, CounterClockwise: hasDirection}
+	case "morph":
+		morph := ConvertMorphFromDevice(value)
+		if morph == nil || morph.BrightnessMs < 0 {
+			return fmt.Errorf("morph must be ticks|speed, with ticks a whole number from 0 and speed from 1 to 10")
+		}
+		r.Morph = morph
+	}
+	return nil
+}
+
+// parseSegments reads start|count|color triples
+func parseSegments(value string) ([]Segment, error) {
+	parts := strings.Split(value, "|")
+	if len(parts)%3 != 0 {
+		return nil, fmt.Errorf("segments must be start|count|color triples, got %d values", len(parts))
+	}
+	segments := make([]Segment, 0, len(parts)/3)
+	for i := 0; i < len(parts); i += 3 {
+		triple := strings.Join(parts[i:i+3], "|")
+		start, err := strconv.Atoi(parts[i])
+		if err != nil || start < 0 || start >= RingLEDs {
+			return nil, fmt.Errorf("segment %s: start must be an LED from 0 to %d", triple, RingLEDs-1)
+		}
+		count, err := strconv.Atoi(parts[i+1])
+		if err != nil || count < 1 || count > RingLEDs {
+			return nil, fmt.Errorf("segment %s: count must be from 1 to %d LEDs", triple, RingLEDs)
+		}
+		if !isHexColor(parts[i+2]) {
+			return nil, fmt.Errorf("segment %s: color must be six digit hex such as ff0000", triple)
+		}
+		segments = append(segments, Segment{Start: start, Count: count, Color: strings.ToLower(parts[i+2])})
+	}
+	return segments, nil
+}
@@ -0,0 +1,84 @@
+package device
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParsePattern(t *testing.T) {
+	pattern, err := ParsePattern("?top_init=1&top_bg=0000FF&top=0|2|ff0000&top=5|3|00ff00|9|1|ffffff&bottom_whirl=250|ccw&bottom_morph=300|5&dim=80&logo=on&effect=rainbow")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pattern.Top == nil || !pattern.Top.Init || pattern.Top.Background != "0000ff" {
+		t.Fatalf("unexpected top ring %+v", pattern.Top)
+	}
+	if len(pattern.Top.Segments) != 3 || pattern.Top.Segments[1] != (Segment{Start: 5, Count: 3, Color: "00ff00"}) {
+		t.Errorf("unexpected segments %+v", pattern.Top.Segments)
+	}
+	if pattern.Bottom == nil || pattern.Bottom.Whirl == nil || *pattern.Bottom.Whirl != (Whirl{Ms: 250, CounterClockwise: true}) {
+		t.Errorf("unexpected bottom whirl %+v", pattern.Bottom)
+	}
+	if pattern.Bottom.Morph == nil || pattern.Bottom.Morph.FadeMs != 666 {
+		t.Errorf("unexpected bottom morph %+v", pattern.Bottom.Morph)
+	}
+	if pattern.Dim == nil || *pattern.Dim != 80 || pattern.Logo != "on" {
+		t.Errorf("unexpected dim %v or logo %q", pattern.Dim, pattern.Logo)
+	}
+	if len(pattern.Unknown) != 1 || pattern.Unknown[0] != "effect" {
+		t.Errorf("unknown = %v, want [effect]", pattern.Unknown)
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	valid := []string{
+		"top_init=1&top=0|15|FF0000",
+		"bottom=0|15|00FF00&bottom_whirl=300",
+		"top=0|5|FF0000|10|5|00FF00&bottom_whirl=400|ccw&logo=on",
+		"top_whirl=0&dim=0",
+		"logo=ff0000|00ff00",
+	}
+	for _, query := range valid {
+		if err := ValidatePattern(query); err != nil {
+			t.Errorf("%s: unexpected error: %v", query, err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		param string
+		want  string
+	}{
+		{"top=0|15", "top", "start|count|color triples"},
+		{"top=15|1|ff0000", "top", "start must be an LED from 0 to 14"},
+		{"top=0|0|ff0000", "top", "count must be from 1 to 15"},
+		{"top=0|3|red", "top", "color must be six digit hex"},
+		{"bottom_bg=blue", "bottom_bg", "six digit hex color"},
+		{"top_whirl=600", "top_whirl", "from 0 to 510"},
+		{"top_whirl=300|left", "top_whirl", "only whirl direction is ccw"},
+		{"top_morph=300", "top_morph", "ticks|speed"},
+		{"top_morph=300|11", "top_morph", "speed from 1 to 10"},
+		{"top_init=yes", "top_init", "init must be 1"},
+		{"top_init", "top_init", "needs a value"},
+		{"dim=256", "dim", "from 0 to 255"},
+		{"logo=1", "logo", "'on', 'off' or 1-4"},
+		{"dim=10&dim=20", "dim", "more than once"},
+		{"top_whril=300", "top_whril", "not a UFO parameter"},
+	}
+	for _, tt := range tests {
+		err := ValidatePattern(tt.query)
+		var patternErr *PatternError
+		if !errors.As(err, &patternErr) {
+			t.Errorf("%s: got %v, want a pattern error", tt.query, err)
+			continue
+		}
+		if patternErr.Param != tt.param || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got %q for %s, want %q for %s", tt.query, err, patternErr.Param, tt.want, tt.param)
+		}
+	}
+
+	if err := ValidatePattern("&&"); err == nil || !strings.Contains(err.Error(), "sets nothing") {
+		t.Errorf("empty pattern: got %v", err)
+	}
+}
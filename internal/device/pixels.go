@@ -0,0 +1,53 @@
+package device
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CompressRing turns the colors of every LED of a ring into the shortest
+// query form: the most common color becomes the background and the other
+// LEDs are grouped into "start|count|color" segments. A run crossing the end
+// of the ring is sent as one segment, since segments wrap around.
+func CompressRing(leds []string) (background string, segments []string, err error) {
+	if len(leds) != RingLEDs {
+		return "", nil, fmt.Errorf("a ring has %d LEDs, got %d colors", RingLEDs, len(leds))
+	}
+	colors := make([]string, RingLEDs)
+	counts := map[string]int{}
+	for i, led := range leds {
+		if !isHexColor(led) {
+			return "", nil, fmt.Errorf("LED %d: invalid color %q", i, led)
+		}
+		colors[i] = strings.ToUpper(led)
+		counts[colors[i]]++
+	}
+
+	// The most common color wins; ties go to the color seen first
+	for _, c := range colors {
+		if background == "" || counts[c] > counts[background] {
+			background = c
+		}
+	}
+	if counts[background] == RingLEDs {
+		return background, nil, nil
+	}
+
+	// Start at an LED that begins a run, so no run is split at LED 0
+	start := 0
+	for colors[start] == colors[(start+RingLEDs-1)%RingLEDs] {
+		start++
+	}
+	for n := 0; n < RingLEDs; {
+		i := (start + n) % RingLEDs
+		length := 1
+		for length < RingLEDs-n && colors[(i+length)%RingLEDs] == colors[i] {
+			length++
+		}
+		if colors[i] != background {
+			segments = append(segments, fmt.Sprintf("%d|%d|%s", i, length, colors[i]))
+		}
+		n += length
+	}
+	return background, segments, nil
+}
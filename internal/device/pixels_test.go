@@ -0,0 +1,66 @@
+package device
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ring builds a ring of colors from a compact description, one letter per LED
+func ring(leds string) []string {
+	names := map[rune]string{'.': "000000", 'r': "FF0000", 'g': "00FF00", 'b': "0000FF"}
+	var colors []string
+	for _, c := range leds {
+		colors = append(colors, names[c])
+	}
+	return colors
+}
+
+func TestCompressRing(t *testing.T) {
+	tests := []struct {
+		leds       string
+		background string
+		segments   []string
+	}{
+		{"...............", "000000", nil},
+		{"rrrrr..........", "000000", []string{"0|5|FF0000"}},
+		{"r.r.r.r.r.r.r.r", "FF0000", []string{"1|1|000000", "3|1|000000", "5|1|000000", "7|1|000000", "9|1|000000", "11|1|000000", "13|1|000000"}},
+		{"rr...........rr", "000000", []string{"13|4|FF0000"}},
+		{"gggggrrrrrbbbbb", "00FF00", []string{"5|5|FF0000", "10|5|0000FF"}},
+		{"bbb.......ggggg", "000000", []string{"0|3|0000FF", "10|5|00FF00"}},
+	}
+	for _, tt := range tests {
+		background, segments, err := CompressRing(ring(tt.leds))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.leds, err)
+		}
+		if background != tt.background || !reflect.DeepEqual(segments, tt.segments) {
+			t.Errorf("%s: got background %s segments %v, want %s %v", tt.leds, background, segments, tt.background, tt.segments)
+		}
+
+		// The compressed form must draw the same ring
+		sim := NewSimulator()
+		query := "top_init=1&top_bg=" + background
+		if len(segments) > 0 {
+			query += "&top=" + strings.Join(segments, "|")
+		}
+		if err := sim.Apply(query); err != nil {
+			t.Fatalf("%s: simulator rejected %s: %v", tt.leds, query, err)
+		}
+		want := ring(tt.leds)
+		for i, led := range sim.rings["top"].LEDs {
+			if !strings.EqualFold(led, want[i]) {
+				t.Errorf("%s: LED %d is %s, want %s", tt.leds, i, led, want[i])
+			}
+		}
+	}
+
+	if _, _, err := CompressRing(ring("rrr")); err == nil {
+		t.Error("expected an error for a short ring")
+	}
+	bad := ring("...............")
+	bad[3] = "red"
+	if _, _, err := CompressRing(bad); err == nil {
+		t.Error("expected an error for an invalid color")
+	}
+}
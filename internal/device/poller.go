@@ -0,0 +1,156 @@
+package device
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Status represents the LED state reported by the UFO firmware
+type Status struct {
+	Top    []string `json:"top,omitempty"`    // hex colors for top ring, nil if not reported
+	Bottom []string `json:"bottom,omitempty"` // hex colors for bottom ring, nil if not reported
+	Dim    *int     `json:"dim,omitempty"`    // brightness level 0-255, nil if not reported
+	LogoOn *bool    `json:"logoOn,omitempty"` // logo LED state, nil if not reported
+	Button *int     `json:"button,omitempty"` // button presses counted since the UFO booted, nil if not reported
+	Raw    string   `json:"-"`                // unparsed firmware response
+}
+
+// ParseStatus parses the JSON status document returned by the UFO firmware.
+// Fields the firmware does not report are left nil so callers can tell
+// "unknown" apart from "off".
+func ParseStatus(body string) (*Status, error) {
+	status := &Status{Raw: body}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return status, fmt.Errorf("parsing UFO status: %w", err)
+	}
+
+	status.Top = parseRingColors(doc["top"])
+	status.Bottom = parseRingColors(doc["bottom"])
+
+	if dim, ok := doc["dim"].(float64); ok {
+		level := int(dim)
+		status.Dim = &level
+	}
+
+	switch v := doc["logo"].(type) {
+	case bool:
+		status.LogoOn = &v
+	case string:
+		on := strings.EqualFold(v, "on")
+		status.LogoOn = &on
+	}
+
+	if presses, ok := doc["button"].(float64); ok && presses >= 0 {
+		count := int(presses)
+		status.Button = &count
+	}
+
+	return status, nil
+}
+
+// parseRingColors converts a JSON array of hex colors into a 15-LED slice
+func parseRingColors(value interface{}) []string {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	colors := make([]string, 15)
+	for i := range colors {
+		colors[i] = "000000"
+	}
+	for i, item := range items {
+		if i >= 15 {
+			break
+		}
+		if color, ok := item.(string); ok && color != "" {
+			colors[i] = strings.ToLower(strings.TrimPrefix(color, "#"))
+		}
+	}
+	return colors
+}
+
+// FetchStatus queries the UFO and parses its reported LED state
+func (c *Client) FetchStatus(ctx context.Context) (*Status, error) {
+	resp, err := c.SendRawQuery(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return ParseStatus(resp)
+}
+
+// Poller periodically queries the UFO and hands the parsed status to a callback
+type Poller struct {
+	client   *Client
+	interval time.Duration
+	onStatus func(*Status)
+	noUptime bool // the firmware does not report its uptime
+	button   buttonTracker
+}
+
+// NewPoller creates a new status poller. onStatus is invoked for every
+// successfully parsed status response.
+func NewPoller(client *Client, interval time.Duration, onStatus func(*Status)) *Poller {
+	return &Poller{
+		client:   client,
+		interval: interval,
+		onStatus: onStatus,
+	}
+}
+
+// Start runs the polling loop in the background until ctx is cancelled
+func (p *Poller) Start(ctx context.Context) {
+	if p.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := p.PollOnce(ctx); err != nil {
+					slog.WarnContext(ctx, "Status poll failed", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// PollOnce performs a single status query and reconciliation. It also reads
+// the UFO's info so a restart is noticed (see Client.OnReboot); firmware
+// that does not report its uptime is only polled for status.
+func (p *Poller) PollOnce(ctx context.Context) error {
+	pollCtx, cancel := context.WithTimeout(ctx, p.interval+5*time.Second)
+	defer cancel()
+
+	status, err := p.client.FetchStatus(pollCtx)
+	if err != nil {
+		return err
+	}
+
+	if p.onStatus != nil {
+		p.onStatus(status)
+	}
+	p.button.observe(status.Button)
+
+	if !p.noUptime {
+		info, err := p.client.FetchInfo(pollCtx)
+		if err != nil {
+			slog.DebugContext(ctx, "Uptime poll failed", "error", err)
+		} else if info.UptimeSeconds == nil {
+			p.noUptime = true
+		}
+	}
+	return nil
+}
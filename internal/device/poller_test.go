@@ -0,0 +1,113 @@
+package device
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestParseStatus(t *testing.T) {
+	body := `{"top":["FF0000","#00ff00"],"dim":128,"logo":"on"}`
+
+	status, err := ParseStatus(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(status.Top) != 15 {
+		t.Fatalf("expected 15 top colors, got %d", len(status.Top))
+	}
+	if status.Top[0] != "ff0000" || status.Top[1] != "00ff00" || status.Top[2] != "000000" {
+		t.Errorf("unexpected top colors: %v", status.Top[:3])
+	}
+	if status.Bottom != nil {
+		t.Errorf("expected bottom to be unknown, got %v", status.Bottom)
+	}
+	if status.Dim == nil || *status.Dim != 128 {
+		t.Errorf("expected dim 128, got %v", status.Dim)
+	}
+	if status.LogoOn == nil || !*status.LogoOn {
+		t.Errorf("expected logo on, got %v", status.LogoOn)
+	}
+}
+
+func TestParseStatus_NotJSON(t *testing.T) {
+	status, err := ParseStatus("OK")
+	if err == nil {
+		t.Error("expected error for non-JSON status")
+	}
+	if status.Raw != "OK" {
+		t.Errorf("expected raw response to be kept, got %q", status.Raw)
+	}
+}
+
+func TestPoller_PollOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"dim":42,"logo":false}`))
+	}))
+	defer server.Close()
+
+	os.Setenv("UFO_IP", server.URL[7:])
+	defer os.Unsetenv("UFO_IP")
+
+	var received *Status
+	poller := NewPoller(NewClient(), 0, func(status *Status) {
+		received = status
+	})
+
+	if err := poller.PollOnce(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received == nil {
+		t.Fatal("expected status callback to be invoked")
+	}
+	if received.Dim == nil || *received.Dim != 42 {
+		t.Errorf("expected dim 42, got %v", received.Dim)
+	}
+	if received.LogoOn == nil || *received.LogoOn {
+		t.Errorf("expected logo off, got %v", received.LogoOn)
+	}
+}
+
+func TestPoller_OnButtonPress(t *testing.T) {
+	responses := []string{
+		`{"dim":42}`,
+		`{"button":3}`,
+		`{"button":3}`,
+		`{"button":5}`,
+		`{"button":1}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Path == "/info" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		w.Write([]byte(responses[0]))
+		responses = responses[1:]
+	}))
+	defer server.Close()
+
+	os.Setenv("UFO_IP", server.URL[7:])
+	defer os.Unsetenv("UFO_IP")
+
+	var presses []int
+	poller := NewPoller(NewClient(), 0, nil)
+	poller.OnButtonPress(func(n int) {
+		presses = append(presses, n)
+	})
+
+	for range 5 {
+		if err := poller.PollOnce(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// The first counter is the baseline; the counter going back means the
+	// UFO restarted and pressed once since
+	if len(presses) != 2 || presses[0] != 2 || presses[1] != 1 {
+		t.Errorf("expected presses [2 1], got %v", presses)
+	}
+}
@@ -0,0 +1,101 @@
+package device
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PreviewStep is a query sent atMs milliseconds into a preview
+type PreviewStep struct {
+	AtMs  int
+	Query string
+}
+
+// PreviewFrame is what the UFO shows at one moment of a preview, with whirl
+// and morph animation applied
+type PreviewFrame struct {
+	AtMs int `json:"atMs"`
+	Frame
+	Dim    int  `json:"dim"`
+	LogoOn bool `json:"logoOn"`
+}
+
+// Render plays steps on a virtual UFO that starts dark and returns what it
+// shows every intervalMs from 0 to durationMs. Whirling rings move one LED
+// every whirl interval and morphing rings hold, fade to their background and
+// back, timed from the last query that touched the ring, using the same
+// conversions as the tools. Nothing is sent to a real UFO.
+func Render(steps []PreviewStep, durationMs, intervalMs int) ([]PreviewFrame, error) {
+	if intervalMs <= 0 {
+		return nil, fmt.Errorf("interval must be positive, got %d", intervalMs)
+	}
+	steps = append([]PreviewStep(nil), steps...)
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].AtMs < steps[j].AtMs })
+
+	sim := NewSimulator()
+	since := map[string]int{}
+	var frames []PreviewFrame
+	next := 0
+	for at := 0; at <= durationMs; at += intervalMs {
+		for ; next < len(steps) && steps[next].AtMs <= at; next++ {
+			rings, _, _, err := parseAPIQuery(steps[next].Query)
+			if err != nil {
+				return nil, fmt.Errorf("query at %dms: %w", steps[next].AtMs, err)
+			}
+			if err := sim.Apply(steps[next].Query); err != nil {
+				return nil, fmt.Errorf("query at %dms: %w", steps[next].AtMs, err)
+			}
+			for name := range rings {
+				since[name] = steps[next].AtMs
+			}
+		}
+		frames = append(frames, sim.frameAt(at, since))
+	}
+	return frames, nil
+}
+
+// frameAt returns what the virtual UFO shows atMs into a preview, given
+// when each ring was last drawn
+func (s *Simulator) frameAt(atMs int, since map[string]int) PreviewFrame {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	frame := PreviewFrame{AtMs: atMs, Dim: s.dim, LogoOn: s.logoOn}
+	frame.Top = s.rings["top"].animate(atMs - since["top"])
+	frame.Bottom = s.rings["bottom"].animate(atMs - since["bottom"])
+	return frame
+}
+
+// animate returns the ring's LEDs elapsedMs after it was drawn
+func (r *simulatedRing) animate(elapsedMs int) [RingLEDs]string {
+	leds := r.LEDs
+	if r.WhirlMs > 0 {
+		shift := (elapsedMs / r.WhirlMs) % RingLEDs
+		if r.CCW {
+			shift = (RingLEDs - shift) % RingLEDs
+		}
+		for i, led := range r.LEDs {
+			leds[(i+shift)%RingLEDs] = led
+		}
+	}
+
+	morph := ConvertMorphFromDevice(r.Morph)
+	if morph == nil || morph.FadeMs <= 0 {
+		return leds
+	}
+	hold, fade := morph.BrightnessMs, morph.FadeMs
+	phase := elapsedMs % (hold + 2*fade)
+	var t float64
+	switch {
+	case phase < hold:
+		return leds
+	case phase < hold+fade:
+		t = float64(phase-hold) / float64(fade)
+	default:
+		t = float64(hold+2*fade-phase) / float64(fade)
+	}
+	for i, led := range leds {
+		leds[i] = MixColor(led, r.Background, t)
+	}
+	return leds
+}
@@ -0,0 +1,69 @@
+package device
+
+import "testing"
+
+func TestRender_Whirl(t *testing.T) {
+	frames, err := Render([]PreviewStep{{Query: "top_init=1&top=0|1|FF0000&top_whirl=100&dim=80"}}, 300, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(frames) != 4 {
+		t.Fatalf("got %d frames, want 4", len(frames))
+	}
+	for i, frame := range frames {
+		if frame.AtMs != i*100 {
+			t.Errorf("frame %d at %dms, want %dms", i, frame.AtMs, i*100)
+		}
+		if frame.Top[i] != "ff0000" {
+			t.Errorf("frame %d: red LED not at %d: %v", i, i, frame.Top)
+		}
+		if frame.Dim != 80 {
+			t.Errorf("frame %d: dim %d, want 80", i, frame.Dim)
+		}
+	}
+
+	frames, err = Render([]PreviewStep{{Query: "bottom_init=1&bottom=0|1|0000FF&bottom_whirl=100|ccw"}}, 100, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if frames[1].Bottom[RingLEDs-1] != "0000ff" {
+		t.Errorf("counter-clockwise whirl should move LED 0 to %d: %v", RingLEDs-1, frames[1].Bottom)
+	}
+}
+
+func TestRender_Morph(t *testing.T) {
+	// 150 ticks hold for about a second, speed 10 fades for 333ms each way
+	frames, err := Render([]PreviewStep{{Query: "top_init=1&top_bg=000000&top=0|15|FF0000&top_morph=150|10"}}, 2000, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for at, want := range map[int]string{0: "ff0000", 999: "ff0000", 1333: "000000", 1500: "800000", 1666: "ff0000", 1700: "ff0000"} {
+		if led := frames[at].Top[0]; led != want {
+			t.Errorf("at %dms LED 0 is %s, want %s", at, led, want)
+		}
+	}
+}
+
+func TestRender_Steps(t *testing.T) {
+	steps := []PreviewStep{
+		{AtMs: 200, Query: "top_init=1&top_bg=0000FF"},
+		{AtMs: 0, Query: "top_init=1&top_bg=FF0000&logo=on"},
+	}
+	frames, err := Render(steps, 300, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"ff0000", "ff0000", "0000ff", "0000ff"}
+	for i, frame := range frames {
+		if frame.Top[0] != want[i] || !frame.LogoOn {
+			t.Errorf("at %dms LED 0 is %s logo %v, want %s and on", frame.AtMs, frame.Top[0], frame.LogoOn, want[i])
+		}
+	}
+
+	if _, err := Render([]PreviewStep{{Query: "top=0|99"}}, 100, 100); err == nil {
+		t.Error("expected an error for an invalid query")
+	}
+	if _, err := Render(nil, 100, 0); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+}
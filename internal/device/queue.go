@@ -0,0 +1,223 @@
+package device
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRequestsPerSecond is the write rate limit used by the server by
+// default; NewClient starts without one
+const DefaultRequestsPerSecond = 10
+
+// WriteQueue describes the rate-limited queue of writes to the UFO
+type WriteQueue struct {
+	RequestsPerSecond float64 `json:"requestsPerSecond"` // 0 when writes are not rate limited
+	Depth             int     `json:"depth"`             // writes waiting to be sent
+	Coalesced         uint64  `json:"coalesced"`         // writes merged into a later write before being sent
+}
+
+// writeQueue sends writes to the UFO one at a time, in order, no faster
+// than its rate. A write that would only be overwritten by the write queued
+// right after it is merged into that write, so bursts (a slider sending
+// dim= after dim=) reach the UFO as a single request.
+type writeQueue struct {
+	mu        sync.Mutex
+	interval  time.Duration // minimum time between writes; 0 disables the queue
+	next      time.Time     // earliest time the next write may be sent
+	pending   []*queuedWrite
+	draining  bool
+	coalesced uint64
+}
+
+// queuedWrite is a write waiting in the queue. Callers whose writes were
+// merged share it and receive the same result.
+type queuedWrite struct {
+	ctx     context.Context
+	query   string
+	key     string // coalescing key; empty when the write cannot be merged
+	waiters int
+	done    chan struct{}
+	resp    string
+	err     error
+}
+
+// setRate replaces the rate limit; 0 or less disables the queue
+func (q *writeQueue) setRate(perSecond float64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if perSecond <= 0 {
+		q.interval = 0
+		return
+	}
+	q.interval = time.Duration(float64(time.Second) / perSecond)
+}
+
+// enabled reports whether writes go through the queue
+func (q *writeQueue) enabled() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.interval > 0
+}
+
+// submit queues a write and waits for its result. A caller that gives up
+// before its write is sent removes it from the queue, unless other writes
+// were merged into it.
+func (q *writeQueue) submit(ctx context.Context, query string, send func(context.Context, string) (string, error)) (string, error) {
+	key := coalesceKey(query)
+
+	q.mu.Lock()
+	var w *queuedWrite
+	if n := len(q.pending); key != "" && n > 0 && q.pending[n-1].key == key {
+		w = q.pending[n-1]
+		w.ctx = ctx
+		w.query = query
+		q.coalesced++
+	} else {
+		w = &queuedWrite{ctx: ctx, query: query, key: key, done: make(chan struct{})}
+		q.pending = append(q.pending, w)
+	}
+	w.waiters++
+	if !q.draining {
+		q.draining = true
+		go q.drain(send)
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return w.resp, w.err
+	case <-ctx.Done():
+		q.mu.Lock()
+		w.waiters--
+		if w.waiters == 0 {
+			q.remove(w)
+		}
+		q.mu.Unlock()
+		return "", ctx.Err()
+	}
+}
+
+// remove drops a write that is still waiting; q.mu must be held
+func (q *writeQueue) remove(w *queuedWrite) {
+	for i, pending := range q.pending {
+		if pending == w {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// drain sends queued writes until the queue is empty. Writes stay in the
+// queue while waiting for their turn, so later writes can still be merged
+// into them; once sent, a write is no longer cancelled with its caller, as
+// the callers merged into it still wait for it.
+func (q *writeQueue) drain(send func(context.Context, string) (string, error)) {
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.draining = false
+			q.mu.Unlock()
+			return
+		}
+		if wait := time.Until(q.next); wait > 0 {
+			q.mu.Unlock()
+			time.Sleep(wait)
+			continue
+		}
+		w := q.pending[0]
+		q.pending = q.pending[1:]
+		q.next = time.Now().Add(q.interval)
+		ctx, query := w.ctx, w.query
+		q.mu.Unlock()
+
+		w.resp, w.err = send(context.WithoutCancel(ctx), query)
+		close(w.done)
+	}
+}
+
+// pace waits for the next write slot and takes it, for a write sent
+// without going through the queue
+func (q *writeQueue) pace(ctx context.Context) error {
+	for {
+		q.mu.Lock()
+		wait := time.Until(q.next)
+		if q.interval == 0 || wait <= 0 {
+			q.next = time.Now().Add(q.interval)
+			q.mu.Unlock()
+			return nil
+		}
+		q.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// stats returns the queue's rate and depth
+func (q *writeQueue) stats() WriteQueue {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := WriteQueue{Depth: len(q.pending), Coalesced: q.coalesced}
+	if q.interval > 0 {
+		stats.RequestsPerSecond = float64(time.Second) / float64(q.interval)
+	}
+	return stats
+}
+
+// absoluteParams replace a setting outright, so a later write of the same
+// parameters makes an earlier one redundant
+var absoluteParams = map[string]bool{"dim": true, "logo": true}
+
+// coalesceKey returns the parameter names of a write that fully replaces
+// the state it sets, or "" if applying it first could change the result of
+// a later write. Ring parameters only qualify alongside their ring's _init,
+// which clears the ring before drawing it again.
+func coalesceKey(query string) string {
+	query = strings.TrimLeft(query, "?/")
+	if query == "" {
+		return ""
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(query, "&") {
+		name, _, _ := strings.Cut(part, "=")
+		if seen[name] {
+			return ""
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	for _, name := range names {
+		if absoluteParams[name] {
+			continue
+		}
+		ring, _, _ := strings.Cut(name, "_")
+		if (ring != "top" && ring != "bottom") || !seen[ring+"_init"] {
+			return ""
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, "&")
+}
+
+// SetRateLimit limits writes to the UFO to perSecond requests per second,
+// queueing them in order and merging redundant consecutive writes. Status
+// reads are not limited. 0 or less sends writes as soon as they are made.
+func (c *Client) SetRateLimit(perSecond float64) {
+	c.queue.setRate(perSecond)
+}
+
+// WriteQueue returns the write rate limit and the queue's current depth
+func (c *Client) WriteQueue() WriteQueue {
+	return c.queue.stats()
+}
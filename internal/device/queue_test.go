@@ -0,0 +1,196 @@
+package device
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCoalesceKey(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"dim=100", "dim"},
+		{"logo=on", "logo"},
+		{"top_init=1&top=0|15|FF0000", "top&top_init"},
+		{"top_init=1&top=0|15|FF0000&dim=50", "dim&top&top_init"},
+		{"top=0|5|FF0000", ""},                // adds to the ring without clearing it
+		{"top_init=1&bottom=0|15|00FF00", ""}, // bottom is not cleared
+		{"dim=10&dim=20", ""},
+		{"effect=rainbow", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := coalesceKey(tt.query); got != tt.want {
+			t.Errorf("coalesceKey(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestClient_RateLimitsWrites(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := NewClient()
+	client.SetRateLimit(20)
+	if got := client.WriteQueue().RequestsPerSecond; got != 20 {
+		t.Errorf("expected 20 requests per second, got %v", got)
+	}
+
+	for _, query := range []string{"logo=on", "top=0|1|FF0000", "logo=off"} {
+		if _, err := client.SendRawQuery(context.Background(), query); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(times) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(times))
+	}
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap < 45*time.Millisecond {
+			t.Errorf("writes %d and %d were %v apart, expected at least 50ms", i-1, i, gap)
+		}
+	}
+}
+
+func TestClient_CoalescesBurst(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		first := len(queries) == 1
+		mu.Unlock()
+		if first {
+			<-release
+		}
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := NewClient()
+	client.SetRateLimit(100)
+
+	// The first write blocks the queue while a burst of dim= piles up
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		client.SendRawQuery(context.Background(), "logo=on")
+	}()
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(queries)
+		mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("first write never reached the UFO")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	results := make([]error, 3)
+	for i, level := range []string{"dim=10", "dim=20", "dim=30"} {
+		wg.Add(1)
+		go func(i int, query string) {
+			defer wg.Done()
+			_, results[i] = client.SendRawQuery(context.Background(), query)
+		}(i, level)
+		// Queue the burst in order
+		for client.WriteQueue().Depth != 1 || (i > 0 && client.WriteQueue().Coalesced != uint64(i)) {
+			if time.Now().After(deadline) {
+				t.Fatalf("burst not queued: %+v", client.WriteQueue())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	close(release)
+	wg.Wait()
+
+	for i, err := range results {
+		if err != nil {
+			t.Errorf("write %d failed: %v", i, err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(queries) != 2 || queries[1] != "dim=30" {
+		t.Errorf("expected the burst to be sent as dim=30 only, got %v", queries)
+	}
+	if stats := client.WriteQueue(); stats.Coalesced != 2 || stats.Depth != 0 {
+		t.Errorf("unexpected queue stats: %+v", stats)
+	}
+}
+
+func TestClient_CancelledWriteLeavesQueue(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		if r.URL.RawQuery == "logo=on" {
+			<-release
+		}
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := NewClient()
+	client.SetRateLimit(100)
+
+	done := make(chan struct{})
+	go func() {
+		client.SendRawQuery(context.Background(), "logo=on")
+		close(done)
+	}()
+	for {
+		mu.Lock()
+		sent := len(queries)
+		mu.Unlock()
+		if sent == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.SendRawQuery(ctx, "dim=50"); err == nil {
+		t.Fatal("expected the queued write to time out")
+	}
+	if depth := client.WriteQueue().Depth; depth != 0 {
+		t.Errorf("cancelled write should leave the queue, depth %d", depth)
+	}
+	close(release)
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, query := range queries {
+		if query == "dim=50" {
+			t.Error("cancelled write should not be sent")
+		}
+	}
+}
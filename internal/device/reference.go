@@ -0,0 +1,121 @@
+package device
+
+// APIReference describes the UFO's raw /api query parameters, for clients
+// composing queries for sendRawApi
+type APIReference struct {
+	Endpoint   string         `json:"endpoint"`
+	Layout     Layout         `json:"layout"`
+	Syntax     string         `json:"syntax"`
+	Parameters []APIParameter `json:"parameters"`
+	Examples   []APIExample   `json:"examples"`
+	Notes      []string       `json:"notes"`
+}
+
+// Layout describes the UFO's LEDs
+type Layout struct {
+	Rings       []string `json:"rings"`
+	LEDsPerRing int      `json:"ledsPerRing"`
+	LEDIndexes  string   `json:"ledIndexes"`
+	Logo        string   `json:"logo"`
+}
+
+// APIParameter describes one query parameter. For ring parameters, <ring>
+// in the name stands for top or bottom.
+type APIParameter struct {
+	Name        string    `json:"name"`
+	Format      string    `json:"format"`
+	Description string    `json:"description"`
+	Range       *APIRange `json:"range,omitempty"`
+	Values      []string  `json:"values,omitempty"`
+	Examples    []string  `json:"examples"`
+}
+
+// APIRange is the inclusive range of a numeric value
+type APIRange struct {
+	Min  int    `json:"min"`
+	Max  int    `json:"max"`
+	Unit string `json:"unit,omitempty"`
+}
+
+// APIExample is a complete query and what it shows
+type APIExample struct {
+	Query       string `json:"query"`
+	Description string `json:"description"`
+}
+
+// Reference returns the description of the UFO's raw query parameters
+func Reference() APIReference {
+	return APIReference{
+		Endpoint: "GET http://<ufo-ip>/api?<query>",
+		Layout: Layout{
+			Rings:       []string{"top", "bottom"},
+			LEDsPerRing: RingLEDs,
+			LEDIndexes:  "0-14, clockwise",
+			Logo:        "Dynatrace logo LEDs, on/off or up to 4 colors",
+		},
+		Syntax: "Parameters are joined with '&' and values with '|'. Send '|' unencoded. Colors are 6-digit hex RRGGBB without '#'. " +
+			"Whatever order they are sent in, each ring applies init, then bg, then segments, then whirl and morph.",
+		Parameters: []APIParameter{
+			{
+				Name:        "<ring>_init",
+				Format:      "1",
+				Description: "Turn every LED of the ring off and stop its whirl and morph before applying the other parameters. Send it with every new pattern.",
+				Values:      []string{"1"},
+				Examples:    []string{"top_init=1"},
+			},
+			{
+				Name:        "<ring>_bg",
+				Format:      "RRGGBB",
+				Description: "Background color for the LEDs no segment covers; morph fades to it.",
+				Examples:    []string{"top_bg=000033", "bottom_bg=000000"},
+			},
+			{
+				Name:        "<ring>",
+				Format:      "START|COUNT|RRGGBB[|START|COUNT|RRGGBB...]",
+				Description: "Segments of COUNT LEDs from LED START in a color. Segments running past LED 14 wrap around to LED 0; later segments draw over earlier ones.",
+				Range:       &APIRange{Min: 0, Max: RingLEDs - 1, Unit: "LED index (START)"},
+				Examples:    []string{"top=0|15|FF0000", "top=0|8|FF0000|8|7|00FF00", "bottom=13|4|0000FF"},
+			},
+			{
+				Name:        "<ring>_whirl",
+				Format:      "MS[|ccw]",
+				Description: "Rotate the ring by one LED every MS milliseconds, clockwise unless '|ccw' is added. 0 stops it; one turn takes 15×MS.",
+				Range:       &APIRange{Min: 0, Max: MaxWhirlMs, Unit: "ms per LED"},
+				Examples:    []string{"top_whirl=200", "bottom_whirl=300|ccw"},
+			},
+			{
+				Name:        "<ring>_morph",
+				Format:      "TICKS|SPEED",
+				Description: "Pulse between the segment colors and the background: hold for TICKS (about 150 per second, ms = TICKS × 6.67), then fade out and in at SPEED 1-10 (fade ms ≈ 3333 / SPEED).",
+				Range:       &APIRange{Min: 1, Max: 10, Unit: "SPEED"},
+				Examples:    []string{"top_morph=150|10", "bottom_morph=1000|1"},
+			},
+			{
+				Name:        "dim",
+				Format:      "LEVEL",
+				Description: "Brightness of all LEDs. 0 is off; 60-150 suits most rooms.",
+				Range:       &APIRange{Min: 0, Max: 255},
+				Examples:    []string{"dim=128"},
+			},
+			{
+				Name:        "logo",
+				Format:      "on|off or RRGGBB[|RRGGBB|RRGGBB|RRGGBB]",
+				Description: "Turn the logo on or off. Firmware with colored logos also takes up to 4 colors, one per logo LED.",
+				Values:      []string{"on", "off"},
+				Examples:    []string{"logo=on", "logo=FF0000|008000|FF0000|008000"},
+			},
+		},
+		Examples: []APIExample{
+			{Query: "top_init=1&top=0|15|FF0000&logo=on&dim=100", Description: "Top ring red at about 40% brightness with the logo on"},
+			{Query: "top_init=1&top=0|8|FF0000|8|7|00FF00&top_bg=000000", Description: "Top ring half red, half green"},
+			{Query: "top_init=1&top=0|5|FF0000|5|5|00FF00|10|5|0000FF&top_whirl=200", Description: "Three colors rotating clockwise"},
+			{Query: "top_init=1&top=0|15|FF00FF&top_bg=000000&top_morph=100|10", Description: "Top ring pulsing purple"},
+			{Query: "top_init=1&top=0|8|FF0000&top_whirl=300&bottom_init=1&bottom=0|8|0000FF&bottom_whirl=300|ccw", Description: "Rings rotating in opposite directions"},
+		},
+		Notes: []string{
+			"The firmware answers 200 with 'OK' and silently ignores parameters it does not know, so check values before sending.",
+			"A query without parameters returns the UFO's status document.",
+			"Parameters for a ring that are not sent leave that ring as it is.",
+		},
+	}
+}
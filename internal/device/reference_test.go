@@ -0,0 +1,38 @@
+package device
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestReference(t *testing.T) {
+	ref := Reference()
+
+	if _, err := json.Marshal(ref); err != nil {
+		t.Fatalf("marshalling reference: %v", err)
+	}
+	described := map[string]bool{}
+	for _, param := range ref.Parameters {
+		described[param.Name] = true
+	}
+	for _, name := range []string{"<ring>", "<ring>_init", "<ring>_bg", "<ring>_whirl", "<ring>_morph", "dim", "logo"} {
+		if !described[name] {
+			t.Errorf("reference does not describe %s", name)
+		}
+	}
+
+	// Every example must be a query the UFO accepts
+	sim := NewSimulator()
+	for _, param := range ref.Parameters {
+		for _, example := range param.Examples {
+			if err := sim.Apply(example); err != nil {
+				t.Errorf("%s example %q rejected: %v", param.Name, example, err)
+			}
+		}
+	}
+	for _, example := range ref.Examples {
+		if err := sim.Apply(example.Query); err != nil {
+			t.Errorf("example %q rejected: %v", example.Query, err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package device
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// maxReplyLength limits how much of a firmware reply is quoted in messages
+const maxReplyLength = 200
+
+// errorSignatures are how firmware builds start a reply to a write they
+// could not apply, even though they answer with status 200
+var errorSignatures = []string{
+	"error",
+	"err:",
+	"invalid",
+	"unknown",
+	"fail",
+	"bad request",
+	"not found",
+	"not supported",
+	"exception",
+}
+
+// ReplyError is a firmware reply to a write that reports a failure despite
+// a 200 status
+type ReplyError struct {
+	Message string // the firmware's message
+}
+
+func (e *ReplyError) Error() string {
+	return "UFO reported an error: " + e.Message
+}
+
+// CheckReply inspects the body the UFO answered a write with. It returns a
+// *ReplyError when the body matches a known firmware error, and a warning
+// when the body is not a recognized acknowledgement such as "OK" or a JSON
+// status document.
+func CheckReply(body string) (warning string, err error) {
+	reply := strings.TrimSpace(body)
+	lower := strings.ToLower(reply)
+	if lower == "" || lower == "ok" || strings.HasPrefix(lower, "ok:") || strings.HasPrefix(lower, "ok ") {
+		return "", nil
+	}
+
+	if strings.HasPrefix(reply, "{") {
+		var doc map[string]interface{}
+		if json.Unmarshal([]byte(reply), &doc) == nil {
+			if message := replyErrorMessage(doc); message != "" {
+				return "", &ReplyError{Message: truncateReply(message)}
+			}
+			return "", nil
+		}
+	}
+
+	for _, signature := range errorSignatures {
+		if strings.HasPrefix(lower, signature) {
+			return "", &ReplyError{Message: truncateReply(reply)}
+		}
+	}
+	return "unexpected reply from UFO: " + truncateReply(reply), nil
+}
+
+// replyErrorMessage returns the error reported in a JSON reply, if any
+func replyErrorMessage(doc map[string]interface{}) string {
+	if message, ok := doc["error"].(string); ok && message != "" {
+		return message
+	}
+	if status, _ := doc["status"].(string); strings.EqualFold(status, "error") {
+		if message, ok := doc["message"].(string); ok && message != "" {
+			return message
+		}
+		return "status error"
+	}
+	return ""
+}
+
+// truncateReply shortens a reply for quoting in messages
+func truncateReply(reply string) string {
+	if len(reply) > maxReplyLength {
+		return reply[:maxReplyLength] + "..."
+	}
+	return reply
+}
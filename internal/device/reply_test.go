@@ -0,0 +1,47 @@
+package device
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckReply(t *testing.T) {
+	tests := []struct {
+		body    string
+		warning bool
+		err     string
+	}{
+		{"OK", false, ""},
+		{"ok\n", false, ""},
+		{"", false, ""},
+		{"OK: top=0|15|FF0000", false, ""},
+		{`{"top":["ff0000"],"dim":128}`, false, ""},
+		{"ERROR: invalid color", false, "ERROR: invalid color"},
+		{"Invalid parameter top_whirl", false, "Invalid parameter top_whirl"},
+		{"unknown command", false, "unknown command"},
+		{`{"error":"segment out of range"}`, false, "segment out of range"},
+		{`{"status":"error","message":"busy"}`, false, "busy"},
+		{"<html>captive portal</html>", true, ""},
+		{"done", true, ""},
+	}
+	for _, tt := range tests {
+		warning, err := CheckReply(tt.body)
+		if tt.err == "" && err != nil {
+			t.Errorf("CheckReply(%q) unexpected error: %v", tt.body, err)
+		}
+		if tt.err != "" {
+			var replyErr *ReplyError
+			if !errors.As(err, &replyErr) || replyErr.Message != tt.err {
+				t.Errorf("CheckReply(%q) error = %v, want message %q", tt.body, err, tt.err)
+			}
+		}
+		if (warning != "") != tt.warning {
+			t.Errorf("CheckReply(%q) warning = %q, want warning %v", tt.body, warning, tt.warning)
+		}
+	}
+
+	if warning, _ := CheckReply(strings.Repeat("x", 500)); len(warning) > maxReplyLength+50 {
+		t.Errorf("long replies should be truncated, got %d characters", len(warning))
+	}
+}
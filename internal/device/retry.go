@@ -0,0 +1,278 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrDeviceOffline is returned without contacting the UFO while the circuit
+// breaker has it marked offline
+var ErrDeviceOffline = errors.New("UFO is offline")
+
+// RetryPolicy controls how failed requests to the UFO are retried and when
+// the UFO is considered offline
+type RetryPolicy struct {
+	MaxAttempts   int           // attempts per request, including the first; 1 disables retries
+	BaseDelay     time.Duration // delay before the first retry, doubled for each further retry
+	MaxDelay      time.Duration // upper bound on a single retry delay
+	OfflineAfter  int           // consecutive failed requests before the UFO is marked offline; 0 disables the breaker
+	ProbeInterval time.Duration // while offline, how often a request is let through to check the UFO
+}
+
+// DefaultRetryPolicy returns the retry policy used by NewClient
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:   3,
+		BaseDelay:     100 * time.Millisecond,
+		MaxDelay:      2 * time.Second,
+		OfflineAfter:  5,
+		ProbeInterval: 10 * time.Second,
+	}
+}
+
+// StatusError is returned when the UFO answers with a non-200 status
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("UFO returned status %d: %s", e.Code, e.Body)
+}
+
+// nonIdempotentParams restart an animation on the UFO, so repeating a
+// request that may already have been applied causes a visible jump
+var nonIdempotentParams = []string{"_whirl=", "_morph="}
+
+// isIdempotent reports whether a query can safely be sent twice
+func isIdempotent(query string) bool {
+	for _, param := range nonIdempotentParams {
+		if strings.Contains(query, param) {
+			return false
+		}
+	}
+	return true
+}
+
+// retryable reports whether a failed request should be retried. Requests
+// that never reached the UFO are always retried; requests that may have
+// been applied are only retried when idempotent.
+func retryable(err error, idempotent bool) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return idempotent && (statusErr.Code >= 500 || statusErr.Code == 429)
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return idempotent
+	}
+	return false
+}
+
+// backoff returns the delay before retry number attempt (starting at 1),
+// with jitter so concurrent callers do not retry in lockstep
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay > p.MaxDelay || delay <= 0 {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// breaker tracks consecutive failures and the UFO's availability
+type breaker struct {
+	mu        sync.Mutex
+	failures  int
+	offline   bool
+	lastProbe time.Time
+	lastErr   error
+	lastErrAt time.Time
+	lastOKAt  time.Time
+	onChange  func(online bool, err error)
+}
+
+// allow reports whether a request may be sent, and whether it is a probe
+// of an offline UFO
+func (b *breaker) allow(policy RetryPolicy, now time.Time) (allowed, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.offline {
+		return true, false
+	}
+	if now.Sub(b.lastProbe) < policy.ProbeInterval {
+		return false, false
+	}
+	b.lastProbe = now
+	return true, true
+}
+
+// result records the outcome of a request and reports availability changes
+func (b *breaker) result(policy RetryPolicy, err error, now time.Time) {
+	b.mu.Lock()
+	var changed bool
+	if err == nil {
+		b.failures = 0
+		b.lastOKAt = now
+		changed = b.offline
+		b.offline = false
+	} else {
+		b.failures++
+		b.lastErr = err
+		b.lastErrAt = now
+		if policy.OfflineAfter > 0 && !b.offline && b.failures >= policy.OfflineAfter {
+			b.offline = true
+			b.lastProbe = now
+			changed = true
+		}
+	}
+	online := !b.offline
+	onChange := b.onChange
+	b.mu.Unlock()
+
+	if changed && onChange != nil {
+		onChange(online, err)
+	}
+}
+
+// SetRetryPolicy replaces the client's retry policy
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	c.mu.Lock()
+	c.retry = policy
+	c.mu.Unlock()
+}
+
+// OnAvailabilityChange registers a function called when the circuit breaker
+// marks the UFO offline, or when it answers again. err is the failure that
+// took the UFO offline, nil when it comes back.
+func (c *Client) OnAvailabilityChange(fn func(online bool, err error)) {
+	c.breaker.mu.Lock()
+	c.breaker.onChange = fn
+	c.breaker.mu.Unlock()
+}
+
+// Online reports whether the UFO is considered reachable
+func (c *Client) Online() bool {
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+	return !c.breaker.offline
+}
+
+// Health describes the UFO's availability as seen by the circuit breaker
+type Health struct {
+	Online              bool      // false while the breaker has the UFO marked offline
+	ConsecutiveFailures int       // failed requests since the last success
+	LastError           string    // most recent failed request, empty if none has failed
+	LastErrorAt         time.Time // when LastError happened
+	LastSuccessAt       time.Time // when the UFO last answered, zero if it never has
+}
+
+// Health returns the UFO's availability, last successful contact and most
+// recent request failure
+func (c *Client) Health() Health {
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+
+	health := Health{
+		Online:              !c.breaker.offline,
+		ConsecutiveFailures: c.breaker.failures,
+		LastErrorAt:         c.breaker.lastErrAt,
+		LastSuccessAt:       c.breaker.lastOKAt,
+	}
+	if c.breaker.lastErr != nil {
+		health.LastError = c.breaker.lastErr.Error()
+	}
+	return health
+}
+
+// sendWithRetry sends a query, retrying transient failures per the policy
+func (c *Client) sendWithRetry(ctx context.Context, query string) (string, error) {
+	c.mu.Lock()
+	policy := c.retry
+	c.mu.Unlock()
+
+	allowed, probe := c.breaker.allow(policy, time.Now())
+	if !allowed {
+		slog.DebugContext(ctx, "UFO request skipped, device offline", "query", query)
+		c.reportCommand(ctx, Command{Query: query, Err: ErrDeviceOffline})
+		return "", ErrDeviceOffline
+	}
+	attempts := policy.MaxAttempts
+	if probe {
+		attempts = 1
+	}
+	idempotent := isIdempotent(query)
+
+	var resp string
+	var err error
+	began := time.Now()
+	attempt := 1
+	for ; ; attempt++ {
+		leave := func() {}
+		if query != "" && !c.inTransaction(ctx) {
+			if leave, err = c.gate.enter(ctx); err != nil {
+				break
+			}
+		}
+		var release func()
+		if release, err = c.limiter.acquire(ctx); err != nil {
+			leave()
+			break
+		}
+		start := time.Now()
+		resp, err = c.sendRawQuery(ctx, query)
+		elapsed := time.Since(start)
+		c.stats.record(elapsed, err, attempt > 1)
+		release()
+		leave()
+		if err != nil {
+			slog.DebugContext(ctx, "UFO request failed", "query", query, "attempt", attempt, "duration", elapsed, "error", err)
+		} else {
+			slog.DebugContext(ctx, "UFO request", "query", query, "attempt", attempt, "duration", elapsed)
+		}
+
+		if err == nil || attempt >= attempts || ctx.Err() != nil || !retryable(err, idempotent) {
+			break
+		}
+
+		delay := policy.backoff(attempt)
+		slog.WarnContext(ctx, "Retrying UFO request", "query", query, "attempt", attempt, "delay", delay, "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	// A cancelled caller says nothing about the UFO's health
+	if ctx.Err() == nil {
+		c.breaker.result(policy, err, time.Now())
+	}
+	c.reportCommand(ctx, Command{Query: query, Reply: resp, Err: err, Attempts: attempt, Latency: time.Since(began)})
+	return resp, err
+}
@@ -0,0 +1,235 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetry keeps test retries quick
+var fastRetry = RetryPolicy{
+	MaxAttempts:   3,
+	BaseDelay:     time.Millisecond,
+	MaxDelay:      5 * time.Millisecond,
+	OfflineAfter:  2,
+	ProbeInterval: 20 * time.Millisecond,
+}
+
+func TestSendRawQuery_RetriesTransientFailures(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := NewClient()
+	client.SetRetryPolicy(fastRetry)
+
+	resp, err := client.SendRawQuery(context.Background(), "dim=100")
+	if err != nil || resp != "OK" {
+		t.Fatalf("expected success after retries, got %q, %v", resp, err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("expected 3 requests, got %d", got)
+	}
+	if stats := client.Stats(); stats.Retries != 2 || stats.Errors != 2 {
+		t.Errorf("expected 2 retries and 2 errors, got %+v", stats)
+	}
+}
+
+func TestSendRawQuery_DoesNotRetryClientErrorsOrAnimations(t *testing.T) {
+	var requests atomic.Int32
+	status := http.StatusBadRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := NewClient()
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	_, err := client.SendRawQuery(context.Background(), "dim=100")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status error, got %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected 4xx not retried, got %d requests", got)
+	}
+
+	// A whirl may already be running on the UFO, so a 5xx is not retried
+	status = http.StatusInternalServerError
+	requests.Store(0)
+	client.SendRawQuery(context.Background(), "top_init=1&top_whirl=300")
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected non-idempotent query not retried, got %d requests", got)
+	}
+}
+
+func TestSendRawQuery_RetriesConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := NewClient()
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	// Refused connections never reached the UFO, so even animations are retried
+	if _, err := client.SendRawQuery(context.Background(), "top_whirl=300"); err == nil {
+		t.Fatal("expected error from closed server")
+	}
+	if stats := client.Stats(); stats.Requests != 3 {
+		t.Errorf("expected 3 attempts, got %d", stats.Requests)
+	}
+}
+
+func TestSendRawQuery_CircuitBreaker(t *testing.T) {
+	var mu sync.Mutex
+	healthy := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !healthy {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := NewClient()
+	client.SetRetryPolicy(fastRetry)
+	var changes []bool
+	client.OnAvailabilityChange(func(online bool, err error) {
+		changes = append(changes, online)
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.SendRawQuery(context.Background(), "dim=100"); err == nil {
+			t.Fatal("expected failure")
+		}
+	}
+	if client.Online() {
+		t.Fatal("expected UFO offline after consecutive failures")
+	}
+
+	requests := client.Stats().Requests
+	if _, err := client.SendRawQuery(context.Background(), "dim=100"); !errors.Is(err, ErrDeviceOffline) {
+		t.Fatalf("expected ErrDeviceOffline, got %v", err)
+	}
+	if client.Stats().Requests != requests {
+		t.Error("expected no request sent while offline")
+	}
+
+	mu.Lock()
+	healthy = true
+	mu.Unlock()
+	time.Sleep(fastRetry.ProbeInterval)
+
+	if _, err := client.SendRawQuery(context.Background(), "dim=100"); err != nil {
+		t.Fatalf("expected probe to succeed, got %v", err)
+	}
+	if !client.Online() {
+		t.Error("expected UFO back online")
+	}
+	if len(changes) != 2 || changes[0] || !changes[1] {
+		t.Errorf("expected offline then online notifications, got %v", changes)
+	}
+}
+
+func TestSendRawQuery_CancelledContextDoesNotTripBreaker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := NewClient()
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, OfflineAfter: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.SendRawQuery(ctx, "dim=100"); err == nil {
+		t.Fatal("expected cancellation error")
+	}
+	if !client.Online() {
+		t.Error("expected a cancelled request not to mark the UFO offline")
+	}
+	if stats := client.Stats(); stats.Requests != 1 {
+		t.Errorf("expected no retries after cancellation, got %d requests", stats.Requests)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		for i := 0; i < 20; i++ {
+			delay := policy.backoff(attempt)
+			if delay < max/2 || delay > max {
+				t.Errorf("attempt %d: delay %s outside [%s, %s]", attempt, delay, max/2, max)
+			}
+		}
+	}
+}
+
+func TestClient_Health(t *testing.T) {
+	var mu sync.Mutex
+	healthy := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !healthy {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := NewClient()
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 1, OfflineAfter: 2, ProbeInterval: time.Hour})
+	if health := client.Health(); !health.Online || health.LastError != "" || !health.LastSuccessAt.IsZero() {
+		t.Errorf("expected a fresh client to be online without errors or contact, got %+v", health)
+	}
+
+	client.SendRawQuery(context.Background(), "dim=100")
+	client.SendRawQuery(context.Background(), "dim=100")
+	health := client.Health()
+	if health.Online || health.ConsecutiveFailures != 2 {
+		t.Errorf("expected offline after 2 failures, got %+v", health)
+	}
+	if !strings.Contains(health.LastError, "status 500") || health.LastErrorAt.IsZero() {
+		t.Errorf("expected the last error to be recorded, got %+v", health)
+	}
+
+	// The last error is kept after the UFO recovers
+	mu.Lock()
+	healthy = true
+	mu.Unlock()
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 1, OfflineAfter: 2})
+	if _, err := client.SendRawQuery(context.Background(), "dim=100"); err != nil {
+		t.Fatalf("expected probe to succeed, got %v", err)
+	}
+	health = client.Health()
+	if !health.Online || health.ConsecutiveFailures != 0 || health.LastError == "" {
+		t.Errorf("expected online with the previous error kept, got %+v", health)
+	}
+	if health.LastSuccessAt.Before(health.LastErrorAt) {
+		t.Errorf("expected the last success after the last error, got %+v", health)
+	}
+}
@@ -0,0 +1,85 @@
+package device
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// FullRingBrightness is the brightness percentage that leaves a ring's
+// colors as they are
+const FullRingBrightness = 100
+
+// SetRingBrightness makes the client scale the colors of each ring to the
+// percentages brightness reports before sending them, so one ring can be
+// dimmer than the other; nil sends colors as given
+func (c *Client) SetRingBrightness(brightness func() (top, bottom int)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ringBrightness = brightness
+}
+
+// RingBrightness returns what reports the rings' brightness, or nil
+func (c *Client) RingBrightness() func() (top, bottom int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ringBrightness
+}
+
+// ScaleRings returns query with the segment and background colors of the
+// top ring scaled to top percent of their brightness and those of the
+// bottom ring to bottom percent. Percentages outside 0-99 leave the ring as
+// it is.
+func ScaleRings(query string, top, bottom int) string {
+	if !dims(top) && !dims(bottom) {
+		return query
+	}
+	parts := strings.Split(query, "&")
+	for i, part := range parts {
+		name, value, found := strings.Cut(part, "=")
+		if !found {
+			continue
+		}
+		ring, isBackground := strings.CutSuffix(name, "_bg")
+		percent := top
+		switch ring {
+		case "top":
+		case "bottom":
+			percent = bottom
+		default:
+			continue
+		}
+		if !dims(percent) {
+			continue
+		}
+		if isBackground {
+			value = scaleColor(value, percent)
+		} else {
+			fields := strings.Split(value, "|")
+			for j := 2; j < len(fields); j += 3 {
+				fields[j] = scaleColor(fields[j], percent)
+			}
+			value = strings.Join(fields, "|")
+		}
+		parts[i] = name + "=" + value
+	}
+	return strings.Join(parts, "&")
+}
+
+// dims reports whether a ring brightness percentage changes colors
+func dims(percent int) bool {
+	return percent >= 0 && percent < FullRingBrightness
+}
+
+// scaleColor scales each channel of a 6-digit hex color to percent of its
+// value. Anything else is returned unchanged.
+func scaleColor(hex string, percent int) string {
+	if !isHexColor(hex) {
+		return hex
+	}
+	rgb := parseRGB(hex)
+	for i := range rgb {
+		rgb[i] = int(math.Round(float64(rgb[i]) * float64(percent) / FullRingBrightness))
+	}
+	return fmt.Sprintf("%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
@@ -0,0 +1,45 @@
+package device
+
+import (
+	"context"
+	"testing"
+)
+
+func TestScaleRings(t *testing.T) {
+	got := ScaleRings("top_init=1&top=0|5|FF0000|5|3|808080&top_bg=ffffff&bottom_init=1&bottom=0|15|ff8040&top_whirl=200&logo=on|ffffff", 50, 25)
+	want := "top_init=1&top=0|5|800000|5|3|404040&top_bg=808080&bottom_init=1&bottom=0|15|402010&top_whirl=200&logo=on|ffffff"
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	// Full brightness leaves a ring alone
+	query := "top=0|5|ff0000&bottom=0|5|00ff00"
+	if got := ScaleRings(query, FullRingBrightness, 10); got != "top=0|5|ff0000&bottom=0|5|001a00" {
+		t.Errorf("expected only the bottom ring scaled, got %s", got)
+	}
+	if got := ScaleRings(query, FullRingBrightness, FullRingBrightness); got != query {
+		t.Errorf("expected full brightness to change nothing, got %s", got)
+	}
+}
+
+func TestClient_RingBrightness(t *testing.T) {
+	simulator := NewSimulator()
+	client := NewSimulatedClient(simulator)
+	client.SetRingBrightness(func() (int, int) { return FullRingBrightness, 50 })
+	ctx := context.Background()
+
+	// Passthrough skips color correction, not the ring's brightness
+	client.SendRawQuery(WithPassthrough(ctx), "top_init=1&top_bg=808080&bottom_init=1&bottom_bg=808080")
+	if got := simulator.rings["top"].LEDs[0]; got != "808080" {
+		t.Errorf("expected the top ring unscaled, got %s", got)
+	}
+	if got := simulator.rings["bottom"].LEDs[0]; got != "404040" {
+		t.Errorf("expected the bottom ring at half brightness, got %s", got)
+	}
+
+	client.SetRingBrightness(nil)
+	client.SendRawQuery(ctx, "bottom_init=1&bottom_bg=808080")
+	if got := simulator.rings["bottom"].LEDs[0]; got != "808080" {
+		t.Errorf("expected colors unscaled without ring brightness, got %s", got)
+	}
+}
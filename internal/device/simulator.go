@@ -0,0 +1,417 @@
+package device
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SimulatorAddress is the address reported for a simulated UFO
+const SimulatorAddress = "simulator"
+
+// Simulator is an in-memory virtual UFO. It serves the firmware's /api and
+// /info endpoints, keeps its own LED state and answers with the documents
+// the firmware would, so the server can run without hardware for demos,
+// acceptance tests and effect development.
+type Simulator struct {
+	mu       sync.Mutex
+	rings    map[string]*simulatedRing
+	dim      int
+	logoOn   bool
+	started  time.Time
+	requests int
+
+	firmware string // installed firmware version
+	offered  string // version the update server offers, empty for none
+	progress int    // percent of the running update, -1 when none runs
+}
+
+// simulatedRing is the state of one ring of the virtual UFO
+type simulatedRing struct {
+	LEDs       [RingLEDs]string
+	Background string // the color morph fades to
+	WhirlMs    int
+	CCW        bool
+	Morph      string
+}
+
+// ringQuery collects the parameters of one ring in a query, which the
+// firmware applies in a fixed order whatever order they are sent in
+type ringQuery struct {
+	init       bool
+	background string
+	segments   string
+	whirl      string
+	morph      string
+}
+
+// NewSimulator creates a virtual UFO with both rings off at full brightness
+func NewSimulator() *Simulator {
+	s := &Simulator{
+		rings:    map[string]*simulatedRing{"top": {}, "bottom": {}},
+		dim:      255,
+		started:  time.Now(),
+		firmware: "simulated",
+		progress: -1,
+	}
+	for _, ring := range s.rings {
+		ring.clear()
+	}
+	return s
+}
+
+// clear turns every LED of the ring off and stops its animations
+func (r *simulatedRing) clear() {
+	for i := range r.LEDs {
+		r.LEDs[i] = "000000"
+	}
+	r.Background = "000000"
+	r.WhirlMs = 0
+	r.CCW = false
+	r.Morph = ""
+}
+
+// ServeHTTP answers requests as the UFO firmware would
+func (s *Simulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/api":
+		if r.URL.RawQuery == "" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(s.statusJSON())
+			return
+		}
+		if err := s.Apply(r.URL.RawQuery); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("ok"))
+	case "/info":
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(s.infoJSON())
+	case "/firmware":
+		if r.URL.Query().Get("update") == "1" {
+			if err := s.startUpdate(); err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			w.Write([]byte("ok"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(s.firmwareJSON())
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Apply changes the virtual UFO's state with an /api query. An invalid
+// query is rejected as a whole, leaving the state unchanged.
+func (s *Simulator) Apply(query string) error {
+	rings, dim, logo, err := parseAPIQuery(query)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Work on copies so a bad parameter leaves the UFO as it was
+	updated := map[string]simulatedRing{}
+	for name, q := range rings {
+		ring := *s.rings[name]
+		if err := ring.apply(q); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		updated[name] = ring
+	}
+	for name, ring := range updated {
+		*s.rings[name] = ring
+	}
+	if dim >= 0 {
+		s.dim = dim
+	}
+	if logo != "" {
+		s.logoOn = logo == "on"
+	}
+	s.requests++
+	return nil
+}
+
+// parseAPIQuery splits an /api query into the parameters of each ring, the
+// brightness (-1 when not set) and the logo ("on", "off" or "" when not set)
+func parseAPIQuery(query string) (map[string]*ringQuery, int, string, error) {
+	rings := map[string]*ringQuery{}
+	dim := -1
+	logo := ""
+	for _, part := range strings.Split(strings.TrimLeft(query, "?/"), "&") {
+		if part == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(part, "=")
+		switch name {
+		case "dim":
+			level, err := strconv.Atoi(value)
+			if err != nil || level < 0 || level > 255 {
+				return nil, 0, "", fmt.Errorf("dim must be between 0 and 255, got %q", value)
+			}
+			dim = level
+			continue
+		case "logo":
+			on, err := parseLogo(value)
+			if err != nil {
+				return nil, 0, "", err
+			}
+			logo = "off"
+			if on {
+				logo = "on"
+			}
+			continue
+		}
+
+		ring, param, _ := strings.Cut(name, "_")
+		if ring != "top" && ring != "bottom" {
+			continue // the firmware ignores parameters it does not know
+		}
+		q := rings[ring]
+		if q == nil {
+			q = &ringQuery{}
+			rings[ring] = q
+		}
+		switch param {
+		case "":
+			q.segments = value
+		case "init":
+			q.init = true
+		case "bg":
+			if !isHexColor(value) {
+				return nil, 0, "", fmt.Errorf("%s: invalid color %q", name, value)
+			}
+			q.background = strings.ToLower(value)
+		case "whirl":
+			q.whirl = value
+		case "morph":
+			q.morph = value
+		}
+	}
+
+	return rings, dim, logo, nil
+}
+
+// apply changes the ring: init clears it, the background fills it, and
+// segments of "start|count|color" triples are drawn over it, wrapping
+// around the ring
+func (r *simulatedRing) apply(q *ringQuery) error {
+	if q.init {
+		r.clear()
+	}
+	if q.background != "" {
+		for i := range r.LEDs {
+			r.LEDs[i] = q.background
+		}
+		r.Background = q.background
+	}
+	if q.segments != "" {
+		parts := strings.Split(q.segments, "|")
+		if len(parts)%3 != 0 {
+			return fmt.Errorf("segments must be start|count|color triples, got %q", q.segments)
+		}
+		for i := 0; i < len(parts); i += 3 {
+			start, err1 := strconv.Atoi(parts[i])
+			count, err2 := strconv.Atoi(parts[i+1])
+			color := parts[i+2]
+			if err1 != nil || err2 != nil || start < 0 || start >= RingLEDs || count < 0 || !isHexColor(color) {
+				return fmt.Errorf("invalid segment %q", strings.Join(parts[i:i+3], "|"))
+			}
+			for n := 0; n < count && n < RingLEDs; n++ {
+				r.LEDs[(start+n)%RingLEDs] = strings.ToLower(color)
+			}
+		}
+	}
+	if q.whirl != "" {
+		speed, direction, _ := strings.Cut(q.whirl, "|")
+		ms, err := strconv.Atoi(speed)
+		if err != nil || ms < 0 || ms > MaxWhirlMs {
+			return fmt.Errorf("whirl must be between 0 and %d, got %q", MaxWhirlMs, q.whirl)
+		}
+		r.WhirlMs = ms
+		r.CCW = direction == "ccw"
+	}
+	if q.morph != "" {
+		if ConvertMorphFromDevice(q.morph) == nil {
+			return fmt.Errorf("morph must be ticks|speed, got %q", q.morph)
+		}
+		r.Morph = q.morph
+	}
+	return nil
+}
+
+// parseLogo reads a logo value, "on", "off" or up to 4 colors, and reports
+// whether it lights the logo
+func parseLogo(value string) (bool, error) {
+	if value == "on" || value == "off" {
+		return value == "on", nil
+	}
+	colors := strings.Split(value, "|")
+	if len(colors) > 4 {
+		return false, fmt.Errorf("logo takes at most 4 colors, got %q", value)
+	}
+	on := false
+	for _, c := range colors {
+		if !isHexColor(c) {
+			return false, fmt.Errorf("logo must be 'on', 'off' or colors, got %q", value)
+		}
+		on = on || c != "000000"
+	}
+	return on, nil
+}
+
+// isHexColor reports whether value is a six digit hex color
+func isHexColor(value string) bool {
+	if len(value) != 6 {
+		return false
+	}
+	_, err := strconv.ParseUint(value, 16, 32)
+	return err == nil
+}
+
+// statusJSON is the status document served on /api
+func (s *Simulator) statusJSON() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logo := "off"
+	if s.logoOn {
+		logo = "on"
+	}
+	doc := map[string]interface{}{
+		"top":    s.rings["top"].LEDs[:],
+		"bottom": s.rings["bottom"].LEDs[:],
+		"dim":    s.dim,
+		"logo":   logo,
+	}
+	for name, ring := range s.rings {
+		doc[name+"_whirl"] = ring.WhirlMs
+		if ring.Morph != "" {
+			doc[name+"_morph"] = ring.Morph
+		}
+	}
+	data, _ := json.Marshal(doc)
+	return data
+}
+
+// infoJSON is the info document served on /info
+func (s *Simulator) infoJSON() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"version":   s.firmware,
+		"ip":        "127.0.0.1",
+		"ssid":      SimulatorAddress,
+		"hostname":  "ufo-" + SimulatorAddress,
+		"uptime":    int64(time.Since(s.started).Seconds()),
+		"time":      time.Now().Unix(),
+		"ntp":       true,
+		"requests":  s.requests,
+		"simulated": true,
+	})
+	return data
+}
+
+// Restart makes the virtual UFO come back as from a power cycle: dark, at
+// full brightness, with its uptime starting again
+func (s *Simulator) Restart() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ring := range s.rings {
+		ring.clear()
+	}
+	s.dim = 255
+	s.logoOn = false
+	s.started = time.Now()
+}
+
+// OfferFirmware makes the virtual UFO's update server offer version
+func (s *Simulator) OfferFirmware(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offered = version
+}
+
+// startUpdate begins installing the offered firmware
+func (s *Simulator) startUpdate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.progress >= 0 {
+		return fmt.Errorf("an update is already running")
+	}
+	if s.offered == "" || s.offered == s.firmware {
+		return fmt.Errorf("no update available")
+	}
+	s.progress = 0
+	return nil
+}
+
+// firmwareJSON is the document served on /firmware. Each read advances a
+// running update by a quarter, so an update finishes after four reads.
+func (s *Simulator) firmwareJSON() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc := map[string]interface{}{
+		"version":   s.firmware,
+		"available": s.offered,
+		"state":     FirmwareIdle,
+	}
+	if s.progress >= 0 {
+		s.progress += 25
+		doc["progress"] = s.progress
+		switch {
+		case s.progress < 50:
+			doc["state"] = FirmwareDownloading
+		case s.progress < 100:
+			doc["state"] = FirmwareFlashing
+		default:
+			doc["state"] = FirmwareDone
+			s.firmware = s.offered
+			s.progress = -1
+			doc["version"] = s.firmware
+		}
+	}
+	data, _ := json.Marshal(doc)
+	return data
+}
+
+// handlerTransport sends requests to an in-process handler
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+// NewSimulatedClient creates a client talking to a virtual UFO instead of
+// the network. Retries, rate limiting and statistics work as with a real
+// UFO.
+func NewSimulatedClient(sim *Simulator) *Client {
+	client := NewClient()
+	client.baseURL = "http://" + SimulatorAddress
+	client.httpClient = &http.Client{Transport: handlerTransport{handler: sim}}
+	return client
+}
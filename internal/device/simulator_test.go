@@ -0,0 +1,65 @@
+package device
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSimulator_Apply(t *testing.T) {
+	client := NewSimulatedClient(NewSimulator())
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	ctx := context.Background()
+
+	if _, err := client.SendRawQuery(ctx, "top_init=1&top_bg=0000ff&top=13|4|FF0000&dim=80&logo=on"); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	status, err := client.FetchStatus(ctx)
+	if err != nil {
+		t.Fatalf("failed to fetch status: %v", err)
+	}
+	// The segment wraps around from LED 13 to LED 1
+	for i, want := range map[int]string{0: "ff0000", 1: "ff0000", 2: "0000ff", 12: "0000ff", 13: "ff0000", 14: "ff0000"} {
+		if status.Top[i] != want {
+			t.Errorf("top LED %d: expected %s, got %s", i, want, status.Top[i])
+		}
+	}
+	if status.Bottom[0] != "000000" {
+		t.Errorf("bottom ring should be untouched, got %v", status.Bottom)
+	}
+	if status.Dim == nil || *status.Dim != 80 || status.LogoOn == nil || !*status.LogoOn {
+		t.Errorf("unexpected dim/logo: %+v", status)
+	}
+
+	// Without init, segments are drawn over what is there
+	client.SendRawQuery(ctx, "top=5|1|00FF00")
+	status, _ = client.FetchStatus(ctx)
+	if status.Top[5] != "00ff00" || status.Top[0] != "ff0000" {
+		t.Errorf("segments should be added to the ring: %v", status.Top)
+	}
+
+	// Invalid queries are rejected without changing anything
+	for _, query := range []string{"dim=300", "top=0|15", "top=0|15|red", "top_whirl=9999", "logo=maybe", "bottom_morph=x"} {
+		if _, err := client.SendRawQuery(ctx, query); err == nil || !strings.Contains(err.Error(), "400") {
+			t.Errorf("%s: expected a 400 error, got %v", query, err)
+		}
+	}
+	status, _ = client.FetchStatus(ctx)
+	if *status.Dim != 80 {
+		t.Errorf("rejected query changed the state: dim %d", *status.Dim)
+	}
+}
+
+func TestSimulator_Info(t *testing.T) {
+	client := NewSimulatedClient(NewSimulator())
+	info, err := client.FetchInfo(context.Background())
+	if err != nil {
+		t.Fatalf("failed to fetch info: %v", err)
+	}
+	if info.Firmware != "simulated" || info.UptimeSeconds == nil {
+		t.Errorf("unexpected info: %+v", info)
+	}
+	if client.Address() != SimulatorAddress {
+		t.Errorf("unexpected address %q", client.Address())
+	}
+}
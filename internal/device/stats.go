@@ -0,0 +1,70 @@
+package device
+
+import (
+	"sync"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds, in seconds, of the request latency histogram
+var LatencyBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Stats summarizes the requests a client has sent to the UFO
+type Stats struct {
+	Requests      uint64   // total requests
+	Errors        uint64   // requests that failed
+	Retries       uint64   // requests that were retries of a failed request
+	LatencyCounts []uint64 // cumulative counts per LatencyBuckets entry
+	LatencySum    float64  // total latency in seconds
+}
+
+// requestStats accumulates request statistics
+type requestStats struct {
+	mu            sync.Mutex
+	requests      uint64
+	errors        uint64
+	retries       uint64
+	latencyCounts []uint64
+	latencySum    float64
+}
+
+// record adds a completed request to the statistics
+func (s *requestStats) record(latency time.Duration, err error, retry bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.latencyCounts == nil {
+		s.latencyCounts = make([]uint64, len(LatencyBuckets))
+	}
+
+	s.requests++
+	if err != nil {
+		s.errors++
+	}
+	if retry {
+		s.retries++
+	}
+
+	seconds := latency.Seconds()
+	s.latencySum += seconds
+	for i, bound := range LatencyBuckets {
+		if seconds <= bound {
+			s.latencyCounts[i]++
+		}
+	}
+}
+
+// Stats returns a snapshot of the client's request statistics
+func (c *Client) Stats() Stats {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+
+	counts := make([]uint64, len(LatencyBuckets))
+	copy(counts, c.stats.latencyCounts)
+	return Stats{
+		Requests:      c.stats.requests,
+		Errors:        c.stats.errors,
+		Retries:       c.stats.retries,
+		LatencyCounts: counts,
+		LatencySum:    c.stats.latencySum,
+	}
+}
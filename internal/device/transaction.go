@@ -0,0 +1,154 @@
+package device
+
+import (
+	"context"
+	"sync"
+)
+
+// writeGate orders writes to the UFO around transactions. Plain writes pass
+// side by side, within the concurrency limit, while no transaction runs. A
+// transaction holds new writes back, waits for those in flight, and then has
+// the UFO to itself until it ends, so its queries are applied without
+// another caller's writes in between. Transactions take turns.
+type writeGate struct {
+	mu      sync.Mutex
+	writes  int           // plain writes in flight
+	held    bool          // a transaction holds the gate or waits for writes to finish
+	changed chan struct{} // closed when writes or held change
+}
+
+// wait returns a channel closed at the next change; g.mu must be held
+func (g *writeGate) wait() <-chan struct{} {
+	if g.changed == nil {
+		g.changed = make(chan struct{})
+	}
+	return g.changed
+}
+
+// notify wakes everyone waiting for a change; g.mu must be held
+func (g *writeGate) notify() {
+	if g.changed != nil {
+		close(g.changed)
+		g.changed = nil
+	}
+}
+
+// enter admits a plain write, waiting while a transaction holds the gate,
+// and returns the function to call when the write is done
+func (g *writeGate) enter(ctx context.Context) (func(), error) {
+	for {
+		g.mu.Lock()
+		if !g.held {
+			g.writes++
+			g.mu.Unlock()
+			return g.leave, nil
+		}
+		changed := g.wait()
+		g.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// leave ends a plain write
+func (g *writeGate) leave() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.writes--
+	g.notify()
+}
+
+// lock takes the gate for a transaction once no other transaction holds it
+// and the writes in flight have finished. It returns the function that
+// releases the gate.
+func (g *writeGate) lock(ctx context.Context) (func(), error) {
+	for {
+		g.mu.Lock()
+		if !g.held {
+			g.held = true
+			g.mu.Unlock()
+			break
+		}
+		changed := g.wait()
+		g.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	for {
+		g.mu.Lock()
+		if g.writes == 0 {
+			g.mu.Unlock()
+			return g.unlock, nil
+		}
+		changed := g.wait()
+		g.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			g.unlock()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// unlock releases the gate taken by lock
+func (g *writeGate) unlock() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.held = false
+	g.notify()
+}
+
+// transactionKey marks the context of a running transaction with its client
+type transactionKey struct{}
+
+// inTransaction reports whether ctx belongs to a transaction on c
+func (c *Client) inTransaction(ctx context.Context) bool {
+	owner, _ := ctx.Value(transactionKey{}).(*Client)
+	return owner == c
+}
+
+// Atomically runs fn as a transaction. Writes made with the context fn is
+// given are sent straight away, paced by the rate limit but not queued; all
+// other writes wait until fn returns. Use it for updates made of several
+// queries, or a query and its verification, that must not be interleaved
+// with an animation frame or another tool's write. Calls made inside a
+// transaction join it.
+func (c *Client) Atomically(ctx context.Context, fn func(ctx context.Context) error) error {
+	if c.inTransaction(ctx) {
+		return fn(ctx)
+	}
+	unlock, err := c.gate.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn(context.WithValue(ctx, transactionKey{}, c))
+}
+
+// SendAtomic sends queries in order as one transaction, stopping at the
+// first that fails. It returns the replies of the queries that were sent.
+func (c *Client) SendAtomic(ctx context.Context, queries ...string) ([]string, error) {
+	replies := make([]string, 0, len(queries))
+	err := c.Atomically(ctx, func(ctx context.Context) error {
+		for _, query := range queries {
+			reply, err := c.SendRawQuery(ctx, query)
+			if err != nil {
+				return err
+			}
+			replies = append(replies, reply)
+		}
+		return nil
+	})
+	return replies, err
+}
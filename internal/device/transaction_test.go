@@ -0,0 +1,151 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingServer answers OK to every request and records the queries
+func recordingServer(t *testing.T) (*Client, func() []string) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		w.Write([]byte("OK"))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	return NewClient(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), queries...)
+	}
+}
+
+func TestClient_AtomicallyHoldsBackOtherWrites(t *testing.T) {
+	for _, rate := range []float64{0, 50} {
+		client, sent := recordingServer(t)
+		client.SetRateLimit(rate)
+
+		written := make(chan error, 1)
+		err := client.Atomically(context.Background(), func(ctx context.Context) error {
+			if _, err := client.SendRawQuery(ctx, "top_init=1&top=0|15|FF0000"); err != nil {
+				return err
+			}
+			go func() {
+				_, err := client.SendRawQuery(context.Background(), "dim=10")
+				written <- err
+			}()
+			time.Sleep(50 * time.Millisecond)
+			_, err := client.SendRawQuery(ctx, "bottom_init=1&bottom=0|15|FF0000")
+			return err
+		})
+		if err != nil {
+			t.Fatalf("rate %v: transaction failed: %v", rate, err)
+		}
+		if err := <-written; err != nil {
+			t.Fatalf("rate %v: write failed: %v", rate, err)
+		}
+
+		want := []string{"top_init=1&top=0|15|FF0000", "bottom_init=1&bottom=0|15|FF0000", "dim=10"}
+		if got := sent(); !reflect.DeepEqual(got, want) {
+			t.Errorf("rate %v: expected %v, got %v", rate, want, got)
+		}
+	}
+}
+
+func TestClient_AtomicallyWaitsForWritesInFlight(t *testing.T) {
+	release := make(chan struct{})
+	received := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery == "dim=10" {
+			received <- struct{}{}
+			<-release
+		}
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+	client := NewClient()
+
+	go client.SendRawQuery(context.Background(), "dim=10")
+	<-received
+
+	started := make(chan struct{})
+	go client.Atomically(context.Background(), func(ctx context.Context) error {
+		close(started)
+		return nil
+	})
+
+	select {
+	case <-started:
+		t.Fatal("transaction started while a write was in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("transaction did not start after the write finished")
+	}
+}
+
+func TestClient_AtomicallyCancelledWhileWaiting(t *testing.T) {
+	client, _ := recordingServer(t)
+
+	inside := make(chan struct{})
+	done := make(chan struct{})
+	go client.Atomically(context.Background(), func(ctx context.Context) error {
+		close(inside)
+		<-done
+		return nil
+	})
+	<-inside
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := client.Atomically(ctx, func(ctx context.Context) error {
+		t.Error("second transaction ran while the first held the UFO")
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if _, err := client.SendRawQuery(ctx, "dim=10"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the write to give up waiting, got %v", err)
+	}
+}
+
+func TestClient_SendAtomic(t *testing.T) {
+	client, sent := recordingServer(t)
+
+	replies, err := client.SendAtomic(context.Background(), "logo=on", "dim=50")
+	if err != nil {
+		t.Fatalf("SendAtomic failed: %v", err)
+	}
+	if !reflect.DeepEqual(replies, []string{"OK", "OK"}) {
+		t.Errorf("expected two OK replies, got %v", replies)
+	}
+	if got := sent(); !reflect.DeepEqual(got, []string{"logo=on", "dim=50"}) {
+		t.Errorf("expected queries in order, got %v", got)
+	}
+
+	// Transactions nest instead of waiting for themselves
+	err = client.Atomically(context.Background(), func(ctx context.Context) error {
+		_, err := client.SendAtomic(ctx, "logo=off")
+		return err
+	})
+	if err != nil {
+		t.Errorf("nested transaction failed: %v", err)
+	}
+}
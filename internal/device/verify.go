@@ -0,0 +1,163 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// VerifyDelay is how long Verify gives the firmware to apply a query before
+// reading the UFO's state back
+const VerifyDelay = 300 * time.Millisecond
+
+// Expectation is what the UFO should report after a query
+type Expectation struct {
+	Frame    Frame
+	Rings    []string        // rings the query redraws completely
+	Rotating map[string]bool // redrawn rings left whirling
+	Dim      int             // -1 when the query leaves brightness alone
+	Logo     string          // "on", "off" or "" when the query leaves the logo alone
+}
+
+// Expect returns what the UFO should report after query. A ring is only
+// predicted when the query clears it with init or fills it with a
+// background, since otherwise the result depends on what it showed before.
+func Expect(query string) (Expectation, error) {
+	rings, dim, logo, err := parseAPIQuery(query)
+	if err != nil {
+		return Expectation{}, err
+	}
+
+	expect := Expectation{Rotating: map[string]bool{}, Dim: dim, Logo: logo}
+	for _, name := range []string{"top", "bottom"} {
+		q, ok := rings[name]
+		if !ok || !q.init && q.background == "" {
+			continue
+		}
+		var ring simulatedRing
+		ring.clear()
+		if err := ring.apply(q); err != nil {
+			return Expectation{}, fmt.Errorf("%s: %w", name, err)
+		}
+		*expect.Frame.ring(name) = ring.LEDs
+		expect.Rings = append(expect.Rings, name)
+		if ring.WhirlMs > 0 {
+			expect.Rotating[name] = true
+		}
+	}
+	return expect, nil
+}
+
+// Verification reports whether the UFO reflects a query
+type Verification struct {
+	Method     string   `json:"method"` // "readback" when the UFO reported its state, "resend" otherwise
+	Verified   bool     `json:"verified"`
+	Checked    []string `json:"checked,omitempty"`    // parts compared with the UFO's report
+	Mismatches []string `json:"mismatches,omitempty"` // each difference found
+	Reply      string   `json:"reply,omitempty"`      // reply to the re-sent query
+}
+
+// Compare checks a status read back from the UFO against the expectation.
+// Parts the firmware does not report are skipped, and a whirling ring
+// matches in any rotation.
+func (e Expectation) Compare(status *Status) Verification {
+	v := Verification{Method: "readback"}
+	for _, name := range e.Rings {
+		reported := status.Top
+		if name == "bottom" {
+			reported = status.Bottom
+		}
+		if reported == nil {
+			continue
+		}
+		v.Checked = append(v.Checked, name)
+		want := e.Frame.ring(name)[:]
+		if !ringMatches(want, reported, e.Rotating[name]) {
+			v.Mismatches = append(v.Mismatches, fmt.Sprintf("%s ring shows %s, expected %s",
+				name, strings.Join(reported, ","), strings.Join(want, ",")))
+		}
+	}
+	if e.Dim >= 0 && status.Dim != nil {
+		v.Checked = append(v.Checked, "dim")
+		if *status.Dim != e.Dim {
+			v.Mismatches = append(v.Mismatches, fmt.Sprintf("brightness is %d, expected %d", *status.Dim, e.Dim))
+		}
+	}
+	if e.Logo != "" && status.LogoOn != nil {
+		v.Checked = append(v.Checked, "logo")
+		if *status.LogoOn != (e.Logo == "on") {
+			v.Mismatches = append(v.Mismatches, fmt.Sprintf("logo is not %s", e.Logo))
+		}
+	}
+	v.Verified = len(v.Checked) > 0 && len(v.Mismatches) == 0
+	return v
+}
+
+// ringMatches reports whether a ring read back from the UFO shows the
+// expected colors, in any rotation when the ring is whirling
+func ringMatches(want, got []string, rotating bool) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	shifts := 1
+	if rotating {
+		shifts = len(want)
+	}
+	for shift := 0; shift < shifts; shift++ {
+		matched := true
+		for i := range want {
+			if !strings.EqualFold(want[i], got[(i+shift)%len(got)]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Verify checks that the UFO reflects a query already sent. After delay it
+// reads the UFO's state and compares the parts the query changes. When the
+// firmware does not report those parts, the query is re-sent and the reply
+// checked instead, which catches rejections the first reply did not show.
+func (c *Client) Verify(ctx context.Context, query string, delay time.Duration) (Verification, error) {
+	// The UFO shows the colors as corrected
+	expect, err := Expect(c.correct(ctx, query))
+	if err != nil {
+		return Verification{}, err
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return Verification{}, ctx.Err()
+	case <-timer.C:
+	}
+
+	// A reply that is not a status document leaves status set with an error
+	status, err := c.FetchStatus(ctx)
+	if status == nil {
+		return Verification{}, fmt.Errorf("reading back UFO state: %w", err)
+	}
+	if err == nil {
+		if v := expect.Compare(status); len(v.Checked) > 0 {
+			return v, nil
+		}
+	}
+
+	reply, err := c.SendRawQuery(ctx, query)
+	if err != nil {
+		return Verification{}, fmt.Errorf("re-sending query: %w", err)
+	}
+	v := Verification{Method: "resend", Reply: truncateReply(strings.TrimSpace(reply))}
+	if _, replyErr := CheckReply(reply); replyErr != nil {
+		v.Mismatches = append(v.Mismatches, replyErr.Error())
+	} else {
+		v.Verified = true
+	}
+	return v, nil
+}
@@ -0,0 +1,117 @@
+package device
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestExpect(t *testing.T) {
+	expect, err := Expect("top_init=1&top=0|5|FF0000&top_whirl=200&bottom=3|1|00ff00&dim=90&logo=off")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The bottom ring is drawn over what it showed, so it is not predicted
+	if len(expect.Rings) != 1 || expect.Rings[0] != "top" || !expect.Rotating["top"] {
+		t.Errorf("expected only a rotating top ring, got %+v", expect)
+	}
+	if expect.Frame.Top[4] != "ff0000" || expect.Frame.Top[5] != "000000" || expect.Dim != 90 || expect.Logo != "off" {
+		t.Errorf("unexpected expectation: %+v", expect)
+	}
+
+	if _, err := Expect("top_init=1&top=0|15"); err == nil {
+		t.Error("expected an invalid query to fail")
+	}
+}
+
+func TestExpectation_Compare(t *testing.T) {
+	expect, _ := Expect("top_init=1&top=0|5|ff0000&top_whirl=100")
+	rotated := make([]string, RingLEDs)
+	for i := range rotated {
+		rotated[i] = "000000"
+	}
+	for i := 3; i < 8; i++ {
+		rotated[i] = "FF0000"
+	}
+	if v := expect.Compare(&Status{Top: rotated}); !v.Verified {
+		t.Errorf("expected a rotated whirling ring to match, got %+v", v)
+	}
+
+	still, _ := Expect("top_init=1&top=0|5|ff0000")
+	if v := still.Compare(&Status{Top: rotated}); v.Verified || len(v.Mismatches) != 1 {
+		t.Errorf("expected a still ring read back rotated not to match, got %+v", v)
+	}
+	if v := still.Compare(&Status{}); v.Verified || len(v.Checked) != 0 {
+		t.Errorf("expected nothing to be checked without a report, got %+v", v)
+	}
+}
+
+func TestClient_Verify(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ReadBack", func(t *testing.T) {
+		client := NewSimulatedClient(NewSimulator())
+		query := "top_init=1&top_bg=0000ff&top=13|4|ff0000&dim=80&logo=on"
+		client.SendRawQuery(ctx, query)
+		v, err := client.Verify(ctx, query, 0)
+		if err != nil || !v.Verified || v.Method != "readback" || strings.Join(v.Checked, ",") != "top,dim,logo" {
+			t.Errorf("expected a verified read-back, got %+v (%v)", v, err)
+		}
+	})
+
+	t.Run("SilentRejection", func(t *testing.T) {
+		// The firmware acknowledges writes but keeps its rings dark
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.RawQuery == "" {
+				w.Write([]byte(`{"top":[],"bottom":[],"dim":255}`))
+				return
+			}
+			w.Write([]byte("OK"))
+		}))
+		defer server.Close()
+		t.Setenv("UFO_IP", server.URL[7:])
+
+		v, err := NewClient().Verify(ctx, "top_init=1&top_bg=ff0000&dim=100", 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v.Verified || len(v.Mismatches) != 2 {
+			t.Errorf("expected ring and brightness mismatches, got %+v", v)
+		}
+	})
+
+	t.Run("ResendWithoutStatus", func(t *testing.T) {
+		var mu sync.Mutex
+		reply := "OK"
+		var writes int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			if r.URL.RawQuery == "" {
+				w.Write([]byte("UFO"))
+				return
+			}
+			writes++
+			w.Write([]byte(reply))
+		}))
+		defer server.Close()
+		t.Setenv("UFO_IP", server.URL[7:])
+		client := NewClient()
+
+		v, err := client.Verify(ctx, "effect=rainbow", 0)
+		if err != nil || !v.Verified || v.Method != "resend" || writes != 1 {
+			t.Errorf("expected the query to be re-sent and accepted, got %+v (%v, %d writes)", v, err, writes)
+		}
+
+		mu.Lock()
+		reply = "ERROR: busy"
+		mu.Unlock()
+		v, err = client.Verify(ctx, "top_init=1&top_bg=ff0000", 0)
+		if err != nil || v.Verified || v.Reply != "ERROR: busy" {
+			t.Errorf("expected the re-sent query to be rejected, got %+v (%v)", v, err)
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package devices
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+)
+
+// Device is the metadata people attach to a UFO so they can refer to it as
+// "the kitchen UFO" instead of by IP
+type Device struct {
+	Address  string `json:"address"` // IP address or host name the UFO answers on
+	Nickname string `json:"nickname,omitempty"`
+	Location string `json:"location,omitempty"`
+	Notes    string `json:"notes,omitempty"`
+
+	// ColorCorrection is applied to colors sent to this UFO, nil for none
+	ColorCorrection *device.ColorCorrection `json:"colorCorrection,omitempty"`
+}
+
+// Registry persists device metadata in a JSON file
+type Registry struct {
+	mu      sync.RWMutex
+	devices map[string]*Device // keyed by address
+	file    string
+}
+
+// NewRegistry creates a registry stored in filePath
+func NewRegistry(filePath string) *Registry {
+	return &Registry{
+		devices: make(map[string]*Device),
+		file:    filePath,
+	}
+}
+
+// Load reads the registry file. A missing file is an empty registry.
+func (r *Registry) Load() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := os.ReadFile(r.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			r.devices = make(map[string]*Device)
+			return nil
+		}
+		return fmt.Errorf("reading devices file: %w", err)
+	}
+
+	var devices []*Device
+	if err := json.Unmarshal(data, &devices); err != nil {
+		return fmt.Errorf("parsing devices JSON: %w", err)
+	}
+
+	r.devices = make(map[string]*Device)
+	for _, device := range devices {
+		r.devices[device.Address] = device
+	}
+	return nil
+}
+
+// saveUnsafe writes the registry file; the caller holds the lock
+func (r *Registry) saveUnsafe() error {
+	data, err := json.MarshalIndent(r.listUnsafe(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling devices: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.file), 0755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	return os.WriteFile(r.file, data, 0644)
+}
+
+// List returns copies of all devices ordered by address
+func (r *Registry) List() []Device {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.listUnsafe()
+}
+
+// listUnsafe lists devices without acquiring the lock
+func (r *Registry) listUnsafe() []Device {
+	devices := make([]Device, 0, len(r.devices))
+	for _, device := range r.devices {
+		devices = append(devices, *device)
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Address < devices[j].Address })
+	return devices
+}
+
+// Get returns the metadata stored for an address
+func (r *Registry) Get(address string) (Device, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	device, exists := r.devices[address]
+	if !exists {
+		return Device{}, false
+	}
+	return *device, true
+}
+
+// Set stores the metadata for device.Address, replacing any previous entry.
+// Nicknames must be unique so they can be used to pick a device.
+func (r *Registry) Set(device Device) error {
+	device.Address = strings.TrimSpace(device.Address)
+	device.Nickname = strings.TrimSpace(device.Nickname)
+	if device.Address == "" {
+		return fmt.Errorf("device address cannot be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if device.Nickname != "" {
+		for _, other := range r.devices {
+			if other.Address != device.Address && strings.EqualFold(other.Nickname, device.Nickname) {
+				return fmt.Errorf("nickname '%s' is already used by %s", device.Nickname, other.Address)
+			}
+		}
+	}
+
+	r.devices[device.Address] = &device
+	return r.saveUnsafe()
+}
+
+// Remove deletes the metadata stored for an address
+func (r *Registry) Remove(address string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.devices[address]; !exists {
+		return fmt.Errorf("no device with address '%s'", address)
+	}
+	delete(r.devices, address)
+	return r.saveUnsafe()
+}
+
+// Resolve returns the address for a nickname or address. Nicknames match
+// case-insensitively, and "the kitchen UFO" finds the device nicknamed
+// "kitchen".
+func (r *Registry) Resolve(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, exists := r.devices[name]; exists {
+		return name, true
+	}
+	for _, candidate := range nicknameForms(name) {
+		for _, device := range r.devices {
+			if device.Nickname != "" && strings.EqualFold(device.Nickname, candidate) {
+				return device.Address, true
+			}
+		}
+	}
+	return "", false
+}
+
+// nicknameForms returns name as given, then without a leading "the" and a
+// trailing "UFO"
+func nicknameForms(name string) []string {
+	forms := []string{name}
+	trimmed := name
+	if len(trimmed) > 4 && strings.EqualFold(trimmed[:4], "the ") {
+		trimmed = strings.TrimSpace(trimmed[4:])
+	}
+	if len(trimmed) > 4 && strings.EqualFold(trimmed[len(trimmed)-4:], " ufo") {
+		trimmed = strings.TrimSpace(trimmed[:len(trimmed)-4])
+	}
+	if trimmed != name {
+		forms = append(forms, trimmed)
+	}
+	return forms
+}
@@ -0,0 +1,61 @@
+package devices
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRegistry_SetAndLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "devices.json")
+	registry := NewRegistry(file)
+	if err := registry.Load(); err != nil {
+		t.Fatalf("expected a missing file to load as empty, got %v", err)
+	}
+
+	if err := registry.Set(Device{Address: "192.168.1.72", Nickname: "kitchen", Location: "2nd floor kitchen"}); err != nil {
+		t.Fatalf("failed to set device: %v", err)
+	}
+	if err := registry.Set(Device{Address: "192.168.1.80", Nickname: "Kitchen"}); err == nil {
+		t.Error("expected a duplicate nickname to be rejected")
+	}
+	if err := registry.Set(Device{Nickname: "nowhere"}); err == nil {
+		t.Error("expected an empty address to be rejected")
+	}
+
+	reloaded := NewRegistry(file)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("failed to reload: %v", err)
+	}
+	device, ok := reloaded.Get("192.168.1.72")
+	if !ok || device.Nickname != "kitchen" || device.Location != "2nd floor kitchen" {
+		t.Errorf("unexpected device after reload: %+v (%v)", device, ok)
+	}
+
+	if err := reloaded.Remove("192.168.1.72"); err != nil {
+		t.Fatalf("failed to remove: %v", err)
+	}
+	if len(reloaded.List()) != 0 {
+		t.Errorf("expected no devices, got %v", reloaded.List())
+	}
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	registry := NewRegistry(filepath.Join(t.TempDir(), "devices.json"))
+	registry.Set(Device{Address: "192.168.1.72", Nickname: "kitchen"})
+	registry.Set(Device{Address: "ufo-office.local"})
+
+	for name, want := range map[string]string{
+		"kitchen":          "192.168.1.72",
+		"KITCHEN":          "192.168.1.72",
+		"the kitchen UFO":  "192.168.1.72",
+		"192.168.1.72":     "192.168.1.72",
+		"ufo-office.local": "ufo-office.local",
+	} {
+		if got, ok := registry.Resolve(name); !ok || got != want {
+			t.Errorf("Resolve(%q) = %q, %v; want %q", name, got, ok, want)
+		}
+	}
+	if _, ok := registry.Resolve("garage"); ok {
+		t.Error("expected an unknown nickname not to resolve")
+	}
+}
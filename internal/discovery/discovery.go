@@ -0,0 +1,240 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+)
+
+// Methods lists the supported discovery methods, in order of preference
+// when the same UFO is found more than once
+var Methods = []string{"mdns", "ssdp", "subnet"}
+
+// Device is a UFO found on the local network
+type Device struct {
+	IP       string                 `json:"ip"`
+	Method   string                 `json:"method"`             // discovery method that found it
+	Name     string                 `json:"name,omitempty"`     // mDNS host name or SSDP server header
+	Firmware string                 `json:"firmware,omitempty"` // firmware version, if /info reports one
+	Info     map[string]interface{} `json:"info,omitempty"`     // the firmware's /info document
+}
+
+// candidate is an address that may be a UFO
+type candidate struct {
+	ip     string
+	method string
+	name   string
+}
+
+// Scanner finds UFOs by asking mDNS and SSDP responders and by probing the
+// /api endpoint of every host on the local subnets
+type Scanner struct {
+	Timeout      time.Duration // how long to wait for mDNS and SSDP responses
+	ProbeTimeout time.Duration // timeout of each HTTP probe
+	Concurrency  int           // parallel HTTP probes
+	Port         int           // HTTP port the UFO listens on
+	Subnets      []*net.IPNet  // subnets to probe; the local interfaces' subnets when empty
+
+	client *http.Client
+}
+
+// NewScanner creates a scanner with defaults suited to a home or office LAN
+func NewScanner() *Scanner {
+	return &Scanner{
+		Timeout:      2 * time.Second,
+		ProbeTimeout: 750 * time.Millisecond,
+		Concurrency:  64,
+		Port:         80,
+		client:       &http.Client{},
+	}
+}
+
+// Scan runs the given discovery methods (all of them when empty) and
+// returns the UFOs that answered a probe, ordered by method preference. An
+// error is only returned when nothing was found and a method failed.
+func (s *Scanner) Scan(ctx context.Context, methods []string) ([]Device, error) {
+	if len(methods) == 0 {
+		methods = Methods
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	found := map[string][]candidate{}
+	var errs []error
+	for _, method := range methods {
+		var find func(context.Context) ([]candidate, error)
+		switch method {
+		case "mdns":
+			find = s.mdns
+		case "ssdp":
+			find = s.ssdp
+		case "subnet":
+			find = s.subnet
+		default:
+			return nil, fmt.Errorf("unknown discovery method %q", method)
+		}
+
+		wg.Add(1)
+		go func(method string) {
+			defer wg.Done()
+			candidates, err := find(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			found[method] = candidates
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", method, err))
+			}
+		}(method)
+	}
+	wg.Wait()
+
+	// Keep one candidate per address, preferring the methods that report a name
+	var candidates []candidate
+	seen := map[string]bool{}
+	for _, method := range Methods {
+		for _, c := range found[method] {
+			if !seen[c.ip] {
+				seen[c.ip] = true
+				candidates = append(candidates, c)
+			}
+		}
+	}
+
+	devices := s.probeAll(ctx, candidates)
+	if len(devices) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return devices, nil
+}
+
+// Probe checks which of the given addresses answer as a UFO and returns
+// them in the given order, for choosing among configured UFOs
+func (s *Scanner) Probe(ctx context.Context, addresses []string) []Device {
+	candidates := make([]candidate, len(addresses))
+	for i, address := range addresses {
+		candidates[i] = candidate{ip: address, method: "configured"}
+	}
+	return s.probeAll(ctx, candidates)
+}
+
+// probeAll probes the candidates in parallel, keeping their order
+func (s *Scanner) probeAll(ctx context.Context, candidates []candidate) []Device {
+	results := make([]*Device, len(candidates))
+	limit := make(chan struct{}, max(s.Concurrency, 1))
+	var wg sync.WaitGroup
+	for i, c := range candidates {
+		wg.Add(1)
+		go func(i int, c candidate) {
+			defer wg.Done()
+			select {
+			case limit <- struct{}{}:
+				defer func() { <-limit }()
+			case <-ctx.Done():
+				return
+			}
+			results[i] = s.probe(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	devices := []Device{}
+	for _, result := range results {
+		if result != nil {
+			devices = append(devices, *result)
+		}
+	}
+	return devices
+}
+
+// probe checks whether a candidate serves the UFO status document on /api
+// and collects its firmware information
+func (s *Scanner) probe(ctx context.Context, c candidate) *Device {
+	base := "http://" + net.JoinHostPort(c.ip, strconv.Itoa(s.Port))
+	body, err := s.get(ctx, base+"/api")
+	if err != nil {
+		return nil
+	}
+	status, err := device.ParseStatus(body)
+	if err != nil || (status.Top == nil && status.Bottom == nil && status.Dim == nil && status.LogoOn == nil) {
+		return nil
+	}
+
+	found := &Device{IP: c.ip, Method: c.method, Name: c.name}
+	if body, err := s.get(ctx, base+"/info"); err == nil {
+		var info map[string]interface{}
+		if json.Unmarshal([]byte(body), &info) == nil {
+			found.Info = info
+			if parsed, err := device.ParseInfo(body); err == nil {
+				found.Firmware = parsed.Firmware
+			}
+		}
+	}
+	return found
+}
+
+// get fetches url within the probe timeout
+func (s *Scanner) get(ctx context.Context, url string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return string(body), nil
+}
+
+// deadline returns when listening for multicast responses should stop
+func (s *Scanner) deadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(s.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		return ctxDeadline
+	}
+	return deadline
+}
+
+// collect reads packets from conn until the deadline or until ctx is done,
+// turning each into candidates with parse
+func collect(ctx context.Context, conn *net.UDPConn, deadline time.Time, parse func(packet []byte, from *net.UDPAddr) []candidate) ([]candidate, error) {
+	stop := context.AfterFunc(ctx, func() { conn.SetReadDeadline(time.Now()) })
+	defer stop()
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var candidates []candidate
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return candidates, nil
+			}
+			return candidates, err
+		}
+		candidates = append(candidates, parse(buf[:n], from)...)
+	}
+}
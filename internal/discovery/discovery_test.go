@@ -0,0 +1,158 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestScan_Subnet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api":
+			w.Write([]byte(`{"top":["ff0000"],"dim":128,"logo":"on"}`))
+		case "/info":
+			w.Write([]byte(`{"version":"2.1.0","ufoid":"ufo-1"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	_, subnet, _ := net.ParseCIDR("127.0.0.1/32")
+	scanner := NewScanner()
+	scanner.Port = serverPort(t, server)
+	scanner.Subnets = []*net.IPNet{subnet}
+
+	devices, err := scanner.Scan(context.Background(), []string{"subnet"})
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if len(devices) != 1 {
+		t.Fatalf("expected 1 UFO, got %+v", devices)
+	}
+	if devices[0].IP != "127.0.0.1" || devices[0].Method != "subnet" || devices[0].Firmware != "2.1.0" {
+		t.Errorf("unexpected device %+v", devices[0])
+	}
+	if devices[0].Info["ufoid"] != "ufo-1" {
+		t.Errorf("expected /info to be included, got %v", devices[0].Info)
+	}
+}
+
+func TestScan_IgnoresOtherServers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api" {
+			w.Write([]byte(`{"status":"ok"}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	_, subnet, _ := net.ParseCIDR("127.0.0.1/32")
+	scanner := NewScanner()
+	scanner.Port = serverPort(t, server)
+	scanner.Subnets = []*net.IPNet{subnet}
+
+	devices, err := scanner.Scan(context.Background(), []string{"subnet"})
+	if err != nil || len(devices) != 0 {
+		t.Errorf("expected no UFOs, got %+v (%v)", devices, err)
+	}
+
+	if _, err := scanner.Scan(context.Background(), []string{"bluetooth"}); err == nil {
+		t.Error("expected an error for an unknown method")
+	}
+}
+
+func TestProbe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api" {
+			w.Write([]byte(`{"top":["ff0000"],"dim":128}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	scanner := NewScanner()
+	scanner.Port = serverPort(t, server)
+	scanner.ProbeTimeout = 200 * time.Millisecond
+
+	devices := scanner.Probe(context.Background(), []string{"127.0.0.2", "127.0.0.1"})
+	if len(devices) != 1 || devices[0].IP != "127.0.0.1" || devices[0].Method != "configured" {
+		t.Errorf("expected only 127.0.0.1 to answer, got %+v", devices)
+	}
+}
+
+func TestHosts(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("192.168.1.0/30")
+	hosts, err := Hosts(subnet)
+	if err != nil || len(hosts) != 2 || hosts[0] != "192.168.1.1" || hosts[1] != "192.168.1.2" {
+		t.Errorf("unexpected hosts %v (%v)", hosts, err)
+	}
+
+	_, subnet, _ = net.ParseCIDR("192.168.1.0/24")
+	if hosts, _ := Hosts(subnet); len(hosts) != 254 {
+		t.Errorf("expected 254 hosts, got %d", len(hosts))
+	}
+
+	_, subnet, _ = net.ParseCIDR("10.0.0.0/16")
+	if _, err := Hosts(subnet); err == nil {
+		t.Error("expected a /16 to be rejected")
+	}
+}
+
+func TestParseMDNSResponse(t *testing.T) {
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{Response: true, Authoritative: true})
+	builder.StartAnswers()
+	builder.AResource(dnsmessage.ResourceHeader{
+		Name:  dnsmessage.MustNewName("ufo.local."),
+		Class: dnsmessage.ClassINET,
+	}, dnsmessage.AResource{A: [4]byte{192, 168, 1, 72}})
+	packet, err := builder.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	candidates := parseMDNSResponse(packet)
+	if len(candidates) != 1 || candidates[0].ip != "192.168.1.72" || candidates[0].name != "ufo.local" {
+		t.Errorf("unexpected candidates %+v", candidates)
+	}
+
+	// Queries from other hosts are not answers
+	query, _ := mdnsQuery()
+	if candidates := parseMDNSResponse(query); len(candidates) != 0 {
+		t.Errorf("expected queries to be ignored, got %+v", candidates)
+	}
+}
+
+func TestParseSSDPResponse(t *testing.T) {
+	from := &net.UDPAddr{IP: net.IPv4(192, 168, 1, 9), Port: 1900}
+	packet := "HTTP/1.1 200 OK\r\nCACHE-CONTROL: max-age=1800\r\nLOCATION: http://192.168.1.72:80/description.xml\r\nSERVER: Arduino/1.0 UPNP/1.1 UFO/2.1\r\nST: upnp:rootdevice\r\n\r\n"
+
+	c, ok := parseSSDPResponse([]byte(packet), from)
+	if !ok || c.ip != "192.168.1.72" || c.name != "Arduino/1.0 UPNP/1.1 UFO/2.1" {
+		t.Errorf("unexpected candidate %+v", c)
+	}
+
+	c, ok = parseSSDPResponse([]byte("HTTP/1.1 200 OK\r\n\r\n"), from)
+	if !ok || c.ip != "192.168.1.9" {
+		t.Errorf("expected the sender address without a LOCATION, got %+v", c)
+	}
+}
+
+// serverPort returns the port a test server listens on
+func serverPort(t *testing.T, server *httptest.Server) int {
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, _ := strconv.Atoi(port)
+	return n
+}
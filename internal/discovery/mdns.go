@@ -0,0 +1,77 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// mdnsAddr is the IPv4 mDNS multicast group
+var mdnsAddr = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// mdnsQuestions ask for the UFO's default host name and for web servers,
+// which the UFO firmware advertises
+var mdnsQuestions = []dnsmessage.Question{
+	{Name: dnsmessage.MustNewName("ufo.local."), Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET},
+	{Name: dnsmessage.MustNewName("_http._tcp.local."), Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET},
+}
+
+// mdns sends a one-shot mDNS query from an ephemeral port, so responders
+// answer directly, and collects the addresses in the answers
+func (s *Scanner) mdns(ctx context.Context) ([]candidate, error) {
+	query, err := mdnsQuery()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.WriteToUDP(query, mdnsAddr); err != nil {
+		return nil, err
+	}
+	return collect(ctx, conn, s.deadline(ctx), func(packet []byte, _ *net.UDPAddr) []candidate {
+		return parseMDNSResponse(packet)
+	})
+}
+
+// mdnsQuery builds the query packet for mdnsQuestions
+func mdnsQuery() ([]byte, error) {
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{})
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	for _, question := range mdnsQuestions {
+		if err := builder.Question(question); err != nil {
+			return nil, err
+		}
+	}
+	return builder.Finish()
+}
+
+// parseMDNSResponse returns the A records of an mDNS response, named after
+// the host they belong to
+func parseMDNSResponse(packet []byte) []candidate {
+	var msg dnsmessage.Message
+	if err := msg.Unpack(packet); err != nil || !msg.Header.Response {
+		return nil
+	}
+
+	var candidates []candidate
+	for _, record := range append(msg.Answers, msg.Additionals...) {
+		a, ok := record.Body.(*dnsmessage.AResource)
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			ip:     net.IP(a.A[:]).String(),
+			method: "mdns",
+			name:   strings.TrimSuffix(record.Header.Name.String(), "."),
+		})
+	}
+	return candidates
+}
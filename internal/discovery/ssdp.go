@@ -0,0 +1,58 @@
+package discovery
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// ssdpAddr is the SSDP multicast group
+var ssdpAddr = &net.UDPAddr{IP: net.IPv4(239, 255, 255, 250), Port: 1900}
+
+// ssdpSearch asks every UPnP device to announce itself
+const ssdpSearch = "M-SEARCH * HTTP/1.1\r\n" +
+	"HOST: 239.255.255.250:1900\r\n" +
+	"MAN: \"ssdp:discover\"\r\n" +
+	"MX: 1\r\n" +
+	"ST: ssdp:all\r\n" +
+	"\r\n"
+
+// ssdp multicasts an M-SEARCH and collects the devices that answer
+func (s *Scanner) ssdp(ctx context.Context) ([]candidate, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.WriteToUDP([]byte(ssdpSearch), ssdpAddr); err != nil {
+		return nil, err
+	}
+	return collect(ctx, conn, s.deadline(ctx), func(packet []byte, from *net.UDPAddr) []candidate {
+		if c, ok := parseSSDPResponse(packet, from); ok {
+			return []candidate{c}
+		}
+		return nil
+	})
+}
+
+// parseSSDPResponse reads an M-SEARCH response. The device is addressed by
+// the host of its LOCATION header, falling back to the sender's address.
+func parseSSDPResponse(packet []byte, from *net.UDPAddr) (candidate, bool) {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(packet)), nil)
+	if err != nil {
+		return candidate{}, false
+	}
+	resp.Body.Close()
+
+	c := candidate{method: "ssdp", name: resp.Header.Get("Server")}
+	if location, err := url.Parse(resp.Header.Get("Location")); err == nil && location.Hostname() != "" {
+		c.ip = location.Hostname()
+	} else if from != nil {
+		c.ip = from.IP.String()
+	}
+	return c, c.ip != ""
+}
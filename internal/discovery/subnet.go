@@ -0,0 +1,106 @@
+package discovery
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// minSubnetBits is the largest subnet probed host by host. Interfaces on
+// larger networks only have the /24 around their own address probed.
+const minSubnetBits = 22
+
+// subnet lists every host address on the subnets to probe
+func (s *Scanner) subnet(ctx context.Context) ([]candidate, error) {
+	subnets := s.Subnets
+	if len(subnets) == 0 {
+		var err error
+		if subnets, err = localSubnets(); err != nil {
+			return nil, err
+		}
+		if len(subnets) == 0 {
+			return nil, fmt.Errorf("no IPv4 network interfaces found")
+		}
+	}
+
+	var candidates []candidate
+	for _, subnet := range subnets {
+		hosts, err := Hosts(subnet)
+		if err != nil {
+			return nil, err
+		}
+		for _, host := range hosts {
+			candidates = append(candidates, candidate{ip: host, method: "subnet"})
+		}
+	}
+	return candidates, nil
+}
+
+// localSubnets returns the IPv4 subnets of the interfaces that are up,
+// excluding loopback
+func localSubnets() ([]*net.IPNet, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var subnets []*net.IPNet
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.To4() == nil {
+				continue
+			}
+			if ones, _ := ipNet.Mask.Size(); ones < minSubnetBits {
+				ipNet = &net.IPNet{IP: ipNet.IP, Mask: net.CIDRMask(24, 32)}
+			}
+			subnets = append(subnets, &net.IPNet{IP: ipNet.IP.Mask(ipNet.Mask), Mask: ipNet.Mask})
+		}
+	}
+	return subnets, nil
+}
+
+// Hosts returns the host addresses of an IPv4 subnet, leaving out the
+// network and broadcast addresses. Subnets larger than /22 are rejected.
+func Hosts(subnet *net.IPNet) ([]string, error) {
+	base := subnet.IP.To4()
+	ones, bits := subnet.Mask.Size()
+	if base == nil || bits != 32 {
+		return nil, fmt.Errorf("%s is not an IPv4 subnet", subnet)
+	}
+	if ones < minSubnetBits {
+		return nil, fmt.Errorf("%s is too large to scan; use a /%d or smaller", subnet, minSubnetBits)
+	}
+
+	first := binary.BigEndian.Uint32(base.Mask(subnet.Mask))
+	size := uint32(1) << (32 - ones)
+	if size <= 2 {
+		// /31 and /32 have no network or broadcast address
+		hosts := make([]string, 0, size)
+		for i := uint32(0); i < size; i++ {
+			hosts = append(hosts, uint32ToIP(first+i))
+		}
+		return hosts, nil
+	}
+
+	hosts := make([]string, 0, size-2)
+	for i := uint32(1); i < size-1; i++ {
+		hosts = append(hosts, uint32ToIP(first+i))
+	}
+	return hosts, nil
+}
+
+// uint32ToIP formats an IPv4 address held in an integer
+func uint32ToIP(n uint32) string {
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, n)
+	return ip.String()
+}
@@ -0,0 +1,33 @@
+package effects
+
+import "fmt"
+
+// Alternate returns the steps of an effect that shows a for aMs, then b for
+// bMs, and repeats, so the engine can run both as one animation. A
+// multi-step effect cycles its own steps during its turn, the last one cut
+// short so the turn ends on time.
+func Alternate(a, b *Effect, aMs, bMs int) ([]Step, error) {
+	if aMs < minStepMs || bMs < minStepMs {
+		return nil, fmt.Errorf("each effect must show for at least %dms", minStepMs)
+	}
+	return append(turn(a, aMs), turn(b, bMs)...), nil
+}
+
+// turn returns the steps that show e for ms
+func turn(e *Effect, ms int) []Step {
+	if len(e.Steps) == 0 {
+		return []Step{{Pattern: e.Pattern, DurationMs: ms}}
+	}
+	var steps []Step
+	for elapsed, i := 0, 0; elapsed < ms; i = (i + 1) % len(e.Steps) {
+		step := e.Steps[i]
+		remaining := ms - elapsed
+		// Stretch the last step rather than leave a sliver too short to send
+		if step.DurationMs <= 0 || step.DurationMs > remaining || remaining-step.DurationMs < minStepMs {
+			step.DurationMs = remaining
+		}
+		steps = append(steps, step)
+		elapsed += step.DurationMs
+	}
+	return steps
+}
@@ -0,0 +1,44 @@
+package effects
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAlternate(t *testing.T) {
+	ambient := &Effect{Name: "ambient", Pattern: "top_init=1&top_bg=000033"}
+	blink := &Effect{Name: "blink", Steps: []Step{
+		{Pattern: "top_bg=FF0000", DurationMs: 400},
+		{Pattern: "top_bg=000000", DurationMs: 400},
+	}}
+
+	steps, err := Alternate(ambient, blink, 50000, 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Step{
+		{Pattern: "top_init=1&top_bg=000033", DurationMs: 50000},
+		{Pattern: "top_bg=FF0000", DurationMs: 400},
+		{Pattern: "top_bg=000000", DurationMs: 400},
+		{Pattern: "top_bg=FF0000", DurationMs: 200},
+	}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("got %+v, want %+v", steps, want)
+	}
+
+	// A sliver shorter than a step is added to the step before it
+	steps, err = Alternate(blink, ambient, 830, 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(steps) != 3 || steps[1].DurationMs != 430 {
+		t.Errorf("expected the second blink step stretched to 430ms, got %+v", steps)
+	}
+	if err := ValidateSteps(steps); err != nil {
+		t.Errorf("alternation steps should be valid: %v", err)
+	}
+
+	if _, err := Alternate(ambient, blink, 10, 1000); err == nil {
+		t.Error("expected an error for a turn shorter than a step")
+	}
+}
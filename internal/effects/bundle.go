@@ -0,0 +1,278 @@
+package effects
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Bundle identification, checked on import so other JSON is not mistaken
+// for effects
+const (
+	BundleFormat  = "ufo-effects"
+	BundleVersion = 1
+)
+
+// Conflict strategies for effects in a bundle whose names are already taken
+const (
+	ConflictFail      = "fail"      // reject the whole import
+	ConflictSkip      = "skip"      // keep the stored effect
+	ConflictOverwrite = "overwrite" // replace the stored effect
+	ConflictRename    = "rename"    // import under a free name such as pulse_2
+)
+
+// Bundle is a shareable set of effects, as written by exportEffects
+type Bundle struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	Effects    []*Effect `json:"effects"`
+}
+
+// ImportOptions controls how a bundle is merged into the store
+type ImportOptions struct {
+	Replace    bool     // remove every stored effect not in the bundle
+	Keep       []string // effects that Replace leaves in place
+	OnConflict string   // one of the Conflict strategies; merging only
+	DryRun     bool     // report what would change without changing it
+}
+
+// ImportReport lists what an import changed, by effect name
+type ImportReport struct {
+	Added     []string          `json:"added"`
+	Updated   []string          `json:"updated"`
+	Skipped   []string          `json:"skipped"`
+	Renamed   map[string]string `json:"renamed"` // bundle name to stored name
+	Removed   []string          `json:"removed"`
+	Conflicts []string          `json:"conflicts,omitempty"` // names taken, when the import failed for them
+	Invalid   map[string]string `json:"invalid,omitempty"`   // effect name to why it is invalid, when the import failed for them
+	DryRun    bool              `json:"dryRun"`
+}
+
+// Export returns a bundle of the named effects, or of every effect when
+// names is empty, ordered by name
+func (s *Store) Export(names []string) (*Bundle, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var selected []*Effect
+	if len(names) == 0 {
+		for _, effect := range s.effects {
+			selected = append(selected, effect)
+		}
+	} else {
+		var missing []string
+		for _, name := range names {
+			effect, exists := s.effects[name]
+			if !exists {
+				missing = append(missing, name)
+				continue
+			}
+			selected = append(selected, effect)
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("unknown effects: %s", strings.Join(missing, ", "))
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Name < selected[j].Name })
+
+	return &Bundle{
+		Format:     BundleFormat,
+		Version:    BundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Effects:    selected,
+	}, nil
+}
+
+// ParseBundle reads a bundle written by Export. A plain array of effects,
+// like the effects file, is accepted too.
+func ParseBundle(data []byte) (*Bundle, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var list []*Effect
+		if err := json.Unmarshal([]byte(trimmed), &list); err != nil {
+			return nil, fmt.Errorf("parsing effects JSON: %w", err)
+		}
+		return &Bundle{Format: BundleFormat, Version: BundleVersion, Effects: list}, nil
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal([]byte(trimmed), &bundle); err != nil {
+		return nil, fmt.Errorf("parsing bundle JSON: %w", err)
+	}
+	if bundle.Format != BundleFormat {
+		return nil, fmt.Errorf("not an effects bundle: format is %q, want %q", bundle.Format, BundleFormat)
+	}
+	if bundle.Version < 1 || bundle.Version > BundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d; this server reads up to version %d", bundle.Version, BundleVersion)
+	}
+	return &bundle, nil
+}
+
+// Import merges a bundle into the store, or replaces the store's effects
+// with it, and saves the result. Every effect is checked first, so a bundle
+// with one bad effect changes nothing.
+func (s *Store) Import(bundle *Bundle, opts ImportOptions) (*ImportReport, error) {
+	if opts.OnConflict == "" {
+		opts.OnConflict = ConflictFail
+	}
+	switch opts.OnConflict {
+	case ConflictFail, ConflictSkip, ConflictOverwrite, ConflictRename:
+	default:
+		return nil, fmt.Errorf("unknown conflict strategy %q", opts.OnConflict)
+	}
+	if len(bundle.Effects) == 0 {
+		return nil, fmt.Errorf("the bundle has no effects")
+	}
+
+	report := &ImportReport{
+		Added:   []string{},
+		Updated: []string{},
+		Skipped: []string{},
+		Renamed: map[string]string{},
+		Removed: []string{},
+		DryRun:  opts.DryRun,
+	}
+	incoming := make([]*Effect, 0, len(bundle.Effects))
+	seen := make(map[string]bool)
+	for i, effect := range bundle.Effects {
+		if effect == nil || effect.Name == "" {
+			return nil, fmt.Errorf("effect %d has no name", i)
+		}
+		if seen[effect.Name] {
+			return nil, fmt.Errorf("effect '%s' appears twice in the bundle", effect.Name)
+		}
+		seen[effect.Name] = true
+		if err := validateImported(effect); err != nil {
+			if report.Invalid == nil {
+				report.Invalid = map[string]string{}
+			}
+			report.Invalid[effect.Name] = err.Error()
+			continue
+		}
+		copied := *effect
+		normalizeLabels(&copied)
+		incoming = append(incoming, &copied)
+	}
+	if len(report.Invalid) > 0 {
+		names := make([]string, 0, len(report.Invalid))
+		for name, reason := range report.Invalid {
+			names = append(names, fmt.Sprintf("'%s' (%s)", name, reason))
+		}
+		sort.Strings(names)
+		return report, fmt.Errorf("invalid effects: %s", strings.Join(names, "; "))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := make(map[string]*Effect, len(s.effects)+len(incoming))
+	if opts.Replace {
+		kept := make(map[string]bool, len(opts.Keep))
+		for _, name := range opts.Keep {
+			kept[name] = true
+		}
+		for name, effect := range s.effects {
+			switch {
+			case kept[name]:
+				next[name] = effect
+			case !seen[name]:
+				report.Removed = append(report.Removed, name)
+			}
+		}
+	} else {
+		for name, effect := range s.effects {
+			next[name] = effect
+		}
+	}
+
+	for _, effect := range incoming {
+		if _, taken := s.effects[effect.Name]; !taken || opts.Replace {
+			if taken {
+				report.Updated = append(report.Updated, effect.Name)
+			} else {
+				report.Added = append(report.Added, effect.Name)
+			}
+			next[effect.Name] = effect
+			continue
+		}
+		switch opts.OnConflict {
+		case ConflictFail:
+			report.Conflicts = append(report.Conflicts, effect.Name)
+		case ConflictSkip:
+			report.Skipped = append(report.Skipped, effect.Name)
+		case ConflictOverwrite:
+			report.Updated = append(report.Updated, effect.Name)
+			next[effect.Name] = effect
+		case ConflictRename:
+			renamed := *effect
+			renamed.Name = freeName(effect.Name, next, seen)
+			report.Renamed[effect.Name] = renamed.Name
+			next[renamed.Name] = &renamed
+		}
+	}
+	sort.Strings(report.Removed)
+	if len(report.Conflicts) > 0 {
+		return report, fmt.Errorf("effects already exist: %s", strings.Join(report.Conflicts, ", "))
+	}
+	if err := composeAll(next); err != nil {
+		return report, err
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+
+	// Keep the versions the import overwrites or removes
+	replaced := make([]*Effect, 0, len(report.Updated)+len(report.Removed))
+	for _, name := range report.Updated {
+		if !sameEffect(s.effects[name], next[name]) {
+			replaced = append(replaced, s.effects[name])
+		}
+	}
+	for _, name := range report.Removed {
+		replaced = append(replaced, s.effects[name])
+	}
+	if err := s.recordUnsafe(RevisionImported, time.Now(), replaced...); err != nil {
+		return nil, err
+	}
+
+	previous := s.effects
+	s.effects = next
+	if err := s.saveUnsafe(); err != nil {
+		s.effects = previous
+		return nil, fmt.Errorf("saving effects: %w", err)
+	}
+	return report, nil
+}
+
+// validateImported checks an incoming effect as addEffect would: its steps,
+// its params, and its pattern and steps against the UFO query grammar. The
+// patterns of composites are composed from their layers once they are
+// stored.
+func validateImported(effect *Effect) error {
+	if err := ValidateSteps(effect.Steps); err != nil {
+		return fmt.Errorf("invalid steps: %w", err)
+	}
+	if err := ValidateParams(effect); err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
+	if effect.Composite == nil {
+		if err := ValidatePatterns(effect); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	return nil
+}
+
+// freeName returns name with the lowest suffix _2, _3, ... that is neither
+// stored nor used by the bundle
+func freeName(name string, stored map[string]*Effect, bundled map[string]bool) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s_%d", name, n)
+		if _, taken := stored[candidate]; !taken && !bundled[candidate] {
+			return candidate
+		}
+	}
+}
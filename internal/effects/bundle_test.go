@@ -0,0 +1,187 @@
+package effects
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func bundleStore(t *testing.T, names ...string) *Store {
+	t.Helper()
+	store := NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	for _, name := range names {
+		if err := store.Add(&Effect{Name: name, Description: "stored " + name, Pattern: "top=0|15|FF0000"}); err != nil {
+			t.Fatalf("adding %s: %v", name, err)
+		}
+	}
+	return store
+}
+
+func TestStore_ExportAndParse(t *testing.T) {
+	store := bundleStore(t, "pulse", "glow", "wave")
+
+	bundle, err := store.Export([]string{"wave", "glow"})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if len(bundle.Effects) != 2 || bundle.Effects[0].Name != "glow" || bundle.Effects[1].Name != "wave" {
+		t.Errorf("expected glow and wave by name, got %v", bundle.Effects)
+	}
+	if _, err := store.Export([]string{"missing"}); err == nil {
+		t.Error("expected an error for an unknown effect")
+	}
+
+	all, _ := store.Export(nil)
+	data, _ := json.Marshal(all)
+	parsed, err := ParseBundle(data)
+	if err != nil {
+		t.Fatalf("ParseBundle: %v", err)
+	}
+	if parsed.Format != BundleFormat || len(parsed.Effects) != 3 {
+		t.Errorf("expected all 3 effects back, got %+v", parsed)
+	}
+
+	// A plain effects file is read as a bundle
+	parsed, err = ParseBundle([]byte(`[{"name": "solo", "pattern": "top=0|15|00FF00"}]`))
+	if err != nil || len(parsed.Effects) != 1 {
+		t.Errorf("expected a plain array to parse, got %v %v", parsed, err)
+	}
+
+	for _, bad := range []string{`{"effects": []}`, `{"format": "ufo-effects", "version": 9, "effects": []}`, `not json`} {
+		if _, err := ParseBundle([]byte(bad)); err == nil {
+			t.Errorf("expected an error for %s", bad)
+		}
+	}
+}
+
+func TestStore_ImportConflicts(t *testing.T) {
+	incoming := func() *Bundle {
+		return &Bundle{Format: BundleFormat, Version: BundleVersion, Effects: []*Effect{
+			{Name: "pulse", Description: "imported pulse", Pattern: "top=0|15|0000FF"},
+			{Name: "sparkle", Description: "imported sparkle", Pattern: "top=0|15|FFFFFF", Tags: []string{"White"}},
+		}}
+	}
+
+	tests := []struct {
+		strategy    string
+		wantErr     bool
+		pulse       string
+		pulseRename string
+	}{
+		{ConflictFail, true, "stored pulse", ""},
+		{ConflictSkip, false, "stored pulse", ""},
+		{ConflictOverwrite, false, "imported pulse", ""},
+		{ConflictRename, false, "stored pulse", "pulse_2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			store := bundleStore(t, "pulse", "glow")
+			report, err := store.Import(incoming(), ImportOptions{OnConflict: tt.strategy})
+			if tt.wantErr {
+				if err == nil || len(report.Conflicts) != 1 {
+					t.Fatalf("expected a conflict error, got %v %+v", err, report)
+				}
+				if _, exists := store.Get("sparkle"); exists {
+					t.Error("a failed import must change nothing")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Import: %v", err)
+			}
+			if pulse, _ := store.Get("pulse"); pulse.Description != tt.pulse {
+				t.Errorf("expected pulse %q, got %q", tt.pulse, pulse.Description)
+			}
+			if sparkle, exists := store.Get("sparkle"); !exists || sparkle.Tags[0] != "white" {
+				t.Errorf("expected sparkle added with normalized tags, got %+v", sparkle)
+			}
+			if tt.pulseRename != "" {
+				if report.Renamed["pulse"] != tt.pulseRename {
+					t.Errorf("expected pulse renamed to %s, got %v", tt.pulseRename, report.Renamed)
+				}
+				if renamed, exists := store.Get(tt.pulseRename); !exists || renamed.Description != "imported pulse" {
+					t.Errorf("expected the imported pulse as %s", tt.pulseRename)
+				}
+			}
+
+			// The import was saved
+			reloaded := NewStore(store.file)
+			if err := reloaded.Load(); err != nil {
+				t.Fatalf("reloading: %v", err)
+			}
+			if _, exists := reloaded.Get("sparkle"); !exists {
+				t.Error("expected the imported effect in the effects file")
+			}
+		})
+	}
+}
+
+func TestStore_ImportReplace(t *testing.T) {
+	store := bundleStore(t, "rainbow", "pulse", "glow")
+	bundle := &Bundle{Format: BundleFormat, Version: BundleVersion, Effects: []*Effect{
+		{Name: "pulse", Description: "imported pulse", Pattern: "top=0|15|0000FF"},
+	}}
+
+	report, err := store.Import(bundle, ImportOptions{Replace: true, Keep: []string{"rainbow"}, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(report.Removed) != 1 || report.Removed[0] != "glow" || len(report.Updated) != 1 {
+		t.Errorf("expected glow removed and pulse updated, got %+v", report)
+	}
+	if _, exists := store.Get("glow"); !exists {
+		t.Error("a dry run must change nothing")
+	}
+
+	if _, err := store.Import(bundle, ImportOptions{Replace: true, Keep: []string{"rainbow"}}); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if _, exists := store.Get("glow"); exists {
+		t.Error("expected glow removed")
+	}
+	if _, exists := store.Get("rainbow"); !exists {
+		t.Error("expected the kept effect to stay")
+	}
+}
+
+func TestStore_ImportRejectsInvalidBundles(t *testing.T) {
+	store := bundleStore(t, "pulse")
+	for name, effects := range map[string][]*Effect{
+		"empty":       {},
+		"unnamed":     {{Pattern: "top=0|15|FF0000"}},
+		"duplicate":   {{Name: "a", Pattern: "x=1"}, {Name: "a", Pattern: "x=2"}},
+		"undeclared":  {{Name: "b", Pattern: "top=0|15|{color}"}},
+		"short steps": {{Name: "c", Steps: []Step{{Pattern: "top=0|15|FF0000", DurationMs: 1}}}},
+	} {
+		if _, err := store.Import(&Bundle{Effects: effects}, ImportOptions{}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := store.Import(&Bundle{Effects: []*Effect{{Name: "d", Pattern: "x=1"}}}, ImportOptions{OnConflict: "merge"}); err == nil {
+		t.Error("expected an error for an unknown strategy")
+	}
+}
+
+func TestStore_ImportReportsInvalidPatterns(t *testing.T) {
+	store := bundleStore(t, "pulse")
+	bundle := &Bundle{Effects: []*Effect{
+		{Name: "good", Pattern: "top_init=1&top=0|15|00FF00"},
+		{Name: "offRing", Pattern: "top_init=1&top=15|1|FF0000"},
+		{Name: "badStep", Steps: []Step{{Pattern: "top=0|15|FF0000", DurationMs: 400}, {Pattern: "dim=300", DurationMs: 400}}},
+	}}
+
+	report, err := store.Import(bundle, ImportOptions{})
+	if err == nil {
+		t.Fatal("expected an error for invalid patterns")
+	}
+	if len(report.Invalid) != 2 {
+		t.Fatalf("expected offRing and badStep reported invalid, got %v", report.Invalid)
+	}
+	if !strings.Contains(report.Invalid["offRing"], "start must be an LED") || !strings.Contains(report.Invalid["badStep"], "step 1") {
+		t.Errorf("unexpected reasons %v", report.Invalid)
+	}
+	if _, exists := store.Get("good"); exists {
+		t.Error("a failed import must change nothing")
+	}
+}
@@ -0,0 +1,241 @@
+package effects
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+)
+
+const (
+	// DefaultFrameMs is the time between the frames of a composite effect
+	DefaultFrameMs = 100
+	// maxCompositeMs is the longest cycle a composite effect repeats. Layers
+	// that only line up again later are cut short, with a jump every cycle.
+	maxCompositeMs = 60000
+)
+
+// Composite layers stored effects on top of each other, e.g. a background
+// gradient with a whirling highlight. The server renders each layer as the
+// UFO would show it and blends them LED by LED into the frames of one
+// multi-step effect, so the UFO only sees a single stream of updates. Only
+// the rings are layered; the layers' logo and brightness are left out.
+type Composite struct {
+	Layers  []Layer `json:"layers"`            // bottom layer first
+	FrameMs int     `json:"frameMs,omitempty"` // time between frames, default DefaultFrameMs
+}
+
+// Layer is one stored effect of a composite
+type Layer struct {
+	Effect string            `json:"effect"`
+	Blend  string            `json:"blend,omitempty"`  // how it covers the layers below: overwrite (default), add or max
+	Params map[string]string `json:"params,omitempty"` // values for the effect's parameters
+}
+
+// Compose renders the layers, looked up with get, and returns what the
+// composite shows: a single pattern when nothing moves, otherwise the steps
+// of one cycle. Each step only redraws the rings that changed.
+func (c *Composite) Compose(get func(name string) (*Effect, bool)) (string, []Step, error) {
+	if len(c.Layers) < 2 {
+		return "", nil, fmt.Errorf("a composite needs at least 2 layers, got %d", len(c.Layers))
+	}
+	frameMs := c.FrameMs
+	if frameMs == 0 {
+		frameMs = DefaultFrameMs
+	}
+	if frameMs < minStepMs {
+		return "", nil, fmt.Errorf("frameMs must be at least %d, got %d", minStepMs, frameMs)
+	}
+
+	layers := make([]*Effect, len(c.Layers))
+	cycleMs := frameMs
+	for i, layer := range c.Layers {
+		if layer.Blend != "" && !slices.Contains(device.LayerModes, layer.Blend) {
+			return "", nil, fmt.Errorf("layer %d: unknown blend %q: use %s", i, layer.Blend, strings.Join(device.LayerModes, ", "))
+		}
+		effect, ok := get(layer.Effect)
+		if !ok {
+			return "", nil, fmt.Errorf("layer %d: effect '%s' not found", i, layer.Effect)
+		}
+		if effect.Composite != nil {
+			return "", nil, fmt.Errorf("layer %d: effect '%s' is itself a composite", i, layer.Effect)
+		}
+		effect, err := effect.Render(layer.Params)
+		if err != nil {
+			return "", nil, fmt.Errorf("layer %d: %w", i, err)
+		}
+		periodMs, err := loopMs(effect, frameMs)
+		if err != nil {
+			return "", nil, fmt.Errorf("layer %d: effect '%s': %w", i, layer.Effect, err)
+		}
+		layers[i] = effect
+		cycleMs = min(lcm(cycleMs, periodMs), maxCompositeMs)
+	}
+	cycleMs -= cycleMs % frameMs
+
+	var frames []device.Frame
+	for i, effect := range layers {
+		rendered, err := device.Render(queries(effect, cycleMs), cycleMs-frameMs, frameMs)
+		if err != nil {
+			return "", nil, fmt.Errorf("layer %d: effect '%s': %w", i, c.Layers[i].Effect, err)
+		}
+		for n, preview := range rendered {
+			if i == 0 {
+				frames = append(frames, preview.Frame)
+				continue
+			}
+			blend := c.Layers[i].Blend
+			if blend == "" {
+				blend = device.LayerOverwrite
+			}
+			if frames[n], err = frames[n].Layer(preview.Frame, blend); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+
+	durations := make([]int, len(frames))
+	for n := range durations {
+		durations[n] = frameMs
+	}
+	steps, err := frameSteps(frames, durations)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(steps) == 1 {
+		return steps[0].Pattern, nil, nil
+	}
+	return "", steps, nil
+}
+
+// frameSteps returns the steps showing each frame for its duration, merging
+// repeated frames. The first step draws both rings, since the UFO may show
+// anything when the steps start or repeat; the others only redraw the rings
+// that changed.
+func frameSteps(frames []device.Frame, durationsMs []int) ([]Step, error) {
+	var steps []Step
+	previous := device.Frame{}
+	for n, frame := range frames {
+		if n > 0 && frame == frames[n-1] {
+			steps[len(steps)-1].DurationMs += durationsMs[n]
+			continue
+		}
+		query, err := frame.Query(previous)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, Step{Pattern: query, DurationMs: durationsMs[n]})
+		previous = frame
+	}
+	return steps, nil
+}
+
+// loopMs returns how often the effect repeats, in whole frames: the length
+// of its steps, and of every whirl and morph it starts
+func loopMs(effect *Effect, frameMs int) (int, error) {
+	periodMs := frameMs
+	if len(effect.Steps) > 0 {
+		total := 0
+		for _, step := range effect.Steps {
+			total += step.DurationMs
+		}
+		periodMs = lcm(periodMs, roundUp(total, frameMs))
+	}
+	for _, query := range effect.patterns() {
+		if query == "" {
+			continue
+		}
+		pattern, err := device.ParsePattern(query)
+		if err != nil {
+			return 0, err
+		}
+		for _, ring := range []*device.RingPattern{pattern.Top, pattern.Bottom} {
+			if ring == nil {
+				continue
+			}
+			if ring.Whirl != nil && ring.Whirl.Ms > 0 {
+				periodMs = min(lcm(periodMs, roundUp(ring.Whirl.Ms*device.RingLEDs, frameMs)), maxCompositeMs)
+			}
+			if ring.Morph != nil && ring.Morph.FadeMs > 0 {
+				periodMs = min(lcm(periodMs, roundUp(ring.Morph.BrightnessMs+2*ring.Morph.FadeMs, frameMs)), maxCompositeMs)
+			}
+		}
+	}
+	return min(periodMs, maxCompositeMs), nil
+}
+
+// queries returns the queries the effect sends in its first untilMs,
+// cycling its steps as the engine does
+func queries(effect *Effect, untilMs int) []device.PreviewStep {
+	if len(effect.Steps) == 0 {
+		return []device.PreviewStep{{Query: effect.Pattern}}
+	}
+	var steps []device.PreviewStep
+	for at := 0; at < untilMs; {
+		for _, step := range effect.Steps {
+			steps = append(steps, device.PreviewStep{AtMs: at, Query: step.Pattern})
+			at += max(step.DurationMs, 1)
+		}
+	}
+	return steps
+}
+
+// roundUp rounds n up to a whole number of units
+func roundUp(n, unit int) int {
+	return max((n+unit-1)/unit, 1) * unit
+}
+
+// lcm returns the least common multiple of two positive numbers
+func lcm(a, b int) int {
+	x, y := a, b
+	for y != 0 {
+		x, y = y, x%y
+	}
+	return a / x * b
+}
+
+// composeAll recomputes the frames of every composite in effects, whose
+// layers may have changed, storing updated copies
+func composeAll(effects map[string]*Effect) error {
+	get := func(name string) (*Effect, bool) {
+		effect, ok := effects[name]
+		return effect, ok
+	}
+	for name, effect := range effects {
+		if effect.Composite == nil {
+			continue
+		}
+		pattern, steps, err := effect.Composite.Compose(get)
+		if err != nil {
+			return fmt.Errorf("composite effect '%s': %w", name, err)
+		}
+		if pattern == effect.Pattern && reflect.DeepEqual(steps, effect.Steps) {
+			continue
+		}
+		composed := *effect
+		composed.Pattern, composed.Steps = pattern, steps
+		effects[name] = &composed
+	}
+	return nil
+}
+
+// layeredIn returns the composites that use the named effect as a layer,
+// sorted
+func layeredIn(effects map[string]*Effect, name string) []string {
+	var composites []string
+	for _, effect := range effects {
+		if effect.Composite == nil {
+			continue
+		}
+		for _, layer := range effect.Composite.Layers {
+			if layer.Effect == name {
+				composites = append(composites, effect.Name)
+				break
+			}
+		}
+	}
+	slices.Sort(composites)
+	return composites
+}
@@ -0,0 +1,126 @@
+package effects
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+)
+
+// lookup finds effects in a list, as the store does
+func lookup(effects ...*Effect) func(name string) (*Effect, bool) {
+	return func(name string) (*Effect, bool) {
+		for _, effect := range effects {
+			if effect.Name == name {
+				return effect, true
+			}
+		}
+		return nil, false
+	}
+}
+
+func TestComposite_Compose(t *testing.T) {
+	background := &Effect{Name: "background", Pattern: "top_init=1&top_bg=000033&bottom_init=1&bottom_bg=000033"}
+	highlight := &Effect{Name: "highlight", Pattern: "top_init=1&top_bg=000000&top=0|1|{color}", Params: []Param{{Name: "color", Default: "FFFFFF"}}}
+	whirling := &Effect{Name: "whirling", Pattern: "top_init=1&top_bg=000000&top=0|1|FF0000&top_whirl=100"}
+	get := lookup(background, highlight, whirling)
+
+	// Nothing moves, so one pattern draws it
+	composite := &Composite{Layers: []Layer{{Effect: "background"}, {Effect: "highlight", Params: map[string]string{"color": "00FF00"}}}}
+	pattern, steps, err := composite.Compose(get)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if steps != nil {
+		t.Fatalf("expected a static pattern, got %d steps", len(steps))
+	}
+	frame, err := device.Frame{}.Apply(pattern)
+	if err != nil {
+		t.Fatalf("composed pattern rejected: %v", err)
+	}
+	if frame.Top[0] != "00ff00" || frame.Top[1] != "000033" || frame.Bottom[0] != "000033" {
+		t.Errorf("expected the highlight over the background, got %v", frame)
+	}
+
+	// A whirl moves the highlight one LED per frame for a whole turn
+	composite = &Composite{Layers: []Layer{{Effect: "background"}, {Effect: "whirling", Blend: device.LayerAdd}}}
+	_, steps, err = composite.Compose(get)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(steps) != device.RingLEDs {
+		t.Fatalf("expected %d steps, got %d", device.RingLEDs, len(steps))
+	}
+	if err := ValidateSteps(steps); err != nil {
+		t.Errorf("composed steps should be valid: %v", err)
+	}
+	if !strings.Contains(steps[0].Pattern, "bottom_bg=000033") {
+		t.Errorf("expected the first step to draw both rings, got %q", steps[0].Pattern)
+	}
+	frame = device.Frame{}
+	for i, step := range steps {
+		if i > 0 && strings.Contains(step.Pattern, "bottom") {
+			t.Errorf("step %d redraws the unchanged bottom ring: %q", i, step.Pattern)
+		}
+		if frame, err = frame.Apply(step.Pattern); err != nil {
+			t.Fatalf("step %d rejected: %v", i, err)
+		}
+		if frame.Top[i] != "ff0033" {
+			t.Errorf("step %d: expected the highlight added at LED %d, got %v", i, i, frame.Top)
+		}
+	}
+
+	for _, bad := range []*Composite{
+		{Layers: []Layer{{Effect: "background"}}},
+		{Layers: []Layer{{Effect: "background"}, {Effect: "missing"}}},
+		{Layers: []Layer{{Effect: "background"}, {Effect: "highlight", Blend: "multiply"}}},
+		{Layers: []Layer{{Effect: "background"}, {Effect: "highlight", Params: map[string]string{"size": "3"}}}},
+		{Layers: []Layer{{Effect: "background"}, {Effect: "highlight"}}, FrameMs: 10},
+	} {
+		if _, _, err := bad.Compose(get); err == nil {
+			t.Errorf("expected an error for %+v", bad)
+		}
+	}
+}
+
+func TestStore_Composite(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	for _, effect := range []*Effect{
+		{Name: "background", Pattern: "top_init=1&top_bg=000033"},
+		{Name: "highlight", Pattern: "top_init=1&top_bg=000000&top=0|1|FFFFFF"},
+		{Name: "layered", Composite: &Composite{Layers: []Layer{{Effect: "background"}, {Effect: "highlight"}}}},
+	} {
+		if err := store.Add(effect); err != nil {
+			t.Fatalf("failed to add %s: %v", effect.Name, err)
+		}
+	}
+	layered, _ := store.Get("layered")
+	if !strings.Contains(layered.Pattern, "top=0|1|FFFFFF") {
+		t.Fatalf("expected the store to compose the effect, got %q", layered.Pattern)
+	}
+
+	// Changing a layer recomposes the composite
+	if err := store.Update(&Effect{Name: "background", Pattern: "top_init=1&top_bg=330000"}); err != nil {
+		t.Fatalf("failed to update a layer: %v", err)
+	}
+	layered, _ = store.Get("layered")
+	if !strings.Contains(layered.Pattern, "top_bg=330000") {
+		t.Errorf("expected the composite to follow its layer, got %q", layered.Pattern)
+	}
+
+	if err := store.Add(&Effect{Name: "nested", Composite: &Composite{Layers: []Layer{{Effect: "layered"}, {Effect: "highlight"}}}}); err == nil {
+		t.Error("expected an error for a composite layering a composite")
+	}
+	if err := store.Delete("highlight"); err == nil || !strings.Contains(err.Error(), "layered") {
+		t.Errorf("expected deleting a layer to be refused, got %v", err)
+	}
+
+	reloaded := NewStore(store.file)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("failed to reload: %v", err)
+	}
+	if effect, _ := reloaded.Get("layered"); effect.Composite == nil || effect.Pattern != layered.Pattern {
+		t.Errorf("expected the composite to survive a reload, got %+v", effect)
+	}
+}
@@ -3,18 +3,35 @@ package effects
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 )
 
 // Effect represents a lighting effect configuration
 type Effect struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Pattern     string `json:"pattern"`
-	Duration    int    `json:"duration"` // Duration in milliseconds (was seconds in v1)
-	Perpetual   bool   `json:"perpetual"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Pattern     string     `json:"pattern"`
+	Duration    int        `json:"duration"` // Duration in milliseconds (was seconds in v1)
+	Perpetual   bool       `json:"perpetual"`
+	Steps       []Step     `json:"steps,omitempty"`     // frames for multi-step effects, cycled until stopped
+	Params      []Param    `json:"params,omitempty"`    // placeholders in Pattern and Steps, filled in by playEffect
+	Category    string     `json:"category,omitempty"`  // one group, e.g. "alerts" or "ambient", for listEffects filters
+	Tags        []string   `json:"tags,omitempty"`      // lowercase labels, e.g. "red" or "incident", for listEffects filters
+	Composite   *Composite `json:"composite,omitempty"` // layers other effects; the store computes Pattern and Steps from them
+}
+
+// FirstPattern returns the query that starts the effect: the first step of
+// a multi-step effect, or its static pattern
+func (e *Effect) FirstPattern() string {
+	if len(e.Steps) > 0 {
+		return e.Steps[0].Pattern
+	}
+	return e.Pattern
 }
 
 // Store manages the collection of lighting effects
@@ -22,13 +39,22 @@ type Store struct {
 	mu      sync.RWMutex
 	effects map[string]*Effect
 	file    string
+
+	revisions       map[string][]Revision // earlier versions by effect name, oldest first
+	revisionsFile   string                // empty keeps revisions in memory only
+	revisionsLoaded bool                  // revisions are recorded
+	maxRevisions    int                   // revisions kept per effect
 }
 
-// NewStore creates a new effect store
+// NewStore creates a new effect store. It keeps earlier versions of effects
+// in memory until SetRevisionsFile is called.
 func NewStore(filePath string) *Store {
 	return &Store{
-		effects: make(map[string]*Effect),
-		file:    filePath,
+		effects:         make(map[string]*Effect),
+		file:            filePath,
+		revisions:       make(map[string][]Revision),
+		revisionsLoaded: true,
+		maxRevisions:    DefaultMaxRevisions,
 	}
 }
 
@@ -36,7 +62,11 @@ func NewStore(filePath string) *Store {
 func (s *Store) Load() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.loadUnsafe()
+}
 
+// loadUnsafe loads without acquiring lock (internal use)
+func (s *Store) loadUnsafe() error {
 	data, err := os.ReadFile(s.file)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -47,7 +77,7 @@ func (s *Store) Load() error {
 				return s.saveUnsafe()
 			}
 			// Load the copied file
-			return s.Load()
+			return s.loadUnsafe()
 		}
 		return fmt.Errorf("reading effects file: %w", err)
 	}
@@ -63,9 +93,15 @@ func (s *Store) Load() error {
 		if effect.Duration > 0 && effect.Duration < 1000 {
 			effect.Duration *= 1000
 		}
+		normalizeLabels(effect)
 		s.effects[effect.Name] = effect
 	}
 
+	// Composites saved by hand may lack their frames
+	if err := composeAll(s.effects); err != nil {
+		slog.Warn("Failed to compose effect", "error", err)
+	}
+
 	return nil
 }
 
@@ -97,6 +133,21 @@ func (s *Store) saveUnsafe() error {
 	return os.WriteFile(s.file, data, 0644)
 }
 
+// CheckWritable verifies that the effects file can be saved by creating and
+// removing a temporary file next to it
+func (s *Store) CheckWritable() error {
+	dir := filepath.Dir(s.file)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	f, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("writing to data directory: %w", err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
 // List returns all effects
 func (s *Store) List() []*Effect {
 	s.mu.RLock()
@@ -123,6 +174,12 @@ func (s *Store) Add(effect *Effect) error {
 	if effect.Name == "" {
 		return fmt.Errorf("effect name cannot be empty")
 	}
+	if err := ValidateSteps(effect.Steps); err != nil {
+		return fmt.Errorf("invalid steps: %w", err)
+	}
+	if err := ValidateParams(effect); err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -131,12 +188,18 @@ func (s *Store) Add(effect *Effect) error {
 		return fmt.Errorf("effect with name '%s' already exists", effect.Name)
 	}
 
+	normalizeLabels(effect)
+
 	// Set default duration if not specified
 	if effect.Duration <= 0 {
 		effect.Duration = 10000 // 10 seconds in milliseconds
 	}
 
-	s.effects[effect.Name] = effect
+	next, err := s.withUnsafe(effect)
+	if err != nil {
+		return err
+	}
+	s.effects = next
 	return s.saveUnsafe()
 }
 
@@ -145,31 +208,74 @@ func (s *Store) Update(effect *Effect) error {
 	if effect.Name == "" {
 		return fmt.Errorf("effect name cannot be empty")
 	}
+	if err := ValidateSteps(effect.Steps); err != nil {
+		return fmt.Errorf("invalid steps: %w", err)
+	}
+	if err := ValidateParams(effect); err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.effects[effect.Name]; !exists {
+	previous, exists := s.effects[effect.Name]
+	if !exists {
 		return fmt.Errorf("effect with name '%s' does not exist", effect.Name)
 	}
 
+	normalizeLabels(effect)
+
 	// Set default duration if not specified
 	if effect.Duration <= 0 {
 		effect.Duration = 10000 // 10 seconds in milliseconds
 	}
 
-	s.effects[effect.Name] = effect
+	next, err := s.withUnsafe(effect)
+	if err != nil {
+		return err
+	}
+
+	// Keep the version being replaced, so it can be rolled back to
+	if !sameEffect(previous, effect) {
+		if err := s.recordUnsafe(RevisionUpdated, time.Now(), previous); err != nil {
+			return err
+		}
+	}
+
+	s.effects = next
 	return s.saveUnsafe()
 }
 
+// withUnsafe returns the stored effects with effect added or replaced and
+// the composites, which may layer it, recomputed. It fails if one of them
+// can no longer be composed.
+func (s *Store) withUnsafe(effect *Effect) (map[string]*Effect, error) {
+	next := make(map[string]*Effect, len(s.effects)+1)
+	for name, stored := range s.effects {
+		next[name] = stored
+	}
+	next[effect.Name] = effect
+	if err := composeAll(next); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
 // Delete removes an effect by name
 func (s *Store) Delete(name string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.effects[name]; !exists {
+	effect, exists := s.effects[name]
+	if !exists {
 		return fmt.Errorf("effect with name '%s' does not exist", name)
 	}
+	if composites := layeredIn(s.effects, name); len(composites) > 0 {
+		return fmt.Errorf("effect '%s' is a layer of %s; change or delete those first", name, strings.Join(composites, ", "))
+	}
+	if err := s.recordUnsafe(RevisionDeleted, time.Now(), effect); err != nil {
+		return err
+	}
 
 	delete(s.effects, name)
 	return s.saveUnsafe()
@@ -106,6 +106,27 @@ func TestStore_LoadSave(t *testing.T) {
 	}
 }
 
+func TestStore_CheckWritable(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(filepath.Join(tmpDir, "data", "effects.json"))
+	if err := store.CheckWritable(); err != nil {
+		t.Fatalf("expected writable store, got %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(tmpDir, "data"))
+	if err != nil || len(entries) != 0 {
+		t.Errorf("expected the check to leave no files behind, got %v (%v)", entries, err)
+	}
+
+	// A data directory that is really a file cannot be written
+	blocked := filepath.Join(tmpDir, "blocked")
+	if err := os.WriteFile(blocked, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewStore(filepath.Join(blocked, "effects.json")).CheckWritable(); err == nil {
+		t.Error("expected an error when the data directory cannot be created")
+	}
+}
+
 func TestStore_SeedEffects(t *testing.T) {
 	tmpDir := t.TempDir()
 	filePath := filepath.Join(tmpDir, "seed_effects.json")
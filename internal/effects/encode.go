@@ -0,0 +1,230 @@
+package effects
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+)
+
+// Schemes for showing text on the rings
+const (
+	SchemeMorse  = "morse"  // both rings flash the text in Morse code
+	SchemeDigits = "digits" // one digit at a time, as that many lit LEDs
+	SchemeBinary = "binary" // the bits of a number, or of each character's code
+)
+
+// Schemes lists the schemes Encode accepts
+var Schemes = []string{SchemeMorse, SchemeDigits, SchemeBinary}
+
+const (
+	// DefaultMorseUnitMs is the length of a Morse dot
+	DefaultMorseUnitMs = 200
+	// DefaultCharacterMs is how long the digits and binary schemes show each
+	// character
+	DefaultCharacterMs = 1000
+	// maxEncodedLength is the longest text Encode shows
+	maxEncodedLength = 40
+	// maxBinaryNumber is the largest number both rings hold in binary
+	maxBinaryNumber = 1<<(2*device.RingLEDs) - 1
+)
+
+// morseCodes are the Morse codes of the characters the morse scheme shows
+var morseCodes = map[rune]string{
+	'A': ".-", 'B': "-...", 'C': "-.-.", 'D': "-..", 'E': ".", 'F': "..-.",
+	'G': "--.", 'H': "....", 'I': "..", 'J': ".---", 'K': "-.-", 'L': ".-..",
+	'M': "--", 'N': "-.", 'O': "---", 'P': ".--.", 'Q': "--.-", 'R': ".-.",
+	'S': "...", 'T': "-", 'U': "..-", 'V': "...-", 'W': ".--", 'X': "-..-",
+	'Y': "-.--", 'Z': "--..",
+	'0': "-----", '1': ".----", '2': "..---", '3': "...--", '4': "....-",
+	'5': ".....", '6': "-....", '7': "--...", '8': "---..", '9': "----.",
+	'.': ".-.-.-", ',': "--..--", '?': "..--..", '/': "-..-.", '-': "-....-",
+	':': "---...",
+}
+
+// digitSeparators are the characters the digits scheme shows as a pause
+const digitSeparators = ".-:/ "
+
+// EncodeOptions control how Encode shows text
+type EncodeOptions struct {
+	Scheme string // one of Schemes
+	Color  string // six digit hex color of lit LEDs
+	UnitMs int    // Morse dot length, or how long each character shows; 0 uses the scheme's default
+}
+
+// unitMs returns the time unit of the options' scheme
+func (o EncodeOptions) unitMs() (int, error) {
+	if !slices.Contains(Schemes, o.Scheme) {
+		return 0, fmt.Errorf("unknown scheme %q: use %s", o.Scheme, strings.Join(Schemes, ", "))
+	}
+	if o.UnitMs == 0 {
+		if o.Scheme == SchemeMorse {
+			return DefaultMorseUnitMs, nil
+		}
+		return DefaultCharacterMs, nil
+	}
+	if o.UnitMs < minStepMs {
+		return 0, fmt.Errorf("the time unit must be at least %dms, got %d", minStepMs, o.UnitMs)
+	}
+	return o.UnitMs, nil
+}
+
+// Encode returns the steps of one pass showing text, ending with a pause so
+// a repeat can be told apart. Morse shows letters, digits and .,?/-: on both
+// rings. The digits scheme shows one digit at a time on the top ring as that
+// many lit LEDs, 0 as ten like a rotary dial, and separators such as the
+// dots of an IP address as a longer pause. The binary scheme shows each
+// character's 7-bit code on the top ring, lowest bit at LED 0. Both mark
+// which character is showing with an LED on the bottom ring.
+func Encode(text string, opts EncodeOptions) ([]Step, error) {
+	unitMs, err := opts.unitMs()
+	if err != nil {
+		return nil, err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("nothing to show")
+	}
+	if len(text) > maxEncodedLength {
+		return nil, fmt.Errorf("text is %d characters; at most %d can be shown", len(text), maxEncodedLength)
+	}
+
+	var frames []device.Frame
+	var durations []int
+	show := func(frame device.Frame, ms int) {
+		frames = append(frames, frame)
+		durations = append(durations, ms)
+	}
+	dark := lit(lit(device.Frame{}, "top", nil, ""), "bottom", nil, "")
+
+	switch opts.Scheme {
+	case SchemeMorse:
+		on := lit(lit(dark, "top", allLEDs(), opts.Color), "bottom", allLEDs(), opts.Color)
+		for i, word := range strings.Fields(strings.ToUpper(text)) {
+			if i > 0 {
+				show(dark, 7*unitMs)
+			}
+			for j, char := range word {
+				code := morseCodes[char]
+				if code == "" {
+					return nil, fmt.Errorf("'%c' has no Morse code; use letters, digits and .,?/-:", char)
+				}
+				if j > 0 {
+					show(dark, 3*unitMs)
+				}
+				for k, symbol := range code {
+					if k > 0 {
+						show(dark, unitMs)
+					}
+					if symbol == '-' {
+						show(on, 3*unitMs)
+					} else {
+						show(on, unitMs)
+					}
+				}
+			}
+		}
+		show(dark, 7*unitMs)
+
+	case SchemeDigits, SchemeBinary:
+		gapMs := max(unitMs/4, minStepMs)
+		for i, char := range text {
+			frame := lit(dark, "bottom", []int{i % device.RingLEDs}, opts.Color)
+			switch {
+			case opts.Scheme == SchemeBinary:
+				if char < ' ' || char > '~' {
+					return nil, fmt.Errorf("'%c' is not a printable ASCII character", char)
+				}
+				frame = binaryRing(frame, "top", uint64(char), 7, opts.Color)
+			case char >= '0' && char <= '9':
+				count := int(char - '0')
+				if count == 0 {
+					count = 10
+				}
+				frame = lit(frame, "top", firstLEDs(count), opts.Color)
+			case strings.ContainsRune(digitSeparators, char):
+				show(dark, unitMs)
+				continue
+			default:
+				return nil, fmt.Errorf("the digits scheme shows only digits and the separators %q; use morse or binary for '%c'", digitSeparators, char)
+			}
+			show(frame, unitMs)
+			show(dark, gapMs)
+		}
+		show(dark, unitMs)
+	}
+	return frameSteps(frames, durations)
+}
+
+// EncodeNumber returns the steps showing a whole number. The binary scheme
+// shows all of it at once, lowest bit at LED 0 of the top ring and bits 15
+// and up on the bottom ring, with 0 bits dimmed. The other schemes show its
+// digits as Encode does.
+func EncodeNumber(n uint64, opts EncodeOptions) ([]Step, error) {
+	if opts.Scheme != SchemeBinary {
+		return Encode(strconv.FormatUint(n, 10), opts)
+	}
+	unitMs, err := opts.unitMs()
+	if err != nil {
+		return nil, err
+	}
+	if n > maxBinaryNumber {
+		return nil, fmt.Errorf("%d does not fit on the rings in binary; the largest is %d", n, maxBinaryNumber)
+	}
+	width := max(bits.Len64(n), 1)
+	frame := binaryRing(device.Frame{}, "top", n&(1<<device.RingLEDs-1), min(width, device.RingLEDs), opts.Color)
+	frame = binaryRing(frame, "bottom", n>>device.RingLEDs, max(width-device.RingLEDs, 0), opts.Color)
+	return frameSteps([]device.Frame{frame}, []int{5 * unitMs})
+}
+
+// lit returns frame with the ring's LEDs at positions lit in color and the
+// others off
+func lit(frame device.Frame, ring string, positions []int, color string) device.Frame {
+	leds := &frame.Top
+	if ring == "bottom" {
+		leds = &frame.Bottom
+	}
+	for i := range leds {
+		leds[i] = "000000"
+	}
+	for _, i := range positions {
+		leds[i] = color
+	}
+	return frame
+}
+
+// binaryRing returns frame with the ring showing the lowest width bits of
+// n, 1 bits in color and 0 bits dimmed, and its other LEDs off
+func binaryRing(frame device.Frame, ring string, n uint64, width int, color string) device.Frame {
+	frame = lit(frame, ring, nil, "")
+	leds := &frame.Top
+	if ring == "bottom" {
+		leds = &frame.Bottom
+	}
+	dim := device.MixColor(color, "000000", 0.85)
+	for i := 0; i < width; i++ {
+		if n>>i&1 == 1 {
+			leds[i] = color
+		} else {
+			leds[i] = dim
+		}
+	}
+	return frame
+}
+
+// firstLEDs returns the positions of the first n LEDs
+func firstLEDs(n int) []int {
+	positions := make([]int, n)
+	for i := range positions {
+		positions[i] = i
+	}
+	return positions
+}
+
+// allLEDs returns the position of every LED of a ring
+func allLEDs() []int {
+	return firstLEDs(device.RingLEDs)
+}
@@ -0,0 +1,144 @@
+package effects
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+)
+
+// play returns the frames steps show and how long each shows
+func play(t *testing.T, steps []Step) ([]device.Frame, []int) {
+	t.Helper()
+	var frames []device.Frame
+	var durations []int
+	var frame device.Frame
+	for i, step := range steps {
+		var err error
+		if frame, err = frame.Apply(step.Pattern); err != nil {
+			t.Fatalf("step %d rejected: %v", i, err)
+		}
+		frames = append(frames, frame)
+		durations = append(durations, step.DurationMs)
+	}
+	return frames, durations
+}
+
+// litLEDs counts the lit LEDs of a ring
+func litLEDs(leds [device.RingLEDs]string) int {
+	n := 0
+	for _, led := range leds {
+		if led != "000000" {
+			n++
+		}
+	}
+	return n
+}
+
+func TestEncode_Morse(t *testing.T) {
+	steps, err := Encode("et a", EncodeOptions{Scheme: SchemeMorse, Color: "FFFFFF", UnitMs: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ValidateSteps(steps); err != nil {
+		t.Errorf("encoded steps should be valid: %v", err)
+	}
+	frames, durations := play(t, steps)
+
+	// E (.), gap, T (-), word gap, A (.-), end pause
+	want := []struct {
+		on bool
+		ms int
+	}{{true, 100}, {false, 300}, {true, 300}, {false, 700}, {true, 100}, {false, 100}, {true, 300}, {false, 700}}
+	if len(frames) != len(want) {
+		t.Fatalf("expected %d steps, got %d: %+v", len(want), len(frames), steps)
+	}
+	for i, w := range want {
+		on := litLEDs(frames[i].Top) == device.RingLEDs && litLEDs(frames[i].Bottom) == device.RingLEDs
+		if on != w.on || durations[i] != w.ms {
+			t.Errorf("step %d: got on=%v for %dms, want on=%v for %dms", i, on, durations[i], w.on, w.ms)
+		}
+	}
+
+	if _, err := Encode("a#b", EncodeOptions{Scheme: SchemeMorse, Color: "FFFFFF"}); err == nil {
+		t.Error("expected an error for a character without a Morse code")
+	}
+}
+
+func TestEncode_Digits(t *testing.T) {
+	steps, err := Encode("10.3", EncodeOptions{Scheme: SchemeDigits, Color: "00FF00", UnitMs: 400})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	frames, durations := play(t, steps)
+
+	var shown []int
+	for i, frame := range frames {
+		if n := litLEDs(frame.Top); n > 0 {
+			shown = append(shown, n)
+			if durations[i] != 400 || litLEDs(frame.Bottom) != 1 {
+				t.Errorf("step %d: expected a digit for 400ms with a position LED, got %dms, %v", i, durations[i], frame.Bottom)
+			}
+		}
+	}
+	if len(shown) != 3 || shown[0] != 1 || shown[1] != 10 || shown[2] != 3 {
+		t.Errorf("expected 1, 10 (for 0) and 3 lit LEDs, got %v", shown)
+	}
+	// The separator is a pause longer than the gap between digits
+	if durations[3] != 100+400 {
+		t.Errorf("expected a 500ms pause for the dot, got %v", durations)
+	}
+
+	if _, err := Encode("B12", EncodeOptions{Scheme: SchemeDigits, Color: "00FF00"}); err == nil {
+		t.Error("expected an error for a letter in the digits scheme")
+	}
+}
+
+func TestEncode_Binary(t *testing.T) {
+	steps, err := Encode("A", EncodeOptions{Scheme: SchemeBinary, Color: "FF0000"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	frames, _ := play(t, steps)
+	// A is 1000001
+	top := frames[0].Top
+	if top[0] != "ff0000" || top[6] != "ff0000" || top[1] != "260000" || top[7] != "000000" {
+		t.Errorf("expected the bits of 'A', got %v", top)
+	}
+
+	steps, err = EncodeNumber(1<<15|5, EncodeOptions{Scheme: SchemeBinary, Color: "FF0000"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(steps) != 1 || steps[0].DurationMs != 5*DefaultCharacterMs {
+		t.Fatalf("expected one step for a binary number, got %+v", steps)
+	}
+	frames, _ = play(t, steps)
+	if frames[0].Top[0] != "ff0000" || frames[0].Top[1] != "260000" || frames[0].Top[2] != "ff0000" || frames[0].Bottom[0] != "ff0000" || frames[0].Bottom[1] != "000000" {
+		t.Errorf("expected 5 on the top ring and bit 15 on the bottom, got %v", frames[0])
+	}
+
+	if _, err := EncodeNumber(1<<30, EncodeOptions{Scheme: SchemeBinary, Color: "FF0000"}); err == nil {
+		t.Error("expected an error for a number too large for the rings")
+	}
+	if steps, err := EncodeNumber(42, EncodeOptions{Scheme: SchemeDigits, Color: "FF0000"}); err != nil || !strings.Contains(steps[0].Pattern, "top=0|4|FF0000") {
+		t.Errorf("expected the digits of 42, got %+v, %v", steps, err)
+	}
+}
+
+func TestEncode_Options(t *testing.T) {
+	for _, opts := range []EncodeOptions{
+		{Scheme: "semaphore", Color: "FFFFFF"},
+		{Scheme: SchemeMorse, Color: "FFFFFF", UnitMs: 10},
+	} {
+		if _, err := Encode("1", opts); err == nil {
+			t.Errorf("expected an error for %+v", opts)
+		}
+	}
+	if _, err := Encode("  ", EncodeOptions{Scheme: SchemeMorse, Color: "FFFFFF"}); err == nil {
+		t.Error("expected an error for blank text")
+	}
+	if _, err := Encode(strings.Repeat("1", maxEncodedLength+1), EncodeOptions{Scheme: SchemeDigits, Color: "FFFFFF"}); err == nil {
+		t.Error("expected an error for text too long")
+	}
+}
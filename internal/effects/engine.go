@@ -0,0 +1,230 @@
+package effects
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// minStepMs is the shortest allowed frame duration, to avoid flooding the UFO
+const minStepMs = 50
+
+// Step is a single frame of a multi-step effect
+type Step struct {
+	Pattern    string `json:"pattern"`    // UFO API query sent for this frame
+	DurationMs int    `json:"durationMs"` // how long the frame is shown
+}
+
+// ValidateSteps checks that every step has a pattern and a usable duration
+func ValidateSteps(steps []Step) error {
+	for i, step := range steps {
+		if step.Pattern == "" {
+			return fmt.Errorf("step %d: pattern is required", i)
+		}
+		if step.DurationMs < minStepMs {
+			return fmt.Errorf("step %d: durationMs must be at least %d", i, minStepMs)
+		}
+	}
+	return nil
+}
+
+// Sender sends a raw query to the UFO
+type Sender interface {
+	SendRawQuery(ctx context.Context, query string) (string, error)
+}
+
+// Engine animates multi-step effects by cycling through their frames in a
+// background goroutine. Only one animation runs at a time; starting another
+// or stopping cancels the current one. The logo has an animation of its own
+// that runs alongside. It also tracks the timers that end timed effects, so
+// shutdown can cancel them.
+type Engine struct {
+	sender Sender
+	apply  sync.Mutex // held by ApplyWithReply from stopping the animation to starting the next
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+	running string
+
+	logoCancel context.CancelFunc
+	logoDone   chan struct{}
+
+	lifetime    context.Context // cancelled by Shutdown
+	end         context.CancelFunc
+	timers      sync.WaitGroup
+	timerCount  int
+	closed      bool
+	callTimeout time.Duration // longest a query may take, retries included; 0 for no limit
+}
+
+// NewEngine creates a new effect engine
+func NewEngine(sender Sender) *Engine {
+	lifetime, end := context.WithCancel(context.Background())
+	return &Engine{sender: sender, lifetime: lifetime, end: end, callTimeout: DefaultCallTimeout}
+}
+
+// Apply shows an effect on the UFO. Multi-step effects are animated in the
+// background until stopped; single patterns are sent once after stopping any
+// running animation. The first frame is sent synchronously so device errors
+// reach the caller.
+func (e *Engine) Apply(ctx context.Context, name, pattern string, steps []Step) error {
+	_, err := e.ApplyWithReply(ctx, name, pattern, steps)
+	return err
+}
+
+// ApplyWithReply is Apply, also returning the UFO's reply to the first frame
+// so callers can inspect what the firmware answered
+func (e *Engine) ApplyWithReply(ctx context.Context, name, pattern string, steps []Step) (string, error) {
+	// Without this, two effects applied at once could both start animating,
+	// leaving one that Stop can no longer reach
+	e.apply.Lock()
+	defer e.apply.Unlock()
+	e.Stop()
+
+	if len(steps) == 0 {
+		return e.send(ctx, pattern)
+	}
+
+	reply, err := e.send(ctx, steps[0].Pattern)
+	if err != nil {
+		return "", err
+	}
+
+	animCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	e.mu.Lock()
+	e.cancel = cancel
+	e.done = done
+	e.running = name
+	e.mu.Unlock()
+
+	go e.animate(animCtx, done, name, steps)
+	return reply, nil
+}
+
+// Stop cancels the running animation, if any, and waits for it to exit
+func (e *Engine) Stop() {
+	e.mu.Lock()
+	cancel, done := e.cancel, e.done
+	e.cancel, e.done, e.running = nil, nil, ""
+	e.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// Animate runs fn in the background as the engine's animation, in place of
+// the running one, for animations that are not a list of steps such as a
+// crossfade. fn's context keeps ctx's values but not its cancellation; it
+// is cancelled like any animation by Stop and so by the next Apply. It runs
+// as a timer started with Go, so Shutdown cancels it and waits for it too.
+// fn must not call Stop or Apply itself. Returns false without running fn
+// once the engine has shut down.
+func (e *Engine) Animate(ctx context.Context, name string, fn func(ctx context.Context)) bool {
+	e.apply.Lock()
+	defer e.apply.Unlock()
+	e.Stop()
+
+	done := make(chan struct{})
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	cancel, started := e.goLocked(ctx, func(ctx context.Context) {
+		defer close(done)
+		fn(ctx)
+
+		// Finished on its own; a later animation may have replaced it
+		e.mu.Lock()
+		if e.done == done {
+			e.cancel, e.done, e.running = nil, nil, ""
+		}
+		e.mu.Unlock()
+	})
+	if !started {
+		return false
+	}
+	e.cancel = cancel
+	e.done = done
+	e.running = name
+	return true
+}
+
+// Overlay sends a query without stopping the running animation. It is for
+// queries that only touch rings the animation leaves alone.
+func (e *Engine) Overlay(ctx context.Context, query string) error {
+	_, err := e.send(ctx, query)
+	return err
+}
+
+// AnimateLogo cycles the logo through steps in the background, alongside
+// any ring animation, until StopLogo is called or another logo animation
+// starts. The caller has already sent the first frame.
+func (e *Engine) AnimateLogo(steps []Step) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	e.mu.Lock()
+	previous, previousDone := e.logoCancel, e.logoDone
+	e.logoCancel, e.logoDone = cancel, done
+	e.mu.Unlock()
+
+	if previous != nil {
+		previous()
+		<-previousDone
+	}
+	go e.animate(ctx, done, "logo", steps)
+}
+
+// StopLogo cancels the logo animation, if any, and waits for it to exit
+func (e *Engine) StopLogo() {
+	e.mu.Lock()
+	cancel, done := e.logoCancel, e.logoDone
+	e.logoCancel, e.logoDone = nil, nil
+	e.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// Running returns the name of the animated effect, or "" if none
+func (e *Engine) Running() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.running
+}
+
+// animate loops over the steps until ctx is cancelled. The first frame has
+// already been sent by Apply.
+func (e *Engine) animate(ctx context.Context, done chan struct{}, name string, steps []Step) {
+	defer close(done)
+
+	i := 0
+	for {
+		timer := time.NewTimer(time.Duration(steps[i].DurationMs) * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		i = (i + 1) % len(steps)
+		if _, err := e.send(ctx, steps[i].Pattern); err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "Effect step failed", "effect", name, "step", i, "error", err)
+		}
+	}
+}
+
+// StepsFromContext returns the steps recorded in an effect stack entry's
+// context, so a multi-step effect can be resumed after being covered
+func StepsFromContext(context map[string]interface{}) []Step {
+	steps, _ := context["steps"].([]Step)
+	return steps
+}
@@ -0,0 +1,189 @@
+package effects
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSender records every query sent to it
+type recordingSender struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+func (r *recordingSender) SendRawQuery(ctx context.Context, query string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = append(r.queries, query)
+	return "OK", nil
+}
+
+func (r *recordingSender) sent() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.queries...)
+}
+
+func TestEngine_AnimatesSteps(t *testing.T) {
+	sender := &recordingSender{}
+	engine := NewEngine(sender)
+
+	steps := []Step{
+		{Pattern: "top_bg=ff0000", DurationMs: 50},
+		{Pattern: "top_bg=0000ff", DurationMs: 50},
+	}
+	if err := engine.Apply(context.Background(), "police", "", steps); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if engine.Running() != "police" {
+		t.Errorf("Expected police to be running, got %q", engine.Running())
+	}
+
+	time.Sleep(180 * time.Millisecond)
+	engine.Stop()
+
+	sent := sender.sent()
+	if len(sent) < 3 {
+		t.Fatalf("Expected at least 3 frames, got %v", sent)
+	}
+	for i, query := range sent {
+		if query != steps[i%2].Pattern {
+			t.Errorf("Frame %d: expected %s, got %s", i, steps[i%2].Pattern, query)
+		}
+	}
+
+	// No frames are sent after stopping
+	time.Sleep(100 * time.Millisecond)
+	if len(sender.sent()) != len(sent) {
+		t.Error("Frames were sent after Stop")
+	}
+	if engine.Running() != "" {
+		t.Errorf("Expected no running effect, got %q", engine.Running())
+	}
+}
+
+func TestEngine_StaticPatternStopsAnimation(t *testing.T) {
+	sender := &recordingSender{}
+	engine := NewEngine(sender)
+
+	engine.Apply(context.Background(), "blink", "", []Step{
+		{Pattern: "a", DurationMs: 50},
+		{Pattern: "b", DurationMs: 50},
+	})
+	if err := engine.Apply(context.Background(), "solid", "top_bg=00ff00", nil); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	count := len(sender.sent())
+	time.Sleep(120 * time.Millisecond)
+	if len(sender.sent()) != count {
+		t.Error("Animation kept running after a static pattern was applied")
+	}
+	if sent := sender.sent(); sent[len(sent)-1] != "top_bg=00ff00" {
+		t.Errorf("Expected static pattern last, got %v", sent)
+	}
+}
+
+func TestEngine_ConcurrentApplyLeavesOneAnimation(t *testing.T) {
+	sender := &recordingSender{}
+	engine := NewEngine(sender)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			engine.Apply(context.Background(), "blink", "", []Step{
+				{Pattern: "a", DurationMs: 10},
+				{Pattern: "b", DurationMs: 10},
+			})
+		}()
+	}
+	wg.Wait()
+	engine.Stop()
+
+	count := len(sender.sent())
+	time.Sleep(60 * time.Millisecond)
+	if len(sender.sent()) != count {
+		t.Error("An animation kept running after Stop")
+	}
+}
+
+func TestEngine_Animate(t *testing.T) {
+	sender := &recordingSender{}
+	engine := NewEngine(sender)
+
+	// Applying an effect cancels the animation before sending
+	cancelled := make(chan struct{})
+	engine.Animate(context.Background(), "fade", func(ctx context.Context) {
+		<-ctx.Done()
+		close(cancelled)
+	})
+	if engine.Running() != "fade" {
+		t.Errorf("Expected fade to be running, got %q", engine.Running())
+	}
+	if err := engine.Apply(context.Background(), "static", "top_bg=ff0000", nil); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	select {
+	case <-cancelled:
+	default:
+		t.Error("Expected the animation to end before Apply returned")
+	}
+
+	// An animation that finishes on its own leaves nothing running
+	engine.Animate(context.Background(), "short", func(ctx context.Context) {})
+	deadline := time.Now().Add(time.Second)
+	for engine.Running() != "" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if engine.Running() != "" {
+		t.Errorf("Expected no running effect, got %q", engine.Running())
+	}
+}
+
+func TestValidateSteps(t *testing.T) {
+	if err := ValidateSteps([]Step{{Pattern: "top_bg=ff0000", DurationMs: 500}}); err != nil {
+		t.Errorf("Expected valid steps, got %v", err)
+	}
+	if err := ValidateSteps([]Step{{Pattern: "", DurationMs: 500}}); err == nil {
+		t.Error("Expected error for empty pattern")
+	}
+	if err := ValidateSteps([]Step{{Pattern: "top_bg=ff0000", DurationMs: 10}}); err == nil {
+		t.Error("Expected error for too short duration")
+	}
+}
+
+func TestEngine_AnimateLogoAlongsideEffect(t *testing.T) {
+	sender := &recordingSender{}
+	engine := NewEngine(sender)
+
+	engine.AnimateLogo([]Step{
+		{Pattern: "logo=ff0000", DurationMs: 50},
+		{Pattern: "logo=000000", DurationMs: 50},
+	})
+	// Applying an effect leaves the logo animation running
+	if err := engine.Apply(context.Background(), "", "top_init=1", nil); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	time.Sleep(180 * time.Millisecond)
+	engine.StopLogo()
+
+	var logoFrames int
+	for _, query := range sender.sent() {
+		if query == "logo=ff0000" || query == "logo=000000" {
+			logoFrames++
+		}
+	}
+	if logoFrames < 2 {
+		t.Fatalf("Expected logo frames after the effect was applied, got %v", sender.sent())
+	}
+
+	sent := len(sender.sent())
+	time.Sleep(100 * time.Millisecond)
+	if len(sender.sent()) != sent {
+		t.Error("Logo frames were sent after StopLogo")
+	}
+}
@@ -0,0 +1,110 @@
+package effects
+
+import (
+	"sort"
+	"strings"
+)
+
+// Filter selects effects from the store. Zero fields select everything.
+type Filter struct {
+	Tag      string // effects carrying this tag
+	Category string // effects in this category
+	Search   string // text in the name, description, category or tags
+	Offset   int    // matches to skip, for paging
+	Limit    int    // most matches to return; 0 returns all
+}
+
+// matches reports whether an effect passes the filter's tag, category and
+// search text, all compared without case
+func (f Filter) matches(effect *Effect) bool {
+	if f.Category != "" && !strings.EqualFold(effect.Category, strings.TrimSpace(f.Category)) {
+		return false
+	}
+	if f.Tag != "" && !effect.HasTag(f.Tag) {
+		return false
+	}
+	search := strings.ToLower(strings.TrimSpace(f.Search))
+	if search == "" {
+		return true
+	}
+	for _, text := range append([]string{effect.Name, effect.Description, effect.Category}, effect.Tags...) {
+		if strings.Contains(strings.ToLower(text), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTag reports whether the effect carries a tag, compared without case
+func (e *Effect) HasTag(tag string) bool {
+	tag = strings.TrimSpace(tag)
+	for _, own := range e.Tags {
+		if strings.EqualFold(own, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns one page of the effects passing filter, ordered by name,
+// and how many matched in all
+func (s *Store) Find(filter Filter) ([]*Effect, int) {
+	s.mu.RLock()
+	matched := make([]*Effect, 0, len(s.effects))
+	for _, effect := range s.effects {
+		if filter.matches(effect) {
+			matched = append(matched, effect)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Name < matched[j].Name })
+	total := len(matched)
+	if filter.Offset > 0 {
+		if filter.Offset >= total {
+			return []*Effect{}, total
+		}
+		matched = matched[filter.Offset:]
+	}
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+	}
+	return matched, total
+}
+
+// Labels counts the effects in each category and carrying each tag, so
+// clients can discover what to filter by
+func (s *Store) Labels() (categories, tags map[string]int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	categories, tags = make(map[string]int), make(map[string]int)
+	for _, effect := range s.effects {
+		if effect.Category != "" {
+			categories[effect.Category]++
+		}
+		for _, tag := range effect.Tags {
+			tags[tag]++
+		}
+	}
+	return categories, tags
+}
+
+// normalizeLabels trims the category and tags and lowercases the tags,
+// dropping empty and repeated ones
+func normalizeLabels(effect *Effect) {
+	effect.Category = strings.TrimSpace(effect.Category)
+	if effect.Tags == nil {
+		return
+	}
+	tags := make([]string, 0, len(effect.Tags))
+	seen := make(map[string]bool)
+	for _, tag := range effect.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	effect.Tags = tags
+}
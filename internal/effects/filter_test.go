@@ -0,0 +1,93 @@
+package effects
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func labelledStore(t *testing.T) *Store {
+	t.Helper()
+	store := NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	for _, effect := range []*Effect{
+		{Name: "redAlert", Description: "Flashing red", Pattern: "top=0|15|FF0000", Category: "alerts", Tags: []string{"Red", " incident ", "red"}},
+		{Name: "calmBlue", Description: "Slow blue whirl", Pattern: "top=0|15|0000FF", Category: " ambient ", Tags: []string{"blue", "calm"}},
+		{Name: "buildPassed", Description: "Green for a passing build", Pattern: "top=0|15|00FF00", Category: "status", Tags: []string{"green", "ci"}},
+		{Name: "amberWarning", Description: "Amber pulse", Pattern: "top=0|15|FFBF00", Category: "alerts", Tags: []string{"amber"}},
+	} {
+		if err := store.Add(effect); err != nil {
+			t.Fatalf("adding %s: %v", effect.Name, err)
+		}
+	}
+	return store
+}
+
+func TestStore_AddNormalizesLabels(t *testing.T) {
+	store := labelledStore(t)
+
+	effect, _ := store.Get("redAlert")
+	if len(effect.Tags) != 2 || effect.Tags[0] != "red" || effect.Tags[1] != "incident" {
+		t.Errorf("expected tags [red incident], got %v", effect.Tags)
+	}
+	effect, _ = store.Get("calmBlue")
+	if effect.Category != "ambient" {
+		t.Errorf("expected category trimmed, got %q", effect.Category)
+	}
+
+	// Labels survive a reload
+	reloaded := NewStore(store.file)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("reloading: %v", err)
+	}
+	effect, _ = reloaded.Get("buildPassed")
+	if effect.Category != "status" || !effect.HasTag("CI") {
+		t.Errorf("expected labels after reload, got %q %v", effect.Category, effect.Tags)
+	}
+}
+
+func TestStore_Find(t *testing.T) {
+	store := labelledStore(t)
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+		total  int
+	}{
+		{"everything by name", Filter{}, []string{"amberWarning", "buildPassed", "calmBlue", "redAlert"}, 4},
+		{"category ignores case", Filter{Category: "ALERTS"}, []string{"amberWarning", "redAlert"}, 2},
+		{"tag", Filter{Tag: "calm"}, []string{"calmBlue"}, 1},
+		{"search description", Filter{Search: "build"}, []string{"buildPassed"}, 1},
+		{"search tags", Filter{Search: "incident"}, []string{"redAlert"}, 1},
+		{"combined", Filter{Category: "alerts", Tag: "red"}, []string{"redAlert"}, 1},
+		{"no match", Filter{Tag: "purple"}, []string{}, 0},
+		{"first page", Filter{Limit: 2}, []string{"amberWarning", "buildPassed"}, 4},
+		{"second page", Filter{Offset: 2, Limit: 2}, []string{"calmBlue", "redAlert"}, 4},
+		{"past the end", Filter{Offset: 10}, []string{}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, total := store.Find(tt.filter)
+			if total != tt.total {
+				t.Errorf("expected total %d, got %d", tt.total, total)
+			}
+			if len(found) != len(tt.want) {
+				t.Fatalf("expected %v, got %d effects", tt.want, len(found))
+			}
+			for i, effect := range found {
+				if effect.Name != tt.want[i] {
+					t.Errorf("expected %v, got %s at %d", tt.want, effect.Name, i)
+				}
+			}
+		})
+	}
+}
+
+func TestStore_Labels(t *testing.T) {
+	categories, tags := labelledStore(t).Labels()
+	if categories["alerts"] != 2 || categories["ambient"] != 1 || len(categories) != 3 {
+		t.Errorf("unexpected categories %v", categories)
+	}
+	if tags["red"] != 1 || tags["calm"] != 1 || len(tags) != 7 {
+		t.Errorf("unexpected tags %v", tags)
+	}
+}
@@ -0,0 +1,115 @@
+package effects
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultCallTimeout is how long a query the engine sends may take, retries
+// included, before it is abandoned
+const DefaultCallTimeout = 30 * time.Second
+
+// SetCallTimeout limits how long each query the engine sends may take,
+// retries and queueing included, 0 for no limit. Timers restoring effects
+// and animations send through the engine, so a UFO that stops answering
+// cannot hold them past the limit.
+func (e *Engine) SetCallTimeout(timeout time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.callTimeout = timeout
+}
+
+// send sends a query to the UFO within the call timeout
+func (e *Engine) send(ctx context.Context, query string) (string, error) {
+	e.mu.Lock()
+	timeout := e.callTimeout
+	e.mu.Unlock()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return e.sender.SendRawQuery(ctx, query)
+}
+
+// Go runs fn in a background goroutine tracked by the engine, for timers
+// that end or advance effects. fn's context keeps ctx's values, such as the
+// request ID, but not its cancellation; it is cancelled by Shutdown. Returns
+// false without running fn once the engine has shut down.
+func (e *Engine) Go(ctx context.Context, fn func(ctx context.Context)) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, started := e.goLocked(ctx, fn)
+	return started
+}
+
+// goLocked is Go for a caller holding e.mu, also returning the function
+// that cancels fn's context
+func (e *Engine) goLocked(ctx context.Context, fn func(ctx context.Context)) (context.CancelFunc, bool) {
+	if e.closed {
+		return nil, false
+	}
+
+	timerCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	e.timers.Add(1)
+	e.timerCount++
+	stop := context.AfterFunc(e.lifetime, cancel)
+	go func() {
+		defer func() {
+			stop()
+			cancel()
+			e.mu.Lock()
+			e.timerCount--
+			e.mu.Unlock()
+			e.timers.Done()
+		}()
+		fn(timerCtx)
+	}()
+	return cancel, true
+}
+
+// Timers returns the number of timers started with Go that are still running
+func (e *Engine) Timers() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.timerCount
+}
+
+// Shutdown stops the running animations and cancels every timer started with
+// Go, then waits for them to return or for ctx to be done. Effects can still
+// be applied afterwards, so the UFO can be left in a known state on exit.
+func (e *Engine) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	e.closed = true
+	e.mu.Unlock()
+	e.end()
+
+	done := make(chan struct{})
+	go func() {
+		e.timers.Wait()
+		close(done)
+	}()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	// Stop last, in case a timer restored an animated effect as it ended
+	e.Stop()
+	e.StopLogo()
+	return err
+}
+
+// Sleep pauses for d, returning false early if ctx is cancelled first
+func Sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
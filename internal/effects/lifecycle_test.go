@@ -0,0 +1,156 @@
+package effects
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type requestKey struct{}
+
+func TestEngine_ShutdownCancelsTimers(t *testing.T) {
+	sender := &recordingSender{}
+	engine := NewEngine(sender)
+
+	// Timers keep the caller's values but not its cancellation
+	callerCtx, cancelCaller := context.WithCancel(context.WithValue(context.Background(), requestKey{}, "req-1"))
+	finished := make(chan bool, 1)
+	started := engine.Go(callerCtx, func(ctx context.Context) {
+		if ctx.Value(requestKey{}) != "req-1" {
+			t.Error("timer context lost the caller's values")
+		}
+		finished <- Sleep(ctx, time.Hour)
+	})
+	if !started {
+		t.Fatal("Go refused a timer before shutdown")
+	}
+	cancelCaller()
+	if engine.Timers() != 1 {
+		t.Errorf("expected 1 running timer, got %d", engine.Timers())
+	}
+
+	steps := []Step{{Pattern: "top_bg=ff0000", DurationMs: 50}, {Pattern: "top_bg=0000ff", DurationMs: 50}}
+	if err := engine.Apply(context.Background(), "police", "", steps); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := engine.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	select {
+	case completed := <-finished:
+		if completed {
+			t.Error("timer slept through shutdown")
+		}
+	default:
+		t.Fatal("Shutdown returned before the timer did")
+	}
+	if engine.Timers() != 0 || engine.Running() != "" {
+		t.Errorf("expected no timers or animation after shutdown, got %d and %q", engine.Timers(), engine.Running())
+	}
+	if engine.Go(context.Background(), func(context.Context) { t.Error("timer ran after shutdown") }) {
+		t.Error("Go accepted a timer after shutdown")
+	}
+
+	// The UFO can still be set on the way out
+	if err := engine.Apply(context.Background(), "", "top_init=1&bottom_init=1", nil); err != nil {
+		t.Errorf("Apply after shutdown failed: %v", err)
+	}
+}
+
+func TestEngine_ShutdownCancelsAnimate(t *testing.T) {
+	engine := NewEngine(&recordingSender{})
+
+	finished := make(chan bool, 1)
+	if !engine.Animate(context.Background(), "fade", func(ctx context.Context) {
+		finished <- Sleep(ctx, time.Hour)
+	}) {
+		t.Fatal("Animate refused an animation before shutdown")
+	}
+	if engine.Timers() != 1 {
+		t.Errorf("expected the animation counted as a timer, got %d", engine.Timers())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := engine.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	select {
+	case completed := <-finished:
+		if completed {
+			t.Error("animation slept through shutdown")
+		}
+	default:
+		t.Fatal("Shutdown returned before the animation did")
+	}
+	if engine.Animate(context.Background(), "fade", func(context.Context) { t.Error("animation ran after shutdown") }) {
+		t.Error("Animate accepted an animation after shutdown")
+	}
+	if engine.Running() != "" {
+		t.Errorf("expected no animation after shutdown, got %q", engine.Running())
+	}
+}
+
+func TestEngine_ShutdownTimeout(t *testing.T) {
+	engine := NewEngine(&recordingSender{})
+	release := make(chan struct{})
+	engine.Go(context.Background(), func(context.Context) { <-release })
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := engine.Shutdown(ctx); err == nil {
+		t.Error("expected Shutdown to report a timer that ignores cancellation")
+	}
+}
+
+func TestSleep(t *testing.T) {
+	if !Sleep(context.Background(), time.Millisecond) {
+		t.Error("Sleep should report the full wait")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if Sleep(ctx, time.Hour) {
+		t.Error("Sleep should return early when cancelled")
+	}
+}
+
+// hungSender never answers, like a UFO that stopped responding
+type hungSender struct{}
+
+func (hungSender) SendRawQuery(ctx context.Context, query string) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestEngine_CallTimeout(t *testing.T) {
+	engine := NewEngine(hungSender{})
+	engine.SetCallTimeout(50 * time.Millisecond)
+
+	start := time.Now()
+	err := engine.Apply(context.Background(), "solid", "top_bg=ff0000", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the query to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the query abandoned after the timeout, took %s", elapsed)
+	}
+
+	// A timer restoring an effect is not held by the UFO either
+	done := make(chan error, 1)
+	engine.Go(context.Background(), func(ctx context.Context) {
+		done <- engine.Overlay(ctx, "bottom_bg=0000ff")
+	})
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the timer's query to time out, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timer still waiting on the UFO")
+	}
+}
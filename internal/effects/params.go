@@ -0,0 +1,201 @@
+package effects
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+)
+
+// Param is a named placeholder in an effect's patterns, written {name}, that
+// is filled in when the effect is played
+type Param struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"` // used when playEffect gives no value; required when empty
+}
+
+// placeholderPattern matches {name} placeholders in a pattern
+var placeholderPattern = regexp.MustCompile(`\{([A-Za-z][A-Za-z0-9_]*)\}`)
+
+// paramNamePattern is the allowed form of a parameter name
+var paramNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// Placeholders returns the names of the placeholders used in the effect's
+// pattern and steps, sorted and without duplicates
+func (e *Effect) Placeholders() []string {
+	seen := map[string]bool{}
+	for _, pattern := range e.patterns() {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(pattern, -1) {
+			seen[match[1]] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// patterns returns the static pattern and every step pattern
+func (e *Effect) patterns() []string {
+	patterns := []string{e.Pattern}
+	for _, step := range e.Steps {
+		patterns = append(patterns, step.Pattern)
+	}
+	return patterns
+}
+
+// ValidateParams checks that parameter names are well formed and unique and
+// that every placeholder in the effect's patterns is declared
+func ValidateParams(effect *Effect) error {
+	declared := map[string]bool{}
+	for _, param := range effect.Params {
+		if !paramNamePattern.MatchString(param.Name) {
+			return fmt.Errorf("parameter name '%s' must start with a letter and contain only letters, numbers and underscores", param.Name)
+		}
+		if declared[param.Name] {
+			return fmt.Errorf("parameter '%s' is declared twice", param.Name)
+		}
+		if err := validateParamValue(param.Default); err != nil {
+			return fmt.Errorf("parameter '%s' default: %w", param.Name, err)
+		}
+		declared[param.Name] = true
+	}
+	for _, name := range effect.Placeholders() {
+		if !declared[name] {
+			return fmt.Errorf("placeholder {%s} is not a declared parameter", name)
+		}
+	}
+	return nil
+}
+
+// ValidatePatterns checks the effect's pattern and steps against the UFO
+// query grammar. Placeholders are filled in with their defaults; a parameter
+// whose value still holds a placeholder is only checked by name, since its
+// value is known when the effect is played.
+func ValidatePatterns(effect *Effect) error {
+	defaults := make(map[string]string, len(effect.Params))
+	for _, param := range effect.Params {
+		if param.Default != "" {
+			defaults[param.Name] = param.Default
+		}
+	}
+
+	for i, pattern := range effect.patterns() {
+		where := "pattern"
+		if i > 0 {
+			where = fmt.Sprintf("step %d", i-1)
+		}
+		if pattern == "" {
+			if i == 0 && len(effect.Steps) > 0 {
+				continue // the steps are shown instead
+			}
+			return fmt.Errorf("%s is empty", where)
+		}
+
+		var checked []string
+		for _, part := range strings.Split(pattern, "&") {
+			filled := placeholderPattern.ReplaceAllStringFunc(part, func(placeholder string) string {
+				if value, ok := defaults[placeholder[1:len(placeholder)-1]]; ok {
+					return value
+				}
+				return placeholder
+			})
+			if !placeholderPattern.MatchString(filled) {
+				checked = append(checked, filled)
+				continue
+			}
+			if name, _, _ := strings.Cut(filled, "="); !device.IsPatternParam(name) {
+				return fmt.Errorf("%s: %s is not a UFO parameter", where, name)
+			}
+		}
+		if len(checked) == 0 {
+			continue
+		}
+		if err := device.ValidatePattern(strings.Join(checked, "&")); err != nil {
+			return fmt.Errorf("%s: %w", where, err)
+		}
+	}
+	return nil
+}
+
+// Render returns a copy of the effect with its placeholders replaced by
+// values, falling back to each parameter's default. Unknown values and
+// parameters left without a value are errors.
+func (e *Effect) Render(values map[string]string) (*Effect, error) {
+	resolved := make(map[string]string, len(e.Params))
+	for _, param := range e.Params {
+		resolved[param.Name] = param.Default
+	}
+
+	var unknown []string
+	for name, value := range values {
+		if _, declared := resolved[name]; !declared {
+			unknown = append(unknown, name)
+			continue
+		}
+		if err := validateParamValue(value); err != nil {
+			return nil, fmt.Errorf("parameter '%s': %w", name, err)
+		}
+		resolved[name] = value
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("effect '%s' has no parameter named %s", e.Name, strings.Join(unknown, ", "))
+	}
+
+	var missing []string
+	for _, name := range e.Placeholders() {
+		if resolved[name] == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("effect '%s' needs a value for %s", e.Name, strings.Join(missing, ", "))
+	}
+
+	// Parameters that held a placeholder were only checked by name when the
+	// effect was saved, so check their values now
+	var invalid error
+	substitute := func(pattern string) string {
+		parts := strings.Split(pattern, "&")
+		for i, part := range parts {
+			if !placeholderPattern.MatchString(part) {
+				continue
+			}
+			parts[i] = placeholderPattern.ReplaceAllStringFunc(part, func(placeholder string) string {
+				return resolved[placeholder[1:len(placeholder)-1]]
+			})
+			if err := device.ValidatePattern(parts[i]); err != nil && invalid == nil {
+				invalid = err
+			}
+		}
+		return strings.Join(parts, "&")
+	}
+
+	rendered := *e
+	rendered.Pattern = substitute(e.Pattern)
+	if len(e.Steps) > 0 {
+		rendered.Steps = make([]Step, len(e.Steps))
+		for i, step := range e.Steps {
+			rendered.Steps[i] = Step{Pattern: substitute(step.Pattern), DurationMs: step.DurationMs}
+		}
+	}
+	if invalid != nil {
+		return nil, fmt.Errorf("effect '%s' with these values: %w", e.Name, invalid)
+	}
+	return &rendered, nil
+}
+
+// validateParamValue rejects values that would add or break query
+// parameters when substituted into a pattern
+func validateParamValue(value string) error {
+	if strings.ContainsAny(value, "&=?# \t\r\n") {
+		return fmt.Errorf("value %q must not contain '&', '=', '?', '#' or whitespace", value)
+	}
+	return nil
+}
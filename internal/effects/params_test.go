@@ -0,0 +1,161 @@
+package effects
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEffect_Render(t *testing.T) {
+	effect := &Effect{
+		Name:    "pulse",
+		Pattern: "top_init=1&top=0|15|{color}&top_morph={speed}|5",
+		Steps: []Step{
+			{Pattern: "top=0|15|{color}", DurationMs: 200},
+			{Pattern: "top=0|15|000000", DurationMs: 200},
+		},
+		Params: []Param{
+			{Name: "color", Description: "hex color"},
+			{Name: "speed", Default: "300"},
+		},
+	}
+
+	rendered, err := effect.Render(map[string]string{"color": "FF0000"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rendered.Pattern != "top_init=1&top=0|15|FF0000&top_morph=300|5" {
+		t.Errorf("unexpected pattern %q", rendered.Pattern)
+	}
+	if rendered.Steps[0].Pattern != "top=0|15|FF0000" || rendered.Steps[0].DurationMs != 200 {
+		t.Errorf("unexpected first step %+v", rendered.Steps[0])
+	}
+	if effect.Pattern != "top_init=1&top=0|15|{color}&top_morph={speed}|5" || effect.Steps[0].Pattern != "top=0|15|{color}" {
+		t.Error("rendering must not modify the stored effect")
+	}
+
+	if _, err := effect.Render(nil); err == nil || !strings.Contains(err.Error(), "color") {
+		t.Errorf("expected missing color error, got %v", err)
+	}
+	if _, err := effect.Render(map[string]string{"color": "FF0000", "size": "3"}); err == nil || !strings.Contains(err.Error(), "size") {
+		t.Errorf("expected unknown parameter error, got %v", err)
+	}
+	if _, err := effect.Render(map[string]string{"color": "FF0000&dim=0"}); err == nil {
+		t.Error("expected values that add query parameters to be rejected")
+	}
+
+	plain := &Effect{Name: "plain", Pattern: "top=0|15|FF0000"}
+	rendered, err = plain.Render(nil)
+	if err != nil || rendered.Pattern != plain.Pattern {
+		t.Errorf("expected effects without parameters to render unchanged, got %v, %v", rendered, err)
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		effect  Effect
+		wantErr string
+	}{
+		{"declared", Effect{Pattern: "top=0|15|{color}", Params: []Param{{Name: "color"}}}, ""},
+		{"undeclared placeholder", Effect{Pattern: "top=0|15|{color}"}, "{color}"},
+		{"undeclared in step", Effect{Steps: []Step{{Pattern: "top=0|15|{color}", DurationMs: 100}}}, "{color}"},
+		{"bad name", Effect{Params: []Param{{Name: "1color"}}}, "must start with a letter"},
+		{"duplicate", Effect{Params: []Param{{Name: "color"}, {Name: "color"}}}, "declared twice"},
+		{"bad default", Effect{Params: []Param{{Name: "color", Default: "a&b"}}}, "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateParams(&tt.effect)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestStore_AddRejectsUndeclaredPlaceholders(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "effects.json"))
+
+	err := store.Add(&Effect{Name: "pulse", Pattern: "top=0|15|{color}"})
+	if err == nil {
+		t.Fatal("expected an effect with an undeclared placeholder to be rejected")
+	}
+
+	err = store.Add(&Effect{Name: "pulse", Pattern: "top=0|15|{color}", Params: []Param{{Name: "color", Default: "FF0000"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.Load(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	effect, _ := store.Get("pulse")
+	if len(effect.Params) != 1 || effect.Params[0].Default != "FF0000" {
+		t.Errorf("expected params to be saved, got %+v", effect.Params)
+	}
+}
+
+func TestValidatePatterns(t *testing.T) {
+	valid := []*Effect{
+		{Pattern: "top_init=1&top=0|15|FF0000"},
+		{Pattern: "top_init=1&top=0|15|{color}&top_morph={speed}|5", Params: []Param{{Name: "color"}, {Name: "speed", Default: "300"}}},
+		{Steps: []Step{{Pattern: "top=0|8|ff0000", DurationMs: 400}, {Pattern: "top=7|8|0000ff", DurationMs: 400}}},
+	}
+	for _, effect := range valid {
+		if err := ValidatePatterns(effect); err != nil {
+			t.Errorf("%q: unexpected error: %v", effect.Pattern, err)
+		}
+	}
+
+	tests := []struct {
+		effect *Effect
+		want   string
+	}{
+		{&Effect{Pattern: "test=1"}, "pattern: test: not a UFO parameter"},
+		{&Effect{Pattern: ""}, "pattern is empty"},
+		{&Effect{Pattern: "top_morph={speed}|5", Params: []Param{{Name: "speed", Default: "fast"}}}, "pattern: top_morph=fast|5"},
+		{&Effect{Pattern: "top_{ring}=ff0000", Params: []Param{{Name: "ring"}}}, "top_{ring} is not a UFO parameter"},
+		{&Effect{Steps: []Step{{Pattern: "top=0|8|ff0000", DurationMs: 400}, {Pattern: "dim=300", DurationMs: 400}}}, "step 1: dim=300"},
+	}
+	for _, tt := range tests {
+		err := ValidatePatterns(tt.effect)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: got %v, want %q", tt.effect.Pattern, err, tt.want)
+		}
+	}
+}
+
+func TestValidatePatterns_SeedEffects(t *testing.T) {
+	// Load copies the shipped effects from data/effects.json
+	store := NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	if err := store.Load(); err != nil {
+		t.Fatalf("loading seed effects: %v", err)
+	}
+	seeded := store.List()
+	if len(seeded) == 0 {
+		t.Fatal("no seed effects were loaded")
+	}
+	for _, effect := range seeded {
+		if err := ValidatePatterns(effect); err != nil {
+			t.Errorf("seed effect '%s': %v", effect.Name, err)
+		}
+	}
+}
+
+func TestEffect_RenderChecksValues(t *testing.T) {
+	effect := &Effect{
+		Name:    "pulse",
+		Pattern: "top_init=1&top=0|15|{color}",
+		Params:  []Param{{Name: "color"}},
+	}
+	_, err := effect.Render(map[string]string{"color": "red"})
+	if err == nil || !strings.Contains(err.Error(), "top=0|15|red: segment 0|15|red: color must be six digit hex") {
+		t.Errorf("got %v, want a segment color error", err)
+	}
+}
@@ -0,0 +1,220 @@
+package effects
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+)
+
+// DefaultMaxRevisions is how many earlier versions the store keeps per effect
+const DefaultMaxRevisions = 20
+
+// Reasons an effect's version was replaced
+const (
+	RevisionUpdated    = "updated"    // by Update
+	RevisionDeleted    = "deleted"    // by Delete
+	RevisionImported   = "imported"   // overwritten or removed by Import
+	RevisionRolledBack = "rolledBack" // by Rollback to an earlier version
+)
+
+// Revision is an earlier version of an effect. An effect's versions are
+// numbered from 1; the stored effect is the version after its latest
+// revision.
+type Revision struct {
+	Version    int       `json:"version"`
+	Effect     Effect    `json:"effect"`
+	ReplacedAt time.Time `json:"replacedAt"`
+	Reason     string    `json:"reason"` // one of the Revision constants
+}
+
+// SetRevisionsFile stores earlier versions of effects in filePath. They are
+// not recorded until LoadRevisions succeeds, so a file that fails to load is
+// not overwritten. Without a file they are kept in memory only.
+func (s *Store) SetRevisionsFile(filePath string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revisionsFile = filePath
+	s.revisionsLoaded = false
+}
+
+// LoadRevisions reads the revisions file. A missing file has no revisions.
+func (s *Store) LoadRevisions() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.revisionsFile == "" {
+		s.revisionsLoaded = true
+		return nil
+	}
+	data, err := os.ReadFile(s.revisionsFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.revisions = make(map[string][]Revision)
+			s.revisionsLoaded = true
+			return nil
+		}
+		return fmt.Errorf("reading effect revisions file: %w", err)
+	}
+
+	revisions := make(map[string][]Revision)
+	if err := json.Unmarshal(data, &revisions); err != nil {
+		return fmt.Errorf("parsing effect revisions JSON: %w", err)
+	}
+	for name, list := range revisions {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+		if len(list) > s.maxRevisions {
+			revisions[name] = list[len(list)-s.maxRevisions:]
+		}
+	}
+	s.revisions = revisions
+	s.revisionsLoaded = true
+	return nil
+}
+
+// Revisions returns the earlier versions of an effect, oldest first, and the
+// version of the stored effect, which is 0 when the effect was deleted
+func (s *Store) Revisions(name string) ([]Revision, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	revisions := make([]Revision, len(s.revisions[name]))
+	copy(revisions, s.revisions[name])
+	current := 0
+	if _, exists := s.effects[name]; exists {
+		current = s.nextVersionUnsafe(name)
+	}
+	return revisions, current
+}
+
+// RevisedEffects returns the names of the effects with earlier versions,
+// including deleted ones, sorted
+func (s *Store) RevisedEffects() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.revisions))
+	for name, revisions := range s.revisions {
+		if len(revisions) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Rollback makes an earlier version the stored effect again, keeping the
+// version it replaces, and returns it. A deleted effect is restored.
+func (s *Store) Rollback(name string, version int) (*Effect, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.effects[name]
+	if exists && version == s.nextVersionUnsafe(name) {
+		return nil, fmt.Errorf("version %d is the current version of effect '%s'", version, name)
+	}
+	var restored *Effect
+	for _, revision := range s.revisions[name] {
+		if revision.Version == version {
+			effect := revision.Effect
+			restored = &effect
+			break
+		}
+	}
+	if restored == nil {
+		return nil, fmt.Errorf("effect '%s' has no version %d", name, version)
+	}
+
+	next, err := s.withUnsafe(restored)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		if err := s.recordUnsafe(RevisionRolledBack, time.Now(), current); err != nil {
+			return nil, err
+		}
+	}
+	previous := s.effects
+	s.effects = next
+	if err := s.saveUnsafe(); err != nil {
+		s.effects = previous
+		return nil, err
+	}
+	return s.effects[name], nil
+}
+
+// nextVersionUnsafe returns the version number after an effect's latest
+// revision; the caller holds the lock
+func (s *Store) nextVersionUnsafe(name string) int {
+	revisions := s.revisions[name]
+	if len(revisions) == 0 {
+		return 1
+	}
+	return revisions[len(revisions)-1].Version + 1
+}
+
+// recordUnsafe keeps the given versions of effects, which are about to be
+// replaced or removed, and saves the revisions. Nothing is kept if saving
+// fails, and nothing is recorded while the revisions file has not loaded.
+// The caller holds the lock.
+func (s *Store) recordUnsafe(reason string, at time.Time, replaced ...*Effect) error {
+	if !s.revisionsLoaded {
+		return nil
+	}
+
+	previous := make(map[string][]Revision, len(replaced))
+	for _, effect := range replaced {
+		if _, saved := previous[effect.Name]; !saved {
+			previous[effect.Name] = s.revisions[effect.Name]
+		}
+		revisions := append(s.revisions[effect.Name], Revision{
+			Version:    s.nextVersionUnsafe(effect.Name),
+			Effect:     *effect,
+			ReplacedAt: timezone.In(at),
+			Reason:     reason,
+		})
+		if len(revisions) > s.maxRevisions {
+			revisions = revisions[len(revisions)-s.maxRevisions:]
+		}
+		s.revisions[effect.Name] = revisions
+	}
+
+	if err := s.saveRevisionsUnsafe(); err != nil {
+		for name, revisions := range previous {
+			if revisions == nil {
+				delete(s.revisions, name)
+			} else {
+				s.revisions[name] = revisions
+			}
+		}
+		return fmt.Errorf("saving effect revisions: %w", err)
+	}
+	return nil
+}
+
+// saveRevisionsUnsafe writes the revisions file, if there is one; the caller
+// holds the lock
+func (s *Store) saveRevisionsUnsafe() error {
+	if s.revisionsFile == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.revisions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling effect revisions: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.revisionsFile), 0755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	return os.WriteFile(s.revisionsFile, data, 0644)
+}
+
+// sameEffect reports whether an update leaves an effect as it was
+func sameEffect(a, b *Effect) bool {
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,121 @@
+package effects
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore_RevisionsAndRollback(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(filepath.Join(dir, "effects.json"))
+	store.SetRevisionsFile(filepath.Join(dir, "effect-revisions.json"))
+	if err := store.LoadRevisions(); err != nil {
+		t.Fatalf("LoadRevisions: %v", err)
+	}
+
+	store.Add(&Effect{Name: "glow", Description: "tuned", Pattern: "top=0|15|FF8800"})
+	if revisions, current := store.Revisions("glow"); len(revisions) != 0 || current != 1 {
+		t.Errorf("expected a new effect at version 1 without revisions, got %d revisions at %d", len(revisions), current)
+	}
+
+	store.Update(&Effect{Name: "glow", Description: "clobbered", Pattern: "top=0|15|FFFFFF"})
+	// An update that changes nothing is not a new version
+	store.Update(&Effect{Name: "glow", Description: "clobbered", Pattern: "top=0|15|FFFFFF"})
+	revisions, current := store.Revisions("glow")
+	if len(revisions) != 1 || current != 2 {
+		t.Fatalf("expected 1 revision at version 2, got %+v at %d", revisions, current)
+	}
+	if revisions[0].Version != 1 || revisions[0].Reason != RevisionUpdated || revisions[0].Effect.Description != "tuned" {
+		t.Errorf("expected version 1 kept as updated, got %+v", revisions[0])
+	}
+
+	if _, err := store.Rollback("glow", 2); err == nil {
+		t.Error("expected an error rolling back to the current version")
+	}
+	if _, err := store.Rollback("glow", 7); err == nil {
+		t.Error("expected an error for an unknown version")
+	}
+	restored, err := store.Rollback("glow", 1)
+	if err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if effect, _ := store.Get("glow"); effect != restored || effect.Description != "tuned" {
+		t.Errorf("expected version 1 stored again, got %+v", effect)
+	}
+	revisions, current = store.Revisions("glow")
+	if len(revisions) != 2 || current != 3 || revisions[1].Reason != RevisionRolledBack || revisions[1].Effect.Description != "clobbered" {
+		t.Errorf("expected the clobbered version kept as version 2, got %+v at %d", revisions, current)
+	}
+
+	// A deleted effect can be restored, also after a restart
+	if err := store.Delete("glow"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	reloaded := NewStore(filepath.Join(dir, "effects.json"))
+	reloaded.Load()
+	reloaded.SetRevisionsFile(filepath.Join(dir, "effect-revisions.json"))
+	if err := reloaded.LoadRevisions(); err != nil {
+		t.Fatalf("LoadRevisions after restart: %v", err)
+	}
+	if names := reloaded.RevisedEffects(); len(names) != 1 || names[0] != "glow" {
+		t.Errorf("expected glow to have revisions, got %v", names)
+	}
+	revisions, current = reloaded.Revisions("glow")
+	if len(revisions) != 3 || current != 0 || revisions[2].Reason != RevisionDeleted {
+		t.Fatalf("expected the deleted version kept, got %+v at %d", revisions, current)
+	}
+	if _, err := reloaded.Rollback("glow", 3); err != nil {
+		t.Fatalf("Rollback of a deleted effect: %v", err)
+	}
+	if effect, exists := reloaded.Get("glow"); !exists || effect.Description != "tuned" {
+		t.Errorf("expected the deleted effect restored, got %+v", effect)
+	}
+}
+
+func TestStore_RevisionsLimit(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	store.maxRevisions = 3
+	store.Add(&Effect{Name: "wave", Pattern: "top=0|15|000000"})
+	for _, color := range []string{"111111", "222222", "333333", "444444", "555555"} {
+		store.Update(&Effect{Name: "wave", Pattern: "top=0|15|" + color})
+	}
+	revisions, current := store.Revisions("wave")
+	if len(revisions) != 3 || revisions[0].Version != 3 || current != 6 {
+		t.Errorf("expected versions 3-5 kept with 6 current, got %+v at %d", revisions, current)
+	}
+}
+
+func TestStore_ImportRecordsRevisions(t *testing.T) {
+	store := bundleStore(t, "pulse", "glow")
+	_, err := store.Import(&Bundle{Format: BundleFormat, Version: BundleVersion, Effects: []*Effect{
+		{Name: "pulse", Description: "imported pulse", Pattern: "top=0|15|0000FF"},
+	}}, ImportOptions{Replace: true})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	for _, name := range []string{"pulse", "glow"} {
+		if revisions, _ := store.Revisions(name); len(revisions) != 1 || revisions[0].Reason != RevisionImported {
+			t.Errorf("expected the stored %s kept as imported, got %+v", name, revisions)
+		}
+	}
+}
+
+func TestStore_UnreadableRevisionsFileIsNotOverwritten(t *testing.T) {
+	dir := t.TempDir()
+	revisionsFile := filepath.Join(dir, "effect-revisions.json")
+	os.WriteFile(revisionsFile, []byte("not json"), 0644)
+
+	store := NewStore(filepath.Join(dir, "effects.json"))
+	store.SetRevisionsFile(revisionsFile)
+	if err := store.LoadRevisions(); err == nil {
+		t.Fatal("expected an error for a corrupt revisions file")
+	}
+	store.Add(&Effect{Name: "glow", Pattern: "top=0|15|FF8800"})
+	if err := store.Update(&Effect{Name: "glow", Pattern: "top=0|15|FFFFFF"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if data, _ := os.ReadFile(revisionsFile); string(data) != "not json" {
+		t.Errorf("expected the corrupt file left alone, got %s", data)
+	}
+}
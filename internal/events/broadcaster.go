@@ -1,9 +1,17 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
+	"log/slog"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/logging"
+	"github.com/starspace46/ufo-mcp-go/internal/redact"
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
 )
 
 // Event represents a state change event
@@ -11,25 +19,71 @@ type Event struct {
 	Type      string                 `json:"type"`
 	Timestamp time.Time              `json:"timestamp"`
 	Data      map[string]interface{} `json:"data,omitempty"`
+	RequestID string                 `json:"requestId,omitempty"` // tool invocation that caused the event, if any
+	Device    string                 `json:"device,omitempty"`    // address of the UFO the event is about, once SetDevice is called
 }
 
-// EventType constants
+// EventType constants. The data of each type is described by its Payload
+// in payloads.go.
 const (
-	EventEffectStarted   = "effect_started"
-	EventEffectStopped   = "effect_stopped"
-	EventEffectCompleted = "effect_completed"
-	EventEffectResumed   = "effect_resumed"
-	EventDimChanged      = "dim_changed"
-	EventRingUpdate      = "ring_update"
-	EventButtonPress     = "button_press"
-	EventRawExecuted     = "raw_executed"
-	EventProgress        = "progress"
+	EventEffectStarted      = "effect_started"
+	EventEffectStopped      = "effect_stopped"
+	EventEffectCompleted    = "effect_completed"
+	EventEffectResumed      = "effect_resumed"
+	EventEffectPaused       = "effect_paused"
+	EventEffectsCleared     = "effects_cleared"
+	EventDimChanged         = "dim_changed"
+	EventRingUpdate         = "ring_update"
+	EventButtonPress        = "button_press"
+	EventRawExecuted        = "raw_executed"
+	EventProgress           = "progress"
+	EventStateReconciled    = "state_reconciled"
+	EventAlertFiring        = "alert_firing"
+	EventAlertResolved      = "alert_resolved"
+	EventAlertAcknowledged  = "alert_acknowledged"
+	EventChannelStatus      = "channel_status"
+	EventDeviceOffline      = "device_offline"
+	EventDeviceOnline       = "device_online"
+	EventVoteCast           = "vote_cast"
+	EventVoteClosed         = "vote_closed"
+	EventFirmwareUpdate     = "firmware_update"
+	EventDeviceRebooted     = "device_rebooted"
+	EventDeviceReconfigured = "device_reconfigured"
+	EventFeatureChanged     = "feature_changed"
+	EventVUMeter            = "vu_meter"
+	EventStackOverflow      = "stack_overflow"
 )
 
 // Subscriber represents a client listening for events
 type Subscriber struct {
 	ID      string
 	Channel chan Event
+	Filter  SubscriptionFilter // events not passing it are not delivered
+}
+
+// SubscriptionFilter selects the events delivered to a subscriber. Zero
+// fields select everything.
+type SubscriptionFilter struct {
+	Types  []string // event types to deliver
+	Device string   // address of the UFO the events are about
+	Effect string   // effect the events are about; events naming no effect are skipped
+}
+
+// Matches reports whether an event passes the filter
+func (f SubscriptionFilter) Matches(event Event) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, event.Type) {
+		return false
+	}
+	if f.Device != "" && event.Device != f.Device {
+		return false
+	}
+	if f.Effect != "" {
+		effect, _ := event.Data["effect"].(string)
+		if effect != f.Effect {
+			return false
+		}
+	}
+	return true
 }
 
 // Broadcaster manages event distribution to multiple clients
@@ -37,6 +91,14 @@ type Broadcaster struct {
 	mu          sync.RWMutex
 	subscribers map[string]*Subscriber
 	eventChan   chan Event
+	closed      bool // set by Close; later events are dropped
+
+	publishDrops    atomic.Uint64 // events dropped because the event channel was full
+	subscriberDrops atomic.Uint64 // deliveries skipped because a subscriber was full
+
+	redactor atomic.Pointer[redact.Redactor] // masks sensitive values in event data
+	device   atomic.Pointer[string]          // address stamped on every event
+	history  *history                        // the last DefaultHistorySize events
 }
 
 // NewBroadcaster creates a new event broadcaster
@@ -44,6 +106,7 @@ func NewBroadcaster() *Broadcaster {
 	b := &Broadcaster{
 		subscribers: make(map[string]*Subscriber),
 		eventChan:   make(chan Event, 100), // Buffer for events
+		history:     newHistory(DefaultHistorySize),
 	}
 
 	// Start the broadcasting goroutine
@@ -52,8 +115,14 @@ func NewBroadcaster() *Broadcaster {
 	return b
 }
 
-// Subscribe adds a new subscriber
+// Subscribe adds a new subscriber receiving every event
 func (b *Broadcaster) Subscribe(id string) *Subscriber {
+	return b.SubscribeFiltered(id, SubscriptionFilter{})
+}
+
+// SubscribeFiltered adds a new subscriber receiving only the events passing
+// filter. Events it skips do not fill its channel or count as dropped.
+func (b *Broadcaster) SubscribeFiltered(id string, filter SubscriptionFilter) *Subscriber {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
@@ -65,6 +134,7 @@ func (b *Broadcaster) Subscribe(id string) *Subscriber {
 	sub := &Subscriber{
 		ID:      id,
 		Channel: make(chan Event, 10), // Buffer for subscriber
+		Filter:  filter,
 	}
 
 	b.subscribers[id] = sub
@@ -82,91 +152,127 @@ func (b *Broadcaster) Unsubscribe(id string) {
 	}
 }
 
+// SetRedactor masks sensitive values in the data of every event published
+// from now on
+func (b *Broadcaster) SetRedactor(r *redact.Redactor) {
+	b.redactor.Store(r)
+}
+
+// SetDevice stamps the address of the UFO this server controls on every
+// event published from now on, so subscribers can filter by device
+func (b *Broadcaster) SetDevice(address string) {
+	b.device.Store(&address)
+}
+
 // Publish sends an event to all subscribers
 func (b *Broadcaster) Publish(event Event) {
-	event.Timestamp = time.Now()
+	b.PublishContext(context.Background(), event)
+}
+
+// PublishContext sends an event to all subscribers, tagged with the request
+// ID carried by ctx so it can be traced back to the tool call that caused it
+func (b *Broadcaster) PublishContext(ctx context.Context, event Event) {
+	event.Timestamp = timezone.Now()
+	event.Data = b.redactor.Load().Map(event.Data)
+	if id := logging.RequestID(ctx); id != "" {
+		event.RequestID = id
+	}
+	if device := b.device.Load(); device != nil && event.Device == "" {
+		event.Device = *device
+	}
+	slog.DebugContext(ctx, "Event published", "type", event.Type)
+	b.history.add(event)
+
+	// Timers finishing in the background may publish after Close
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return
+	}
 	select {
 	case b.eventChan <- event:
 	default:
 		// Event channel is full, drop the event
-		// In production, we might want to log this
+		b.publishDrops.Add(1)
 	}
 }
 
 // PublishEffectStarted publishes an effect started event
 func (b *Broadcaster) PublishEffectStarted(effectName string, duration int) {
-	b.Publish(Event{
-		Type: EventEffectStarted,
-		Data: map[string]interface{}{
-			"effect":   effectName,
-			"duration": duration,
-		},
-	})
+	b.Publish(NewEvent(EffectStarted{Effect: effectName, Duration: duration}))
 }
 
 // PublishEffectStopped publishes an effect stopped event
 func (b *Broadcaster) PublishEffectStopped(effectName string, reason string) {
-	b.Publish(Event{
-		Type: EventEffectStopped,
-		Data: map[string]interface{}{
-			"effect": effectName,
-			"reason": reason,
-		},
-	})
+	b.Publish(NewEvent(EffectStopped{Effect: effectName, Reason: reason}))
 }
 
 // PublishProgress publishes a progress event
 func (b *Broadcaster) PublishProgress(effectName string, elapsed int, total int) {
-	b.Publish(Event{
-		Type: EventProgress,
-		Data: map[string]interface{}{
-			"effect":  effectName,
-			"elapsed": elapsed,
-			"total":   total,
-		},
-	})
+	b.Publish(NewEvent(Progress{Effect: effectName, Elapsed: elapsed, Total: total}))
 }
 
 // PublishDimChanged publishes a brightness change event
 func (b *Broadcaster) PublishDimChanged(newLevel int) {
-	b.Publish(Event{
-		Type: EventDimChanged,
-		Data: map[string]interface{}{
-			"level": newLevel,
-		},
-	})
+	b.Publish(NewEvent(DimChanged{Level: newLevel}))
 }
 
 // PublishRawExecuted publishes a raw API execution event
-func (b *Broadcaster) PublishRawExecuted(query string, result string) {
-	b.Publish(Event{
-		Type: EventRawExecuted,
-		Data: map[string]interface{}{
-			"query":  query,
-			"result": result,
-		},
-	})
+func (b *Broadcaster) PublishRawExecuted(ctx context.Context, query string, result string) {
+	b.PublishPayload(ctx, RawExecuted{Query: query, Result: result})
 }
 
 // PublishRingUpdate publishes a ring LED update event
-func (b *Broadcaster) PublishRingUpdate(ring string, data map[string]interface{}) {
-	eventData := map[string]interface{}{
-		"ring": ring,
+func (b *Broadcaster) PublishRingUpdate(update RingUpdate) {
+	b.Publish(NewEvent(update))
+}
+
+// PublishStateReconciled publishes a drift detection event after the shadow
+// state was corrected from the device's reported state
+func (b *Broadcaster) PublishStateReconciled(fields []string) {
+	b.Publish(NewEvent(StateReconciled{Fields: fields}))
+}
+
+// PublishAlert publishes an alert firing, acknowledged or resolved event from an integration
+func (b *Broadcaster) PublishAlert(eventType, source, key, name string) {
+	switch eventType {
+	case EventAlertFiring:
+		b.Publish(NewEvent(AlertFiring{Source: source, Key: key, Name: name}))
+	case EventAlertAcknowledged:
+		b.Publish(NewEvent(AlertAcknowledged{Source: source, Key: key, Name: name}))
+	default:
+		b.Publish(NewEvent(AlertResolved{Source: source, Key: key, Name: name}))
 	}
-	for k, v := range data {
-		eventData[k] = v
+}
+
+// PublishDeviceAvailability publishes a device offline or online event when
+// the device client's circuit breaker changes state
+func (b *Broadcaster) PublishDeviceAvailability(online bool, err error) {
+	if online {
+		b.Publish(NewEvent(DeviceOnline{}))
+		return
 	}
-	b.Publish(Event{
-		Type: EventRingUpdate,
-		Data: eventData,
-	})
+
+	var offline DeviceOffline
+	if err != nil {
+		offline.Error = err.Error()
+	}
+	b.Publish(NewEvent(offline))
+}
+
+// PublishDeviceRebooted publishes a device rebooted event when the UFO's
+// uptime shows it restarted
+func (b *Broadcaster) PublishDeviceRebooted(bootedAt time.Time, uptime time.Duration) {
+	b.Publish(NewEvent(DeviceRebooted{
+		BootedAt:      bootedAt.Format(time.RFC3339),
+		UptimeSeconds: int64(uptime.Seconds()),
+	}))
 }
 
-// PublishButtonPress publishes a button press event
-func (b *Broadcaster) PublishButtonPress() {
-	b.Publish(Event{
-		Type: EventButtonPress,
-	})
+// PublishButtonPress publishes a button press event for presses of the
+// UFO's button noticed by one poll, and the tool called for them if any
+func (b *Broadcaster) PublishButtonPress(presses int, action string) {
+	b.Publish(NewEvent(ButtonPress{Presses: presses, Action: action}))
 }
 
 // run is the main broadcasting loop
@@ -174,10 +280,14 @@ func (b *Broadcaster) run() {
 	for event := range b.eventChan {
 		b.mu.RLock()
 		for _, sub := range b.subscribers {
+			if !sub.Filter.Matches(event) {
+				continue
+			}
 			select {
 			case sub.Channel <- event:
 			default:
 				// Subscriber channel is full, skip this event for this subscriber
+				b.subscriberDrops.Add(1)
 			}
 		}
 		b.mu.RUnlock()
@@ -189,13 +299,17 @@ func (b *Broadcaster) Close() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	// Close all subscriber channels
+	// Close all subscriber channels; later Unsubscribe calls become no-ops
 	for _, sub := range b.subscribers {
 		close(sub.Channel)
 	}
+	b.subscribers = make(map[string]*Subscriber)
 
 	// Close the event channel
-	close(b.eventChan)
+	if !b.closed {
+		close(b.eventChan)
+		b.closed = true
+	}
 }
 
 // GetSubscriberCount returns the number of active subscribers
@@ -205,6 +319,12 @@ func (b *Broadcaster) GetSubscriberCount() int {
 	return len(b.subscribers)
 }
 
+// DroppedEvents returns how many events were dropped at publish time and how
+// many subscriber deliveries were skipped because the subscriber was full
+func (b *Broadcaster) DroppedEvents() (publish, subscriber uint64) {
+	return b.publishDrops.Load(), b.subscriberDrops.Load()
+}
+
 // ToSSEData converts an event to Server-Sent Events format
 func (e Event) ToSSEData() string {
 	data, _ := json.Marshal(e)
@@ -1,8 +1,12 @@
 package events
 
 import (
+	"context"
 	"testing"
 	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/logging"
+	"github.com/starspace46/ufo-mcp-go/internal/redact"
 )
 
 func TestBroadcaster_SubscribeUnsubscribe(t *testing.T) {
@@ -74,6 +78,28 @@ func TestBroadcaster_PublishEvents(t *testing.T) {
 	}
 }
 
+func TestBroadcaster_Redaction(t *testing.T) {
+	b := NewBroadcaster()
+	defer b.Close()
+	redactor, _ := redact.New(nil)
+	b.SetRedactor(redactor)
+
+	sub := b.Subscribe("test_client")
+	b.PublishRawExecuted(logging.WithRequestID(context.Background(), "req-1"), "dim=10&wifi_pass=hunter2", "OK")
+
+	select {
+	case received := <-sub.Channel:
+		if received.Data["query"] != "dim=10&wifi_pass=[redacted]" {
+			t.Errorf("expected password redacted, got %v", received.Data["query"])
+		}
+		if received.RequestID != "req-1" {
+			t.Errorf("expected the request ID from the context, got %q", received.RequestID)
+		}
+	case <-time.After(1 * time.Second):
+		t.Error("timeout waiting for event")
+	}
+}
+
 func TestBroadcaster_SpecificEvents(t *testing.T) {
 	b := NewBroadcaster()
 	defer b.Close()
@@ -116,14 +142,14 @@ func TestBroadcaster_SpecificEvents(t *testing.T) {
 		{
 			name: "raw executed",
 			publish: func() {
-				b.PublishRawExecuted("effect=rainbow", "OK")
+				b.PublishRawExecuted(context.Background(), "effect=rainbow", "OK")
 			},
 			expected: EventRawExecuted,
 		},
 		{
 			name: "button press",
 			publish: func() {
-				b.PublishButtonPress()
+				b.PublishButtonPress(1, "stopAllEffects")
 			},
 			expected: EventButtonPress,
 		},
@@ -168,6 +194,15 @@ func TestBroadcaster_MultipleSubscribers(t *testing.T) {
 	}
 }
 
+func TestBroadcaster_PublishAfterClose(t *testing.T) {
+	b := NewBroadcaster()
+	b.Close()
+
+	// Background timers may still finish; their events are dropped
+	b.Publish(Event{Type: "late"})
+	b.Close()
+}
+
 func TestEvent_ToSSEData(t *testing.T) {
 	event := Event{
 		Type:      "test_event",
@@ -201,3 +236,51 @@ func contains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestBroadcaster_SubscribeFiltered(t *testing.T) {
+	b := NewBroadcaster()
+	defer b.Close()
+	b.SetDevice("192.168.1.72")
+
+	all := b.Subscribe("all")
+	typed := b.SubscribeFiltered("typed", SubscriptionFilter{Types: []string{EventEffectStarted}})
+	effect := b.SubscribeFiltered("effect", SubscriptionFilter{Effect: "rainbow"})
+	other := b.SubscribeFiltered("other", SubscriptionFilter{Device: "192.168.1.80"})
+
+	b.PublishRawExecuted(context.Background(), "top=0|15|FF0000", "OK")
+	b.PublishEffectStarted("police", 0)
+	b.PublishEffectStarted("rainbow", 5000)
+
+	received := func(sub *Subscriber, want int) []Event {
+		t.Helper()
+		var got []Event
+		for len(got) < want {
+			select {
+			case event := <-sub.Channel:
+				got = append(got, event)
+			case <-time.After(time.Second):
+				t.Fatalf("%s: expected %d events, got %d", sub.ID, want, len(got))
+			}
+		}
+		return got
+	}
+	if got := received(all, 3); got[0].Device != "192.168.1.72" {
+		t.Errorf("expected events stamped with the device, got %q", got[0].Device)
+	}
+	if got := received(typed, 2); got[0].Data["effect"] != "police" || got[1].Data["effect"] != "rainbow" {
+		t.Errorf("expected both effect_started events, got %v", got)
+	}
+	if got := received(effect, 1); got[0].Type != EventEffectStarted {
+		t.Errorf("expected only the rainbow event, got %v", got)
+	}
+
+	// Skipped events are neither delivered nor counted as dropped
+	select {
+	case event := <-other.Channel:
+		t.Errorf("expected no events for another device, got %v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if _, dropped := b.DroppedEvents(); dropped != 0 {
+		t.Errorf("expected no dropped deliveries, got %d", dropped)
+	}
+}
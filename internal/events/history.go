@@ -0,0 +1,94 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultHistorySize is how many recent events the broadcaster keeps for
+// clients that connect late
+const DefaultHistorySize = 500
+
+// HistoryFilter selects events from the broadcaster's history. Zero fields
+// select everything.
+type HistoryFilter struct {
+	Types []string  // event types to include
+	Since time.Time // earliest timestamp, inclusive
+	Until time.Time // latest timestamp, inclusive
+	Limit int       // keep only the newest Limit matches
+}
+
+// matches reports whether an event passes the filter's type and time range
+func (f HistoryFilter) matches(event Event) bool {
+	if !f.Since.IsZero() && event.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && event.Timestamp.After(f.Until) {
+		return false
+	}
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, eventType := range f.Types {
+		if event.Type == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// history keeps the most recent events in a ring buffer, whether or not
+// anyone was subscribed when they were published
+type history struct {
+	mu     sync.Mutex
+	events []Event // ring buffer; next is the oldest entry once full
+	next   int
+	full   bool
+}
+
+// newHistory creates a history holding at most size events
+func newHistory(size int) *history {
+	if size < 1 {
+		size = 1
+	}
+	return &history{events: make([]Event, size)}
+}
+
+// add records an event, dropping the oldest when the buffer is full
+func (h *history) add(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events[h.next] = event
+	h.next = (h.next + 1) % len(h.events)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// list returns the events passing filter, oldest first
+func (h *history) list(filter HistoryFilter) []Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ordered := h.events[:h.next]
+	if h.full {
+		ordered = append(append([]Event(nil), h.events[h.next:]...), h.events[:h.next]...)
+	}
+	matched := make([]Event, 0, len(ordered))
+	for _, event := range ordered {
+		if filter.matches(event) {
+			matched = append(matched, event)
+		}
+	}
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		matched = matched[len(matched)-filter.Limit:]
+	}
+	return matched
+}
+
+// Recent returns the events published most recently that pass filter,
+// oldest first. Events dropped because the broadcaster was busy are kept
+// here too.
+func (b *Broadcaster) Recent(filter HistoryFilter) []Event {
+	return b.history.list(filter)
+}
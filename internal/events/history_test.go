@@ -0,0 +1,75 @@
+package events
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestHistory_KeepsNewestEvents(t *testing.T) {
+	h := newHistory(3)
+	for i := 1; i <= 5; i++ {
+		h.add(Event{Type: fmt.Sprintf("e%d", i)})
+	}
+
+	got := h.list(HistoryFilter{})
+	if len(got) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(got))
+	}
+	for i, want := range []string{"e3", "e4", "e5"} {
+		if got[i].Type != want {
+			t.Errorf("event %d: expected %s, got %s", i, want, got[i].Type)
+		}
+	}
+}
+
+func TestHistory_Filter(t *testing.T) {
+	start := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	h := newHistory(10)
+	for i, eventType := range []string{EventEffectStarted, EventDimChanged, EventEffectStarted, EventEffectStopped} {
+		h.add(Event{Type: eventType, Timestamp: start.Add(time.Duration(i) * time.Minute)})
+	}
+
+	tests := []struct {
+		name   string
+		filter HistoryFilter
+		want   int
+	}{
+		{"everything", HistoryFilter{}, 4},
+		{"by type", HistoryFilter{Types: []string{EventEffectStarted}}, 2},
+		{"several types", HistoryFilter{Types: []string{EventDimChanged, EventEffectStopped}}, 2},
+		{"since", HistoryFilter{Since: start.Add(2 * time.Minute)}, 2},
+		{"until", HistoryFilter{Until: start.Add(time.Minute)}, 2},
+		{"range and type", HistoryFilter{Types: []string{EventEffectStarted}, Since: start.Add(time.Minute), Until: start.Add(3 * time.Minute)}, 1},
+		{"limit", HistoryFilter{Limit: 3}, 3},
+	}
+	for _, tt := range tests {
+		if got := h.list(tt.filter); len(got) != tt.want {
+			t.Errorf("%s: expected %d events, got %d", tt.name, tt.want, len(got))
+		}
+	}
+
+	// A limit keeps the newest matches
+	if got := h.list(HistoryFilter{Limit: 1}); got[0].Type != EventEffectStopped {
+		t.Errorf("expected the newest event, got %s", got[0].Type)
+	}
+}
+
+func TestBroadcaster_RecentWithoutSubscribers(t *testing.T) {
+	b := NewBroadcaster()
+	defer b.Close()
+
+	b.PublishDimChanged(100)
+	b.PublishEffectStarted("rainbow", 10)
+
+	recent := b.Recent(HistoryFilter{})
+	if len(recent) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(recent))
+	}
+	if recent[0].Type != EventDimChanged || recent[1].Type != EventEffectStarted {
+		t.Errorf("expected events oldest first, got %s, %s", recent[0].Type, recent[1].Type)
+	}
+	if recent[0].Timestamp.IsZero() {
+		t.Error("expected recorded events to carry their timestamp")
+	}
+}
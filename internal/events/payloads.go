@@ -0,0 +1,408 @@
+package events
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Payload is the data of an event of one type. Its fields become the
+// event's data under their JSON names, those tagged omitempty left out when
+// empty and pointers replaced by what they point to. A field's doc tag
+// describes it in the event schema.
+type Payload interface {
+	EventType() string
+}
+
+// NewEvent builds the event carrying payload
+func NewEvent(payload Payload) Event {
+	data := map[string]interface{}{}
+	addFields(data, reflect.ValueOf(payload))
+	return Event{Type: payload.EventType(), Data: data}
+}
+
+// PublishPayload publishes the event carrying payload, tagged with the
+// request ID carried by ctx
+func (b *Broadcaster) PublishPayload(ctx context.Context, payload Payload) {
+	b.PublishContext(ctx, NewEvent(payload))
+}
+
+// addFields adds the fields of the struct v to data, flattening embedded
+// structs
+func addFields(data map[string]interface{}, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		if field.Anonymous {
+			addFields(data, value)
+			continue
+		}
+		name, omitEmpty := jsonName(field)
+		if name == "" {
+			continue
+		}
+		if omitEmpty && isEmpty(value) {
+			continue
+		}
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+		data[name] = value.Interface()
+	}
+}
+
+// jsonName returns the name a field is encoded under, empty for fields
+// left out, and whether it is omitted when empty
+func jsonName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, options == "omitempty"
+}
+
+// isEmpty reports whether encoding/json treats v as empty for omitempty
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
+
+// EffectStarted is the data of an effect_started event
+type EffectStarted struct {
+	Effect       string            `json:"effect" doc:"Name of the effect"`
+	InstanceID   string            `json:"instanceId" doc:"ID of the new stack entry"`
+	Duration     int               `json:"duration" doc:"How long the effect runs in milliseconds, 0 until stopped"`
+	Pattern      string            `json:"pattern" doc:"Query of the effect's first frame"`
+	Steps        int               `json:"steps,omitempty" doc:"Number of animation frames the server steps through"`
+	Params       map[string]string `json:"params,omitempty" doc:"Values of the effect's parameters"`
+	Sequence     int               `json:"sequence,omitempty" doc:"Number of steps of a runSequence sequence, counting repeats"`
+	Alternate    []string          `json:"alternate,omitempty" doc:"The two effects alternateEffects takes turns showing"`
+	Text         string            `json:"text,omitempty" doc:"Text shown by displayText or displayNumber"`
+	Scheme       string            `json:"scheme,omitempty" doc:"How displayed text is encoded"`
+	Source       string            `json:"source,omitempty" doc:"Integration that showed the effect"`
+	Replaced     string            `json:"replaced,omitempty" doc:"Instance ID of the entry replaceEffect swapped out"`
+	Background   bool              `json:"background,omitempty" doc:"Whether the effect was played beneath the effect showing"`
+	Beneath      string            `json:"beneath,omitempty" doc:"Effect showing above a background effect"`
+	BeneathAlert string            `json:"beneathAlert,omitempty" doc:"Alert covering the effect until it ends"`
+	Warning      string            `json:"warning,omitempty" doc:"Unexpected reply from the UFO"`
+	StackDepth   int               `json:"stackDepth" doc:"Number of entries on the effect stack"`
+}
+
+// EventType returns effect_started
+func (EffectStarted) EventType() string { return EventEffectStarted }
+
+// EffectStopped is the data of an effect_stopped event
+type EffectStopped struct {
+	Effect     string `json:"effect" doc:"Name of the effect"`
+	InstanceID string `json:"instanceId,omitempty" doc:"ID of the stack entry"`
+	Manual     bool   `json:"manual,omitempty" doc:"Whether a tool call stopped it"`
+	ReplacedBy string `json:"replacedBy,omitempty" doc:"Instance ID of the entry replaceEffect put in its place"`
+	Reason     string `json:"reason,omitempty" doc:"Why the effect stopped"`
+	StackDepth int    `json:"stackDepth" doc:"Number of entries on the effect stack"`
+}
+
+// EventType returns effect_stopped
+func (EffectStopped) EventType() string { return EventEffectStopped }
+
+// EffectCompleted is the data of an effect_completed event
+type EffectCompleted struct {
+	Effect     string `json:"effect" doc:"Name of the effect"`
+	InstanceID string `json:"instanceId" doc:"ID of the stack entry"`
+	StackDepth int    `json:"stackDepth" doc:"Number of entries on the effect stack"`
+}
+
+// EventType returns effect_completed
+func (EffectCompleted) EventType() string { return EventEffectCompleted }
+
+// EffectResumed is the data of an effect_resumed event
+type EffectResumed struct {
+	Effect      string `json:"effect" doc:"Name of the effect showing again"`
+	InstanceID  string `json:"instanceId" doc:"ID of the stack entry"`
+	Unpaused    bool   `json:"unpaused,omitempty" doc:"Whether resumeEffect continued its countdown"`
+	RemainingMs int64  `json:"remainingMs,omitempty" doc:"Time left of a timed effect in milliseconds"`
+	StackDepth  int    `json:"stackDepth" doc:"Number of entries on the effect stack"`
+}
+
+// EventType returns effect_resumed
+func (EffectResumed) EventType() string { return EventEffectResumed }
+
+// EffectPaused is the data of an effect_paused event
+type EffectPaused struct {
+	Effect      string `json:"effect" doc:"Name of the effect"`
+	InstanceID  string `json:"instanceId" doc:"ID of the stack entry"`
+	RemainingMs int64  `json:"remainingMs,omitempty" doc:"Time left of a timed effect in milliseconds"`
+	StackDepth  int    `json:"stackDepth" doc:"Number of entries on the effect stack"`
+}
+
+// EventType returns effect_paused
+func (EffectPaused) EventType() string { return EventEffectPaused }
+
+// ClearedEffect is an entry stopAllEffects removed
+type ClearedEffect struct {
+	Effect     string `json:"effect" doc:"Name of the effect"`
+	InstanceID string `json:"instanceId" doc:"ID of the stack entry"`
+	Alert      string `json:"alert,omitempty" doc:"Name of the alert the entry showed"`
+	RanMs      int64  `json:"ranMs,omitempty" doc:"How long it ran in milliseconds"`
+}
+
+// EffectsCleared is the data of an effects_cleared event
+type EffectsCleared struct {
+	Stopped    []ClearedEffect `json:"stopped" doc:"Entries removed, top first"`
+	Count      int             `json:"count" doc:"Number of entries removed"`
+	ToDepth    int             `json:"toDepth" doc:"Depth the stack was unwound to"`
+	Kept       int             `json:"kept" doc:"Number of entries kept"`
+	Cleared    bool            `json:"cleared" doc:"Whether the stack is now empty"`
+	Showing    string          `json:"showing,omitempty" doc:"Effect showing now"`
+	Manual     bool            `json:"manual" doc:"Whether a tool call cleared them"`
+	StackDepth int             `json:"stackDepth" doc:"Number of entries on the effect stack"`
+}
+
+// EventType returns effects_cleared
+func (EffectsCleared) EventType() string { return EventEffectsCleared }
+
+// DimChanged is the data of a dim_changed event
+type DimChanged struct {
+	Level int `json:"level" doc:"Brightness from 0 to 255"`
+}
+
+// EventType returns dim_changed
+func (DimChanged) EventType() string { return EventDimChanged }
+
+// RingUpdate is the data of a ring_update event
+type RingUpdate struct {
+	Ring       string   `json:"ring" doc:"top or bottom"`
+	Colors     []string `json:"colors,omitempty" doc:"Hex color of each of the 15 LEDs"`
+	Segments   []string `json:"segments,omitempty" doc:"Hex color of each LED set by setRingPattern"`
+	Background string   `json:"background,omitempty" doc:"Background color of unlit LEDs"`
+	Brightness int      `json:"brightness,omitempty" doc:"Brightness of the ring in percent"`
+	Reset      bool     `json:"reset,omitempty" doc:"Whether the ring was turned off"`
+	Query      string   `json:"query,omitempty" doc:"Raw query that drew the ring"`
+}
+
+// EventType returns ring_update
+func (RingUpdate) EventType() string { return EventRingUpdate }
+
+// ButtonPress is the data of a button_press event
+type ButtonPress struct {
+	Presses int    `json:"presses" doc:"Presses since the UFO was last polled"`
+	Action  string `json:"action,omitempty" doc:"Tool called for the press, per --button-action"`
+}
+
+// EventType returns button_press
+func (ButtonPress) EventType() string { return EventButtonPress }
+
+// RawExecuted is the data of a raw_executed event
+type RawExecuted struct {
+	Query  string `json:"query" doc:"Query sent to the UFO, sensitive values masked"`
+	Result string `json:"result" doc:"OK, or ERROR: and why it failed"`
+}
+
+// EventType returns raw_executed
+func (RawExecuted) EventType() string { return EventRawExecuted }
+
+// Progress is the data of a progress event
+type Progress struct {
+	Effect     string `json:"effect" doc:"Name of the effect or sequence"`
+	InstanceID string `json:"instanceId,omitempty" doc:"ID of the stack entry"`
+	Step       int    `json:"step,omitempty" doc:"Sequence step starting, counting from 1"`
+	Steps      int    `json:"steps,omitempty" doc:"Number of sequence steps"`
+	Elapsed    int    `json:"elapsed" doc:"Milliseconds run, paused time not counting"`
+	Remaining  int    `json:"remaining,omitempty" doc:"Milliseconds left of a timed effect"`
+	Total      int    `json:"total" doc:"Total duration in milliseconds"`
+}
+
+// EventType returns progress
+func (Progress) EventType() string { return EventProgress }
+
+// StateReconciled is the data of a state_reconciled event
+type StateReconciled struct {
+	Fields []string `json:"fields" doc:"Shadow state fields corrected from the UFO's report"`
+}
+
+// EventType returns state_reconciled
+func (StateReconciled) EventType() string { return EventStateReconciled }
+
+// AlertFiring is the data of an alert_firing event
+type AlertFiring struct {
+	Source     string `json:"source" doc:"Integration, or raiseAlert, that raised the alert"`
+	Key        string `json:"key" doc:"Key identifying the alert within its source"`
+	Name       string `json:"name" doc:"Name of the alert"`
+	InstanceID string `json:"instanceId,omitempty" doc:"ID of the stack entry"`
+	Priority   int    `json:"priority,omitempty" doc:"Priority of a raised alert"`
+	Showing    *bool  `json:"showing,omitempty" doc:"Whether a raised alert is on top of the stack"`
+	Reason     string `json:"reason,omitempty" doc:"Why the alert was raised"`
+}
+
+// EventType returns alert_firing
+func (AlertFiring) EventType() string { return EventAlertFiring }
+
+// AlertResolved is the data of an alert_resolved event
+type AlertResolved struct {
+	Source     string `json:"source" doc:"Integration, or raiseAlert, that raised the alert"`
+	Key        string `json:"key" doc:"Key identifying the alert within its source"`
+	Name       string `json:"name" doc:"Name of the alert"`
+	InstanceID string `json:"instanceId,omitempty" doc:"ID of the stack entry"`
+	Expired    bool   `json:"expired,omitempty" doc:"Whether the alert's duration ran out"`
+}
+
+// EventType returns alert_resolved
+func (AlertResolved) EventType() string { return EventAlertResolved }
+
+// AlertAcknowledged is the data of an alert_acknowledged event
+type AlertAcknowledged struct {
+	Source string `json:"source" doc:"Integration that raised the alert"`
+	Key    string `json:"key" doc:"Key identifying the alert within its source"`
+	Name   string `json:"name" doc:"Name of the alert"`
+}
+
+// EventType returns alert_acknowledged
+func (AlertAcknowledged) EventType() string { return EventAlertAcknowledged }
+
+// ChannelStatus is the data of a channel_status event
+type ChannelStatus struct {
+	Channel  string `json:"channel" doc:"Name of the status board channel"`
+	Severity string `json:"severity" doc:"ok, info, warning, error, critical, custom for a color of its own, or off"`
+	Color    string `json:"color,omitempty" doc:"Hex color the channel's LEDs show, unless off"`
+	Message  string `json:"message,omitempty" doc:"What the reporting system said about the status"`
+	Previous string `json:"previous,omitempty" doc:"Severity the channel had before"`
+	Showing  *bool  `json:"showing,omitempty" doc:"Whether the channel is visible, rather than covered by an effect"`
+}
+
+// EventType returns channel_status
+func (ChannelStatus) EventType() string { return EventChannelStatus }
+
+// DeviceOffline is the data of a device_offline event
+type DeviceOffline struct {
+	Error string `json:"error,omitempty" doc:"Last error reaching the UFO"`
+}
+
+// EventType returns device_offline
+func (DeviceOffline) EventType() string { return EventDeviceOffline }
+
+// DeviceOnline is the data of a device_online event
+type DeviceOnline struct{}
+
+// EventType returns device_online
+func (DeviceOnline) EventType() string { return EventDeviceOnline }
+
+// VoteOption is one option's share of a vote
+type VoteOption struct {
+	Option string `json:"option" doc:"Name of the option"`
+	Votes  int    `json:"votes" doc:"Votes cast for it"`
+	Color  string `json:"color" doc:"Hex color of its share of the ring"`
+}
+
+// VoteRound describes a castVote round
+type VoteRound struct {
+	InstanceID string       `json:"instanceId" doc:"ID of the round's stack entry"`
+	Tally      []VoteOption `json:"tally" doc:"Votes per option"`
+	Votes      int          `json:"votes" doc:"Votes cast in the round"`
+	StartTime  time.Time    `json:"startTime" doc:"When the round opened"`
+	WindowMs   int          `json:"windowMs" doc:"How long the round stays open in milliseconds"`
+}
+
+// VoteCast is the data of a vote_cast event
+type VoteCast struct {
+	VoteRound
+	Option    string `json:"option" doc:"Option voted for"`
+	Voter     string `json:"voter,omitempty" doc:"Who voted"`
+	MovedFrom string `json:"movedFrom,omitempty" doc:"Option the voter voted for before"`
+}
+
+// EventType returns vote_cast
+func (VoteCast) EventType() string { return EventVoteCast }
+
+// VoteClosed is the data of a vote_closed event
+type VoteClosed struct {
+	VoteRound
+	Reason string `json:"reason" doc:"Why the round closed"`
+	Winner string `json:"winner,omitempty" doc:"Option with the most votes, if one has"`
+}
+
+// EventType returns vote_closed
+func (VoteClosed) EventType() string { return EventVoteClosed }
+
+// FirmwareUpdate is the data of a firmware_update event
+type FirmwareUpdate struct {
+	From     string `json:"from" doc:"Firmware version before the update"`
+	To       string `json:"to" doc:"Firmware version requested"`
+	State    string `json:"state" doc:"started, pending, downloading, flashing, rebooting, done or failed"`
+	Progress *int   `json:"progress,omitempty" doc:"Percent of the update done"`
+	Message  string `json:"message,omitempty" doc:"Detail reported by the UFO"`
+	Version  string `json:"version,omitempty" doc:"Version running once done"`
+}
+
+// EventType returns firmware_update
+func (FirmwareUpdate) EventType() string { return EventFirmwareUpdate }
+
+// DeviceRebooted is the data of a device_rebooted event
+type DeviceRebooted struct {
+	BootedAt      string `json:"bootedAt" doc:"When the UFO started, RFC 3339"`
+	UptimeSeconds int64  `json:"uptimeSeconds" doc:"Uptime the UFO reported"`
+}
+
+// EventType returns device_rebooted
+func (DeviceRebooted) EventType() string { return EventDeviceRebooted }
+
+// DeviceReconfigured is the data of a device_reconfigured event
+type DeviceReconfigured struct {
+	From      string   `json:"from" doc:"Address of the UFO controlled before"`
+	To        string   `json:"to" doc:"Address of the UFO controlled now"`
+	Addresses []string `json:"addresses,omitempty" doc:"IP addresses a host name resolved to"`
+	Trigger   string   `json:"trigger" doc:"What changed it: reconfigureDevice or SIGHUP"`
+}
+
+// EventType returns device_reconfigured
+func (DeviceReconfigured) EventType() string { return EventDeviceReconfigured }
+
+// FeatureChanged is the data of a feature_changed event
+type FeatureChanged struct {
+	Feature string `json:"feature" doc:"Name of the optional feature"`
+	Status  string `json:"status" doc:"Its new status"`
+	Reason  string `json:"reason" doc:"Why it has that status"`
+}
+
+// EventType returns feature_changed
+func (FeatureChanged) EventType() string { return EventFeatureChanged }
+
+// VUMeter is the data of a vu_meter event
+type VUMeter struct {
+	Status string `json:"status" doc:"started or stopped"`
+}
+
+// EventType returns vu_meter
+func (VUMeter) EventType() string { return EventVUMeter }
+
+// StackEntry names an effect stack entry
+type StackEntry struct {
+	Name       string `json:"name" doc:"Name of the effect"`
+	InstanceID string `json:"instanceId" doc:"ID of the stack entry"`
+}
+
+// StackOverflow is the data of a stack_overflow event
+type StackOverflow struct {
+	Effect     string       `json:"effect" doc:"Effect being pushed onto the full stack"`
+	Policy     string       `json:"policy" doc:"Overflow policy applied"`
+	MaxDepth   int          `json:"maxDepth" doc:"Most entries the stack holds"`
+	Dropped    []StackEntry `json:"dropped" doc:"Entries removed to make room"`
+	Rejected   bool         `json:"rejected" doc:"Whether the effect was refused"`
+	StackDepth int          `json:"stackDepth" doc:"Number of entries on the effect stack"`
+}
+
+// EventType returns stack_overflow
+func (StackOverflow) EventType() string { return EventStackOverflow }
@@ -0,0 +1,93 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewEvent(t *testing.T) {
+	event := NewEvent(EffectStarted{Effect: "rainbow", InstanceID: "a1", Duration: 5000, Pattern: "top=0|15|FF0000", StackDepth: 1})
+	if event.Type != EventEffectStarted {
+		t.Errorf("expected type %s, got %s", EventEffectStarted, event.Type)
+	}
+	want := map[string]interface{}{
+		"effect":     "rainbow",
+		"instanceId": "a1",
+		"duration":   5000,
+		"pattern":    "top=0|15|FF0000",
+		"stackDepth": 1,
+	}
+	if !reflect.DeepEqual(event.Data, want) {
+		t.Errorf("expected empty optional fields left out, got %v", event.Data)
+	}
+
+	// Pointers are dereferenced, and left out when nil
+	showing := false
+	event = NewEvent(AlertFiring{Name: "build", Showing: &showing})
+	if value, ok := event.Data["showing"].(bool); !ok || value {
+		t.Errorf("expected showing false, got %v", event.Data["showing"])
+	}
+	event = NewEvent(FirmwareUpdate{From: "1.0", To: "1.1", State: "pending"})
+	if _, exists := event.Data["progress"]; exists {
+		t.Errorf("expected no progress, got %v", event.Data["progress"])
+	}
+
+	// Embedded structs are flattened
+	start := time.Now()
+	event = NewEvent(VoteClosed{VoteRound: VoteRound{InstanceID: "v1", Tally: []VoteOption{{Option: "yes", Votes: 2}}, Votes: 2, StartTime: start}, Reason: "closed", Winner: "yes"})
+	if event.Data["instanceId"] != "v1" || event.Data["winner"] != "yes" || event.Data["startTime"] != start {
+		t.Errorf("expected round fields alongside the vote's, got %v", event.Data)
+	}
+	if _, exists := event.Data["VoteRound"]; exists {
+		t.Error("expected the embedded struct not to be nested")
+	}
+}
+
+func TestEventSchema(t *testing.T) {
+	schema := EventSchema()
+	if len(schema.Events) != len(catalog) {
+		t.Fatalf("expected %d event types, got %d", len(catalog), len(schema.Events))
+	}
+
+	seen := map[string]bool{}
+	for _, eventType := range schema.Events {
+		if seen[eventType.Type] {
+			t.Errorf("event type %s listed twice", eventType.Type)
+		}
+		seen[eventType.Type] = true
+		if eventType.Description == "" {
+			t.Errorf("event type %s has no description", eventType.Type)
+		}
+		for _, field := range eventType.Data {
+			if field.Description == "" {
+				t.Errorf("field %s of %s has no description", field.Name, eventType.Type)
+			}
+			for _, item := range field.Items {
+				if item.Description == "" {
+					t.Errorf("field %s.%s of %s has no description", field.Name, item.Name, eventType.Type)
+				}
+			}
+		}
+	}
+	for _, eventType := range []string{EventEffectStarted, EventProgress, EventVoteCast, EventStackOverflow} {
+		if !seen[eventType] {
+			t.Errorf("expected event type %s in the schema", eventType)
+		}
+	}
+
+	// Field types follow the JSON encoding
+	for _, eventType := range schema.Events {
+		if eventType.Type != EventVoteCast {
+			continue
+		}
+		types := map[string]string{}
+		for _, field := range eventType.Data {
+			types[field.Name] = field.Type
+		}
+		want := map[string]string{"instanceId": "string", "tally": "array", "votes": "integer", "startTime": "string", "windowMs": "integer", "option": "string", "voter": "string", "movedFrom": "string"}
+		if !reflect.DeepEqual(types, want) {
+			t.Errorf("expected vote_cast fields %v, got %v", want, types)
+		}
+	}
+}
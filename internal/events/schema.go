@@ -0,0 +1,136 @@
+package events
+
+import (
+	"reflect"
+	"time"
+)
+
+// FieldSchema documents a field of an event or of its data
+type FieldSchema struct {
+	Name        string        `json:"name"`
+	Type        string        `json:"type"` // JSON type: string, integer, boolean, array or object
+	Description string        `json:"description"`
+	Optional    bool          `json:"optional,omitempty"` // left out when empty
+	Items       []FieldSchema `json:"items,omitempty"`    // fields of the objects in an array
+}
+
+// TypeSchema documents an event type and its data
+type TypeSchema struct {
+	Type        string        `json:"type"`
+	Description string        `json:"description"`
+	Data        []FieldSchema `json:"data"`
+}
+
+// Schema documents the events the server publishes
+type Schema struct {
+	Envelope []FieldSchema `json:"envelope"` // fields every event has
+	Events   []TypeSchema  `json:"events"`
+}
+
+// catalog lists every event type the server publishes with its payload
+var catalog = []struct {
+	payload     Payload
+	description string
+}{
+	{EffectStarted{}, "An effect was pushed onto the effect stack"},
+	{EffectStopped{}, "An effect was stopped before it ended"},
+	{EffectCompleted{}, "A timed effect ran out and left the stack"},
+	{EffectResumed{}, "An effect shows again after the one above it ended, or its countdown continues after a pause"},
+	{EffectPaused{}, "The countdown of the current effect was suspended"},
+	{EffectsCleared{}, "stopAllEffects removed entries from the effect stack"},
+	{Progress{}, "A timed effect's countdown, published every second, or a sequence step starting"},
+	{StackOverflow{}, "An effect was pushed onto a full effect stack"},
+	{DimChanged{}, "The global brightness changed"},
+	{RingUpdate{}, "The LEDs or brightness of a ring changed"},
+	{RawExecuted{}, "A query was sent to the UFO"},
+	{ButtonPress{}, "The UFO's button was pressed"},
+	{StateReconciled{}, "The shadow state was corrected from what the UFO reports"},
+	{AlertFiring{}, "An alert was raised"},
+	{AlertResolved{}, "An alert was cleared or expired"},
+	{AlertAcknowledged{}, "An integration alert was acknowledged"},
+	{ChannelStatus{}, "A status board channel changed its status"},
+	{DeviceOffline{}, "The UFO stopped answering"},
+	{DeviceOnline{}, "The UFO answers again"},
+	{DeviceRebooted{}, "The UFO restarted"},
+	{DeviceReconfigured{}, "The server switched to another UFO address"},
+	{FirmwareUpdate{}, "A firmware update moved on"},
+	{VoteCast{}, "A vote was cast in a castVote round"},
+	{VoteClosed{}, "A castVote round closed"},
+	{FeatureChanged{}, "An optional feature became available or unavailable"},
+	{VUMeter{}, "The VU meter started or stopped"},
+}
+
+// envelope documents the fields of Event
+var envelope = []FieldSchema{
+	{Name: "type", Type: "string", Description: "Event type"},
+	{Name: "timestamp", Type: "string", Description: "When the event was published, RFC 3339"},
+	{Name: "data", Type: "object", Description: "Fields of the event type", Optional: true},
+	{Name: "requestId", Type: "string", Description: "Tool call that caused the event", Optional: true},
+	{Name: "device", Type: "string", Description: "Address of the UFO the event is about", Optional: true},
+}
+
+// EventSchema returns the schema of every event type, built from the
+// payload types so it cannot drift from what is published
+func EventSchema() Schema {
+	schema := Schema{Envelope: envelope}
+	for _, entry := range catalog {
+		schema.Events = append(schema.Events, TypeSchema{
+			Type:        entry.payload.EventType(),
+			Description: entry.description,
+			Data:        fieldSchemas(reflect.TypeOf(entry.payload)),
+		})
+	}
+	return schema
+}
+
+// fieldSchemas documents the fields of the struct type t
+func fieldSchemas(t reflect.Type) []FieldSchema {
+	fields := []FieldSchema{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			fields = append(fields, fieldSchemas(field.Type)...)
+			continue
+		}
+		name, omitEmpty := jsonName(field)
+		if name == "" {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		schema := FieldSchema{
+			Name:        name,
+			Type:        jsonType(fieldType),
+			Description: field.Tag.Get("doc"),
+			Optional:    omitEmpty,
+		}
+		if fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() == reflect.Struct {
+			schema.Items = fieldSchemas(fieldType.Elem())
+		}
+		fields = append(fields, schema)
+	}
+	return fields
+}
+
+// jsonType names the JSON type values of t are encoded as
+func jsonType(t reflect.Type) string {
+	if t == reflect.TypeOf(time.Time{}) {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	}
+	return "object"
+}
@@ -0,0 +1,181 @@
+// Package features tracks which of the server's optional capabilities are
+// working, so the server can start without the ones whose dependencies
+// failed, report them, and bring them back when the dependencies return.
+package features
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Feature statuses
+const (
+	Available   = "available"   // working normally
+	Degraded    = "degraded"    // working with reduced capability
+	Unavailable = "unavailable" // not working; its tools are not offered
+)
+
+// Names of the features the server tracks
+const (
+	Device          = "device"          // the UFO itself
+	Devices         = "devices"         // known UFOs with their nicknames
+	Palettes        = "palettes"        // saved color palettes
+	Macros          = "macros"          // saved macros
+	Scenes          = "scenes"          // saved lighting scenes
+	StateHistory    = "stateHistory"    // recorded states for diffStates
+	EffectStack     = "effectStack"     // the effect stack saved across restarts
+	EffectRevisions = "effectRevisions" // earlier versions of stored effects
+	Hooks           = "hooks"           // external command hooks
+	Integrations    = "integrations"    // integrations from the integrations file
+	Dynatrace       = "dynatrace"       // Dynatrace problem polling
+	Webhook         = "webhook"         // the generic webhook endpoint
+	Channels        = "channels"        // the status board from the channels file
+	HomeAssistant   = "homeAssistant"   // the Home Assistant light entity over MQTT
+)
+
+// RetryInterval is how often the data of an unavailable feature is loaded
+// again
+const RetryInterval = 30 * time.Second
+
+// Feature is the availability of one optional capability
+type Feature struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	Reason      string    `json:"reason,omitempty"` // why it is not available
+	Since       time.Time `json:"since"`            // when the status last changed
+	Tools       []string  `json:"tools,omitempty"`  // tools that depend on it
+}
+
+// Registry holds the availability of the server's optional features
+type Registry struct {
+	mu       sync.Mutex
+	features map[string]*Feature
+	onChange func(Feature)
+}
+
+// NewRegistry creates an empty feature registry
+func NewRegistry() *Registry {
+	return &Registry{features: make(map[string]*Feature)}
+}
+
+// Register adds a feature, available until told otherwise, with the tools
+// that depend on it
+func (r *Registry) Register(name, description string, tools ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.features[name] = &Feature{
+		Name:        name,
+		Description: description,
+		Status:      Available,
+		Since:       time.Now(),
+		Tools:       tools,
+	}
+}
+
+// Set changes a feature's status. reason says why it is not available and
+// is ignored when it is. The change function is called when the status or
+// reason changed. Unknown features are registered on the way.
+func (r *Registry) Set(name, status, reason string) {
+	if status == Available {
+		reason = ""
+	}
+
+	r.mu.Lock()
+	feature, exists := r.features[name]
+	if !exists {
+		feature = &Feature{Name: name, Status: Available}
+		r.features[name] = feature
+	}
+	if exists && feature.Status == status && feature.Reason == reason {
+		r.mu.Unlock()
+		return
+	}
+	feature.Status, feature.Reason, feature.Since = status, reason, time.Now()
+	changed := clone(feature)
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	if onChange != nil {
+		onChange(changed)
+	}
+}
+
+// OnChange registers a function called after a feature's status changes
+func (r *Registry) OnChange(fn func(Feature)) {
+	r.mu.Lock()
+	r.onChange = fn
+	r.mu.Unlock()
+}
+
+// Get returns the named feature
+func (r *Registry) Get(name string) (Feature, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	feature, exists := r.features[name]
+	if !exists {
+		return Feature{}, false
+	}
+	return clone(feature), true
+}
+
+// List returns every feature ordered by name
+func (r *Registry) List() []Feature {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := make([]Feature, 0, len(r.features))
+	for _, feature := range r.features {
+		list = append(list, clone(feature))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Impaired returns the features that are not fully available
+func (r *Registry) Impaired() []Feature {
+	impaired := []Feature{}
+	for _, feature := range r.List() {
+		if feature.Status != Available {
+			impaired = append(impaired, feature)
+		}
+	}
+	return impaired
+}
+
+// Retry calls load in the background every interval until it succeeds or
+// ctx ends. On success the feature is marked available and then enable is
+// called, for instance to register the feature's tools.
+func (r *Registry) Retry(ctx context.Context, name string, interval time.Duration, load func() error, enable func()) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := load(); err != nil {
+				slog.DebugContext(ctx, "Feature still unavailable", "feature", name, "error", err)
+				r.Set(name, Unavailable, err.Error())
+				continue
+			}
+			slog.InfoContext(ctx, "Feature recovered", "feature", name)
+			r.Set(name, Available, "")
+			if enable != nil {
+				enable()
+			}
+			return
+		}
+	}()
+}
+
+// clone copies a feature so callers cannot change the registry's
+func clone(feature *Feature) Feature {
+	copied := *feature
+	copied.Tools = append([]string(nil), feature.Tools...)
+	return copied
+}
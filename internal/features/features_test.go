@@ -0,0 +1,114 @@
+package features
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRegistry_SetReportsChanges(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Palettes, "Saved color palettes", "savePalette", "listPalettes")
+
+	var mu sync.Mutex
+	var changes []Feature
+	registry.OnChange(func(feature Feature) {
+		mu.Lock()
+		changes = append(changes, feature)
+		mu.Unlock()
+	})
+
+	registry.Set(Palettes, Available, "") // unchanged
+	registry.Set(Palettes, Unavailable, "permission denied")
+	registry.Set(Palettes, Unavailable, "permission denied") // unchanged
+	registry.Set(Palettes, Available, "ignored")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %v", changes)
+	}
+	if changes[0].Status != Unavailable || changes[0].Reason != "permission denied" {
+		t.Errorf("expected unavailable with its reason, got %+v", changes[0])
+	}
+	if changes[1].Status != Available || changes[1].Reason != "" {
+		t.Errorf("expected available without a reason, got %+v", changes[1])
+	}
+
+	feature, ok := registry.Get(Palettes)
+	if !ok || len(feature.Tools) != 2 || feature.Description != "Saved color palettes" {
+		t.Errorf("expected the registered feature, got %+v", feature)
+	}
+}
+
+func TestRegistry_Impaired(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Device, "The UFO itself")
+	registry.Register(Macros, "Saved macros")
+	registry.Register(Hooks, "Hooks")
+	if impaired := registry.Impaired(); len(impaired) != 0 {
+		t.Errorf("expected nothing impaired, got %v", impaired)
+	}
+
+	registry.Set(Macros, Unavailable, "bad JSON")
+	registry.Set(Device, Degraded, "slow")
+	impaired := registry.Impaired()
+	if len(impaired) != 2 || impaired[0].Name != Device || impaired[1].Name != Macros {
+		t.Errorf("expected device and macros impaired, got %v", impaired)
+	}
+	if list := registry.List(); len(list) != 3 {
+		t.Errorf("expected 3 features, got %d", len(list))
+	}
+}
+
+func TestRegistry_RetryRecovers(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Macros, "Saved macros")
+	registry.Set(Macros, Unavailable, "not mounted")
+
+	var attempts atomic.Int32
+	enabled := make(chan struct{})
+	registry.Retry(context.Background(), Macros, 10*time.Millisecond, func() error {
+		if attempts.Add(1) < 3 {
+			return errors.New("still not mounted")
+		}
+		return nil
+	}, func() { close(enabled) })
+
+	select {
+	case <-enabled:
+	case <-time.After(time.Second):
+		t.Fatal("feature was not enabled after loading")
+	}
+	if feature, _ := registry.Get(Macros); feature.Status != Available {
+		t.Errorf("expected macros available, got %+v", feature)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestRegistry_RetryStopsWithContext(t *testing.T) {
+	registry := NewRegistry()
+	ctx, cancel := context.WithCancel(context.Background())
+	var attempts atomic.Int32
+	registry.Retry(ctx, Hooks, 5*time.Millisecond, func() error {
+		attempts.Add(1)
+		return errors.New("missing")
+	}, nil)
+
+	time.Sleep(30 * time.Millisecond)
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	stopped := attempts.Load()
+	time.Sleep(30 * time.Millisecond)
+	if attempts.Load() != stopped {
+		t.Error("retries went on after the context ended")
+	}
+	if feature, _ := registry.Get(Hooks); feature.Status != Unavailable || feature.Reason != "missing" {
+		t.Errorf("expected hooks unavailable, got %+v", feature)
+	}
+}
@@ -0,0 +1,54 @@
+// Package format renders durations and times in human readable tool
+// messages, so every tool shows them the same way.
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+)
+
+// Duration formats d for people followed by the exact milliseconds, e.g.
+// "1m 30s (90000 ms)". Durations under a second are shown in milliseconds
+// only, e.g. "750 ms".
+func Duration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	if d < time.Second {
+		return fmt.Sprintf("%d ms", ms)
+	}
+
+	var parts []string
+	if h := d / time.Hour; h > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", h))
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", m))
+		d -= m * time.Minute
+	}
+	if len(parts) == 0 {
+		// Under a minute, keep tenths of a second
+		seconds := strconv.FormatFloat(d.Truncate(100*time.Millisecond).Seconds(), 'f', -1, 64)
+		parts = append(parts, seconds+"s")
+	} else if s := d / time.Second; s > 0 {
+		parts = append(parts, fmt.Sprintf("%ds", s))
+	}
+	return fmt.Sprintf("%s (%d ms)", strings.Join(parts, " "), ms)
+}
+
+// Millis formats a duration given in milliseconds, see Duration
+func Millis(ms int64) string {
+	return Duration(time.Duration(ms) * time.Millisecond)
+}
+
+// Time formats t as an RFC 3339 timestamp in the configured time zone, e.g.
+// "2024-03-01T14:05:09+01:00"
+func Time(t time.Time) string {
+	return timezone.ISO(t)
+}
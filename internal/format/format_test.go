@@ -0,0 +1,47 @@
+package format
+
+import (
+	"testing"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+)
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0 ms"},
+		{-time.Second, "0 ms"},
+		{750 * time.Millisecond, "750 ms"},
+		{time.Second, "1s (1000 ms)"},
+		{2500 * time.Millisecond, "2.5s (2500 ms)"},
+		{5049 * time.Millisecond, "5s (5049 ms)"},
+		{90 * time.Second, "1m 30s (90000 ms)"},
+		{2 * time.Minute, "2m (120000 ms)"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "1h 2m 3s (3723000 ms)"},
+	}
+	for _, tt := range tests {
+		if got := Duration(tt.d); got != tt.want {
+			t.Errorf("Duration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+	if got := Millis(30000); got != "30s (30000 ms)" {
+		t.Errorf("Millis(30000) = %q", got)
+	}
+}
+
+func TestTime(t *testing.T) {
+	defer timezone.Set(nil)
+	loc, err := timezone.Load("Europe/Vienna")
+	if err != nil {
+		t.Fatalf("loading zone: %v", err)
+	}
+	timezone.Set(loc)
+
+	instant := time.Date(2024, 3, 1, 13, 5, 9, 0, time.UTC)
+	if got := Time(instant); got != "2024-03-01T14:05:09+01:00" {
+		t.Errorf("Time = %q, want 2024-03-01T14:05:09+01:00", got)
+	}
+}
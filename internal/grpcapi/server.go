@@ -0,0 +1,314 @@
+// Package grpcapi serves the gRPC management API defined in
+// api/ufo/v1/ufo.proto. Its methods call the server's MCP tools, so they
+// behave exactly like the tools of the same names.
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	ufov1 "github.com/starspace46/ufo-mcp-go/api/ufo/v1"
+	"github.com/starspace46/ufo-mcp-go/internal/auth"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ToolCaller calls the server's MCP tools by name, as a client would
+type ToolCaller interface {
+	CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error)
+}
+
+// Server implements the UfoService gRPC service
+type Server struct {
+	ufov1.UnimplementedUfoServiceServer
+
+	caller      ToolCaller
+	broadcaster *events.Broadcaster
+	streams     atomic.Uint64 // numbers event stream subscriptions
+}
+
+// NewServer creates a service calling tools through caller and streaming the
+// events of broadcaster
+func NewServer(caller ToolCaller, broadcaster *events.Broadcaster) *Server {
+	return &Server{
+		caller:      caller,
+		broadcaster: broadcaster,
+	}
+}
+
+// ConfigureLighting calls the configureLighting tool
+func (s *Server) ConfigureLighting(ctx context.Context, request *ufov1.ConfigureLightingRequest) (*ufov1.ToolResult, error) {
+	arguments := map[string]interface{}{}
+	if ring := ringArguments(request.GetTop()); ring != nil {
+		arguments["top"] = ring
+	}
+	if ring := ringArguments(request.GetBottom()); ring != nil {
+		arguments["bottom"] = ring
+	}
+	if ring := ringArguments(request.GetBoth()); ring != nil {
+		arguments["both"] = ring
+	}
+	if request.GetMirrorBottom() {
+		arguments["mirrorBottom"] = true
+	}
+	if logo := logoArguments(request.GetLogo()); logo != nil {
+		arguments["logo"] = logo
+	}
+	if request.Brightness != nil {
+		arguments["brightness"] = request.GetBrightness()
+	}
+	if request.GetApplyAndVerify() {
+		arguments["applyAndVerify"] = true
+	}
+	if request.GetPassthrough() {
+		arguments["passthrough"] = true
+	}
+	return s.call(ctx, "configureLighting", arguments)
+}
+
+// PlayEffect calls the playEffect tool
+func (s *Server) PlayEffect(ctx context.Context, request *ufov1.PlayEffectRequest) (*ufov1.ToolResult, error) {
+	arguments := map[string]interface{}{"name": request.GetName()}
+	if request.DurationMs != nil {
+		arguments["duration"] = request.GetDurationMs()
+	}
+	if len(request.GetParams()) > 0 {
+		params := map[string]interface{}{}
+		for name, value := range request.GetParams() {
+			params[name] = value
+		}
+		arguments["params"] = params
+	}
+	if request.GetBackground() {
+		arguments["background"] = true
+	}
+	return s.call(ctx, "playEffect", arguments)
+}
+
+// StopEffect calls the stopEffect tool
+func (s *Server) StopEffect(ctx context.Context, request *ufov1.StopEffectRequest) (*ufov1.ToolResult, error) {
+	arguments := map[string]interface{}{}
+	if request.GetInstanceId() != "" {
+		arguments["instanceId"] = request.GetInstanceId()
+	}
+	return s.call(ctx, "stopEffect", arguments)
+}
+
+// CallTool calls any tool by name
+func (s *Server) CallTool(ctx context.Context, request *ufov1.CallToolRequest) (*ufov1.ToolResult, error) {
+	if request.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	return s.call(ctx, request.GetName(), request.GetArguments().AsMap())
+}
+
+// StreamEvents sends the events published from now on until the client
+// cancels or the server shuts down
+func (s *Server) StreamEvents(request *ufov1.StreamEventsRequest, stream ufov1.UfoService_StreamEventsServer) error {
+	id := fmt.Sprintf("grpc-%d", s.streams.Add(1))
+	subscriber := s.broadcaster.SubscribeFiltered(id, events.SubscriptionFilter{Types: request.GetTypes()})
+	defer s.broadcaster.Unsubscribe(id)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-subscriber.Channel:
+			if !ok {
+				return status.Error(codes.Unavailable, "server is shutting down")
+			}
+			message, err := eventMessage(event)
+			if err != nil {
+				slog.Warn("Dropping event that cannot be sent over gRPC", "type", event.Type, "error", err)
+				continue
+			}
+			if err := stream.Send(message); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// call runs a tool and converts its result. A tool reporting an error is a
+// successful call with IsError set, as over MCP; a call that cannot run
+// fails with a gRPC status.
+func (s *Server) call(ctx context.Context, name string, arguments map[string]interface{}) (*ufov1.ToolResult, error) {
+	result, err := s.caller.CallTool(ctx, name, arguments)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	var text []string
+	for _, content := range result.Content {
+		if textContent, ok := content.(mcp.TextContent); ok {
+			text = append(text, textContent.Text)
+		}
+	}
+	return &ufov1.ToolResult{Text: strings.Join(text, "\n"), IsError: result.IsError}, nil
+}
+
+// ringArguments converts a ring configuration to configureLighting
+// arguments, or nil when it is not set
+func ringArguments(ring *ufov1.RingConfig) map[string]interface{} {
+	if ring == nil {
+		return nil
+	}
+	arguments := map[string]interface{}{}
+	if len(ring.GetSegments()) > 0 {
+		segments := make([]interface{}, len(ring.GetSegments()))
+		for i, segment := range ring.GetSegments() {
+			segments[i] = segment
+		}
+		arguments["segments"] = segments
+	}
+	if ring.GetPalette() != "" {
+		arguments["palette"] = ring.GetPalette()
+	}
+	if ring.GetBackground() != "" {
+		arguments["background"] = ring.GetBackground()
+	}
+	if ring.Whirl != nil {
+		arguments["whirl"] = ring.GetWhirl()
+	}
+	if ring.GetCounterClockwise() {
+		arguments["counterClockwise"] = true
+	}
+	if gradient := ring.GetGradient(); gradient != nil {
+		arguments["gradient"] = gradientArguments(gradient)
+	}
+	if ring.Brightness != nil {
+		arguments["brightness"] = ring.GetBrightness()
+	}
+	if morph := ring.GetMorph(); morph != nil {
+		arguments["morph"] = map[string]interface{}{
+			"brightnessMs": morph.GetBrightnessMs(),
+			"fadeMs":       morph.GetFadeMs(),
+		}
+	}
+	return arguments
+}
+
+// gradientArguments converts a gradient to configureLighting arguments
+func gradientArguments(gradient *ufov1.Gradient) map[string]interface{} {
+	arguments := map[string]interface{}{
+		"from": gradient.GetFrom(),
+		"to":   gradient.GetTo(),
+	}
+	if gradient.Start != nil {
+		arguments["start"] = gradient.GetStart()
+	}
+	if gradient.Length != nil {
+		arguments["length"] = gradient.GetLength()
+	}
+	if gradient.GetEasing() != "" {
+		arguments["easing"] = gradient.GetEasing()
+	}
+	return arguments
+}
+
+// logoArguments converts a logo configuration to configureLighting
+// arguments, or nil when it is not set
+func logoArguments(logo *ufov1.LogoConfig) map[string]interface{} {
+	if logo == nil {
+		return nil
+	}
+	arguments := map[string]interface{}{}
+	for name, value := range map[string]string{
+		"state":     logo.GetState(),
+		"color1":    logo.GetColor1(),
+		"color2":    logo.GetColor2(),
+		"animation": logo.GetAnimation(),
+	} {
+		if value != "" {
+			arguments[name] = value
+		}
+	}
+	if logo.PeriodMs != nil {
+		arguments["periodMs"] = logo.GetPeriodMs()
+	}
+	return arguments
+}
+
+// eventMessage converts an event to its protobuf message. The data goes
+// through JSON first, so it arrives as ufo://events/recent shows it.
+func eventMessage(event events.Event) (*ufov1.Event, error) {
+	encoded, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(encoded, &values); err != nil {
+		return nil, err
+	}
+	data, err := structpb.NewStruct(values)
+	if err != nil {
+		return nil, err
+	}
+	return &ufov1.Event{
+		Type:      event.Type,
+		Timestamp: timestamppb.New(event.Timestamp),
+		Data:      data,
+		RequestId: event.RequestID,
+	}, nil
+}
+
+// authInterceptors reject calls without an accepted token in the
+// "authorization" (Bearer) or "x-api-key" metadata
+func authInterceptors(authenticator *auth.Authenticator) []grpc.ServerOption {
+	authenticate := func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if !authenticator.AuthenticateCredentials(first(md.Get("authorization")), first(md.Get(auth.APIKeyHeader))) {
+			return status.Error(codes.Unauthenticated, "missing or invalid token")
+		}
+		return nil
+	}
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := authenticate(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, request)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := authenticate(stream.Context()); err != nil {
+				return err
+			}
+			return handler(srv, stream)
+		}),
+	}
+}
+
+// first returns the first of values, or "" when there is none
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Serve serves the service on listener until ctx is cancelled. A nil
+// authenticator accepts every call.
+func Serve(ctx context.Context, listener net.Listener, service *Server, authenticator *auth.Authenticator) error {
+	var options []grpc.ServerOption
+	if authenticator != nil {
+		options = authInterceptors(authenticator)
+	}
+	grpcServer := grpc.NewServer(options...)
+	ufov1.RegisterUfoServiceServer(grpcServer, service)
+
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+	}()
+	return grpcServer.Serve(listener)
+}
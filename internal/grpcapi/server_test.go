@@ -0,0 +1,152 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	ufov1 "github.com/starspace46/ufo-mcp-go/api/ufo/v1"
+	"github.com/starspace46/ufo-mcp-go/internal/auth"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// recordingCaller records tool calls and answers each with a fixed result
+type recordingCaller struct {
+	name      string
+	arguments map[string]interface{}
+	result    *mcp.CallToolResult
+}
+
+func (c *recordingCaller) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	c.name, c.arguments = name, arguments
+	return c.result, nil
+}
+
+// dial serves the service in memory and returns a client for it
+func dial(t *testing.T, service *Server, authenticator *auth.Authenticator) ufov1.UfoServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go Serve(ctx, listener, service, authenticator)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return ufov1.NewUfoServiceClient(conn)
+}
+
+func TestServer_ConfigureLighting(t *testing.T) {
+	caller := &recordingCaller{result: mcp.NewToolResultText("Lighting configured")}
+	client := dial(t, NewServer(caller, events.NewBroadcaster()), nil)
+
+	result, err := client.ConfigureLighting(context.Background(), &ufov1.ConfigureLightingRequest{
+		Top: &ufov1.RingConfig{
+			Segments: []string{"0|5|FF0000"},
+			Whirl:    proto.Int32(0),
+			Morph:    &ufov1.Morph{BrightnessMs: 1000, FadeMs: 500},
+		},
+		Logo:       &ufov1.LogoConfig{State: "on"},
+		Brightness: proto.Int32(128),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Lighting configured", result.Text)
+	assert.False(t, result.IsError)
+
+	assert.Equal(t, "configureLighting", caller.name)
+	assert.Equal(t, map[string]interface{}{
+		"top": map[string]interface{}{
+			"segments": []interface{}{"0|5|FF0000"},
+			"whirl":    int32(0),
+			"morph":    map[string]interface{}{"brightnessMs": int32(1000), "fadeMs": int32(500)},
+		},
+		"logo":       map[string]interface{}{"state": "on"},
+		"brightness": int32(128),
+	}, caller.arguments)
+}
+
+func TestServer_ToolErrors(t *testing.T) {
+	caller := &recordingCaller{result: mcp.NewToolResultError("Error: Effect 'nope' not found")}
+	client := dial(t, NewServer(caller, events.NewBroadcaster()), nil)
+
+	result, err := client.PlayEffect(context.Background(), &ufov1.PlayEffectRequest{
+		Name:       "nope",
+		DurationMs: proto.Int64(5000),
+		Params:     map[string]string{"color": "FF0000"},
+	})
+	require.NoError(t, err, "a tool error is reported in the result")
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Text, "not found")
+	assert.Equal(t, map[string]interface{}{
+		"name":     "nope",
+		"duration": int64(5000),
+		"params":   map[string]interface{}{"color": "FF0000"},
+	}, caller.arguments)
+
+	_, err = client.CallTool(context.Background(), &ufov1.CallToolRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_Authentication(t *testing.T) {
+	caller := &recordingCaller{result: mcp.NewToolResultText("stopped")}
+	client := dial(t, NewServer(caller, events.NewBroadcaster()), auth.New([]string{"secret"}))
+
+	_, err := client.StopEffect(context.Background(), &ufov1.StopEffectRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Empty(t, caller.name, "an unauthenticated call must not reach the tool")
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	_, err = client.StopEffect(ctx, &ufov1.StopEffectRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "stopEffect", caller.name)
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret")
+	_, err = client.StopEffect(ctx, &ufov1.StopEffectRequest{})
+	assert.NoError(t, err)
+}
+
+func TestServer_StreamEvents(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	client := dial(t, NewServer(&recordingCaller{}, broadcaster), nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.StreamEvents(ctx, &ufov1.StreamEventsRequest{Types: []string{events.EventDimChanged}})
+	require.NoError(t, err)
+
+	// Publish until the stream has subscribed; other types are filtered out
+	received := make(chan *ufov1.Event, 1)
+	go func() {
+		event, err := stream.Recv()
+		if err == nil {
+			received <- event
+		}
+	}()
+	for {
+		broadcaster.PublishEffectStarted("rainbow", 1000)
+		broadcaster.PublishDimChanged(42)
+		select {
+		case event := <-received:
+			assert.Equal(t, events.EventDimChanged, event.Type)
+			assert.Equal(t, float64(42), event.Data.AsMap()["level"])
+			assert.False(t, event.Timestamp.AsTime().IsZero())
+			return
+		case <-ctx.Done():
+			t.Fatal("no event received")
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
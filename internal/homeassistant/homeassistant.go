@@ -0,0 +1,375 @@
+package homeassistant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// Defaults of the MQTT configuration file
+const (
+	defaultDiscoveryPrefix = "homeassistant"
+	defaultTopicPrefix     = "ufo"
+	defaultObjectID        = "ufo"
+	defaultName            = "UFO"
+	defaultColor           = "ffffff"
+)
+
+// passwordEnv supplies the broker password when the configuration file has
+// none, so it can be kept out of the file
+const passwordEnv = "UFO_MQTT_PASSWORD"
+
+// publishTimeout bounds how long a publish may wait for the broker
+const publishTimeout = 5 * time.Second
+
+// Config is the MQTT configuration file
+type Config struct {
+	Broker          string `json:"broker"`                    // e.g. tcp://homeassistant.local:1883
+	ClientID        string `json:"clientId,omitempty"`        // default ufo-mcp-<objectId>
+	Username        string `json:"username,omitempty"`        // broker user
+	Password        string `json:"password,omitempty"`        // broker password, else UFO_MQTT_PASSWORD
+	DiscoveryPrefix string `json:"discoveryPrefix,omitempty"` // Home Assistant's discovery prefix, default homeassistant
+	TopicPrefix     string `json:"topicPrefix,omitempty"`     // prefix of the state and command topics, default ufo
+	ObjectID        string `json:"objectId,omitempty"`        // entity object ID, default ufo
+	Name            string `json:"name,omitempty"`            // entity name, default UFO
+}
+
+// Load reads the MQTT configuration file. The password falls back to the
+// UFO_MQTT_PASSWORD environment variable.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading MQTT config file: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing MQTT config JSON: %w", err)
+	}
+	if cfg.Password == "" {
+		cfg.Password = os.Getenv(passwordEnv)
+	}
+	if cfg.Broker == "" {
+		return nil, fmt.Errorf("mqtt: broker is required")
+	}
+	return &cfg, nil
+}
+
+// withDefaults fills in the settings the file left out
+func (cfg Config) withDefaults() Config {
+	if cfg.DiscoveryPrefix == "" {
+		cfg.DiscoveryPrefix = defaultDiscoveryPrefix
+	}
+	if cfg.TopicPrefix == "" {
+		cfg.TopicPrefix = defaultTopicPrefix
+	}
+	if cfg.ObjectID == "" {
+		cfg.ObjectID = defaultObjectID
+	}
+	if cfg.Name == "" {
+		cfg.Name = defaultName
+	}
+	if cfg.ClientID == "" {
+		cfg.ClientID = "ufo-mcp-" + cfg.ObjectID
+	}
+	return cfg
+}
+
+// ToolCaller calls a tool by name. The server implements it, so commands
+// from Home Assistant pass the same policy and audit as any other call.
+type ToolCaller interface {
+	CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error)
+}
+
+// RGB is a color as Home Assistant's JSON light schema gives it
+type RGB struct {
+	R int `json:"r"`
+	G int `json:"g"`
+	B int `json:"b"`
+}
+
+// LightState is the JSON light schema message of the state and command
+// topics
+type LightState struct {
+	State      string `json:"state"`                // ON or OFF
+	Brightness *int   `json:"brightness,omitempty"` // 0-255
+	Color      *RGB   `json:"color,omitempty"`
+	ColorMode  string `json:"color_mode,omitempty"`
+}
+
+// Bridge shows the UFO in Home Assistant as a light entity found by MQTT
+// discovery: the rings' color and the global brightness are published on
+// the state topic, and commands from the command topic are turned into
+// configureLighting calls
+type Bridge struct {
+	cfg          Config
+	caller       ToolCaller
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+
+	mu        sync.Mutex
+	client    mqtt.Client
+	lastColor string // hex color the light turns on with
+	published []byte // last state message, so only changes are published
+}
+
+// NewBridge creates the Home Assistant bridge from its configuration
+func NewBridge(cfg Config, caller ToolCaller, broadcaster *events.Broadcaster, stateManager *state.Manager) *Bridge {
+	return &Bridge{
+		cfg:          cfg.withDefaults(),
+		caller:       caller,
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		lastColor:    defaultColor,
+	}
+}
+
+// baseTopic returns the topic the entity's own topics live under
+func (b *Bridge) baseTopic() string {
+	return b.cfg.TopicPrefix + "/" + b.cfg.ObjectID
+}
+
+func (b *Bridge) stateTopic() string        { return b.baseTopic() + "/state" }
+func (b *Bridge) commandTopic() string      { return b.baseTopic() + "/set" }
+func (b *Bridge) availabilityTopic() string { return b.baseTopic() + "/availability" }
+
+// discoveryTopic is where Home Assistant looks for the entity's configuration
+func (b *Bridge) discoveryTopic() string {
+	return b.cfg.DiscoveryPrefix + "/light/" + b.cfg.ObjectID + "/config"
+}
+
+// Discovery returns the discovery message announcing the light entity
+func (b *Bridge) Discovery() []byte {
+	message, _ := json.Marshal(map[string]interface{}{
+		"name":                  nil, // the entity is the device's main feature
+		"unique_id":             "ufo_mcp_" + b.cfg.ObjectID,
+		"schema":                "json",
+		"state_topic":           b.stateTopic(),
+		"command_topic":         b.commandTopic(),
+		"availability_topic":    b.availabilityTopic(),
+		"brightness":            true,
+		"brightness_scale":      255,
+		"supported_color_modes": []string{"rgb"},
+		"device": map[string]interface{}{
+			"identifiers":  []string{"ufo_mcp_" + b.cfg.ObjectID},
+			"name":         b.cfg.Name,
+			"manufacturer": "Dynatrace",
+			"model":        "UFO",
+		},
+	})
+	return message
+}
+
+// Start connects to the broker in the background, reconnecting as needed.
+// On every connection the entity is announced and marked available, the
+// command topic subscribed and the state published; the state is published
+// again as events change it. The broker marks the entity unavailable when
+// the server goes away.
+func (b *Bridge) Start(ctx context.Context) {
+	opts := mqtt.NewClientOptions().
+		AddBroker(b.cfg.Broker).
+		SetClientID(b.cfg.ClientID).
+		SetUsername(b.cfg.Username).
+		SetPassword(b.cfg.Password).
+		SetWill(b.availabilityTopic(), "offline", 1, true).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		// Commands call the UFO and publish, so each gets its own goroutine
+		SetOrderMatters(false).
+		SetOnConnectHandler(func(client mqtt.Client) {
+			slog.Info("Connected to MQTT broker", "broker", b.cfg.Broker)
+			b.announce(ctx, client)
+		}).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			slog.Warn("Lost MQTT connection, reconnecting", "broker", b.cfg.Broker, "error", err)
+		})
+
+	client := mqtt.NewClient(opts)
+	b.mu.Lock()
+	b.client = client
+	b.mu.Unlock()
+	client.Connect()
+
+	sub := b.broadcaster.Subscribe("homeassistant")
+	go func() {
+		defer func() {
+			b.publish(b.availabilityTopic(), []byte("offline"))
+			client.Disconnect(250)
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				b.publishState(false)
+			}
+		}
+	}()
+}
+
+// announce publishes the entity's discovery message, availability and state,
+// and subscribes to its command topic and to Home Assistant's status, which
+// says "online" when Home Assistant restarts and needs the entity again
+func (b *Bridge) announce(ctx context.Context, client mqtt.Client) {
+	client.Subscribe(b.commandTopic(), 1, func(_ mqtt.Client, message mqtt.Message) {
+		if err := b.HandleCommand(ctx, message.Payload()); err != nil {
+			slog.Warn("Home Assistant command failed", "error", err)
+		}
+	})
+	client.Subscribe(b.cfg.DiscoveryPrefix+"/status", 1, func(_ mqtt.Client, message mqtt.Message) {
+		if string(message.Payload()) == "online" {
+			b.publish(b.discoveryTopic(), b.Discovery())
+			b.publishState(true)
+		}
+	})
+	b.publish(b.discoveryTopic(), b.Discovery())
+	b.publish(b.availabilityTopic(), []byte("online"))
+	b.publishState(true)
+}
+
+// publishState publishes the light's state when it changed since it was
+// last published, or always when force is set
+func (b *Bridge) publishState(force bool) {
+	message, _ := json.Marshal(b.State())
+
+	b.mu.Lock()
+	unchanged := string(message) == string(b.published)
+	b.published = message
+	b.mu.Unlock()
+
+	if !unchanged || force {
+		b.publish(b.stateTopic(), message)
+	}
+}
+
+// publish sends a retained message, logging failures
+func (b *Bridge) publish(topic string, payload []byte) {
+	b.mu.Lock()
+	client := b.client
+	b.mu.Unlock()
+	if client == nil || !client.IsConnectionOpen() {
+		return
+	}
+
+	token := client.Publish(topic, 1, true, payload)
+	if !token.WaitTimeout(publishTimeout) {
+		slog.Warn("MQTT publish timed out", "topic", topic)
+	} else if err := token.Error(); err != nil {
+		slog.Warn("MQTT publish failed", "topic", topic, "error", err)
+	}
+}
+
+// State returns the light's state from the shadow state: on while an LED of
+// either ring is lit, in the color most of the lit LEDs show, at the global
+// brightness. The color is remembered for turning the light on again.
+func (b *Bridge) State() LightState {
+	snapshot := b.stateManager.Snapshot()
+
+	counts := map[string]int{}
+	var main string
+	for _, hex := range append(snapshot.Top[:], snapshot.Bottom[:]...) {
+		hex = strings.ToLower(strings.TrimPrefix(hex, "#"))
+		if hex == "" || hex == "000000" {
+			continue
+		}
+		counts[hex]++
+		if counts[hex] > counts[main] {
+			main = hex
+		}
+	}
+	if main == "" {
+		return LightState{State: "OFF"}
+	}
+
+	b.mu.Lock()
+	b.lastColor = main
+	b.mu.Unlock()
+
+	brightness := snapshot.Dim
+	rgb := toRGB(main)
+	return LightState{State: "ON", Brightness: &brightness, Color: &rgb, ColorMode: "rgb"}
+}
+
+// HandleCommand turns a command topic message into a configureLighting
+// call. Turning the light on fills both rings with the color given, or the
+// last color shown; brightness alone only changes the global brightness
+// while the light is on. Turning it off darkens the rings and the logo.
+func (b *Bridge) HandleCommand(ctx context.Context, payload []byte) error {
+	var command LightState
+	if err := json.Unmarshal(payload, &command); err != nil {
+		return fmt.Errorf("parsing command: %w", err)
+	}
+	if command.Brightness != nil && (*command.Brightness < 0 || *command.Brightness > 255) {
+		return fmt.Errorf("brightness must be 0-255, got %d", *command.Brightness)
+	}
+
+	arguments := map[string]interface{}{}
+	switch strings.ToUpper(command.State) {
+	case "OFF":
+		arguments["both"] = map[string]interface{}{"background": "000000"}
+		arguments["logo"] = map[string]interface{}{"state": "off"}
+	case "ON":
+		on := b.State().State == "ON"
+		if command.Color != nil || !on {
+			b.mu.Lock()
+			hex := b.lastColor
+			b.mu.Unlock()
+			if command.Color != nil {
+				hex = command.Color.hex()
+			}
+			arguments["both"] = map[string]interface{}{"background": hex}
+			arguments["logo"] = map[string]interface{}{"state": "on"}
+		}
+		if command.Brightness != nil {
+			arguments["brightness"] = *command.Brightness
+		}
+	default:
+		return fmt.Errorf("state must be ON or OFF, got %q", command.State)
+	}
+	if len(arguments) == 0 {
+		b.publishState(true)
+		return nil
+	}
+
+	result, err := b.caller.CallTool(ctx, "configureLighting", arguments)
+	if err != nil {
+		return err
+	}
+	if result.IsError {
+		// Home Assistant shows the state it asked for; put it right
+		b.publishState(true)
+		if len(result.Content) > 0 {
+			if text, ok := result.Content[0].(mcp.TextContent); ok {
+				return fmt.Errorf("configureLighting: %s", text.Text)
+			}
+		}
+		return fmt.Errorf("configureLighting failed")
+	}
+	return nil
+}
+
+// toRGB splits a hex color into its channels
+func toRGB(hex string) RGB {
+	var rgb RGB
+	fmt.Sscanf(hex, "%02x%02x%02x", &rgb.R, &rgb.G, &rgb.B)
+	return rgb
+}
+
+// hex returns the color as hex, its channels clamped to 0-255
+func (c RGB) hex() string {
+	clamp := func(v int) int { return min(max(v, 0), 255) }
+	return fmt.Sprintf("%02x%02x%02x", clamp(c.R), clamp(c.G), clamp(c.B))
+}
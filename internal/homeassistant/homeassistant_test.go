@@ -0,0 +1,120 @@
+package homeassistant
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingCaller records tool calls and answers them with result
+type recordingCaller struct {
+	calls  []map[string]interface{}
+	result *mcp.CallToolResult
+}
+
+func (c *recordingCaller) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	c.calls = append(c.calls, map[string]interface{}{"tool": name, "arguments": arguments})
+	if c.result != nil {
+		return c.result, nil
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "ok"}}}, nil
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mqtt.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"broker":"tcp://broker:1883","username":"ufo"}`), 0o644))
+	t.Setenv("UFO_MQTT_PASSWORD", "secret")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "tcp://broker:1883", cfg.Broker)
+	assert.Equal(t, "secret", cfg.Password)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{}`), 0o644))
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "broker is required")
+}
+
+func TestBridge_Discovery(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	bridge := NewBridge(Config{Broker: "tcp://broker:1883", ObjectID: "office"}, &recordingCaller{}, broadcaster, state.NewManager(broadcaster))
+
+	assert.Equal(t, "homeassistant/light/office/config", bridge.discoveryTopic())
+	var discovery map[string]interface{}
+	require.NoError(t, json.Unmarshal(bridge.Discovery(), &discovery))
+	assert.Equal(t, "json", discovery["schema"])
+	assert.Equal(t, "ufo/office/set", discovery["command_topic"])
+	assert.Equal(t, "ufo/office/state", discovery["state_topic"])
+	assert.Equal(t, "ufo/office/availability", discovery["availability_topic"])
+	assert.Equal(t, []interface{}{"rgb"}, discovery["supported_color_modes"])
+	assert.Equal(t, "UFO", discovery["device"].(map[string]interface{})["name"])
+}
+
+func TestBridge_State(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	bridge := NewBridge(Config{Broker: "tcp://broker:1883"}, &recordingCaller{}, broadcaster, stateManager)
+
+	assert.Equal(t, LightState{State: "OFF"}, bridge.State())
+
+	_, err := stateManager.ApplyQuery("top_init=1&top=0|10|FF8000|10|5|0000FF&bottom_init=1&bottom_bg=FF8000&dim=128")
+	require.NoError(t, err)
+	lightState := bridge.State()
+	assert.Equal(t, "ON", lightState.State)
+	require.NotNil(t, lightState.Brightness)
+	assert.Equal(t, 128, *lightState.Brightness)
+	assert.Equal(t, &RGB{R: 255, G: 128, B: 0}, lightState.Color)
+}
+
+func TestBridge_HandleCommand(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	caller := &recordingCaller{}
+	bridge := NewBridge(Config{Broker: "tcp://broker:1883"}, caller, broadcaster, stateManager)
+	ctx := context.Background()
+
+	// Turning the light on fills both rings with the color given
+	require.NoError(t, bridge.HandleCommand(ctx, []byte(`{"state":"ON","color":{"r":255,"g":0,"b":128},"brightness":200}`)))
+	require.Len(t, caller.calls, 1)
+	assert.Equal(t, "configureLighting", caller.calls[0]["tool"])
+	assert.Equal(t, map[string]interface{}{
+		"both":       map[string]interface{}{"background": "ff0080"},
+		"logo":       map[string]interface{}{"state": "on"},
+		"brightness": 200,
+	}, caller.calls[0]["arguments"])
+
+	// Brightness alone only dims a light that is on
+	_, err := stateManager.ApplyQuery("top_init=1&top_bg=00FF00")
+	require.NoError(t, err)
+	require.NoError(t, bridge.HandleCommand(ctx, []byte(`{"state":"ON","brightness":50}`)))
+	assert.Equal(t, map[string]interface{}{"brightness": 50}, caller.calls[1]["arguments"])
+
+	// Turning it off and on again shows the color it had
+	require.NoError(t, bridge.HandleCommand(ctx, []byte(`{"state":"OFF"}`)))
+	assert.Equal(t, map[string]interface{}{
+		"both": map[string]interface{}{"background": "000000"},
+		"logo": map[string]interface{}{"state": "off"},
+	}, caller.calls[2]["arguments"])
+	_, err = stateManager.ApplyQuery("top_init=1&bottom_init=1")
+	require.NoError(t, err)
+	require.NoError(t, bridge.HandleCommand(ctx, []byte(`{"state":"ON"}`)))
+	assert.Equal(t, map[string]interface{}{"background": "00ff00"}, caller.calls[3]["arguments"].(map[string]interface{})["both"])
+
+	assert.ErrorContains(t, bridge.HandleCommand(ctx, []byte(`{"state":"TOGGLE"}`)), "state must be ON or OFF")
+	assert.ErrorContains(t, bridge.HandleCommand(ctx, []byte(`{"state":"ON","brightness":300}`)), "brightness must be 0-255")
+	assert.Len(t, caller.calls, 4)
+
+	caller.result = &mcp.CallToolResult{IsError: true, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "read-only mode"}}}
+	assert.ErrorContains(t, bridge.HandleCommand(ctx, []byte(`{"state":"OFF"}`)), "read-only mode")
+}
@@ -0,0 +1,187 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/audit"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+)
+
+// defaultTimeoutMs is used when a hook does not configure its own timeout
+const defaultTimeoutMs = 5000
+
+// maxOutputBytes limits how much command output is kept in the audit log
+const maxOutputBytes = 4096
+
+// Hook describes an external command run when matching events occur
+type Hook struct {
+	Name      string   `json:"name"`
+	Events    []string `json:"events"`    // event types that trigger the hook
+	Command   string   `json:"command"`   // executable to run
+	Args      []string `json:"args"`      // arguments, templated from the event
+	TimeoutMs int      `json:"timeoutMs"` // maximum run time in milliseconds
+}
+
+// Load reads hook definitions from a JSON file
+func Load(path string) ([]Hook, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading hooks file: %w", err)
+	}
+
+	var hooks []Hook
+	if err := json.Unmarshal(data, &hooks); err != nil {
+		return nil, fmt.Errorf("parsing hooks JSON: %w", err)
+	}
+
+	for i, hook := range hooks {
+		if hook.Command == "" {
+			return nil, fmt.Errorf("hook %d (%s) has no command", i, hook.Name)
+		}
+		if len(hook.Events) == 0 {
+			return nil, fmt.Errorf("hook %d (%s) has no events", i, hook.Name)
+		}
+		for _, arg := range hook.Args {
+			if _, err := template.New("arg").Parse(arg); err != nil {
+				return nil, fmt.Errorf("hook %d (%s) has invalid argument template %q: %w", i, hook.Name, arg, err)
+			}
+		}
+	}
+
+	return hooks, nil
+}
+
+// Matches reports whether the hook is triggered by the given event type
+func (h Hook) Matches(eventType string) bool {
+	for _, e := range h.Events {
+		if e == eventType || e == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Runner executes hooks in response to broadcaster events
+type Runner struct {
+	hooks       []Hook
+	broadcaster *events.Broadcaster
+	audit       *audit.Logger
+}
+
+// NewRunner creates a new hook runner
+func NewRunner(hooks []Hook, broadcaster *events.Broadcaster, auditLogger *audit.Logger) *Runner {
+	return &Runner{
+		hooks:       hooks,
+		broadcaster: broadcaster,
+		audit:       auditLogger,
+	}
+}
+
+// Start subscribes to the broadcaster and dispatches matching events until
+// ctx is cancelled or the broadcaster is closed
+func (r *Runner) Start(ctx context.Context) {
+	sub := r.broadcaster.Subscribe("hooks")
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				for _, hook := range r.hooks {
+					if hook.Matches(event.Type) {
+						go r.Run(ctx, hook, event)
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Run executes a single hook for an event and records the outcome in the audit log
+func (r *Runner) Run(ctx context.Context, hook Hook, event events.Event) (string, error) {
+	args, err := renderArgs(hook.Args, event)
+	if err != nil {
+		r.record(hook, event, args, "", 0, err)
+		return "", err
+	}
+
+	timeoutMs := hook.TimeoutMs
+	if timeoutMs <= 0 {
+		timeoutMs = defaultTimeoutMs
+	}
+	runCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutMs)*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	output, err := exec.CommandContext(runCtx, hook.Command, args...).CombinedOutput()
+	if runCtx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %dms", timeoutMs)
+	}
+
+	r.record(hook, event, args, truncate(string(output)), time.Since(start), err)
+	return string(output), err
+}
+
+// record writes the hook outcome to the audit log
+func (r *Runner) record(hook Hook, event events.Event, args []string, output string, elapsed time.Duration, err error) {
+	result := "OK"
+	if err != nil {
+		result = fmt.Sprintf("ERROR: %v", err)
+		slog.Warn("Hook failed", "hook", hook.Name, "event", event.Type, "error", err)
+	}
+
+	if r.audit == nil {
+		return
+	}
+	r.audit.Record(audit.Entry{
+		Kind:   "hook",
+		Action: hook.Name,
+		Result: result,
+		Data: map[string]interface{}{
+			"event":     event.Type,
+			"command":   hook.Command,
+			"args":      args,
+			"output":    output,
+			"latencyMs": elapsed.Milliseconds(),
+		},
+	})
+}
+
+// renderArgs expands the argument templates with the event fields
+func renderArgs(templates []string, event events.Event) ([]string, error) {
+	args := make([]string, 0, len(templates))
+	for _, text := range templates {
+		tmpl, err := template.New("arg").Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return args, fmt.Errorf("parsing argument template %q: %w", text, err)
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, event); err != nil {
+			return args, fmt.Errorf("rendering argument template %q: %w", text, err)
+		}
+		args = append(args, strings.ReplaceAll(buf.String(), "<no value>", ""))
+	}
+	return args, nil
+}
+
+// truncate limits command output to maxOutputBytes
+func truncate(output string) string {
+	if len(output) <= maxOutputBytes {
+		return output
+	}
+	return output[:maxOutputBytes] + "...(truncated)"
+}
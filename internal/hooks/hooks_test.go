@@ -0,0 +1,90 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/audit"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hooks.json")
+	os.WriteFile(path, []byte(`[{"name":"notify","events":["effect_started"],"command":"echo","args":["{{.Data.effect}}"]}]`), 0644)
+
+	hooks, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to load hooks: %v", err)
+	}
+	if len(hooks) != 1 || hooks[0].Name != "notify" {
+		t.Fatalf("unexpected hooks: %+v", hooks)
+	}
+	if !hooks[0].Matches(events.EventEffectStarted) || hooks[0].Matches(events.EventDimChanged) {
+		t.Error("hook event matching is wrong")
+	}
+
+	os.WriteFile(path, []byte(`[{"name":"broken","events":["dim_changed"]}]`), 0644)
+	if _, err := Load(path); err == nil {
+		t.Error("expected error for hook without command")
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLogger, err := audit.NewLogger(auditPath)
+	if err != nil {
+		t.Fatalf("failed to create audit logger: %v", err)
+	}
+	defer auditLogger.Close()
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	runner := NewRunner(nil, broadcaster, auditLogger)
+	hook := Hook{
+		Name:    "echo",
+		Events:  []string{events.EventDimChanged},
+		Command: "echo",
+		Args:    []string{"level={{.Data.level}}", "{{.Data.missing}}"},
+	}
+	event := events.Event{Type: events.EventDimChanged, Data: map[string]interface{}{"level": 42}}
+
+	output, err := runner.Run(context.Background(), hook, event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.TrimSpace(output) != "level=42" {
+		t.Errorf("expected templated output, got %q", output)
+	}
+
+	data, _ := os.ReadFile(auditPath)
+	var entry audit.Entry
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(data))), &entry); err != nil {
+		t.Fatalf("failed to parse audit entry: %v", err)
+	}
+	if entry.Kind != "hook" || entry.Result != "OK" {
+		t.Errorf("unexpected audit entry: %+v", entry)
+	}
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	runner := NewRunner(nil, broadcaster, nil)
+	hook := Hook{Name: "slow", Events: []string{"*"}, Command: "sleep", Args: []string{"5"}, TimeoutMs: 50}
+
+	start := time.Now()
+	_, err := runner.Run(context.Background(), hook, events.Event{Type: events.EventEffectStarted})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Error("hook was not killed on timeout")
+	}
+}
@@ -0,0 +1,201 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// ambientPriority keeps ambient entries beneath every ordinary effect and
+// alert, which have priority 0 or more
+const ambientPriority = -1
+
+// ShowAmbient shows a low-key indication, such as the weather, as the lowest
+// layer of the effect stack. Effects and alerts pushed later always cover
+// it, but while the effect on top leaves the ambient pattern's rings alone,
+// the pattern is sent as well, so a top ring status zone and a bottom ring
+// indication show together. Showing the same pattern again is a no-op and
+// returns false.
+func (d *Display) ShowAmbient(ctx context.Context, source, key, name, pattern string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	isOwn := func(item state.EffectStackItem) bool {
+		return ownsAmbient(item, source, key)
+	}
+	existing := d.findAmbient(source, key)
+	if existing != nil && existing.Pattern == pattern {
+		return false, nil
+	}
+
+	instanceID := state.NewInstanceID()
+	startTime := time.Now()
+	if existing != nil {
+		instanceID, startTime = existing.InstanceID(), existing.StartTime()
+	}
+	effectContext := map[string]interface{}{
+		"instanceId": instanceID,
+		"source":     source,
+		"ambientKey": key,
+		"perpetual":  true,
+		"priority":   ambientPriority,
+		"startTime":  startTime,
+	}
+	var stale bool
+	if existing != nil {
+		d.stateManager.ReplaceEffect(isOwn, state.EffectStackItem{Name: name, Pattern: pattern, Context: effectContext})
+	} else {
+		_, stale = d.stateManager.PushEffect(name, pattern, effectContext)
+	}
+
+	if stale {
+		// Making room dropped the effect that was showing; restoring the new
+		// top redraws this indication too if it is not covered
+		return true, d.restoreTop(ctx)
+	}
+
+	top := d.stateManager.GetCurrentEffect()
+	var err error
+	switch {
+	case top != nil && isOwn(*top):
+		err = d.engine.Apply(ctx, name, pattern, nil)
+	case top != nil && !sharesRings(*top, pattern):
+		err = d.engine.Overlay(ctx, pattern)
+	default:
+		// Covered; it shows once the effects above it end
+		return true, nil
+	}
+	if err != nil {
+		d.broadcaster.PublishRawExecuted(ctx, pattern, fmt.Sprintf("ERROR: %v", err))
+		if existing == nil {
+			// Leave the indication off the stack so the integration tries again
+			d.stateManager.RemoveEffects(isOwn)
+		}
+		return false, fmt.Errorf("sending %s pattern to UFO: %w", source, err)
+	}
+	d.broadcaster.PublishRawExecuted(ctx, pattern, "OK")
+
+	if existing == nil && isOwn(*top) {
+		d.broadcaster.PublishPayload(ctx, events.EffectStarted{
+			Effect:     name,
+			InstanceID: instanceID,
+			Pattern:    pattern,
+			Source:     source,
+			StackDepth: d.stateManager.GetEffectStackDepth(),
+		})
+	}
+	return true, nil
+}
+
+// ClearAmbient removes an ambient entry from the stack and turns its rings
+// off if they were showing. Returns false if the entry was not on the stack.
+func (d *Display) ClearAmbient(ctx context.Context, source, key string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	existing := d.findAmbient(source, key)
+	if existing == nil {
+		return false, nil
+	}
+	_, topChanged := d.stateManager.RemoveEffects(func(item state.EffectStackItem) bool {
+		return ownsAmbient(item, source, key)
+	})
+	if topChanged {
+		return true, d.restoreTop(ctx)
+	}
+
+	top := d.stateManager.GetCurrentEffect()
+	if top == nil || sharesRings(*top, existing.Pattern) {
+		return true, nil
+	}
+	var parts []string
+	for _, ring := range []string{"top", "bottom"} {
+		if touchesRing(existing.Pattern, ring) {
+			parts = append(parts, ring+"_init=1")
+		}
+	}
+	query := strings.Join(parts, "&")
+	if err := d.engine.Overlay(ctx, query); err != nil {
+		d.broadcaster.PublishRawExecuted(ctx, query, fmt.Sprintf("ERROR: %v", err))
+		return true, fmt.Errorf("clearing %s pattern: %w", source, err)
+	}
+	d.broadcaster.PublishRawExecuted(ctx, query, "OK")
+	return true, nil
+}
+
+// redrawAmbient sends the ambient patterns beneath top whose rings top leaves
+// alone, after top was sent to the UFO; d.mu must be held
+func (d *Display) redrawAmbient(ctx context.Context, top *state.EffectStackItem) {
+	if top == nil || isAmbient(*top) {
+		return
+	}
+	for _, item := range d.stateManager.GetEffectStack() {
+		if !isAmbient(item) || sharesRings(*top, item.Pattern) {
+			continue
+		}
+		if err := d.engine.Overlay(ctx, item.Pattern); err != nil {
+			slog.WarnContext(ctx, "Failed to redraw ambient layer", "effect", item.Name, "error", err)
+			continue
+		}
+		d.broadcaster.PublishRawExecuted(ctx, item.Pattern, "OK")
+	}
+}
+
+// findAmbient returns the stack entry for an ambient indication, or nil
+func (d *Display) findAmbient(source, key string) *state.EffectStackItem {
+	for _, item := range d.stateManager.GetEffectStack() {
+		if ownsAmbient(item, source, key) {
+			return &item
+		}
+	}
+	return nil
+}
+
+// isAmbient reports whether a stack entry was created by ShowAmbient
+func isAmbient(item state.EffectStackItem) bool {
+	_, ok := item.Context["ambientKey"].(string)
+	return ok
+}
+
+// ownsAmbient reports whether a stack entry is the given ambient indication
+func ownsAmbient(item state.EffectStackItem, source, key string) bool {
+	itemSource, _ := item.Context["source"].(string)
+	itemKey, _ := item.Context["ambientKey"].(string)
+	return itemSource == source && itemKey == key
+}
+
+// sharesRings reports whether a stack entry draws on any ring pattern does
+func sharesRings(item state.EffectStackItem, pattern string) bool {
+	patterns := []string{item.Pattern}
+	for _, step := range effects.StepsFromContext(item.Context) {
+		patterns = append(patterns, step.Pattern)
+	}
+	for _, ring := range []string{"top", "bottom"} {
+		if !touchesRing(pattern, ring) {
+			continue
+		}
+		for _, p := range patterns {
+			if touchesRing(p, ring) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// touchesRing reports whether a query sets any of a ring's parameters
+func touchesRing(query, ring string) bool {
+	for _, part := range strings.Split(query, "&") {
+		key, _, _ := strings.Cut(part, "=")
+		if key == ring || strings.HasPrefix(key, ring+"_") {
+			return true
+		}
+	}
+	return false
+}
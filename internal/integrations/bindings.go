@@ -0,0 +1,458 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/audit"
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+)
+
+// BindingSource identifies stack entries created by generic poller bindings
+const BindingSource = "binding"
+
+// defaultBindingPollMs is used when a binding has no poll interval
+const defaultBindingPollMs = 30000
+
+// minBindingPollMs keeps bindings from hammering the polled API
+const minBindingPollMs = 1000
+
+// Binding polls a URL, extracts a value with a JSONPath expression and maps
+// it to lighting through the first matching rule. When no rule matches, the
+// binding's light is cleared.
+type Binding struct {
+	Name           string            `json:"name"`
+	URL            string            `json:"url"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Path           string            `json:"path"` // JSONPath, e.g. "$.status.indicator"
+	PollIntervalMs int               `json:"pollIntervalMs,omitempty"`
+	Rules          []BindingRule     `json:"rules"`
+}
+
+// BindingRule matches an extracted value. Every condition that is set must
+// hold; a rule with no conditions matches any value. A rule without an
+// effect or color clears the binding's light.
+type BindingRule struct {
+	Equals interface{} `json:"equals,omitempty"` // exact match, compared as text
+	Min    *float64    `json:"min,omitempty"`    // numeric lower bound (inclusive)
+	Max    *float64    `json:"max,omitempty"`    // numeric upper bound (inclusive)
+	Match  string      `json:"match,omitempty"`  // regular expression on the value as text
+	Action
+}
+
+// BindingStatus reports a binding and the outcome of its last poll
+type BindingStatus struct {
+	Binding
+	LastPoll  *time.Time  `json:"lastPoll,omitempty"`
+	LastValue interface{} `json:"lastValue,omitempty"`
+	LastRule  *int        `json:"lastRule,omitempty"` // index of the matched rule
+	LastError string      `json:"lastError,omitempty"`
+}
+
+// bindingEntry is a registered binding and its runtime state
+type bindingEntry struct {
+	binding BindingStatus
+	matches []*regexp.Regexp
+	cancel  context.CancelFunc
+}
+
+// Bindings manages generic poller bindings. Bindings can be added and
+// removed at runtime; changes made through tools are not persisted.
+type Bindings struct {
+	display    *Display
+	audit      *audit.Logger
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	ctx     context.Context // set by Start; nil until then
+	entries map[string]*bindingEntry
+	health  health
+}
+
+// NewBindings creates a binding manager
+func NewBindings(display *Display, auditLogger *audit.Logger) *Bindings {
+	return &Bindings{
+		display:    display,
+		audit:      auditLogger,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		entries:    make(map[string]*bindingEntry),
+	}
+}
+
+// compile checks a binding's fields and compiles its rule expressions
+func (b Binding) compile() ([]*regexp.Regexp, error) {
+	if b.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if b.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	if _, err := parsePath(b.Path); err != nil {
+		return nil, err
+	}
+	if b.PollIntervalMs != 0 && b.PollIntervalMs < minBindingPollMs {
+		return nil, fmt.Errorf("pollIntervalMs must be at least %d", minBindingPollMs)
+	}
+	if len(b.Rules) == 0 {
+		return nil, fmt.Errorf("at least one rule is required")
+	}
+
+	matches := make([]*regexp.Regexp, len(b.Rules))
+	for i, rule := range b.Rules {
+		if rule.Action != (Action{}) {
+			if err := rule.Action.Validate(); err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i, err)
+			}
+		}
+		if rule.Match != "" {
+			re, err := regexp.Compile(rule.Match)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: invalid match: %w", i, err)
+			}
+			matches[i] = re
+		}
+	}
+	return matches, nil
+}
+
+// Add registers a binding, replacing any binding with the same name, and
+// starts polling it if the manager is running
+func (b *Bindings) Add(binding Binding) error {
+	matches, err := binding.compile()
+	if err != nil {
+		return fmt.Errorf("binding '%s': %w", binding.Name, err)
+	}
+	if binding.PollIntervalMs == 0 {
+		binding.PollIntervalMs = defaultBindingPollMs
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if existing, ok := b.entries[binding.Name]; ok && existing.cancel != nil {
+		existing.cancel()
+	}
+	entry := &bindingEntry{binding: BindingStatus{Binding: binding}, matches: matches}
+	b.entries[binding.Name] = entry
+	if b.ctx != nil {
+		b.startLocked(entry)
+	}
+	return nil
+}
+
+// Remove stops a binding and clears its light
+func (b *Bindings) Remove(ctx context.Context, name string) error {
+	b.mu.Lock()
+	entry, ok := b.entries[name]
+	if ok {
+		delete(b.entries, name)
+		if entry.cancel != nil {
+			entry.cancel()
+		}
+	}
+	b.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("binding '%s' not found", name)
+	}
+	_, err := b.display.Deactivate(ctx, BindingSource, name, name)
+	return err
+}
+
+// List returns every binding with its last poll result, ordered by name.
+// Header values are redacted since they usually hold credentials.
+func (b *Bindings) List() []BindingStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statuses := make([]BindingStatus, 0, len(b.entries))
+	for _, entry := range b.entries {
+		status := entry.binding
+		if len(status.Headers) > 0 {
+			status.Headers = make(map[string]string, len(entry.binding.Headers))
+			for name := range entry.binding.Headers {
+				status.Headers[name] = "[redacted]"
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// Start polls every binding in the background until ctx is cancelled.
+// Bindings added later start immediately.
+func (b *Bindings) Start(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.ctx = ctx
+	for _, entry := range b.entries {
+		b.startLocked(entry)
+	}
+}
+
+// startLocked starts a binding's poll loop; b.mu must be held
+func (b *Bindings) startLocked(entry *bindingEntry) {
+	ctx, cancel := context.WithCancel(b.ctx)
+	entry.cancel = cancel
+	name := entry.binding.Name
+	interval := time.Duration(entry.binding.PollIntervalMs) * time.Millisecond
+	runPoller(ctx, "Binding '"+name+"'", interval, func(ctx context.Context) error {
+		return b.PollOnce(ctx, name)
+	})
+}
+
+// PollOnce polls a single binding and updates the UFO. It does nothing
+// while bindings are disabled.
+func (b *Bindings) PollOnce(ctx context.Context, name string) error {
+	if !b.health.enabled() {
+		return nil
+	}
+
+	b.mu.Lock()
+	entry, ok := b.entries[name]
+	var binding Binding
+	var matches []*regexp.Regexp
+	if ok {
+		binding, matches = entry.binding.Binding, entry.matches
+	}
+	b.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("binding '%s' not found", name)
+	}
+
+	value, err := b.fetch(ctx, binding)
+	rule := -1
+	if err == nil {
+		rule = matchBindingRule(binding.Rules, matches, value)
+		if rule >= 0 && binding.Rules[rule].Action != (Action{}) {
+			_, err = b.display.Show(ctx, BindingSource, name, name, fmt.Sprintf("rule %d", rule), binding.Rules[rule].Action)
+		} else {
+			_, err = b.display.Deactivate(ctx, BindingSource, name, name)
+		}
+	}
+
+	b.mu.Lock()
+	if current, ok := b.entries[name]; ok && current == entry {
+		previous := -1
+		if entry.binding.LastRule != nil {
+			previous = *entry.binding.LastRule
+		}
+		if previous != rule || (err != nil && entry.binding.LastError == "") {
+			defer b.record(binding, value, rule, err)
+		}
+		now := timezone.Now()
+		entry.binding.LastPoll = &now
+		entry.binding.LastValue = value
+		entry.binding.LastRule = nil
+		if rule >= 0 {
+			entry.binding.LastRule = &rule
+		}
+		entry.binding.LastError = ""
+		if err != nil {
+			entry.binding.LastError = err.Error()
+		}
+	}
+	b.mu.Unlock()
+
+	b.health.observe(err)
+	return err
+}
+
+// Status reports the health of all bindings together
+func (b *Bindings) Status() IntegrationStatus {
+	status := b.health.status()
+	b.mu.Lock()
+	status.Configured = len(b.entries)
+	b.mu.Unlock()
+	status.Active = b.display.countSource(BindingSource)
+	return status
+}
+
+// SetEnabled turns polling of every binding on or off. Disabling clears
+// binding lights from the UFO; the next polls after enabling restore them.
+func (b *Bindings) SetEnabled(ctx context.Context, enabled bool) error {
+	if !b.health.setEnabled(enabled) || enabled {
+		return nil
+	}
+	_, err := b.display.DeactivateSource(ctx, BindingSource)
+	return err
+}
+
+// fetch polls the binding URL and extracts its value
+func (b *Bindings) fetch(ctx context.Context, binding Binding) (interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, binding.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, value := range binding.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxWebhookBytes))
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("returned status %d", resp.StatusCode)
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+	return ExtractPath(doc, binding.Path)
+}
+
+// record writes a binding state change to the audit log
+func (b *Bindings) record(binding Binding, value interface{}, rule int, err error) {
+	if b.audit == nil {
+		return
+	}
+
+	result := "ok"
+	data := map[string]interface{}{
+		"binding": binding.Name,
+		"value":   value,
+		"rule":    rule,
+	}
+	if err != nil {
+		result = "error"
+		data["error"] = err.Error()
+	}
+	b.audit.Record(audit.Entry{
+		Kind:   "integration",
+		Action: BindingSource,
+		Result: result,
+		Data:   data,
+	})
+}
+
+// matchBindingRule returns the index of the first rule matching value, or -1
+func matchBindingRule(rules []BindingRule, matches []*regexp.Regexp, value interface{}) int {
+	text := fmt.Sprint(value)
+	number, isNumber := toNumber(value)
+
+	for i, rule := range rules {
+		if rule.Equals != nil && fmt.Sprint(rule.Equals) != text {
+			continue
+		}
+		if rule.Min != nil && (!isNumber || number < *rule.Min) {
+			continue
+		}
+		if rule.Max != nil && (!isNumber || number > *rule.Max) {
+			continue
+		}
+		if matches[i] != nil && !matches[i].MatchString(text) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// toNumber converts JSON numbers and numeric strings to float64
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// Test runs a binding's pipeline once with its own alert key and clears the
+// result during cleanup. input may set "binding" (optional when only one is
+// configured) and "value" to skip polling and match that value instead.
+func (b *Bindings) Test(ctx context.Context, input map[string]interface{}) (*TestReport, func(context.Context) error) {
+	report := &TestReport{}
+
+	b.mu.Lock()
+	name := inputString(input, "binding", "")
+	if name == "" && len(b.entries) == 1 {
+		for only := range b.entries {
+			name = only
+		}
+	}
+	entry, ok := b.entries[name]
+	var binding Binding
+	var matches []*regexp.Regexp
+	if ok {
+		binding, matches = entry.binding.Binding, entry.matches
+	}
+	b.mu.Unlock()
+
+	if !ok {
+		if name == "" {
+			report.step("mapping", false, "set 'binding' to one of %v", b.names())
+		} else {
+			report.step("mapping", false, "binding '%s' not found", name)
+		}
+		return report, nil
+	}
+
+	value, given := input["value"]
+	if given {
+		report.step("mapping", true, "using value %v", value)
+	} else {
+		var err error
+		value, err = b.fetch(ctx, binding)
+		if err != nil {
+			report.step("mapping", false, "polling %s: %v", binding.URL, err)
+			return report, nil
+		}
+		report.step("mapping", true, "extracted %v from %s at %s", value, binding.URL, binding.Path)
+	}
+
+	rule := matchBindingRule(binding.Rules, matches, value)
+	if rule < 0 {
+		report.step("rules", false, "no rule matches %v; the light would be cleared", value)
+		return report, nil
+	}
+	action := binding.Rules[rule].Action
+	if action == (Action{}) {
+		report.step("rules", false, "rule %d matches %v and clears the light", rule, value)
+		return report, nil
+	}
+	report.step("rules", true, "rule %d matches %v and shows %s", rule, value, action.Describe())
+
+	key := testKey()
+	_, err := b.display.Show(ctx, BindingSource, key, binding.Name, fmt.Sprintf("rule %d", rule), action)
+	cleanup := func(ctx context.Context) error {
+		_, err := b.display.Deactivate(ctx, BindingSource, key, binding.Name)
+		return err
+	}
+	if err != nil {
+		report.step("lighting", false, "%v", err)
+		return report, cleanup
+	}
+	report.step("lighting", true, "%s", b.display.describeTop(BindingSource, key))
+	return report, cleanup
+}
+
+// names returns the binding names in order
+func (b *Bindings) names() []string {
+	var names []string
+	for _, status := range b.List() {
+		names = append(names, status.Name)
+	}
+	return names
+}
@@ -0,0 +1,132 @@
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testAPI serves a JSON body that tests can change between polls
+func testAPI(t *testing.T) (*httptest.Server, func(string)) {
+	t.Helper()
+
+	var mu sync.Mutex
+	body := `{}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, func(b string) {
+		mu.Lock()
+		body = b
+		mu.Unlock()
+	}
+}
+
+func TestBindings_PollOnce(t *testing.T) {
+	display, stateManager, queries := testDisplay(t)
+	api, setBody := testAPI(t)
+
+	min80 := 80.0
+	bindings := NewBindings(display, nil)
+	err := bindings.Add(Binding{
+		Name:    "cpu",
+		URL:     api.URL,
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+		Path:    "$.hosts[0].cpu",
+		Rules: []BindingRule{
+			{Min: &min80, Action: Action{Color: "red"}},
+			{Match: "^[0-9.]+$", Action: Action{Effect: "alarm"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to add binding: %v", err)
+	}
+
+	setBody(`{"hosts": [{"cpu": 95}]}`)
+	if err := bindings.PollOnce(context.Background(), "cpu"); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	top := stateManager.GetCurrentEffect()
+	if top == nil || top.Context["source"] != BindingSource || !strings.Contains(top.Pattern, "top_bg=ff0000") {
+		t.Fatalf("expected red binding light, got %+v", top)
+	}
+
+	setBody(`{"hosts": [{"cpu": 12.5}]}`)
+	if err := bindings.PollOnce(context.Background(), "cpu"); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	if top := stateManager.GetCurrentEffect(); top == nil || top.Pattern != "effect=alarm" {
+		t.Fatalf("expected alarm effect, got %+v", top)
+	}
+	if depth := stateManager.GetEffectStackDepth(); depth != 1 {
+		t.Errorf("expected the binding to replace its own light, stack depth %d", depth)
+	}
+
+	setBody(`{"hosts": [{"cpu": "n/a"}]}`)
+	if err := bindings.PollOnce(context.Background(), "cpu"); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	if depth := stateManager.GetEffectStackDepth(); depth != 0 {
+		t.Errorf("expected light cleared when no rule matches, stack depth %d", depth)
+	}
+
+	status := bindings.List()
+	if len(status) != 1 || status[0].LastValue != "n/a" || status[0].LastRule != nil || status[0].LastPoll == nil {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	if status[0].Headers["Authorization"] != "[redacted]" {
+		t.Errorf("expected header values redacted, got %v", status[0].Headers)
+	}
+	if len(queries()) == 0 {
+		t.Error("expected queries sent to the UFO")
+	}
+}
+
+func TestBindings_Errors(t *testing.T) {
+	display, _, _ := testDisplay(t)
+	api, _ := testAPI(t)
+	bindings := NewBindings(display, nil)
+
+	invalid := []Binding{
+		{URL: api.URL, Path: "$.a", Rules: []BindingRule{{Action: Action{Color: "red"}}}},
+		{Name: "b", Path: "$.a", Rules: []BindingRule{{Action: Action{Color: "red"}}}},
+		{Name: "b", URL: api.URL, Path: "a", Rules: []BindingRule{{Action: Action{Color: "red"}}}},
+		{Name: "b", URL: api.URL, Path: "$.a"},
+		{Name: "b", URL: api.URL, Path: "$.a", PollIntervalMs: 10, Rules: []BindingRule{{Action: Action{Color: "red"}}}},
+		{Name: "b", URL: api.URL, Path: "$.a", Rules: []BindingRule{{Match: "(", Action: Action{Color: "red"}}}},
+		{Name: "b", URL: api.URL, Path: "$.a", Rules: []BindingRule{{Action: Action{Color: "nope"}}}},
+	}
+	for i, binding := range invalid {
+		if err := bindings.Add(binding); err == nil {
+			t.Errorf("binding %d: expected validation error", i)
+		}
+	}
+
+	// Missing the Authorization header, so the API rejects the poll
+	if err := bindings.Add(Binding{Name: "b", URL: api.URL, Path: "$.a", Rules: []BindingRule{{Action: Action{Color: "red"}}}}); err != nil {
+		t.Fatalf("failed to add binding: %v", err)
+	}
+	if err := bindings.PollOnce(context.Background(), "b"); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected status error, got %v", err)
+	}
+	if status := bindings.List(); status[0].LastError == "" {
+		t.Error("expected last error recorded")
+	}
+
+	if err := bindings.Remove(context.Background(), "b"); err != nil {
+		t.Errorf("remove failed: %v", err)
+	}
+	if err := bindings.Remove(context.Background(), "b"); err == nil {
+		t.Error("expected error removing unknown binding")
+	}
+}
@@ -0,0 +1,365 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/color"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+)
+
+// BoardSource identifies the stack entry of the status board
+const BoardSource = "board"
+
+// boardKey is the ambient key of the status board
+const boardKey = "channels"
+
+// Channel severities besides the alert severities info, warning, error and
+// critical
+const (
+	ChannelOK     = "ok"
+	ChannelOff    = "off"    // the channel's LEDs are dark
+	ChannelCustom = "custom" // a color given instead of a severity
+)
+
+// ChannelSeverities lists the severities a channel can report, from least
+// to most severe
+var ChannelSeverities = []string{ChannelOK, "info", "warning", "error", "critical"}
+
+// defaultChannelColors is the color of each severity unless the board
+// configuration overrides it
+var defaultChannelColors = map[string]string{
+	ChannelOK:  "green",
+	"info":     "blue",
+	"warning":  "yellow",
+	"error":    "orange",
+	"critical": "red",
+}
+
+// Errors of Board.Set for statuses the caller got wrong, as opposed to the
+// UFO failing to show them
+var (
+	ErrUnknownChannel = errors.New("unknown channel")
+	ErrInvalidStatus  = errors.New("invalid status")
+)
+
+// BoardConfig is the status board configuration file: named channels, each
+// owning LEDs of one ring, so several systems can report on the UFO at once
+type BoardConfig struct {
+	Channels []Channel         `json:"channels"`
+	Colors   map[string]string `json:"colors,omitempty"` // severity -> color, overriding the defaults
+}
+
+// Channel is a run of LEDs on one ring reserved for one system's status
+type Channel struct {
+	Name        string `json:"name"`
+	Ring        string `json:"ring"`  // "top" or "bottom"
+	Start       int    `json:"start"` // first LED, 0-14
+	Count       int    `json:"count"` // LEDs, wrapping around the ring
+	Description string `json:"description,omitempty"`
+}
+
+// ChannelState is a channel and the status it shows
+type ChannelState struct {
+	Channel
+	Severity  string     `json:"severity"`        // a ChannelSeverities entry, custom or off
+	Color     string     `json:"color,omitempty"` // hex color shown, unless off
+	Message   string     `json:"message,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// LoadBoardConfig reads the status board configuration from a JSON file
+func LoadBoardConfig(path string) (*BoardConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading channels file: %w", err)
+	}
+
+	var cfg BoardConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing channels JSON: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Board shows the status of named channels on their ring segments. The
+// channels share one ambient entry of the effect stack, so effects and
+// alerts cover the board while they run and it shows again afterwards.
+type Board struct {
+	display  *Display
+	channels []Channel         // in configuration order
+	colors   map[string]string // severity -> hex color
+
+	mu       sync.Mutex // serializes updates so the pattern sent matches the statuses
+	statuses map[string]ChannelState
+}
+
+// Validate checks that channels have unique names and own LEDs no other
+// channel does, and that the colors are valid
+func (cfg BoardConfig) Validate() error {
+	if len(cfg.Channels) == 0 {
+		return fmt.Errorf("channels: at least one channel is required")
+	}
+	owners := map[string][]string{"top": make([]string, device.RingLEDs), "bottom": make([]string, device.RingLEDs)}
+	seen := map[string]bool{}
+	for i, channel := range cfg.Channels {
+		if channel.Name == "" {
+			return fmt.Errorf("channels: channel %d: name is required", i)
+		}
+		if seen[channel.Name] {
+			return fmt.Errorf("channels: channel '%s' is defined twice", channel.Name)
+		}
+		seen[channel.Name] = true
+		leds, ok := owners[channel.Ring]
+		if !ok {
+			return fmt.Errorf("channels: channel '%s': ring must be 'top' or 'bottom', got %q", channel.Name, channel.Ring)
+		}
+		if channel.Start < 0 || channel.Start >= device.RingLEDs || channel.Count < 1 || channel.Count > device.RingLEDs {
+			return fmt.Errorf("channels: channel '%s': start must be 0-%d and count 1-%d", channel.Name, device.RingLEDs-1, device.RingLEDs)
+		}
+		for led := channel.Start; led < channel.Start+channel.Count; led++ {
+			if owner := leds[led%device.RingLEDs]; owner != "" {
+				return fmt.Errorf("channels: channel '%s' overlaps '%s' at %s LED %d", channel.Name, owner, channel.Ring, led%device.RingLEDs)
+			}
+			leds[led%device.RingLEDs] = channel.Name
+		}
+	}
+	_, err := cfg.severityColors()
+	return err
+}
+
+// severityColors returns the hex color of each severity
+func (cfg BoardConfig) severityColors() (map[string]string, error) {
+	for severity := range cfg.Colors {
+		if _, ok := defaultChannelColors[severity]; !ok {
+			return nil, fmt.Errorf("channels: colors: unknown severity %q, use %s", severity, strings.Join(ChannelSeverities, ", "))
+		}
+	}
+	colors := make(map[string]string, len(defaultChannelColors))
+	for _, severity := range ChannelSeverities {
+		spec, ok := cfg.Colors[severity]
+		if !ok {
+			spec = defaultChannelColors[severity]
+		}
+		hex, err := color.Parse(spec)
+		if err != nil {
+			return nil, fmt.Errorf("channels: color for '%s': %w", severity, err)
+		}
+		colors[severity] = strings.ToLower(hex)
+	}
+	return colors, nil
+}
+
+// NewBoard creates the status board from its configuration. Colors shown
+// by a board entry left on the stack from before a restart are taken over,
+// so the board looks the same until the systems report again.
+func NewBoard(cfg BoardConfig, display *Display) (*Board, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	colors, err := cfg.severityColors()
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Board{
+		display:  display,
+		channels: cfg.Channels,
+		colors:   colors,
+		statuses: map[string]ChannelState{},
+	}
+	b.restore()
+	return b, nil
+}
+
+// restore takes over the colors of a board entry on the stack
+func (b *Board) restore() {
+	existing := b.display.findAmbient(BoardSource, boardKey)
+	if existing == nil {
+		return
+	}
+	pattern, err := device.ParsePattern(existing.Pattern)
+	if err != nil {
+		return
+	}
+	rings := map[string]*device.RingPattern{"top": pattern.Top, "bottom": pattern.Bottom}
+	for _, channel := range b.channels {
+		ring := rings[channel.Ring]
+		if ring == nil {
+			continue
+		}
+		for _, segment := range ring.Segments {
+			if segment.Start == channel.Start && segment.Count == channel.Count {
+				b.statuses[channel.Name] = ChannelState{Channel: channel, Severity: b.severityOf(segment.Color), Color: segment.Color}
+			}
+		}
+	}
+}
+
+// severityOf returns the severity shown in hex, or custom
+func (b *Board) severityOf(hex string) string {
+	for _, severity := range ChannelSeverities {
+		if b.colors[severity] == hex {
+			return severity
+		}
+	}
+	return ChannelCustom
+}
+
+// Set shows a status on a channel: a severity, a color of its own given as
+// colorSpec, or both to report a severity in another color. Severity "off"
+// turns the channel's LEDs off. It returns the new state of the channel.
+func (b *Board) Set(ctx context.Context, name, severity, colorSpec, message string) (ChannelState, error) {
+	var channel *Channel
+	for i := range b.channels {
+		if b.channels[i].Name == name {
+			channel = &b.channels[i]
+		}
+	}
+	if channel == nil {
+		return ChannelState{}, fmt.Errorf("%w '%s': the board has %s", ErrUnknownChannel, name, strings.Join(b.names(), ", "))
+	}
+
+	_, known := b.colors[severity]
+	switch {
+	case severity == ChannelOff && colorSpec != "":
+		return ChannelState{}, fmt.Errorf("%w: a channel that is off shows no color", ErrInvalidStatus)
+	case severity == "" && colorSpec == "":
+		return ChannelState{}, fmt.Errorf("%w: give a severity or a color", ErrInvalidStatus)
+	case severity != "" && severity != ChannelOff && !known:
+		return ChannelState{}, fmt.Errorf("%w: severity must be one of %s or off, got %q", ErrInvalidStatus, strings.Join(ChannelSeverities, ", "), severity)
+	}
+
+	now := timezone.Now()
+	next := ChannelState{Channel: *channel, Severity: severity, Color: b.colors[severity], Message: message, UpdatedAt: &now}
+	if colorSpec != "" {
+		hex, err := color.Parse(colorSpec)
+		if err != nil {
+			return ChannelState{}, fmt.Errorf("%w: %v", ErrInvalidStatus, err)
+		}
+		next.Color = strings.ToLower(hex)
+		if severity == "" {
+			next.Severity = ChannelCustom
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	previous, had := b.statuses[name]
+	if next.Color == "" {
+		delete(b.statuses, name)
+	} else {
+		b.statuses[name] = next
+	}
+	if err := b.show(ctx); err != nil {
+		if had {
+			b.statuses[name] = previous
+		} else {
+			delete(b.statuses, name)
+		}
+		return ChannelState{}, err
+	}
+
+	if previous.Severity == "" {
+		previous.Severity = ChannelOff
+	}
+	showing := b.Covering(name) == ""
+	b.display.broadcaster.PublishPayload(ctx, events.ChannelStatus{
+		Channel:  name,
+		Severity: next.Severity,
+		Color:    next.Color,
+		Message:  message,
+		Previous: previous.Severity,
+		Showing:  &showing,
+	})
+	return next, nil
+}
+
+// show sends the board's pattern, or removes the board when every channel
+// is off; b.mu must be held
+func (b *Board) show(ctx context.Context) error {
+	if len(b.statuses) == 0 {
+		_, err := b.display.ClearAmbient(ctx, BoardSource, boardKey)
+		return err
+	}
+	_, err := b.display.ShowAmbient(ctx, BoardSource, boardKey, "status-board", b.pattern())
+	return err
+}
+
+// pattern returns the query showing the statuses; b.mu must be held. Rings
+// with channels are cleared first, so channels turned off go dark.
+func (b *Board) pattern() string {
+	var parts []string
+	for _, ring := range []string{"top", "bottom"} {
+		var segments []string
+		used := false
+		for _, channel := range b.channels {
+			if channel.Ring != ring {
+				continue
+			}
+			used = true
+			if status, ok := b.statuses[channel.Name]; ok {
+				segments = append(segments, fmt.Sprintf("%d|%d|%s", channel.Start, channel.Count, status.Color))
+			}
+		}
+		if !used {
+			continue
+		}
+		parts = append(parts, ring+"_init=1")
+		if len(segments) > 0 {
+			parts = append(parts, ring+"="+strings.Join(segments, "|"))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// Covering returns the name of the effect hiding a channel, or "" when the
+// channel is visible
+func (b *Board) Covering(name string) string {
+	top := b.display.stateManager.GetCurrentEffect()
+	if top == nil || ownsAmbient(*top, BoardSource, boardKey) {
+		return ""
+	}
+	for _, channel := range b.channels {
+		if channel.Name == name && sharesRings(*top, channel.Ring+"_init=1") {
+			return top.Name
+		}
+	}
+	return ""
+}
+
+// Channels returns every channel with its status, in configuration order
+func (b *Board) Channels() []ChannelState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	states := make([]ChannelState, 0, len(b.channels))
+	for _, channel := range b.channels {
+		status, ok := b.statuses[channel.Name]
+		if !ok {
+			status = ChannelState{Channel: channel, Severity: ChannelOff}
+		}
+		states = append(states, status)
+	}
+	return states
+}
+
+// names returns the channel names, sorted
+func (b *Board) names() []string {
+	names := make([]string, len(b.channels))
+	for i, channel := range b.channels {
+		names[i] = channel.Name
+	}
+	sort.Strings(names)
+	return names
+}
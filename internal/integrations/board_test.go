@@ -0,0 +1,116 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// testBoardConfig has two channels on the top ring and one on the bottom
+var testBoardConfig = BoardConfig{Channels: []Channel{
+	{Name: "build", Ring: "top", Start: 0, Count: 5},
+	{Name: "deploy", Ring: "top", Start: 5, Count: 5},
+	{Name: "oncall", Ring: "bottom", Start: 0, Count: 15},
+}}
+
+func TestNewBoard_Validation(t *testing.T) {
+	display, _, _ := testDisplay(t)
+	tests := []struct {
+		name string
+		cfg  BoardConfig
+		want string
+	}{
+		{"no channels", BoardConfig{}, "at least one channel"},
+		{"no name", BoardConfig{Channels: []Channel{{Ring: "top", Count: 1}}}, "name is required"},
+		{"bad ring", BoardConfig{Channels: []Channel{{Name: "a", Ring: "middle", Count: 1}}}, "ring must be"},
+		{"too long", BoardConfig{Channels: []Channel{{Name: "a", Ring: "top", Count: 16}}}, "count 1-15"},
+		{"twice", BoardConfig{Channels: []Channel{{Name: "a", Ring: "top", Count: 1}, {Name: "a", Ring: "bottom", Count: 1}}}, "defined twice"},
+		{"overlap", BoardConfig{Channels: []Channel{{Name: "a", Ring: "top", Start: 12, Count: 5}, {Name: "b", Ring: "top", Start: 1, Count: 2}}}, "overlaps 'a' at top LED 1"},
+		{"bad color", BoardConfig{Channels: testBoardConfig.Channels, Colors: map[string]string{"ok": "nope"}}, "color for 'ok'"},
+		{"unknown severity", BoardConfig{Channels: testBoardConfig.Channels, Colors: map[string]string{"meh": "red"}}, "unknown severity"},
+	}
+	for _, tt := range tests {
+		_, err := NewBoard(tt.cfg, display)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestBoard_Set(t *testing.T) {
+	display, stateManager, queries := testDisplay(t)
+	board, err := NewBoard(BoardConfig{Channels: testBoardConfig.Channels, Colors: map[string]string{"ok": "00ff00"}}, display)
+	if err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := board.Set(ctx, "build", "error", "", "main is red"); err != nil {
+		t.Fatalf("setting build: %v", err)
+	}
+	if _, err := board.Set(ctx, "deploy", "ok", "", ""); err != nil {
+		t.Fatalf("setting deploy: %v", err)
+	}
+	sent := queries()
+	if want := "top_init=1&top=0|5|ffa500|5|5|00ff00&bottom_init=1"; sent[len(sent)-1] != want {
+		t.Errorf("expected %q, got %q", want, sent[len(sent)-1])
+	}
+
+	// Channels keep their own segments when another reports
+	status, err := board.Set(ctx, "oncall", "", "purple", "")
+	if err != nil {
+		t.Fatalf("setting oncall: %v", err)
+	}
+	if status.Severity != ChannelCustom || status.Color != "800080" {
+		t.Errorf("expected a custom purple status, got %+v", status)
+	}
+	sent = queries()
+	if want := "top_init=1&top=0|5|ffa500|5|5|00ff00&bottom_init=1&bottom=0|15|800080"; sent[len(sent)-1] != want {
+		t.Errorf("expected %q, got %q", want, sent[len(sent)-1])
+	}
+	if depth := stateManager.GetEffectStackDepth(); depth != 1 {
+		t.Errorf("expected the board as one stack entry, got %d", depth)
+	}
+
+	// An effect on the top ring covers its channels, not the bottom one
+	stateManager.PushEffect("calm", "top_init=1&top_bg=0000ff", nil)
+	if covering := board.Covering("build"); covering != "calm" {
+		t.Errorf("expected build covered by calm, got %q", covering)
+	}
+	if covering := board.Covering("oncall"); covering != "" {
+		t.Errorf("expected oncall visible, got covered by %q", covering)
+	}
+	stateManager.RemoveEffects(func(item state.EffectStackItem) bool { return item.Name == "calm" })
+
+	if _, err := board.Set(ctx, "nightly", "ok", "", ""); !errors.Is(err, ErrUnknownChannel) {
+		t.Errorf("expected ErrUnknownChannel, got %v", err)
+	}
+	for _, bad := range [][2]string{{"meh", ""}, {"off", "red"}, {"", ""}} {
+		if _, err := board.Set(ctx, "build", bad[0], bad[1], ""); err == nil {
+			t.Errorf("expected an error for severity %q and color %q", bad[0], bad[1])
+		}
+	}
+
+	// The board survives a restart with the stack
+	restarted, err := NewBoard(BoardConfig{Channels: testBoardConfig.Channels, Colors: map[string]string{"ok": "00ff00"}}, display)
+	if err != nil {
+		t.Fatalf("failed to recreate board: %v", err)
+	}
+	channels := restarted.Channels()
+	if channels[0].Severity != "error" || channels[1].Severity != "ok" || channels[2].Severity != ChannelCustom {
+		t.Errorf("expected the statuses taken over, got %+v", channels)
+	}
+
+	// Turning every channel off removes the board
+	for _, name := range []string{"build", "deploy", "oncall"} {
+		if _, err := board.Set(ctx, name, ChannelOff, "", ""); err != nil {
+			t.Fatalf("turning %s off: %v", name, err)
+		}
+	}
+	if depth := stateManager.GetEffectStackDepth(); depth != 0 {
+		t.Errorf("expected the board removed from the stack, got depth %d", depth)
+	}
+}
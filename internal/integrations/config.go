@@ -0,0 +1,89 @@
+package integrations
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// Config is the integrations configuration file. Each section is optional;
+// an integration is only enabled when its section is present.
+type Config struct {
+	Grafana   *GrafanaConfig   `json:"grafana,omitempty"`
+	PagerDuty *PagerDutyConfig `json:"pagerduty,omitempty"`
+	Jenkins   *JenkinsConfig   `json:"jenkins,omitempty"`
+	Weather   *WeatherConfig   `json:"weather,omitempty"`
+	Bindings  []Binding        `json:"bindings,omitempty"`
+	Rollup    *RollupConfig    `json:"rollup,omitempty"`
+}
+
+// Route maps alerts whose labels match to an action. Match values are
+// regular expressions anchored to the whole label value; an empty Match
+// matches every alert.
+type Route struct {
+	Name  string            `json:"name"`
+	Match map[string]string `json:"match,omitempty"`
+	Action
+}
+
+// compiledRoute is a route with its label matchers compiled
+type compiledRoute struct {
+	Route
+	matchers map[string]*regexp.Regexp
+}
+
+// LoadConfig reads the integrations configuration from a JSON file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading integrations file: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing integrations JSON: %w", err)
+	}
+	return &cfg, nil
+}
+
+// compileRoutes validates routes and compiles their label matchers
+func compileRoutes(routes []Route) ([]compiledRoute, error) {
+	compiled := make([]compiledRoute, 0, len(routes))
+	for i, route := range routes {
+		if route.Name == "" {
+			return nil, fmt.Errorf("route %d: name is required", i)
+		}
+		if err := route.Action.Validate(); err != nil {
+			return nil, fmt.Errorf("route '%s': %w", route.Name, err)
+		}
+
+		matchers := make(map[string]*regexp.Regexp, len(route.Match))
+		for label, pattern := range route.Match {
+			re, err := regexp.Compile("^(?:" + pattern + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("route '%s': invalid matcher for label '%s': %w", route.Name, label, err)
+			}
+			matchers[label] = re
+		}
+		compiled = append(compiled, compiledRoute{Route: route, matchers: matchers})
+	}
+	return compiled, nil
+}
+
+// matchRoute returns the first route whose matchers all match labels
+func matchRoute(routes []compiledRoute, labels map[string]string) *compiledRoute {
+	for i := range routes {
+		matched := true
+		for label, re := range routes[i].matchers {
+			if !re.MatchString(labels[label]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return &routes[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,361 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/color"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// Alert states recorded on stack entries
+const (
+	AlertFiring       = "firing"
+	AlertAcknowledged = "acknowledged"
+)
+
+// clearQuery turns all LEDs off when no effect is left on the stack
+const clearQuery = "top_init=1&bottom_init=1"
+
+// Action describes what the UFO shows while an alert is active
+type Action struct {
+	Effect string `json:"effect,omitempty"` // name of a stored effect
+	Color  string `json:"color,omitempty"`  // solid color (hex or name), used when Effect is empty
+	Zone   string `json:"zone,omitempty"`   // "top", "bottom" or "all" (default) for Color
+
+	Severity string `json:"severity,omitempty"` // info, warning, error or critical; used by the rollup
+}
+
+// Validate checks that the action names an effect or a valid color and zone
+func (a Action) Validate() error {
+	if a.Severity != "" {
+		if _, ok := severityRanks[a.Severity]; !ok {
+			return fmt.Errorf("severity must be 'info', 'warning', 'error' or 'critical', got %q", a.Severity)
+		}
+	}
+	if a.Effect != "" {
+		return nil
+	}
+	if a.Color == "" {
+		return fmt.Errorf("action needs an effect or a color")
+	}
+	if _, err := color.Parse(a.Color); err != nil {
+		return err
+	}
+	switch a.Zone {
+	case "", "all", "top", "bottom":
+		return nil
+	default:
+		return fmt.Errorf("zone must be 'top', 'bottom' or 'all', got %q", a.Zone)
+	}
+}
+
+// Resolve turns the action into a UFO query string, plus the frames to
+// animate when it names a multi-step effect
+func (a Action) Resolve(store *effects.Store) (string, []effects.Step, error) {
+	if a.Effect != "" {
+		effect, ok := store.Get(a.Effect)
+		if !ok {
+			return "", nil, fmt.Errorf("effect '%s' not found", a.Effect)
+		}
+		return effect.FirstPattern(), effect.Steps, nil
+	}
+
+	hex, err := color.Parse(a.Color)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var parts []string
+	for _, ring := range []string{"top", "bottom"} {
+		if a.Zone == "" || a.Zone == "all" || a.Zone == ring {
+			parts = append(parts, fmt.Sprintf("%s_init=1&%s_bg=%s", ring, ring, hex))
+		}
+	}
+	return strings.Join(parts, "&"), nil, nil
+}
+
+// Display shows integration alerts on the UFO through the effect stack.
+// Each active alert owns one stack entry, tagged with its source and key, so
+// it can be removed when the alert resolves regardless of what was played
+// on top of it in the meantime.
+type Display struct {
+	broadcaster  *events.Broadcaster
+	store        *effects.Store
+	stateManager *state.Manager
+	engine       *effects.Engine
+
+	mu     sync.Mutex    // serializes stack changes so rollups never interleave
+	rollup *RollupConfig // nil shows the most recent alert on top
+}
+
+// NewDisplay creates a new alert display
+func NewDisplay(broadcaster *events.Broadcaster, store *effects.Store, stateManager *state.Manager, engine *effects.Engine) *Display {
+	return &Display{
+		broadcaster:  broadcaster,
+		store:        store,
+		stateManager: stateManager,
+		engine:       engine,
+	}
+}
+
+// IsActive reports whether an alert from source with the given key is on the stack
+func (d *Display) IsActive(source, key string) bool {
+	return d.find(source, key) != nil
+}
+
+// find returns the stack entry for an alert, or nil if it is not active
+func (d *Display) find(source, key string) *state.EffectStackItem {
+	for _, item := range d.stateManager.GetEffectStack() {
+		if ownedBy(item, source, key) {
+			return &item
+		}
+	}
+	return nil
+}
+
+// Activate shows a firing alert. See Show.
+func (d *Display) Activate(ctx context.Context, source, key, name string, action Action) (bool, error) {
+	return d.Show(ctx, source, key, name, AlertFiring, action)
+}
+
+// Show displays an alert in the given state (for example "triggered" or
+// "acknowledged"). A new alert is pushed onto the effect stack; an active
+// alert moving to a different state has its stack entry replaced in place,
+// so acknowledging a buried alert does not bring it to the top. Showing an
+// alert in the state it is already in is a no-op and returns false. With a
+// rollup configured, alerts are kept ordered so the highest scoring one is
+// the alert on top.
+func (d *Display) Show(ctx context.Context, source, key, name, alertState string, action Action) (bool, error) {
+	pattern, steps, err := action.Resolve(d.store)
+	if err != nil {
+		return false, err
+	}
+
+	effectName := action.Effect
+	if effectName == "" {
+		effectName = name
+	}
+	return d.show(ctx, source, key, name, alertState, action.Severity, effectName, pattern, steps)
+}
+
+// ShowPattern displays an alert using a raw pattern, for integrations that
+// compute their lighting (such as build progress). It follows the same rules
+// as Show; an alert already showing the same state and pattern is left alone.
+func (d *Display) ShowPattern(ctx context.Context, source, key, name, alertState, severity, pattern string) (bool, error) {
+	return d.show(ctx, source, key, name, alertState, severity, name, pattern, nil)
+}
+
+// show pushes or replaces an alert's stack entry, applies the rollup and
+// updates the device if the top of the stack changed
+func (d *Display) show(ctx context.Context, source, key, name, alertState, severity, effectName, pattern string, steps []effects.Step) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	startTime := time.Now()
+	stateChanged := true
+	existing := d.find(source, key)
+	if existing != nil {
+		if current, _ := existing.Context["alertState"].(string); current == alertState {
+			if existing.Pattern == pattern {
+				return false, nil
+			}
+			// Same state with new lighting, e.g. build progress
+			startTime = existing.StartTime()
+			stateChanged = false
+		}
+	}
+
+	instanceID := state.NewInstanceID()
+	if existing != nil && existing.InstanceID() != "" {
+		instanceID = existing.InstanceID()
+	}
+	effectContext := map[string]interface{}{
+		"instanceId": instanceID,
+		"source":     source,
+		"alertKey":   key,
+		"alertName":  name,
+		"alertState": alertState,
+		"perpetual":  true,
+		"startTime":  startTime,
+	}
+	if severity != "" {
+		effectContext["severity"] = severity
+	}
+	if len(steps) > 0 {
+		effectContext["steps"] = steps
+	}
+	item := state.EffectStackItem{Name: effectName, Pattern: pattern, Context: effectContext}
+
+	isOwn := func(candidate state.EffectStackItem) bool {
+		return ownedBy(candidate, source, key)
+	}
+	before := d.stateManager.GetCurrentEffect()
+	if existing != nil {
+		d.stateManager.ReplaceEffect(isOwn, item)
+	} else {
+		d.stateManager.PushEffect(effectName, pattern, effectContext)
+	}
+	d.applyRollup()
+
+	top := d.stateManager.GetCurrentEffect()
+	visible := top != nil && isOwn(*top)
+	if top != nil && !sameEntry(before, top) {
+		if err := d.engine.Apply(ctx, top.Name, top.Pattern, effects.StepsFromContext(top.Context)); err != nil {
+			d.broadcaster.PublishRawExecuted(ctx, top.Pattern, fmt.Sprintf("ERROR: %v", err))
+			if existing == nil {
+				// Leave the alert inactive so the integration tries again
+				d.stateManager.RemoveEffects(isOwn)
+			}
+			return false, fmt.Errorf("sending alert pattern to UFO: %w", err)
+		}
+		d.broadcaster.PublishRawExecuted(ctx, top.Pattern, "OK")
+		d.redrawAmbient(ctx, top)
+	}
+
+	if !stateChanged {
+		return true, nil
+	}
+
+	eventType := events.EventAlertFiring
+	if alertState == AlertAcknowledged {
+		eventType = events.EventAlertAcknowledged
+	}
+	d.broadcaster.PublishAlert(eventType, source, key, name)
+	if visible {
+		d.broadcaster.PublishPayload(ctx, events.EffectStarted{
+			Effect:     effectName,
+			InstanceID: instanceID,
+			Pattern:    pattern,
+			Source:     source,
+			StackDepth: d.stateManager.GetEffectStackDepth(),
+		})
+	}
+	return true, nil
+}
+
+// Deactivate removes an alert's stack entry. If the alert was showing, the
+// effect underneath it is restored, or the UFO is cleared when the stack is
+// empty. Returns false if the alert was not active.
+func (d *Display) Deactivate(ctx context.Context, source, key, name string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	removed, topChanged := d.stateManager.RemoveEffects(func(item state.EffectStackItem) bool {
+		return ownedBy(item, source, key)
+	})
+	if removed == 0 {
+		return false, nil
+	}
+
+	d.broadcaster.PublishAlert(events.EventAlertResolved, source, key, name)
+	if !topChanged {
+		return true, nil
+	}
+	return true, d.restoreTop(ctx)
+}
+
+// DeactivateSource removes every alert from source, restoring whatever is
+// left on top. It returns how many alerts were removed.
+func (d *Display) DeactivateSource(ctx context.Context, source string) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var alerts []state.EffectStackItem
+	for _, item := range d.stateManager.GetEffectStack() {
+		if itemSource, _ := item.Context["source"].(string); itemSource == source && isAlert(item) {
+			alerts = append(alerts, item)
+		}
+	}
+	removed, topChanged := d.stateManager.RemoveEffects(func(item state.EffectStackItem) bool {
+		itemSource, _ := item.Context["source"].(string)
+		return itemSource == source && isAlert(item)
+	})
+
+	for _, item := range alerts {
+		key, _ := item.Context["alertKey"].(string)
+		name, _ := item.Context["alertName"].(string)
+		d.broadcaster.PublishAlert(events.EventAlertResolved, source, key, name)
+	}
+	if !topChanged {
+		return removed, nil
+	}
+	return removed, d.restoreTop(ctx)
+}
+
+// countSource returns how many alerts from source are on the stack
+func (d *Display) countSource(source string) int {
+	count := 0
+	for _, item := range d.stateManager.GetEffectStack() {
+		if itemSource, _ := item.Context["source"].(string); itemSource == source && isAlert(item) {
+			count++
+		}
+	}
+	return count
+}
+
+// restoreTop shows the new top of the stack after alerts were removed, or
+// clears the UFO when the stack is empty; d.mu must be held
+func (d *Display) restoreTop(ctx context.Context) error {
+	query, effectName := clearQuery, ""
+	var steps []effects.Step
+	current := d.stateManager.GetCurrentEffect()
+	if current != nil {
+		query, effectName = current.Pattern, current.Name
+		steps = effects.StepsFromContext(current.Context)
+	}
+
+	if err := d.engine.Apply(ctx, effectName, query, steps); err != nil {
+		d.broadcaster.PublishRawExecuted(ctx, query, fmt.Sprintf("ERROR: %v", err))
+		return fmt.Errorf("restoring previous state: %w", err)
+	}
+	d.broadcaster.PublishRawExecuted(ctx, query, "OK")
+	d.redrawAmbient(ctx, current)
+
+	if current != nil {
+		d.broadcaster.PublishPayload(ctx, events.EffectResumed{
+			Effect:     current.Name,
+			InstanceID: current.InstanceID(),
+			StackDepth: d.stateManager.GetEffectStackDepth(),
+		})
+	}
+	return nil
+}
+
+// sameEntry reports whether two stack entries would show the same thing
+func sameEntry(a, b *state.EffectStackItem) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Name == b.Name && a.Pattern == b.Pattern && a.StartTime().Equal(b.StartTime())
+}
+
+// ownedBy reports whether a stack entry belongs to the given alert
+func ownedBy(item state.EffectStackItem, source, key string) bool {
+	itemSource, _ := item.Context["source"].(string)
+	itemKey, _ := item.Context["alertKey"].(string)
+	return itemSource == source && itemKey == key
+}
+
+// Describe summarizes the action for reports
+func (a Action) Describe() string {
+	var text string
+	if a.Effect != "" {
+		text = fmt.Sprintf("effect '%s'", a.Effect)
+	} else {
+		zone := a.Zone
+		if zone == "" {
+			zone = "all"
+		}
+		text = fmt.Sprintf("color %s on %s", a.Color, zone)
+	}
+	if a.Severity != "" {
+		text += fmt.Sprintf(" (%s)", a.Severity)
+	}
+	return text
+}
@@ -0,0 +1,421 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/audit"
+)
+
+// DynatraceSource identifies stack entries created by the Dynatrace integration
+const DynatraceSource = "dynatrace"
+
+// defaultDynatracePollMs is used when no poll interval is configured
+const defaultDynatracePollMs = 60000
+
+// maxDynatracePages bounds how many pages of open problems one poll fetches
+const maxDynatracePages = 10
+
+// Dynatrace problem severity levels, from most to least severe
+const (
+	SeverityAvailability          = "AVAILABILITY"
+	SeverityError                 = "ERROR"
+	SeverityPerformance           = "PERFORMANCE"
+	SeverityResourceContention    = "RESOURCE_CONTENTION"
+	SeverityCustomAlert           = "CUSTOM_ALERT"
+	SeverityMonitoringUnavailable = "MONITORING_UNAVAILABLE"
+	SeverityInfo                  = "INFO"
+)
+
+// dynatraceSeverityOrder ranks severity levels; a problem with a lower index
+// is more severe
+var dynatraceSeverityOrder = []string{
+	SeverityAvailability,
+	SeverityError,
+	SeverityPerformance,
+	SeverityResourceContention,
+	SeverityCustomAlert,
+	SeverityMonitoringUnavailable,
+	SeverityInfo,
+}
+
+// defaultDynatraceActions is the lighting for each severity level unless the
+// configuration overrides it
+var defaultDynatraceActions = map[string]Action{
+	SeverityAvailability:          {Color: "red", Severity: "critical"},
+	SeverityError:                 {Color: "orange", Severity: "error"},
+	SeverityPerformance:           {Color: "yellow", Severity: "warning"},
+	SeverityResourceContention:    {Color: "yellow", Zone: "bottom", Severity: "warning"},
+	SeverityCustomAlert:           {Color: "purple", Severity: "warning"},
+	SeverityMonitoringUnavailable: {Color: "blue", Zone: "bottom", Severity: "info"},
+	SeverityInfo:                  {Color: "blue", Zone: "top", Severity: "info"},
+}
+
+// DynatraceConfig configures the Dynatrace problems poller
+type DynatraceConfig struct {
+	EnvironmentURL  string             `json:"environmentUrl"`            // e.g. https://abc12345.live.dynatrace.com
+	APIToken        string             `json:"apiToken,omitempty"`        // needs the problems.read scope; default $UFO_DYNATRACE_API_TOKEN
+	PollIntervalMs  int                `json:"pollIntervalMs,omitempty"`  // default 60000
+	ProblemSelector string             `json:"problemSelector,omitempty"` // extra problem filter, e.g. managementZones("Production")
+	Severities      map[string]*Action `json:"severities,omitempty"`      // lighting by severity level; null ignores a level
+}
+
+// DynatraceProblem is the subset of the Dynatrace problem API used
+type DynatraceProblem struct {
+	ProblemID     string `json:"problemId"`
+	DisplayID     string `json:"displayId"` // e.g. P-2403127
+	Title         string `json:"title"`
+	SeverityLevel string `json:"severityLevel"`
+	ImpactLevel   string `json:"impactLevel"`
+	Status        string `json:"status"`    // OPEN or CLOSED
+	StartTime     int64  `json:"startTime"` // ms since epoch
+}
+
+// name labels the problem on the effect stack
+func (p DynatraceProblem) name() string {
+	if p.DisplayID == "" {
+		return p.Title
+	}
+	if p.Title == "" {
+		return p.DisplayID
+	}
+	return p.DisplayID + " " + p.Title
+}
+
+// Dynatrace polls open Dynatrace problems and shows each one in the color of
+// its severity level, so the UFO lights up for problems without any webhook
+// setup
+type Dynatrace struct {
+	cfg        DynatraceConfig
+	actions    map[string]*Action // severity level -> lighting, nil to ignore
+	display    *Display
+	audit      *audit.Logger
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	problems map[string]DynatraceProblem // open problems shown on the UFO
+	health   health
+}
+
+// LoadDynatraceConfig reads the Dynatrace configuration from a JSON file.
+// The API token may be left out of the file and given in the
+// UFO_DYNATRACE_API_TOKEN environment variable instead.
+func LoadDynatraceConfig(path string) (*DynatraceConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading Dynatrace config file: %w", err)
+	}
+
+	var cfg DynatraceConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing Dynatrace config JSON: %w", err)
+	}
+	if cfg.APIToken == "" {
+		cfg.APIToken = os.Getenv("UFO_DYNATRACE_API_TOKEN")
+	}
+	return &cfg, nil
+}
+
+// NewDynatrace creates the Dynatrace integration from its configuration
+func NewDynatrace(cfg DynatraceConfig, display *Display, auditLogger *audit.Logger) (*Dynatrace, error) {
+	if cfg.EnvironmentURL == "" {
+		return nil, fmt.Errorf("dynatrace: environmentUrl is required")
+	}
+	if _, err := url.ParseRequestURI(cfg.EnvironmentURL); err != nil {
+		return nil, fmt.Errorf("dynatrace: invalid environmentUrl: %w", err)
+	}
+	if cfg.APIToken == "" {
+		return nil, fmt.Errorf("dynatrace: apiToken is required")
+	}
+	if cfg.PollIntervalMs <= 0 {
+		cfg.PollIntervalMs = defaultDynatracePollMs
+	}
+
+	actions := make(map[string]*Action, len(defaultDynatraceActions))
+	for level, action := range defaultDynatraceActions {
+		action := action
+		actions[level] = &action
+	}
+	for level, action := range cfg.Severities {
+		level = strings.ToUpper(level)
+		if _, ok := defaultDynatraceActions[level]; !ok {
+			return nil, fmt.Errorf("dynatrace: unknown severity level %q, expected one of %s", level, strings.Join(dynatraceSeverityOrder, ", "))
+		}
+		if action != nil {
+			if err := action.Validate(); err != nil {
+				return nil, fmt.Errorf("dynatrace: %s: %w", level, err)
+			}
+		}
+		actions[level] = action
+	}
+
+	return &Dynatrace{
+		cfg:        cfg,
+		actions:    actions,
+		display:    display,
+		audit:      auditLogger,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		problems:   make(map[string]DynatraceProblem),
+	}, nil
+}
+
+// Start polls open problems in the background until ctx is cancelled
+func (d *Dynatrace) Start(ctx context.Context) {
+	runPoller(ctx, "Dynatrace", time.Duration(d.cfg.PollIntervalMs)*time.Millisecond, d.PollOnce)
+}
+
+// PollOnce fetches open problems and reconciles the UFO with them. Problems
+// shown earlier that are no longer open are cleared. It does nothing while
+// the integration is disabled.
+func (d *Dynatrace) PollOnce(ctx context.Context) error {
+	if !d.health.enabled() {
+		return nil
+	}
+	err := d.pollProblems(ctx)
+	d.health.observe(err)
+	return err
+}
+
+// pollProblems performs one poll for PollOnce
+func (d *Dynatrace) pollProblems(ctx context.Context) error {
+	problems, err := d.fetchOpenProblems(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Show the most severe problems last, so they end up on top
+	sort.SliceStable(problems, func(i, j int) bool {
+		return severityIndex(problems[i].SeverityLevel) > severityIndex(problems[j].SeverityLevel)
+	})
+
+	open := make(map[string]bool, len(problems))
+	var errs []string
+	for _, problem := range problems {
+		open[problem.ProblemID] = true
+		if _, err := d.Apply(ctx, problem); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", problem.DisplayID, err))
+		}
+	}
+
+	for _, problem := range d.Problems() {
+		if !open[problem.ProblemID] {
+			problem.Status = "CLOSED"
+			if _, err := d.Apply(ctx, problem); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", problem.DisplayID, err))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Apply updates the UFO for a problem's current status and severity. It
+// returns false if nothing changed.
+func (d *Dynatrace) Apply(ctx context.Context, problem DynatraceProblem) (bool, error) {
+	action := d.actions[strings.ToUpper(problem.SeverityLevel)]
+
+	var changed bool
+	var err error
+	if problem.Status == "OPEN" && action != nil {
+		changed, err = d.display.Show(ctx, DynatraceSource, problem.ProblemID, problem.name(), AlertFiring, *action)
+	} else {
+		changed, err = d.display.Deactivate(ctx, DynatraceSource, problem.ProblemID, problem.name())
+	}
+
+	d.mu.Lock()
+	if problem.Status == "OPEN" && action != nil {
+		d.problems[problem.ProblemID] = problem
+	} else {
+		delete(d.problems, problem.ProblemID)
+	}
+	d.mu.Unlock()
+
+	if changed || err != nil {
+		d.record(problem, err)
+	}
+	return changed, err
+}
+
+// Problems returns the open problems currently shown on the UFO
+func (d *Dynatrace) Problems() []DynatraceProblem {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	problems := make([]DynatraceProblem, 0, len(d.problems))
+	for _, problem := range d.problems {
+		problems = append(problems, problem)
+	}
+	return problems
+}
+
+// Status reports the integration's health
+func (d *Dynatrace) Status() IntegrationStatus {
+	status := d.health.status()
+	for _, action := range d.actions {
+		if action != nil {
+			status.Configured++
+		}
+	}
+	status.Active = d.display.countSource(DynatraceSource)
+	return status
+}
+
+// SetEnabled turns polling on or off. Disabling clears Dynatrace problems
+// from the UFO; the next poll shows open ones again.
+func (d *Dynatrace) SetEnabled(ctx context.Context, enabled bool) error {
+	if !d.health.setEnabled(enabled) || enabled {
+		return nil
+	}
+	d.mu.Lock()
+	d.problems = make(map[string]DynatraceProblem)
+	d.mu.Unlock()
+	_, err := d.display.DeactivateSource(ctx, DynatraceSource)
+	return err
+}
+
+// fetchOpenProblems lists open problems, following result pages
+func (d *Dynatrace) fetchOpenProblems(ctx context.Context) ([]DynatraceProblem, error) {
+	selector := `status("open")`
+	if d.cfg.ProblemSelector != "" {
+		selector += "," + d.cfg.ProblemSelector
+	}
+	query := url.Values{}
+	query.Set("problemSelector", selector)
+	query.Set("pageSize", "500")
+
+	var problems []DynatraceProblem
+	for page := 0; page < maxDynatracePages; page++ {
+		var response struct {
+			Problems    []DynatraceProblem `json:"problems"`
+			NextPageKey string             `json:"nextPageKey"`
+		}
+		if err := d.call(ctx, "/api/v2/problems?"+query.Encode(), &response); err != nil {
+			return nil, err
+		}
+		problems = append(problems, response.Problems...)
+		if response.NextPageKey == "" {
+			return problems, nil
+		}
+		// Later pages are selected by the page key alone
+		query = url.Values{}
+		query.Set("nextPageKey", response.NextPageKey)
+	}
+	return problems, nil
+}
+
+// call performs a Dynatrace API GET request
+func (d *Dynatrace) call(ctx context.Context, path string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(d.cfg.EnvironmentURL, "/")+path, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Api-Token "+d.cfg.APIToken)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Dynatrace request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Dynatrace returned status %d: %s", resp.StatusCode, string(data))
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("parsing Dynatrace response: %w", err)
+	}
+	return nil
+}
+
+// record writes a problem transition to the audit log
+func (d *Dynatrace) record(problem DynatraceProblem, err error) {
+	if d.audit == nil {
+		return
+	}
+
+	result := "ok"
+	data := map[string]interface{}{
+		"problem":  problem.DisplayID,
+		"title":    problem.Title,
+		"severity": problem.SeverityLevel,
+		"status":   problem.Status,
+	}
+	if err != nil {
+		result = "error"
+		data["error"] = err.Error()
+	}
+	d.audit.Record(audit.Entry{
+		Kind:   "integration",
+		Action: DynatraceSource + " " + strings.ToLower(problem.Status),
+		Result: result,
+		Data:   data,
+	})
+}
+
+// Test applies a synthetic open problem and closes it during cleanup. input
+// may set "severity" (a severity level, default AVAILABILITY) and "title".
+func (d *Dynatrace) Test(ctx context.Context, input map[string]interface{}) (*TestReport, func(context.Context) error) {
+	report := &TestReport{}
+
+	key := testKey()
+	problem := DynatraceProblem{
+		ProblemID:     key,
+		DisplayID:     "P-TEST",
+		Title:         inputString(input, "title", "UFO test problem"),
+		SeverityLevel: strings.ToUpper(inputString(input, "severity", SeverityAvailability)),
+		Status:        "OPEN",
+	}
+	if severityIndex(problem.SeverityLevel) < 0 {
+		report.step("mapping", false, "severity must be one of %s, got %q", strings.Join(dynatraceSeverityOrder, ", "), problem.SeverityLevel)
+		return report, nil
+	}
+	report.step("mapping", true, "open %s problem '%s'", problem.SeverityLevel, problem.Title)
+
+	action := d.actions[problem.SeverityLevel]
+	if action == nil {
+		report.step("rules", false, "%s problems are ignored", problem.SeverityLevel)
+		return report, nil
+	}
+	report.step("rules", true, "%s problems show %s", problem.SeverityLevel, action.Describe())
+
+	_, err := d.Apply(ctx, problem)
+	cleanup := func(ctx context.Context) error {
+		closed := problem
+		closed.Status = "CLOSED"
+		_, err := d.Apply(ctx, closed)
+		return err
+	}
+	if err != nil {
+		report.step("lighting", false, "%v", err)
+		return report, cleanup
+	}
+	report.step("lighting", true, "%s", d.display.describeTop(DynatraceSource, key))
+	return report, cleanup
+}
+
+// severityIndex ranks a severity level, 0 being the most severe, or -1 for
+// an unknown level
+func severityIndex(level string) int {
+	for i, known := range dynatraceSeverityOrder {
+		if strings.EqualFold(level, known) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,95 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestDynatrace_PollProblems(t *testing.T) {
+	display, stateManager, queries := testDisplay(t)
+
+	var mu sync.Mutex
+	open := []DynatraceProblem{
+		{ProblemID: "p1", DisplayID: "P-1", Title: "Slow checkout", SeverityLevel: SeverityPerformance, Status: "OPEN"},
+		{ProblemID: "p2", DisplayID: "P-2", Title: "Checkout down", SeverityLevel: SeverityAvailability, Status: "OPEN"},
+		{ProblemID: "p3", DisplayID: "P-3", Title: "Disk info", SeverityLevel: SeverityInfo, Status: "OPEN"},
+	}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Api-Token dt0c01.secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/api/v2/problems" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if selector := r.URL.Query().Get("problemSelector"); selector != `status("open"),managementZones("Prod")` {
+			t.Errorf("unexpected problem selector %q", selector)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"problems": open})
+	}))
+	defer api.Close()
+
+	dynatrace, err := NewDynatrace(DynatraceConfig{
+		EnvironmentURL:  api.URL,
+		APIToken:        "dt0c01.secret",
+		ProblemSelector: `managementZones("Prod")`,
+		Severities: map[string]*Action{
+			"availability": {Effect: "alarm", Severity: "critical"},
+			SeverityInfo:   nil,
+		},
+	}, display, nil)
+	if err != nil {
+		t.Fatalf("failed to create dynatrace integration: %v", err)
+	}
+
+	if err := dynatrace.PollOnce(context.Background()); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+
+	// The availability problem is shown on top; ignored levels are not shown
+	if depth := stateManager.GetEffectStackDepth(); depth != 2 {
+		t.Fatalf("expected 2 problems on the stack, got %d", depth)
+	}
+	if current := stateManager.GetCurrentEffect(); current == nil || current.Name != "alarm" {
+		t.Errorf("expected the availability problem's effect on top, got %+v", current)
+	}
+	if status := dynatrace.Status(); status.Active != 2 || status.Configured != 6 {
+		t.Errorf("expected 2 active and 6 configured, got %+v", status)
+	}
+
+	// Problems that are no longer open are cleared
+	mu.Lock()
+	open = open[:1]
+	mu.Unlock()
+	if err := dynatrace.PollOnce(context.Background()); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	if current := stateManager.GetCurrentEffect(); current == nil || current.Name != "P-1 Slow checkout" {
+		t.Errorf("expected only the performance problem, got %+v", current)
+	}
+	sent := queries()
+	if last := sent[len(sent)-1]; last != "top_init=1&top_bg=ffff00&bottom_init=1&bottom_bg=ffff00" {
+		t.Errorf("expected yellow for the performance problem, got %s", last)
+	}
+}
+
+func TestNewDynatrace_Validation(t *testing.T) {
+	display, _, _ := testDisplay(t)
+
+	for name, cfg := range map[string]DynatraceConfig{
+		"missing url":    {APIToken: "token"},
+		"missing token":  {EnvironmentURL: "https://abc.live.dynatrace.com"},
+		"unknown level":  {EnvironmentURL: "https://abc.live.dynatrace.com", APIToken: "token", Severities: map[string]*Action{"P1": {Color: "red"}}},
+		"invalid action": {EnvironmentURL: "https://abc.live.dynatrace.com", APIToken: "token", Severities: map[string]*Action{"ERROR": {Color: "nope"}}},
+	} {
+		if _, err := NewDynatrace(cfg, display, nil); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
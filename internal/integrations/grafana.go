@@ -0,0 +1,255 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/audit"
+)
+
+// GrafanaSource identifies stack entries created by the Grafana integration
+const GrafanaSource = "grafana"
+
+// maxWebhookBytes limits the size of accepted webhook payloads
+const maxWebhookBytes = 1 << 20
+
+// GrafanaConfig configures the Grafana unified alerting contact point
+type GrafanaConfig struct {
+	Routes []Route `json:"routes"` // evaluated in order, first match wins
+}
+
+// GrafanaPayload is the webhook body sent by a Grafana unified alerting
+// webhook contact point
+type GrafanaPayload struct {
+	Receiver     string            `json:"receiver"`
+	Status       string            `json:"status"` // "firing" or "resolved"
+	GroupKey     string            `json:"groupKey"`
+	CommonLabels map[string]string `json:"commonLabels"`
+	Alerts       []GrafanaAlert    `json:"alerts"`
+}
+
+// GrafanaAlert is a single alert within a Grafana webhook payload
+type GrafanaAlert struct {
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	Fingerprint string            `json:"fingerprint"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+// GrafanaResult summarizes how a webhook payload was applied
+type GrafanaResult struct {
+	Fired    []string `json:"fired"`    // alerts newly shown on the UFO
+	Resolved []string `json:"resolved"` // alerts removed from the UFO
+	Ignored  []string `json:"ignored"`  // alerts with no matching route or already in that state
+}
+
+// Grafana applies Grafana alert notifications to the UFO
+type Grafana struct {
+	routes  []compiledRoute
+	display *Display
+	audit   *audit.Logger
+	health  health
+}
+
+// NewGrafana creates the Grafana integration from its configuration
+func NewGrafana(cfg GrafanaConfig, display *Display, auditLogger *audit.Logger) (*Grafana, error) {
+	routes, err := compileRoutes(cfg.Routes)
+	if err != nil {
+		return nil, fmt.Errorf("grafana: %w", err)
+	}
+	return &Grafana{
+		routes:  routes,
+		display: display,
+		audit:   auditLogger,
+	}, nil
+}
+
+// Handle applies every alert in a payload. Firing alerts that match a route
+// push that route's action onto the effect stack; resolved alerts remove
+// their entry and restore whatever was showing before.
+func (g *Grafana) Handle(ctx context.Context, payload GrafanaPayload) (*GrafanaResult, error) {
+	result := &GrafanaResult{Fired: []string{}, Resolved: []string{}, Ignored: []string{}}
+	if !g.health.enabled() {
+		for _, alert := range payload.Alerts {
+			result.Ignored = append(result.Ignored, alert.Labels["alertname"])
+		}
+		return result, nil
+	}
+
+	var errs []string
+	for _, alert := range payload.Alerts {
+		status := alert.Status
+		if status == "" {
+			status = payload.Status
+		}
+		key := alertKey(alert)
+		name := alert.Labels["alertname"]
+		if name == "" {
+			name = key
+		}
+
+		switch status {
+		case "firing":
+			route := matchRoute(g.routes, alert.Labels)
+			if route == nil {
+				result.Ignored = append(result.Ignored, name)
+				continue
+			}
+			fired, err := g.display.Activate(ctx, GrafanaSource, key, name, route.Action)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+				continue
+			}
+			if fired {
+				result.Fired = append(result.Fired, name)
+			} else {
+				result.Ignored = append(result.Ignored, name)
+			}
+		case "resolved":
+			resolved, err := g.display.Deactivate(ctx, GrafanaSource, key, name)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			}
+			if resolved {
+				result.Resolved = append(result.Resolved, name)
+			} else {
+				result.Ignored = append(result.Ignored, name)
+			}
+		default:
+			errs = append(errs, fmt.Sprintf("%s: unknown status %q", name, status))
+		}
+	}
+
+	g.record(payload, result, errs)
+
+	var err error
+	if len(errs) > 0 {
+		err = fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	g.health.observe(err)
+	return result, err
+}
+
+// Status reports the integration's health
+func (g *Grafana) Status() IntegrationStatus {
+	status := g.health.status()
+	status.Configured = len(g.routes)
+	status.Active = g.display.countSource(GrafanaSource)
+	return status
+}
+
+// SetEnabled turns webhook processing on or off. Disabling clears Grafana
+// alerts from the UFO; they return with Grafana's next notification.
+func (g *Grafana) SetEnabled(ctx context.Context, enabled bool) error {
+	if !g.health.setEnabled(enabled) || enabled {
+		return nil
+	}
+	_, err := g.display.DeactivateSource(ctx, GrafanaSource)
+	return err
+}
+
+// ServeHTTP accepts Grafana webhook contact point deliveries
+func (g *Grafana) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var payload GrafanaPayload
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBytes)).Decode(&payload); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid Grafana payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	result, err := g.Handle(r.Context(), payload)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Grafana webhook failed", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// record writes the outcome of a webhook delivery to the audit log
+func (g *Grafana) record(payload GrafanaPayload, result *GrafanaResult, errs []string) {
+	if g.audit == nil {
+		return
+	}
+
+	outcome := "ok"
+	if len(errs) > 0 {
+		outcome = "error"
+	}
+	g.audit.Record(audit.Entry{
+		Kind:   "integration",
+		Action: GrafanaSource,
+		Result: outcome,
+		Data: map[string]interface{}{
+			"receiver": payload.Receiver,
+			"groupKey": payload.GroupKey,
+			"status":   payload.Status,
+			"fired":    result.Fired,
+			"resolved": result.Resolved,
+			"ignored":  result.Ignored,
+			"errors":   errs,
+		},
+	})
+}
+
+// alertKey identifies an alert across notifications. Grafana provides a
+// fingerprint; older versions without one fall back to the sorted label set.
+func alertKey(alert GrafanaAlert) string {
+	if alert.Fingerprint != "" {
+		return alert.Fingerprint
+	}
+
+	labels := make([]string, 0, len(alert.Labels))
+	for name, value := range alert.Labels {
+		labels = append(labels, name+"="+value)
+	}
+	sort.Strings(labels)
+	return strings.Join(labels, ",")
+}
+
+// Test sends a synthetic firing alert through the routes and resolves it
+// during cleanup. input may set "labels"; alertname defaults to UFOTest.
+func (g *Grafana) Test(ctx context.Context, input map[string]interface{}) (*TestReport, func(context.Context) error) {
+	report := &TestReport{}
+
+	labels := map[string]string{"alertname": "UFOTest"}
+	if raw, ok := input["labels"].(map[string]interface{}); ok {
+		for name, value := range raw {
+			labels[name] = fmt.Sprint(value)
+		}
+	}
+	alert := GrafanaAlert{Status: "firing", Labels: labels, Fingerprint: testKey(), StartsAt: time.Now()}
+	report.step("mapping", true, "firing alert '%s' with labels %v", labels["alertname"], labels)
+
+	route := matchRoute(g.routes, labels)
+	if route == nil {
+		report.step("rules", false, "no route matches labels %v; the alert would be ignored", labels)
+		return report, nil
+	}
+	report.step("rules", true, "matched route '%s', which shows %s", route.Name, route.Action.Describe())
+
+	_, err := g.Handle(ctx, GrafanaPayload{Status: "firing", Alerts: []GrafanaAlert{alert}})
+	cleanup := func(ctx context.Context) error {
+		alert.Status = "resolved"
+		_, err := g.Handle(ctx, GrafanaPayload{Status: "resolved", Alerts: []GrafanaAlert{alert}})
+		return err
+	}
+	if err != nil {
+		report.step("lighting", false, "%v", err)
+		return report, cleanup
+	}
+	report.step("lighting", true, "%s", g.display.describeTop(GrafanaSource, alert.Fingerprint))
+	return report, cleanup
+}
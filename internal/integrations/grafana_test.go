@@ -0,0 +1,179 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// testDisplay returns a display backed by a fake UFO that records queries
+func testDisplay(t *testing.T) (*Display, *state.Manager, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		w.Write([]byte("OK"))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	broadcaster := events.NewBroadcaster()
+	t.Cleanup(broadcaster.Close)
+	stateManager := state.NewManager(broadcaster)
+
+	store := effects.NewStore(t.TempDir() + "/effects.json")
+	if err := store.Load(); err != nil {
+		t.Fatalf("failed to load effects: %v", err)
+	}
+	store.Add(&effects.Effect{Name: "alarm", Pattern: "effect=alarm", Perpetual: true})
+
+	display := NewDisplay(broadcaster, store, stateManager, effects.NewEngine(device.NewClient()))
+	return display, stateManager, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), queries...)
+	}
+}
+
+func TestGrafana_FiringAndResolved(t *testing.T) {
+	display, stateManager, queries := testDisplay(t)
+
+	grafana, err := NewGrafana(GrafanaConfig{Routes: []Route{
+		{Name: "critical", Match: map[string]string{"severity": "critical|page"}, Action: Action{Color: "red"}},
+		{Name: "warning", Match: map[string]string{"severity": "warning"}, Action: Action{Color: "orange", Zone: "top"}},
+	}}, display, nil)
+	if err != nil {
+		t.Fatalf("failed to create grafana integration: %v", err)
+	}
+
+	// Something is already playing before the alert
+	stateManager.PushEffect("calm", "top_init=1&top_bg=0000ff", nil)
+
+	firing := GrafanaPayload{Status: "firing", Alerts: []GrafanaAlert{
+		{Status: "firing", Fingerprint: "abc", Labels: map[string]string{"alertname": "DiskFull", "severity": "critical"}},
+		{Status: "firing", Fingerprint: "def", Labels: map[string]string{"alertname": "Noise", "severity": "info"}},
+	}}
+	result, err := grafana.Handle(context.Background(), firing)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Fired) != 1 || result.Fired[0] != "DiskFull" {
+		t.Errorf("expected DiskFull to fire, got %+v", result)
+	}
+	if len(result.Ignored) != 1 || result.Ignored[0] != "Noise" {
+		t.Errorf("expected Noise to be ignored, got %+v", result)
+	}
+	if current := stateManager.GetCurrentEffect(); current == nil || current.Name != "DiskFull" {
+		t.Fatalf("expected DiskFull on top of the stack, got %+v", current)
+	}
+
+	// Grafana repeats firing notifications; they must not stack up
+	grafana.Handle(context.Background(), firing)
+	if depth := stateManager.GetEffectStackDepth(); depth != 2 {
+		t.Errorf("expected stack depth 2 after repeated notification, got %d", depth)
+	}
+
+	resolved := GrafanaPayload{Status: "resolved", Alerts: []GrafanaAlert{
+		{Status: "resolved", Fingerprint: "abc", Labels: map[string]string{"alertname": "DiskFull", "severity": "critical"}},
+	}}
+	result, err = grafana.Handle(context.Background(), resolved)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Resolved) != 1 {
+		t.Errorf("expected DiskFull to resolve, got %+v", result)
+	}
+	if current := stateManager.GetCurrentEffect(); current == nil || current.Name != "calm" {
+		t.Errorf("expected calm to be restored, got %+v", current)
+	}
+
+	sent := queries()
+	if len(sent) != 2 {
+		t.Fatalf("expected 2 device queries, got %v", sent)
+	}
+	if sent[0] != "top_init=1&top_bg=ff0000&bottom_init=1&bottom_bg=ff0000" {
+		t.Errorf("unexpected alert query: %s", sent[0])
+	}
+	if sent[1] != "top_init=1&top_bg=0000ff" {
+		t.Errorf("expected previous pattern to be restored, got %s", sent[1])
+	}
+}
+
+func TestGrafana_ResolveBuriedAlert(t *testing.T) {
+	display, stateManager, queries := testDisplay(t)
+
+	grafana, err := NewGrafana(GrafanaConfig{Routes: []Route{{Name: "all", Action: Action{Color: "red"}}}}, display, nil)
+	if err != nil {
+		t.Fatalf("failed to create grafana integration: %v", err)
+	}
+
+	grafana.Handle(context.Background(), GrafanaPayload{Alerts: []GrafanaAlert{
+		{Status: "firing", Labels: map[string]string{"alertname": "A"}},
+	}})
+	stateManager.PushEffect("rainbow", "effect=rainbow", nil)
+
+	// Resolving an alert that is not showing removes it without touching the device
+	grafana.Handle(context.Background(), GrafanaPayload{Alerts: []GrafanaAlert{
+		{Status: "resolved", Labels: map[string]string{"alertname": "A"}},
+	}})
+	if depth := stateManager.GetEffectStackDepth(); depth != 1 {
+		t.Errorf("expected only rainbow left on the stack, got depth %d", depth)
+	}
+	if len(queries()) != 1 {
+		t.Errorf("expected no restore query, got %v", queries())
+	}
+}
+
+func TestGrafana_InvalidRoute(t *testing.T) {
+	display, _, _ := testDisplay(t)
+
+	if _, err := NewGrafana(GrafanaConfig{Routes: []Route{{Name: "bad", Action: Action{Color: "notacolor"}}}}, display, nil); err == nil {
+		t.Error("expected error for invalid color")
+	}
+	if _, err := NewGrafana(GrafanaConfig{Routes: []Route{{Name: "bad", Match: map[string]string{"a": "("}, Action: Action{Color: "red"}}}}, display, nil); err == nil {
+		t.Error("expected error for invalid matcher")
+	}
+}
+
+func TestGrafana_ServeHTTP(t *testing.T) {
+	display, _, _ := testDisplay(t)
+
+	grafana, err := NewGrafana(GrafanaConfig{Routes: []Route{{Name: "all", Action: Action{Effect: "alarm"}}}}, display, nil)
+	if err != nil {
+		t.Fatalf("failed to create grafana integration: %v", err)
+	}
+
+	body, _ := json.Marshal(GrafanaPayload{Status: "firing", Alerts: []GrafanaAlert{
+		{Status: "firing", Fingerprint: "x", Labels: map[string]string{"alertname": "HighLatency"}},
+	}})
+	rec := httptest.NewRecorder()
+	grafana.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/integrations/grafana", bytes.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var result GrafanaResult
+	json.Unmarshal(rec.Body.Bytes(), &result)
+	if len(result.Fired) != 1 {
+		t.Errorf("expected alert to fire, got %+v", result)
+	}
+
+	rec = httptest.NewRecorder()
+	grafana.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/integrations/grafana", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for GET, got %d", rec.Code)
+	}
+}
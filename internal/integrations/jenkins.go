@@ -0,0 +1,373 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/audit"
+	"github.com/starspace46/ufo-mcp-go/internal/color"
+)
+
+// JenkinsSource identifies stack entries created by the Jenkins integration
+const JenkinsSource = "jenkins"
+
+// defaultJenkinsPollMs is used when no poll interval is configured
+const defaultJenkinsPollMs = 15000
+
+// Jenkins build states
+const (
+	BuildBuilding = "building"
+	BuildSuccess  = "success"
+	BuildUnstable = "unstable"
+	BuildFailed   = "failed"
+)
+
+// JenkinsConfig configures the Jenkins build status poller
+type JenkinsConfig struct {
+	PollIntervalMs int          `json:"pollIntervalMs,omitempty"` // default 15000
+	Jobs           []JenkinsJob `json:"jobs"`
+}
+
+// JenkinsJob maps the state of a Jenkins job's latest build to lighting.
+// A state without an action clears the job's light.
+type JenkinsJob struct {
+	Name     string  `json:"name"`
+	URL      string  `json:"url"` // job URL, e.g. https://ci.example.com/job/app/job/main
+	Username string  `json:"username,omitempty"`
+	APIToken string  `json:"apiToken,omitempty"`
+	Building *Action `json:"building,omitempty"` // a color is drawn as a progress arc
+	Success  *Action `json:"success,omitempty"`
+	Unstable *Action `json:"unstable,omitempty"`
+	Failed   *Action `json:"failed,omitempty"`
+}
+
+// JenkinsBuild is the subset of the Jenkins build API used
+type JenkinsBuild struct {
+	Number            int    `json:"number"`
+	Building          bool   `json:"building"`
+	Result            string `json:"result"`            // SUCCESS, UNSTABLE, FAILURE, ABORTED, or empty while building
+	Timestamp         int64  `json:"timestamp"`         // start time in ms since epoch
+	EstimatedDuration int64  `json:"estimatedDuration"` // ms, -1 if unknown
+	URL               string `json:"url"`
+}
+
+// State returns the lighting state for the build, or "" for results that
+// clear the light (aborted, not built)
+func (b JenkinsBuild) State() string {
+	if b.Building {
+		return BuildBuilding
+	}
+	switch b.Result {
+	case "SUCCESS":
+		return BuildSuccess
+	case "UNSTABLE":
+		return BuildUnstable
+	case "FAILURE":
+		return BuildFailed
+	}
+	return ""
+}
+
+// Progress returns how far a running build is through its estimated
+// duration, between 0 and 1
+func (b JenkinsBuild) Progress(now time.Time) float64 {
+	if b.EstimatedDuration <= 0 || b.Timestamp <= 0 {
+		return 0
+	}
+	elapsed := now.Sub(time.UnixMilli(b.Timestamp))
+	progress := float64(elapsed.Milliseconds()) / float64(b.EstimatedDuration)
+	if progress < 0 {
+		return 0
+	}
+	if progress > 1 {
+		return 1
+	}
+	return progress
+}
+
+// Jenkins polls Jenkins jobs and shows their build status on the UFO
+type Jenkins struct {
+	cfg        JenkinsConfig
+	display    *Display
+	audit      *audit.Logger
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	states map[string]string // job name -> last applied state
+	health health
+}
+
+// NewJenkins creates the Jenkins integration from its configuration
+func NewJenkins(cfg JenkinsConfig, display *Display, auditLogger *audit.Logger) (*Jenkins, error) {
+	for i, job := range cfg.Jobs {
+		if job.Name == "" || job.URL == "" {
+			return nil, fmt.Errorf("jenkins: job %d: name and url are required", i)
+		}
+		for state, action := range job.actions() {
+			if action == nil {
+				continue
+			}
+			if err := action.Validate(); err != nil {
+				return nil, fmt.Errorf("jenkins: job '%s': %s: %w", job.Name, state, err)
+			}
+		}
+	}
+	if cfg.PollIntervalMs <= 0 {
+		cfg.PollIntervalMs = defaultJenkinsPollMs
+	}
+
+	return &Jenkins{
+		cfg:        cfg,
+		display:    display,
+		audit:      auditLogger,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		states:     make(map[string]string),
+	}, nil
+}
+
+// actions returns the job's actions keyed by build state
+func (j JenkinsJob) actions() map[string]*Action {
+	return map[string]*Action{
+		BuildBuilding: j.Building,
+		BuildSuccess:  j.Success,
+		BuildUnstable: j.Unstable,
+		BuildFailed:   j.Failed,
+	}
+}
+
+// Start polls every job in the background until ctx is cancelled
+func (j *Jenkins) Start(ctx context.Context) {
+	if len(j.cfg.Jobs) == 0 {
+		return
+	}
+	runPoller(ctx, "Jenkins", time.Duration(j.cfg.PollIntervalMs)*time.Millisecond, j.PollOnce)
+}
+
+// PollOnce fetches the latest build of every job and updates the UFO. It
+// does nothing while the integration is disabled.
+func (j *Jenkins) PollOnce(ctx context.Context) error {
+	if !j.health.enabled() {
+		return nil
+	}
+	err := j.pollJobs(ctx)
+	j.health.observe(err)
+	return err
+}
+
+// pollJobs performs one poll for PollOnce
+func (j *Jenkins) pollJobs(ctx context.Context) error {
+	var errs []string
+	for _, job := range j.cfg.Jobs {
+		build, err := j.fetchLastBuild(ctx, job)
+		if err == nil {
+			err = j.Apply(ctx, job, *build)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", job.Name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Apply shows a build's state for a job
+func (j *Jenkins) Apply(ctx context.Context, job JenkinsJob, build JenkinsBuild) error {
+	buildState := build.State()
+	action := job.actions()[buildState]
+
+	var changed bool
+	var err error
+	switch {
+	case action == nil:
+		changed, err = j.display.Deactivate(ctx, JenkinsSource, job.Name, job.Name)
+	case buildState == BuildBuilding && action.Effect == "":
+		changed, err = j.display.ShowPattern(ctx, JenkinsSource, job.Name, job.Name, buildState, action.Severity, progressArc(*action, build.Progress(time.Now())))
+	default:
+		changed, err = j.display.Show(ctx, JenkinsSource, job.Name, job.Name, buildState, *action)
+	}
+
+	j.mu.Lock()
+	previous := j.states[job.Name]
+	j.states[job.Name] = buildState
+	j.mu.Unlock()
+
+	if (changed && previous != buildState) || err != nil {
+		j.record(job, build, buildState, err)
+	}
+	return err
+}
+
+// Status reports the integration's health
+func (j *Jenkins) Status() IntegrationStatus {
+	status := j.health.status()
+	status.Configured = len(j.cfg.Jobs)
+	status.Active = j.display.countSource(JenkinsSource)
+	return status
+}
+
+// SetEnabled turns polling on or off. Disabling clears build lights from
+// the UFO; the next poll after enabling shows them again.
+func (j *Jenkins) SetEnabled(ctx context.Context, enabled bool) error {
+	if !j.health.setEnabled(enabled) || enabled {
+		return nil
+	}
+	j.mu.Lock()
+	j.states = make(map[string]string)
+	j.mu.Unlock()
+	_, err := j.display.DeactivateSource(ctx, JenkinsSource)
+	return err
+}
+
+// progressArc lights a share of the action's zone proportional to progress,
+// always at least one LED so a starting build is visible
+func progressArc(action Action, progress float64) string {
+	hex, _ := color.Parse(action.Color)
+	leds := int(progress*15 + 0.5)
+	if leds < 1 {
+		leds = 1
+	}
+
+	var parts []string
+	for _, ring := range []string{"top", "bottom"} {
+		if action.Zone == "" || action.Zone == "all" || action.Zone == ring {
+			parts = append(parts, fmt.Sprintf("%s_init=1&%s=0|%d|%s", ring, ring, leds, hex))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// fetchLastBuild queries the job's latest build
+func (j *Jenkins) fetchLastBuild(ctx context.Context, job JenkinsJob) (*JenkinsBuild, error) {
+	url := strings.TrimRight(job.URL, "/") + "/lastBuild/api/json?tree=number,building,result,timestamp,estimatedDuration,url"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	if job.Username != "" {
+		req.SetBasicAuth(job.Username, job.APIToken)
+	}
+
+	resp, err := j.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Jenkins request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		// Job has never been built
+		return &JenkinsBuild{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Jenkins returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var build JenkinsBuild
+	if err := json.Unmarshal(body, &build); err != nil {
+		return nil, fmt.Errorf("parsing Jenkins response: %w", err)
+	}
+	return &build, nil
+}
+
+// record writes a build state change to the audit log
+func (j *Jenkins) record(job JenkinsJob, build JenkinsBuild, buildState string, err error) {
+	if j.audit == nil {
+		return
+	}
+
+	result := "ok"
+	data := map[string]interface{}{
+		"job":   job.Name,
+		"build": build.Number,
+		"state": buildState,
+		"url":   build.URL,
+	}
+	if err != nil {
+		result = "error"
+		data["error"] = err.Error()
+	}
+	j.audit.Record(audit.Entry{
+		Kind:   "integration",
+		Action: JenkinsSource,
+		Result: result,
+		Data:   data,
+	})
+}
+
+// Test applies a synthetic build to a job and clears it during cleanup.
+// input may set "job" (default the first job) and "state" (building,
+// success, unstable or failed; default failed). The test lighting uses its
+// own key, so the job's real light is left alone.
+func (j *Jenkins) Test(ctx context.Context, input map[string]interface{}) (*TestReport, func(context.Context) error) {
+	report := &TestReport{}
+
+	if len(j.cfg.Jobs) == 0 {
+		report.step("mapping", false, "no jobs are configured")
+		return report, nil
+	}
+	name := inputString(input, "job", j.cfg.Jobs[0].Name)
+	var job *JenkinsJob
+	for i := range j.cfg.Jobs {
+		if j.cfg.Jobs[i].Name == name {
+			job = &j.cfg.Jobs[i]
+		}
+	}
+	if job == nil {
+		report.step("mapping", false, "job '%s' is not configured", name)
+		return report, nil
+	}
+
+	now := time.Now()
+	build := JenkinsBuild{Timestamp: now.Add(-30 * time.Second).UnixMilli(), EstimatedDuration: 60000}
+	buildState := inputString(input, "state", BuildFailed)
+	switch buildState {
+	case BuildBuilding:
+		build.Building = true
+	case BuildSuccess:
+		build.Result = "SUCCESS"
+	case BuildUnstable:
+		build.Result = "UNSTABLE"
+	case BuildFailed:
+		build.Result = "FAILURE"
+	default:
+		report.step("mapping", false, "state must be building, success, unstable or failed, got %q", buildState)
+		return report, nil
+	}
+	report.step("mapping", true, "%s build of job '%s'", buildState, job.Name)
+
+	action := job.actions()[buildState]
+	if action == nil {
+		report.step("rules", false, "job '%s' has no %s action; its light would be cleared", job.Name, buildState)
+		return report, nil
+	}
+	report.step("rules", true, "%s builds show %s", buildState, action.Describe())
+
+	testJob := *job
+	testJob.Name = TestKeyPrefix + job.Name
+	err := j.Apply(ctx, testJob, build)
+	cleanup := func(ctx context.Context) error {
+		j.mu.Lock()
+		delete(j.states, testJob.Name)
+		j.mu.Unlock()
+		_, err := j.display.Deactivate(ctx, JenkinsSource, testJob.Name, testJob.Name)
+		return err
+	}
+	if err != nil {
+		report.step("lighting", false, "%v", err)
+		return report, cleanup
+	}
+	report.step("lighting", true, "%s", j.display.describeTop(JenkinsSource, testJob.Name))
+	return report, cleanup
+}
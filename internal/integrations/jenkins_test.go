@@ -0,0 +1,95 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJenkinsBuild_StateAndProgress(t *testing.T) {
+	now := time.Now()
+	build := JenkinsBuild{Building: true, Timestamp: now.Add(-30 * time.Second).UnixMilli(), EstimatedDuration: 60000}
+	if build.State() != BuildBuilding {
+		t.Errorf("expected building, got %s", build.State())
+	}
+	if progress := build.Progress(now); progress < 0.49 || progress > 0.51 {
+		t.Errorf("expected progress 0.5, got %f", progress)
+	}
+
+	cases := map[string]string{"SUCCESS": BuildSuccess, "UNSTABLE": BuildUnstable, "FAILURE": BuildFailed, "ABORTED": ""}
+	for result, want := range cases {
+		if got := (JenkinsBuild{Result: result}).State(); got != want {
+			t.Errorf("%s: expected %q, got %q", result, want, got)
+		}
+	}
+}
+
+func TestProgressArc(t *testing.T) {
+	if got := progressArc(Action{Color: "blue", Zone: "top"}, 0.5); got != "top_init=1&top=0|8|0000ff" {
+		t.Errorf("unexpected arc: %s", got)
+	}
+	if got := progressArc(Action{Color: "blue"}, 0); !strings.Contains(got, "top=0|1|0000ff") || !strings.Contains(got, "bottom=0|1|0000ff") {
+		t.Errorf("expected one LED on both rings at start, got %s", got)
+	}
+}
+
+func TestJenkins_PollLifecycle(t *testing.T) {
+	display, stateManager, queries := testDisplay(t)
+
+	build := JenkinsBuild{Number: 7, Building: true, Timestamp: time.Now().UnixMilli(), EstimatedDuration: 600000}
+	ci := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "bot" || pass != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/job/app/lastBuild/api/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(build)
+	}))
+	defer ci.Close()
+
+	jenkins, err := NewJenkins(JenkinsConfig{Jobs: []JenkinsJob{{
+		Name:     "app",
+		URL:      ci.URL + "/job/app/",
+		Username: "bot",
+		APIToken: "token",
+		Building: &Action{Color: "blue", Zone: "top"},
+		Failed:   &Action{Color: "red"},
+	}}}, display, nil)
+	if err != nil {
+		t.Fatalf("failed to create jenkins integration: %v", err)
+	}
+
+	if err := jenkins.PollOnce(context.Background()); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	if sent := queries(); len(sent) != 1 || sent[0] != "top_init=1&top=0|1|0000ff" {
+		t.Fatalf("expected progress arc, got %v", sent)
+	}
+
+	// Unchanged progress does not resend
+	jenkins.PollOnce(context.Background())
+	if len(queries()) != 1 {
+		t.Errorf("expected no new query for unchanged progress, got %v", queries())
+	}
+
+	build = JenkinsBuild{Number: 7, Result: "FAILURE"}
+	jenkins.PollOnce(context.Background())
+	if current := stateManager.GetCurrentEffect(); current == nil || current.Context["alertState"] != BuildFailed {
+		t.Fatalf("expected failed state, got %+v", current)
+	}
+
+	// Success has no action configured, so the light is cleared
+	build = JenkinsBuild{Number: 8, Result: "SUCCESS"}
+	jenkins.PollOnce(context.Background())
+	if depth := stateManager.GetEffectStackDepth(); depth != 0 {
+		t.Errorf("expected light cleared on success, got depth %d", depth)
+	}
+}
@@ -0,0 +1,101 @@
+package integrations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ExtractPath evaluates a simple JSONPath expression against a decoded JSON
+// document. Supported syntax is the root `$`, dotted member access
+// (`$.data.status`), bracketed member access (`$['build-status']`) and array
+// indexes, including negative indexes from the end (`$.items[0]`, `$.items[-1]`).
+func ExtractPath(doc interface{}, path string) (interface{}, error) {
+	tokens, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := doc
+	for _, token := range tokens {
+		switch key := token.(type) {
+		case string:
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot read member '%s' of %T", key, current)
+			}
+			value, exists := object[key]
+			if !exists {
+				return nil, fmt.Errorf("member '%s' not found", key)
+			}
+			current = value
+		case int:
+			array, ok := current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot index %T", current)
+			}
+			index := key
+			if index < 0 {
+				index += len(array)
+			}
+			if index < 0 || index >= len(array) {
+				return nil, fmt.Errorf("index %d out of range (length %d)", key, len(array))
+			}
+			current = array[index]
+		}
+	}
+	return current, nil
+}
+
+// ValidatePath reports whether path is a JSONPath expression ExtractPath
+// understands
+func ValidatePath(path string) error {
+	_, err := parsePath(path)
+	return err
+}
+
+// parsePath splits a path into member names (string) and indexes (int)
+func parsePath(path string) ([]interface{}, error) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("path must start with '$': %q", path)
+	}
+
+	var tokens []interface{}
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty member name in %q", path)
+			}
+			tokens = append(tokens, rest[:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed '[' in %q", path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				tokens = append(tokens, inner[1:len(inner)-1])
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %q in %q", inner, path)
+			}
+			tokens = append(tokens, index)
+		default:
+			return nil, fmt.Errorf("unexpected %q in %q", rest[0], path)
+		}
+	}
+	return tokens, nil
+}
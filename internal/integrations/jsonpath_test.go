@@ -0,0 +1,44 @@
+package integrations
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExtractPath(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(`{
+		"status": {"indicator": "minor"},
+		"build-status": "green",
+		"items": [{"value": 1}, {"value": 2}, {"value": 3}]
+	}`), &doc); err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"$.status.indicator", "minor"},
+		{"$['build-status']", "green"},
+		{`$["status"]["indicator"]`, "minor"},
+		{"$.items[0].value", float64(1)},
+		{"$.items[-1].value", float64(3)},
+	}
+	for _, tt := range tests {
+		got, err := ExtractPath(doc, tt.path)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.path, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	for _, path := range []string{"status", "$.missing", "$.items[5]", "$.status[0]", "$.items.value", "$.items[x]", "$.", "$[0"} {
+		if _, err := ExtractPath(doc, path); err == nil {
+			t.Errorf("%s: expected an error", path)
+		}
+	}
+}
@@ -0,0 +1,501 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/audit"
+)
+
+// PagerDutySource identifies stack entries created by the PagerDuty integration
+const PagerDutySource = "pagerduty"
+
+// defaultPagerDutyAPI is the PagerDuty REST API base URL
+const defaultPagerDutyAPI = "https://api.pagerduty.com"
+
+// PagerDutyConfig configures the PagerDuty webhook receiver and poller
+type PagerDutyConfig struct {
+	Services       []string `json:"services,omitempty"`       // service IDs to follow; empty follows all
+	Triggered      Action   `json:"triggered"`                // shown while an incident is triggered
+	Acknowledged   Action   `json:"acknowledged"`             // shown while an incident is acknowledged
+	APIToken       string   `json:"apiToken,omitempty"`       // REST API token, needed for polling and acknowledging
+	From           string   `json:"from,omitempty"`           // email of the PagerDuty user acknowledging incidents
+	WebhookSecret  string   `json:"webhookSecret,omitempty"`  // verifies X-PagerDuty-Signature when set
+	PollIntervalMs int      `json:"pollIntervalMs,omitempty"` // poll open incidents; 0 disables polling
+	APIURL         string   `json:"apiUrl,omitempty"`         // override for testing
+}
+
+// PagerDutyIncident is the incident data used from webhooks and the REST API
+type PagerDutyIncident struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Status  string `json:"status"` // "triggered", "acknowledged" or "resolved"
+	HTMLURL string `json:"html_url"`
+	Service struct {
+		ID      string `json:"id"`
+		Summary string `json:"summary"`
+	} `json:"service"`
+}
+
+// PagerDutyWebhook is a PagerDuty V3 webhook delivery
+type PagerDutyWebhook struct {
+	Event struct {
+		ID        string            `json:"id"`
+		EventType string            `json:"event_type"` // e.g. "incident.triggered"
+		Data      PagerDutyIncident `json:"data"`
+	} `json:"event"`
+}
+
+// PagerDuty maps PagerDuty incidents on selected services to lighting states
+type PagerDuty struct {
+	cfg        PagerDutyConfig
+	display    *Display
+	audit      *audit.Logger
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	incidents map[string]PagerDutyIncident // open incidents shown on the UFO
+	health    health
+}
+
+// NewPagerDuty creates the PagerDuty integration from its configuration
+func NewPagerDuty(cfg PagerDutyConfig, display *Display, auditLogger *audit.Logger) (*PagerDuty, error) {
+	if err := cfg.Triggered.Validate(); err != nil {
+		return nil, fmt.Errorf("pagerduty: triggered: %w", err)
+	}
+	if err := cfg.Acknowledged.Validate(); err != nil {
+		return nil, fmt.Errorf("pagerduty: acknowledged: %w", err)
+	}
+	if cfg.PollIntervalMs > 0 && cfg.APIToken == "" {
+		return nil, fmt.Errorf("pagerduty: apiToken is required for polling")
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = defaultPagerDutyAPI
+	}
+
+	return &PagerDuty{
+		cfg:        cfg,
+		display:    display,
+		audit:      auditLogger,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		incidents:  make(map[string]PagerDutyIncident),
+	}, nil
+}
+
+// CanAcknowledge reports whether the integration has the credentials needed
+// to acknowledge incidents
+func (p *PagerDuty) CanAcknowledge() bool {
+	return p.cfg.APIToken != "" && p.cfg.From != ""
+}
+
+// follows reports whether incidents on a service are tracked
+func (p *PagerDuty) follows(serviceID string) bool {
+	if len(p.cfg.Services) == 0 {
+		return true
+	}
+	for _, id := range p.cfg.Services {
+		if id == serviceID {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply updates the UFO for an incident's current status. It returns false
+// if nothing changed.
+func (p *PagerDuty) Apply(ctx context.Context, incident PagerDutyIncident) (bool, error) {
+	if !p.follows(incident.Service.ID) {
+		return false, nil
+	}
+
+	name := incident.Title
+	if name == "" {
+		name = incident.ID
+	}
+
+	var changed bool
+	var err error
+	switch incident.Status {
+	case "triggered":
+		changed, err = p.display.Show(ctx, PagerDutySource, incident.ID, name, AlertFiring, p.cfg.Triggered)
+	case "acknowledged":
+		changed, err = p.display.Show(ctx, PagerDutySource, incident.ID, name, AlertAcknowledged, p.cfg.Acknowledged)
+	case "resolved":
+		changed, err = p.display.Deactivate(ctx, PagerDutySource, incident.ID, name)
+	default:
+		return false, nil
+	}
+
+	p.mu.Lock()
+	if incident.Status == "resolved" {
+		delete(p.incidents, incident.ID)
+	} else {
+		p.incidents[incident.ID] = incident
+	}
+	p.mu.Unlock()
+
+	if changed || err != nil {
+		p.record("incident."+incident.Status, incident, err)
+	}
+	return changed, err
+}
+
+// Incidents returns the open incidents currently shown on the UFO
+func (p *PagerDuty) Incidents() []PagerDutyIncident {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	incidents := make([]PagerDutyIncident, 0, len(p.incidents))
+	for _, incident := range p.incidents {
+		incidents = append(incidents, incident)
+	}
+	return incidents
+}
+
+// ServeHTTP accepts PagerDuty V3 webhook deliveries
+func (p *PagerDuty) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	if p.cfg.WebhookSecret != "" && !validPagerDutySignature(p.cfg.WebhookSecret, body, r.Header.Get("X-PagerDuty-Signature")) {
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+	if !p.health.enabled() {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var webhook PagerDutyWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid PagerDuty payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Only incident status changes affect the lights; other event types
+	// (annotations, priority changes, ...) are accepted and ignored
+	incident := webhook.Event.Data
+	switch webhook.Event.EventType {
+	case "incident.triggered", "incident.reopened":
+		incident.Status = "triggered"
+	case "incident.acknowledged":
+		incident.Status = "acknowledged"
+	case "incident.resolved":
+		incident.Status = "resolved"
+	default:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	_, err = p.Apply(r.Context(), incident)
+	if err != nil {
+		slog.WarnContext(r.Context(), "PagerDuty webhook failed", "error", err)
+	}
+	p.health.observe(err)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validPagerDutySignature checks the X-PagerDuty-Signature header, which may
+// carry several comma-separated "v1=<hex>" signatures during secret rotation
+func validPagerDutySignature(secret string, body []byte, header string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := "v1=" + hex.EncodeToString(mac.Sum(nil))
+
+	for _, signature := range strings.Split(header, ",") {
+		if hmac.Equal([]byte(strings.TrimSpace(signature)), []byte(expected)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Start polls open incidents in the background until ctx is cancelled
+func (p *PagerDuty) Start(ctx context.Context) {
+	if p.cfg.PollIntervalMs <= 0 {
+		return
+	}
+
+	runPoller(ctx, "PagerDuty", time.Duration(p.cfg.PollIntervalMs)*time.Millisecond, p.PollOnce)
+}
+
+// PollOnce fetches open incidents and reconciles the UFO with them.
+// Incidents shown earlier that are no longer open are treated as resolved.
+// It does nothing while the integration is disabled.
+func (p *PagerDuty) PollOnce(ctx context.Context) error {
+	if !p.health.enabled() {
+		return nil
+	}
+	err := p.pollIncidents(ctx)
+	p.health.observe(err)
+	return err
+}
+
+// pollIncidents performs one poll for PollOnce
+func (p *PagerDuty) pollIncidents(ctx context.Context) error {
+	query := url.Values{}
+	query.Add("statuses[]", "triggered")
+	query.Add("statuses[]", "acknowledged")
+	for _, service := range p.cfg.Services {
+		query.Add("service_ids[]", service)
+	}
+
+	var response struct {
+		Incidents []PagerDutyIncident `json:"incidents"`
+	}
+	if err := p.call(ctx, http.MethodGet, "/incidents?"+query.Encode(), nil, &response); err != nil {
+		return err
+	}
+
+	open := make(map[string]bool, len(response.Incidents))
+	var errs []string
+	for _, incident := range response.Incidents {
+		open[incident.ID] = true
+		if _, err := p.Apply(ctx, incident); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", incident.ID, err))
+		}
+	}
+
+	for _, incident := range p.Incidents() {
+		if !open[incident.ID] {
+			incident.Status = "resolved"
+			if _, err := p.Apply(ctx, incident); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", incident.ID, err))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Status reports the integration's health
+func (p *PagerDuty) Status() IntegrationStatus {
+	status := p.health.status()
+	status.Configured = len(p.cfg.Services)
+	status.Active = p.display.countSource(PagerDutySource)
+	return status
+}
+
+// SetEnabled turns webhook and poll processing on or off. Disabling clears
+// PagerDuty incidents from the UFO; the next poll shows open ones again.
+func (p *PagerDuty) SetEnabled(ctx context.Context, enabled bool) error {
+	if !p.health.setEnabled(enabled) || enabled {
+		return nil
+	}
+	p.mu.Lock()
+	p.incidents = make(map[string]PagerDutyIncident)
+	p.mu.Unlock()
+	_, err := p.display.DeactivateSource(ctx, PagerDutySource)
+	return err
+}
+
+// Acknowledge acknowledges an incident through the PagerDuty REST API and
+// switches its light to the acknowledged state. An empty id acknowledges
+// the most recently shown triggered incident.
+func (p *PagerDuty) Acknowledge(ctx context.Context, id string) (*PagerDutyIncident, error) {
+	if !p.CanAcknowledge() {
+		return nil, fmt.Errorf("acknowledging requires apiToken and from in the PagerDuty configuration")
+	}
+
+	if id == "" {
+		incident := p.latestTriggered()
+		if incident == nil {
+			return nil, fmt.Errorf("no triggered PagerDuty incident is being shown")
+		}
+		id = incident.ID
+	}
+
+	body := map[string]interface{}{
+		"incident": map[string]interface{}{
+			"type":   "incident_reference",
+			"status": "acknowledged",
+		},
+	}
+	var response struct {
+		Incident PagerDutyIncident `json:"incident"`
+	}
+	if err := p.call(ctx, http.MethodPut, "/incidents/"+url.PathEscape(id), body, &response); err != nil {
+		return nil, err
+	}
+
+	incident := response.Incident
+	if incident.ID == "" {
+		incident.ID = id
+	}
+	incident.Status = "acknowledged"
+	if known, ok := p.known(id); ok {
+		incident.Title, incident.Service = known.Title, known.Service
+	}
+	if _, err := p.Apply(ctx, incident); err != nil {
+		return &incident, err
+	}
+	return &incident, nil
+}
+
+// known returns a tracked incident by ID
+func (p *PagerDuty) known(id string) (PagerDutyIncident, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	incident, ok := p.incidents[id]
+	return incident, ok
+}
+
+// latestTriggered returns the topmost triggered incident on the effect stack
+func (p *PagerDuty) latestTriggered() *PagerDutyIncident {
+	stack := p.display.stateManager.GetEffectStack()
+	for i := len(stack) - 1; i >= 0; i-- {
+		item := stack[i]
+		if source, _ := item.Context["source"].(string); source != PagerDutySource {
+			continue
+		}
+		if alertState, _ := item.Context["alertState"].(string); alertState != AlertFiring {
+			continue
+		}
+		id, _ := item.Context["alertKey"].(string)
+		if incident, ok := p.known(id); ok {
+			return &incident
+		}
+		return &PagerDutyIncident{ID: id}
+	}
+	return nil
+}
+
+// call performs a PagerDuty REST API request
+func (p *PagerDuty) call(ctx context.Context, method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshaling request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(p.cfg.APIURL, "/")+path, reader)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Token token="+p.cfg.APIToken)
+	if p.cfg.From != "" {
+		req.Header.Set("From", p.cfg.From)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("PagerDuty request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("PagerDuty returned status %d: %s", resp.StatusCode, string(data))
+	}
+
+	if result != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, result); err != nil {
+			return fmt.Errorf("parsing PagerDuty response: %w", err)
+		}
+	}
+	return nil
+}
+
+// record writes an incident transition to the audit log
+func (p *PagerDuty) record(action string, incident PagerDutyIncident, err error) {
+	if p.audit == nil {
+		return
+	}
+
+	result := "ok"
+	data := map[string]interface{}{
+		"incident": incident.ID,
+		"title":    incident.Title,
+		"service":  incident.Service.ID,
+	}
+	if err != nil {
+		result = "error"
+		data["error"] = err.Error()
+	}
+	p.audit.Record(audit.Entry{
+		Kind:   "integration",
+		Action: PagerDutySource + " " + action,
+		Result: result,
+		Data:   data,
+	})
+}
+
+// Test applies a synthetic incident and resolves it during cleanup. input
+// may set "status" (triggered or acknowledged), "service" and "title".
+func (p *PagerDuty) Test(ctx context.Context, input map[string]interface{}) (*TestReport, func(context.Context) error) {
+	report := &TestReport{}
+
+	service := "UFOTEST"
+	if len(p.cfg.Services) > 0 {
+		service = p.cfg.Services[0]
+	}
+	incident := PagerDutyIncident{
+		ID:     testKey(),
+		Title:  inputString(input, "title", "UFO test incident"),
+		Status: inputString(input, "status", "triggered"),
+	}
+	incident.Service.ID = inputString(input, "service", service)
+
+	var action Action
+	switch incident.Status {
+	case "triggered":
+		action = p.cfg.Triggered
+	case "acknowledged":
+		action = p.cfg.Acknowledged
+	default:
+		report.step("mapping", false, "status must be 'triggered' or 'acknowledged', got %q", incident.Status)
+		return report, nil
+	}
+	report.step("mapping", true, "%s incident '%s' on service %s", incident.Status, incident.Title, incident.Service.ID)
+
+	if !p.follows(incident.Service.ID) {
+		report.step("rules", false, "service %s is not one of the followed services %v", incident.Service.ID, p.cfg.Services)
+		return report, nil
+	}
+	report.step("rules", true, "%s incidents show %s", incident.Status, action.Describe())
+
+	_, err := p.Apply(ctx, incident)
+	cleanup := func(ctx context.Context) error {
+		resolved := incident
+		resolved.Status = "resolved"
+		_, err := p.Apply(ctx, resolved)
+		return err
+	}
+	if err != nil {
+		report.step("lighting", false, "%v", err)
+		return report, cleanup
+	}
+	report.step("lighting", true, "%s", p.display.describeTop(PagerDutySource, incident.ID))
+	return report, cleanup
+}
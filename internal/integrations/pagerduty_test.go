@@ -0,0 +1,191 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func pagerDutyWebhookBody(eventType, id, service string) []byte {
+	var webhook PagerDutyWebhook
+	webhook.Event.EventType = eventType
+	webhook.Event.Data.ID = id
+	webhook.Event.Data.Title = "Database down"
+	webhook.Event.Data.Service.ID = service
+	body, _ := json.Marshal(webhook)
+	return body
+}
+
+func TestPagerDuty_WebhookLifecycle(t *testing.T) {
+	display, stateManager, queries := testDisplay(t)
+
+	pagerDuty, err := NewPagerDuty(PagerDutyConfig{
+		Services:     []string{"PSVC1"},
+		Triggered:    Action{Color: "red"},
+		Acknowledged: Action{Color: "yellow"},
+	}, display, nil)
+	if err != nil {
+		t.Fatalf("failed to create pagerduty integration: %v", err)
+	}
+
+	send := func(eventType, id, service string) {
+		rec := httptest.NewRecorder()
+		pagerDuty.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/integrations/pagerduty", bytes.NewReader(pagerDutyWebhookBody(eventType, id, service))))
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	stateManager.PushEffect("calm", "effect=calm", nil)
+
+	// Incidents on other services are ignored
+	send("incident.triggered", "PINC0", "POTHER")
+	if depth := stateManager.GetEffectStackDepth(); depth != 1 {
+		t.Fatalf("expected unfollowed service to be ignored, got depth %d", depth)
+	}
+
+	send("incident.triggered", "PINC1", "PSVC1")
+	current := stateManager.GetCurrentEffect()
+	if current == nil || current.Context["alertState"] != AlertFiring {
+		t.Fatalf("expected triggered incident on top, got %+v", current)
+	}
+
+	// Acknowledging replaces the entry in place instead of pushing another
+	send("incident.acknowledged", "PINC1", "PSVC1")
+	if depth := stateManager.GetEffectStackDepth(); depth != 2 {
+		t.Errorf("expected stack depth 2 after acknowledge, got %d", depth)
+	}
+	current = stateManager.GetCurrentEffect()
+	if current == nil || current.Context["alertState"] != AlertAcknowledged {
+		t.Errorf("expected acknowledged incident on top, got %+v", current)
+	}
+
+	send("incident.resolved", "PINC1", "PSVC1")
+	if current := stateManager.GetCurrentEffect(); current == nil || current.Name != "calm" {
+		t.Errorf("expected calm to be restored, got %+v", current)
+	}
+
+	sent := queries()
+	want := []string{
+		"top_init=1&top_bg=ff0000&bottom_init=1&bottom_bg=ff0000",
+		"top_init=1&top_bg=ffff00&bottom_init=1&bottom_bg=ffff00",
+		"effect=calm",
+	}
+	if len(sent) != len(want) {
+		t.Fatalf("expected queries %v, got %v", want, sent)
+	}
+	for i := range want {
+		if sent[i] != want[i] {
+			t.Errorf("query %d: expected %s, got %s", i, want[i], sent[i])
+		}
+	}
+}
+
+func TestPagerDuty_WebhookSignature(t *testing.T) {
+	display, _, _ := testDisplay(t)
+
+	pagerDuty, err := NewPagerDuty(PagerDutyConfig{
+		Triggered:     Action{Color: "red"},
+		Acknowledged:  Action{Color: "yellow"},
+		WebhookSecret: "s3cret",
+	}, display, nil)
+	if err != nil {
+		t.Fatalf("failed to create pagerduty integration: %v", err)
+	}
+
+	body := pagerDutyWebhookBody("incident.triggered", "PINC1", "PSVC1")
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/integrations/pagerduty", bytes.NewReader(body))
+	req.Header.Set("X-PagerDuty-Signature", "v1=deadbeef")
+	pagerDuty.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for bad signature, got %d", rec.Code)
+	}
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/integrations/pagerduty", bytes.NewReader(body))
+	req.Header.Set("X-PagerDuty-Signature", "v1=old, v1="+hex.EncodeToString(mac.Sum(nil)))
+	pagerDuty.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204 for valid signature, got %d", rec.Code)
+	}
+}
+
+func TestPagerDuty_PollAndAcknowledge(t *testing.T) {
+	display, stateManager, _ := testDisplay(t)
+
+	var acknowledged string
+	open := []PagerDutyIncident{{ID: "PINC1", Title: "Disk full", Status: "triggered"}}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Token token=tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/incidents":
+			json.NewEncoder(w).Encode(map[string]interface{}{"incidents": open})
+		case r.Method == http.MethodPut && r.URL.Path == "/incidents/PINC1":
+			if r.Header.Get("From") != "oncall@example.com" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			acknowledged = string(body)
+			json.NewEncoder(w).Encode(map[string]interface{}{"incident": map[string]string{"id": "PINC1", "status": "acknowledged"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	pagerDuty, err := NewPagerDuty(PagerDutyConfig{
+		Triggered:    Action{Color: "red"},
+		Acknowledged: Action{Color: "yellow"},
+		APIToken:     "tok",
+		From:         "oncall@example.com",
+		APIURL:       api.URL,
+	}, display, nil)
+	if err != nil {
+		t.Fatalf("failed to create pagerduty integration: %v", err)
+	}
+
+	if err := pagerDuty.PollOnce(context.Background()); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	if depth := stateManager.GetEffectStackDepth(); depth != 1 {
+		t.Fatalf("expected polled incident on the stack, got depth %d", depth)
+	}
+
+	incident, err := pagerDuty.Acknowledge(context.Background(), "")
+	if err != nil {
+		t.Fatalf("acknowledge failed: %v", err)
+	}
+	if incident.ID != "PINC1" || incident.Title != "Disk full" {
+		t.Errorf("unexpected incident: %+v", incident)
+	}
+	if acknowledged == "" {
+		t.Error("expected PagerDuty API to receive the acknowledgement")
+	}
+	if current := stateManager.GetCurrentEffect(); current == nil || current.Context["alertState"] != AlertAcknowledged {
+		t.Errorf("expected acknowledged light, got %+v", current)
+	}
+
+	// Incidents missing from the next poll are resolved
+	open = nil
+	if err := pagerDuty.PollOnce(context.Background()); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	if depth := stateManager.GetEffectStackDepth(); depth != 0 {
+		t.Errorf("expected resolved incident to be removed, got depth %d", depth)
+	}
+}
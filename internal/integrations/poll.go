@@ -0,0 +1,27 @@
+package integrations
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// runPoller calls poll immediately and then every interval until ctx is
+// cancelled, logging failures under name
+func runPoller(ctx context.Context, name string, interval time.Duration, poll func(ctx context.Context) error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := poll(ctx); err != nil && ctx.Err() == nil {
+				slog.WarnContext(ctx, "Integration poll failed", "integration", name, "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,241 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+)
+
+// Integration is a configured integration managed at runtime
+type Integration interface {
+	Tester
+	Status() IntegrationStatus
+	SetEnabled(ctx context.Context, enabled bool) error
+}
+
+// IntegrationStatus reports an integration's health
+type IntegrationStatus struct {
+	Name         string     `json:"name"`
+	Enabled      bool       `json:"enabled"`
+	LastActivity *time.Time `json:"lastActivity,omitempty"` // last poll or webhook delivery
+	LastError    string     `json:"lastError,omitempty"`
+	LastErrorAt  *time.Time `json:"lastErrorAt,omitempty"`
+	Configured   int        `json:"configured"` // routes, services, jobs or bindings
+	Active       int        `json:"active"`     // alerts currently mapped onto the UFO
+}
+
+// health tracks an integration's activity and whether it is enabled. A
+// disabled integration ignores webhook deliveries and skips its polls.
+type health struct {
+	mu           sync.Mutex
+	disabled     bool
+	lastActivity time.Time
+	lastError    string
+	lastErrorAt  time.Time
+}
+
+// enabled reports whether the integration is processing events
+func (h *health) enabled() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return !h.disabled
+}
+
+// setEnabled switches the integration on or off, reporting whether that
+// changed anything
+func (h *health) setEnabled(enabled bool) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	changed := h.disabled == enabled
+	h.disabled = !enabled
+	return changed
+}
+
+// observe records a poll or delivery and its error, if any. The last error
+// is kept after later successes so operators can still see it.
+func (h *health) observe(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastActivity = timezone.Now()
+	if err != nil {
+		h.lastError = err.Error()
+		h.lastErrorAt = h.lastActivity
+	}
+}
+
+// status returns the health fields of an integration's status
+func (h *health) status() IntegrationStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	status := IntegrationStatus{Enabled: !h.disabled, LastError: h.lastError}
+	if !h.lastActivity.IsZero() {
+		at := h.lastActivity
+		status.LastActivity = &at
+	}
+	if !h.lastErrorAt.IsZero() {
+		at := h.lastErrorAt
+		status.LastErrorAt = &at
+	}
+	return status
+}
+
+// Registry holds the configured integrations by name
+type Registry struct {
+	mu           sync.RWMutex
+	integrations map[string]Integration
+	engine       *effects.Engine // runs the holds of test lighting
+
+	testsMu sync.Mutex
+	tests   map[string]*heldTest // test lighting waiting to be cleared, by integration
+}
+
+// heldTest is lighting shown by a test, held until it is cleared
+type heldTest struct {
+	cleanup func(ctx context.Context) error
+	stop    chan struct{} // closed to end the hold early
+}
+
+// NewRegistry creates an empty integration registry. Test lighting is held
+// with engine's timers, so it ends when the engine shuts down.
+func NewRegistry(engine *effects.Engine) *Registry {
+	return &Registry{
+		integrations: make(map[string]Integration),
+		engine:       engine,
+		tests:        make(map[string]*heldTest),
+	}
+}
+
+// Register adds an integration under name
+func (r *Registry) Register(name string, integration Integration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.integrations[name] = integration
+}
+
+// Names returns the registered integration names in order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.integrations))
+	for name := range r.integrations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// get returns the named integration
+func (r *Registry) get(name string) (Integration, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	integration, ok := r.integrations[name]
+	if !ok {
+		return nil, fmt.Errorf("integration '%s' is not configured", name)
+	}
+	return integration, nil
+}
+
+// Statuses returns the status of every integration, ordered by name
+func (r *Registry) Statuses() []IntegrationStatus {
+	names := r.Names()
+	statuses := make([]IntegrationStatus, 0, len(names))
+	for _, name := range names {
+		integration, err := r.get(name)
+		if err != nil {
+			continue
+		}
+		status := integration.Status()
+		status.Name = name
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// SetEnabled enables or disables the named integration. Disabling clears
+// the integration's alerts from the UFO; enabling lets the next poll or
+// delivery show them again.
+func (r *Registry) SetEnabled(ctx context.Context, name string, enabled bool) error {
+	integration, err := r.get(name)
+	if err != nil {
+		return err
+	}
+	return integration.SetEnabled(ctx, enabled)
+}
+
+// Test runs a synthetic event through the named integration. Lighting shown
+// by the test is removed after hold in the background; lighting still held
+// from an earlier test of the integration is removed first. A shutdown ends
+// the hold without touching the UFO or the stack.
+func (r *Registry) Test(ctx context.Context, name string, input map[string]interface{}, hold time.Duration) (*TestReport, error) {
+	integration, err := r.get(name)
+	if err != nil {
+		return nil, err
+	}
+	if !integration.Status().Enabled {
+		return nil, fmt.Errorf("integration '%s' is disabled; enable it before testing", name)
+	}
+
+	if held := r.takeTest(name, nil); held != nil {
+		close(held.stop)
+		if err := held.cleanup(ctx); err != nil {
+			slog.WarnContext(ctx, "Clearing integration test lighting failed", "integration", name, "error", err)
+		}
+	}
+
+	report, cleanup := integration.Test(ctx, input)
+	report.Integration = name
+	report.Passed = len(report.Steps) > 0
+	for _, step := range report.Steps {
+		report.Passed = report.Passed && step.OK
+	}
+
+	if cleanup != nil {
+		report.ClearsAfterMs = hold.Milliseconds()
+		held := &heldTest{cleanup: cleanup, stop: make(chan struct{})}
+		r.testsMu.Lock()
+		r.tests[name] = held
+		r.testsMu.Unlock()
+		r.engine.Go(ctx, func(ctx context.Context) {
+			timer := time.NewTimer(hold)
+			defer timer.Stop()
+			select {
+			case <-ctx.Done():
+				return
+			case <-held.stop:
+				return
+			case <-timer.C:
+			}
+			if r.takeTest(name, held) == nil {
+				return
+			}
+			if err := held.cleanup(ctx); err != nil && ctx.Err() == nil {
+				slog.WarnContext(ctx, "Clearing integration test lighting failed", "integration", name, "error", err)
+			}
+		})
+	}
+	return report, nil
+}
+
+// takeTest removes the lighting held for the named integration's test,
+// only if it is want when want is given, and returns it, or nil if there
+// was none
+func (r *Registry) takeTest(name string, want *heldTest) *heldTest {
+	r.testsMu.Lock()
+	defer r.testsMu.Unlock()
+	held := r.tests[name]
+	if held == nil || (want != nil && held != want) {
+		return nil
+	}
+	delete(r.tests, name)
+	return held
+}
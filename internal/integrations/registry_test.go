@@ -0,0 +1,86 @@
+package integrations
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRegistry_EnableDisable(t *testing.T) {
+	display, stateManager, _ := testDisplay(t)
+	grafana, err := NewGrafana(GrafanaConfig{Routes: []Route{
+		{Name: "critical", Match: map[string]string{"severity": "critical"}, Action: Action{Color: "red"}},
+	}}, display, nil)
+	if err != nil {
+		t.Fatalf("failed to create grafana integration: %v", err)
+	}
+	registry := NewRegistry(display.engine)
+	registry.Register("grafana", grafana)
+
+	firing := GrafanaPayload{Status: "firing", Alerts: []GrafanaAlert{
+		{Status: "firing", Fingerprint: "abc", Labels: map[string]string{"alertname": "DiskFull", "severity": "critical"}},
+	}}
+	if _, err := grafana.Handle(context.Background(), firing); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	statuses := registry.Statuses()
+	if len(statuses) != 1 || statuses[0].Name != "grafana" || !statuses[0].Enabled {
+		t.Fatalf("unexpected statuses: %+v", statuses)
+	}
+	if statuses[0].Configured != 1 || statuses[0].Active != 1 || statuses[0].LastActivity == nil {
+		t.Errorf("expected one route, one active alert and recorded activity, got %+v", statuses[0])
+	}
+
+	// Disabling clears the alert and ignores further deliveries
+	if err := registry.SetEnabled(context.Background(), "grafana", false); err != nil {
+		t.Fatalf("disable failed: %v", err)
+	}
+	if depth := stateManager.GetEffectStackDepth(); depth != 0 {
+		t.Errorf("expected alerts cleared on disable, stack depth %d", depth)
+	}
+	result, _ := grafana.Handle(context.Background(), firing)
+	if len(result.Ignored) != 1 || stateManager.GetEffectStackDepth() != 0 {
+		t.Errorf("expected delivery ignored while disabled, got %+v", result)
+	}
+	if status := grafana.Status(); status.Enabled || status.Active != 0 {
+		t.Errorf("unexpected status while disabled: %+v", status)
+	}
+	if _, err := registry.Test(context.Background(), "grafana", nil, time.Millisecond); err == nil {
+		t.Error("expected testing a disabled integration to fail")
+	}
+
+	if err := registry.SetEnabled(context.Background(), "grafana", true); err != nil {
+		t.Fatalf("enable failed: %v", err)
+	}
+	grafana.Handle(context.Background(), firing)
+	if depth := stateManager.GetEffectStackDepth(); depth != 1 {
+		t.Errorf("expected alert shown after enabling, stack depth %d", depth)
+	}
+
+	if err := registry.SetEnabled(context.Background(), "missing", false); err == nil {
+		t.Error("expected error for unknown integration")
+	}
+}
+
+func TestBindings_StatusRecordsErrors(t *testing.T) {
+	display, _, _ := testDisplay(t)
+	bindings := NewBindings(display, nil)
+	if err := bindings.Add(Binding{Name: "down", URL: "http://127.0.0.1:1", Path: "$.value", Rules: []BindingRule{{Action: Action{Color: "red"}}}}); err != nil {
+		t.Fatalf("failed to add binding: %v", err)
+	}
+
+	if err := bindings.PollOnce(context.Background(), "down"); err == nil {
+		t.Fatal("expected poll of unreachable API to fail")
+	}
+	status := bindings.Status()
+	if status.Configured != 1 || status.LastError == "" || status.LastErrorAt == nil {
+		t.Errorf("expected last error recorded, got %+v", status)
+	}
+
+	// Disabled bindings skip their polls entirely
+	bindings.SetEnabled(context.Background(), false)
+	if err := bindings.PollOnce(context.Background(), "down"); err != nil {
+		t.Errorf("expected disabled poll to be skipped, got %v", err)
+	}
+}
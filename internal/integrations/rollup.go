@@ -0,0 +1,157 @@
+package integrations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+)
+
+// Rollup policies
+const (
+	RollupWorst    = "worst"    // the most severe alert wins
+	RollupWeighted = "weighted" // severity multiplied by the source's weight wins
+)
+
+// severityRanks orders alert severities; alerts without one rank lowest
+var severityRanks = map[string]int{
+	"info":     1,
+	"warning":  2,
+	"error":    3,
+	"critical": 4,
+}
+
+// RollupConfig decides which alert is shown when several integrations have
+// alerts active at once, so they do not fight over the UFO. Ties go to the
+// most recent alert.
+type RollupConfig struct {
+	Policy  string             `json:"policy"`            // "worst" or "weighted"
+	Weights map[string]float64 `json:"weights,omitempty"` // source -> weight for "weighted", default 1
+}
+
+// Validate checks the rollup policy and weights
+func (r RollupConfig) Validate() error {
+	switch r.Policy {
+	case RollupWorst, RollupWeighted:
+	default:
+		return fmt.Errorf("rollup: policy must be '%s' or '%s', got %q", RollupWorst, RollupWeighted, r.Policy)
+	}
+	for source, weight := range r.Weights {
+		if weight < 0 {
+			return fmt.Errorf("rollup: weight for '%s' must not be negative", source)
+		}
+	}
+	return nil
+}
+
+// score rates an alert stack entry under the rollup policy
+func (r RollupConfig) score(item state.EffectStackItem) float64 {
+	severity, _ := item.Context["severity"].(string)
+	score := float64(severityRanks[severity])
+	if r.Policy == RollupWeighted {
+		source, _ := item.Context["source"].(string)
+		if weight, ok := r.Weights[source]; ok {
+			score *= weight
+		}
+	}
+	return score
+}
+
+// SetRollup enables the severity rollup across integrations
+func (d *Display) SetRollup(cfg RollupConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.rollup = &cfg
+	d.mu.Unlock()
+	return nil
+}
+
+// applyRollup orders alert entries on the stack by score; d.mu must be held
+func (d *Display) applyRollup() {
+	if d.rollup == nil {
+		return
+	}
+	rollup := *d.rollup
+	d.stateManager.SortEffects(isAlert, func(a, b state.EffectStackItem) bool {
+		return rollup.score(a) < rollup.score(b)
+	})
+}
+
+// isAlert reports whether a stack entry was created by an integration
+func isAlert(item state.EffectStackItem) bool {
+	source, _ := item.Context["source"].(string)
+	_, hasKey := item.Context["alertKey"].(string)
+	return source != "" && hasKey
+}
+
+// SourceAlert describes one active integration alert
+type SourceAlert struct {
+	Source   string     `json:"source"`
+	Key      string     `json:"key"`
+	Name     string     `json:"name"`
+	State    string     `json:"state"`
+	Severity string     `json:"severity,omitempty"`
+	Score    float64    `json:"score"`
+	Effect   string     `json:"effect"`
+	Since    *time.Time `json:"since,omitempty"`
+	Showing  bool       `json:"showing"` // true when the alert is on top of the stack
+}
+
+// SourcesView is the per-source breakdown of active alerts and the rollup
+// outcome
+type SourcesView struct {
+	Policy  string                   `json:"policy"` // "latest" without a rollup
+	Winner  *SourceAlert             `json:"winner,omitempty"`
+	Sources map[string][]SourceAlert `json:"sources"`
+}
+
+// Sources returns every active integration alert grouped by source
+func (d *Display) Sources() SourcesView {
+	d.mu.Lock()
+	rollup := d.rollup
+	d.mu.Unlock()
+
+	view := SourcesView{Policy: "latest", Sources: map[string][]SourceAlert{}}
+	if rollup != nil {
+		view.Policy = rollup.Policy
+	}
+
+	stack := d.stateManager.GetEffectStack()
+	for i := len(stack) - 1; i >= 0; i-- {
+		item := stack[i]
+		if !isAlert(item) {
+			continue
+		}
+		alert := SourceAlert{Effect: item.Name, Showing: i == len(stack)-1}
+		alert.Source, _ = item.Context["source"].(string)
+		alert.Key, _ = item.Context["alertKey"].(string)
+		alert.Name, _ = item.Context["alertName"].(string)
+		alert.State, _ = item.Context["alertState"].(string)
+		alert.Severity, _ = item.Context["severity"].(string)
+		if rollup != nil {
+			alert.Score = rollup.score(item)
+		} else {
+			alert.Score = float64(severityRanks[alert.Severity])
+		}
+		if start := item.StartTime(); !start.IsZero() {
+			start = timezone.In(start)
+			alert.Since = &start
+		}
+
+		// Walking from the top, the first alert is the one the rollup chose
+		if view.Winner == nil {
+			winner := alert
+			view.Winner = &winner
+		}
+		view.Sources[alert.Source] = append(view.Sources[alert.Source], alert)
+	}
+
+	for _, alerts := range view.Sources {
+		sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].Score > alerts[j].Score })
+	}
+	return view
+}
@@ -0,0 +1,92 @@
+package integrations
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDisplay_RollupWorstWins(t *testing.T) {
+	display, stateManager, queries := testDisplay(t)
+	if err := display.SetRollup(RollupConfig{Policy: RollupWorst}); err != nil {
+		t.Fatalf("failed to set rollup: %v", err)
+	}
+	ctx := context.Background()
+
+	display.Show(ctx, "pagerduty", "P1", "Database down", AlertFiring, Action{Color: "red", Severity: "critical"})
+	display.Show(ctx, "jenkins", "app", "app", BuildFailed, Action{Color: "orange", Severity: "error"})
+
+	// The less severe build failure must not cover the critical incident
+	if top := stateManager.GetCurrentEffect(); top == nil || top.Context["source"] != "pagerduty" {
+		t.Fatalf("expected pagerduty alert on top, got %+v", top)
+	}
+	if sent := queries(); len(sent) != 1 {
+		t.Errorf("expected only the critical alert sent to the UFO, got %v", sent)
+	}
+
+	view := display.Sources()
+	if view.Policy != RollupWorst || view.Winner == nil || view.Winner.Key != "P1" || !view.Winner.Showing {
+		t.Errorf("unexpected winner: %+v", view.Winner)
+	}
+	if len(view.Sources["jenkins"]) != 1 || view.Sources["jenkins"][0].Showing {
+		t.Errorf("expected buried jenkins alert, got %+v", view.Sources["jenkins"])
+	}
+
+	// Resolving the incident reveals the build failure
+	display.Deactivate(ctx, "pagerduty", "P1", "Database down")
+	if top := stateManager.GetCurrentEffect(); top == nil || top.Context["source"] != "jenkins" {
+		t.Fatalf("expected jenkins alert on top, got %+v", top)
+	}
+	if sent := queries(); len(sent) != 2 || sent[1] != "top_init=1&top_bg=ffa500&bottom_init=1&bottom_bg=ffa500" {
+		t.Errorf("expected build failure shown, got %v", sent)
+	}
+}
+
+func TestDisplay_RollupWeighted(t *testing.T) {
+	display, stateManager, _ := testDisplay(t)
+	if err := display.SetRollup(RollupConfig{Policy: RollupWeighted, Weights: map[string]float64{"grafana": 3}}); err != nil {
+		t.Fatalf("failed to set rollup: %v", err)
+	}
+	ctx := context.Background()
+
+	display.Show(ctx, "grafana", "disk", "DiskFull", AlertFiring, Action{Color: "yellow", Severity: "warning"})
+	display.Show(ctx, "pagerduty", "P1", "Outage", AlertFiring, Action{Color: "red", Severity: "critical"})
+
+	// warning (2) x 3 outweighs critical (4) x 1
+	if top := stateManager.GetCurrentEffect(); top == nil || top.Context["source"] != "grafana" {
+		t.Fatalf("expected weighted grafana alert on top, got %+v", top)
+	}
+
+	// A user effect played on top stays on top
+	stateManager.PushEffect("rainbow", "effect=rainbow", nil)
+	display.Show(ctx, "pagerduty", "P1", "Outage", AlertAcknowledged, Action{Color: "green", Severity: "info"})
+	if top := stateManager.GetCurrentEffect(); top == nil || top.Name != "rainbow" {
+		t.Errorf("expected rainbow to stay on top, got %+v", top)
+	}
+}
+
+func TestDisplay_NoRollupShowsLatest(t *testing.T) {
+	display, stateManager, _ := testDisplay(t)
+	ctx := context.Background()
+
+	display.Show(ctx, "pagerduty", "P1", "Outage", AlertFiring, Action{Color: "red", Severity: "critical"})
+	display.Show(ctx, "jenkins", "app", "app", BuildFailed, Action{Color: "orange", Severity: "info"})
+
+	if top := stateManager.GetCurrentEffect(); top == nil || top.Context["source"] != "jenkins" {
+		t.Fatalf("expected latest alert on top, got %+v", top)
+	}
+	if view := display.Sources(); view.Policy != "latest" || view.Winner.Source != "jenkins" {
+		t.Errorf("unexpected sources view: %+v", view)
+	}
+}
+
+func TestRollupConfig_Validate(t *testing.T) {
+	if err := (RollupConfig{Policy: "loudest"}).Validate(); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+	if err := (RollupConfig{Policy: RollupWeighted, Weights: map[string]float64{"a": -1}}).Validate(); err == nil {
+		t.Error("expected error for negative weight")
+	}
+	if err := (Action{Color: "red", Severity: "urgent"}).Validate(); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+}
@@ -0,0 +1,66 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TestKeyPrefix marks alert keys created by integration tests, so they never
+// collide with real alerts
+const TestKeyPrefix = "ufo-test-"
+
+// Tester is implemented by integrations that can run a synthetic event
+// through their pipeline. Test returns the report and a function that
+// removes the test lighting, nil if nothing was shown.
+type Tester interface {
+	Test(ctx context.Context, input map[string]interface{}) (*TestReport, func(context.Context) error)
+}
+
+// TestStep is the outcome of one pipeline stage
+type TestStep struct {
+	Name   string `json:"name"` // "mapping", "rules" or "lighting"
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail"`
+}
+
+// TestReport describes a synthetic event's path through an integration
+type TestReport struct {
+	Integration   string     `json:"integration"`
+	Passed        bool       `json:"passed"`
+	Steps         []TestStep `json:"steps"`
+	ClearsAfterMs int64      `json:"clearsAfterMs,omitempty"` // when the test lighting is removed
+}
+
+// step records a pipeline stage and returns ok, so callers can stop at the
+// first failure
+func (r *TestReport) step(name string, ok bool, format string, args ...interface{}) bool {
+	r.Steps = append(r.Steps, TestStep{Name: name, OK: ok, Detail: fmt.Sprintf(format, args...)})
+	return ok
+}
+
+// testKey returns a unique alert key for a test event
+func testKey() string {
+	return fmt.Sprintf("%s%d", TestKeyPrefix, time.Now().UnixNano())
+}
+
+// describeTop reports the pattern shown for a test alert
+func (d *Display) describeTop(source, key string) string {
+	item := d.find(source, key)
+	if item == nil {
+		return "no stack entry"
+	}
+	top := d.stateManager.GetCurrentEffect()
+	if top != nil && ownedBy(*top, source, key) {
+		return fmt.Sprintf("showing '%s': %s", item.Name, item.Pattern)
+	}
+	return fmt.Sprintf("'%s' is on the stack but covered by '%s': %s", item.Name, top.Name, item.Pattern)
+}
+
+// inputString reads an optional string from test input
+func inputString(input map[string]interface{}, key, def string) string {
+	if value, ok := input[key].(string); ok && value != "" {
+		return value
+	}
+	return def
+}
@@ -0,0 +1,150 @@
+package integrations
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistry_TestGrafana(t *testing.T) {
+	display, stateManager, queries := testDisplay(t)
+	grafana, err := NewGrafana(GrafanaConfig{Routes: []Route{
+		{Name: "critical", Match: map[string]string{"severity": "critical"}, Action: Action{Color: "red"}},
+	}}, display, nil)
+	if err != nil {
+		t.Fatalf("failed to create grafana integration: %v", err)
+	}
+	registry := NewRegistry(display.engine)
+	registry.Register("grafana", grafana)
+
+	report, err := registry.Test(context.Background(), "grafana", map[string]interface{}{
+		"labels": map[string]interface{}{"severity": "critical"},
+	}, 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.Passed || len(report.Steps) != 3 || report.ClearsAfterMs != 20 {
+		t.Fatalf("expected passing 3-step report, got %+v", report)
+	}
+	if !strings.Contains(report.Steps[1].Detail, "critical") || !strings.Contains(report.Steps[2].Detail, "showing") {
+		t.Errorf("unexpected step details: %+v", report.Steps)
+	}
+	if top := stateManager.GetCurrentEffect(); top == nil || !strings.HasPrefix(top.Context["alertKey"].(string), TestKeyPrefix) {
+		t.Fatalf("expected test alert on the stack, got %+v", top)
+	}
+
+	// The test lighting is cleared after the hold; the stack empties before
+	// the clearing query reaches the UFO, so wait for both
+	deadline := time.Now().Add(time.Second)
+	for (stateManager.GetEffectStackDepth() != 0 || len(queries()) < 2) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if depth := stateManager.GetEffectStackDepth(); depth != 0 {
+		t.Errorf("expected test alert cleared, stack depth %d", depth)
+	}
+	if len(queries()) != 2 {
+		t.Errorf("expected show and clear queries, got %v", queries())
+	}
+
+	// Labels no route matches fail at the rules step without touching the UFO
+	report, _ = registry.Test(context.Background(), "grafana", nil, time.Millisecond)
+	if report.Passed || len(report.Steps) != 2 || report.Steps[1].OK {
+		t.Errorf("expected rules failure, got %+v", report)
+	}
+
+	if _, err := registry.Test(context.Background(), "missing", nil, time.Millisecond); err == nil {
+		t.Error("expected error for unknown integration")
+	}
+}
+
+func TestRegistry_TestHold(t *testing.T) {
+	display, stateManager, _ := testDisplay(t)
+	grafana, err := NewGrafana(GrafanaConfig{Routes: []Route{
+		{Name: "critical", Match: map[string]string{"severity": "critical"}, Action: Action{Color: "red"}},
+	}}, display, nil)
+	if err != nil {
+		t.Fatalf("failed to create grafana integration: %v", err)
+	}
+	registry := NewRegistry(display.engine)
+	registry.Register("grafana", grafana)
+	input := map[string]interface{}{"labels": map[string]interface{}{"severity": "critical"}}
+
+	first, _ := registry.Test(context.Background(), "grafana", input, time.Hour)
+	if !first.Passed || stateManager.GetEffectStackDepth() != 1 {
+		t.Fatalf("expected the test alert shown, got %+v and depth %d", first, stateManager.GetEffectStackDepth())
+	}
+
+	// Testing again clears the lighting the first test still holds
+	time.Sleep(time.Millisecond)
+	registry.Test(context.Background(), "grafana", input, time.Hour)
+	if depth := stateManager.GetEffectStackDepth(); depth != 1 {
+		t.Errorf("expected only the second test alert, stack depth %d", depth)
+	}
+
+	// Shutting down ends the hold and leaves the stack alone
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := display.engine.Shutdown(ctx); err != nil {
+		t.Fatalf("expected the hold to end on shutdown: %v", err)
+	}
+	if depth := stateManager.GetEffectStackDepth(); depth != 1 {
+		t.Errorf("expected the stack left as it was, stack depth %d", depth)
+	}
+}
+
+func TestJenkins_Test(t *testing.T) {
+	display, stateManager, _ := testDisplay(t)
+	jenkins, err := NewJenkins(JenkinsConfig{Jobs: []JenkinsJob{
+		{Name: "app", URL: "http://jenkins.invalid/job/app", Failed: &Action{Effect: "alarm"}},
+	}}, display, nil)
+	if err != nil {
+		t.Fatalf("failed to create jenkins integration: %v", err)
+	}
+
+	report, cleanup := jenkins.Test(context.Background(), nil)
+	if len(report.Steps) != 3 || !report.Steps[2].OK || cleanup == nil {
+		t.Fatalf("expected failed build to be shown, got %+v", report)
+	}
+	if top := stateManager.GetCurrentEffect(); top == nil || top.Context["alertKey"] != TestKeyPrefix+"app" {
+		t.Fatalf("expected test key on the stack, got %+v", top)
+	}
+	if err := cleanup(context.Background()); err != nil || stateManager.GetEffectStackDepth() != 0 {
+		t.Errorf("expected cleanup to clear the stack, err %v", err)
+	}
+
+	report, cleanup = jenkins.Test(context.Background(), map[string]interface{}{"state": "success"})
+	if report.Steps[len(report.Steps)-1].OK || cleanup != nil {
+		t.Errorf("expected success without an action to fail the rules step, got %+v", report)
+	}
+	report, _ = jenkins.Test(context.Background(), map[string]interface{}{"job": "other"})
+	if report.Steps[0].OK {
+		t.Errorf("expected unknown job to fail mapping, got %+v", report)
+	}
+}
+
+func TestBindings_Test(t *testing.T) {
+	display, stateManager, _ := testDisplay(t)
+	bindings := NewBindings(display, nil)
+	bindings.Add(Binding{Name: "queue", URL: "http://api.invalid", Path: "$.depth", Rules: []BindingRule{
+		{Min: floatPtr(100), Action: Action{Color: "red"}},
+	}})
+
+	report, cleanup := bindings.Test(context.Background(), map[string]interface{}{"value": float64(250)})
+	if len(report.Steps) != 3 || !report.Steps[2].OK {
+		t.Fatalf("expected value to be shown, got %+v", report)
+	}
+	cleanup(context.Background())
+	if depth := stateManager.GetEffectStackDepth(); depth != 0 {
+		t.Errorf("expected cleanup to clear the stack, depth %d", depth)
+	}
+
+	report, _ = bindings.Test(context.Background(), map[string]interface{}{"value": float64(5)})
+	if report.Steps[1].OK {
+		t.Errorf("expected no rule to match, got %+v", report)
+	}
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,538 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/audit"
+	"github.com/starspace46/ufo-mcp-go/internal/color"
+)
+
+// WeatherSource identifies the stack entry created by the weather integration
+const WeatherSource = "weather"
+
+// weatherKey is the ambient key of the current conditions
+const weatherKey = "current"
+
+const (
+	// defaultWeatherPollMs is used when no poll interval is configured
+	defaultWeatherPollMs = 600000
+	// minWeatherPollMs keeps polling within the providers' fair use terms
+	minWeatherPollMs = 60000
+	// defaultWeatherIntensity is the share of full brightness, in percent,
+	// the indication is drawn at so it stays in the background
+	defaultWeatherIntensity = 25
+)
+
+// Weather providers
+const (
+	WeatherOpenWeatherMap = "openweathermap"
+	WeatherMET            = "met"
+)
+
+// weatherURLs are the providers' current conditions endpoints
+var weatherURLs = map[string]string{
+	WeatherOpenWeatherMap: "https://api.openweathermap.org/data/2.5/weather",
+	WeatherMET:            "https://api.met.no/weatherapi/locationforecast/2.0/compact",
+}
+
+// Weather conditions, the providers' codes grouped into what the UFO shows
+const (
+	ConditionClear        = "clear"
+	ConditionClouds       = "clouds"
+	ConditionFog          = "fog"
+	ConditionRain         = "rain"
+	ConditionSnow         = "snow"
+	ConditionThunderstorm = "thunderstorm"
+)
+
+// defaultWeatherConditions is the color of the marks drawn for each
+// condition unless the configuration overrides it; clear skies have none
+var defaultWeatherConditions = map[string]string{
+	ConditionClear:        "",
+	ConditionClouds:       "silver",
+	ConditionFog:          "silver",
+	ConditionRain:         "blue",
+	ConditionSnow:         "white",
+	ConditionThunderstorm: "purple",
+}
+
+// defaultWeatherBands colors the ring from cold blue to hot red
+var defaultWeatherBands = []WeatherBand{
+	{Max: degrees(0), Color: "blue"},
+	{Max: degrees(10), Color: "cyan"},
+	{Max: degrees(20), Color: "green"},
+	{Max: degrees(28), Color: "orange"},
+	{Color: "red"},
+}
+
+// degrees returns a pointer to a band limit
+func degrees(celsius float64) *float64 {
+	return &celsius
+}
+
+// weatherMarks are the bottom ring LEDs showing the condition color
+var weatherMarks = []int{0, 3, 6, 9, 12}
+
+// WeatherConfig configures the weather poller
+type WeatherConfig struct {
+	Provider       string            `json:"provider"`         // "openweathermap" or "met"
+	APIKey         string            `json:"apiKey,omitempty"` // OpenWeatherMap only; default $UFO_WEATHER_API_KEY
+	Latitude       float64           `json:"latitude"`
+	Longitude      float64           `json:"longitude"`
+	PollIntervalMs int               `json:"pollIntervalMs,omitempty"` // default 600000 (10 minutes)
+	Intensity      int               `json:"intensity,omitempty"`      // percent of full brightness, default 25
+	Temperatures   []WeatherBand     `json:"temperatures,omitempty"`   // coldest first; default blue to red
+	Conditions     map[string]string `json:"conditions,omitempty"`     // mark color by condition, "" for none
+	URL            string            `json:"url,omitempty"`            // overrides the provider's endpoint
+	UserAgent      string            `json:"userAgent,omitempty"`      // sent to MET, which requires one
+}
+
+// WeatherBand colors temperatures up to Max degrees Celsius. The last band
+// may leave Max out to cover every warmer temperature.
+type WeatherBand struct {
+	Max   *float64 `json:"max,omitempty"`
+	Color string   `json:"color"`
+}
+
+// WeatherReport is the current weather at the configured location
+type WeatherReport struct {
+	Condition    string  `json:"condition"`   // one of the Condition constants
+	Description  string  `json:"description"` // the provider's wording, e.g. "light rain"
+	TemperatureC float64 `json:"temperatureC"`
+}
+
+// Weather polls a weather provider and shows the current conditions and
+// temperature as a dim bottom ring beneath every effect and alert
+type Weather struct {
+	cfg        WeatherConfig
+	conditions map[string]string // condition -> hex mark color, "" for none
+	bands      []WeatherBand     // colors already parsed to hex
+	display    *Display
+	audit      *audit.Logger
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	last   *WeatherReport
+	health health
+}
+
+// NewWeather creates the weather integration from its configuration
+func NewWeather(cfg WeatherConfig, display *Display, auditLogger *audit.Logger) (*Weather, error) {
+	if _, ok := weatherURLs[cfg.Provider]; !ok {
+		return nil, fmt.Errorf("weather: provider must be '%s' or '%s', got %q", WeatherOpenWeatherMap, WeatherMET, cfg.Provider)
+	}
+	if cfg.Latitude == 0 && cfg.Longitude == 0 {
+		return nil, fmt.Errorf("weather: latitude and longitude are required")
+	}
+	if cfg.Latitude < -90 || cfg.Latitude > 90 || cfg.Longitude < -180 || cfg.Longitude > 180 {
+		return nil, fmt.Errorf("weather: latitude must be between -90 and 90 and longitude between -180 and 180")
+	}
+	if cfg.Provider == WeatherOpenWeatherMap && cfg.APIKey == "" {
+		cfg.APIKey = os.Getenv("UFO_WEATHER_API_KEY")
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("weather: apiKey is required for OpenWeatherMap")
+		}
+	}
+	if cfg.URL == "" {
+		cfg.URL = weatherURLs[cfg.Provider]
+	}
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = "ufo-mcp-server github.com/starspace46/ufo-mcp-go"
+	}
+	switch {
+	case cfg.PollIntervalMs == 0:
+		cfg.PollIntervalMs = defaultWeatherPollMs
+	case cfg.PollIntervalMs < minWeatherPollMs:
+		return nil, fmt.Errorf("weather: pollIntervalMs must be at least %d", minWeatherPollMs)
+	}
+	switch {
+	case cfg.Intensity == 0:
+		cfg.Intensity = defaultWeatherIntensity
+	case cfg.Intensity < 1 || cfg.Intensity > 100:
+		return nil, fmt.Errorf("weather: intensity must be between 1 and 100 percent")
+	}
+
+	bands := cfg.Temperatures
+	if len(bands) == 0 {
+		bands = defaultWeatherBands
+	}
+	parsed := make([]WeatherBand, len(bands))
+	for i, band := range bands {
+		hex, err := color.Parse(band.Color)
+		if err != nil {
+			return nil, fmt.Errorf("weather: temperature band %d: %w", i, err)
+		}
+		if band.Max == nil && i < len(bands)-1 {
+			return nil, fmt.Errorf("weather: temperature band %d: only the last band may leave out max", i)
+		}
+		if i > 0 && band.Max != nil && *band.Max <= *bands[i-1].Max {
+			return nil, fmt.Errorf("weather: temperature bands must be ordered coldest first")
+		}
+		parsed[i] = WeatherBand{Max: band.Max, Color: hex}
+	}
+
+	conditions := make(map[string]string, len(defaultWeatherConditions))
+	for condition, spec := range defaultWeatherConditions {
+		conditions[condition] = spec
+	}
+	for condition, spec := range cfg.Conditions {
+		if _, ok := defaultWeatherConditions[condition]; !ok {
+			return nil, fmt.Errorf("weather: unknown condition %q, expected one of %s", condition, strings.Join(weatherConditionNames(), ", "))
+		}
+		conditions[condition] = spec
+	}
+	for condition, spec := range conditions {
+		if spec == "" {
+			continue
+		}
+		hex, err := color.Parse(spec)
+		if err != nil {
+			return nil, fmt.Errorf("weather: %s: %w", condition, err)
+		}
+		conditions[condition] = hex
+	}
+
+	return &Weather{
+		cfg:        cfg,
+		conditions: conditions,
+		bands:      parsed,
+		display:    display,
+		audit:      auditLogger,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// weatherConditionNames lists the known conditions in order
+func weatherConditionNames() []string {
+	names := make([]string, 0, len(defaultWeatherConditions))
+	for condition := range defaultWeatherConditions {
+		names = append(names, condition)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start polls the weather in the background until ctx is cancelled
+func (w *Weather) Start(ctx context.Context) {
+	runPoller(ctx, "Weather", time.Duration(w.cfg.PollIntervalMs)*time.Millisecond, w.PollOnce)
+}
+
+// PollOnce fetches the current weather and updates the UFO. It does nothing
+// while the integration is disabled.
+func (w *Weather) PollOnce(ctx context.Context) error {
+	if !w.health.enabled() {
+		return nil
+	}
+	report, err := w.fetch(ctx)
+	if err == nil {
+		err = w.Apply(ctx, *report)
+	}
+	w.health.observe(err)
+	return err
+}
+
+// Apply shows a weather report on the bottom ring
+func (w *Weather) Apply(ctx context.Context, report WeatherReport) error {
+	return w.apply(ctx, weatherKey, report)
+}
+
+// apply shows a report under the given ambient key and audits changes of
+// condition or temperature band
+func (w *Weather) apply(ctx context.Context, key string, report WeatherReport) error {
+	pattern := w.Pattern(report)
+	_, err := w.display.ShowAmbient(ctx, WeatherSource, key, "weather", pattern)
+	if key != weatherKey {
+		return err
+	}
+
+	w.mu.Lock()
+	previous := w.last
+	if err == nil {
+		w.last = &report
+	}
+	w.mu.Unlock()
+
+	if err != nil || previous == nil || previous.Condition != report.Condition || w.Pattern(*previous) != pattern {
+		w.record(report, pattern, err)
+	}
+	return err
+}
+
+// Pattern returns the bottom ring query for a report: the temperature's
+// color as the background with the condition's color on every third LED,
+// both dimmed to the configured intensity
+func (w *Weather) Pattern(report WeatherReport) string {
+	background := scaleColor(w.bandColor(report.TemperatureC), w.cfg.Intensity)
+	query := "bottom_init=1"
+	if mark := w.conditions[report.Condition]; mark != "" {
+		mark = scaleColor(mark, w.cfg.Intensity)
+		segments := make([]string, len(weatherMarks))
+		for i, led := range weatherMarks {
+			segments[i] = fmt.Sprintf("%d|1|%s", led, mark)
+		}
+		query += "&bottom=" + strings.Join(segments, "|")
+	}
+	return query + "&bottom_bg=" + background
+}
+
+// bandColor returns the hex color of the band a temperature falls in
+func (w *Weather) bandColor(celsius float64) string {
+	for _, band := range w.bands {
+		if band.Max == nil || celsius <= *band.Max {
+			return band.Color
+		}
+	}
+	return w.bands[len(w.bands)-1].Color
+}
+
+// scaleColor dims a hex color to percent of its brightness
+func scaleColor(hex string, percent int) string {
+	var r, g, b int
+	fmt.Sscanf(hex, "%02x%02x%02x", &r, &g, &b)
+	scale := func(c int) int {
+		return (c*percent + 50) / 100
+	}
+	return fmt.Sprintf("%02x%02x%02x", scale(r), scale(g), scale(b))
+}
+
+// Status reports the integration's health
+func (w *Weather) Status() IntegrationStatus {
+	status := w.health.status()
+	status.Configured = 1
+	if w.display.findAmbient(WeatherSource, weatherKey) != nil {
+		status.Active = 1
+	}
+	return status
+}
+
+// SetEnabled turns polling on or off. Disabling clears the indication from
+// the UFO; the next poll after enabling shows it again.
+func (w *Weather) SetEnabled(ctx context.Context, enabled bool) error {
+	if !w.health.setEnabled(enabled) || enabled {
+		return nil
+	}
+	w.mu.Lock()
+	w.last = nil
+	w.mu.Unlock()
+	_, err := w.display.ClearAmbient(ctx, WeatherSource, weatherKey)
+	return err
+}
+
+// fetch asks the provider for the current weather
+func (w *Weather) fetch(ctx context.Context) (*WeatherReport, error) {
+	query := url.Values{}
+	query.Set("lat", fmt.Sprintf("%.4f", w.cfg.Latitude))
+	query.Set("lon", fmt.Sprintf("%.4f", w.cfg.Longitude))
+	if w.cfg.Provider == WeatherOpenWeatherMap {
+		query.Set("units", "metric")
+		query.Set("appid", w.cfg.APIKey)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.cfg.URL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", w.cfg.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("weather request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("weather provider returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if w.cfg.Provider == WeatherOpenWeatherMap {
+		return parseOpenWeatherMap(body)
+	}
+	return parseMET(body)
+}
+
+// parseOpenWeatherMap reads an OpenWeatherMap current weather response
+func parseOpenWeatherMap(body []byte) (*WeatherReport, error) {
+	var response struct {
+		Weather []struct {
+			ID          int    `json:"id"`
+			Description string `json:"description"`
+		} `json:"weather"`
+		Main *struct {
+			Temp float64 `json:"temp"`
+		} `json:"main"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("parsing OpenWeatherMap response: %w", err)
+	}
+	if len(response.Weather) == 0 || response.Main == nil {
+		return nil, fmt.Errorf("OpenWeatherMap response has no current conditions")
+	}
+
+	// Condition codes: https://openweathermap.org/weather-conditions
+	condition := ConditionClouds
+	switch id := response.Weather[0].ID; {
+	case id >= 200 && id < 300:
+		condition = ConditionThunderstorm
+	case id >= 300 && id < 600:
+		condition = ConditionRain
+	case id >= 600 && id < 700:
+		condition = ConditionSnow
+	case id >= 700 && id < 800:
+		condition = ConditionFog
+	case id == 800:
+		condition = ConditionClear
+	}
+	return &WeatherReport{
+		Condition:    condition,
+		Description:  response.Weather[0].Description,
+		TemperatureC: response.Main.Temp,
+	}, nil
+}
+
+// parseMET reads a MET Norway locationforecast response, using the first
+// timeseries entry as the current weather
+func parseMET(body []byte) (*WeatherReport, error) {
+	type summary struct {
+		Summary struct {
+			SymbolCode string `json:"symbol_code"`
+		} `json:"summary"`
+	}
+	var response struct {
+		Properties struct {
+			Timeseries []struct {
+				Data struct {
+					Instant struct {
+						Details struct {
+							AirTemperature float64 `json:"air_temperature"`
+						} `json:"details"`
+					} `json:"instant"`
+					Next1Hours *summary `json:"next_1_hours"`
+					Next6Hours *summary `json:"next_6_hours"`
+				} `json:"data"`
+			} `json:"timeseries"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("parsing MET response: %w", err)
+	}
+	if len(response.Properties.Timeseries) == 0 {
+		return nil, fmt.Errorf("MET response has no timeseries")
+	}
+	data := response.Properties.Timeseries[0].Data
+	next := data.Next1Hours
+	if next == nil {
+		next = data.Next6Hours
+	}
+	if next == nil {
+		return nil, fmt.Errorf("MET response has no weather symbol")
+	}
+
+	// Symbol codes: https://api.met.no/weatherapi/weathericon/2.0/documentation
+	symbol := next.Summary.SymbolCode
+	base, _, _ := strings.Cut(symbol, "_")
+	condition := ConditionClouds
+	switch {
+	case strings.Contains(base, "thunder"):
+		condition = ConditionThunderstorm
+	case strings.Contains(base, "snow") || strings.Contains(base, "sleet"):
+		condition = ConditionSnow
+	case strings.Contains(base, "rain"):
+		condition = ConditionRain
+	case base == "fog":
+		condition = ConditionFog
+	case base == "clearsky" || base == "fair":
+		condition = ConditionClear
+	}
+	return &WeatherReport{
+		Condition:    condition,
+		Description:  strings.ReplaceAll(symbol, "_", " "),
+		TemperatureC: data.Instant.Details.AirTemperature,
+	}, nil
+}
+
+// record writes a weather change to the audit log
+func (w *Weather) record(report WeatherReport, pattern string, err error) {
+	if w.audit == nil {
+		return
+	}
+
+	result := "ok"
+	data := map[string]interface{}{
+		"condition":    report.Condition,
+		"description":  report.Description,
+		"temperatureC": report.TemperatureC,
+		"pattern":      pattern,
+	}
+	if err != nil {
+		result = "error"
+		data["error"] = err.Error()
+	}
+	w.audit.Record(audit.Entry{
+		Kind:   "integration",
+		Action: WeatherSource,
+		Result: result,
+		Data:   data,
+	})
+}
+
+// Test shows a synthetic weather report and clears it during cleanup. input
+// may set "condition" (default rain) and "temperature" in Celsius (default
+// 12). The test indication uses its own key, so the real one is left alone.
+func (w *Weather) Test(ctx context.Context, input map[string]interface{}) (*TestReport, func(context.Context) error) {
+	report := &TestReport{}
+
+	condition := inputString(input, "condition", ConditionRain)
+	if _, ok := w.conditions[condition]; !ok {
+		report.step("mapping", false, "condition must be one of %s, got %q", strings.Join(weatherConditionNames(), ", "), condition)
+		return report, nil
+	}
+	temperature := 12.0
+	if value, exists := input["temperature"]; exists {
+		celsius, ok := value.(float64)
+		if !ok {
+			report.step("mapping", false, "temperature must be a number of degrees Celsius")
+			return report, nil
+		}
+		temperature = celsius
+	}
+	weather := WeatherReport{Condition: condition, Description: "test", TemperatureC: temperature}
+	report.step("mapping", true, "%s at %.1f°C", condition, temperature)
+
+	marks := "no marks"
+	if mark := w.conditions[condition]; mark != "" {
+		marks = "marks #" + mark
+	}
+	report.step("rules", true, "bottom ring #%s with %s at %d%% intensity", w.bandColor(temperature), marks, w.cfg.Intensity)
+
+	key := TestKeyPrefix + weatherKey
+	err := w.apply(ctx, key, weather)
+	cleanup := func(ctx context.Context) error {
+		_, err := w.display.ClearAmbient(ctx, WeatherSource, key)
+		return err
+	}
+	if err != nil {
+		report.step("lighting", false, "%v", err)
+		return report, cleanup
+	}
+
+	detail := "showing: " + w.Pattern(weather)
+	if top := w.display.stateManager.GetCurrentEffect(); top != nil && !ownsAmbient(*top, WeatherSource, key) && sharesRings(*top, w.Pattern(weather)) {
+		detail = fmt.Sprintf("on the stack but covered by '%s': %s", top.Name, w.Pattern(weather))
+	}
+	report.step("lighting", true, "%s", detail)
+	return report, cleanup
+}
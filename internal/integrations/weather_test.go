@@ -0,0 +1,178 @@
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWeather_Pattern(t *testing.T) {
+	weather, err := NewWeather(WeatherConfig{Provider: WeatherMET, Latitude: 59.91, Longitude: 10.75}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create weather integration: %v", err)
+	}
+
+	rain := weather.Pattern(WeatherReport{Condition: ConditionRain, TemperatureC: 12})
+	if want := "bottom_init=1&bottom=0|1|000040|3|1|000040|6|1|000040|9|1|000040|12|1|000040&bottom_bg=002000"; rain != want {
+		t.Errorf("expected %s, got %s", want, rain)
+	}
+	if clear := weather.Pattern(WeatherReport{Condition: ConditionClear, TemperatureC: -3}); clear != "bottom_init=1&bottom_bg=000040" {
+		t.Errorf("expected dim blue ring without marks, got %s", clear)
+	}
+	if hot := weather.Pattern(WeatherReport{Condition: ConditionClear, TemperatureC: 35}); hot != "bottom_init=1&bottom_bg=400000" {
+		t.Errorf("expected dim red ring above the last band, got %s", hot)
+	}
+}
+
+func TestWeather_PollOpenWeatherMap(t *testing.T) {
+	display, stateManager, queries := testDisplay(t)
+
+	var mu sync.Mutex
+	response := `{"weather":[{"id":500,"main":"Rain","description":"light rain"}],"main":{"temp":12.4}}`
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("appid") != "owm-key" || query.Get("units") != "metric" || query.Get("lat") != "52.5200" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		w.Write([]byte(response))
+	}))
+	defer api.Close()
+
+	weather, err := NewWeather(WeatherConfig{
+		Provider:  WeatherOpenWeatherMap,
+		APIKey:    "owm-key",
+		Latitude:  52.52,
+		Longitude: 13.405,
+		URL:       api.URL,
+	}, display, nil)
+	if err != nil {
+		t.Fatalf("failed to create weather integration: %v", err)
+	}
+
+	if err := weather.PollOnce(context.Background()); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	rain := weather.Pattern(WeatherReport{Condition: ConditionRain, TemperatureC: 12.4})
+	if sent := queries(); len(sent) != 1 || sent[0] != rain {
+		t.Fatalf("expected rain pattern, got %v", sent)
+	}
+	if top := stateManager.GetCurrentEffect(); top == nil || top.Priority() != ambientPriority || isAlert(*top) {
+		t.Fatalf("expected ambient weather entry, got %+v", top)
+	}
+
+	// Unchanged weather does not resend
+	weather.PollOnce(context.Background())
+	if len(queries()) != 1 {
+		t.Errorf("expected no new query for unchanged weather, got %v", queries())
+	}
+
+	// A top ring status zone covers the entry but leaves the bottom ring
+	// showing the weather
+	if _, err := display.Activate(context.Background(), "grafana", "cpu", "CPU", Action{Color: "yellow", Zone: "top"}); err != nil {
+		t.Fatalf("activate failed: %v", err)
+	}
+	if sent := queries(); len(sent) != 3 || sent[1] != "top_init=1&top_bg=ffff00" || sent[2] != rain {
+		t.Fatalf("expected status zone and weather redraw, got %v", sent)
+	}
+	if top := stateManager.GetCurrentEffect(); top == nil || top.Name != "CPU" {
+		t.Fatalf("expected alert on top, got %+v", top)
+	}
+
+	// New conditions are drawn beside the status zone
+	mu.Lock()
+	response = `{"weather":[{"id":800,"description":"clear sky"}],"main":{"temp":22}}`
+	mu.Unlock()
+	weather.PollOnce(context.Background())
+	clear := weather.Pattern(WeatherReport{Condition: ConditionClear, TemperatureC: 22})
+	if sent := queries(); len(sent) != 4 || sent[3] != clear {
+		t.Fatalf("expected clear sky overlay, got %v", sent)
+	}
+
+	// An alert on both rings hides the weather until it resolves
+	display.Activate(context.Background(), "grafana", "down", "Down", Action{Color: "red"})
+	mu.Lock()
+	response = `{"weather":[{"id":601,"description":"snow"}],"main":{"temp":-2}}`
+	mu.Unlock()
+	weather.PollOnce(context.Background())
+	if sent := queries(); len(sent) != 5 {
+		t.Fatalf("expected covered weather not to be sent, got %v", sent)
+	}
+	display.Deactivate(context.Background(), "grafana", "down", "Down")
+	snow := weather.Pattern(WeatherReport{Condition: ConditionSnow, TemperatureC: -2})
+	if sent := queries(); len(sent) != 7 || sent[5] != "top_init=1&top_bg=ffff00" || sent[6] != snow {
+		t.Fatalf("expected status zone and snow after the alert resolved, got %v", sent)
+	}
+
+	// Disabling clears the bottom ring beside the status zone
+	if err := weather.SetEnabled(context.Background(), false); err != nil {
+		t.Fatalf("disable failed: %v", err)
+	}
+	if sent := queries(); len(sent) != 8 || sent[7] != "bottom_init=1" {
+		t.Fatalf("expected bottom ring cleared, got %v", sent)
+	}
+	if status := weather.Status(); status.Enabled || status.Active != 0 {
+		t.Errorf("expected disabled with nothing shown, got %+v", status)
+	}
+}
+
+func TestParseMET(t *testing.T) {
+	body := `{"properties":{"timeseries":[{"time":"2026-10-16T10:00:00Z","data":{
+		"instant":{"details":{"air_temperature":7.5}},
+		"next_1_hours":{"summary":{"symbol_code":"lightrainshowers_day"}}}}]}}`
+	report, err := parseMET([]byte(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Condition != ConditionRain || report.TemperatureC != 7.5 || report.Description != "lightrainshowers day" {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	cases := map[string]string{
+		"clearsky_night":         ConditionClear,
+		"fair_day":               ConditionClear,
+		"partlycloudy_day":       ConditionClouds,
+		"fog":                    ConditionFog,
+		"heavysleet":             ConditionSnow,
+		"rainandthunder":         ConditionThunderstorm,
+		"lightssnowshowers_day":  ConditionSnow,
+		"heavyrainshowers_night": ConditionRain,
+	}
+	for symbol, want := range cases {
+		body := `{"properties":{"timeseries":[{"data":{"next_6_hours":{"summary":{"symbol_code":"` + symbol + `"}}}}]}}`
+		report, err := parseMET([]byte(body))
+		if err != nil || report.Condition != want {
+			t.Errorf("%s: expected %s, got %+v (%v)", symbol, want, report, err)
+		}
+	}
+
+	if _, err := parseMET([]byte(`{"properties":{"timeseries":[]}}`)); err == nil {
+		t.Error("expected error for empty timeseries")
+	}
+}
+
+func TestNewWeather_Validation(t *testing.T) {
+	t.Setenv("UFO_WEATHER_API_KEY", "")
+	cases := map[string]WeatherConfig{
+		"provider":    {Provider: "accuweather", Latitude: 1, Longitude: 1},
+		"latitude":    {Provider: WeatherMET},
+		"range":       {Provider: WeatherMET, Latitude: 91, Longitude: 1},
+		"apiKey":      {Provider: WeatherOpenWeatherMap, Latitude: 1, Longitude: 1},
+		"interval":    {Provider: WeatherMET, Latitude: 1, Longitude: 1, PollIntervalMs: 1000},
+		"intensity":   {Provider: WeatherMET, Latitude: 1, Longitude: 1, Intensity: 150},
+		"condition":   {Provider: WeatherMET, Latitude: 1, Longitude: 1, Conditions: map[string]string{"hail": "white"}},
+		"color":       {Provider: WeatherMET, Latitude: 1, Longitude: 1, Conditions: map[string]string{"rain": "nope"}},
+		"band order":  {Provider: WeatherMET, Latitude: 1, Longitude: 1, Temperatures: []WeatherBand{{Max: degrees(10), Color: "blue"}, {Max: degrees(5), Color: "red"}}},
+		"open middle": {Provider: WeatherMET, Latitude: 1, Longitude: 1, Temperatures: []WeatherBand{{Color: "blue"}, {Color: "red"}}},
+	}
+	for name, cfg := range cases {
+		if _, err := NewWeather(cfg, nil, nil); err == nil || !strings.HasPrefix(err.Error(), "weather: ") {
+			t.Errorf("%s: expected configuration error, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// requestIDKey is the context key for the request correlation ID
+type requestIDKey struct{}
+
+// RequestIDAttr is the attribute name request correlation IDs are logged under
+const RequestIDAttr = "requestId"
+
+// New creates a logger writing to w at the given level ("debug", "info",
+// "warn" or "error") in the given format ("text" or "json"). Every record
+// logged with a context carrying a request ID includes it.
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: use debug, info, warn or error", level)
+	}
+
+	options := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "text", "":
+		handler = slog.NewTextHandler(w, options)
+	case "json":
+		handler = slog.NewJSONHandler(w, options)
+	default:
+		return nil, fmt.Errorf("invalid log format %q: use text or json", format)
+	}
+	return slog.New(contextHandler{handler}), nil
+}
+
+// contextHandler adds the request ID from the record's context
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String(RequestIDAttr, id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// NewRequestID returns a random correlation ID for a tool invocation
+func NewRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithRequestID returns a context carrying a request correlation ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request correlation ID carried by ctx, or ""
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Fatal logs msg at error level and exits
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
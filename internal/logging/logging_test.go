@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNew_JSONWithRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "debug", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithRequestID(context.Background(), "abc123")
+	logger.With("component", "test").DebugContext(ctx, "UFO request", "query", "dim=10")
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected a JSON record, got %q: %v", buf.String(), err)
+	}
+	if record[RequestIDAttr] != "abc123" || record["query"] != "dim=10" || record["component"] != "test" {
+		t.Errorf("unexpected record %v", record)
+	}
+}
+
+func TestNew_Level(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "WARN", "text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("hidden")
+	logger.Warn("shown")
+	if strings.Contains(buf.String(), "hidden") || !strings.Contains(buf.String(), "shown") {
+		t.Errorf("expected only warnings, got %q", buf.String())
+	}
+	if strings.Contains(buf.String(), RequestIDAttr) {
+		t.Errorf("expected no request ID without one in the context, got %q", buf.String())
+	}
+
+	if _, err := New(&buf, "loud", "text"); err == nil {
+		t.Error("expected an invalid level to be rejected")
+	}
+	if _, err := New(&buf, "info", "xml"); err == nil {
+		t.Error("expected an invalid format to be rejected")
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	if RequestID(context.Background()) != "" {
+		t.Error("expected no request ID in a bare context")
+	}
+	a, b := NewRequestID(), NewRequestID()
+	if len(a) != 16 || a == b {
+		t.Errorf("expected distinct 16 character IDs, got %q and %q", a, b)
+	}
+}
@@ -0,0 +1,334 @@
+package macros
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MaxSteps caps the number of tool calls in one macro
+const MaxSteps = 50
+
+// Step error policies: what runMacro does when a step fails
+const (
+	OnErrorAbort    = "abort"    // stop the macro (the default)
+	OnErrorContinue = "continue" // report the failure and run the next step
+)
+
+// Macro is a named, ordered list of tool calls that runMacro replays on the
+// server, such as the handful of calls that set up a demo
+type Macro struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Params      []Param `json:"params,omitempty"`
+	Steps       []Step  `json:"steps"`
+}
+
+// Param is a named placeholder in a macro's step arguments, written {name},
+// that is filled in when the macro is run
+type Param struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Default     interface{} `json:"default,omitempty"` // used when runMacro gives no value; required when nil
+}
+
+// Step is one tool call of a macro
+type Step struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	OnError   string                 `json:"onError,omitempty"` // OnErrorAbort or OnErrorContinue; empty means abort
+}
+
+// placeholderPattern matches {name} placeholders in argument strings
+var placeholderPattern = regexp.MustCompile(`\{([A-Za-z][A-Za-z0-9_]*)\}`)
+
+// paramNamePattern is the allowed form of a parameter name
+var paramNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// Store persists macros in a JSON file
+type Store struct {
+	mu     sync.RWMutex
+	macros map[string]*Macro // keyed by lowercase name
+	file   string
+}
+
+// NewStore creates a macro store saved in filePath
+func NewStore(filePath string) *Store {
+	return &Store{
+		macros: make(map[string]*Macro),
+		file:   filePath,
+	}
+}
+
+// Load reads the macros file. A missing file is an empty store.
+func (s *Store) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.macros = make(map[string]*Macro)
+			return nil
+		}
+		return fmt.Errorf("reading macros file: %w", err)
+	}
+
+	var macros []*Macro
+	if err := json.Unmarshal(data, &macros); err != nil {
+		return fmt.Errorf("parsing macros JSON: %w", err)
+	}
+
+	s.macros = make(map[string]*Macro)
+	for _, macro := range macros {
+		normalized, err := normalize(*macro)
+		if err != nil {
+			return fmt.Errorf("invalid macro '%s': %w", macro.Name, err)
+		}
+		s.macros[strings.ToLower(normalized.Name)] = &normalized
+	}
+	return nil
+}
+
+// saveUnsafe writes the macros file; the caller holds the lock
+func (s *Store) saveUnsafe() error {
+	data, err := json.MarshalIndent(s.listUnsafe(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling macros: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.file), 0755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	return os.WriteFile(s.file, data, 0644)
+}
+
+// List returns copies of all macros ordered by name
+func (s *Store) List() []Macro {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.listUnsafe()
+}
+
+// listUnsafe lists macros without acquiring the lock
+func (s *Store) listUnsafe() []Macro {
+	macros := make([]Macro, 0, len(s.macros))
+	for _, macro := range s.macros {
+		macros = append(macros, clone(macro))
+	}
+	sort.Slice(macros, func(i, j int) bool {
+		return strings.ToLower(macros[i].Name) < strings.ToLower(macros[j].Name)
+	})
+	return macros
+}
+
+// Get returns the macro with the given name, ignoring case
+func (s *Store) Get(name string) (Macro, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	macro, exists := s.macros[strings.ToLower(strings.TrimSpace(name))]
+	if !exists {
+		return Macro{}, false
+	}
+	return clone(macro), true
+}
+
+// Save stores macro, replacing one with the same name. It returns the macro
+// as saved and whether it replaced an existing one.
+func (s *Store) Save(macro Macro) (Macro, bool, error) {
+	normalized, err := normalize(macro)
+	if err != nil {
+		return Macro{}, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(normalized.Name)
+	previous, replaced := s.macros[key]
+	s.macros[key] = &normalized
+	if err := s.saveUnsafe(); err != nil {
+		if replaced {
+			s.macros[key] = previous
+		} else {
+			delete(s.macros, key)
+		}
+		return Macro{}, false, err
+	}
+	return clone(&normalized), replaced, nil
+}
+
+// Delete removes the macro with the given name, ignoring case
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(strings.TrimSpace(name))
+	previous, exists := s.macros[key]
+	if !exists {
+		return fmt.Errorf("no macro named '%s'", name)
+	}
+	delete(s.macros, key)
+	if err := s.saveUnsafe(); err != nil {
+		s.macros[key] = previous
+		return err
+	}
+	return nil
+}
+
+// normalize validates a macro and trims its names
+func normalize(macro Macro) (Macro, error) {
+	macro.Name = strings.TrimSpace(macro.Name)
+	macro.Description = strings.TrimSpace(macro.Description)
+	if macro.Name == "" {
+		return Macro{}, fmt.Errorf("macro name cannot be empty")
+	}
+	if len(macro.Name) > 64 {
+		return Macro{}, fmt.Errorf("macro name must be at most 64 characters")
+	}
+	if len(macro.Steps) == 0 || len(macro.Steps) > MaxSteps {
+		return Macro{}, fmt.Errorf("a macro needs 1 to %d steps, got %d", MaxSteps, len(macro.Steps))
+	}
+	declared := map[string]bool{}
+	for _, param := range macro.Params {
+		if !paramNamePattern.MatchString(param.Name) {
+			return Macro{}, fmt.Errorf("parameter name '%s' must start with a letter and contain only letters, numbers and underscores", param.Name)
+		}
+		if declared[param.Name] {
+			return Macro{}, fmt.Errorf("parameter '%s' is declared twice", param.Name)
+		}
+		declared[param.Name] = true
+	}
+	for i := range macro.Steps {
+		step := &macro.Steps[i]
+		step.Tool = strings.TrimSpace(step.Tool)
+		if step.Tool == "" {
+			return Macro{}, fmt.Errorf("step %d: tool cannot be empty", i+1)
+		}
+		switch step.OnError {
+		case "", OnErrorAbort, OnErrorContinue:
+		default:
+			return Macro{}, fmt.Errorf("step %d: onError must be '%s' or '%s'", i+1, OnErrorAbort, OnErrorContinue)
+		}
+		for _, name := range placeholders(step.Arguments) {
+			if !declared[name] {
+				return Macro{}, fmt.Errorf("step %d: placeholder {%s} is not a declared parameter", i+1, name)
+			}
+		}
+	}
+	return macro, nil
+}
+
+// Render returns the macro's steps with their placeholders replaced by
+// values, falling back to each parameter's default. An argument that is a
+// placeholder alone takes the value as given, keeping numbers and booleans;
+// a placeholder within other text is replaced by the value's text. Unknown
+// values and required parameters left without a value are errors.
+func (m Macro) Render(values map[string]interface{}) ([]Step, error) {
+	resolved := make(map[string]interface{}, len(m.Params))
+	for _, param := range m.Params {
+		resolved[param.Name] = param.Default
+	}
+
+	var unknown []string
+	for name, value := range values {
+		if _, declared := resolved[name]; !declared {
+			unknown = append(unknown, name)
+			continue
+		}
+		resolved[name] = value
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("macro '%s' has no parameter named %s", m.Name, strings.Join(unknown, ", "))
+	}
+
+	var missing []string
+	for _, param := range m.Params {
+		if resolved[param.Name] == nil {
+			missing = append(missing, param.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("macro '%s' needs a value for %s", m.Name, strings.Join(missing, ", "))
+	}
+
+	steps := make([]Step, len(m.Steps))
+	for i, step := range m.Steps {
+		steps[i] = step
+		if step.Arguments != nil {
+			steps[i].Arguments = substitute(step.Arguments, resolved).(map[string]interface{})
+		}
+	}
+	return steps, nil
+}
+
+// substitute returns a copy of an argument value with placeholders replaced
+func substitute(value interface{}, resolved map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = substitute(item, resolved)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = substitute(item, resolved)
+		}
+		return copied
+	case string:
+		if match := placeholderPattern.FindStringSubmatch(v); match != nil && match[0] == v {
+			return resolved[match[1]]
+		}
+		return placeholderPattern.ReplaceAllStringFunc(v, func(placeholder string) string {
+			return fmt.Sprint(resolved[placeholder[1:len(placeholder)-1]])
+		})
+	}
+	return value
+}
+
+// placeholders returns the names of the placeholders used anywhere in an
+// argument value, sorted and without duplicates
+func placeholders(value interface{}) []string {
+	seen := map[string]bool{}
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case string:
+			for _, match := range placeholderPattern.FindAllStringSubmatch(v, -1) {
+				seen[match[1]] = true
+			}
+		}
+	}
+	walk(value)
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// clone copies a macro so callers cannot change the stored steps. Argument
+// maps are shared; nothing modifies them after a macro is saved.
+func clone(macro *Macro) Macro {
+	copied := *macro
+	copied.Params = append([]Param(nil), macro.Params...)
+	copied.Steps = append([]Step(nil), macro.Steps...)
+	return copied
+}
@@ -0,0 +1,127 @@
+package macros
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStore_SaveAndLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "macros.json")
+	store := NewStore(file)
+	if err := store.Load(); err != nil {
+		t.Fatalf("expected a missing file to load as empty, got %v", err)
+	}
+
+	macro := Macro{
+		Name: " demo setup ",
+		Steps: []Step{
+			{Tool: "setBrightness", Arguments: map[string]interface{}{"level": float64(200)}},
+			{Tool: " playEffect ", Arguments: map[string]interface{}{"name": "rainbow"}},
+		},
+	}
+	saved, replaced, err := store.Save(macro)
+	if err != nil || replaced {
+		t.Fatalf("failed to save macro: %v (replaced %v)", err, replaced)
+	}
+	if saved.Name != "demo setup" || saved.Steps[1].Tool != "playEffect" {
+		t.Errorf("expected names to be trimmed, got %+v", saved)
+	}
+	if _, replaced, _ := store.Save(Macro{Name: "Demo Setup", Steps: macro.Steps}); !replaced {
+		t.Error("expected a save under the same name in other case to replace the macro")
+	}
+
+	reloaded := NewStore(file)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("failed to reload: %v", err)
+	}
+	got, ok := reloaded.Get("DEMO SETUP")
+	if !ok || got.Name != "Demo Setup" || len(got.Steps) != 2 || got.Steps[0].Arguments["level"] != float64(200) {
+		t.Errorf("unexpected macro after reload: %+v (%v)", got, ok)
+	}
+
+	if err := reloaded.Delete("demo setup"); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if err := reloaded.Delete("demo setup"); err == nil {
+		t.Error("expected deleting a missing macro to fail")
+	}
+	if len(reloaded.List()) != 0 {
+		t.Errorf("expected no macros, got %v", reloaded.List())
+	}
+}
+
+func TestStore_SaveValidation(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "macros.json"))
+	cases := map[string]Macro{
+		"empty name": {Steps: []Step{{Tool: "getLedState"}}},
+		"no steps":   {Name: "none"},
+		"empty tool": {Name: "blank", Steps: []Step{{Tool: " "}}},
+		"too many":   {Name: "many", Steps: make([]Step, MaxSteps+1)},
+	}
+	for name, macro := range cases {
+		if _, _, err := store.Save(macro); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMacro_Render(t *testing.T) {
+	macro := Macro{
+		Name: "deployStatus",
+		Params: []Param{
+			{Name: "env"},
+			{Name: "color", Default: "green"},
+			{Name: "level", Default: float64(255)},
+		},
+		Steps: []Step{
+			{Tool: "setBrightness", Arguments: map[string]interface{}{"level": "{level}"}},
+			{Tool: "configureLighting", Arguments: map[string]interface{}{
+				"top": map[string]interface{}{"segments": []interface{}{"0|15|{color}"}},
+			}},
+			{Tool: "raiseAlert", Arguments: map[string]interface{}{"message": "Deployed to {env} ({color})"}},
+		},
+	}
+	if _, err := normalize(macro); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	steps, err := macro.Render(map[string]interface{}{"env": "prod", "level": float64(120)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if steps[0].Arguments["level"] != float64(120) {
+		t.Errorf("expected a lone placeholder to keep the number, got %#v", steps[0].Arguments["level"])
+	}
+	segments := steps[1].Arguments["top"].(map[string]interface{})["segments"].([]interface{})
+	if segments[0] != "0|15|green" {
+		t.Errorf("expected the default color, got %v", segments[0])
+	}
+	if steps[2].Arguments["message"] != "Deployed to prod (green)" {
+		t.Errorf("unexpected message: %v", steps[2].Arguments["message"])
+	}
+	if macro.Steps[2].Arguments["message"] != "Deployed to {env} ({color})" {
+		t.Error("expected rendering to leave the macro unchanged")
+	}
+
+	if _, err := macro.Render(nil); err == nil {
+		t.Error("expected a missing required parameter to fail")
+	}
+	if _, err := macro.Render(map[string]interface{}{"env": "prod", "region": "eu"}); err == nil {
+		t.Error("expected an unknown parameter to fail")
+	}
+}
+
+func TestStore_SaveValidatesParams(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "macros.json"))
+	cases := map[string]Macro{
+		"undeclared": {Name: "a", Steps: []Step{{Tool: "playEffect", Arguments: map[string]interface{}{"name": "{effect}"}}}},
+		"bad name":   {Name: "b", Params: []Param{{Name: "1st"}}, Steps: []Step{{Tool: "stopAllEffects"}}},
+		"twice":      {Name: "c", Params: []Param{{Name: "x"}, {Name: "x"}}, Steps: []Step{{Tool: "stopAllEffects"}}},
+		"on error":   {Name: "d", Steps: []Step{{Tool: "stopAllEffects", OnError: "retry"}}},
+	}
+	for name, macro := range cases {
+		if _, _, err := store.Save(macro); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
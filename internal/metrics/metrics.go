@@ -0,0 +1,187 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/starspace46/ufo-mcp-go/internal/version"
+)
+
+// subscriberID is the broadcaster subscription used to count events
+const subscriberID = "metrics"
+
+// Collector exports server and device health in the Prometheus text format
+type Collector struct {
+	client       *device.Client
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+
+	mu     sync.Mutex
+	plays  map[string]uint64 // effect name -> times started
+	events map[string]uint64 // event type -> times seen
+}
+
+// NewCollector creates a new metrics collector
+func NewCollector(client *device.Client, broadcaster *events.Broadcaster, stateManager *state.Manager) *Collector {
+	return &Collector{
+		client:       client,
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		plays:        make(map[string]uint64),
+		events:       make(map[string]uint64),
+	}
+}
+
+// Start counts published events in the background until ctx is cancelled
+func (c *Collector) Start(ctx context.Context) {
+	sub := c.broadcaster.Subscribe(subscriberID)
+
+	go func() {
+		defer c.broadcaster.Unsubscribe(subscriberID)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				c.observe(event)
+			}
+		}
+	}()
+}
+
+// observe counts a single event
+func (c *Collector) observe(event events.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.events[event.Type]++
+	if event.Type == events.EventEffectStarted {
+		if name, ok := event.Data["effect"].(string); ok && name != "" {
+			c.plays[name]++
+		}
+	}
+}
+
+// ServeHTTP serves the metrics for Prometheus to scrape
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+
+	writeHeader(&b, "ufo_build_info", "gauge", "Build information for the UFO MCP server.")
+	fmt.Fprintf(&b, "ufo_build_info{version=%s,commit=%s} 1\n", quote(version.Version), quote(version.GitCommit))
+
+	stats := c.client.Stats()
+	writeHeader(&b, "ufo_device_requests_total", "counter", "Requests sent to the UFO device by result.")
+	fmt.Fprintf(&b, "ufo_device_requests_total{result=\"success\"} %d\n", stats.Requests-stats.Errors)
+	fmt.Fprintf(&b, "ufo_device_requests_total{result=\"error\"} %d\n", stats.Errors)
+
+	writeHeader(&b, "ufo_device_retries_total", "counter", "Requests to the UFO device that retried a failed request.")
+	fmt.Fprintf(&b, "ufo_device_retries_total %d\n", stats.Retries)
+
+	online := 0
+	if c.client.Online() {
+		online = 1
+	}
+	writeHeader(&b, "ufo_device_online", "gauge", "Whether the UFO device is considered reachable (0 while the circuit breaker is open).")
+	fmt.Fprintf(&b, "ufo_device_online %d\n", online)
+
+	concurrency := c.client.Concurrency()
+	writeHeader(&b, "ufo_device_concurrency_limit", "gauge", "Requests allowed in flight to the UFO device at once.")
+	fmt.Fprintf(&b, "ufo_device_concurrency_limit %d\n", concurrency.Max)
+	writeHeader(&b, "ufo_device_requests_in_flight", "gauge", "Requests currently in flight to the UFO device.")
+	fmt.Fprintf(&b, "ufo_device_requests_in_flight %d\n", concurrency.InFlight)
+	writeHeader(&b, "ufo_device_requests_waiting", "gauge", "Requests waiting for a free slot before being sent to the UFO device.")
+	fmt.Fprintf(&b, "ufo_device_requests_waiting %d\n", concurrency.Waiting)
+
+	queue := c.client.WriteQueue()
+	writeHeader(&b, "ufo_device_write_queue_depth", "gauge", "Writes waiting in the rate-limited queue to the UFO device.")
+	fmt.Fprintf(&b, "ufo_device_write_queue_depth %d\n", queue.Depth)
+	writeHeader(&b, "ufo_device_writes_coalesced_total", "counter", "Writes merged into a later write before being sent to the UFO device.")
+	fmt.Fprintf(&b, "ufo_device_writes_coalesced_total %d\n", queue.Coalesced)
+
+	writeHeader(&b, "ufo_device_request_duration_seconds", "histogram", "Latency of requests to the UFO device.")
+	for i, bound := range device.LatencyBuckets {
+		fmt.Fprintf(&b, "ufo_device_request_duration_seconds_bucket{le=\"%g\"} %d\n", bound, stats.LatencyCounts[i])
+	}
+	fmt.Fprintf(&b, "ufo_device_request_duration_seconds_bucket{le=\"+Inf\"} %d\n", stats.Requests)
+	fmt.Fprintf(&b, "ufo_device_request_duration_seconds_sum %g\n", stats.LatencySum)
+	fmt.Fprintf(&b, "ufo_device_request_duration_seconds_count %d\n", stats.Requests)
+
+	c.mu.Lock()
+	plays := sortedCounts(c.plays)
+	eventCounts := sortedCounts(c.events)
+	c.mu.Unlock()
+
+	writeHeader(&b, "ufo_effect_plays_total", "counter", "Effects started, by effect name.")
+	for _, entry := range plays {
+		fmt.Fprintf(&b, "ufo_effect_plays_total{effect=%s} %d\n", quote(entry.name), entry.count)
+	}
+
+	writeHeader(&b, "ufo_events_total", "counter", "Events published, by type.")
+	for _, entry := range eventCounts {
+		fmt.Fprintf(&b, "ufo_events_total{type=%s} %d\n", quote(entry.name), entry.count)
+	}
+
+	writeHeader(&b, "ufo_effect_stack_depth", "gauge", "Number of effects on the effect stack.")
+	fmt.Fprintf(&b, "ufo_effect_stack_depth %d\n", c.stateManager.GetEffectStackDepth())
+
+	writeHeader(&b, "ufo_event_subscribers", "gauge", "Active event broadcaster subscribers.")
+	fmt.Fprintf(&b, "ufo_event_subscribers %d\n", c.broadcaster.GetSubscriberCount())
+
+	publishDrops, subscriberDrops := c.broadcaster.DroppedEvents()
+	writeHeader(&b, "ufo_events_dropped_total", "counter", "Events dropped because a buffer was full.")
+	fmt.Fprintf(&b, "ufo_events_dropped_total{stage=\"publish\"} %d\n", publishDrops)
+	fmt.Fprintf(&b, "ufo_events_dropped_total{stage=\"subscriber\"} %d\n", subscriberDrops)
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// namedCount is a labelled counter value
+type namedCount struct {
+	name  string
+	count uint64
+}
+
+// sortedCounts returns counts ordered by name for stable output
+func sortedCounts(counts map[string]uint64) []namedCount {
+	entries := make([]namedCount, 0, len(counts))
+	for name, count := range counts {
+		entries = append(entries, namedCount{name, count})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+	return entries
+}
+
+// writeHeader writes the HELP and TYPE lines for a metric
+func writeHeader(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// quote formats a label value with Prometheus escaping
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	return `"` + value + `"`
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+func TestCollector_ServeHTTP(t *testing.T) {
+	ufo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery == "fail=1" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("OK"))
+	}))
+	defer ufo.Close()
+	t.Setenv("UFO_IP", ufo.URL[7:])
+
+	client := device.NewClient()
+	client.SetRetryPolicy(device.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+
+	collector := NewCollector(client, broadcaster, stateManager)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	collector.Start(ctx)
+
+	client.SendRawQuery(context.Background(), "dim=100")
+	client.SendRawQuery(context.Background(), "fail=1")
+	stateManager.PushEffect("rainbow", "effect=rainbow", nil)
+	broadcaster.PublishEffectStarted(`rain"bow`, 0)
+	time.Sleep(50 * time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		`ufo_device_requests_total{result="success"} 1`,
+		`ufo_device_requests_total{result="error"} 2`,
+		`ufo_device_retries_total 1`,
+		`ufo_device_online 1`,
+		`ufo_device_concurrency_limit 1`,
+		`ufo_device_requests_in_flight 0`,
+		`ufo_device_write_queue_depth 0`,
+		`ufo_device_request_duration_seconds_count 3`,
+		`ufo_device_request_duration_seconds_bucket{le="+Inf"} 3`,
+		`ufo_effect_plays_total{effect="rain\"bow"} 1`,
+		`ufo_effect_stack_depth 1`,
+		`ufo_event_subscribers 1`,
+		`ufo_events_dropped_total{stage="publish"} 0`,
+		"# TYPE ufo_device_request_duration_seconds histogram",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package palettes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/starspace46/ufo-mcp-go/internal/color"
+)
+
+// MaxColors is the most colors a palette holds, one per LED of a ring
+const MaxColors = 15
+
+// Palette is a named set of colors, such as a brand's colors, that lighting
+// tools can refer to instead of repeating hex values
+type Palette struct {
+	Name        string   `json:"name"`
+	Colors      []string `json:"colors"` // lowercase 6-character hex
+	Description string   `json:"description,omitempty"`
+}
+
+// Store persists palettes in a JSON file
+type Store struct {
+	mu       sync.RWMutex
+	palettes map[string]*Palette // keyed by lowercase name
+	file     string
+}
+
+// NewStore creates a palette store saved in filePath
+func NewStore(filePath string) *Store {
+	return &Store{
+		palettes: make(map[string]*Palette),
+		file:     filePath,
+	}
+}
+
+// Load reads the palettes file. A missing file is an empty store.
+func (s *Store) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.palettes = make(map[string]*Palette)
+			return nil
+		}
+		return fmt.Errorf("reading palettes file: %w", err)
+	}
+
+	var palettes []*Palette
+	if err := json.Unmarshal(data, &palettes); err != nil {
+		return fmt.Errorf("parsing palettes JSON: %w", err)
+	}
+
+	s.palettes = make(map[string]*Palette)
+	for _, palette := range palettes {
+		normalized, err := normalize(*palette)
+		if err != nil {
+			return fmt.Errorf("invalid palette '%s': %w", palette.Name, err)
+		}
+		s.palettes[strings.ToLower(normalized.Name)] = &normalized
+	}
+	return nil
+}
+
+// saveUnsafe writes the palettes file; the caller holds the lock
+func (s *Store) saveUnsafe() error {
+	data, err := json.MarshalIndent(s.listUnsafe(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling palettes: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.file), 0755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	return os.WriteFile(s.file, data, 0644)
+}
+
+// List returns copies of all palettes ordered by name
+func (s *Store) List() []Palette {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.listUnsafe()
+}
+
+// listUnsafe lists palettes without acquiring the lock
+func (s *Store) listUnsafe() []Palette {
+	palettes := make([]Palette, 0, len(s.palettes))
+	for _, palette := range s.palettes {
+		palettes = append(palettes, clone(palette))
+	}
+	sort.Slice(palettes, func(i, j int) bool {
+		return strings.ToLower(palettes[i].Name) < strings.ToLower(palettes[j].Name)
+	})
+	return palettes
+}
+
+// Get returns the palette with the given name, ignoring case
+func (s *Store) Get(name string) (Palette, bool) {
+	if s == nil {
+		return Palette{}, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	palette, exists := s.palettes[strings.ToLower(strings.TrimSpace(name))]
+	if !exists {
+		return Palette{}, false
+	}
+	return clone(palette), true
+}
+
+// Save stores palette, replacing one with the same name. Colors may use any
+// form color.Parse accepts and are saved as hex. It returns the palette as
+// saved and whether it replaced an existing one.
+func (s *Store) Save(palette Palette) (Palette, bool, error) {
+	normalized, err := normalize(palette)
+	if err != nil {
+		return Palette{}, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(normalized.Name)
+	previous, replaced := s.palettes[key]
+	s.palettes[key] = &normalized
+	if err := s.saveUnsafe(); err != nil {
+		if replaced {
+			s.palettes[key] = previous
+		} else {
+			delete(s.palettes, key)
+		}
+		return Palette{}, false, err
+	}
+	return clone(&normalized), replaced, nil
+}
+
+// Delete removes the palette with the given name, ignoring case
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(strings.TrimSpace(name))
+	previous, exists := s.palettes[key]
+	if !exists {
+		return fmt.Errorf("no palette named '%s'", name)
+	}
+	delete(s.palettes, key)
+	if err := s.saveUnsafe(); err != nil {
+		s.palettes[key] = previous
+		return err
+	}
+	return nil
+}
+
+// Colors returns the colors of the named palette
+func (s *Store) Colors(name string) ([]string, error) {
+	palette, ok := s.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("no palette named '%s'", name)
+	}
+	return palette.Colors, nil
+}
+
+// Color parses a color specification that may refer to a palette color as
+// "name:n", the palette's nth color counting from 1 (e.g. "xmas:2").
+// Anything else is parsed with color.Parse.
+func (s *Store) Color(spec string) (string, error) {
+	name, position, isReference := strings.Cut(strings.TrimSpace(spec), ":")
+	if !isReference {
+		return color.Parse(spec)
+	}
+
+	colors, err := s.Colors(name)
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(position))
+	if err != nil || n < 1 || n > len(colors) {
+		return "", fmt.Errorf("palette '%s' has colors 1-%d, not %q", name, len(colors), position)
+	}
+	return colors[n-1], nil
+}
+
+// normalize validates a palette and converts its colors to hex
+func normalize(palette Palette) (Palette, error) {
+	palette.Name = strings.TrimSpace(palette.Name)
+	palette.Description = strings.TrimSpace(palette.Description)
+	if err := validateName(palette.Name); err != nil {
+		return Palette{}, err
+	}
+	if len(palette.Colors) == 0 || len(palette.Colors) > MaxColors {
+		return Palette{}, fmt.Errorf("a palette needs 1 to %d colors, got %d", MaxColors, len(palette.Colors))
+	}
+
+	colors := make([]string, len(palette.Colors))
+	for i, spec := range palette.Colors {
+		hex, err := color.Parse(spec)
+		if err != nil {
+			return Palette{}, fmt.Errorf("color %d: %v", i+1, err)
+		}
+		colors[i] = strings.ToLower(hex)
+	}
+	palette.Colors = colors
+	return palette, nil
+}
+
+// validateName checks that a palette name can be used in a "name:n" color
+// reference: letters, digits, '-' and '_'
+func validateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("palette name cannot be empty")
+	}
+	if len(name) > 64 {
+		return fmt.Errorf("palette name must be at most 64 characters")
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return fmt.Errorf("palette name '%s' may only contain letters, digits, '-' and '_'", name)
+		}
+	}
+	return nil
+}
+
+// clone copies a palette so callers cannot change the stored colors
+func clone(palette *Palette) Palette {
+	copied := *palette
+	copied.Colors = append([]string(nil), palette.Colors...)
+	return copied
+}
@@ -0,0 +1,117 @@
+package palettes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore_SaveAndLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "palettes.json")
+	store := NewStore(file)
+	if err := store.Load(); err != nil {
+		t.Fatalf("expected a missing file to load as empty, got %v", err)
+	}
+
+	saved, replaced, err := store.Save(Palette{Name: "xmas", Colors: []string{"red", "#0F0", "FFFFFF"}, Description: "Festive"})
+	if err != nil || replaced {
+		t.Fatalf("failed to save palette: %v (replaced %v)", err, replaced)
+	}
+	if want := []string{"ff0000", "00ff00", "ffffff"}; !equal(saved.Colors, want) {
+		t.Errorf("expected colors %v, got %v", want, saved.Colors)
+	}
+	if _, replaced, _ := store.Save(Palette{Name: "XMAS", Colors: []string{"red", "green"}}); !replaced {
+		t.Error("expected a save under the same name in other case to replace the palette")
+	}
+
+	reloaded := NewStore(file)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("failed to reload: %v", err)
+	}
+	palette, ok := reloaded.Get("Xmas")
+	if !ok || palette.Name != "XMAS" || !equal(palette.Colors, []string{"ff0000", "008000"}) {
+		t.Errorf("unexpected palette after reload: %+v (%v)", palette, ok)
+	}
+
+	if err := reloaded.Delete("xmas"); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if err := reloaded.Delete("xmas"); err == nil {
+		t.Error("expected deleting a missing palette to fail")
+	}
+	if len(reloaded.List()) != 0 {
+		t.Errorf("expected no palettes, got %v", reloaded.List())
+	}
+}
+
+func TestStore_SaveValidation(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "palettes.json"))
+	cases := map[string]Palette{
+		"empty name":   {Colors: []string{"red"}},
+		"colon":        {Name: "a:b", Colors: []string{"red"}},
+		"no colors":    {Name: "none"},
+		"bad color":    {Name: "bad", Colors: []string{"red", "nope"}},
+		"too many":     {Name: "many", Colors: make([]string, MaxColors+1)},
+		"space inside": {Name: "brand colors", Colors: []string{"red"}},
+	}
+	for name, palette := range cases {
+		if _, _, err := store.Save(palette); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if len(store.List()) != 0 {
+		t.Errorf("expected rejected palettes not to be stored, got %v", store.List())
+	}
+}
+
+func TestStore_Color(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "palettes.json"))
+	store.Save(Palette{Name: "dynatrace-brand", Colors: []string{"1496ff", "6f2da8", "b4dc00"}})
+
+	for spec, want := range map[string]string{
+		"dynatrace-brand:1": "1496ff",
+		"Dynatrace-Brand:3": "b4dc00",
+		"red":               "ff0000",
+		"#abc":              "aabbcc",
+	} {
+		if got, err := store.Color(spec); err != nil || got != want {
+			t.Errorf("Color(%q) = %q, %v; want %q", spec, got, err, want)
+		}
+	}
+	for _, spec := range []string{"dynatrace-brand:0", "dynatrace-brand:4", "dynatrace-brand:x", "xmas:1", "nope"} {
+		if _, err := store.Color(spec); err == nil {
+			t.Errorf("Color(%q): expected an error", spec)
+		}
+	}
+
+	// A server without a palette store still parses plain colors
+	var none *Store
+	if got, err := none.Color("blue"); err != nil || got != "0000ff" {
+		t.Errorf("expected plain colors without a store, got %q, %v", got, err)
+	}
+	if _, err := none.Color("xmas:1"); err == nil {
+		t.Error("expected a palette reference without a store to fail")
+	}
+}
+
+func TestStore_LoadInvalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "palettes.json")
+	if err := os.WriteFile(file, []byte(`[{"name":"bad","colors":["nope"]}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewStore(file).Load(); err == nil {
+		t.Error("expected an invalid palette file to fail to load")
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
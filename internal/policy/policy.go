@@ -0,0 +1,230 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/starspace46/ufo-mcp-go/internal/audit"
+)
+
+// Rule effects
+const (
+	EffectDeny   = "deny"
+	EffectModify = "modify"
+)
+
+// Rule is a single policy rule. Condition and Set values are CEL expressions
+// evaluated against the request variables:
+//
+//	tool    string             name of the tool being called
+//	args    map(string, dyn)   tool arguments
+//	client  string             calling client (client name or session ID)
+//	now     timestamp          time of the call
+//	hour    int                local hour of day (0-23)
+//	weekday int                local day of week (0 = Sunday)
+type Rule struct {
+	Name      string            `json:"name"`
+	Condition string            `json:"condition"`         // rule applies when this evaluates to true
+	Effect    string            `json:"effect"`            // "deny" or "modify"
+	Message   string            `json:"message,omitempty"` // explanation returned to the client
+	Set       map[string]string `json:"set,omitempty"`     // argument name -> replacement expression (modify only)
+}
+
+// Request describes a tool call being evaluated
+type Request struct {
+	Tool   string
+	Args   map[string]interface{}
+	Client string
+	Time   time.Time
+}
+
+// Decision is the outcome of evaluating a request against the policy
+type Decision struct {
+	Allowed   bool                   // false if a deny rule matched
+	Rule      string                 // name of the deny rule, if any
+	Message   string                 // deny message, if any
+	Arguments map[string]interface{} // arguments after modifications
+	Modified  []string               // names of the modify rules that applied
+}
+
+// compiledRule holds the CEL programs for a rule
+type compiledRule struct {
+	Rule
+	condition cel.Program
+	set       map[string]cel.Program
+}
+
+// Engine evaluates tool calls against a set of CEL rules
+type Engine struct {
+	rules []compiledRule
+	audit *audit.Logger
+}
+
+// Load reads and compiles policy rules from a JSON file
+func Load(path string, auditLogger *audit.Logger) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading policy file: %w", err)
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parsing policy JSON: %w", err)
+	}
+
+	return NewEngine(rules, auditLogger)
+}
+
+// NewEngine compiles the given rules
+func NewEngine(rules []Rule, auditLogger *audit.Logger) (*Engine, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("tool", cel.StringType),
+		cel.Variable("args", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("client", cel.StringType),
+		cel.Variable("now", cel.TimestampType),
+		cel.Variable("hour", cel.IntType),
+		cel.Variable("weekday", cel.IntType),
+		cel.CrossTypeNumericComparisons(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating CEL environment: %w", err)
+	}
+
+	engine := &Engine{audit: auditLogger}
+	for i, rule := range rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule%d", i+1)
+		}
+		if rule.Effect != EffectDeny && rule.Effect != EffectModify {
+			return nil, fmt.Errorf("rule %s: effect must be %q or %q", rule.Name, EffectDeny, EffectModify)
+		}
+		if rule.Effect == EffectModify && len(rule.Set) == 0 {
+			return nil, fmt.Errorf("rule %s: modify rules need at least one 'set' entry", rule.Name)
+		}
+
+		condition, err := compile(env, rule.Condition, cel.BoolType)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s condition: %w", rule.Name, err)
+		}
+
+		compiled := compiledRule{Rule: rule, condition: condition, set: make(map[string]cel.Program)}
+		for arg, expr := range rule.Set {
+			program, err := compile(env, expr, nil)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s set %s: %w", rule.Name, arg, err)
+			}
+			compiled.set[arg] = program
+		}
+		engine.rules = append(engine.rules, compiled)
+	}
+
+	return engine, nil
+}
+
+// compile parses and type-checks an expression, optionally enforcing its result type
+func compile(env *cel.Env, expr string, resultType *cel.Type) (cel.Program, error) {
+	ast, issues := env.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if resultType != nil && !ast.OutputType().IsExactType(resultType) && !ast.OutputType().IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("expression must return %s, got %s", resultType, ast.OutputType())
+	}
+	return env.Program(ast)
+}
+
+// Evaluate applies the policy rules to a request. Rules run in order: modify
+// rules rewrite arguments for subsequent rules, the first matching deny rule
+// rejects the call. Every decision that matched a rule is recorded in the
+// audit log.
+func (e *Engine) Evaluate(req Request) (*Decision, error) {
+	args := make(map[string]interface{}, len(req.Args))
+	for k, v := range req.Args {
+		args[k] = v
+	}
+	decision := &Decision{Allowed: true, Arguments: args}
+
+	for _, rule := range e.rules {
+		vars := map[string]interface{}{
+			"tool":    req.Tool,
+			"args":    decision.Arguments,
+			"client":  req.Client,
+			"now":     req.Time,
+			"hour":    int64(req.Time.Hour()),
+			"weekday": int64(req.Time.Weekday()),
+		}
+
+		out, _, err := rule.condition.Eval(vars)
+		if err != nil {
+			// Conditions referencing absent arguments simply don't match
+			continue
+		}
+		if matched, ok := out.Value().(bool); !ok || !matched {
+			continue
+		}
+
+		if rule.Effect == EffectDeny {
+			decision.Allowed = false
+			decision.Rule = rule.Name
+			decision.Message = rule.Message
+			break
+		}
+
+		for arg, program := range rule.set {
+			value, _, err := program.Eval(vars)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s set %s: %w", rule.Name, arg, err)
+			}
+			decision.Arguments[arg] = nativeValue(value.Value())
+		}
+		decision.Modified = append(decision.Modified, rule.Name)
+	}
+
+	e.record(req, decision)
+	return decision, nil
+}
+
+// record writes matched decisions to the audit log
+func (e *Engine) record(req Request, decision *Decision) {
+	if e.audit == nil || (decision.Allowed && len(decision.Modified) == 0) {
+		return
+	}
+
+	result := "allowed"
+	data := map[string]interface{}{
+		"tool":   req.Tool,
+		"client": req.Client,
+		"args":   req.Args,
+	}
+	if !decision.Allowed {
+		result = "denied"
+		data["rule"] = decision.Rule
+		data["message"] = decision.Message
+	} else {
+		result = "modified"
+		data["rules"] = decision.Modified
+		data["modifiedArgs"] = decision.Arguments
+	}
+
+	e.audit.Record(audit.Entry{
+		Kind:   "policy",
+		Action: req.Tool,
+		Result: result,
+		Data:   data,
+	})
+}
+
+// nativeValue converts CEL integer results to float64 so modified arguments
+// look like JSON-decoded tool arguments
+func nativeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	}
+	return value
+}
@@ -0,0 +1,108 @@
+package policy
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/audit"
+)
+
+func TestEngine_DenyAndModify(t *testing.T) {
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLogger, _ := audit.NewLogger(auditPath)
+	defer auditLogger.Close()
+
+	engine, err := NewEngine([]Rule{
+		{
+			Name:      "clampAfterHours",
+			Condition: `tool == "setBrightness" && (hour >= 22 || hour < 7) && args.level > 64`,
+			Effect:    EffectModify,
+			Set:       map[string]string{"level": "64"},
+		},
+		{
+			Name:      "noRawApi",
+			Condition: `tool == "sendRawApi" && client != "admin"`,
+			Effect:    EffectDeny,
+			Message:   "raw API is restricted",
+		},
+	}, auditLogger)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+
+	night := time.Date(2025, 6, 1, 23, 0, 0, 0, time.Local)
+	day := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+
+	// Clamped after hours
+	decision, err := engine.Evaluate(Request{Tool: "setBrightness", Args: map[string]interface{}{"level": 200.0}, Time: night})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decision.Allowed || decision.Arguments["level"] != 64.0 {
+		t.Errorf("expected level clamped to 64, got %+v", decision)
+	}
+
+	// Untouched during the day
+	decision, _ = engine.Evaluate(Request{Tool: "setBrightness", Args: map[string]interface{}{"level": 200.0}, Time: day})
+	if decision.Arguments["level"] != 200.0 || len(decision.Modified) != 0 {
+		t.Errorf("expected level unchanged, got %+v", decision)
+	}
+
+	// Denied for non-admin clients
+	decision, _ = engine.Evaluate(Request{Tool: "sendRawApi", Client: "claude", Time: day})
+	if decision.Allowed || decision.Rule != "noRawApi" || decision.Message != "raw API is restricted" {
+		t.Errorf("expected deny decision, got %+v", decision)
+	}
+	decision, _ = engine.Evaluate(Request{Tool: "sendRawApi", Client: "admin", Time: day})
+	if !decision.Allowed {
+		t.Error("expected admin to be allowed")
+	}
+
+	// Only the modify and deny decisions are audited
+	data, _ := os.ReadFile(auditPath)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d", len(lines))
+	}
+	var entry audit.Entry
+	json.Unmarshal([]byte(lines[1]), &entry)
+	if entry.Kind != "policy" || entry.Result != "denied" {
+		t.Errorf("unexpected audit entry: %+v", entry)
+	}
+}
+
+func TestEngine_MissingArgumentDoesNotMatch(t *testing.T) {
+	engine, err := NewEngine([]Rule{
+		{Name: "lowDim", Condition: `args.level < 10`, Effect: EffectDeny},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+
+	decision, err := engine.Evaluate(Request{Tool: "setLogo", Args: map[string]interface{}{"state": "on"}, Time: time.Now()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decision.Allowed {
+		t.Error("rule referencing a missing argument should not match")
+	}
+}
+
+func TestNewEngine_InvalidRules(t *testing.T) {
+	invalid := []Rule{
+		{Name: "badEffect", Condition: `true`, Effect: "allow"},
+		{Name: "badSyntax", Condition: `tool ==`, Effect: EffectDeny},
+		{Name: "notBool", Condition: `tool`, Effect: EffectDeny},
+		{Name: "noSet", Condition: `true`, Effect: EffectModify},
+	}
+
+	for _, rule := range invalid {
+		if _, err := NewEngine([]Rule{rule}, nil); err == nil {
+			t.Errorf("expected error for rule %s", rule.Name)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Package prompts provides curated MCP prompts for common lighting tasks.
+// Each prompt expands into step by step instructions naming the tool calls
+// and arguments to use, so a client gets useful results without first
+// working out the tool schemas.
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+)
+
+// Prompt is a curated prompt and the function that expands it
+type Prompt struct {
+	Definition mcp.Prompt
+	Get        func(ctx context.Context, arguments map[string]string) (*mcp.GetPromptResult, error)
+}
+
+// buildColors maps each build status to the lighting that shows it
+var buildColors = map[string]string{
+	"passing": "green",
+	"failing": "red",
+	"running": "blue",
+}
+
+// incidentPriorities maps each incident severity to its alert priority
+var incidentPriorities = map[string]int{
+	"critical": 90,
+	"warning":  60,
+	"info":     30,
+}
+
+// incidentColors maps each incident severity to its alert color
+var incidentColors = map[string]string{
+	"critical": "red",
+	"warning":  "orange",
+	"info":     "blue",
+}
+
+// List returns the curated prompts. Prompts that suggest stored effects read
+// them from the store when they are expanded, so they follow later changes.
+func List(store *effects.Store) []Prompt {
+	return []Prompt{
+		{
+			Definition: mcp.NewPrompt("setMoodLighting",
+				mcp.WithPromptDescription("Set calm ambient lighting that fits a mood, using a stored effect when one fits"),
+				mcp.WithArgument("mood", mcp.ArgumentDescription("The mood to set, e.g. 'relaxed', 'focused', 'party'"), mcp.RequiredArgument()),
+				mcp.WithArgument("brightness", mcp.ArgumentDescription("Brightness from 0 to 255 (optional)")),
+			),
+			Get: func(ctx context.Context, arguments map[string]string) (*mcp.GetPromptResult, error) {
+				return moodLighting(store, arguments)
+			},
+		},
+		{
+			Definition: mcp.NewPrompt("showBuildStatus",
+				mcp.WithPromptDescription("Show the status of a CI build on the UFO: green when passing, red when failing, spinning blue while running"),
+				mcp.WithArgument("status", mcp.ArgumentDescription("Build status: passing, failing or running"), mcp.RequiredArgument()),
+				mcp.WithArgument("project", mcp.ArgumentDescription("Project or pipeline name (optional)")),
+			),
+			Get: func(ctx context.Context, arguments map[string]string) (*mcp.GetPromptResult, error) {
+				return buildStatus(arguments)
+			},
+		},
+		{
+			Definition: mcp.NewPrompt("incidentAlert",
+				mcp.WithPromptDescription("Signal an incident on the UFO with an alert that stays until it is cleared"),
+				mcp.WithArgument("severity", mcp.ArgumentDescription("Incident severity: critical, warning or info"), mcp.RequiredArgument()),
+				mcp.WithArgument("summary", mcp.ArgumentDescription("What is happening, used as the alert reason (optional)")),
+				mcp.WithArgument("name", mcp.ArgumentDescription("Alert name for clearing it later (optional, default 'incident')")),
+			),
+			Get: func(ctx context.Context, arguments map[string]string) (*mcp.GetPromptResult, error) {
+				return incidentAlert(arguments)
+			},
+		},
+	}
+}
+
+// moodLighting expands the setMoodLighting prompt
+func moodLighting(store *effects.Store, arguments map[string]string) (*mcp.GetPromptResult, error) {
+	mood := strings.TrimSpace(arguments["mood"])
+	if mood == "" {
+		return nil, fmt.Errorf("'mood' is required")
+	}
+	brightness := -1
+	if value := strings.TrimSpace(arguments["brightness"]); value != "" {
+		level, err := strconv.Atoi(value)
+		if err != nil || level < 0 || level > 255 {
+			return nil, fmt.Errorf("'brightness' must be a whole number from 0 to 255")
+		}
+		brightness = level
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Set the Dynatrace UFO to mood lighting for: %s.\n\n", mood)
+	b.WriteString("1. Check whether a stored effect already fits the mood")
+	if stored := store.List(); len(stored) > 0 {
+		b.WriteString(". Stored effects:\n")
+		for _, effect := range stored {
+			fmt.Fprintf(&b, "   - %s: %s\n", effect.Name, effect.Description)
+		}
+	} else {
+		b.WriteString(" (call listEffects to see them).\n")
+	}
+	b.WriteString("   If one fits, call playEffect with {\"name\": \"<effect>\"}. If an effect is already running, call replaceEffect with the same arguments instead so the UFO does not flash back to the previous effect.\n")
+	b.WriteString("2. Otherwise call configureLighting with colors that suit the mood. Keep it calm: solid backgrounds, at most a slow whirl, e.g.\n")
+	b.WriteString("   {\"top\": {\"background\": \"<color>\"}, \"bottom\": {\"background\": \"<color>\", \"morph\": {\"brightnessMs\": 2000, \"fadeMs\": 1500}}, \"logo\": {\"state\": \"on\"}}\n")
+	if brightness >= 0 {
+		fmt.Fprintf(&b, "3. Call setBrightness with {\"level\": %d, \"fadeMs\": 1000}.\n", brightness)
+	} else {
+		b.WriteString("3. If the mood calls for dim light, call setBrightness with a lower level and \"fadeMs\": 1000 so it fades.\n")
+	}
+	b.WriteString("\nDescribe the lighting you chose in one sentence.")
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Mood lighting: %s", mood),
+		[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(b.String()))},
+	), nil
+}
+
+// buildStatus expands the showBuildStatus prompt
+func buildStatus(arguments map[string]string) (*mcp.GetPromptResult, error) {
+	status := strings.ToLower(strings.TrimSpace(arguments["status"]))
+	color, ok := buildColors[status]
+	if !ok {
+		return nil, fmt.Errorf("'status' must be passing, failing or running")
+	}
+	subject := "the build"
+	if project := strings.TrimSpace(arguments["project"]); project != "" {
+		subject = fmt.Sprintf("the %s build", project)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Show on the Dynatrace UFO that %s is %s.\n\n", subject, status)
+	b.WriteString("1. Call getEffectStack. If a build status effect is already showing, use replaceEffect instead of playEffect below so the stack does not grow with every build.\n")
+	switch status {
+	case "passing":
+		fmt.Fprintf(&b, "2. Call configureLighting with {\"top\": {\"background\": \"%s\"}, \"bottom\": {\"background\": \"%s\"}}.\n", color, color)
+		b.WriteString("3. If a 'build' alert is still raised from an earlier failure, call clearAlert with {\"name\": \"build\"}.\n")
+	case "failing":
+		fmt.Fprintf(&b, "2. Call raiseAlert with {\"name\": \"build\", \"color\": \"%s\", \"priority\": 70, \"reason\": \"%s failed\"} so the failure stays visible over other effects until it is fixed.\n", color, subject)
+		b.WriteString("3. When the build passes again, call clearAlert with {\"name\": \"build\"}.\n")
+	case "running":
+		fmt.Fprintf(&b, "2. Call configureLighting with {\"top\": {\"segments\": [\"0|4|%s\"], \"whirl\": 200}, \"bottom\": {\"background\": \"%s\"}} for a spinner while the build runs.\n", color, color)
+	}
+	b.WriteString("\nReport the build status shown in one sentence.")
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Build status: %s", status),
+		[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(b.String()))},
+	), nil
+}
+
+// incidentAlert expands the incidentAlert prompt
+func incidentAlert(arguments map[string]string) (*mcp.GetPromptResult, error) {
+	severity := strings.ToLower(strings.TrimSpace(arguments["severity"]))
+	priority, ok := incidentPriorities[severity]
+	if !ok {
+		return nil, fmt.Errorf("'severity' must be critical, warning or info")
+	}
+	name := strings.TrimSpace(arguments["name"])
+	if name == "" {
+		name = "incident"
+	}
+	reason := strings.TrimSpace(arguments["summary"])
+	if reason == "" {
+		reason = severity + " incident"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Signal a %s incident on the Dynatrace UFO: %s.\n\n", severity, reason)
+	b.WriteString("1. Check listEffects for an alert effect such as a pulse or flash in a fitting color.\n")
+	fmt.Fprintf(&b, "2. Call raiseAlert with {\"name\": %q, \"priority\": %d, \"reason\": %q} plus either \"effect\": \"<effect>\" or \"color\": %q.\n", name, priority, reason, incidentColors[severity])
+	if severity == "critical" {
+		b.WriteString("3. Call setBrightness with {\"level\": 255} so the alert is seen.\n")
+	}
+	fmt.Fprintf(&b, "\nThe alert stays until clearAlert is called with {\"name\": %q}; say so in your reply.", name)
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Incident alert: %s", severity),
+		[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(b.String()))},
+	), nil
+}
@@ -0,0 +1,141 @@
+package prompts
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+)
+
+// expand gets the named prompt and returns its text
+func expand(t *testing.T, list []Prompt, name string, arguments map[string]string) (string, error) {
+	t.Helper()
+	for _, prompt := range list {
+		if prompt.Definition.Name != name {
+			continue
+		}
+		result, err := prompt.Get(context.Background(), arguments)
+		if err != nil {
+			return "", err
+		}
+		if len(result.Messages) != 1 || result.Messages[0].Role != mcp.RoleUser {
+			t.Fatalf("%s: expected one user message, got %+v", name, result.Messages)
+		}
+		return result.Messages[0].Content.(mcp.TextContent).Text, nil
+	}
+	t.Fatalf("prompt %s not found", name)
+	return "", nil
+}
+
+func TestList_Definitions(t *testing.T) {
+	list := List(effects.NewStore(filepath.Join(t.TempDir(), "effects.json")))
+	required := map[string]string{
+		"setMoodLighting": "mood",
+		"showBuildStatus": "status",
+		"incidentAlert":   "severity",
+	}
+	if len(list) != len(required) {
+		t.Fatalf("expected %d prompts, got %d", len(required), len(list))
+	}
+	for _, prompt := range list {
+		argument, ok := required[prompt.Definition.Name]
+		if !ok {
+			t.Errorf("unexpected prompt %s", prompt.Definition.Name)
+			continue
+		}
+		if prompt.Definition.Description == "" {
+			t.Errorf("%s has no description", prompt.Definition.Name)
+		}
+		found := false
+		for _, arg := range prompt.Definition.Arguments {
+			if arg.Name == argument && arg.Required {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s should require %q", prompt.Definition.Name, argument)
+		}
+	}
+}
+
+func TestMoodLighting_SuggestsStoredEffects(t *testing.T) {
+	store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	if err := store.Add(&effects.Effect{Name: "calmBlue", Description: "Slow blue breathing", Pattern: "top_bg=0000FF"}); err != nil {
+		t.Fatal(err)
+	}
+	list := List(store)
+
+	text, err := expand(t, list, "setMoodLighting", map[string]string{"mood": "relaxed", "brightness": "80"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"relaxed", "calmBlue: Slow blue breathing", "playEffect", "configureLighting", `{"level": 80`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	if _, err := expand(t, list, "setMoodLighting", map[string]string{"mood": "relaxed", "brightness": "300"}); err == nil {
+		t.Error("expected an error for brightness 300")
+	}
+	if _, err := expand(t, list, "setMoodLighting", map[string]string{}); err == nil {
+		t.Error("expected an error without a mood")
+	}
+}
+
+func TestBuildStatus(t *testing.T) {
+	list := List(effects.NewStore(filepath.Join(t.TempDir(), "effects.json")))
+
+	tests := []struct {
+		status string
+		want   []string
+	}{
+		{"passing", []string{"checkout build is passing", `"background": "green"`, "clearAlert"}},
+		{"Failing", []string{"raiseAlert", `"name": "build"`, `"color": "red"`}},
+		{"running", []string{`"whirl": 200`, "blue"}},
+	}
+	for _, tt := range tests {
+		text, err := expand(t, list, "showBuildStatus", map[string]string{"status": tt.status, "project": "checkout"})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.status, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(text, want) {
+				t.Errorf("%s: expected %q in:\n%s", tt.status, want, text)
+			}
+		}
+	}
+
+	if _, err := expand(t, list, "showBuildStatus", map[string]string{"status": "flaky"}); err == nil {
+		t.Error("expected an error for an unknown status")
+	}
+}
+
+func TestIncidentAlert(t *testing.T) {
+	list := List(effects.NewStore(filepath.Join(t.TempDir(), "effects.json")))
+
+	text, err := expand(t, list, "incidentAlert", map[string]string{"severity": "critical", "summary": "checkout is down"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{`"name": "incident"`, `"priority": 90`, `"reason": "checkout is down"`, "setBrightness", "clearAlert"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	text, err = expand(t, list, "incidentAlert", map[string]string{"severity": "info", "name": "deploy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(text, `"name": "deploy"`) || strings.Contains(text, "setBrightness") {
+		t.Errorf("unexpected info alert prompt:\n%s", text)
+	}
+
+	if _, err := expand(t, list, "incidentAlert", map[string]string{"severity": "panic"}); err == nil {
+		t.Error("expected an error for an unknown severity")
+	}
+}
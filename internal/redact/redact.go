@@ -0,0 +1,142 @@
+package redact
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Mask replaces redacted values
+const Mask = "[redacted]"
+
+// DefaultPatterns match parameter names whose values are always redacted
+var DefaultPatterns = []string{
+	`(?i)pass`, `(?i)pwd`, `(?i)psk`, `(?i)secret`, `(?i)token`, `(?i)key`, `(?i)auth`, `(?i)credential`,
+}
+
+// paramPattern finds name=value pairs in query strings, URLs and free text
+var paramPattern = regexp.MustCompile(`([A-Za-z0-9_.\-\[\]]+)=([^&\s"'#]*)`)
+
+// Redactor masks the values of sensitive parameters before they reach logs,
+// events or audit records. A nil Redactor leaves everything unchanged.
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// New creates a redactor for the default patterns plus extra, which are
+// regular expressions matched against parameter names
+func New(extra []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, pattern := range append(append([]string{}, DefaultPatterns...), extra...) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// ParsePatterns splits a comma-separated list of patterns, ignoring blanks
+func ParsePatterns(list string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(list, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// Sensitive reports whether values of the named parameter are redacted
+func (r *Redactor) Sensitive(name string) bool {
+	if r == nil {
+		return false
+	}
+	for _, re := range r.patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// String masks the value of every sensitive name=value pair in s
+func (r *Redactor) String(s string) string {
+	if r == nil || !strings.Contains(s, "=") {
+		return s
+	}
+	return paramPattern.ReplaceAllStringFunc(s, func(pair string) string {
+		name := pair[:strings.Index(pair, "=")]
+		if !r.Sensitive(name) {
+			return pair
+		}
+		return name + "=" + Mask
+	})
+}
+
+// Value returns a redacted copy of a decoded JSON-like value, redacting
+// every string in it with String. Map keys are left alone, so field names
+// such as alertKey never hide their values.
+func (r *Redactor) Value(v interface{}) interface{} {
+	if r == nil {
+		return v
+	}
+	switch value := v.(type) {
+	case string:
+		return r.String(value)
+	case map[string]interface{}:
+		return r.Map(value)
+	case map[string]string:
+		copied := make(map[string]string, len(value))
+		for k, item := range value {
+			copied[k] = r.String(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(value))
+		for i, item := range value {
+			copied[i] = r.Value(item)
+		}
+		return copied
+	case []string:
+		copied := make([]string, len(value))
+		for i, item := range value {
+			copied[i] = r.String(item)
+		}
+		return copied
+	}
+	return v
+}
+
+// Map returns a redacted copy of m
+func (r *Redactor) Map(m map[string]interface{}) map[string]interface{} {
+	if r == nil || m == nil {
+		return m
+	}
+	copied := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		copied[k] = r.Value(v)
+	}
+	return copied
+}
+
+// Writer wraps w so everything written through it is redacted. The log
+// package writes each line with a single call, so lines are never split.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	return &writer{redactor: r, out: w}
+}
+
+// writer redacts writes before passing them on
+type writer struct {
+	redactor *Redactor
+	out      io.Writer
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.out, w.redactor.String(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
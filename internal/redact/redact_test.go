@@ -0,0 +1,70 @@
+package redact
+
+import (
+	"bytes"
+	"log"
+	"testing"
+)
+
+func TestRedactor_String(t *testing.T) {
+	r, err := New([]string{`^ssid$`})
+	if err != nil {
+		t.Fatalf("failed to create redactor: %v", err)
+	}
+
+	tests := []struct {
+		in, want string
+	}{
+		{"top_init=1&top_bg=ff0000", "top_init=1&top_bg=ff0000"},
+		{"wifi_ssid=home&wifi_pass=hunter2", "wifi_ssid=home&wifi_pass=[redacted]"},
+		{"ssid=home&psk=hunter2", "ssid=[redacted]&psk=[redacted]"},
+		{`Get "http://ufo/api?api_key=abc&dim=10": dial tcp`, `Get "http://ufo/api?api_key=[redacted]&dim=10": dial tcp`},
+		{"TOKEN=abc", "TOKEN=[redacted]"},
+		{"no pairs here", "no pairs here"},
+	}
+	for _, tt := range tests {
+		if got := r.String(tt.in); got != tt.want {
+			t.Errorf("String(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := New([]string{"("}); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestRedactor_Map(t *testing.T) {
+	r, _ := New(nil)
+	data := map[string]interface{}{
+		"query":    "dim=10&token=abc",
+		"alertKey": "disk",
+		"args":     map[string]interface{}{"queries": []interface{}{"password=x"}},
+	}
+
+	got := r.Map(data)
+	if got["query"] != "dim=10&token=[redacted]" || got["alertKey"] != "disk" {
+		t.Errorf("unexpected redaction: %v", got)
+	}
+	if nested := got["args"].(map[string]interface{})["queries"].([]interface{}); nested[0] != "password=[redacted]" {
+		t.Errorf("expected nested values redacted, got %v", nested)
+	}
+	if data["query"] != "dim=10&token=abc" {
+		t.Error("expected the original map to be left unchanged")
+	}
+
+	var nilRedactor *Redactor
+	if nilRedactor.String("token=abc") != "token=abc" {
+		t.Error("expected a nil redactor to leave values unchanged")
+	}
+}
+
+func TestRedactor_Writer(t *testing.T) {
+	r, _ := New(nil)
+	var buf bytes.Buffer
+	logger := log.New(r.Writer(&buf), "", 0)
+	logger.Printf("sending secret=abc to the UFO")
+
+	if buf.String() != "sending secret=[redacted] to the UFO\n" {
+		t.Errorf("unexpected log output: %q", buf.String())
+	}
+}
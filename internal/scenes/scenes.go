@@ -0,0 +1,199 @@
+// Package scenes stores snapshots of the UFO's complete lighting under a
+// name, so a look can be brought back exactly. Unlike effects, which are
+// patterns drawn over whatever the UFO shows, a scene replaces everything.
+package scenes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+)
+
+// Scene is the complete lighting at one moment: both rings with their whirl
+// and morph, the logo and the brightness
+type Scene struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	SavedAt     time.Time      `json:"savedAt"`
+	Lighting    state.LedState `json:"lighting"` // Effect is always empty
+}
+
+// Store persists scenes in a JSON file
+type Store struct {
+	mu     sync.RWMutex
+	scenes map[string]*Scene // keyed by lowercase name
+	file   string
+}
+
+// NewStore creates a scene store saved in filePath
+func NewStore(filePath string) *Store {
+	return &Store{
+		scenes: make(map[string]*Scene),
+		file:   filePath,
+	}
+}
+
+// Load reads the scenes file. A missing file is an empty store.
+func (s *Store) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.scenes = make(map[string]*Scene)
+			return nil
+		}
+		return fmt.Errorf("reading scenes file: %w", err)
+	}
+
+	var scenes []*Scene
+	if err := json.Unmarshal(data, &scenes); err != nil {
+		return fmt.Errorf("parsing scenes JSON: %w", err)
+	}
+
+	s.scenes = make(map[string]*Scene)
+	for _, scene := range scenes {
+		if err := validateName(scene.Name); err != nil {
+			return err
+		}
+		s.scenes[strings.ToLower(scene.Name)] = scene
+	}
+	return nil
+}
+
+// saveUnsafe writes the scenes file; the caller holds the lock
+func (s *Store) saveUnsafe() error {
+	data, err := json.MarshalIndent(s.listUnsafe(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling scenes: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.file), 0755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	return os.WriteFile(s.file, data, 0644)
+}
+
+// List returns copies of all scenes ordered by name
+func (s *Store) List() []Scene {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.listUnsafe()
+}
+
+// listUnsafe lists scenes without acquiring the lock
+func (s *Store) listUnsafe() []Scene {
+	scenes := make([]Scene, 0, len(s.scenes))
+	for _, scene := range s.scenes {
+		scenes = append(scenes, clone(scene))
+	}
+	sort.Slice(scenes, func(i, j int) bool {
+		return strings.ToLower(scenes[i].Name) < strings.ToLower(scenes[j].Name)
+	})
+	return scenes
+}
+
+// Get returns the scene with the given name, ignoring case
+func (s *Store) Get(name string) (Scene, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	scene, exists := s.scenes[strings.ToLower(strings.TrimSpace(name))]
+	if !exists {
+		return Scene{}, false
+	}
+	return clone(scene), true
+}
+
+// Save stores the lighting under a name, replacing a scene with the same
+// name. It returns the scene as saved and whether it replaced one.
+func (s *Store) Save(name, description string, lighting state.LedState, at time.Time) (Scene, bool, error) {
+	name = strings.TrimSpace(name)
+	if err := validateName(name); err != nil {
+		return Scene{}, false, err
+	}
+	lighting.Effect = ""
+	scene := &Scene{
+		Name:        name,
+		Description: strings.TrimSpace(description),
+		SavedAt:     timezone.In(at),
+		Lighting:    lighting,
+	}
+	scene.Lighting = clone(scene).Lighting
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(name)
+	previous, replaced := s.scenes[key]
+	s.scenes[key] = scene
+	if err := s.saveUnsafe(); err != nil {
+		if replaced {
+			s.scenes[key] = previous
+		} else {
+			delete(s.scenes, key)
+		}
+		return Scene{}, false, err
+	}
+	return clone(scene), replaced, nil
+}
+
+// Delete removes the scene with the given name, ignoring case
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(strings.TrimSpace(name))
+	previous, exists := s.scenes[key]
+	if !exists {
+		return fmt.Errorf("no scene named '%s'", name)
+	}
+	delete(s.scenes, key)
+	if err := s.saveUnsafe(); err != nil {
+		s.scenes[key] = previous
+		return err
+	}
+	return nil
+}
+
+// validateName checks a scene name: letters, digits, '-' and '_'
+func validateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("scene name cannot be empty")
+	}
+	if len(name) > 64 {
+		return fmt.Errorf("scene name must be at most 64 characters")
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return fmt.Errorf("scene name '%s' may only contain letters, digits, '-' and '_'", name)
+		}
+	}
+	return nil
+}
+
+// clone copies a scene so callers cannot change the stored animations
+func clone(scene *Scene) Scene {
+	copied := *scene
+	if morph := scene.Lighting.TopMorph; morph != nil {
+		m := *morph
+		copied.Lighting.TopMorph = &m
+	}
+	if morph := scene.Lighting.BottomMorph; morph != nil {
+		m := *morph
+		copied.Lighting.BottomMorph = &m
+	}
+	if animation := scene.Lighting.LogoAnimation; animation != nil {
+		a := *animation
+		copied.Lighting.LogoAnimation = &a
+	}
+	return copied
+}
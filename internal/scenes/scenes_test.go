@@ -0,0 +1,73 @@
+package scenes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+func TestStore_SaveAndLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "scenes.json")
+	store := NewStore(file)
+	if err := store.Load(); err != nil {
+		t.Fatalf("expected a missing file to load as empty, got %v", err)
+	}
+
+	lighting := state.LedState{Effect: "rainbow", Dim: 80, LogoOn: true, TopWhirlMs: 300, TopMorph: &state.MorphData{BrightnessMs: 1000, FadeMs: 500}}
+	lighting.Top[0] = "ff0000"
+	saved, replaced, err := store.Save("focus", " Calm blue ", lighting, time.Now())
+	if err != nil || replaced {
+		t.Fatalf("failed to save scene: %v (replaced %v)", err, replaced)
+	}
+	if saved.Description != "Calm blue" || saved.Lighting.Effect != "" {
+		t.Errorf("expected a trimmed description and no effect, got %+v", saved)
+	}
+
+	// The stored scene does not share its morph with the caller's state
+	lighting.TopMorph.FadeMs = 1
+	if scene, _ := store.Get("FOCUS"); scene.Lighting.TopMorph.FadeMs != 500 {
+		t.Errorf("expected the stored morph unchanged, got %+v", scene.Lighting.TopMorph)
+	}
+	if _, replaced, _ := store.Save("Focus", "", lighting, time.Now()); !replaced {
+		t.Error("expected a save under the same name in other case to replace the scene")
+	}
+
+	reloaded := NewStore(file)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("failed to reload: %v", err)
+	}
+	scene, exists := reloaded.Get("focus")
+	if !exists || scene.Lighting.Top[0] != "ff0000" || scene.Lighting.Dim != 80 || scene.Lighting.TopWhirlMs != 300 {
+		t.Errorf("expected the scene back after reloading, got %+v", scene)
+	}
+
+	if err := reloaded.Delete("focus"); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if err := reloaded.Delete("focus"); err == nil {
+		t.Error("expected an error deleting a missing scene")
+	}
+	if len(reloaded.List()) != 0 {
+		t.Errorf("expected no scenes left, got %v", reloaded.List())
+	}
+}
+
+func TestStore_InvalidNames(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "scenes.json"))
+	for _, name := range []string{"", "demo day", "a/b"} {
+		if _, _, err := store.Save(name, "", state.LedState{}, time.Now()); err == nil {
+			t.Errorf("expected name %q to be rejected", name)
+		}
+	}
+}
+
+func TestStore_LoadInvalidFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "scenes.json")
+	os.WriteFile(file, []byte("{"), 0644)
+	if err := NewStore(file).Load(); err == nil {
+		t.Error("expected an error for a corrupt scenes file")
+	}
+}
@@ -0,0 +1,220 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LedChange is one LED whose color differs between two states
+type LedChange struct {
+	Index int    `json:"index"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// ValueChange is a setting that differs between two states
+type ValueChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// StateDiff describes what changed from one LED state to another. Fields
+// that did not change are omitted.
+type StateDiff struct {
+	Changed        bool                   `json:"changed"`
+	Top            []LedChange            `json:"top,omitempty"`
+	Bottom         []LedChange            `json:"bottom,omitempty"`
+	Brightness     *ValueChange           `json:"brightness,omitempty"`
+	RingBrightness map[string]ValueChange `json:"ringBrightness,omitempty"` // percent by ring
+	Logo           *ValueChange           `json:"logo,omitempty"`
+	Effect         *ValueChange           `json:"effect,omitempty"`
+	Animations     map[string]ValueChange `json:"animations,omitempty"` // whirl, morph and logo animation settings by name
+}
+
+// Diff compares two LED states
+func Diff(from, to LedState) StateDiff {
+	var diff StateDiff
+	diff.Top = diffRing(from.Top, to.Top)
+	diff.Bottom = diffRing(from.Bottom, to.Bottom)
+	if from.Dim != to.Dim {
+		diff.Brightness = &ValueChange{From: from.Dim, To: to.Dim}
+	}
+	fromTop, fromBottom := from.RingBrightness()
+	toTop, toBottom := to.RingBrightness()
+	for _, ring := range []struct {
+		name     string
+		from, to int
+	}{{"top", fromTop, toTop}, {"bottom", fromBottom, toBottom}} {
+		if ring.from != ring.to {
+			if diff.RingBrightness == nil {
+				diff.RingBrightness = map[string]ValueChange{}
+			}
+			diff.RingBrightness[ring.name] = ValueChange{From: ring.from, To: ring.to}
+		}
+	}
+	if from.LogoOn != to.LogoOn {
+		diff.Logo = &ValueChange{From: from.LogoOn, To: to.LogoOn}
+	}
+	if from.Effect != to.Effect {
+		diff.Effect = &ValueChange{From: from.Effect, To: to.Effect}
+	}
+
+	animations := map[string]ValueChange{}
+	if from.TopWhirlMs != to.TopWhirlMs {
+		animations["topWhirlMs"] = ValueChange{From: from.TopWhirlMs, To: to.TopWhirlMs}
+	}
+	if from.BottomWhirlMs != to.BottomWhirlMs {
+		animations["bottomWhirlMs"] = ValueChange{From: from.BottomWhirlMs, To: to.BottomWhirlMs}
+	}
+	if from.TopWhirlCounterClockwise != to.TopWhirlCounterClockwise {
+		animations["topWhirlCounterClockwise"] = ValueChange{From: from.TopWhirlCounterClockwise, To: to.TopWhirlCounterClockwise}
+	}
+	if from.BottomWhirlCounterClockwise != to.BottomWhirlCounterClockwise {
+		animations["bottomWhirlCounterClockwise"] = ValueChange{From: from.BottomWhirlCounterClockwise, To: to.BottomWhirlCounterClockwise}
+	}
+	if !sameMorph(from.TopMorph, to.TopMorph) {
+		animations["topMorph"] = ValueChange{From: from.TopMorph, To: to.TopMorph}
+	}
+	if !sameMorph(from.BottomMorph, to.BottomMorph) {
+		animations["bottomMorph"] = ValueChange{From: from.BottomMorph, To: to.BottomMorph}
+	}
+	if !sameLogoAnimation(from.LogoAnimation, to.LogoAnimation) {
+		animations["logoAnimation"] = ValueChange{From: from.LogoAnimation, To: to.LogoAnimation}
+	}
+	if len(animations) > 0 {
+		diff.Animations = animations
+	}
+
+	diff.Changed = len(diff.Top) > 0 || len(diff.Bottom) > 0 || diff.Brightness != nil ||
+		diff.RingBrightness != nil || diff.Logo != nil || diff.Effect != nil || diff.Animations != nil
+	return diff
+}
+
+// diffRing lists the LEDs whose colors differ, ignoring case
+func diffRing(from, to [15]string) []LedChange {
+	var changes []LedChange
+	for i := range from {
+		if !strings.EqualFold(from[i], to[i]) {
+			changes = append(changes, LedChange{Index: i, From: from[i], To: to[i]})
+		}
+	}
+	return changes
+}
+
+// sameMorph compares morph settings, treating nil as no morph
+func sameMorph(a, b *MorphData) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// sameLogoAnimation compares logo animations, treating nil as a static logo
+func sameLogoAnimation(a, b *LogoAnimation) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Summary describes the diff in a few lines for people
+func (d StateDiff) Summary() string {
+	if !d.Changed {
+		return "No changes"
+	}
+
+	var lines []string
+	for _, ring := range []struct {
+		name    string
+		changes []LedChange
+	}{{"Top", d.Top}, {"Bottom", d.Bottom}} {
+		if len(ring.changes) > 0 {
+			lines = append(lines, fmt.Sprintf("%s ring: %d LEDs changed (%s)", ring.name, len(ring.changes), summarizeLeds(ring.changes)))
+		}
+	}
+	if d.Brightness != nil {
+		lines = append(lines, fmt.Sprintf("Brightness: %v → %v", d.Brightness.From, d.Brightness.To))
+	}
+	for _, ring := range []string{"top", "bottom"} {
+		if change, ok := d.RingBrightness[ring]; ok {
+			lines = append(lines, fmt.Sprintf("%s ring brightness: %v%% → %v%%", strings.ToUpper(ring[:1])+ring[1:], change.From, change.To))
+		}
+	}
+	if d.Logo != nil {
+		lines = append(lines, fmt.Sprintf("Logo: %s → %s", onOff(d.Logo.From), onOff(d.Logo.To)))
+	}
+	if d.Effect != nil {
+		lines = append(lines, fmt.Sprintf("Effect: %s → %s", effectLabel(d.Effect.From), effectLabel(d.Effect.To)))
+	}
+	for _, name := range []string{"topWhirlMs", "bottomWhirlMs", "topWhirlCounterClockwise", "bottomWhirlCounterClockwise", "topMorph", "bottomMorph", "logoAnimation"} {
+		if change, ok := d.Animations[name]; ok {
+			lines = append(lines, fmt.Sprintf("%s: %s → %s", name, animationLabel(change.From), animationLabel(change.To)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// summarizeLeds groups LED changes by their colors, e.g. "off → FF0000 ×15"
+func summarizeLeds(changes []LedChange) string {
+	var order []string
+	counts := map[string]int{}
+	for _, change := range changes {
+		key := fmt.Sprintf("%s → %s", colorLabel(change.From), colorLabel(change.To))
+		if counts[key] == 0 {
+			order = append(order, key)
+		}
+		counts[key]++
+	}
+	parts := make([]string, len(order))
+	for i, key := range order {
+		parts[i] = fmt.Sprintf("%s ×%d", key, counts[key])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func colorLabel(color string) string {
+	if color == "" || color == "000000" {
+		return "off"
+	}
+	return color
+}
+
+func onOff(value interface{}) string {
+	if on, _ := value.(bool); on {
+		return "on"
+	}
+	return "off"
+}
+
+func effectLabel(value interface{}) string {
+	if name, _ := value.(string); name != "" {
+		return name
+	}
+	return "none"
+}
+
+func animationLabel(value interface{}) string {
+	switch v := value.(type) {
+	case int:
+		if v == 0 {
+			return "off"
+		}
+		return fmt.Sprintf("%dms", v)
+	case bool:
+		if v {
+			return "counter-clockwise"
+		}
+		return "clockwise"
+	case *MorphData:
+		if v == nil {
+			return "off"
+		}
+		return fmt.Sprintf("%dms bright, %dms fade", v.BrightnessMs, v.FadeMs)
+	case *LogoAnimation:
+		if v == nil {
+			return "off"
+		}
+		return fmt.Sprintf("%s %s every %dms", v.Mode, v.Color1, v.PeriodMs)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,160 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+)
+
+// DefaultHistorySize is how many snapshots the state history keeps
+const DefaultHistorySize = 500
+
+// historySubscriberID is the broadcaster subscription used by the history
+const historySubscriberID = "state-history"
+
+// HistoryEntry is the shadow state as it was from Time until the next entry
+type HistoryEntry struct {
+	Time  time.Time `json:"time"`
+	State LedState  `json:"state"`
+}
+
+// History records the shadow state each time it changes and persists the
+// entries, so earlier states can be compared with the current one even
+// after a restart
+type History struct {
+	mu      sync.RWMutex
+	entries []HistoryEntry // oldest first
+	file    string
+	size    int
+}
+
+// NewHistory creates a history stored in filePath that keeps up to size entries
+func NewHistory(filePath string, size int) *History {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	return &History{
+		file: filePath,
+		size: size,
+	}
+}
+
+// Load reads the history file. A missing file is an empty history.
+func (h *History) Load() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	data, err := os.ReadFile(h.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			h.entries = nil
+			return nil
+		}
+		return fmt.Errorf("reading state history file: %w", err)
+	}
+
+	var entries []HistoryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("parsing state history JSON: %w", err)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	if len(entries) > h.size {
+		entries = entries[len(entries)-h.size:]
+	}
+	h.entries = entries
+	return nil
+}
+
+// Record appends a snapshot taken at the given time unless it matches the
+// latest entry, and saves the history. Returns true if an entry was added.
+func (h *History) Record(snapshot LedState, at time.Time) (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if n := len(h.entries); n > 0 && !Diff(h.entries[n-1].State, snapshot).Changed {
+		return false, nil
+	}
+	h.entries = append(h.entries, HistoryEntry{Time: timezone.In(at), State: snapshot})
+	if len(h.entries) > h.size {
+		h.entries = h.entries[len(h.entries)-h.size:]
+	}
+	return true, h.saveUnsafe()
+}
+
+// saveUnsafe writes the history file; the caller holds the lock
+func (h *History) saveUnsafe() error {
+	data, err := json.MarshalIndent(h.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling state history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(h.file), 0755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	return os.WriteFile(h.file, data, 0644)
+}
+
+// Entries returns a copy of the history, oldest first
+func (h *History) Entries() []HistoryEntry {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	entries := make([]HistoryEntry, len(h.entries))
+	copy(entries, h.entries)
+	return entries
+}
+
+// Latest returns the most recently recorded entry
+func (h *History) Latest() (HistoryEntry, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.entries) == 0 {
+		return HistoryEntry{}, false
+	}
+	return h.entries[len(h.entries)-1], true
+}
+
+// At returns the entry in effect at t: the latest one recorded at or before
+// it. It returns false if the history starts after t.
+func (h *History) At(t time.Time) (HistoryEntry, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	i := sort.Search(len(h.entries), func(i int) bool { return h.entries[i].Time.After(t) })
+	if i == 0 {
+		return HistoryEntry{}, false
+	}
+	return h.entries[i-1], true
+}
+
+// Start records the manager's state after every published event until ctx
+// is cancelled or the broadcaster is closed. Failures to save are reported
+// to onError.
+func (h *History) Start(ctx context.Context, broadcaster *events.Broadcaster, manager *Manager, onError func(error)) {
+	sub := broadcaster.Subscribe(historySubscriberID)
+
+	go func() {
+		defer broadcaster.Unsubscribe(historySubscriberID)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if _, err := h.Record(*manager.Snapshot(), time.Now()); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
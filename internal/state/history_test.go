@@ -0,0 +1,102 @@
+package state
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+)
+
+func TestDiff(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	manager := NewManager(broadcaster)
+
+	before := *manager.Snapshot()
+	if diff := Diff(before, before); diff.Changed || diff.Summary() != "No changes" {
+		t.Fatalf("Expected no changes, got %+v", diff)
+	}
+
+	manager.UpdateTopRing([]string{"ff0000", "ff0000", "ff0000"})
+	manager.UpdateBrightness(128)
+	manager.UpdateLogo(true)
+	manager.UpdateEffect("rainbow")
+	manager.UpdateWhirl("bottom", 300, false)
+	manager.SetRingBrightness(40, 100)
+	after := *manager.Snapshot()
+
+	diff := Diff(before, after)
+	if !diff.Changed {
+		t.Fatal("Expected changes")
+	}
+	if len(diff.Top) != 3 || len(diff.Bottom) != 0 {
+		t.Errorf("Expected 3 top and 0 bottom LED changes, got %d and %d", len(diff.Top), len(diff.Bottom))
+	}
+	if diff.Brightness == nil || diff.Logo == nil || diff.Effect == nil {
+		t.Errorf("Expected brightness, logo and effect changes, got %+v", diff)
+	}
+	if _, ok := diff.Animations["bottomWhirlMs"]; !ok {
+		t.Errorf("Expected a bottomWhirlMs change, got %v", diff.Animations)
+	}
+	if len(diff.RingBrightness) != 1 || diff.RingBrightness["top"].To != 40 {
+		t.Errorf("Expected only a top ring brightness change, got %v", diff.RingBrightness)
+	}
+
+	summary := diff.Summary()
+	for _, want := range []string{"Top ring: 3 LEDs changed (off → ff0000 ×3)", "Brightness: 255 → 128", "Logo: off → on", "Effect: none → rainbow", "bottomWhirlMs: off → 300ms", "Top ring brightness: 100% → 40%"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Summary missing %q:\n%s", want, summary)
+		}
+	}
+
+	// Colors differing only in case are the same
+	upper := after
+	upper.Top[0] = "FF0000"
+	if diff := Diff(after, upper); diff.Changed {
+		t.Errorf("Expected case-insensitive colors to match, got %+v", diff)
+	}
+}
+
+func TestHistory_RecordAndLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "state-history.json")
+	history := NewHistory(file, 3)
+	if err := history.Load(); err != nil {
+		t.Fatalf("Loading a missing history should succeed: %v", err)
+	}
+
+	morning := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	state := LedState{Dim: 255}
+	for i, dim := range []int{255, 255, 100, 50, 10} {
+		state.Dim = dim
+		added, err := history.Record(state, morning.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+		if added != (i != 1) {
+			t.Errorf("Record %d: expected added=%v, got %v", i, i != 1, added)
+		}
+	}
+
+	// Only the newest entries are kept, and they survive a reload
+	reloaded := NewHistory(file, 3)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	entries := reloaded.Entries()
+	if len(entries) != 3 || entries[0].State.Dim != 100 {
+		t.Fatalf("Expected the 3 newest entries starting at dim 100, got %+v", entries)
+	}
+
+	latest, ok := reloaded.Latest()
+	if !ok || latest.State.Dim != 10 {
+		t.Errorf("Expected latest dim 10, got %+v", latest)
+	}
+	if entry, ok := reloaded.At(morning.Add(3*time.Hour + 30*time.Minute)); !ok || entry.State.Dim != 50 {
+		t.Errorf("Expected dim 50 at 11:30, got %+v", entry)
+	}
+	if _, ok := reloaded.At(morning); ok {
+		t.Error("Expected no entry before the history starts")
+	}
+}
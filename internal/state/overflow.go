@@ -0,0 +1,132 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+)
+
+// Overflow policies, saying what a push onto a full effect stack does
+const (
+	OverflowDropOldest        = "drop-oldest"        // remove the oldest entries to make room
+	OverflowReject            = "reject"             // refuse the new effect
+	OverflowCollapseSynthetic = "collapse-synthetic" // remove the oldest synthetic entries, else refuse
+)
+
+// OverflowPolicies lists the overflow policies SetStackLimit accepts
+var OverflowPolicies = []string{OverflowDropOldest, OverflowReject, OverflowCollapseSynthetic}
+
+// ErrStackFull is returned when the effect stack has no room for another
+// effect
+var ErrStackFull = errors.New("the effect stack is full")
+
+// Synthetic reports whether the server generated the entry from a tool's
+// arguments, such as alternating effects or displayed text, rather than it
+// being a stored effect or an alert
+func (item EffectStackItem) Synthetic() bool {
+	synthetic, _ := item.Context["synthetic"].(bool)
+	return synthetic
+}
+
+// SetStackLimit caps the effect stack at maxDepth entries, 0 for no limit,
+// with policy saying what a push onto a full stack does. Entries already
+// beyond the limit stay until they end.
+func (m *Manager) SetStackLimit(maxDepth int, policy string) error {
+	if maxDepth < 0 {
+		return fmt.Errorf("the stack limit must be 0 or more, got %d", maxDepth)
+	}
+	if !slices.Contains(OverflowPolicies, policy) {
+		return fmt.Errorf("unknown overflow policy %q: use %s", policy, strings.Join(OverflowPolicies, ", "))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxDepth, m.overflow = maxDepth, policy
+	return nil
+}
+
+// StackLimit returns the stack's limit, 0 for none, and overflow policy
+func (m *Manager) StackLimit() (int, string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.maxDepth, m.overflow
+}
+
+// CheckRoom returns ErrStackFull if pushing an ordinary effect now would be
+// refused, so tools can decline before showing it on the UFO
+func (m *Manager) CheckRoom() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.maxDepth == 0 || len(m.effectStack) < m.maxDepth {
+		return nil
+	}
+	switch m.overflow {
+	case OverflowReject:
+		return fmt.Errorf("%w: %d of %d entries", ErrStackFull, len(m.effectStack), m.maxDepth)
+	case OverflowCollapseSynthetic:
+		if !slices.ContainsFunc(m.effectStack, EffectStackItem.Synthetic) {
+			return fmt.Errorf("%w: %d of %d entries and none are synthetic", ErrStackFull, len(m.effectStack), m.maxDepth)
+		}
+	}
+	return nil
+}
+
+// makeRoomUnsafe frees room for item on a full stack as the overflow policy
+// says, publishing a stack_overflow event, and reports whether item may be
+// pushed and whether the entry on top was dropped to make room. Entries are
+// only removed when item may be pushed, so a refused effect costs the stack
+// nothing. Alerts and other entries with a priority are never refused. The
+// caller must hold the lock.
+func (m *Manager) makeRoomUnsafe(item EffectStackItem) (ok, topDropped bool) {
+	excess := len(m.effectStack) + 1 - m.maxDepth
+	if m.maxDepth == 0 || excess <= 0 {
+		return true, false
+	}
+
+	// Ordinary entries go first, so raised alerts outlast the effects they
+	// cover
+	removable := func(candidate EffectStackItem) bool {
+		return candidate.Priority() <= 0
+	}
+	if m.overflow == OverflowCollapseSynthetic {
+		removable = EffectStackItem.Synthetic
+	}
+	var drop []int
+	if m.overflow != OverflowReject {
+		for i := 0; i < len(m.effectStack) && len(drop) < excess; i++ {
+			if removable(m.effectStack[i]) {
+				drop = append(drop, i)
+			}
+		}
+	}
+	rejected := len(drop) < excess && item.Priority() <= 0
+
+	var dropped []EffectStackItem
+	if !rejected {
+		topDropped = len(drop) > 0 && drop[len(drop)-1] == len(m.effectStack)-1
+		for i := len(drop) - 1; i >= 0; i-- {
+			dropped = append([]EffectStackItem{m.effectStack[drop[i]]}, dropped...)
+			m.effectStack = slices.Delete(m.effectStack, drop[i], drop[i]+1)
+		}
+	}
+	slog.Warn("Effect stack full", "effect", item.Name, "policy", m.overflow, "maxDepth", m.maxDepth, "dropped", len(dropped), "rejected", rejected)
+
+	names := make([]events.StackEntry, len(dropped))
+	for i, entry := range dropped {
+		names[i] = events.StackEntry{Name: entry.Name, InstanceID: entry.InstanceID()}
+	}
+	m.broadcaster.Publish(events.NewEvent(events.StackOverflow{
+		Effect:     item.Name,
+		Policy:     m.overflow,
+		MaxDepth:   m.maxDepth,
+		Dropped:    names,
+		Rejected:   rejected,
+		StackDepth: len(m.effectStack),
+	}))
+	return !rejected, topDropped
+}
@@ -0,0 +1,193 @@
+package state
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+)
+
+func stackNames(manager *Manager) string {
+	var names []string
+	for _, item := range manager.GetEffectStack() {
+		names = append(names, item.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestSetStackLimit(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	manager := NewManager(broadcaster)
+	if err := manager.SetStackLimit(-1, OverflowReject); err == nil {
+		t.Error("Expected an error for a negative limit")
+	}
+	if err := manager.SetStackLimit(3, "drop-newest"); err == nil {
+		t.Error("Expected an error for an unknown policy")
+	}
+	if err := manager.SetStackLimit(3, OverflowReject); err != nil {
+		t.Fatalf("SetStackLimit: %v", err)
+	}
+	if depth, policy := manager.StackLimit(); depth != 3 || policy != OverflowReject {
+		t.Errorf("Expected limit 3 with reject, got %d with %s", depth, policy)
+	}
+}
+
+func TestStackLimit_DropOldest(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	manager := NewManager(broadcaster)
+	manager.SetStackLimit(3, OverflowDropOldest)
+	manager.PushEffect("first", "effect=first", nil)
+	manager.PushEffect("alert", "effect=alert", map[string]interface{}{"priority": 50})
+	manager.PushEffect("second", "effect=second", nil)
+
+	sub := broadcaster.Subscribe("test")
+	defer broadcaster.Unsubscribe("test")
+
+	if err := manager.CheckRoom(); err != nil {
+		t.Errorf("Expected room to be made, got %v", err)
+	}
+	manager.PushEffect("third", "effect=third", nil)
+	if names := stackNames(manager); names != "second,third,alert" {
+		t.Errorf("Expected the oldest effect dropped and the alert kept, got %s", names)
+	}
+
+	select {
+	case event := <-sub.Channel:
+		if event.Type != events.EventStackOverflow {
+			t.Fatalf("Expected stack_overflow event, got %s", event.Type)
+		}
+		if event.Data["effect"] != "third" || event.Data["rejected"] != false {
+			t.Errorf("Unexpected event data: %v", event.Data)
+		}
+		dropped, _ := event.Data["dropped"].([]events.StackEntry)
+		if len(dropped) != 1 || dropped[0].Name != "first" {
+			t.Errorf("Expected 'first' dropped, got %v", event.Data["dropped"])
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected stack_overflow event, got none")
+	}
+}
+
+func TestStackLimit_Reject(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	manager := NewManager(broadcaster)
+	manager.SetStackLimit(2, OverflowReject)
+	manager.PushEffect("first", "effect=first", nil)
+	manager.PushEffect("second", "effect=second", nil)
+
+	if err := manager.CheckRoom(); !errors.Is(err, ErrStackFull) {
+		t.Errorf("Expected ErrStackFull, got %v", err)
+	}
+	if onTop, _ := manager.PushEffect("third", "effect=third", nil); onTop {
+		t.Error("Expected the effect to be refused")
+	}
+	if names := stackNames(manager); names != "first,second" {
+		t.Errorf("Expected the stack unchanged, got %s", names)
+	}
+
+	// Alerts are never refused
+	if onTop, _ := manager.PushEffect("alert", "effect=alert", map[string]interface{}{"priority": 50}); !onTop {
+		t.Error("Expected the alert on top")
+	}
+	if names := stackNames(manager); names != "first,second,alert" {
+		t.Errorf("Expected the alert pushed, got %s", names)
+	}
+}
+
+func TestStackLimit_CollapseSynthetic(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	manager := NewManager(broadcaster)
+	manager.SetStackLimit(3, OverflowCollapseSynthetic)
+	manager.PushEffect("stored", "effect=stored", nil)
+	manager.PushEffect("display:B12", "effect=display", map[string]interface{}{"synthetic": true})
+	manager.PushEffect("other", "effect=other", nil)
+
+	manager.PushEffect("next", "effect=next", nil)
+	if names := stackNames(manager); names != "stored,other,next" {
+		t.Errorf("Expected the synthetic entry dropped, got %s", names)
+	}
+
+	// With nothing synthetic left, the effect is refused
+	if err := manager.CheckRoom(); !errors.Is(err, ErrStackFull) {
+		t.Errorf("Expected ErrStackFull, got %v", err)
+	}
+	if onTop, _ := manager.PushEffect("last", "effect=last", nil); onTop {
+		t.Error("Expected the effect to be refused")
+	}
+	if names := stackNames(manager); names != "stored,other,next" {
+		t.Errorf("Expected the stack unchanged, got %s", names)
+	}
+}
+
+func TestStackLimit_RejectKeepsEntries(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	manager := NewManager(broadcaster)
+	manager.PushEffect("first", "effect=first", nil)
+	manager.PushEffect("alert", "effect=alert", map[string]interface{}{"priority": 50})
+	manager.PushEffect("critical", "effect=critical", map[string]interface{}{"priority": 90})
+	manager.SetStackLimit(2, OverflowDropOldest)
+
+	sub := broadcaster.Subscribe("test")
+	defer broadcaster.Unsubscribe("test")
+
+	// Dropping 'first' would not make room, so it is kept
+	if onTop, stale := manager.PushEffect("next", "effect=next", nil); onTop || stale {
+		t.Errorf("Expected the effect to be refused, got onTop %v, stale %v", onTop, stale)
+	}
+	if names := stackNames(manager); names != "first,alert,critical" {
+		t.Errorf("Expected the stack unchanged, got %s", names)
+	}
+
+	select {
+	case event := <-sub.Channel:
+		dropped, _ := event.Data["dropped"].([]events.StackEntry)
+		if event.Data["rejected"] != true || len(dropped) != 0 {
+			t.Errorf("Expected a rejection dropping nothing, got %v", event.Data)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected stack_overflow event, got none")
+	}
+}
+
+func TestStackLimit_TopDropped(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	manager := NewManager(broadcaster)
+	manager.SetStackLimit(2, OverflowCollapseSynthetic)
+	manager.PushEffect("stored", "effect=stored", nil)
+	manager.PushEffect("display:B12", "effect=display", map[string]interface{}{"synthetic": true})
+
+	// The entry showing is dropped and the new one lands beneath 'stored',
+	// so the caller has to show 'stored'
+	if onTop, stale := manager.PushEffect("ambient", "effect=ambient", map[string]interface{}{"priority": -1}); onTop || !stale {
+		t.Errorf("Expected the effect beneath a stale top, got onTop %v, stale %v", onTop, stale)
+	}
+	if names := stackNames(manager); names != "ambient,stored" {
+		t.Errorf("Expected the synthetic entry dropped, got %s", names)
+	}
+	if state := manager.Snapshot(); state.Effect != "stored" {
+		t.Errorf("Expected current effect 'stored', got %s", state.Effect)
+	}
+
+	manager.SetStackLimit(3, OverflowCollapseSynthetic)
+	manager.PushEffect("display:C3", "effect=display", map[string]interface{}{"synthetic": true})
+	if onTop, stale := manager.InsertEffectBelowTop("calm", "effect=calm", nil); onTop || !stale {
+		t.Errorf("Expected the effect beneath a stale top, got onTop %v, stale %v", onTop, stale)
+	}
+	if names := stackNames(manager); names != "ambient,calm,stored" {
+		t.Errorf("Expected the synthetic entry dropped, got %s", names)
+	}
+}
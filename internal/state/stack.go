@@ -0,0 +1,277 @@
+package state
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NewInstanceID returns a random UUID for a new stack entry
+func NewInstanceID() string {
+	return uuid.NewString()
+}
+
+// InstanceID returns the ID that tells this stack entry apart from other
+// pushes of the same effect, or "" if it has none
+func (item EffectStackItem) InstanceID() string {
+	id, _ := item.Context["instanceId"].(string)
+	return id
+}
+
+// StartTime returns when the effect was pushed, or the zero time if unknown
+func (item EffectStackItem) StartTime() time.Time {
+	start, _ := item.Context["startTime"].(time.Time)
+	return start
+}
+
+// DurationMs returns the effect's configured duration; 0 means it runs until stopped
+func (item EffectStackItem) DurationMs() int {
+	switch v := item.Context["duration"].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// Perpetual reports whether the effect runs until stopped
+func (item EffectStackItem) Perpetual() bool {
+	perpetual, _ := item.Context["perpetual"].(bool)
+	return perpetual || item.DurationMs() <= 0
+}
+
+// Priority returns the effect's priority. Entries never sit above one with a
+// higher priority; ordinary effects have priority 0.
+func (item EffectStackItem) Priority() int {
+	switch v := item.Context["priority"].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// Paused reports whether the effect's countdown is suspended
+func (item EffectStackItem) Paused() bool {
+	paused, _ := item.Context["paused"].(bool)
+	return paused
+}
+
+// Elapsed returns how long the effect has been running at now, excluding
+// time spent paused
+func (item EffectStackItem) Elapsed(now time.Time) time.Duration {
+	start := item.StartTime()
+	if start.IsZero() {
+		return 0
+	}
+
+	pausedMs, _ := item.Context["pausedMs"].(int64)
+	end := now
+	if pausedAt, ok := item.Context["pausedAt"].(time.Time); ok && item.Paused() {
+		end = pausedAt
+	}
+
+	elapsed := end.Sub(start) - time.Duration(pausedMs)*time.Millisecond
+	if elapsed < 0 {
+		return 0
+	}
+	return elapsed
+}
+
+// Remaining returns the time left before a timed effect completes. The
+// second result is false for perpetual effects.
+func (item EffectStackItem) Remaining(now time.Time) (time.Duration, bool) {
+	if item.Perpetual() {
+		return 0, false
+	}
+
+	remaining := time.Duration(item.DurationMs())*time.Millisecond - item.Elapsed(now)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, true
+}
+
+// FindEffect returns the stack entry started at startTime, if it is still on
+// the stack
+func (m *Manager) FindEffect(startTime time.Time) *EffectStackItem {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for i := range m.effectStack {
+		if m.effectStack[i].StartTime().Equal(startTime) {
+			item := m.effectStack[i]
+			return &item
+		}
+	}
+	return nil
+}
+
+// FindInstance returns the stack entry with the given instance ID, if it is
+// still on the stack
+func (m *Manager) FindInstance(instanceID string) *EffectStackItem {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for i := range m.effectStack {
+		if m.effectStack[i].InstanceID() == instanceID {
+			item := m.effectStack[i]
+			return &item
+		}
+	}
+	return nil
+}
+
+// ReplaceEffect swaps the first stack entry matching match for item, keeping
+// its position. It reports whether an entry was replaced and whether it is
+// the top of the stack.
+func (m *Manager) ReplaceEffect(match func(item EffectStackItem) bool, item EffectStackItem) (bool, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.effectStack {
+		if match(m.effectStack[i]) {
+			m.effectStack[i] = item
+			isTop := i == len(m.effectStack)-1
+			if isTop {
+				m.state.Effect = item.Name
+			}
+			return true, isTop
+		}
+	}
+	return false, false
+}
+
+// InsertEffectBelowTop adds an effect directly beneath the top of the stack,
+// so it shows once the current effect ends without interrupting it. Higher
+// priority entries beneath the top stay above it as PushEffect would keep
+// them. On an empty stack the effect becomes the top; the return value
+// reports whether it did. A full stack makes room as for PushEffect, and
+// stale reports as for PushEffect that another entry must be shown.
+func (m *Manager) InsertEffectBelowTop(name, pattern string, context map[string]interface{}) (onTop, stale bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if context == nil {
+		context = map[string]interface{}{}
+	}
+	if _, ok := context["instanceId"].(string); !ok {
+		context["instanceId"] = NewInstanceID()
+	}
+	item := EffectStackItem{Name: name, Pattern: pattern, Context: context}
+	ok, topDropped := m.makeRoomUnsafe(item)
+	if !ok {
+		return false, false
+	}
+
+	if len(m.effectStack) == 0 {
+		m.effectStack = append(m.effectStack, item)
+		m.state.Effect = name
+		return true, false
+	}
+	position := len(m.effectStack) - 1
+	for position > 0 && m.effectStack[position-1].Priority() > item.Priority() {
+		position--
+	}
+	m.effectStack = slices.Insert(m.effectStack, position, item)
+	m.state.Effect = m.effectStack[len(m.effectStack)-1].Name
+	return false, topDropped
+}
+
+// SortEffects stably reorders the entries accepted by selected among the
+// positions they already occupy, so the greatest entry according to less
+// ends up highest. Other entries keep their positions. Returns true if the
+// top of the stack changed.
+func (m *Manager) SortEffects(selected func(item EffectStackItem) bool, less func(a, b EffectStackItem) bool) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var positions []int
+	var items []EffectStackItem
+	for i, item := range m.effectStack {
+		if selected(item) {
+			positions = append(positions, i)
+			items = append(items, item)
+		}
+	}
+	if len(items) < 2 {
+		return false
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return less(items[i], items[j]) })
+
+	topIndex := len(m.effectStack) - 1
+	topChanged := false
+	for n, i := range positions {
+		if i == topIndex && !items[n].StartTime().Equal(m.effectStack[i].StartTime()) {
+			topChanged = true
+			m.state.Effect = items[n].Name
+		}
+		m.effectStack[i] = items[n]
+	}
+	return topChanged
+}
+
+// PauseEffect suspends the countdown of the current effect
+func (m *Manager) PauseEffect() (*EffectStackItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.effectStack) == 0 {
+		return nil, fmt.Errorf("no effect is running")
+	}
+	top := &m.effectStack[len(m.effectStack)-1]
+	if top.Paused() {
+		return nil, fmt.Errorf("effect '%s' is already paused", top.Name)
+	}
+
+	context := copyContext(top.Context)
+	context["paused"] = true
+	context["pausedAt"] = time.Now()
+	top.Context = context
+
+	item := *top
+	return &item, nil
+}
+
+// ResumeEffect restarts the countdown of the current effect after a pause
+func (m *Manager) ResumeEffect() (*EffectStackItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.effectStack) == 0 {
+		return nil, fmt.Errorf("no effect is running")
+	}
+	top := &m.effectStack[len(m.effectStack)-1]
+	if !top.Paused() {
+		return nil, fmt.Errorf("effect '%s' is not paused", top.Name)
+	}
+
+	context := copyContext(top.Context)
+	pausedMs, _ := context["pausedMs"].(int64)
+	if pausedAt, ok := context["pausedAt"].(time.Time); ok {
+		pausedMs += time.Since(pausedAt).Milliseconds()
+	}
+	context["pausedMs"] = pausedMs
+	delete(context, "paused")
+	delete(context, "pausedAt")
+	top.Context = context
+
+	item := *top
+	return &item, nil
+}
+
+// copyContext copies a stack entry context so readers holding the old map
+// never observe a concurrent write
+func copyContext(context map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(context)+2)
+	for k, v := range context {
+		copied[k] = v
+	}
+	return copied
+}
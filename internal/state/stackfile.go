@@ -0,0 +1,178 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+)
+
+// stackFileSubscriberID is the broadcaster subscription used by the stack file
+const stackFileSubscriberID = "stack-file"
+
+// savedStackItem is the JSON form of an effect stack entry
+type savedStackItem struct {
+	Name    string                 `json:"name"`
+	Pattern string                 `json:"pattern"`
+	Context map[string]interface{} `json:"context,omitempty"`
+}
+
+// StackFile persists the effect stack so effects running when the server
+// stops can be resumed when it starts again
+type StackFile struct {
+	mu    sync.Mutex
+	file  string
+	saved []byte // last data written, to skip unchanged saves
+}
+
+// NewStackFile creates a stack file stored in filePath
+func NewStackFile(filePath string) *StackFile {
+	return &StackFile{file: filePath}
+}
+
+// Load reads the saved stack, bottom first. A missing file is an empty
+// stack. Context values come back as JSON decodes them, except the times,
+// counters and parameters the stack itself relies on; effect steps are left
+// for the caller to decode.
+func (f *StackFile) Load() ([]EffectStackItem, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := os.ReadFile(f.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading stack file: %w", err)
+	}
+
+	var saved []savedStackItem
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("parsing stack JSON: %w", err)
+	}
+	f.saved = data
+
+	stack := make([]EffectStackItem, len(saved))
+	for i, item := range saved {
+		context, err := decodeContext(item.Context)
+		if err != nil {
+			return nil, fmt.Errorf("stack entry %d (%s): %w", i, item.Name, err)
+		}
+		stack[i] = EffectStackItem{Name: item.Name, Pattern: item.Pattern, Context: context}
+	}
+	return stack, nil
+}
+
+// Save writes the stack unless it is unchanged since the last save
+func (f *StackFile) Save(stack []EffectStackItem) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	saved := make([]savedStackItem, len(stack))
+	for i, item := range stack {
+		saved[i] = savedStackItem{Name: item.Name, Pattern: item.Pattern, Context: item.Context}
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling stack: %w", err)
+	}
+	if bytes.Equal(data, f.saved) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.file), 0755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	if err := os.WriteFile(f.file, data, 0644); err != nil {
+		return err
+	}
+	f.saved = data
+	return nil
+}
+
+// Start saves the manager's stack after every published event until ctx is
+// cancelled or the broadcaster is closed. Failures to save are reported to
+// onError.
+func (f *StackFile) Start(ctx context.Context, broadcaster *events.Broadcaster, manager *Manager, onError func(error)) {
+	sub := broadcaster.Subscribe(stackFileSubscriberID)
+
+	go func() {
+		defer broadcaster.Unsubscribe(stackFileSubscriberID)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-sub.Channel:
+				if !ok {
+					return
+				}
+				if err := f.Save(manager.GetEffectStack()); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// decodeContext restores the Go types of the context values the stack
+// accessors and tools type-assert
+func decodeContext(context map[string]interface{}) (map[string]interface{}, error) {
+	decoded := make(map[string]interface{}, len(context))
+	for key, value := range context {
+		decoded[key] = value
+		switch key {
+		case "startTime", "pausedAt":
+			text, _ := value.(string)
+			t, err := time.Parse(time.RFC3339Nano, text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %v", key, value)
+			}
+			decoded[key] = t
+		case "duration", "priority", "sequenceStep":
+			if n, ok := value.(float64); ok && n == math.Trunc(n) {
+				decoded[key] = int(n)
+			}
+		case "pausedMs":
+			if n, ok := value.(float64); ok {
+				decoded[key] = int64(n)
+			}
+		case "params":
+			if values, ok := value.(map[string]interface{}); ok {
+				params := make(map[string]string, len(values))
+				for name, v := range values {
+					params[name] = fmt.Sprint(v)
+				}
+				decoded[key] = params
+			}
+		}
+	}
+	return decoded, nil
+}
+
+// RestoreStack replaces the effect stack with a saved one, bottom first.
+// Entries saved without an instance ID are given one.
+func (m *Manager) RestoreStack(stack []EffectStackItem) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.effectStack = append([]EffectStackItem(nil), stack...)
+	for i, item := range m.effectStack {
+		if item.InstanceID() == "" {
+			context := copyContext(item.Context)
+			context["instanceId"] = NewInstanceID()
+			m.effectStack[i].Context = context
+		}
+	}
+	if len(m.effectStack) > 0 {
+		m.state.Effect = m.effectStack[len(m.effectStack)-1].Name
+	} else {
+		m.state.Effect = ""
+	}
+}
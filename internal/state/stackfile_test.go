@@ -0,0 +1,69 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+)
+
+func TestStackFile_SaveAndLoad(t *testing.T) {
+	file := NewStackFile(filepath.Join(t.TempDir(), "effect-stack.json"))
+	stack, err := file.Load()
+	if err != nil || len(stack) != 0 {
+		t.Fatalf("Loading a missing stack file should return an empty stack, got %v, %v", stack, err)
+	}
+
+	startTime := time.Now().Add(-time.Minute).Round(0)
+	saved := []EffectStackItem{
+		{Name: "calm", Pattern: "top_init=1", Context: map[string]interface{}{"perpetual": true, "startTime": startTime}},
+		{Name: "alert:outage", Pattern: "top_bg=ff0000", Context: map[string]interface{}{
+			"duration":    60000,
+			"priority":    80,
+			"startTime":   startTime,
+			"pausedMs":    int64(1500),
+			"raisedAlert": "outage",
+			"params":      map[string]string{"color": "ff0000"},
+		}},
+	}
+	if err := file.Save(saved); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	stack, err = NewStackFile(file.file).Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(stack) != 2 || stack[1].Name != "alert:outage" {
+		t.Fatalf("Expected the saved stack back, got %+v", stack)
+	}
+
+	alert := stack[1]
+	if !alert.StartTime().Equal(startTime) {
+		t.Errorf("Expected start time %v, got %v", startTime, alert.StartTime())
+	}
+	if alert.DurationMs() != 60000 || alert.Priority() != 80 {
+		t.Errorf("Expected duration 60000 and priority 80, got %d and %d", alert.DurationMs(), alert.Priority())
+	}
+	if pausedMs, _ := alert.Context["pausedMs"].(int64); pausedMs != 1500 {
+		t.Errorf("Expected pausedMs 1500, got %v", alert.Context["pausedMs"])
+	}
+	if params, _ := alert.Context["params"].(map[string]string); params["color"] != "ff0000" {
+		t.Errorf("Expected params to be restored, got %v", alert.Context["params"])
+	}
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	manager := NewManager(broadcaster)
+	manager.RestoreStack(stack)
+	if manager.GetEffectStackDepth() != 2 || manager.Snapshot().Effect != "alert:outage" {
+		t.Errorf("Expected the restored stack with alert:outage on top, got %+v", manager.GetEffectStack())
+	}
+
+	// Entries saved without an instance ID are given one to be found by
+	restored := manager.GetEffectStack()[1]
+	if restored.InstanceID() == "" || manager.FindInstance(restored.InstanceID()) == nil {
+		t.Errorf("Expected the restored alert to have an instance ID, got %q", restored.InstanceID())
+	}
+}
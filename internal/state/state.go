@@ -2,8 +2,12 @@ package state
 
 import (
 	"encoding/json"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/starspace46/ufo-mcp-go/internal/device"
 	"github.com/starspace46/ufo-mcp-go/internal/events"
 )
 
@@ -18,8 +22,32 @@ type LedState struct {
 	// Animation state in milliseconds
 	TopWhirlMs    int        `json:"topWhirlMs,omitempty"`    // top ring rotation speed in ms
 	BottomWhirlMs int        `json:"bottomWhirlMs,omitempty"` // bottom ring rotation speed in ms
+	// Rotation direction of each ring while it whirls; clockwise otherwise
+	TopWhirlCounterClockwise    bool `json:"topWhirlCounterClockwise,omitempty"`
+	BottomWhirlCounterClockwise bool `json:"bottomWhirlCounterClockwise,omitempty"`
 	TopMorph      *MorphData `json:"topMorph,omitempty"`      // top ring morph settings
 	BottomMorph   *MorphData `json:"bottomMorph,omitempty"`   // bottom ring morph settings
+
+	LogoAnimation *LogoAnimation `json:"logoAnimation,omitempty"` // logo animation run by the server
+
+	// Brightness of each ring in percent, scaling its colors before they
+	// are sent; 0 means full brightness
+	TopBrightness    int `json:"topBrightness,omitempty"`
+	BottomBrightness int `json:"bottomBrightness,omitempty"`
+}
+
+// RingBrightness returns the brightness of each ring in percent, 100 when
+// the ring is not dimmed
+func (s LedState) RingBrightness() (top, bottom int) {
+	return fullWhenZero(s.TopBrightness), fullWhenZero(s.BottomBrightness)
+}
+
+// fullWhenZero returns 100 for an unset ring brightness
+func fullWhenZero(percent int) int {
+	if percent == 0 {
+		return 100
+	}
+	return percent
 }
 
 // MorphData represents morph animation settings in milliseconds
@@ -28,6 +56,22 @@ type MorphData struct {
 	FadeMs       int `json:"fadeMs"`       // fade transition duration
 }
 
+// Logo animation modes
+const (
+	LogoBlink     = "blink"     // switch between Color1 and Color2 (off by default)
+	LogoPulse     = "pulse"     // fade Color1 in and out
+	LogoAlternate = "alternate" // switch between Color1 and Color2
+)
+
+// LogoAnimation is a logo animation the server runs, since the firmware
+// only shows static logo colors
+type LogoAnimation struct {
+	Mode     string `json:"mode"`             // one of the Logo modes
+	Color1   string `json:"color1"`           // hex color
+	Color2   string `json:"color2,omitempty"` // hex color for blink and alternate
+	PeriodMs int    `json:"periodMs"`         // length of one cycle
+}
+
 // EffectStackItem represents an effect in the stack
 type EffectStackItem struct {
 	Name      string                 // Effect name
@@ -41,6 +85,8 @@ type Manager struct {
 	state       *LedState
 	effectStack []EffectStackItem
 	broadcaster *events.Broadcaster
+	maxDepth    int    // most entries on the effect stack; 0 means no limit
+	overflow    string // what a push past maxDepth does, one of OverflowPolicies
 }
 
 // NewManager creates a new state manager
@@ -73,9 +119,29 @@ func (m *Manager) Snapshot() *LedState {
 
 	// Create a deep copy
 	stateCopy := &LedState{
-		LogoOn: m.state.LogoOn,
-		Effect: m.state.Effect,
-		Dim:    m.state.Dim,
+		LogoOn:        m.state.LogoOn,
+		Effect:        m.state.Effect,
+		Dim:           m.state.Dim,
+		TopWhirlMs:    m.state.TopWhirlMs,
+		BottomWhirlMs: m.state.BottomWhirlMs,
+
+		TopWhirlCounterClockwise:    m.state.TopWhirlCounterClockwise,
+		BottomWhirlCounterClockwise: m.state.BottomWhirlCounterClockwise,
+
+		TopBrightness:    m.state.TopBrightness,
+		BottomBrightness: m.state.BottomBrightness,
+	}
+	if m.state.TopMorph != nil {
+		morph := *m.state.TopMorph
+		stateCopy.TopMorph = &morph
+	}
+	if m.state.BottomMorph != nil {
+		morph := *m.state.BottomMorph
+		stateCopy.BottomMorph = &morph
+	}
+	if m.state.LogoAnimation != nil {
+		animation := *m.state.LogoAnimation
+		stateCopy.LogoAnimation = &animation
 	}
 
 	// Copy arrays
@@ -104,6 +170,20 @@ func (m *Manager) UpdateLogo(on bool) {
 	m.state.LogoOn = on
 }
 
+// UpdateLogoAnimation records the logo animation the server runs, or nil
+// when the logo is static
+func (m *Manager) UpdateLogoAnimation(animation *LogoAnimation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if animation != nil {
+		copied := *animation
+		animation = &copied
+		m.state.LogoOn = true
+	}
+	m.state.LogoAnimation = animation
+}
+
 // UpdateRingSegments updates specific segments on a ring
 func (m *Manager) UpdateRingSegments(ring string, segments []string, background string) {
 	m.mu.Lock()
@@ -133,9 +213,10 @@ func (m *Manager) UpdateRingSegments(ring string, segments []string, background
 	}
 
 	// Emit ring update event
-	m.broadcaster.PublishRingUpdate(ring, map[string]interface{}{
-		"segments":   segments,
-		"background": background,
+	m.broadcaster.PublishRingUpdate(events.RingUpdate{
+		Ring:       ring,
+		Segments:   segments,
+		Background: background,
 	})
 }
 
@@ -166,16 +247,72 @@ func (m *Manager) Reset() {
 		m.state.Bottom[i] = "000000"
 	}
 	m.state.LogoOn = false
+	m.state.LogoAnimation = nil
 	m.state.Effect = ""
 	// Keep current brightness level
 
 	// Emit events for the reset
-	m.broadcaster.PublishRingUpdate("top", map[string]interface{}{
-		"reset": true,
-	})
-	m.broadcaster.PublishRingUpdate("bottom", map[string]interface{}{
-		"reset": true,
-	})
+	m.broadcaster.PublishRingUpdate(events.RingUpdate{Ring: "top", Reset: true})
+	m.broadcaster.PublishRingUpdate(events.RingUpdate{Ring: "bottom", Reset: true})
+}
+
+// SetLighting replaces the whole lighting with a saved one: both rings with
+// their whirl and morph, the logo and its animation, and the brightness. The
+// running effect is kept.
+func (m *Manager) SetLighting(lighting LedState) {
+	m.mu.Lock()
+	effect := m.state.Effect
+	dimChanged := m.state.Dim != lighting.Dim
+	m.state = &lighting
+	m.state.Effect = effect
+	if lighting.TopMorph != nil {
+		morph := *lighting.TopMorph
+		m.state.TopMorph = &morph
+	}
+	if lighting.BottomMorph != nil {
+		morph := *lighting.BottomMorph
+		m.state.BottomMorph = &morph
+	}
+	if lighting.LogoAnimation != nil {
+		animation := *lighting.LogoAnimation
+		m.state.LogoAnimation = &animation
+	}
+	m.mu.Unlock()
+
+	m.broadcaster.PublishRingUpdate(events.RingUpdate{Ring: "top", Colors: lighting.Top[:]})
+	m.broadcaster.PublishRingUpdate(events.RingUpdate{Ring: "bottom", Colors: lighting.Bottom[:]})
+	if dimChanged {
+		m.broadcaster.PublishDimChanged(lighting.Dim)
+	}
+}
+
+// RingBrightness returns the brightness of each ring in percent
+func (m *Manager) RingBrightness() (top, bottom int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.state.RingBrightness()
+}
+
+// SetRingBrightness sets the brightness of each ring in percent, 1-100.
+// Colors sent from then on are scaled to it, so set it before sending.
+func (m *Manager) SetRingBrightness(top, bottom int) {
+	m.mu.Lock()
+	oldTop, oldBottom := m.state.RingBrightness()
+	m.state.TopBrightness, m.state.BottomBrightness = top, bottom
+	if top == 100 {
+		m.state.TopBrightness = 0
+	}
+	if bottom == 100 {
+		m.state.BottomBrightness = 0
+	}
+	m.mu.Unlock()
+
+	if top != oldTop {
+		m.broadcaster.PublishRingUpdate(events.RingUpdate{Ring: "top", Brightness: top})
+	}
+	if bottom != oldBottom {
+		m.broadcaster.PublishRingUpdate(events.RingUpdate{Ring: "bottom", Brightness: bottom})
+	}
 }
 
 // SetActiveEffect updates the currently running effect
@@ -186,12 +323,27 @@ func (m *Manager) SetActiveEffect(effectName string) {
 	m.state.Effect = effectName
 }
 
-// ToJSON serializes the current state to JSON
+// ToJSON serializes the current state to JSON. While a timed effect is on
+// top of the stack it adds the effect's elapsed and remaining milliseconds,
+// so clients can draw a countdown.
 func (m *Manager) ToJSON() (string, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	data, err := json.Marshal(m.state)
+	view := struct {
+		*LedState
+		EffectElapsedMs   *int64 `json:"effectElapsedMs,omitempty"`
+		EffectRemainingMs *int64 `json:"effectRemainingMs,omitempty"`
+	}{LedState: m.state}
+	if n := len(m.effectStack); n > 0 {
+		top, now := m.effectStack[n-1], time.Now()
+		if !top.Perpetual() {
+			elapsedMs := top.Elapsed(now).Milliseconds()
+			remainingMs := max(int64(top.DurationMs())-elapsedMs, 0)
+			view.EffectElapsedMs, view.EffectRemainingMs = &elapsedMs, &remainingMs
+		}
+	}
+	data, err := json.Marshal(view)
 	if err != nil {
 		return "", err
 	}
@@ -199,20 +351,45 @@ func (m *Manager) ToJSON() (string, error) {
 	return string(data), nil
 }
 
-// PushEffect pushes a new effect onto the stack
-func (m *Manager) PushEffect(name, pattern string, context map[string]interface{}) {
+// PushEffect pushes a new effect onto the stack. An effect never lands above
+// an entry with a higher "priority" in its context, so a high priority alert
+// stays on top until it ends. A context without an "instanceId" is given a
+// new one. A full stack makes room as its overflow policy says, or refuses
+// the effect. onTop reports whether the new effect is on top. stale reports
+// that making room dropped the entry that was showing and another entry is
+// now on top, which the caller must show.
+func (m *Manager) PushEffect(name, pattern string, context map[string]interface{}) (onTop, stale bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Add to stack
-	m.effectStack = append(m.effectStack, EffectStackItem{
+	if context == nil {
+		context = map[string]interface{}{}
+	}
+	if _, ok := context["instanceId"].(string); !ok {
+		context["instanceId"] = NewInstanceID()
+	}
+
+	item := EffectStackItem{
 		Name:    name,
 		Pattern: pattern,
 		Context: context,
-	})
+	}
+	ok, topDropped := m.makeRoomUnsafe(item)
+	if !ok {
+		return false, false
+	}
+
+	// Insert below any higher priority entries
+	position := len(m.effectStack)
+	for position > 0 && m.effectStack[position-1].Priority() > item.Priority() {
+		position--
+	}
+	m.effectStack = slices.Insert(m.effectStack, position, item)
 
 	// Update current effect
-	m.state.Effect = name
+	m.state.Effect = m.effectStack[len(m.effectStack)-1].Name
+	onTop = position == len(m.effectStack)-1
+	return onTop, topDropped && !onTop
 }
 
 // PopEffect removes the current effect from the stack and returns the new current effect
@@ -260,6 +437,46 @@ func (m *Manager) GetEffectStackDepth() int {
 	return len(m.effectStack)
 }
 
+// GetEffectStack returns a copy of the effect stack, bottom first
+func (m *Manager) GetEffectStack() []EffectStackItem {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stack := make([]EffectStackItem, len(m.effectStack))
+	copy(stack, m.effectStack)
+	return stack
+}
+
+// RemoveEffects removes every stack entry for which match returns true,
+// wherever it sits in the stack. It reports how many entries were removed
+// and whether the top of the stack changed as a result.
+func (m *Manager) RemoveEffects(match func(item EffectStackItem) bool) (int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.effectStack) == 0 {
+		return 0, false
+	}
+	topRemoved := match(m.effectStack[len(m.effectStack)-1])
+
+	kept := m.effectStack[:0]
+	for _, item := range m.effectStack {
+		if !match(item) {
+			kept = append(kept, item)
+		}
+	}
+	removed := len(m.effectStack) - len(kept)
+	m.effectStack = kept
+
+	if len(m.effectStack) > 0 {
+		m.state.Effect = m.effectStack[len(m.effectStack)-1].Name
+	} else {
+		m.state.Effect = ""
+	}
+
+	return removed, topRemoved
+}
+
 // UpdateTopRing updates all LEDs on the top ring
 func (m *Manager) UpdateTopRing(colors []string) {
 	m.mu.Lock()
@@ -280,9 +497,7 @@ func (m *Manager) UpdateTopRing(colors []string) {
 	}
 
 	// Emit ring update event
-	m.broadcaster.PublishRingUpdate("top", map[string]interface{}{
-		"colors": colors,
-	})
+	m.broadcaster.PublishRingUpdate(events.RingUpdate{Ring: "top", Colors: colors})
 }
 
 // UpdateBottomRing updates all LEDs on the bottom ring
@@ -305,48 +520,117 @@ func (m *Manager) UpdateBottomRing(colors []string) {
 	}
 
 	// Emit ring update event
-	m.broadcaster.PublishRingUpdate("bottom", map[string]interface{}{
-		"colors": colors,
-	})
+	m.broadcaster.PublishRingUpdate(events.RingUpdate{Ring: "bottom", Colors: colors})
 }
 
-// ParseRingCommand parses a ring pattern command and updates state accordingly
-// This handles patterns like "top_init=1&top=ff0000|00ff00|0000ff&top_bg=ffffff"
-func (m *Manager) ParseRingCommand(ring string, query string) {
-	// This is a simplified parser - in production you'd want more robust parsing
-	// For now, we'll just handle basic color updates
-	
-	// Extract segments from the query
-	segments := extractSegments(query, ring)
-	background := extractBackground(query, ring)
-	
-	if len(segments) > 0 || background != "" {
-		m.UpdateRingSegments(ring, segments, background)
+// ApplyQuery records what a raw UFO query changes: the LEDs of the rings
+// it draws on, their whirl and morph (init stops both, as the firmware
+// does), the brightness and the logo. Parameters the firmware ignores are
+// ignored here too. An invalid query changes nothing. It returns the names
+// of the parts of the state that changed.
+func (m *Manager) ApplyQuery(query string) ([]string, error) {
+	pattern, err := device.ParsePattern(query)
+	if err != nil {
+		return nil, err
 	}
-}
 
-// Helper function to extract segment colors from query
-func extractSegments(query, ring string) []string {
-	// This is a placeholder - implement proper parsing based on UFO API format
-	// For now, return empty to avoid errors
-	return []string{}
-}
+	m.mu.Lock()
+	current := device.Frame{Top: m.state.Top, Bottom: m.state.Bottom}
+	drawn, err := current.Apply(query)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
 
-// Helper function to extract background color from query
-func extractBackground(query, ring string) string {
-	// This is a placeholder - implement proper parsing based on UFO API format
-	return ""
+	var changed []string
+	var ringUpdates []string
+	for _, ring := range []struct {
+		name    string
+		pattern *device.RingPattern
+		leds    *[15]string
+		drawn   [15]string
+		whirlMs *int
+		ccw     *bool
+		morph   **MorphData
+	}{
+		{"top", pattern.Top, &m.state.Top, drawn.Top, &m.state.TopWhirlMs, &m.state.TopWhirlCounterClockwise, &m.state.TopMorph},
+		{"bottom", pattern.Bottom, &m.state.Bottom, drawn.Bottom, &m.state.BottomWhirlMs, &m.state.BottomWhirlCounterClockwise, &m.state.BottomMorph},
+	} {
+		if ring.pattern == nil {
+			continue
+		}
+		if *ring.leds != ring.drawn {
+			*ring.leds = ring.drawn
+			changed = append(changed, ring.name)
+			ringUpdates = append(ringUpdates, ring.name)
+		}
+		whirlMs, ccw, morph := *ring.whirlMs, *ring.ccw, *ring.morph
+		if ring.pattern.Init {
+			whirlMs, ccw, morph = 0, false, nil
+		}
+		if ring.pattern.Whirl != nil {
+			whirlMs, ccw = ring.pattern.Whirl.Ms, ring.pattern.Whirl.CounterClockwise && ring.pattern.Whirl.Ms > 0
+		}
+		if ring.pattern.Morph != nil {
+			morph = &MorphData{BrightnessMs: ring.pattern.Morph.BrightnessMs, FadeMs: ring.pattern.Morph.FadeMs}
+		}
+		if whirlMs != *ring.whirlMs || ccw != *ring.ccw {
+			*ring.whirlMs, *ring.ccw = whirlMs, ccw
+			changed = append(changed, ring.name+"Whirl")
+		}
+		if !sameMorph(morph, *ring.morph) {
+			*ring.morph = morph
+			changed = append(changed, ring.name+"Morph")
+		}
+	}
+
+	dimChanged := false
+	if pattern.Dim != nil && *pattern.Dim != m.state.Dim {
+		m.state.Dim = *pattern.Dim
+		dimChanged = true
+		changed = append(changed, "dim")
+	}
+	if pattern.Logo != "" {
+		on := pattern.Logo == "on"
+		if pattern.Logo != "off" && !on {
+			for _, color := range strings.Split(pattern.Logo, "|") {
+				on = on || color != "000000"
+			}
+		}
+		if on != m.state.LogoOn {
+			m.state.LogoOn = on
+			changed = append(changed, "logo")
+		}
+	}
+	snapshot := *m.state
+	m.mu.Unlock()
+
+	for _, ring := range ringUpdates {
+		leds := snapshot.Top
+		if ring == "bottom" {
+			leds = snapshot.Bottom
+		}
+		m.broadcaster.PublishRingUpdate(events.RingUpdate{Ring: ring, Colors: leds[:], Query: query})
+	}
+	if dimChanged {
+		m.broadcaster.PublishDimChanged(snapshot.Dim)
+	}
+	return changed, nil
 }
 
-// UpdateWhirl updates the whirl (rotation) speed for a ring
-func (m *Manager) UpdateWhirl(ring string, speedMs int) {
+// UpdateWhirl updates the whirl (rotation) speed and direction for a ring.
+// A ring that stops whirling has no direction.
+func (m *Manager) UpdateWhirl(ring string, speedMs int, counterClockwise bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	counterClockwise = counterClockwise && speedMs > 0
 	if ring == "top" {
 		m.state.TopWhirlMs = speedMs
+		m.state.TopWhirlCounterClockwise = counterClockwise
 	} else if ring == "bottom" {
 		m.state.BottomWhirlMs = speedMs
+		m.state.BottomWhirlCounterClockwise = counterClockwise
 	}
 }
 
@@ -367,6 +651,18 @@ func (m *Manager) UpdateMorph(ring string, brightnessMs, fadeMs int) {
 	}
 }
 
+// ClearMorph stops the morph of a ring, as initializing the ring does
+func (m *Manager) ClearMorph(ring string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if ring == "top" {
+		m.state.TopMorph = nil
+	} else if ring == "bottom" {
+		m.state.BottomMorph = nil
+	}
+}
+
 // ClearAnimations clears all animation settings
 func (m *Manager) ClearAnimations() {
 	m.mu.Lock()
@@ -374,6 +670,68 @@ func (m *Manager) ClearAnimations() {
 
 	m.state.TopWhirlMs = 0
 	m.state.BottomWhirlMs = 0
+	m.state.TopWhirlCounterClockwise = false
+	m.state.BottomWhirlCounterClockwise = false
 	m.state.TopMorph = nil
 	m.state.BottomMorph = nil
-}
\ No newline at end of file
+}
+
+// Observed represents the LED state reported by the device. Nil fields are
+// unknown and are left untouched during reconciliation.
+type Observed struct {
+	Top    []string
+	Bottom []string
+	Dim    *int
+	LogoOn *bool
+}
+
+// Reconcile compares the observed device state with the shadow state,
+// adopts the device values where they differ and returns the names of the
+// drifted fields. A state_reconciled event is emitted when drift is found.
+func (m *Manager) Reconcile(observed Observed) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var drifted []string
+
+	if reconcileRing(&m.state.Top, observed.Top) {
+		drifted = append(drifted, "top")
+	}
+	if reconcileRing(&m.state.Bottom, observed.Bottom) {
+		drifted = append(drifted, "bottom")
+	}
+	if observed.Dim != nil && *observed.Dim != m.state.Dim {
+		m.state.Dim = *observed.Dim
+		drifted = append(drifted, "dim")
+	}
+	if observed.LogoOn != nil && *observed.LogoOn != m.state.LogoOn {
+		m.state.LogoOn = *observed.LogoOn
+		drifted = append(drifted, "logo")
+	}
+
+	if len(drifted) > 0 {
+		m.broadcaster.PublishStateReconciled(drifted)
+	}
+
+	return drifted
+}
+
+// reconcileRing copies observed colors into the ring and reports whether anything changed
+func reconcileRing(ring *[15]string, observed []string) bool {
+	if observed == nil {
+		return false
+	}
+
+	changed := false
+	for i := 0; i < 15; i++ {
+		color := "000000"
+		if i < len(observed) && observed[i] != "" {
+			color = observed[i]
+		}
+		if !strings.EqualFold(ring[i], color) {
+			ring[i] = color
+			changed = true
+		}
+	}
+	return changed
+}
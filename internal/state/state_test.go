@@ -2,6 +2,7 @@ package state
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -298,4 +299,331 @@ func TestConcurrency(t *testing.T) {
 	}
 
 	// If we get here without deadlock or panic, concurrency is working
-}
\ No newline at end of file
+}
+
+func TestReconcile(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	manager := NewManager(broadcaster)
+	sub := broadcaster.Subscribe("test")
+	defer broadcaster.Unsubscribe("test")
+
+	// Matching state should not report drift
+	dim := 255
+	if drifted := manager.Reconcile(Observed{Dim: &dim}); len(drifted) != 0 {
+		t.Errorf("Expected no drift, got %v", drifted)
+	}
+
+	// Device reports a different ring and brightness
+	dim = 80
+	logo := true
+	top := []string{"ff0000", "ff0000"}
+	drifted := manager.Reconcile(Observed{Top: top, Dim: &dim, LogoOn: &logo})
+	if len(drifted) != 3 {
+		t.Fatalf("Expected 3 drifted fields, got %v", drifted)
+	}
+
+	state := manager.Snapshot()
+	if state.Top[0] != "ff0000" || state.Top[2] != "000000" {
+		t.Errorf("Top ring not reconciled: %v", state.Top)
+	}
+	if state.Dim != 80 {
+		t.Errorf("Expected brightness 80, got %d", state.Dim)
+	}
+	if !state.LogoOn {
+		t.Error("Expected logo to be on after reconcile")
+	}
+
+	select {
+	case event := <-sub.Channel:
+		if event.Type != events.EventStateReconciled {
+			t.Errorf("Expected state_reconciled event, got %s", event.Type)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected state_reconciled event, got none")
+	}
+}
+
+func TestRemoveEffects(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	manager := NewManager(broadcaster)
+	manager.PushEffect("base", "effect=base", nil)
+	manager.PushEffect("alert", "effect=alert", map[string]interface{}{"source": "grafana"})
+	manager.PushEffect("top", "effect=top", nil)
+
+	isAlert := func(item EffectStackItem) bool { return item.Context["source"] == "grafana" }
+
+	// Removing a buried entry leaves the top untouched
+	removed, topChanged := manager.RemoveEffects(isAlert)
+	if removed != 1 || topChanged {
+		t.Errorf("Expected 1 buried entry removed, got removed=%d topChanged=%v", removed, topChanged)
+	}
+	stack := manager.GetEffectStack()
+	if len(stack) != 2 || stack[0].Name != "base" || stack[1].Name != "top" {
+		t.Errorf("Unexpected stack after removal: %+v", stack)
+	}
+
+	// Removing the top entry reports the change and updates the current effect
+	removed, topChanged = manager.RemoveEffects(func(item EffectStackItem) bool { return item.Name == "top" })
+	if removed != 1 || !topChanged {
+		t.Errorf("Expected top entry removed, got removed=%d topChanged=%v", removed, topChanged)
+	}
+	if state := manager.Snapshot(); state.Effect != "base" {
+		t.Errorf("Expected current effect 'base', got %s", state.Effect)
+	}
+}
+
+func TestSortEffects(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	manager := NewManager(broadcaster)
+	start := time.Now()
+	push := func(name string, rank int, offset time.Duration) {
+		manager.PushEffect(name, "effect="+name, map[string]interface{}{"rank": rank, "startTime": start.Add(offset)})
+	}
+	push("low", 1, 0)
+	manager.PushEffect("user", "effect=user", nil)
+	push("high", 3, time.Second)
+	push("mid", 2, 2*time.Second)
+
+	hasRank := func(item EffectStackItem) bool { _, ok := item.Context["rank"]; return ok }
+	byRank := func(a, b EffectStackItem) bool { return a.Context["rank"].(int) < b.Context["rank"].(int) }
+
+	if !manager.SortEffects(hasRank, byRank) {
+		t.Error("Expected top to change")
+	}
+	var names []string
+	for _, item := range manager.GetEffectStack() {
+		names = append(names, item.Name)
+	}
+	if strings.Join(names, ",") != "low,user,mid,high" {
+		t.Errorf("Unexpected order: %v", names)
+	}
+	if state := manager.Snapshot(); state.Effect != "high" {
+		t.Errorf("Expected current effect 'high', got %s", state.Effect)
+	}
+
+	if manager.SortEffects(hasRank, byRank) {
+		t.Error("Expected sorted stack to be unchanged")
+	}
+}
+
+func TestPushEffect_Priority(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	manager := NewManager(broadcaster)
+	if onTop, _ := manager.PushEffect("base", "effect=base", nil); !onTop {
+		t.Error("Expected first effect on top")
+	}
+	if onTop, _ := manager.PushEffect("alert", "effect=alert", map[string]interface{}{"priority": 50}); !onTop {
+		t.Error("Expected alert on top")
+	}
+
+	// Lower priority pushes land beneath the alert
+	if onTop, _ := manager.PushEffect("user", "effect=user", nil); onTop {
+		t.Error("Expected ordinary effect to be pushed beneath the alert")
+	}
+	if state := manager.Snapshot(); state.Effect != "alert" {
+		t.Errorf("Expected current effect 'alert', got %s", state.Effect)
+	}
+
+	// Equal or higher priority pushes go on top
+	if onTop, _ := manager.PushEffect("critical", "effect=critical", map[string]interface{}{"priority": 90}); !onTop {
+		t.Error("Expected higher priority alert on top")
+	}
+	if onTop, _ := manager.PushEffect("warning", "effect=warning", map[string]interface{}{"priority": 50}); onTop {
+		t.Error("Expected lower priority alert beneath the critical one")
+	}
+
+	var names []string
+	for _, item := range manager.GetEffectStack() {
+		names = append(names, item.Name)
+	}
+	if strings.Join(names, ",") != "base,user,alert,warning,critical" {
+		t.Errorf("Unexpected order: %v", names)
+	}
+}
+
+func TestPushEffect_InstanceID(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	manager := NewManager(broadcaster)
+	manager.PushEffect("rainbow", "effect=rainbow", nil)
+	manager.PushEffect("rainbow", "effect=rainbow", map[string]interface{}{})
+	manager.PushEffect("rainbow", "effect=rainbow", map[string]interface{}{"instanceId": "given"})
+
+	stack := manager.GetEffectStack()
+	if stack[0].InstanceID() == "" || stack[0].InstanceID() == stack[1].InstanceID() {
+		t.Errorf("Expected distinct instance IDs, got %q and %q", stack[0].InstanceID(), stack[1].InstanceID())
+	}
+	if stack[2].InstanceID() != "given" {
+		t.Errorf("Expected the given instance ID to be kept, got %q", stack[2].InstanceID())
+	}
+}
+
+func TestInsertEffectBelowTop(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	manager := NewManager(broadcaster)
+	if onTop, _ := manager.InsertEffectBelowTop("ambient", "effect=ambient", nil); !onTop {
+		t.Error("Expected the only effect on top")
+	}
+	manager.PushEffect("alert", "effect=alert", map[string]interface{}{"priority": 50})
+
+	if onTop, _ := manager.InsertEffectBelowTop("calm", "effect=calm", nil); onTop {
+		t.Error("Expected the effect beneath the top")
+	}
+	if state := manager.Snapshot(); state.Effect != "alert" {
+		t.Errorf("Expected current effect 'alert', got %s", state.Effect)
+	}
+
+	// Higher priority entries beneath the top keep their place above it
+	manager.PushEffect("critical", "effect=critical", map[string]interface{}{"priority": 90})
+	manager.InsertEffectBelowTop("ambient2", "effect=ambient2", nil)
+
+	var names []string
+	for _, item := range manager.GetEffectStack() {
+		names = append(names, item.Name)
+		if item.InstanceID() == "" {
+			t.Errorf("Expected an instance ID for %s", item.Name)
+		}
+	}
+	if strings.Join(names, ",") != "ambient,calm,ambient2,alert,critical" {
+		t.Errorf("Unexpected order: %v", names)
+	}
+}
+
+func TestManager_ApplyQuery(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	manager := NewManager(broadcaster)
+
+	changed, err := manager.ApplyQuery("top_init=1&top_bg=0000FF&top=13|4|00FF00&top_whirl=200&bottom=0|2|ff0000&bottom_morph=300|5&dim=80&logo=on&effect=rainbow")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(changed, ","); got != "top,topWhirl,bottom,bottomMorph,dim,logo" {
+		t.Errorf("changed = %s", got)
+	}
+	snapshot := manager.Snapshot()
+	if snapshot.Top[0] != "00ff00" || snapshot.Top[2] != "0000ff" || snapshot.Top[14] != "00ff00" {
+		t.Errorf("unexpected top ring %v", snapshot.Top)
+	}
+	if snapshot.Bottom[1] != "ff0000" || snapshot.Bottom[2] != "000000" {
+		t.Errorf("unexpected bottom ring %v", snapshot.Bottom)
+	}
+	if snapshot.TopWhirlMs != 200 || snapshot.BottomMorph == nil || snapshot.BottomMorph.FadeMs != 666 {
+		t.Errorf("unexpected animations: whirl %d, morph %+v", snapshot.TopWhirlMs, snapshot.BottomMorph)
+	}
+	if snapshot.Dim != 80 || !snapshot.LogoOn {
+		t.Errorf("dim = %d, logo on = %v", snapshot.Dim, snapshot.LogoOn)
+	}
+
+	// Segments draw over what the ring shows; init stops its animations
+	if _, err := manager.ApplyQuery("top=0|1|ffffff&logo=000000"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	snapshot = manager.Snapshot()
+	if snapshot.Top[0] != "ffffff" || snapshot.Top[14] != "00ff00" || snapshot.TopWhirlMs != 200 || snapshot.LogoOn {
+		t.Errorf("unexpected state after segments: %v whirl %d logo %v", snapshot.Top, snapshot.TopWhirlMs, snapshot.LogoOn)
+	}
+	// A direction change alone is a whirl change
+	changed, err = manager.ApplyQuery("top_whirl=200|ccw")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(changed, ","); got != "topWhirl" || !manager.Snapshot().TopWhirlCounterClockwise {
+		t.Errorf("expected the top ring turning counter-clockwise, changed = %s", got)
+	}
+	if _, err := manager.ApplyQuery("top_init=1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snapshot = manager.Snapshot(); snapshot.Top[0] != "000000" || snapshot.TopWhirlMs != 0 || snapshot.TopWhirlCounterClockwise {
+		t.Errorf("init should clear the ring and its whirl: %v whirl %d ccw %v", snapshot.Top, snapshot.TopWhirlMs, snapshot.TopWhirlCounterClockwise)
+	}
+
+	// An invalid query changes nothing
+	if _, err := manager.ApplyQuery("bottom_init=1&top_whirl=999"); err == nil {
+		t.Error("expected an error for an invalid whirl")
+	}
+	if snapshot = manager.Snapshot(); snapshot.Bottom[1] != "ff0000" {
+		t.Errorf("invalid query changed the bottom ring: %v", snapshot.Bottom)
+	}
+}
+
+func TestManager_RingBrightness(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	manager := NewManager(broadcaster)
+
+	if top, bottom := manager.RingBrightness(); top != 100 || bottom != 100 {
+		t.Errorf("expected both rings at full brightness, got %d and %d", top, bottom)
+	}
+
+	manager.SetRingBrightness(100, 30)
+	snapshot := manager.Snapshot()
+	if snapshot.TopBrightness != 0 || snapshot.BottomBrightness != 30 {
+		t.Errorf("expected only the bottom ring dimmed, got %+v", snapshot)
+	}
+	encoded, _ := json.Marshal(snapshot)
+	if strings.Contains(string(encoded), "topBrightness") || !strings.Contains(string(encoded), `"bottomBrightness":30`) {
+		t.Errorf("expected full brightness left out of the JSON, got %s", encoded)
+	}
+
+	// Restoring lighting brings its ring brightness back
+	manager.SetLighting(LedState{Dim: 255, TopBrightness: 50})
+	if top, bottom := manager.RingBrightness(); top != 50 || bottom != 100 {
+		t.Errorf("expected the restored ring brightness, got %d and %d", top, bottom)
+	}
+}
+
+func TestToJSON_Countdown(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	manager := NewManager(broadcaster)
+	manager.PushEffect("forever", "effect=forever", map[string]interface{}{"perpetual": true})
+
+	var countdown struct {
+		ElapsedMs   *int64 `json:"effectElapsedMs"`
+		RemainingMs *int64 `json:"effectRemainingMs"`
+	}
+	jsonStr, err := manager.ToJSON()
+	if err != nil {
+		t.Fatalf("Failed to convert to JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &countdown); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if countdown.ElapsedMs != nil || countdown.RemainingMs != nil {
+		t.Errorf("Expected no countdown for a perpetual effect, got %s", jsonStr)
+	}
+
+	manager.PushEffect("timed", "effect=timed", map[string]interface{}{
+		"duration":  10000,
+		"startTime": time.Now().Add(-4 * time.Second),
+	})
+	jsonStr, err = manager.ToJSON()
+	if err != nil {
+		t.Fatalf("Failed to convert to JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &countdown); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if countdown.ElapsedMs == nil || countdown.RemainingMs == nil {
+		t.Fatalf("Expected a countdown for a timed effect, got %s", jsonStr)
+	}
+	if *countdown.ElapsedMs < 4000 || *countdown.RemainingMs > 6000 || *countdown.ElapsedMs+*countdown.RemainingMs != 10000 {
+		t.Errorf("Unexpected countdown: %d ms elapsed, %d ms remaining", *countdown.ElapsedMs, *countdown.RemainingMs)
+	}
+	if !strings.Contains(jsonStr, `"effect":"timed"`) {
+		t.Errorf("Expected the state fields too, got %s", jsonStr)
+	}
+}
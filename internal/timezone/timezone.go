@@ -0,0 +1,59 @@
+package timezone
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// location is the configured time zone; nil means the server's local zone
+var location atomic.Pointer[time.Location]
+
+// Load resolves an IANA time zone name such as "Europe/Vienna". An empty
+// name or "Local" is the server's local zone.
+func Load(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "Local" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// Set makes loc the zone used for schedules and timestamps
+func Set(loc *time.Location) {
+	location.Store(loc)
+}
+
+// Location returns the configured time zone
+func Location() *time.Location {
+	if loc := location.Load(); loc != nil {
+		return loc
+	}
+	return time.Local
+}
+
+// Now returns the current time in the configured time zone
+func Now() time.Time {
+	return time.Now().In(Location())
+}
+
+// In converts t to the configured time zone
+func In(t time.Time) time.Time {
+	return t.In(Location())
+}
+
+// Clock formats t as a wall clock time for people, e.g. "14:05:09 CET"
+func Clock(t time.Time) string {
+	return In(t).Format("15:04:05 MST")
+}
+
+// ISO formats t as an ISO-8601 timestamp with its offset for machine
+// outputs, e.g. "2024-03-01T14:05:09+01:00"
+func ISO(t time.Time) string {
+	return In(t).Format(time.RFC3339)
+}
@@ -0,0 +1,40 @@
+package timezone
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	loc, err := Load("")
+	if err != nil || loc != time.Local {
+		t.Errorf("expected the local zone for an empty name, got %v, %v", loc, err)
+	}
+	loc, err = Load("America/New_York")
+	if err != nil || loc.String() != "America/New_York" {
+		t.Errorf("expected America/New_York, got %v, %v", loc, err)
+	}
+	if _, err := Load("Mars/Olympus_Mons"); err == nil {
+		t.Error("expected an error for an unknown zone")
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	defer Set(nil)
+	loc, err := Load("Europe/Vienna")
+	if err != nil {
+		t.Fatalf("loading zone: %v", err)
+	}
+	Set(loc)
+
+	instant := time.Date(2024, 3, 1, 13, 5, 9, 0, time.UTC)
+	if got := Clock(instant); got != "14:05:09 CET" {
+		t.Errorf("Clock = %q, want 14:05:09 CET", got)
+	}
+	if got := ISO(instant); got != "2024-03-01T14:05:09+01:00" {
+		t.Errorf("ISO = %q, want 2024-03-01T14:05:09+01:00", got)
+	}
+	if Now().Location() != loc {
+		t.Error("Now should be in the configured zone")
+	}
+}
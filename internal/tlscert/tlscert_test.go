@@ -0,0 +1,82 @@
+package tlscert
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSelfSigned(t *testing.T) {
+	certificate, err := SelfSigned([]string{"localhost", "127.0.0.1", "ufo.example"})
+	if err != nil {
+		t.Fatalf("generating certificate: %v", err)
+	}
+	if got := certificate.Leaf.DNSNames; len(got) != 2 || got[0] != "localhost" || got[1] != "ufo.example" {
+		t.Errorf("expected DNS names localhost and ufo.example, got %v", got)
+	}
+	if len(certificate.Leaf.IPAddresses) != 1 || certificate.Leaf.IPAddresses[0].String() != "127.0.0.1" {
+		t.Errorf("expected IP address 127.0.0.1, got %v", certificate.Leaf.IPAddresses)
+	}
+
+	// A client trusting the certificate can connect to a server using it
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{certificate}}
+	server.StartTLS()
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(certificate.Leaf)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	response, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected the certificate to verify, got %v", err)
+	}
+	response.Body.Close()
+}
+
+func TestConfig(t *testing.T) {
+	certificate, err := SelfSigned([]string{"localhost"})
+	if err != nil {
+		t.Fatalf("generating certificate: %v", err)
+	}
+	key, err := x509.MarshalPKCS8PrivateKey(certificate.PrivateKey)
+	if err != nil {
+		t.Fatalf("encoding key: %v", err)
+	}
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Certificate[0]}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}), 0o600)
+
+	config, err := Config(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("loading certificate: %v", err)
+	}
+	if config.MinVersion != tls.VersionTLS12 {
+		t.Errorf("expected TLS 1.2 at least, got %x", config.MinVersion)
+	}
+	fingerprint := Fingerprint(config)
+	if len(fingerprint) != 32*3-1 || strings.Count(fingerprint, ":") != 31 {
+		t.Errorf("expected a colon-separated SHA-256 fingerprint, got %q", fingerprint)
+	}
+
+	// The key must belong to the certificate
+	other, _ := SelfSigned([]string{"localhost"})
+	otherKey, _ := x509.MarshalPKCS8PrivateKey(other.PrivateKey)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: otherKey}), 0o600)
+	if _, err := Config(certFile, keyFile); err == nil {
+		t.Error("expected an error for a key of another certificate")
+	}
+	if _, err := Config(filepath.Join(dir, "missing.pem"), keyFile); err == nil {
+		t.Error("expected an error for a missing certificate")
+	}
+}
@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+)
+
+// AckIncidentLightTool implements the ackIncidentLight MCP tool
+type AckIncidentLightTool struct {
+	pagerDuty *integrations.PagerDuty
+}
+
+// NewAckIncidentLightTool creates a new ackIncidentLight tool instance
+func NewAckIncidentLightTool(pagerDuty *integrations.PagerDuty) *AckIncidentLightTool {
+	return &AckIncidentLightTool{
+		pagerDuty: pagerDuty,
+	}
+}
+
+// Definition returns the MCP tool definition for ackIncidentLight
+func (t *AckIncidentLightTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "ackIncidentLight",
+		Description: "Acknowledge a PagerDuty incident shown on the UFO. Acknowledges the incident in PagerDuty and switches its light to the acknowledged state. Without incidentId, the most recent triggered incident is acknowledged.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"incidentId": map[string]interface{}{
+					"type":        "string",
+					"description": "PagerDuty incident ID (e.g., 'PT4KHLK'). Optional, defaults to the latest triggered incident.",
+				},
+			},
+		},
+	}
+}
+
+// Execute runs the ackIncidentLight tool
+func (t *AckIncidentLightTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	id := ""
+	if value, exists := arguments["incidentId"]; exists {
+		str, ok := value.(string)
+		if !ok {
+			return invalidArgument("incidentId", "'incidentId' must be a string"), nil
+		}
+		id = str
+	}
+
+	incident, err := t.pagerDuty.Acknowledge(ctx, id)
+	if err != nil {
+		return toolError(CodeDeviceError, fmt.Sprintf("Failed to acknowledge incident: %v", err)), nil
+	}
+
+	message := fmt.Sprintf("✅ Acknowledged PagerDuty incident %s", incident.ID)
+	if incident.Title != "" {
+		message += fmt.Sprintf(" (%s)", incident.Title)
+	}
+	if incident.HTMLURL != "" {
+		message += "\n" + incident.HTMLURL
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+)
+
+// AddBindingTool implements the addBinding MCP tool
+type AddBindingTool struct {
+	bindings *integrations.Bindings
+}
+
+// NewAddBindingTool creates a new addBinding tool instance
+func NewAddBindingTool(bindings *integrations.Bindings) *AddBindingTool {
+	return &AddBindingTool{
+		bindings: bindings,
+	}
+}
+
+// Definition returns the MCP tool definition for addBinding
+func (t *AddBindingTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "addBinding",
+		Description: "Add or replace a poller binding that drives the UFO from any HTTP JSON API. The URL is polled, a value is extracted with a JSONPath expression, and the first matching rule's effect or color is shown. When no rule matches the binding's light is cleared. Bindings added here last until the server restarts.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Unique binding name; an existing binding with this name is replaced",
+				},
+				"url": map[string]interface{}{
+					"type":        "string",
+					"description": "URL returning JSON (e.g. 'https://www.githubstatus.com/api/v2/status.json')",
+				},
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "JSONPath to the value (e.g. '$.status.indicator', '$.items[0].state', \"$['build-status']\")",
+				},
+				"headers": map[string]interface{}{
+					"type":        "object",
+					"description": "Optional request headers (e.g. {\"Authorization\": \"Bearer ...\"})",
+				},
+				"pollIntervalMs": map[string]interface{}{
+					"type":        "number",
+					"description": "Poll interval in milliseconds (minimum 1000, default 30000)",
+				},
+				"rules": map[string]interface{}{
+					"type":        "array",
+					"description": "Rules checked in order. Each may set equals (exact value), min/max (numeric range, inclusive) and match (regular expression); all set conditions must hold and a rule with none matches anything. Each rule shows an 'effect' name or a 'color' on an optional 'zone' (top, bottom, all).",
+					"items": map[string]interface{}{
+						"type": "object",
+					},
+				},
+			},
+			Required: []string{"name", "url", "path", "rules"},
+		},
+	}
+}
+
+// Execute runs the addBinding tool
+func (t *AddBindingTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	// Decode the arguments through JSON so rules use the configuration file format
+	var binding integrations.Binding
+	data, err := json.Marshal(arguments)
+	if err == nil {
+		err = json.Unmarshal(data, &binding)
+	}
+	if err != nil {
+		return toolError(CodeInvalidArgument, fmt.Sprintf("Invalid binding: %v", err)), nil
+	}
+
+	if err := t.bindings.Add(binding); err != nil {
+		return toolError(CodeInvalidArgument, fmt.Sprintf("%v", err)), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("✅ Binding '%s' added: polling %s for %s with %d rules", binding.Name, binding.URL, binding.Path, len(binding.Rules)),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindingTools(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	store := effects.NewStore(t.TempDir() + "/effects.json")
+	display := integrations.NewDisplay(broadcaster, store, stateManager, nil)
+	bindings := integrations.NewBindings(display, nil)
+
+	addTool := NewAddBindingTool(bindings)
+	listTool := NewListBindingsTool(bindings)
+	removeTool := NewRemoveBindingTool(bindings)
+
+	result, err := addTool.Execute(context.Background(), map[string]interface{}{
+		"name":           "status",
+		"url":            "http://example.invalid/status.json",
+		"path":           "$.status.indicator",
+		"pollIntervalMs": float64(60000),
+		"rules": []interface{}{
+			map[string]interface{}{"equals": "major", "color": "red"},
+		},
+	})
+	require.NoError(t, err)
+	assert.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+
+	result, err = addTool.Execute(context.Background(), map[string]interface{}{
+		"name": "broken",
+		"url":  "http://example.invalid",
+		"path": "status",
+		"rules": []interface{}{
+			map[string]interface{}{"color": "red"},
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	result, err = addTool.Execute(context.Background(), map[string]interface{}{
+		"name":  "broken",
+		"rules": "not a list",
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	result, err = listTool.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	var listed []integrations.BindingStatus
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &listed))
+	require.Len(t, listed, 1)
+	assert.Equal(t, "status", listed[0].Name)
+	assert.Equal(t, 60000, listed[0].PollIntervalMs)
+
+	result, err = removeTool.Execute(context.Background(), map[string]interface{}{"name": "status"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Empty(t, bindings.List())
+
+	result, err = removeTool.Execute(context.Background(), map[string]interface{}{"name": "status"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/starspace46/ufo-mcp-go/internal/effects"
@@ -24,7 +25,7 @@ func NewAddEffectTool(store *effects.Store) *AddEffectTool {
 func (t *AddEffectTool) Definition() mcp.Tool {
 	return mcp.Tool{
 		Name:        "addEffect",
-		Description: "Add a new custom lighting effect. The effect will be persisted to the effects database. Name must be unique.",
+		Description: "Add a new custom lighting effect. The effect will be persisted to the effects database. Name must be unique. Patterns can contain {name} placeholders declared in 'params' to make a template that playEffect fills in, e.g. one pulse effect for any color.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -44,6 +45,28 @@ func (t *AddEffectTool) Definition() mcp.Tool {
 					"type":        "number",
 					"description": "Duration in milliseconds (0-3600000, 0 means infinite)",
 				},
+				"category": map[string]interface{}{
+					"type":        "string",
+					"description": "Group for listEffects filters, e.g. 'alerts', 'ambient' or 'status' (optional)",
+				},
+				"tags": map[string]interface{}{
+					"type":        "array",
+					"description": "Labels for listEffects filters, e.g. [\"red\", \"incident\"]; stored lowercase (optional)",
+					"items":       map[string]interface{}{"type": "string"},
+				},
+				"params": map[string]interface{}{
+					"type":        "array",
+					"description": "Parameters for {name} placeholders in the pattern (optional), e.g. [{\"name\": \"color\", \"default\": \"FF0000\"}] with pattern 'top_init=1&top=0|15|{color}'",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"name":        map[string]interface{}{"type": "string", "description": "Placeholder name, used as {name} in the pattern"},
+							"description": map[string]interface{}{"type": "string", "description": "What the value controls, e.g. 'hex color'"},
+							"default":     map[string]interface{}{"type": "string", "description": "Value used when playEffect gives none (optional; the parameter is required without one)"},
+						},
+						"required": []string{"name"},
+					},
+				},
 			},
 			Required: []string{"name", "description", "pattern"},
 		},
@@ -55,70 +78,30 @@ func (t *AddEffectTool) Execute(ctx context.Context, arguments map[string]interf
 	// Extract and validate name
 	name, ok := arguments["name"].(string)
 	if !ok || name == "" {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'name' parameter is required and must be a non-empty string",
-				},
-			},
-			IsError: true,
-		}, nil
+		return missingArgument("name", "'name' parameter is required and must be a non-empty string"), nil
 	}
 
 	// Validate name format (alphanumeric + underscore)
 	if !isValidEffectName(name) {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: Effect name must contain only letters, numbers, and underscores",
-				},
-			},
-			IsError: true,
-		}, nil
+		return invalidArgument("name", "Effect name must contain only letters, numbers, and underscores"), nil
 	}
 
 	// Check if effect already exists
 	_, exists := t.store.Get(name)
 	if exists {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error: Effect '%s' already exists. Use updateEffect to modify it.", name),
-				},
-			},
-			IsError: true,
-		}, nil
+		return ToolError{Code: CodeConflict, Message: fmt.Sprintf("Effect '%s' already exists", name), Parameter: "name", Hint: "Use updateEffect to modify it."}.Result(), nil
 	}
 
 	// Extract description
 	description, ok := arguments["description"].(string)
 	if !ok || description == "" {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'description' parameter is required and must be a non-empty string",
-				},
-			},
-			IsError: true,
-		}, nil
+		return missingArgument("description", "'description' parameter is required and must be a non-empty string"), nil
 	}
 
 	// Extract pattern
 	pattern, ok := arguments["pattern"].(string)
 	if !ok || pattern == "" {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'pattern' parameter is required and must be a non-empty string",
-				},
-			},
-			IsError: true,
-		}, nil
+		return missingArgument("pattern", "'pattern' parameter is required and must be a non-empty string"), nil
 	}
 
 	// Extract duration (optional, defaults to 0)
@@ -130,29 +113,28 @@ func (t *AddEffectTool) Execute(ctx context.Context, arguments map[string]interf
 		case int:
 			duration = v
 		default:
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Error: 'duration' must be a number",
-					},
-				},
-				IsError: true,
-			}, nil
+			return invalidArgument("duration", "'duration' must be a number"), nil
 		}
 	}
 
 	// Validate duration range
 	if duration < 0 || duration > 3600000 {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'duration' must be between 0 and 3600000 milliseconds (1 hour)",
-				},
-			},
-			IsError: true,
-		}, nil
+		return invalidArgument("duration", "'duration' must be between 0 and 3600000 milliseconds (1 hour)"), nil
+	}
+
+	// Extract template parameters (optional)
+	var params []effects.Param
+	if paramsVal, exists := arguments["params"]; exists {
+		var err error
+		if params, err = parseEffectParams(paramsVal); err != nil {
+			return toolError(CodeInvalidArgument, err.Error()), nil
+		}
+	}
+
+	// Extract the category and tags (optional)
+	category, tags, err := parseEffectLabels(arguments)
+	if err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
 	}
 
 	// Create the new effect
@@ -161,22 +143,24 @@ func (t *AddEffectTool) Execute(ctx context.Context, arguments map[string]interf
 		Description: description,
 		Pattern:     pattern,
 		Duration:    duration,
+		Params:      params,
+		Category:    category,
+		Tags:        tags,
+	}
+
+	// Check the pattern against the UFO query grammar
+	if err := effects.ValidatePatterns(newEffect); err != nil {
+		return invalidArgument("pattern", fmt.Sprintf("Invalid pattern: %v", err)), nil
 	}
 
 	// Add to store
-	t.store.Add(newEffect)
+	if err := t.store.Add(newEffect); err != nil {
+		return toolError(CodeInvalidArgument, fmt.Sprintf("Failed to add effect: %v", err)), nil
+	}
 
 	// Save to disk
 	if err := t.store.Save(); err != nil {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error: Failed to save effect: %v", err),
-				},
-			},
-			IsError: true,
-		}, nil
+		return toolError(CodeInvalidArgument, fmt.Sprintf("Failed to save effect: %v", err)), nil
 	}
 
 	// Success message
@@ -185,10 +169,14 @@ func (t *AddEffectTool) Execute(ctx context.Context, arguments map[string]interf
 	message += fmt.Sprintf("• Name: %s\n", name)
 	message += fmt.Sprintf("• Description: %s\n", description)
 	message += fmt.Sprintf("• Pattern: %s\n", pattern)
-	message += fmt.Sprintf("• Duration: %d seconds", duration)
+	message += fmt.Sprintf("• Duration: %dms", duration)
 	if duration == 0 {
 		message += " (infinite)"
 	}
+	if len(params) > 0 {
+		message += fmt.Sprintf("\n• Parameters: %s", formatEffectParams(params))
+	}
+	message += formatEffectLabels(newEffect)
 	message += "\n\nYou can now use playEffect to activate this effect."
 
 	return &mcp.CallToolResult{
@@ -210,4 +198,91 @@ func isValidEffectName(name string) bool {
 		}
 	}
 	return true
-}
\ No newline at end of file
+}
+
+// parseEffectParams converts the params argument of addEffect and
+// updateEffect to effect parameters
+func parseEffectParams(value interface{}) ([]effects.Param, error) {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'params' must be an array of parameter objects")
+	}
+	params := make([]effects.Param, 0, len(list))
+	for i, item := range list {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("params[%d] must be an object", i)
+		}
+		var param effects.Param
+		for key, target := range map[string]*string{"name": &param.Name, "description": &param.Description, "default": &param.Default} {
+			raw, exists := fields[key]
+			if !exists {
+				continue
+			}
+			text, ok := raw.(string)
+			if !ok {
+				return nil, fmt.Errorf("params[%d].%s must be a string", i, key)
+			}
+			*target = text
+		}
+		if param.Name == "" {
+			return nil, fmt.Errorf("params[%d].name is required", i)
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// parseEffectLabels reads the optional category and tags arguments of
+// addEffect and updateEffect
+func parseEffectLabels(arguments map[string]interface{}) (string, []string, error) {
+	var category string
+	if value, exists := arguments["category"]; exists {
+		text, ok := value.(string)
+		if !ok {
+			return "", nil, fmt.Errorf("'category' must be a string")
+		}
+		category = strings.TrimSpace(text)
+	}
+	var tags []string
+	if value, exists := arguments["tags"]; exists {
+		list, ok := value.([]interface{})
+		if !ok {
+			return "", nil, fmt.Errorf("'tags' must be an array of strings")
+		}
+		tags = make([]string, 0, len(list))
+		for i, item := range list {
+			tag, ok := item.(string)
+			if !ok || strings.TrimSpace(tag) == "" {
+				return "", nil, fmt.Errorf("tags[%d] must be a non-empty string", i)
+			}
+			tags = append(tags, tag)
+		}
+	}
+	return category, tags, nil
+}
+
+// formatEffectLabels lists an effect's category and tags for the addEffect
+// and updateEffect replies
+func formatEffectLabels(effect *effects.Effect) string {
+	var message string
+	if effect.Category != "" {
+		message += fmt.Sprintf("\n• Category: %s", effect.Category)
+	}
+	if len(effect.Tags) > 0 {
+		message += fmt.Sprintf("\n• Tags: %s", strings.Join(effect.Tags, ", "))
+	}
+	return message
+}
+
+// formatEffectParams lists parameters as {name} with their defaults
+func formatEffectParams(params []effects.Param) string {
+	parts := make([]string, len(params))
+	for i, param := range params {
+		parts[i] = "{" + param.Name + "}"
+		if param.Default != "" {
+			parts[i] += " (default " + param.Default + ")"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -81,10 +81,21 @@ func TestAddEffectTool_Execute_Success(t *testing.T) {
 			arguments: map[string]interface{}{
 				"name":        "complexEffect",
 				"description": "Complex multi-ring effect",
-				"pattern":     "top=0|5|FF0000|10|5|00FF00&bottom_whirl=400|ccw&logo=1",
+				"pattern":     "top=0|5|FF0000|10|5|00FF00&bottom_whirl=400|ccw&logo=on",
 				"duration":    120,
 			},
 		},
+		{
+			name: "add template effect",
+			arguments: map[string]interface{}{
+				"name":        "templateEffect",
+				"description": "Pulse in any color",
+				"pattern":     "top_init=1&top=0|15|{color}&top_morph=300|5",
+				"params": []interface{}{
+					map[string]interface{}{"name": "color", "description": "hex color", "default": "FF0000"},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -231,10 +242,10 @@ func TestAddEffectTool_Execute_Errors(t *testing.T) {
 				"name":        "testEffect",
 				"description": "Test",
 				"pattern":     "test=1",
-				"duration":    4000, // > 3600
+				"duration":    4000000, // > 3600000
 			},
 			expectError: true,
-			expectText:  "must be between 0 and 3600 seconds",
+			expectText:  "must be between 0 and 3600000 milliseconds",
 		},
 	}
 
@@ -290,4 +301,31 @@ func TestIsValidEffectName(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestAddEffectTool_Execute_UndeclaredPlaceholder(t *testing.T) {
+	tmpFile := "/tmp/test-add-effects-params.json"
+	defer os.Remove(tmpFile)
+
+	store := effects.NewStore(tmpFile)
+	store.Load()
+	tool := NewAddEffectTool(store)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"name":        "badTemplate",
+		"description": "Uses an undeclared placeholder",
+		"pattern":     "top=0|15|{color}",
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected an error result")
+	}
+	if !strings.Contains(result.Content[0].(mcp.TextContent).Text, "{color}") {
+		t.Errorf("expected the placeholder in the error, got %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if _, exists := store.Get("badTemplate"); exists {
+		t.Error("invalid effect should not be stored")
+	}
+}
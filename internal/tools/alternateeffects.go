@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// AlternateEffectsTool implements the alternateEffects MCP tool, which shows
+// two effects in turn as a single stack entry
+type AlternateEffectsTool struct {
+	broadcaster  *events.Broadcaster
+	store        *effects.Store
+	stateManager *state.Manager
+	engine       *effects.Engine
+	restores     *Restores
+}
+
+// NewAlternateEffectsTool creates a new alternateEffects tool instance
+func NewAlternateEffectsTool(broadcaster *events.Broadcaster, store *effects.Store, stateManager *state.Manager, engine *effects.Engine, restores *Restores) *AlternateEffectsTool {
+	return &AlternateEffectsTool{
+		broadcaster:  broadcaster,
+		store:        store,
+		stateManager: stateManager,
+		engine:       engine,
+		restores:     restores,
+	}
+}
+
+// Definition returns the MCP tool definition for alternateEffects
+func (t *AlternateEffectsTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "alternateEffects",
+		Description: "Alternate between two effects, e.g. an ambient effect for 50 seconds then a status display for 10 seconds, over and over. Both run as one entry on the effect stack, so one stopEffect call stops them, and pausing or covering it with another effect works as for any effect. Template effects use their parameter defaults.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"first": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the effect shown first",
+				},
+				"firstMs": map[string]interface{}{
+					"type":        "integer",
+					"description": "How long the first effect shows each turn, in milliseconds",
+					"minimum":     50,
+				},
+				"second": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the effect shown second",
+				},
+				"secondMs": map[string]interface{}{
+					"type":        "integer",
+					"description": "How long the second effect shows each turn, in milliseconds",
+					"minimum":     50,
+				},
+				"duration": map[string]interface{}{
+					"type":        "number",
+					"description": "How long to alternate in milliseconds (optional, default until stopped)",
+				},
+			},
+			Required: []string{"first", "firstMs", "second", "secondMs"},
+		},
+	}
+}
+
+// Execute runs the alternateEffects tool
+func (t *AlternateEffectsTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var turns [2]*effects.Effect
+	var turnMs [2]int
+	for i, key := range []string{"first", "second"} {
+		name, _ := arguments[key].(string)
+		if name == "" {
+			return invalidArgument(key, fmt.Sprintf("'%s' must be the name of an effect", key)), nil
+		}
+		effect, exists := t.store.Get(name)
+		if !exists {
+			return effectNotFound(key, name), nil
+		}
+		effect, err := effect.Render(nil)
+		if err != nil {
+			return toolError(CodeInvalidArgument, fmt.Sprintf("%s: %v", name, err)), nil
+		}
+		ms, ok := wholeNumber(arguments[key+"Ms"])
+		if !ok {
+			return invalidArgument(key+"Ms", fmt.Sprintf("'%sMs' must be a whole number of milliseconds", key)), nil
+		}
+		turns[i], turnMs[i] = effect, ms
+	}
+	steps, err := effects.Alternate(turns[0], turns[1], turnMs[0], turnMs[1])
+	if err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+
+	duration := 0
+	if value, exists := arguments["duration"]; exists {
+		ms, ok := value.(float64)
+		if !ok || ms < 0 || ms > 3600000 {
+			return invalidArgument("duration", "'duration' must be between 0 and 3600000 milliseconds (1 hour)"), nil
+		}
+		duration = int(ms)
+	}
+	name := turns[0].Name + "/" + turns[1].Name
+	if full := stackFull(t.stateManager); full != nil {
+		return full, nil
+	}
+
+	// A raised alert holds the UFO; the alternation then waits beneath it
+	alert := activeAlert(t.stateManager)
+	var warning string
+	if alert == nil {
+		reply, err := t.engine.ApplyWithReply(ctx, name, "", steps)
+		if err != nil {
+			return toolError(CodeDeviceError, fmt.Sprintf("Failed to send effect to UFO: %v", err)), nil
+		}
+		if warning, err = device.CheckReply(reply); err != nil {
+			t.engine.Stop()
+			if t.stateManager.GetCurrentEffect() != nil {
+				if restoreErr := restoreTop(ctx, t.engine, t.broadcaster, t.stateManager); restoreErr != nil {
+					slog.WarnContext(ctx, "Failed to restore effect after UFO error", "error", restoreErr)
+				}
+			}
+			return toolError(CodeDeviceError, fmt.Sprintf("'%s' was not shown: %v\n\nPattern sent: %s", turns[0].Name, err, steps[0].Pattern)), nil
+		}
+	}
+
+	startTime := time.Now()
+	effectContext := map[string]interface{}{
+		"instanceId": state.NewInstanceID(),
+		"duration":   duration,
+		"perpetual":  duration == 0,
+		"startTime":  startTime,
+		"steps":      steps,
+		"alternate":  []string{turns[0].Name, turns[1].Name},
+		"synthetic":  true,
+	}
+	_, stale := t.stateManager.PushEffect(name, steps[0].Pattern, effectContext)
+	restoreStale(ctx, stale, t.engine, t.broadcaster, t.stateManager)
+
+	started := events.EffectStarted{
+		Effect:     name,
+		InstanceID: effectContext["instanceId"].(string),
+		Duration:   duration,
+		Pattern:    steps[0].Pattern,
+		Steps:      len(steps),
+		Alternate:  []string{turns[0].Name, turns[1].Name},
+		Warning:    warning,
+		StackDepth: t.stateManager.GetEffectStackDepth(),
+	}
+	if alert != nil {
+		started.BeneathAlert = alert.Name
+	}
+	t.broadcaster.PublishPayload(ctx, started)
+
+	message := fmt.Sprintf("🔁 Alternating '%s' and '%s'\n\n", turns[0].Name, turns[1].Name)
+	if alert != nil {
+		message = fmt.Sprintf("⏳ Alternation of '%s' and '%s' queued beneath alert '%s' and will show when the alert is cleared or expires.\n\n", turns[0].Name, turns[1].Name, alert.Name)
+	}
+	message += fmt.Sprintf("• Instance: %s\n", effectContext["instanceId"])
+	message += fmt.Sprintf("• '%s' for %s, then '%s' for %s\n", turns[0].Name, format.Millis(int64(turnMs[0])), turns[1].Name, format.Millis(int64(turnMs[1])))
+	if duration > 0 {
+		message += fmt.Sprintf("• Duration: %s\n", format.Millis(int64(duration)))
+		message += fmt.Sprintf("• Will stop at: %s\n", format.Time(startTime.Add(time.Duration(duration)*time.Millisecond)))
+	} else {
+		message += "• Duration: Perpetual (use stopEffect to stop both)\n"
+	}
+	if warning != "" {
+		message += fmt.Sprintf("\n⚠️ Warning: %s. Check that the UFO shows the effect.", warning)
+	}
+
+	if duration > 0 {
+		t.restores.scheduleCompletion(ctx, effectContext["instanceId"].(string))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlternateEffectsTool_Execute(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+	sent := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), queries...)
+	}
+
+	store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	require.NoError(t, store.Add(&effects.Effect{Name: "ambient", Description: "Ambient", Pattern: "top_init=1&top=0|15|000033", Perpetual: true}))
+	require.NoError(t, store.Add(&effects.Effect{Name: "status", Description: "Status", Pattern: "top_init=1&top=0|15|00FF00", Duration: 10000}))
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	client := device.NewClient()
+	engine := effects.NewEngine(client)
+	defer engine.Shutdown(context.Background())
+	tool := NewAlternateEffectsTool(broadcaster, store, stateManager, engine, NewRestores(engine, broadcaster, stateManager))
+	stopTool := NewStopEffectTool(client, broadcaster, stateManager, engine)
+
+	t.Run("Definition", func(t *testing.T) {
+		def := tool.Definition()
+		assert.Equal(t, "alternateEffects", def.Name)
+		assert.ElementsMatch(t, []string{"first", "firstMs", "second", "secondMs"}, def.InputSchema.Required)
+	})
+
+	t.Run("AlternatesAsOneEntry", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"first":    "ambient",
+			"firstMs":  float64(150),
+			"second":   "status",
+			"secondMs": float64(100),
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Perpetual")
+
+		stack := stateManager.GetEffectStack()
+		require.Len(t, stack, 1)
+		assert.Equal(t, "ambient/status", stack[0].Name)
+		assert.True(t, stack[0].Perpetual())
+		assert.Len(t, effects.StepsFromContext(stack[0].Context), 2)
+
+		require.Eventually(t, func() bool {
+			return len(sent()) >= 3
+		}, 2*time.Second, 10*time.Millisecond)
+		q := sent()
+		assert.Equal(t, "top_init=1&top=0|15|000033", q[0])
+		assert.Equal(t, "top_init=1&top=0|15|00FF00", q[1])
+		assert.Equal(t, "top_init=1&top=0|15|000033", q[2])
+
+		// One stopEffect stops both
+		result, err = stopTool.Execute(context.Background(), map[string]interface{}{})
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		assert.Empty(t, stateManager.GetEffectStack())
+		assert.Equal(t, "", engine.Running())
+	})
+
+	t.Run("Timed", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"first":    "status",
+			"firstMs":  float64(1000),
+			"second":   "ambient",
+			"secondMs": float64(1000),
+			"duration": float64(200),
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+		require.Eventually(t, func() bool {
+			return stateManager.GetEffectStackDepth() == 0
+		}, 2*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			arguments map[string]interface{}
+			message   string
+		}{
+			{"MissingFirst", map[string]interface{}{"second": "status", "firstMs": float64(1000), "secondMs": float64(1000)}, "'first'"},
+			{"UnknownSecond", map[string]interface{}{"first": "ambient", "second": "nope", "firstMs": float64(1000), "secondMs": float64(1000)}, "'nope' not found"},
+			{"MissingInterval", map[string]interface{}{"first": "ambient", "second": "status", "firstMs": float64(1000)}, "'secondMs'"},
+			{"ShortInterval", map[string]interface{}{"first": "ambient", "second": "status", "firstMs": float64(10), "secondMs": float64(1000)}, "at least 50ms"},
+			{"BadDuration", map[string]interface{}{"first": "ambient", "second": "status", "firstMs": float64(1000), "secondMs": float64(1000), "duration": "long"}, "'duration'"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := tool.Execute(context.Background(), tt.arguments)
+				require.NoError(t, err)
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.message)
+			})
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/scenes"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// ApplySceneTool implements the applyScene MCP tool
+type ApplySceneTool struct {
+	store        *scenes.Store
+	engine       *effects.Engine
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+}
+
+// NewApplySceneTool creates a new applyScene tool instance
+func NewApplySceneTool(store *scenes.Store, engine *effects.Engine, broadcaster *events.Broadcaster, stateManager *state.Manager) *ApplySceneTool {
+	return &ApplySceneTool{
+		store:        store,
+		engine:       engine,
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+	}
+}
+
+// Definition returns the MCP tool definition for applyScene
+func (t *ApplySceneTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "applyScene",
+		Description: "Show a scene saved with saveScene, replacing the UFO's whole lighting: both rings with their whirl and morph, the logo and its animation, and the brightness. The scene becomes the base state that getLedState reports. Effects draw over the lighting, so stop running effects first.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the scene, from listScenes",
+				},
+			},
+			Required: []string{"name"},
+		},
+	}
+}
+
+// Execute runs the applyScene tool
+func (t *ApplySceneTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, ok := arguments["name"].(string)
+	if !ok || strings.TrimSpace(name) == "" {
+		return missingArgument("name", "'name' parameter is required and must be a non-empty string"), nil
+	}
+	scene, exists := t.store.Get(name)
+	if !exists {
+		return ToolError{Code: CodeNotFound, Message: fmt.Sprintf("no scene named '%s'", name), Parameter: "name", Hint: "Use listScenes to see the saved scenes"}.Result(), nil
+	}
+	if current := t.stateManager.GetCurrentEffect(); current != nil {
+		return toolError(CodeConflict, fmt.Sprintf("effect '%s' is running and would draw over the scene; stop it with stopEffect or stopAllEffects first", current.Name)), nil
+	}
+
+	lighting := scene.Lighting
+	query, err := shadowQuery(&lighting, t.stateManager.Snapshot())
+	if err != nil {
+		return toolError(CodeConflict, fmt.Sprintf("scene '%s' cannot be shown: %v", scene.Name, err)), nil
+	}
+	query = strings.Trim(query+fmt.Sprintf("&dim=%d", lighting.Dim), "&")
+
+	// The scene's logo replaces a running logo animation, and its rings are
+	// scaled to its ring brightness as they are sent
+	t.engine.StopLogo()
+	top, bottom := t.stateManager.RingBrightness()
+	t.stateManager.SetRingBrightness(lighting.RingBrightness())
+	if err := t.engine.Apply(ctx, "", query, nil); err != nil {
+		t.stateManager.SetRingBrightness(top, bottom)
+		t.broadcaster.PublishRawExecuted(ctx, query, fmt.Sprintf("ERROR: %v", err))
+		return toolError(CodeDeviceError, fmt.Sprintf("Failed to apply scene: %v", err)), nil
+	}
+	t.broadcaster.PublishRawExecuted(ctx, query, "OK")
+	t.stateManager.SetLighting(lighting)
+	if lighting.LogoAnimation != nil {
+		t.engine.AnimateLogo(logoSteps(*lighting.LogoAnimation))
+	}
+
+	message := fmt.Sprintf("🎬 Applied scene '%s'\n", scene.Name)
+	if scene.Description != "" {
+		message += scene.Description + "\n"
+	}
+	for _, line := range describeLighting(lighting) {
+		message += "• " + line + "\n"
+	}
+	message += "\nQuery: " + query
+	return sceneResult(message), nil
+}
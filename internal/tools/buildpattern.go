@@ -0,0 +1,338 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/color"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+)
+
+// defaultBuildPreviewMs is how much of a built pattern is previewed
+const defaultBuildPreviewMs = 2000
+
+// BuildPatternTool implements the buildPattern MCP tool, which compiles a
+// description of zones, colors and motion into a query without sending it
+type BuildPatternTool struct{}
+
+// NewBuildPatternTool creates a new buildPattern tool instance
+func NewBuildPatternTool() *BuildPatternTool {
+	return &BuildPatternTool{}
+}
+
+// patternBuild collects the compiled query parts and the outcome of every
+// check made on the way
+type patternBuild struct {
+	queries []string
+	checks  []string
+	errors  int
+}
+
+func (b *patternBuild) pass(format string, args ...interface{}) {
+	b.checks = append(b.checks, "✓ "+fmt.Sprintf(format, args...))
+}
+
+func (b *patternBuild) warn(format string, args ...interface{}) {
+	b.checks = append(b.checks, "⚠ "+fmt.Sprintf(format, args...))
+}
+
+func (b *patternBuild) fail(format string, args ...interface{}) {
+	b.checks = append(b.checks, "✗ "+fmt.Sprintf(format, args...))
+	b.errors++
+}
+
+// Definition returns the MCP tool definition for buildPattern
+func (t *BuildPatternTool) Definition() mcp.Tool {
+	ringSchema := func(name string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "object",
+			"description": fmt.Sprintf("The %s ring: a background, colored zones drawn over it in order, and motion", name),
+			"properties": map[string]interface{}{
+				"background": map[string]interface{}{
+					"type":        "string",
+					"description": "Color of LEDs no zone covers (optional, default off)",
+				},
+				"zones": map[string]interface{}{
+					"type":        "array",
+					"description": fmt.Sprintf("Runs of LEDs in one color, from LED 'start' (0-%d) for 'count' LEDs or up to LED 'end', wrapping past the last LED", device.RingLEDs-1),
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"start": map[string]interface{}{"type": "integer", "minimum": 0, "maximum": device.RingLEDs - 1},
+							"count": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": device.RingLEDs},
+							"end":   map[string]interface{}{"type": "integer", "minimum": 0, "maximum": device.RingLEDs - 1},
+							"color": map[string]interface{}{"type": "string"},
+						},
+						"required": []string{"start", "color"},
+					},
+				},
+				"motion": map[string]interface{}{
+					"type":        "object",
+					"description": "How the ring moves; rotate and pulse can be combined (optional, default still)",
+					"properties": map[string]interface{}{
+						"rotate": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"periodSeconds":    map[string]interface{}{"type": "number", "description": "Seconds for one full turn"},
+								"counterClockwise": map[string]interface{}{"type": "boolean"},
+							},
+							"required": []string{"periodSeconds"},
+						},
+						"pulse": map[string]interface{}{
+							"type":        "object",
+							"description": "Fade the zones to the background and back",
+							"properties": map[string]interface{}{
+								"holdMs": map[string]interface{}{"type": "integer", "minimum": 0, "description": "Time at full color"},
+								"fadeMs": map[string]interface{}{"type": "integer", "minimum": 100, "maximum": 10000, "description": "Time to fade each way"},
+							},
+							"required": []string{"holdMs", "fadeMs"},
+						},
+					},
+				},
+			},
+		}
+	}
+	return mcp.Tool{
+		Name:        "buildPattern",
+		Description: "Compose a UFO pattern from zones, colors and motion and get back the compiled query, a check of every part and a preview, without sending anything to the UFO. Play the result with sendRawApi or save it with addEffect once it looks right.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"top":    ringSchema("top"),
+				"bottom": ringSchema("bottom"),
+				"logo": map[string]interface{}{
+					"type": "string",
+					"enum": []string{"on", "off"},
+				},
+				"brightness": map[string]interface{}{
+					"type":    "integer",
+					"minimum": 0,
+					"maximum": 255,
+				},
+				"previewMs": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("How much of the pattern to preview in milliseconds, 0 for none (optional, default %d)", defaultBuildPreviewMs),
+					"minimum":     0,
+					"maximum":     maxPreviewMs,
+				},
+			},
+		},
+	}
+}
+
+// Execute runs the buildPattern tool
+func (t *BuildPatternTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	b := &patternBuild{}
+
+	if value, exists := arguments["brightness"]; exists {
+		level, ok := wholeNumber(value)
+		switch {
+		case !ok || level < 0 || level > 255:
+			b.fail("brightness must be a whole number between 0 and 255")
+		case level == 0:
+			b.warn("brightness 0 turns every LED off")
+			b.queries = append(b.queries, "dim=0")
+		default:
+			b.pass("brightness %d", level)
+			b.queries = append(b.queries, fmt.Sprintf("dim=%d", level))
+		}
+	}
+	for _, ring := range []string{"top", "bottom"} {
+		if value, exists := arguments[ring]; exists {
+			b.ring(ring, value)
+		}
+	}
+	if value, exists := arguments["logo"]; exists {
+		if logo, ok := value.(string); ok && (logo == "on" || logo == "off") {
+			b.pass("logo %s", logo)
+			b.queries = append(b.queries, "logo="+logo)
+		} else {
+			b.fail("logo must be 'on' or 'off'")
+		}
+	}
+
+	previewMs := defaultBuildPreviewMs
+	if value, exists := arguments["previewMs"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < 0 || n > maxPreviewMs {
+			b.fail("previewMs must be a whole number between 0 and %d", maxPreviewMs)
+		}
+		previewMs = n
+	}
+
+	if len(b.checks) == 0 {
+		b.fail("nothing to build; describe 'top', 'bottom', 'logo' or 'brightness'")
+	}
+	checks := strings.Join(b.checks, "\n")
+	if b.errors > 0 {
+		return toolError(CodeInvalidArgument, fmt.Sprintf("the pattern has %d problem(s)\n\n%s", b.errors, checks)), nil
+	}
+
+	query := strings.Join(b.queries, "&")
+	message := "🧩 Pattern built (not sent to the UFO)\n\n" + checks + "\n\nQuery: " + query +
+		"\n\nPlay it with sendRawApi, or save it as an effect pattern with addEffect."
+	if previewMs > 0 {
+		intervalMs := max(defaultPreviewIntervalMs, previewMs/(maxPreviewFrames-1))
+		frames, err := device.Render([]device.PreviewStep{{Query: query}}, previewMs, intervalMs)
+		if err != nil {
+			message += fmt.Sprintf("\n\nNo preview: %v", err)
+		} else {
+			message += "\n\nPreview:\n" + renderASCII(frames)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// ring checks and compiles the description of one ring
+func (b *patternBuild) ring(name string, value interface{}) {
+	spec, ok := value.(map[string]interface{})
+	if !ok {
+		b.fail("%s must be an object with background, zones and motion", name)
+		return
+	}
+	errors := b.errors
+
+	background := "000000"
+	if value, exists := spec["background"]; exists {
+		s, _ := value.(string)
+		if hex, err := color.Parse(s); err != nil {
+			b.fail("%s background: %v", name, err)
+		} else {
+			background = hex
+			b.pass("%s background #%s", name, hex)
+		}
+	}
+
+	// Draw the zones to spot overlaps and whether motion will show
+	var leds [device.RingLEDs]string
+	var drawn [device.RingLEDs]bool
+	for i := range leds {
+		leds[i] = background
+	}
+	var segments []string
+	zones, ok := spec["zones"].([]interface{})
+	if _, exists := spec["zones"]; exists && !ok {
+		b.fail("%s zones must be an array", name)
+	}
+	for i, item := range zones {
+		start, count, hex, err := parseZone(item)
+		if err != nil {
+			b.fail("%s zone %d: %v", name, i+1, err)
+			continue
+		}
+		overlaps := false
+		for n := 0; n < count; n++ {
+			led := (start + n) % device.RingLEDs
+			overlaps = overlaps || drawn[led]
+			drawn[led] = true
+			leds[led] = hex
+		}
+		segments = append(segments, fmt.Sprintf("%d|%d|%s", start, count, hex))
+		b.pass("%s zone %d: LEDs %d-%d #%s", name, i+1, start, (start+count-1)%device.RingLEDs, hex)
+		if overlaps {
+			b.warn("%s zone %d draws over an earlier zone", name, i+1)
+		}
+	}
+	uniform := true
+	for _, led := range leds {
+		uniform = uniform && led == leds[0]
+	}
+
+	whirlMs, ccw, morph := 0, false, ""
+	if value, exists := spec["motion"]; exists {
+		motion, ok := value.(map[string]interface{})
+		if !ok {
+			b.fail("%s motion must be an object with rotate and/or pulse", name)
+			motion = nil
+		}
+		if value, exists := motion["rotate"]; exists {
+			rotate, _ := value.(map[string]interface{})
+			seconds, ok := rotate["periodSeconds"].(float64)
+			whirl, err := device.ConvertRotationPeriodToWhirl(int(math.Round(seconds * 1000)))
+			switch {
+			case !ok:
+				b.fail("%s rotate needs periodSeconds", name)
+			case err != nil:
+				b.fail("%s rotate: %v", name, err)
+			default:
+				whirlMs = whirl
+				ccw, _ = rotate["counterClockwise"].(bool)
+				direction := "clockwise"
+				if ccw {
+					direction = "counter-clockwise"
+				}
+				b.pass("%s rotates %s, one LED every %d ms", name, direction, whirl)
+				if uniform {
+					b.warn("%s is one color, so its rotation cannot be seen", name)
+				}
+			}
+		}
+		if value, exists := motion["pulse"]; exists {
+			pulse, _ := value.(map[string]interface{})
+			holdMs, holdOK := wholeNumber(pulse["holdMs"])
+			fadeMs, fadeOK := wholeNumber(pulse["fadeMs"])
+			switch {
+			case !holdOK || holdMs < 0:
+				b.fail("%s pulse holdMs must be a whole number of at least 0", name)
+			case !fadeOK || fadeMs < 100 || fadeMs > 10000:
+				b.fail("%s pulse fadeMs must be a whole number between 100 and 10000", name)
+			default:
+				morph = device.ConvertMorphToDevice(&device.MorphConfig{BrightnessMs: holdMs, FadeMs: fadeMs})
+				b.pass("%s pulses: hold %d ms, fade %d ms", name, holdMs, fadeMs)
+				if uniform {
+					b.warn("%s zones match the background, so its pulse cannot be seen", name)
+				}
+			}
+		}
+	}
+
+	if b.errors == errors {
+		b.queries = append(b.queries, buildRingPatternCommand(name, segments, background, whirlMs, ccw, morph))
+	}
+}
+
+// parseZone reads a zone as its start LED, LED count and hex color
+func parseZone(item interface{}) (int, int, string, error) {
+	zone, ok := item.(map[string]interface{})
+	if !ok {
+		return 0, 0, "", fmt.Errorf("must be an object with start, count or end, and color")
+	}
+	start, ok := wholeNumber(zone["start"])
+	if !ok || start < 0 || start >= device.RingLEDs {
+		return 0, 0, "", fmt.Errorf("start must be an LED between 0 and %d", device.RingLEDs-1)
+	}
+
+	var count int
+	if value, exists := zone["count"]; exists {
+		if count, ok = wholeNumber(value); !ok || count < 1 || count > device.RingLEDs {
+			return 0, 0, "", fmt.Errorf("count must be between 1 and %d", device.RingLEDs)
+		}
+	} else if value, exists := zone["end"]; exists {
+		end, ok := wholeNumber(value)
+		if !ok || end < 0 || end >= device.RingLEDs {
+			return 0, 0, "", fmt.Errorf("end must be an LED between 0 and %d", device.RingLEDs-1)
+		}
+		count = (end-start+device.RingLEDs)%device.RingLEDs + 1
+	} else {
+		return 0, 0, "", fmt.Errorf("give count or end")
+	}
+
+	spec, _ := zone["color"].(string)
+	hex, err := color.Parse(spec)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("color: %v", err)
+	}
+	return start, count, hex, nil
+}
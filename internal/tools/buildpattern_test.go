@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPatternTool_Execute(t *testing.T) {
+	tool := NewBuildPatternTool()
+
+	t.Run("Definition", func(t *testing.T) {
+		def := tool.Definition()
+		assert.Equal(t, "buildPattern", def.Name)
+		assert.Contains(t, def.InputSchema.Properties, "top")
+		assert.Contains(t, def.InputSchema.Properties, "bottom")
+		assert.Contains(t, def.InputSchema.Properties, "previewMs")
+	})
+
+	t.Run("Compiles", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"top": map[string]interface{}{
+				"background": "navy",
+				"zones": []interface{}{
+					map[string]interface{}{"start": float64(0), "count": float64(5), "color": "red"},
+					map[string]interface{}{"start": float64(13), "end": float64(1), "color": "#fff"},
+				},
+				"motion": map[string]interface{}{
+					"rotate": map[string]interface{}{"periodSeconds": 3.0, "counterClockwise": true},
+					"pulse":  map[string]interface{}{"holdMs": float64(1000), "fadeMs": float64(333)},
+				},
+			},
+			"logo":       "on",
+			"brightness": float64(120),
+		})
+		require.NoError(t, err)
+		text := result.Content[0].(mcp.TextContent).Text
+		require.False(t, result.IsError, text)
+		assert.Contains(t, text, "Query: dim=120&top_init=1&top=0|5|ff0000|13|4|ffffff&top_bg=000080&top_whirl=200|ccw&top_morph=150|10&logo=on")
+		assert.Contains(t, text, "✓ top zone 2: LEDs 13-1 #ffffff")
+		assert.Contains(t, text, "⚠ top zone 2 draws over an earlier zone")
+		assert.Contains(t, text, "Preview:")
+		assert.Contains(t, text, "0ms  top WWRRR")
+	})
+
+	t.Run("WarnsAboutInvisibleMotion", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"bottom": map[string]interface{}{
+				"background": "green",
+				"motion":     map[string]interface{}{"rotate": map[string]interface{}{"periodSeconds": 1.5}},
+			},
+			"previewMs": float64(0),
+		})
+		require.NoError(t, err)
+		text := result.Content[0].(mcp.TextContent).Text
+		require.False(t, result.IsError, text)
+		assert.Contains(t, text, "rotation cannot be seen")
+		assert.Contains(t, text, "Query: bottom_init=1&bottom_bg=008000&bottom_whirl=100")
+		assert.NotContains(t, text, "Preview:")
+	})
+
+	t.Run("ReportsEveryProblem", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"top": map[string]interface{}{
+				"zones": []interface{}{
+					map[string]interface{}{"start": float64(15), "count": float64(1), "color": "red"},
+					map[string]interface{}{"start": float64(0), "color": "red"},
+					map[string]interface{}{"start": float64(0), "count": float64(2), "color": "nope"},
+				},
+				"motion": map[string]interface{}{"rotate": map[string]interface{}{"periodSeconds": 100.0}},
+			},
+			"logo": "blink",
+		})
+		require.NoError(t, err)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.True(t, result.IsError)
+		assert.Contains(t, text, "5 problem(s)")
+		assert.Contains(t, text, "✗ top zone 1: start must be an LED between 0 and 14")
+		assert.Contains(t, text, "✗ top zone 2: give count or end")
+		assert.Contains(t, text, "✗ top zone 3: color")
+		assert.Contains(t, text, "✗ top rotate: rotation period")
+		assert.Contains(t, text, "✗ logo must be 'on' or 'off'")
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "nothing to build")
+	})
+}
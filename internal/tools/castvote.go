@@ -0,0 +1,421 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/color"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+const (
+	// voteEffectName names the stack entry showing a vote
+	voteEffectName = "vote"
+	// defaultVoteWindowMs is how long a vote stays open after its first ballot
+	defaultVoteWindowMs = 300000
+	// minVoteWindowMs and maxVoteWindowMs bound the voting window
+	minVoteWindowMs = 10000
+	maxVoteWindowMs = 3600000
+)
+
+// votePalette colors options whose name is not a color, in order of their
+// first vote
+var votePalette = []string{"00ff00", "ffff00", "ff8000", "ff0000", "0000ff", "8000ff", "00ffff", "ff00ff", "ffffff"}
+
+// CastVoteTool implements the castVote MCP tool, which collects votes for a
+// short window and shows the tally as proportional segments of the top ring
+type CastVoteTool struct {
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+	engine       *effects.Engine
+
+	mu    sync.Mutex
+	round *voteRound // nil when no vote is open
+}
+
+// voteRound is one open vote
+type voteRound struct {
+	instanceID string
+	startTime  time.Time
+	windowMs   int
+	options    []*voteOption     // in order of their first vote
+	voters     map[string]string // voter -> option name, for named voters
+	ballots    int
+}
+
+// voteOption is one choice in a vote and its count
+type voteOption struct {
+	name  string
+	color string
+	votes int
+}
+
+// NewCastVoteTool creates a new castVote tool instance
+func NewCastVoteTool(broadcaster *events.Broadcaster, stateManager *state.Manager, engine *effects.Engine) *CastVoteTool {
+	return &CastVoteTool{
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		engine:       engine,
+	}
+}
+
+// Definition returns the MCP tool definition for castVote
+func (t *CastVoteTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "castVote",
+		Description: "Cast a vote, e.g. for a retro mood check, and show the running tally on the top ring with each option's share of the LEDs in its color. The first vote opens a round that closes after windowMs, when the tally is published as a vote_closed event and the UFO returns to what it showed before. Options named after a color (green, yellow, red...) light in that color. A named voter who votes again moves their vote.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"option": map[string]interface{}{
+					"type":        "string",
+					"description": "What to vote for, e.g. 'green' or 'happy'",
+				},
+				"voter": map[string]interface{}{
+					"type":        "string",
+					"description": "Who is voting, so a second vote replaces their first (optional, default anonymous)",
+				},
+				"color": map[string]interface{}{
+					"type":        "string",
+					"description": "Color for the option (optional; defaults to the option name if it is a color, otherwise the next palette color)",
+				},
+				"windowMs": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("How long the vote stays open, used by the vote that opens a round (optional, default %d)", defaultVoteWindowMs),
+					"minimum":     minVoteWindowMs,
+					"maximum":     maxVoteWindowMs,
+				},
+			},
+			Required: []string{"option"},
+		},
+	}
+}
+
+// Execute runs the castVote tool
+func (t *CastVoteTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, _ := arguments["option"].(string)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return invalidArgument("option", "'option' must be a non-empty string"), nil
+	}
+	voter, _ := arguments["voter"].(string)
+	voter = strings.TrimSpace(voter)
+	optionColor := ""
+	if value, exists := arguments["color"]; exists {
+		spec, _ := value.(string)
+		hex, err := color.Parse(spec)
+		if err != nil {
+			return invalidArgument("color", fmt.Sprintf("invalid color: %v", err)), nil
+		}
+		optionColor = hex
+	}
+	windowMs := defaultVoteWindowMs
+	if value, exists := arguments["windowMs"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < minVoteWindowMs || n > maxVoteWindowMs {
+			return invalidArgument("windowMs", fmt.Sprintf("'windowMs' must be a whole number between %d and %d", minVoteWindowMs, maxVoteWindowMs)), nil
+		}
+		windowMs = n
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// A vote entry stopped with stopEffect ends its round
+	if t.round != nil && t.stateManager.FindEffect(t.round.startTime) == nil {
+		t.closeRound(ctx, "stopped")
+	}
+	opened := t.round == nil
+	if opened {
+		t.round = &voteRound{
+			instanceID: state.NewInstanceID(),
+			startTime:  time.Now(),
+			windowMs:   windowMs,
+			voters:     map[string]string{},
+		}
+	}
+	round := t.round
+
+	option := round.option(name)
+	if option == nil {
+		if len(round.options) == device.RingLEDs {
+			return toolError(CodeConflict, fmt.Sprintf("a vote holds at most %d options", device.RingLEDs)), nil
+		}
+		option = &voteOption{name: name, color: optionColor}
+		if option.color == "" {
+			option.color = round.nextColor(name)
+		}
+		round.options = append(round.options, option)
+	} else if optionColor != "" {
+		option.color = optionColor
+	}
+	moved := ""
+	if previous, voted := round.voters[voter]; voter != "" && voted {
+		if strings.EqualFold(previous, option.name) {
+			return toolError(CodeConflict, fmt.Sprintf("%s already voted for '%s'", voter, option.name)), nil
+		}
+		round.option(previous).votes--
+		round.ballots--
+		moved = previous
+	}
+	if voter != "" {
+		round.voters[voter] = option.name
+	}
+	option.votes++
+	round.ballots++
+
+	pattern := round.pattern()
+	var isTop, stale bool
+	if opened {
+		isTop, stale = t.stateManager.PushEffect(voteEffectName, pattern, map[string]interface{}{
+			"instanceId": round.instanceID,
+			"startTime":  round.startTime,
+			"duration":   round.windowMs,
+			"perpetual":  false,
+			"vote":       true,
+		})
+		started := round.startTime
+		t.engine.Go(ctx, func(ctx context.Context) {
+			t.await(ctx, started)
+		})
+	} else {
+		current := t.stateManager.FindEffect(round.startTime)
+		item := *current
+		item.Pattern = pattern
+		_, isTop = t.stateManager.ReplaceEffect(func(candidate state.EffectStackItem) bool {
+			return candidate.InstanceID() == round.instanceID
+		}, item)
+	}
+
+	var sendErr error
+	if isTop {
+		if sendErr = t.engine.Apply(ctx, voteEffectName, pattern, nil); sendErr != nil {
+			t.broadcaster.PublishRawExecuted(ctx, pattern, fmt.Sprintf("ERROR: %v", sendErr))
+		} else {
+			t.broadcaster.PublishRawExecuted(ctx, pattern, "OK")
+		}
+	}
+	restoreStale(ctx, stale, t.engine, t.broadcaster, t.stateManager)
+
+	t.broadcaster.PublishPayload(ctx, events.VoteCast{
+		VoteRound: round.eventData(),
+		Option:    option.name,
+		Voter:     voter,
+		MovedFrom: moved,
+	})
+
+	message := fmt.Sprintf("🗳️ Vote for '%s' counted", option.name)
+	if moved != "" {
+		message = fmt.Sprintf("🗳️ %s's vote moved from '%s' to '%s'", voter, moved, option.name)
+	}
+	closesAt := round.startTime.Add(time.Duration(round.windowMs) * time.Millisecond)
+	message += fmt.Sprintf(" (%d votes so far, the vote closes at %s)\n\n%s", round.ballots, format.Time(closesAt), round.describe())
+	if !isTop {
+		if top := t.stateManager.GetCurrentEffect(); top != nil {
+			message += fmt.Sprintf("\n⏳ The tally is beneath '%s' and shows when it ends.", top.Name)
+		}
+	}
+	if sendErr != nil {
+		message += fmt.Sprintf("\n⚠️ The vote was counted but the UFO was not updated: %v", sendErr)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// await closes the round started at startTime once its window has passed,
+// holding the countdown while the vote entry is paused
+func (t *CastVoteTool) await(ctx context.Context, startTime time.Time) {
+	for {
+		item := t.stateManager.FindEffect(startTime)
+		if item == nil {
+			break
+		}
+		if item.Paused() {
+			if !effects.Sleep(ctx, pausePollInterval) {
+				return
+			}
+			continue
+		}
+		remaining, _ := item.Remaining(time.Now())
+		if remaining <= 0 {
+			break
+		}
+		if !effects.Sleep(ctx, remaining) {
+			return
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.round != nil && t.round.startTime.Equal(startTime) {
+		t.closeRound(ctx, "expired")
+	}
+}
+
+// closeRound removes the vote from the stack, restoring what is beneath it,
+// and publishes the final tally; t.mu must be held
+func (t *CastVoteTool) closeRound(ctx context.Context, reason string) {
+	round := t.round
+	t.round = nil
+
+	removed, topRemoved := t.stateManager.RemoveEffects(func(item state.EffectStackItem) bool {
+		return item.InstanceID() == round.instanceID
+	})
+	if removed > 0 && topRemoved {
+		if err := restoreTop(ctx, t.engine, t.broadcaster, t.stateManager); err != nil {
+			slog.WarnContext(ctx, "Failed to restore effect after vote", "error", err)
+		}
+	}
+
+	t.broadcaster.PublishPayload(ctx, events.VoteClosed{
+		VoteRound: round.eventData(),
+		Reason:    reason,
+		Winner:    round.winner(),
+	})
+}
+
+// option returns the option with the given name, ignoring case, or nil
+func (r *voteRound) option(name string) *voteOption {
+	for _, option := range r.options {
+		if strings.EqualFold(option.name, name) {
+			return option
+		}
+	}
+	return nil
+}
+
+// nextColor picks a color for a new option: its name if that is a color,
+// otherwise the first palette color no other option uses
+func (r *voteRound) nextColor(name string) string {
+	if hex, err := color.Parse(name); err == nil {
+		return hex
+	}
+	for _, candidate := range votePalette {
+		if r.colorUnused(candidate) {
+			return candidate
+		}
+	}
+	return votePalette[len(r.options)%len(votePalette)]
+}
+
+// colorUnused reports whether no option is shown in hex yet
+func (r *voteRound) colorUnused(hex string) bool {
+	for _, option := range r.options {
+		if option.color == hex {
+			return false
+		}
+	}
+	return true
+}
+
+// shares splits the ring's LEDs between the options with votes: one LED
+// each, then the rest by largest remainder of their share of the votes
+func (r *voteRound) shares() []int {
+	leds := make([]int, len(r.options))
+	if r.ballots == 0 {
+		return leds
+	}
+	var voted []int
+	for i, option := range r.options {
+		if option.votes > 0 {
+			leds[i] = 1
+			voted = append(voted, i)
+		}
+	}
+	spare := device.RingLEDs - len(voted)
+	remainders := make(map[int]int, len(voted))
+	given := 0
+	for _, i := range voted {
+		exact := spare * r.options[i].votes
+		leds[i] += exact / r.ballots
+		remainders[i] = exact % r.ballots
+		given += exact / r.ballots
+	}
+	sort.SliceStable(voted, func(a, b int) bool {
+		return remainders[voted[a]] > remainders[voted[b]]
+	})
+	for _, i := range voted[:spare-given] {
+		leds[i]++
+	}
+	return leds
+}
+
+// pattern draws each option's share of the top ring in its color, in order
+// of the options' first votes
+func (r *voteRound) pattern() string {
+	var segments []string
+	start := 0
+	for i, count := range r.shares() {
+		if count == 0 {
+			continue
+		}
+		segments = append(segments, fmt.Sprintf("%d|%d|%s", start, count, r.options[i].color))
+		start += count
+	}
+	return buildRingPatternCommand("top", segments, "000000", 0, false, "")
+}
+
+// winner returns the option with the most votes, or "" on a tie or when
+// nobody voted
+func (r *voteRound) winner() string {
+	best, bestVotes, tied := "", 0, false
+	for _, option := range r.options {
+		switch {
+		case option.votes > bestVotes:
+			best, bestVotes, tied = option.name, option.votes, false
+		case option.votes == bestVotes:
+			tied = true
+		}
+	}
+	if tied || bestVotes == 0 {
+		return ""
+	}
+	return best
+}
+
+// eventData describes the round's tally for vote events
+func (r *voteRound) eventData() events.VoteRound {
+	tally := make([]events.VoteOption, len(r.options))
+	for i, option := range r.options {
+		tally[i] = events.VoteOption{Option: option.name, Votes: option.votes, Color: option.color}
+	}
+	return events.VoteRound{
+		InstanceID: r.instanceID,
+		Tally:      tally,
+		Votes:      r.ballots,
+		StartTime:  r.startTime,
+		WindowMs:   r.windowMs,
+	}
+}
+
+// describe lists the tally, one option per line
+func (r *voteRound) describe() string {
+	var b strings.Builder
+	shares := r.shares()
+	for i, option := range r.options {
+		percent := 0
+		if r.ballots > 0 {
+			percent = (option.votes*100 + r.ballots/2) / r.ballots
+		}
+		fmt.Fprintf(&b, "• '%s': %d vote(s), %d%% (%d LEDs, #%s)\n", option.name, option.votes, percent, shares[i], option.color)
+	}
+	return b.String()
+}
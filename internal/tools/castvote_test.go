@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCastVoteTool_Execute(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	engine := effects.NewEngine(device.NewClient())
+	defer engine.Shutdown(context.Background())
+	tool := NewCastVoteTool(broadcaster, stateManager, engine)
+	subscriber := broadcaster.Subscribe("test")
+
+	sent := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		result := queries
+		queries = nil
+		return result
+	}
+	nextEvent := func(eventType string) events.Event {
+		t.Helper()
+		for {
+			select {
+			case event := <-subscriber.Channel:
+				if event.Type == eventType {
+					return event
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("no %s event", eventType)
+			}
+		}
+	}
+	vote := func(arguments map[string]interface{}) string {
+		t.Helper()
+		result, err := tool.Execute(context.Background(), arguments)
+		require.NoError(t, err)
+		text := result.Content[0].(mcp.TextContent).Text
+		require.False(t, result.IsError, text)
+		return text
+	}
+
+	stateManager.PushEffect("base", "top_init=1&top_bg=0000ff", map[string]interface{}{"instanceId": "base-id", "perpetual": true})
+	sent()
+
+	t.Run("TallyShares", func(t *testing.T) {
+		text := vote(map[string]interface{}{"option": "green", "voter": "ana", "windowMs": float64(60000)})
+		assert.Contains(t, text, "Vote for 'green' counted (1 votes so far")
+		assert.Equal(t, []string{"top_init=1&top=0|15|008000&top_bg=000000"}, sent())
+		assert.Equal(t, 2, stateManager.GetEffectStackDepth())
+
+		vote(map[string]interface{}{"option": "Red"})
+		vote(map[string]interface{}{"option": "red", "voter": "bo"})
+		assert.Equal(t, "top_init=1&top=0|5|008000|5|10|ff0000&top_bg=000000", sent()[1])
+
+		// A named voter moves their vote
+		text = vote(map[string]interface{}{"option": "happy", "voter": "ana"})
+		assert.Contains(t, text, "ana's vote moved from 'green' to 'happy'")
+		assert.Contains(t, text, "'green': 0 vote(s), 0% (0 LEDs, #008000)")
+		assert.Equal(t, []string{"top_init=1&top=0|10|ff0000|10|5|00ff00&top_bg=000000"}, sent())
+
+		event := nextEvent(events.EventVoteCast)
+		for event.Data["movedFrom"] == nil {
+			event = nextEvent(events.EventVoteCast)
+		}
+		assert.Equal(t, "green", event.Data["movedFrom"])
+		assert.Equal(t, 3, event.Data["votes"])
+
+		result, err := tool.Execute(context.Background(), map[string]interface{}{"option": "HAPPY", "voter": "ana"})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("StoppedRoundCloses", func(t *testing.T) {
+		stateManager.RemoveEffects(func(item state.EffectStackItem) bool { return item.Name == voteEffectName })
+		sent()
+
+		text := vote(map[string]interface{}{"option": "meh"})
+		assert.Contains(t, text, "(1 votes so far")
+
+		closed := nextEvent(events.EventVoteClosed)
+		assert.Equal(t, "stopped", closed.Data["reason"])
+		assert.Equal(t, "Red", closed.Data["winner"])
+		assert.Equal(t, []string{"top_init=1&top=0|15|00ff00&top_bg=000000"}, sent())
+	})
+
+	t.Run("WindowExpires", func(t *testing.T) {
+		tool.mu.Lock()
+		started := tool.round.startTime
+		tool.mu.Unlock()
+
+		// Shorten the window so the round is already over
+		current := *stateManager.FindEffect(started)
+		shortened := map[string]interface{}{}
+		for key, value := range current.Context {
+			shortened[key] = value
+		}
+		shortened["duration"] = 1
+		current.Context = shortened
+		stateManager.ReplaceEffect(func(candidate state.EffectStackItem) bool {
+			return candidate.InstanceID() == current.InstanceID()
+		}, current)
+		sent()
+
+		tool.await(context.Background(), started)
+		closed := nextEvent(events.EventVoteClosed)
+		assert.Equal(t, "expired", closed.Data["reason"])
+		assert.Equal(t, "meh", closed.Data["winner"])
+		assert.Equal(t, "base", stateManager.GetCurrentEffect().Name)
+		assert.Equal(t, []string{"top_init=1&top_bg=0000ff"}, sent())
+	})
+
+	t.Run("InvalidArguments", func(t *testing.T) {
+		cases := []map[string]interface{}{
+			{},
+			{"option": "  "},
+			{"option": "x", "color": "nope"},
+			{"option": "x", "windowMs": float64(5)},
+		}
+		for _, arguments := range cases {
+			result, err := tool.Execute(context.Background(), arguments)
+			require.NoError(t, err)
+			assert.True(t, result.IsError, arguments)
+		}
+	})
+}
+
+func TestVoteRound_Shares(t *testing.T) {
+	round := &voteRound{}
+	for _, votes := range []int{7, 2, 1, 0} {
+		round.options = append(round.options, &voteOption{votes: votes})
+		round.ballots += votes
+	}
+	shares := round.shares()
+	assert.Equal(t, []int{10, 3, 2, 0}, shares)
+
+	total := 0
+	for _, n := range shares {
+		total += n
+	}
+	assert.Equal(t, device.RingLEDs, total)
+}
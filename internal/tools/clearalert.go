@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// ClearAlertTool implements the clearAlert MCP tool
+type ClearAlertTool struct {
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+	engine       *effects.Engine
+}
+
+// NewClearAlertTool creates a new clearAlert tool instance
+func NewClearAlertTool(broadcaster *events.Broadcaster, stateManager *state.Manager, engine *effects.Engine) *ClearAlertTool {
+	return &ClearAlertTool{
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		engine:       engine,
+	}
+}
+
+// Definition returns the MCP tool definition for clearAlert
+func (t *ClearAlertTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "clearAlert",
+		Description: "Clear an alert raised with raiseAlert. If it was showing, the next alert or the effect beneath it resumes, or the UFO is cleared. Without a name, every raised alert is cleared.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the alert to clear (optional, default all raised alerts)",
+				},
+			},
+		},
+	}
+}
+
+// Execute runs the clearAlert tool
+func (t *ClearAlertTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name := ""
+	if value, exists := arguments["name"]; exists {
+		str, ok := value.(string)
+		if !ok {
+			return invalidArgument("name", "'name' must be a string"), nil
+		}
+		name = str
+	}
+
+	var cleared []string
+	var instances []string
+	for _, item := range t.stateManager.GetEffectStack() {
+		if alert := raisedAlertName(item); alert != "" && (name == "" || alert == name) {
+			cleared = append(cleared, alert)
+			instances = append(instances, item.InstanceID())
+		}
+	}
+	if len(cleared) == 0 {
+		if name == "" {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Type: "text",
+						Text: "No alerts are raised",
+					},
+				},
+				IsError: false,
+			}, nil
+		}
+		return toolError(CodeNotFound, fmt.Sprintf("No alert named '%s' is raised%s", name, activeAlertList(t.stateManager))), nil
+	}
+
+	_, topChanged := t.stateManager.RemoveEffects(func(item state.EffectStackItem) bool {
+		alert := raisedAlertName(item)
+		return alert != "" && (name == "" || alert == name)
+	})
+	for i, alert := range cleared {
+		t.broadcaster.PublishPayload(ctx, events.AlertResolved{
+			Source:     alertSource,
+			Key:        alert,
+			Name:       alert,
+			InstanceID: instances[i],
+		})
+	}
+
+	noun := "alert"
+	if len(cleared) > 1 {
+		noun = "alerts"
+	}
+	message := fmt.Sprintf("✅ Cleared %s %s", noun, strings.Join(quoted(cleared), ", "))
+	if topChanged {
+		if err := restoreTop(ctx, t.engine, t.broadcaster, t.stateManager); err != nil {
+			return toolError(CodeDeviceError, err.Error()), nil
+		}
+		if current := t.stateManager.GetCurrentEffect(); current != nil {
+			message += fmt.Sprintf("; now showing '%s'", current.Name)
+		} else {
+			message += "; the UFO is cleared"
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// activeAlertList names the raised alerts for an error message
+func activeAlertList(stateManager *state.Manager) string {
+	var names []string
+	for _, item := range stateManager.GetEffectStack() {
+		if alert := raisedAlertName(item); alert != "" {
+			names = append(names, alert)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return ". Raised alerts: " + strings.Join(quoted(names), ", ")
+}
+
+// quoted wraps each name in single quotes
+func quoted(names []string) []string {
+	result := make([]string, len(names))
+	for i, name := range names {
+		result[i] = "'" + name + "'"
+	}
+	return result
+}
@@ -0,0 +1,215 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+)
+
+// ComposeEffectTool implements the composeEffect MCP tool, which saves a
+// composite effect layering stored effects
+type ComposeEffectTool struct {
+	store *effects.Store
+}
+
+// NewComposeEffectTool creates a new composeEffect tool instance
+func NewComposeEffectTool(store *effects.Store) *ComposeEffectTool {
+	return &ComposeEffectTool{
+		store: store,
+	}
+}
+
+// Definition returns the MCP tool definition for composeEffect
+func (t *ComposeEffectTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "composeEffect",
+		Description: "Save a composite effect that layers two or more stored effects, e.g. a background gradient under a whirling highlight. The server renders every layer as the UFO would show it, whirls, morphs and steps included, blends them LED by LED and plays the result as one stream of frames. Only the rings are layered. Saving an existing composite replaces it; composites follow changes to their layers. Play it with playEffect.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Unique name for the composite (letters, numbers and underscores)",
+				},
+				"description": map[string]interface{}{
+					"type":        "string",
+					"description": "Human-readable description of what the composite shows",
+				},
+				"layers": map[string]interface{}{
+					"type":        "array",
+					"description": "Stored effects to layer, bottom first (at least 2)",
+					"minItems":    2,
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"effect": map[string]interface{}{
+								"type":        "string",
+								"description": "Name of a stored effect that is not itself a composite",
+							},
+							"blend": map[string]interface{}{
+								"type":        "string",
+								"description": "How the layer covers those below: its lit LEDs replace them ('overwrite'), colors are added ('add') or the brighter of each red, green and blue wins ('max'). Off LEDs are transparent (optional, default overwrite)",
+								"enum":        device.LayerModes,
+								"default":     device.LayerOverwrite,
+							},
+							"params": map[string]interface{}{
+								"type":        "object",
+								"description": "Values for a template effect's parameters (optional)",
+							},
+						},
+						"required": []string{"effect"},
+					},
+				},
+				"frameMs": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Time between frames in milliseconds (optional, default %d). Shorter follows fast whirls more closely but sends more updates", effects.DefaultFrameMs),
+					"minimum":     50,
+				},
+				"duration": map[string]interface{}{
+					"type":        "number",
+					"description": "Duration in milliseconds (0-3600000, 0 means infinite)",
+				},
+				"category": map[string]interface{}{
+					"type":        "string",
+					"description": "Group for listEffects filters, e.g. 'alerts', 'ambient' or 'status' (optional)",
+				},
+				"tags": map[string]interface{}{
+					"type":        "array",
+					"description": "Labels for listEffects filters, e.g. [\"red\", \"incident\"]; stored lowercase (optional)",
+					"items":       map[string]interface{}{"type": "string"},
+				},
+			},
+			Required: []string{"name", "description", "layers"},
+		},
+	}
+}
+
+// Execute runs the composeEffect tool
+func (t *ComposeEffectTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, _ := arguments["name"].(string)
+	if name == "" || !isValidEffectName(name) {
+		return missingArgument("name", "'name' is required and must contain only letters, numbers, and underscores"), nil
+	}
+	existing, exists := t.store.Get(name)
+	if exists && existing.Composite == nil {
+		return toolError(CodeConflict, fmt.Sprintf("effect '%s' already exists and is not a composite", name)), nil
+	}
+	description, _ := arguments["description"].(string)
+	if description == "" {
+		return missingArgument("description", "'description' is required and must be a non-empty string"), nil
+	}
+
+	composite := &effects.Composite{}
+	list, ok := arguments["layers"].([]interface{})
+	if !ok {
+		return invalidArgument("layers", "'layers' must be an array of layer objects"), nil
+	}
+	for i, item := range list {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return invalidArgument("layers", fmt.Sprintf("layers[%d] must be an object", i)), nil
+		}
+		var layer effects.Layer
+		if layer.Effect, ok = fields["effect"].(string); !ok || layer.Effect == "" {
+			return invalidArgument("layers", fmt.Sprintf("layers[%d].effect must be the name of a stored effect", i)), nil
+		}
+		if value, exists := fields["blend"]; exists {
+			if layer.Blend, ok = value.(string); !ok {
+				return invalidArgument("layers", fmt.Sprintf("layers[%d].blend must be one of %s", i, strings.Join(device.LayerModes, ", "))), nil
+			}
+		}
+		if value, exists := fields["params"]; exists {
+			values, err := paramValues(value)
+			if err != nil {
+				return invalidArgument("layers", fmt.Sprintf("layers[%d]: %v", i, err)), nil
+			}
+			layer.Params = values
+		}
+		composite.Layers = append(composite.Layers, layer)
+	}
+	if value, exists := arguments["frameMs"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok {
+			return invalidArgument("frameMs", "'frameMs' must be a whole number of milliseconds"), nil
+		}
+		composite.FrameMs = n
+	}
+
+	duration := 0
+	if value, exists := arguments["duration"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < 0 || n > 3600000 {
+			return invalidArgument("duration", "'duration' must be between 0 and 3600000 milliseconds (1 hour)"), nil
+		}
+		duration = n
+	}
+	category, tags, err := parseEffectLabels(arguments)
+	if err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+
+	effect := &effects.Effect{
+		Name:        name,
+		Description: description,
+		Duration:    duration,
+		Category:    category,
+		Tags:        tags,
+		Composite:   composite,
+	}
+	save := t.store.Add
+	if exists {
+		save = t.store.Update
+	}
+	if err := save(effect); err != nil {
+		return toolError(CodeInvalidArgument, fmt.Sprintf("Failed to save composite: %v", err)), nil
+	}
+	effect, _ = t.store.Get(name)
+
+	verb := "Added"
+	if exists {
+		verb = "Replaced"
+	}
+	message := fmt.Sprintf("🧅 %s composite effect '%s'\n\n", verb, name)
+	message += fmt.Sprintf("• Description: %s\n", description)
+	for i, layer := range composite.Layers {
+		blend := layer.Blend
+		if blend == "" {
+			blend = device.LayerOverwrite
+		}
+		if i == 0 {
+			blend = "bottom"
+		}
+		message += fmt.Sprintf("• Layer %d: %s (%s)", i+1, layer.Effect, blend)
+		if len(layer.Params) > 0 {
+			message += " with " + formatParamValues(layer.Params)
+		}
+		message += "\n"
+	}
+	if len(effect.Steps) > 0 {
+		cycle := 0
+		for _, step := range effect.Steps {
+			cycle += step.DurationMs
+		}
+		message += fmt.Sprintf("• Frames: %d updates per %s cycle\n", len(effect.Steps), format.Millis(int64(cycle)))
+	} else {
+		message += "• Frames: none needed, the layers do not move\n"
+	}
+	message += fmt.Sprintf("• Duration: %dms", effect.Duration)
+	message += formatEffectLabels(effect)
+	message += "\n\nYou can now use playEffect to activate this effect."
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
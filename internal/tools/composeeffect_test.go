@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComposeEffectTool_Execute(t *testing.T) {
+	store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	require.NoError(t, store.Add(&effects.Effect{Name: "gradient", Pattern: "top_init=1&top_bg=000033&top=0|5|000066"}))
+	require.NoError(t, store.Add(&effects.Effect{Name: "spot", Pattern: "top_init=1&top_bg=000000&top=0|1|{color}&top_whirl=100", Params: []effects.Param{{Name: "color", Default: "FFFFFF"}}}))
+
+	tool := NewComposeEffectTool(store)
+	assert.Equal(t, "composeEffect", tool.Definition().Name)
+
+	arguments := map[string]interface{}{
+		"name":        "spotlight",
+		"description": "Gradient with a whirling spot",
+		"layers": []interface{}{
+			map[string]interface{}{"effect": "gradient"},
+			map[string]interface{}{"effect": "spot", "blend": "max", "params": map[string]interface{}{"color": "FF0000"}},
+		},
+		"tags": []interface{}{"Ambient"},
+	}
+	result, err := tool.Execute(context.Background(), arguments)
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	require.False(t, result.IsError, text)
+	assert.Contains(t, text, "Added composite effect 'spotlight'")
+	assert.Contains(t, text, "Layer 2: spot (max) with color=FF0000")
+	assert.Contains(t, text, "15 updates per 1.5s")
+
+	effect, exists := store.Get("spotlight")
+	require.True(t, exists)
+	require.NotNil(t, effect.Composite)
+	assert.Len(t, effect.Steps, 15)
+	assert.Equal(t, []string{"ambient"}, effect.Tags)
+
+	// Saving again replaces the composite
+	arguments["layers"] = []interface{}{
+		map[string]interface{}{"effect": "gradient"},
+		map[string]interface{}{"effect": "spot"},
+	}
+	arguments["frameMs"] = float64(300)
+	result, err = tool.Execute(context.Background(), arguments)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Replaced composite effect")
+	effect, _ = store.Get("spotlight")
+	assert.Len(t, effect.Steps, 5)
+
+	// Composites cannot be edited as patterns
+	result, err = NewUpdateEffectTool(store).Execute(context.Background(), map[string]interface{}{"name": "spotlight", "pattern": "top_init=1"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestComposeEffectTool_ValidationErrors(t *testing.T) {
+	store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	require.NoError(t, store.Add(&effects.Effect{Name: "gradient", Pattern: "top_init=1&top_bg=000033"}))
+	tool := NewComposeEffectTool(store)
+
+	layers := func(items ...map[string]interface{}) []interface{} {
+		list := make([]interface{}, len(items))
+		for i, item := range items {
+			list[i] = item
+		}
+		return list
+	}
+	for _, arguments := range []map[string]interface{}{
+		{"name": "bad name", "description": "x", "layers": layers(map[string]interface{}{"effect": "gradient"}, map[string]interface{}{"effect": "gradient"})},
+		{"name": "gradient", "description": "x", "layers": layers(map[string]interface{}{"effect": "gradient"}, map[string]interface{}{"effect": "gradient"})},
+		{"name": "layered", "description": "x", "layers": "gradient"},
+		{"name": "layered", "description": "x", "layers": layers(map[string]interface{}{"effect": "gradient"})},
+		{"name": "layered", "description": "x", "layers": layers(map[string]interface{}{"effect": "gradient"}, map[string]interface{}{"effect": "missing"})},
+		{"name": "layered", "description": "x", "layers": layers(map[string]interface{}{"effect": "gradient"}, map[string]interface{}{"effect": "gradient", "blend": "multiply"})},
+		{"name": "layered", "description": "x", "layers": layers(map[string]interface{}{"effect": "gradient"}, map[string]interface{}{"effect": "gradient"}), "frameMs": float64(10)},
+	} {
+		result, err := tool.Execute(context.Background(), arguments)
+		require.NoError(t, err)
+		assert.True(t, result.IsError, "arguments %v", arguments)
+	}
+	_, exists := store.Get("layered")
+	assert.False(t, exists)
+}
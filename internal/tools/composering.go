@@ -0,0 +1,397 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/palettes"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// ComposeRingTool implements the composeRing MCP tool, which builds ring
+// patterns from a semantic description instead of raw segment strings
+type ComposeRingTool struct {
+	client       *device.Client
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+	palettes     *palettes.Store
+}
+
+// ringComposition is a validated composeRing request
+type ringComposition struct {
+	rings            []string
+	segments         []string
+	background       string
+	whirlMs          int
+	counterClockwise bool
+	morph            *device.MorphConfig
+}
+
+// NewComposeRingTool creates a new composeRing tool instance
+func NewComposeRingTool(client *device.Client, broadcaster *events.Broadcaster, stateManager *state.Manager, palettes *palettes.Store) *ComposeRingTool {
+	return &ComposeRingTool{
+		client:       client,
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		palettes:     palettes,
+	}
+}
+
+// Definition returns the MCP tool definition for composeRing
+func (t *ComposeRingTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name: "composeRing",
+		Description: "Compose a ring pattern from a description: divide the ring into N equal segments with the given colors, " +
+			"leave gaps between them, and rotate it once every few seconds. The tool works out the LED segments and whirl speed " +
+			"for you and reports the device query it sent. Each ring has 15 LEDs. Give a saved palette instead of colors to use its colors, " +
+			"or a gradient to blend smoothly from one color to another across the ring.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"ring": map[string]interface{}{
+					"type":        "string",
+					"description": "Which ring to compose: 'top', 'bottom' or 'both'",
+					"enum":        []string{"top", "bottom", "both"},
+				},
+				"colors": map[string]interface{}{
+					"type":        "array",
+					"description": "Segment colors (hex RRGGBB, #RGB, rgb(r,g,b), CSS color name or a palette color such as 'xmas:2'). Colors repeat when there are more segments than colors",
+					"items":       map[string]interface{}{"type": "string"},
+					"minItems":    1,
+					"maxItems":    device.RingLEDs,
+					"examples":    [][]string{{"red", "white"}, {"FF0000", "00FF00", "0000FF"}},
+				},
+				"palette": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of a saved palette whose colors to use, instead of colors",
+					"examples":    []string{"xmas", "dynatrace-brand"},
+				},
+				"gradient": gradientSchema("Fill the ring, or part of it, with a smooth gradient from one color to another, computed per LED. Replaces colors and palette; cannot be combined with segments, gap or offset"),
+				"segments": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of equal segments to divide the ring into (optional, defaults to the number of colors). LEDs that do not divide evenly go to the first segments",
+					"minimum":     1,
+					"maximum":     device.RingLEDs,
+				},
+				"gap": map[string]interface{}{
+					"type":        "integer",
+					"description": "Unlit LEDs after each segment (optional, default 0). Gaps show the background color",
+					"minimum":     0,
+					"default":     0,
+				},
+				"offset": map[string]interface{}{
+					"type":        "integer",
+					"description": "LED the first segment starts at (optional, default 0). Segments wrap around the ring",
+					"minimum":     0,
+					"maximum":     device.RingLEDs - 1,
+					"default":     0,
+				},
+				"background": map[string]interface{}{
+					"type":        "string",
+					"description": "Color of the gaps (optional, default off)",
+					"examples":    []string{"000000", "navy"},
+				},
+				"rotationPeriodSeconds": map[string]interface{}{
+					"type": "number",
+					"description": fmt.Sprintf("Seconds for the pattern to travel once around the ring (optional, %.3g-%.3g). Omit or use 0 for a still pattern",
+						float64(device.RingLEDs)/1000, float64(device.RingLEDs*device.MaxWhirlMs)/1000),
+					"minimum":  0,
+					"examples": []float64{1.5, 3, 7.5},
+				},
+				"counterClockwise": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Rotate counter-clockwise (optional, default false)",
+					"default":     false,
+				},
+				"morph": map[string]interface{}{
+					"type":        "object",
+					"description": "Fade the ring in and out (optional)",
+					"properties": map[string]interface{}{
+						"brightnessMs": map[string]interface{}{
+							"type":        "integer",
+							"description": "Time to stay at full brightness in milliseconds",
+							"minimum":     0,
+						},
+						"fadeMs": map[string]interface{}{
+							"type":        "integer",
+							"description": "Time to fade in or out in milliseconds (100-10000)",
+							"minimum":     100,
+							"maximum":     10000,
+						},
+					},
+					"required": []string{"brightnessMs", "fadeMs"},
+				},
+			},
+			Required: []string{"ring"},
+		},
+	}
+}
+
+// Execute runs the composeRing tool
+func (t *ComposeRingTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	composition, err := parseComposition(arguments, t.palettes)
+	if err != nil {
+		return toolError(CodeInvalidArgument, fmt.Sprintf("%v", err)), nil
+	}
+
+	morphSpec := ""
+	if composition.morph != nil {
+		morphSpec = device.ConvertMorphToDevice(composition.morph)
+	}
+	var queries []string
+	for _, ring := range composition.rings {
+		queries = append(queries, buildRingPatternCommand(ring, composition.segments, composition.background,
+			composition.whirlMs, composition.counterClockwise, morphSpec))
+	}
+	query := strings.Join(queries, "&")
+
+	if _, err := t.client.SendRawQuery(ctx, query); err != nil {
+		t.broadcaster.PublishRawExecuted(ctx, query, fmt.Sprintf("ERROR: %v", err))
+		return toolError(CodeDeviceError, fmt.Sprintf("Failed to compose ring pattern: %v", err)), nil
+	}
+	t.broadcaster.PublishRawExecuted(ctx, query, "OK")
+
+	ledColors := parseLedColors(composition.segments, composition.background)
+	for _, ring := range composition.rings {
+		t.stateManager.UpdateRingSegments(ring, ledColors, composition.background)
+		t.stateManager.UpdateWhirl(ring, composition.whirlMs, composition.counterClockwise)
+		if composition.morph != nil {
+			t.stateManager.UpdateMorph(ring, composition.morph.BrightnessMs, composition.morph.FadeMs)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Composed %s ring", strings.Join(composition.rings, " and "))
+	if len(composition.rings) > 1 {
+		b.WriteString("s")
+	}
+	fmt.Fprintf(&b, "\nSegments: %s", strings.Join(composition.segments, ", "))
+	if composition.background != "" {
+		fmt.Fprintf(&b, "\nBackground: #%s", composition.background)
+	}
+	if composition.whirlMs > 0 {
+		direction := "clockwise"
+		if composition.counterClockwise {
+			direction = "counter-clockwise"
+		}
+		fmt.Fprintf(&b, "\nRotation: one turn every %.2fs %s (whirl %dms per LED)",
+			float64(device.ConvertWhirlToRotationPeriod(composition.whirlMs))/1000, direction, composition.whirlMs)
+	}
+	if composition.morph != nil {
+		fmt.Fprintf(&b, "\nMorph: %dms bright, %dms fade", composition.morph.BrightnessMs, composition.morph.FadeMs)
+	}
+	fmt.Fprintf(&b, "\nQuery: %s", query)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: b.String(),
+			},
+		},
+	}, nil
+}
+
+// parseComposition validates a composeRing request and translates it into
+// device segments and a whirl speed. Colors may come from a palette in store.
+func parseComposition(arguments map[string]interface{}, store *palettes.Store) (*ringComposition, error) {
+	composition := &ringComposition{}
+
+	switch ring, _ := arguments["ring"].(string); ring {
+	case "top", "bottom":
+		composition.rings = []string{ring}
+	case "both":
+		composition.rings = []string{"top", "bottom"}
+	default:
+		return nil, fmt.Errorf("'ring' must be 'top', 'bottom' or 'both'")
+	}
+
+	if value, exists := arguments["gradient"]; exists {
+		for _, name := range []string{"colors", "palette", "segments", "gap", "offset"} {
+			if _, conflict := arguments[name]; conflict {
+				return nil, fmt.Errorf("give either 'gradient' or '%s', not both", name)
+			}
+		}
+		segments, err := parseGradient(value, store)
+		if err != nil {
+			return nil, err
+		}
+		composition.segments = segments
+	} else {
+		segments, err := parseLayout(arguments, store)
+		if err != nil {
+			return nil, err
+		}
+		composition.segments = segments
+	}
+
+	if value, exists := arguments["background"]; exists {
+		spec, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("'background' must be a string")
+		}
+		hex, err := store.Color(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid background color: %v", err)
+		}
+		composition.background = hex
+	}
+
+	if value, exists := arguments["rotationPeriodSeconds"]; exists {
+		seconds, ok := value.(float64)
+		if !ok || seconds < 0 {
+			return nil, fmt.Errorf("'rotationPeriodSeconds' must be a non-negative number")
+		}
+		if seconds > 0 {
+			whirl, err := device.ConvertRotationPeriodToWhirl(int(math.Round(seconds * 1000)))
+			if err != nil {
+				return nil, err
+			}
+			composition.whirlMs = whirl
+		}
+	}
+
+	if value, exists := arguments["counterClockwise"]; exists {
+		ccw, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("'counterClockwise' must be a boolean")
+		}
+		composition.counterClockwise = ccw
+	}
+
+	if value, exists := arguments["morph"]; exists {
+		morph, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("morph must be an object with brightnessMs and fadeMs properties")
+		}
+		brightnessMs, ok := wholeNumber(morph["brightnessMs"])
+		if !ok || brightnessMs < 0 {
+			return nil, fmt.Errorf("morph brightnessMs must be a non-negative whole number")
+		}
+		fadeMs, ok := wholeNumber(morph["fadeMs"])
+		if !ok || fadeMs < 100 || fadeMs > 10000 {
+			return nil, fmt.Errorf("morph fadeMs must be between 100 and 10000")
+		}
+		composition.morph = &device.MorphConfig{BrightnessMs: brightnessMs, FadeMs: fadeMs}
+	}
+
+	return composition, nil
+}
+
+// parseLayout validates the colors, palette, segments, gap and offset of a
+// composeRing request and lays out the ring's segments
+func parseLayout(arguments map[string]interface{}, store *palettes.Store) ([]string, error) {
+	var colors []string
+	if value, exists := arguments["palette"]; exists {
+		name, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("'palette' must be a string")
+		}
+		if _, hasColors := arguments["colors"]; hasColors {
+			return nil, fmt.Errorf("give either 'colors' or 'palette', not both")
+		}
+		paletteColors, err := store.Colors(name)
+		if err != nil {
+			return nil, err
+		}
+		colors = paletteColors
+	} else {
+		colorList, ok := arguments["colors"].([]interface{})
+		if !ok || len(colorList) == 0 {
+			return nil, fmt.Errorf("'colors' must be a non-empty array of colors, or give a 'palette'")
+		}
+		for i, value := range colorList {
+			spec, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("color at index %d must be a string", i)
+			}
+			hex, err := store.Color(spec)
+			if err != nil {
+				return nil, fmt.Errorf("invalid color at index %d: %v", i, err)
+			}
+			colors = append(colors, hex)
+		}
+	}
+
+	count := len(colors)
+	if value, exists := arguments["segments"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < 1 || n > device.RingLEDs {
+			return nil, fmt.Errorf("'segments' must be a whole number between 1 and %d", device.RingLEDs)
+		}
+		count = n
+	}
+
+	gap := 0
+	if value, exists := arguments["gap"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < 0 {
+			return nil, fmt.Errorf("'gap' must be a non-negative whole number")
+		}
+		gap = n
+	}
+
+	offset := 0
+	if value, exists := arguments["offset"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < 0 || n >= device.RingLEDs {
+			return nil, fmt.Errorf("'offset' must be a whole number between 0 and %d", device.RingLEDs-1)
+		}
+		offset = n
+	}
+
+	return layoutSegments(colors, count, gap, offset)
+}
+
+// layoutSegments divides the ring into count equal segments separated by gap
+// unlit LEDs, starting at offset. LEDs left over after an even split go one
+// each to the first segments. Segments that run past the last LED continue
+// from LED 0, which the device needs as two separate segments.
+func layoutSegments(colors []string, count, gap, offset int) ([]string, error) {
+	lit := device.RingLEDs - count*gap
+	if lit < count {
+		return nil, fmt.Errorf("%d segments with a gap of %d do not fit on a %d LED ring", count, gap, device.RingLEDs)
+	}
+
+	size, extra := lit/count, lit%count
+	var segments []string
+	start := offset
+	for i := 0; i < count; i++ {
+		length := size
+		if i < extra {
+			length++
+		}
+		segmentColor := colors[i%len(colors)]
+
+		first := start % device.RingLEDs
+		if first+length <= device.RingLEDs {
+			segments = append(segments, fmt.Sprintf("%d|%d|%s", first, length, segmentColor))
+		} else {
+			head := device.RingLEDs - first
+			segments = append(segments,
+				fmt.Sprintf("%d|%d|%s", first, head, segmentColor),
+				fmt.Sprintf("0|%d|%s", length-head, segmentColor))
+		}
+		start += length + gap
+	}
+	return segments, nil
+}
+
+// wholeNumber converts a JSON number argument to an int, rejecting fractions
+func wholeNumber(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, false
+		}
+		return int(v), true
+	}
+	return 0, false
+}
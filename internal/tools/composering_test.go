@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/palettes"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayoutSegments(t *testing.T) {
+	// 15 LEDs split three ways
+	segments, err := layoutSegments([]string{"FF0000", "00FF00", "0000FF"}, 3, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0|5|FF0000", "5|5|00FF00", "10|5|0000FF"}, segments)
+
+	// 4 segments with a gap of 1 leave 11 LEDs, so the first three get 3
+	segments, err = layoutSegments([]string{"FF0000", "FFFFFF"}, 4, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0|3|FF0000", "4|3|FFFFFF", "8|3|FF0000", "12|2|FFFFFF"}, segments)
+
+	// A segment running past the last LED is split in two
+	segments, err = layoutSegments([]string{"FF0000"}, 1, 5, 12)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"12|3|FF0000", "0|7|FF0000"}, segments)
+
+	_, err = layoutSegments([]string{"FF0000"}, 8, 1, 0)
+	assert.Error(t, err)
+}
+
+func TestComposeRingTool_Execute(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	tool := NewComposeRingTool(device.NewClient(), broadcaster, stateManager, nil)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"ring":                  "both",
+		"colors":                []interface{}{"red", "white"},
+		"segments":              float64(3),
+		"rotationPeriodSeconds": 3.0,
+		"counterClockwise":      true,
+		"morph":                 map[string]interface{}{"brightnessMs": float64(1000), "fadeMs": float64(500)},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+
+	segments := "0|5|ff0000|5|5|ffffff|10|5|ff0000"
+	assert.Contains(t, query, "top_init=1&top="+segments+"&top_whirl=200|ccw&top_morph=")
+	assert.Contains(t, query, "bottom_init=1&bottom="+segments+"&bottom_whirl=200|ccw")
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "one turn every 3.00s counter-clockwise")
+
+	snapshot := stateManager.Snapshot()
+	assert.Equal(t, 200, snapshot.TopWhirlMs)
+	assert.Equal(t, 200, snapshot.BottomWhirlMs)
+	assert.Equal(t, "ffffff", snapshot.Top[5])
+	require.NotNil(t, snapshot.BottomMorph)
+	assert.Equal(t, 500, snapshot.BottomMorph.FadeMs)
+}
+
+func TestComposeRingTool_ValidationErrors(t *testing.T) {
+	tool := NewComposeRingTool(device.NewClient(), events.NewBroadcaster(), nil, nil)
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		message   string
+	}{
+		{"missing ring", map[string]interface{}{"colors": []interface{}{"red"}}, "'ring' must be"},
+		{"missing colors", map[string]interface{}{"ring": "top"}, "'colors' must be"},
+		{"bad color", map[string]interface{}{"ring": "top", "colors": []interface{}{"nope"}}, "invalid color at index 0"},
+		{"too many segments", map[string]interface{}{"ring": "top", "colors": []interface{}{"red"}, "segments": float64(16)}, "'segments' must be"},
+		{"gaps too large", map[string]interface{}{"ring": "top", "colors": []interface{}{"red"}, "segments": float64(5), "gap": float64(3)}, "do not fit"},
+		{"rotation too slow", map[string]interface{}{"ring": "top", "colors": []interface{}{"red"}, "rotationPeriodSeconds": 10.0}, "rotation period must be between"},
+		{"bad morph", map[string]interface{}{"ring": "top", "colors": []interface{}{"red"}, "morph": map[string]interface{}{"brightnessMs": float64(0), "fadeMs": float64(50)}}, "fadeMs must be between"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Execute(context.Background(), tt.arguments)
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.message)
+		})
+	}
+}
+
+func TestParseComposition_Palette(t *testing.T) {
+	store := palettes.NewStore(filepath.Join(t.TempDir(), "palettes.json"))
+	_, _, err := store.Save(palettes.Palette{Name: "xmas", Colors: []string{"red", "green", "white"}})
+	require.NoError(t, err)
+
+	composition, err := parseComposition(map[string]interface{}{"ring": "top", "palette": "xmas"}, store)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0|5|ff0000", "5|5|008000", "10|5|ffffff"}, composition.segments)
+
+	composition, err = parseComposition(map[string]interface{}{
+		"ring":       "top",
+		"colors":     []interface{}{"xmas:2", "gold"},
+		"background": "xmas:3",
+	}, store)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0|8|008000", "8|7|ffd700"}, composition.segments)
+	assert.Equal(t, "ffffff", composition.background)
+
+	for name, arguments := range map[string]map[string]interface{}{
+		"unknown palette":    {"ring": "top", "palette": "easter"},
+		"colors and palette": {"ring": "top", "palette": "xmas", "colors": []interface{}{"red"}},
+		"out of range":       {"ring": "top", "colors": []interface{}{"xmas:4"}},
+	} {
+		_, err := parseComposition(arguments, store)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestParseComposition_Gradient(t *testing.T) {
+	composition, err := parseComposition(map[string]interface{}{
+		"ring":     "top",
+		"gradient": map[string]interface{}{"from": "black", "to": "white", "start": float64(12), "length": float64(5)},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"12|1|000000", "13|1|404040", "14|1|808080", "0|1|bfbfbf", "1|1|ffffff"}, composition.segments)
+
+	// Neighbouring LEDs of the same color share a segment
+	composition, err = parseComposition(map[string]interface{}{
+		"ring":     "bottom",
+		"gradient": map[string]interface{}{"from": "red", "to": "red", "easing": "easeInOut"},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0|15|ff0000"}, composition.segments)
+
+	for name, arguments := range map[string]map[string]interface{}{
+		"with colors":    {"ring": "top", "gradient": map[string]interface{}{"from": "red", "to": "blue"}, "colors": []interface{}{"red"}},
+		"missing to":     {"ring": "top", "gradient": map[string]interface{}{"from": "red"}},
+		"bad color":      {"ring": "top", "gradient": map[string]interface{}{"from": "red", "to": "nope"}},
+		"too short":      {"ring": "top", "gradient": map[string]interface{}{"from": "red", "to": "blue", "length": float64(1)}},
+		"unknown easing": {"ring": "top", "gradient": map[string]interface{}{"from": "red", "to": "blue", "easing": "bounce"}},
+	} {
+		_, err := parseComposition(arguments, nil)
+		assert.Error(t, err, name)
+	}
+}
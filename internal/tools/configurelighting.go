@@ -3,27 +3,40 @@ package tools
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
 	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+	"github.com/starspace46/ufo-mcp-go/internal/palettes"
 	"github.com/starspace46/ufo-mcp-go/internal/state"
 )
 
+// configEffectName names the effect stack entries configureLighting records
+const configEffectName = "__config__"
+
 // ConfigureLightingTool implements the configureLighting MCP tool
 type ConfigureLightingTool struct {
 	client       *device.Client
 	broadcaster  *events.Broadcaster
 	stateManager *state.Manager
+	engine       *effects.Engine // runs logo animations
+	palettes     *palettes.Store
 }
 
 // NewConfigureLightingTool creates a new configureLighting tool instance
-func NewConfigureLightingTool(client *device.Client, broadcaster *events.Broadcaster, stateManager *state.Manager) *ConfigureLightingTool {
+func NewConfigureLightingTool(client *device.Client, broadcaster *events.Broadcaster, stateManager *state.Manager, engine *effects.Engine, palettes *palettes.Store) *ConfigureLightingTool {
 	return &ConfigureLightingTool{
 		client:       client,
 		broadcaster:  broadcaster,
 		stateManager: stateManager,
+		engine:       engine,
+		palettes:     palettes,
 	}
 }
 
@@ -31,7 +44,7 @@ func NewConfigureLightingTool(client *device.Client, broadcaster *events.Broadca
 func (t *ConfigureLightingTool) Definition() mcp.Tool {
 	return mcp.Tool{
 		Name:        "configureLighting",
-		Description: "Configure the entire UFO lighting in one command - top ring, bottom ring, and logo. This is the most efficient way to set UFO lighting patterns.",
+		Description: "Configure the entire UFO lighting in one command - top ring, bottom ring, and logo. This is the most efficient way to set UFO lighting patterns. Use 'both' to give the two rings the same configuration, and mirrorBottom to make the bottom ring a mirror image of the top. Colors may refer to a saved palette as 'name:n', a ring's palette fills it with the palette's colors, and a ring's gradient blends smoothly from one color to another. A ring's brightness dims it relative to the other ring. Set passthrough to send exact color values, skipping the UFO's color correction. The logo can blink, pulse or alternate between two colors; the server animates it until the logo is configured again. Set stack to keep the configuration on the effect stack so it shows again when effects played over it end; later configurations update that entry while it is on top.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -41,15 +54,20 @@ func (t *ConfigureLightingTool) Definition() mcp.Tool {
 					"properties": map[string]interface{}{
 						"segments": map[string]interface{}{
 							"type":        "array",
-							"description": "Array of segment patterns in format 'position|length|color'",
+							"description": "Array of segment patterns in format 'position|length|color'. Color may be hex, #RGB, rgb(r,g,b), a CSS color name or a palette color such as 'xmas:2'",
 							"items":       map[string]interface{}{"type": "string"},
-							"examples":    []interface{}{[]string{"0|5|FF0000", "10|5|00FF00"}},
+							"examples":    []interface{}{[]string{"0|5|FF0000", "10|5|green", "5|5|xmas:1"}},
+						},
+						"palette": map[string]interface{}{
+							"type":        "string",
+							"description": "Name of a saved palette to divide the ring into equal segments, one per palette color. Cannot be combined with segments",
+							"examples":    []string{"xmas"},
 						},
+						"gradient": gradientSchema("Smooth gradient from one color to another, computed per LED. Cannot be combined with segments or palette"),
 						"background": map[string]interface{}{
 							"type":        "string",
-							"description": "Background color for unlit LEDs (6-char hex)",
-							"pattern":     "^[0-9A-Fa-f]{6}$",
-							"examples":    []string{"000000", "202020"},
+							"description": "Background color for unlit LEDs (hex, #RGB, rgb(r,g,b) or CSS color name)",
+							"examples":    []string{"000000", "202020", "midnightblue"},
 						},
 						"whirl": map[string]interface{}{
 							"type":        "integer",
@@ -62,6 +80,7 @@ func (t *ConfigureLightingTool) Definition() mcp.Tool {
 							"description": "Rotate counter-clockwise if true",
 							"default":     false,
 						},
+						"brightness": ringBrightnessSchema,
 						"morph": map[string]interface{}{
 							"type":        "object",
 							"description": "Morph/fade effect configuration",
@@ -93,10 +112,14 @@ func (t *ConfigureLightingTool) Definition() mcp.Tool {
 							"description": "Array of segment patterns",
 							"items":       map[string]interface{}{"type": "string"},
 						},
+						"palette": map[string]interface{}{
+							"type":        "string",
+							"description": "Name of a saved palette to fill the ring with",
+						},
+						"gradient": gradientSchema("Smooth gradient from one color to another"),
 						"background": map[string]interface{}{
 							"type":        "string",
 							"description": "Background color for unlit LEDs",
-							"pattern":     "^[0-9A-Fa-f]{6}$",
 						},
 						"whirl": map[string]interface{}{
 							"type":        "integer",
@@ -109,6 +132,7 @@ func (t *ConfigureLightingTool) Definition() mcp.Tool {
 							"description": "Rotate counter-clockwise",
 							"default":     false,
 						},
+						"brightness": ringBrightnessSchema,
 						"morph": map[string]interface{}{
 							"type":        "object",
 							"description": "Morph/fade effect configuration",
@@ -129,6 +153,15 @@ func (t *ConfigureLightingTool) Definition() mcp.Tool {
 						},
 					},
 				},
+				"both": map[string]interface{}{
+					"type":        "object",
+					"description": "Configuration applied to both rings at once (same options as top); cannot be combined with top or bottom",
+				},
+				"mirrorBottom": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Give the bottom ring a mirror image of the top (or both) configuration: LED order reversed and rotation direction swapped. Cannot be combined with bottom",
+					"default":     false,
+				},
 				"logo": map[string]interface{}{
 					"type":        "object",
 					"description": "Logo configuration",
@@ -140,13 +173,22 @@ func (t *ConfigureLightingTool) Definition() mcp.Tool {
 						},
 						"color1": map[string]interface{}{
 							"type":        "string",
-							"description": "First logo color (6-char hex)",
-							"pattern":     "^[0-9A-Fa-f]{6}$",
+							"description": "First logo color (hex, #RGB, rgb(r,g,b) or CSS color name)",
 						},
 						"color2": map[string]interface{}{
 							"type":        "string",
-							"description": "Second logo color (6-char hex)",
-							"pattern":     "^[0-9A-Fa-f]{6}$",
+							"description": "Second logo color (hex, #RGB, rgb(r,g,b) or CSS color name)",
+						},
+						"animation": map[string]interface{}{
+							"type":        "string",
+							"description": "Animate the logo: 'blink' switches color1 (default white) on and off, or to color2; 'pulse' fades color1 in and out; 'alternate' switches between color1 and color2; 'none' stops an animation (optional)",
+							"enum":        []string{state.LogoBlink, state.LogoPulse, state.LogoAlternate, "none"},
+						},
+						"periodMs": map[string]interface{}{
+							"type":        "integer",
+							"description": fmt.Sprintf("Length of one animation cycle in milliseconds (%d-%d, default %d)", minLogoPeriodMs, maxLogoPeriodMs, defaultLogoPeriodMs),
+							"minimum":     minLogoPeriodMs,
+							"maximum":     maxLogoPeriodMs,
 						},
 					},
 				},
@@ -156,15 +198,178 @@ func (t *ConfigureLightingTool) Definition() mcp.Tool {
 					"minimum":     0,
 					"maximum":     255,
 				},
+				"applyAndVerify": map[string]interface{}{
+					"type":        "boolean",
+					"description": "For critical changes: after sending, read the UFO's state back (or re-send when the firmware does not report it) and fail if the UFO does not show the requested lighting (optional, default false)",
+					"default":     false,
+				},
+				"passthrough": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Send colors and brightness exactly as given, skipping the UFO's color correction (optional, default false)",
+					"default":     false,
+				},
+				"stack": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Push the configuration onto the effect stack as a new __config__ entry, layered over the effect showing, instead of updating the __config__ entry on top (optional, default false)",
+					"default":     false,
+				},
 			},
 		},
 	}
 }
 
+// lightingConfig is a validated configureLighting request
+type lightingConfig struct {
+	query      string   // combined query sent to the UFO
+	messages   []string // description of each configured part
+	brightness *int     // set when the request changes brightness
+	logoOn     *bool    // set when the request turns the logo on or off
+	// logoAnimation is set when the request animates the logo; the query
+	// holds its first frame
+	logoAnimation *state.LogoAnimation
+	// ringBrightness holds the brightness percentage of each ring the
+	// request sets it for
+	ringBrightness map[string]int
+	// rings holds the parsed query of each ring the request configures.
+	// Rings are initialized, so one without a whirl or morph stops it.
+	rings map[string]*device.RingPattern
+}
+
+// ringBrightnessSchema is the JSON schema of a ring's brightness
+var ringBrightnessSchema = map[string]interface{}{
+	"type":        "integer",
+	"description": "Brightness of this ring in percent of the global brightness (1-100). The server scales the ring's colors before sending, also for effects shown later, until the ring is configured with another brightness (optional, default unchanged)",
+	"minimum":     1,
+	"maximum":     device.FullRingBrightness,
+}
+
 // Execute runs the configureLighting tool
 func (t *ConfigureLightingTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	config, err := t.parseConfig(arguments)
+	if err != nil {
+		return toolError(CodeInvalidArgument, fmt.Sprintf("%v", err)), nil
+	}
+
+	verify := false
+	if value, exists := arguments["applyAndVerify"]; exists {
+		b, ok := value.(bool)
+		if !ok {
+			return invalidArgument("applyAndVerify", "applyAndVerify must be true or false"), nil
+		}
+		verify = b
+	}
+	if value, exists := arguments["passthrough"]; exists {
+		passthrough, ok := value.(bool)
+		if !ok {
+			return invalidArgument("passthrough", "passthrough must be true or false"), nil
+		}
+		if passthrough {
+			ctx = device.WithPassthrough(ctx)
+		}
+	}
+	layer := false
+	if value, exists := arguments["stack"]; exists {
+		b, ok := value.(bool)
+		if !ok {
+			return invalidArgument("stack", "stack must be true or false"), nil
+		}
+		layer = b
+	}
+
+	// If no configurations provided
+	if config.query == "" {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: "No lighting configuration provided",
+				},
+			},
+			IsError: false,
+		}, nil
+	}
+
+	// A layered configuration covers the effect showing
+	if layer {
+		if full := stackFull(t.stateManager); full != nil {
+			return full, nil
+		}
+		if t.engine != nil {
+			t.engine.Stop()
+		}
+	}
+
+	// A crossfade still running would fade over the new configuration
+	if t.engine != nil && t.engine.Running() == transitionName {
+		t.engine.Stop()
+	}
+
+	// A running logo animation would draw over the new logo
+	if config.logoOn != nil && t.engine != nil {
+		t.engine.StopLogo()
+	}
+
+	// Ring colors are scaled to the rings' brightness as they are sent, so a
+	// new brightness takes effect first and is undone if sending fails
+	restoreRingBrightness := t.setRingBrightness(config)
+
+	// Send all parts to the UFO in one request. When verifying, the send and
+	// the check are one device transaction so no other write lands between
+	// them and spoils the check.
+	var note string
+	verified := true
+	send := func(ctx context.Context) error {
+		if _, err := t.client.SendRawQuery(ctx, config.query); err != nil {
+			return err
+		}
+		if verify {
+			note, verified = verifyApplied(ctx, t.client, config.query)
+		}
+		return nil
+	}
+	if verify {
+		err = t.client.Atomically(ctx, send)
+	} else {
+		err = send(ctx)
+	}
+	if err != nil {
+		restoreRingBrightness()
+		t.broadcaster.PublishRawExecuted(ctx, config.query, fmt.Sprintf("ERROR: %v", err))
+		if layer && t.engine != nil && t.stateManager.GetCurrentEffect() != nil {
+			if restoreErr := restoreTop(ctx, t.engine, t.broadcaster, t.stateManager); restoreErr != nil {
+				slog.WarnContext(ctx, "Failed to restore effect after UFO error", "error", restoreErr)
+			}
+		}
+		return toolError(CodeDeviceError, fmt.Sprintf("Failed to configure lighting: %v", err)), nil
+	}
+
+	t.broadcaster.PublishRawExecuted(ctx, config.query, "OK")
+	t.updateState(config)
+
+	// Build success message
+	successMsg := "✨ UFO lighting configured successfully!\n\n" + strings.Join(config.messages, "\n")
+	successMsg += t.stackConfig(ctx, config.query, layer)
+	if verify {
+		successMsg += "\n\n" + note
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: successMsg,
+			},
+		},
+		IsError: !verified,
+	}, nil
+}
+
+// parseConfig validates the brightness, ring and logo parts of a request and
+// combines them into a single query. The query is empty when nothing is
+// configured.
+func (t *ConfigureLightingTool) parseConfig(arguments map[string]interface{}) (*lightingConfig, error) {
+	config := &lightingConfig{}
 	var queries []string
-	var messages []string
 
 	// Process brightness if provided
 	if brightnessVal, hasBrightness := arguments["brightness"]; hasBrightness {
@@ -175,129 +380,371 @@ func (t *ConfigureLightingTool) Execute(ctx context.Context, arguments map[strin
 		case int:
 			brightness = v
 		}
-		
+
 		if brightness < 0 || brightness > 255 {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Error: brightness must be between 0 and 255",
-					},
-				},
-				IsError: true,
-			}, nil
+			return nil, fmt.Errorf("brightness must be between 0 and 255")
 		}
-		
+
 		queries = append(queries, fmt.Sprintf("dim=%d", brightness))
-		messages = append(messages, fmt.Sprintf("Brightness set to %d", brightness))
-		t.stateManager.UpdateBrightness(brightness)
+		config.messages = append(config.messages, fmt.Sprintf("Brightness set to %d", brightness))
+		config.brightness = &brightness
 	}
 
-	// Process top ring
-	if topConfig, hasTop := arguments["top"].(map[string]interface{}); hasTop {
-		query, msg, err := t.buildRingQuery("top", topConfig)
+	// Process top and bottom rings
+	arguments, err := t.expandRingColors(arguments)
+	if err != nil {
+		return nil, err
+	}
+	rings, mirrored, err := ringConfigs(arguments)
+	if err != nil {
+		return nil, err
+	}
+	for _, ring := range []string{"top", "bottom"} {
+		ringConfig, ok := rings[ring]
+		if !ok {
+			continue
+		}
+		query, msg, err := t.buildRingQuery(ring, ringConfig)
 		if err != nil {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: fmt.Sprintf("Error in top ring config: %v", err),
-					},
-				},
-				IsError: true,
-			}, nil
+			return nil, fmt.Errorf("invalid %s ring config: %v", ring, err)
+		}
+		if value, exists := ringConfig["brightness"]; exists {
+			percent, ok := wholeNumber(value)
+			if !ok || percent < 1 || percent > device.FullRingBrightness {
+				return nil, fmt.Errorf("invalid %s ring config: brightness must be a whole percentage between 1 and %d", ring, device.FullRingBrightness)
+			}
+			if config.ringBrightness == nil {
+				config.ringBrightness = map[string]int{}
+			}
+			config.ringBrightness[ring] = percent
+			msg += fmt.Sprintf(", brightness %d%%", percent)
 		}
 		if query != "" {
 			queries = append(queries, query)
-			messages = append(messages, "Top ring: "+msg)
+			if pattern := ringPattern(ring, query); pattern != nil {
+				if config.rings == nil {
+					config.rings = map[string]*device.RingPattern{}
+				}
+				config.rings[ring] = pattern
+			}
+			if ring == "bottom" && mirrored {
+				msg += " (mirrored)"
+			}
+			config.messages = append(config.messages, strings.ToUpper(ring[:1])+ring[1:]+" ring: "+msg)
 		}
 	}
 
-	// Process bottom ring
-	if bottomConfig, hasBottom := arguments["bottom"].(map[string]interface{}); hasBottom {
-		query, msg, err := t.buildRingQuery("bottom", bottomConfig)
+	// Process logo
+	if logoConfig, hasLogo := arguments["logo"].(map[string]interface{}); hasLogo {
+		query, msg, on, animation, err := t.buildLogoQuery(logoConfig)
 		if err != nil {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: fmt.Sprintf("Error in bottom ring config: %v", err),
-					},
-				},
-				IsError: true,
-			}, nil
+			return nil, fmt.Errorf("invalid logo config: %v", err)
 		}
 		if query != "" {
 			queries = append(queries, query)
-			messages = append(messages, "Bottom ring: "+msg)
+			config.messages = append(config.messages, "Logo: "+msg)
+			config.logoOn = &on
+			config.logoAnimation = animation
 		}
 	}
 
-	// Process logo
-	if logoConfig, hasLogo := arguments["logo"].(map[string]interface{}); hasLogo {
-		query, msg, err := t.buildLogoQuery(logoConfig)
-		if err != nil {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: fmt.Sprintf("Error in logo config: %v", err),
-					},
-				},
-				IsError: true,
-			}, nil
+	config.query = strings.Join(queries, "&")
+	return config, nil
+}
+
+// ringConfigs returns the configuration of each ring in a request. 'both'
+// configures the two rings alike, and mirrorBottom gives the bottom ring a
+// mirrored copy of the top configuration; the second result reports whether
+// it did.
+func ringConfigs(arguments map[string]interface{}) (map[string]map[string]interface{}, bool, error) {
+	rings := map[string]map[string]interface{}{}
+	for _, ring := range []string{"top", "bottom"} {
+		if ringConfig, ok := arguments[ring].(map[string]interface{}); ok {
+			rings[ring] = ringConfig
 		}
-		if query != "" {
-			queries = append(queries, query)
-			messages = append(messages, "Logo: "+msg)
+	}
+	if value, exists := arguments["both"]; exists {
+		ringConfig, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false, fmt.Errorf("both must be a ring configuration object")
+		}
+		if len(rings) > 0 {
+			return nil, false, fmt.Errorf("both cannot be combined with top or bottom")
 		}
+		rings["top"], rings["bottom"] = ringConfig, ringConfig
 	}
 
-	// If no configurations provided
-	if len(queries) == 0 {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "No lighting configuration provided",
-				},
-			},
-			IsError: false,
-		}, nil
+	value, exists := arguments["mirrorBottom"]
+	if !exists {
+		return rings, false, nil
+	}
+	mirror, ok := value.(bool)
+	if !ok {
+		return nil, false, fmt.Errorf("mirrorBottom must be true or false")
+	}
+	if !mirror {
+		return rings, false, nil
+	}
+	if _, hasBottom := arguments["bottom"]; hasBottom {
+		return nil, false, fmt.Errorf("mirrorBottom copies the top ring, so bottom cannot be given too")
+	}
+	top, ok := rings["top"]
+	if !ok {
+		return nil, false, fmt.Errorf("mirrorBottom needs a top or both ring configuration")
+	}
+	bottom, err := mirrorRing(top)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid top ring config: %v", err)
+	}
+	rings["bottom"] = bottom
+	return rings, true, nil
+}
+
+// expandRingColors returns arguments with the palette or gradient of each
+// ring configuration replaced by the segments drawing it
+func (t *ConfigureLightingTool) expandRingColors(arguments map[string]interface{}) (map[string]interface{}, error) {
+	expanded := make(map[string]interface{}, len(arguments))
+	for key, value := range arguments {
+		expanded[key] = value
+	}
+	for _, ring := range []string{"top", "bottom", "both"} {
+		ringConfig, ok := arguments[ring].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		paletteValue, hasPalette := ringConfig["palette"]
+		gradientValue, hasGradient := ringConfig["gradient"]
+		if !hasPalette && !hasGradient {
+			continue
+		}
+		if hasPalette && hasGradient {
+			return nil, fmt.Errorf("invalid %s ring config: give either palette or gradient, not both", ring)
+		}
+		if _, hasSegments := ringConfig["segments"]; hasSegments {
+			kind := "palette"
+			if hasGradient {
+				kind = "gradient"
+			}
+			return nil, fmt.Errorf("invalid %s ring config: %s cannot be combined with segments", ring, kind)
+		}
+
+		var segments []string
+		if hasGradient {
+			var err error
+			segments, err = parseGradient(gradientValue, t.palettes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s ring config: %v", ring, err)
+			}
+		} else {
+			name, ok := paletteValue.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid %s ring config: palette must be a string", ring)
+			}
+			colors, err := t.palettes.Colors(name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s ring config: %v", ring, err)
+			}
+			segments, err = layoutSegments(colors, len(colors), 0, 0)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s ring config: %v", ring, err)
+			}
+		}
+
+		withSegments := make(map[string]interface{}, len(ringConfig))
+		for key, value := range ringConfig {
+			withSegments[key] = value
+		}
+		delete(withSegments, "palette")
+		delete(withSegments, "gradient")
+		list := make([]interface{}, len(segments))
+		for i, segment := range segments {
+			list[i] = segment
+		}
+		withSegments["segments"] = list
+		expanded[ring] = withSegments
+	}
+	return expanded, nil
+}
+
+// mirrorRing returns a copy of a ring configuration with the LED order
+// reversed and the rotation direction swapped, so the two rings mirror each
+// other
+func mirrorRing(ringConfig map[string]interface{}) (map[string]interface{}, error) {
+	mirrored := make(map[string]interface{}, len(ringConfig)+1)
+	for key, value := range ringConfig {
+		mirrored[key] = value
+	}
+	if segments, ok := ringConfig["segments"].([]interface{}); ok {
+		flipped := make([]interface{}, len(segments))
+		for i, segment := range segments {
+			s, ok := segment.(string)
+			if !ok {
+				// Left for buildRingQuery to report
+				flipped[i] = segment
+				continue
+			}
+			m, err := mirrorSegment(s)
+			if err != nil {
+				return nil, fmt.Errorf("cannot mirror segment %s: %v", s, err)
+			}
+			flipped[i] = m
+		}
+		mirrored["segments"] = flipped
+	}
+	if _, hasWhirl := ringConfig["whirl"]; hasWhirl {
+		ccw, _ := ringConfig["counterClockwise"].(bool)
+		mirrored["counterClockwise"] = !ccw
+	}
+	return mirrored, nil
+}
+
+// mirrorSegment reflects a 'position|length|color' segment so that LED i
+// becomes LED 14-i
+func mirrorSegment(segment string) (string, error) {
+	parts := strings.SplitN(segment, "|", 3)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("expected 3 '|'-separated parts, got %d", len(parts))
+	}
+	start, startErr := strconv.Atoi(parts[0])
+	length, lengthErr := strconv.Atoi(parts[1])
+	if startErr != nil || lengthErr != nil || start < 0 || start >= device.RingLEDs || length < 1 || length > device.RingLEDs {
+		return "", fmt.Errorf("position must be 0-%d and length 1-%d", device.RingLEDs-1, device.RingLEDs)
 	}
+	end := start + length - 1
+	mirroredStart := ((device.RingLEDs-1-end)%device.RingLEDs + device.RingLEDs) % device.RingLEDs
+	return fmt.Sprintf("%d|%d|%s", mirroredStart, length, parts[2]), nil
+}
 
-	// Combine all queries and send in one request
-	combinedQuery := strings.Join(queries, "&")
-	
-	// Send to UFO
-	_, err := t.client.SendRawQuery(ctx, combinedQuery)
+// ringPattern returns what a ring query sets on the ring, or nil if it
+// cannot be read
+func ringPattern(ring, query string) *device.RingPattern {
+	pattern, err := device.ParsePattern(query)
 	if err != nil {
-		t.broadcaster.PublishRawExecuted(combinedQuery, fmt.Sprintf("ERROR: %v", err))
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Failed to configure lighting: %v", err),
-				},
-			},
-			IsError: true,
-		}, nil
+		return nil
+	}
+	if ring == "top" {
+		return pattern.Top
 	}
+	return pattern.Bottom
+}
 
-	t.broadcaster.PublishRawExecuted(combinedQuery, "OK")
+// updateState records the brightness, logo and ring animations of a
+// configuration that was sent to the UFO in the shadow state, and starts or
+// stops the logo animation
+func (t *ConfigureLightingTool) updateState(config *lightingConfig) {
+	for ring, pattern := range config.rings {
+		var whirl device.Whirl
+		if pattern.Whirl != nil {
+			whirl = *pattern.Whirl
+		}
+		t.stateManager.UpdateWhirl(ring, whirl.Ms, whirl.CounterClockwise)
+		if pattern.Morph != nil {
+			t.stateManager.UpdateMorph(ring, pattern.Morph.BrightnessMs, pattern.Morph.FadeMs)
+		} else {
+			t.stateManager.ClearMorph(ring)
+		}
+	}
+	if config.brightness != nil {
+		t.stateManager.UpdateBrightness(*config.brightness)
+	}
+	if config.logoOn != nil {
+		t.stateManager.UpdateLogo(*config.logoOn)
+		t.stateManager.UpdateLogoAnimation(config.logoAnimation)
+		if t.engine == nil {
+			return
+		}
+		if config.logoAnimation != nil {
+			t.engine.AnimateLogo(logoSteps(*config.logoAnimation))
+		} else {
+			t.engine.StopLogo()
+		}
+	}
+}
 
-	// Build success message
-	successMsg := "✨ UFO lighting configured successfully!\n\n" + strings.Join(messages, "\n")
+// stackConfig records a configuration sent to the UFO as a synthetic
+// __config__ entry on the effect stack, so it shows again when effects
+// played over it end. While such an entry is on top, a new configuration
+// updates it rather than piling another entry on it; layer pushes a new
+// entry regardless. Configurations are otherwise left off the stack. It
+// returns a line for the reply, empty when nothing was recorded.
+func (t *ConfigureLightingTool) stackConfig(ctx context.Context, query string, layer bool) string {
+	top := t.stateManager.GetCurrentEffect()
+	onConfig := top != nil && top.Name == configEffectName
+	if onConfig {
+		query = mergeConfigQuery(top.Pattern, query)
+	}
+	if !layer {
+		if !onConfig {
+			return ""
+		}
+		item := *top
+		item.Pattern = query
+		t.stateManager.ReplaceEffect(func(candidate state.EffectStackItem) bool {
+			return candidate.InstanceID() == top.InstanceID()
+		}, item)
+		return fmt.Sprintf("\n\n• Updated stack entry %s (instance %s)", configEffectName, top.InstanceID())
+	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: successMsg,
-			},
-		},
-		IsError: false,
-	}, nil
+	instanceID := state.NewInstanceID()
+	effectContext := map[string]interface{}{
+		"instanceId": instanceID,
+		"duration":   0,
+		"perpetual":  true,
+		"startTime":  time.Now(),
+		"synthetic":  true,
+	}
+	_, stale := t.stateManager.PushEffect(configEffectName, query, effectContext)
+	restoreStale(ctx, stale, t.engine, t.broadcaster, t.stateManager)
+	t.broadcaster.PublishPayload(ctx, events.EffectStarted{
+		Effect:     configEffectName,
+		InstanceID: instanceID,
+		Duration:   0,
+		Pattern:    query,
+		StackDepth: t.stateManager.GetEffectStackDepth(),
+	})
+	return fmt.Sprintf("\n\n• Stacked as %s (instance %s); use stopEffect to remove it", configEffectName, instanceID)
+}
+
+// mergeConfigQuery combines the query of a recorded configuration with a
+// newer one: the brightness, logo or ring parts the newer query sets replace
+// those of the older, and the rest are kept
+func mergeConfigQuery(previous, next string) string {
+	part := func(param string) string {
+		key, _, _ := strings.Cut(param, "=")
+		key, _, _ = strings.Cut(key, "_")
+		return key
+	}
+	set := map[string]bool{}
+	for _, param := range strings.Split(next, "&") {
+		set[part(param)] = true
+	}
+	var merged []string
+	for _, param := range strings.Split(previous, "&") {
+		if param != "" && !set[part(param)] {
+			merged = append(merged, param)
+		}
+	}
+	return strings.Join(append(merged, next), "&")
+}
+
+// setRingBrightness sets the ring brightness the request configures and
+// returns a function restoring the previous brightness
+func (t *ConfigureLightingTool) setRingBrightness(config *lightingConfig) func() {
+	if len(config.ringBrightness) == 0 {
+		return func() {}
+	}
+	top, bottom := t.stateManager.RingBrightness()
+	newTop, newBottom := top, bottom
+	if percent, ok := config.ringBrightness["top"]; ok {
+		newTop = percent
+	}
+	if percent, ok := config.ringBrightness["bottom"]; ok {
+		newBottom = percent
+	}
+	t.stateManager.SetRingBrightness(newTop, newBottom)
+	return func() {
+		t.stateManager.SetRingBrightness(top, bottom)
+	}
 }
 
 // buildRingQuery builds a query string for a ring configuration
@@ -321,10 +768,15 @@ func (t *ConfigureLightingTool) buildRingQuery(ring string, config map[string]in
 			if !ok {
 				return "", "", fmt.Errorf("segment must be a string")
 			}
-			if !isValidSegmentFormat(segStr) {
-				return "", "", fmt.Errorf("invalid segment format: %s", segStr)
+			resolved, err := resolveSegmentColor(t.palettes, segStr)
+			if err != nil {
+				return "", "", fmt.Errorf("invalid segment color: %s (%v)", segStr, err)
+			}
+			normalized, err := normalizeSegment(resolved)
+			if err != nil {
+				return "", "", fmt.Errorf("invalid segment format: %s (%v)", segStr, err)
 			}
-			segmentStrs = append(segmentStrs, segStr)
+			segmentStrs = append(segmentStrs, normalized)
 		}
 
 		if len(segmentStrs) > 0 {
@@ -339,9 +791,11 @@ func (t *ConfigureLightingTool) buildRingQuery(ring string, config map[string]in
 		if !ok {
 			return "", "", fmt.Errorf("background must be a string")
 		}
-		if !isValidHexColor(bg) {
-			return "", "", fmt.Errorf("invalid background color: %s", bg)
+		bgHex, err := t.palettes.Color(bg)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid background color: %v", err)
 		}
+		bg = bgHex
 		queryParts = append(queryParts, fmt.Sprintf("%s_bg=%s", ring, bg))
 		message = append(message, fmt.Sprintf("background #%s", bg))
 	}
@@ -365,9 +819,9 @@ func (t *ConfigureLightingTool) buildRingQuery(ring string, config map[string]in
 			ccw, _ := config["counterClockwise"].(bool)
 			if ccw {
 				whirlStr += "|ccw"
-				message = append(message, fmt.Sprintf("rotating CCW at %dms", whirl))
+				message = append(message, fmt.Sprintf("rotating CCW every %s", format.Millis(int64(whirl))))
 			} else {
-				message = append(message, fmt.Sprintf("rotating CW at %dms", whirl))
+				message = append(message, fmt.Sprintf("rotating CW every %s", format.Millis(int64(whirl))))
 			}
 			queryParts = append(queryParts, fmt.Sprintf("%s_whirl=%s", ring, whirlStr))
 		}
@@ -425,33 +879,66 @@ func (t *ConfigureLightingTool) buildRingQuery(ring string, config map[string]in
 		})
 
 		queryParts = append(queryParts, fmt.Sprintf("%s_morph=%s", ring, morphDevice))
-		message = append(message, fmt.Sprintf("morphing %dms bright, %dms fade", brightnessMs, fadeMs))
+		message = append(message, fmt.Sprintf("morphing %s bright, %s fade", format.Millis(int64(brightnessMs)), format.Millis(int64(fadeMs))))
 	}
 
 	return strings.Join(queryParts, "&"), strings.Join(message, ", "), nil
 }
 
-// buildLogoQuery builds a query string for logo configuration
-func (t *ConfigureLightingTool) buildLogoQuery(config map[string]interface{}) (string, string, error) {
-	state, _ := config["state"].(string)
+// buildLogoQuery builds a query string for logo configuration and reports
+// whether it turns the logo on and how it is animated. An animated logo's
+// query is its first frame.
+func (t *ConfigureLightingTool) buildLogoQuery(config map[string]interface{}) (string, string, bool, *state.LogoAnimation, error) {
+	logoState, _ := config["state"].(string)
 	color1, _ := config["color1"].(string)
 	color2, _ := config["color2"].(string)
 
+	if mode, _ := config["animation"].(string); mode != "" && mode != "none" {
+		if logoState == "off" {
+			return "", "", false, nil, fmt.Errorf("an animated logo cannot be off")
+		}
+		if t.engine == nil {
+			return "", "", false, nil, fmt.Errorf("logo animations are not available here")
+		}
+		for _, color := range []*string{&color1, &color2} {
+			if *color == "" {
+				continue
+			}
+			hex, err := t.palettes.Color(*color)
+			if err != nil {
+				return "", "", false, nil, fmt.Errorf("invalid color: %v", err)
+			}
+			*color = hex
+		}
+		animation, err := logoAnimation(config, color1, color2)
+		if err != nil {
+			return "", "", false, nil, err
+		}
+		return logoSteps(*animation)[0].Pattern, describeLogoAnimation(*animation), true, animation, nil
+	}
+
 	var query string
 	var message string
 
-	if state == "off" {
+	if logoState == "off" {
 		query = "logo=000000|000000|000000|000000"
 		message = "turned off"
-		t.stateManager.UpdateLogo(false)
-	} else if state == "on" || color1 != "" || color2 != "" {
+	} else if logoState == "on" || color1 != "" || color2 != "" || config["animation"] == "none" {
 		if color1 != "" || color2 != "" {
-			// Validate colors
-			if color1 != "" && !isValidHexColor(color1) {
-				return "", "", fmt.Errorf("invalid color1: %s", color1)
+			// Validate colors and convert to hex
+			if color1 != "" {
+				hex, err := t.palettes.Color(color1)
+				if err != nil {
+					return "", "", false, nil, fmt.Errorf("invalid color1: %v", err)
+				}
+				color1 = hex
 			}
-			if color2 != "" && !isValidHexColor(color2) {
-				return "", "", fmt.Errorf("invalid color2: %s", color2)
+			if color2 != "" {
+				hex, err := t.palettes.Color(color2)
+				if err != nil {
+					return "", "", false, nil, fmt.Errorf("invalid color2: %v", err)
+				}
+				color2 = hex
 			}
 
 			// Build pattern
@@ -471,8 +958,21 @@ func (t *ConfigureLightingTool) buildLogoQuery(config map[string]interface{}) (s
 			query = "logo=on"
 			message = "turned on"
 		}
-		t.stateManager.UpdateLogo(true)
 	}
 
-	return query, message, nil
-}
\ No newline at end of file
+	return query, message, logoState != "off", nil, nil
+}
+
+// resolveSegmentColor replaces a palette color reference such as 'xmas:2'
+// in a 'position|length|color' segment with its hex value
+func resolveSegmentColor(store *palettes.Store, segment string) (string, error) {
+	parts := strings.SplitN(segment, "|", 3)
+	if len(parts) != 3 || !strings.Contains(parts[2], ":") {
+		return segment, nil
+	}
+	hex, err := store.Color(parts[2])
+	if err != nil {
+		return "", err
+	}
+	return parts[0] + "|" + parts[1] + "|" + hex, nil
+}
@@ -0,0 +1,308 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/palettes"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigureLightingTool_BothRings(t *testing.T) {
+	tool := NewConfigureLightingTool(nil, nil, nil, nil, nil)
+
+	config, err := tool.parseConfig(map[string]interface{}{
+		"both": map[string]interface{}{
+			"segments":   []interface{}{"0|5|red"},
+			"background": "000000",
+			"whirl":      float64(200),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "top_init=1&top=0|5|ff0000&top_bg=000000&top_whirl=200&bottom_init=1&bottom=0|5|ff0000&bottom_bg=000000&bottom_whirl=200", config.query)
+	assert.Len(t, config.messages, 2)
+}
+
+func TestConfigureLightingTool_MirrorBottom(t *testing.T) {
+	tool := NewConfigureLightingTool(nil, nil, nil, nil, nil)
+
+	config, err := tool.parseConfig(map[string]interface{}{
+		"top": map[string]interface{}{
+			"segments": []interface{}{"0|5|red", "12|5|blue"},
+			"whirl":    float64(100),
+		},
+		"mirrorBottom": true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "top_init=1&top=0|5|ff0000|12|5|0000ff&top_whirl=100&bottom_init=1&bottom=10|5|ff0000|13|5|0000ff&bottom_whirl=100|ccw", config.query)
+	assert.Contains(t, config.messages[1], "(mirrored)")
+
+	// A counter-clockwise top ring turns clockwise on the bottom
+	config, err = tool.parseConfig(map[string]interface{}{
+		"both":         map[string]interface{}{"whirl": float64(100), "counterClockwise": true},
+		"mirrorBottom": true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "top_init=1&top_whirl=100|ccw&bottom_init=1&bottom_whirl=100", config.query)
+}
+
+func TestConfigureLightingTool_RingTargetErrors(t *testing.T) {
+	tool := NewConfigureLightingTool(nil, nil, nil, nil, nil)
+	ring := map[string]interface{}{"background": "red"}
+
+	cases := map[string]map[string]interface{}{
+		"both with top":        {"both": ring, "top": ring},
+		"both not an object":   {"both": "red"},
+		"mirror with bottom":   {"top": ring, "bottom": ring, "mirrorBottom": true},
+		"mirror without top":   {"mirrorBottom": true},
+		"mirror not a boolean": {"top": ring, "mirrorBottom": "yes"},
+		"unmirrorable segment": {"top": map[string]interface{}{"segments": []interface{}{"x|5|red"}}, "mirrorBottom": true},
+	}
+	for name, arguments := range cases {
+		_, err := tool.parseConfig(arguments)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestConfigureLightingTool_Palettes(t *testing.T) {
+	store := palettes.NewStore(filepath.Join(t.TempDir(), "palettes.json"))
+	_, _, err := store.Save(palettes.Palette{Name: "brand", Colors: []string{"1496ff", "6f2da8", "b4dc00"}})
+	require.NoError(t, err)
+	tool := NewConfigureLightingTool(nil, nil, nil, nil, store)
+
+	config, err := tool.parseConfig(map[string]interface{}{
+		"top":          map[string]interface{}{"palette": "brand", "background": "brand:2"},
+		"mirrorBottom": true,
+		"logo":         map[string]interface{}{"color1": "brand:3"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "top_init=1&top=0|5|1496ff|5|5|6f2da8|10|5|b4dc00&top_bg=6f2da8"+
+		"&bottom_init=1&bottom=10|5|1496ff|5|5|6f2da8|0|5|b4dc00&bottom_bg=6f2da8&logo=b4dc00", config.query)
+
+	config, err = tool.parseConfig(map[string]interface{}{
+		"bottom": map[string]interface{}{"segments": []interface{}{"0|3|brand:1", "3|3|red"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "bottom_init=1&bottom=0|3|1496ff|3|3|ff0000", config.query)
+
+	for name, arguments := range map[string]map[string]interface{}{
+		"unknown palette":    {"top": map[string]interface{}{"palette": "xmas"}},
+		"with segments":      {"top": map[string]interface{}{"palette": "brand", "segments": []interface{}{"0|1|red"}}},
+		"color out of range": {"top": map[string]interface{}{"segments": []interface{}{"0|3|brand:9"}}},
+	} {
+		_, err := tool.parseConfig(arguments)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestConfigureLightingTool_ApplyAndVerify(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	arguments := map[string]interface{}{
+		"top":            map[string]interface{}{"segments": []interface{}{"0|5|red"}, "whirl": float64(100)},
+		"brightness":     float64(120),
+		"applyAndVerify": true,
+	}
+
+	tool := NewConfigureLightingTool(device.NewSimulatedClient(device.NewSimulator()), broadcaster, stateManager, nil, nil)
+	result, err := tool.Execute(context.Background(), arguments)
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	require.False(t, result.IsError, text)
+	assert.Contains(t, text, "Verified: the UFO reports the requested top, dim")
+
+	// Firmware that acknowledges the write but keeps showing red
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery == "" {
+			w.Write([]byte(`{"top":["ff0000"],"dim":120}`))
+			return
+		}
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	tool = NewConfigureLightingTool(device.NewClient(), broadcaster, stateManager, nil, nil)
+	result, err = tool.Execute(context.Background(), arguments)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "The UFO does not reflect the request: top ring shows")
+}
+
+func TestConfigureLightingTool_Gradient(t *testing.T) {
+	tool := NewConfigureLightingTool(nil, nil, nil, nil, nil)
+
+	config, err := tool.parseConfig(map[string]interface{}{
+		"both": map[string]interface{}{
+			"gradient":   map[string]interface{}{"from": "000000", "to": "ffffff", "length": float64(3)},
+			"background": "0000ff",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "top_init=1&top=0|1|000000|1|1|808080|2|1|ffffff&top_bg=0000ff"+
+		"&bottom_init=1&bottom=0|1|000000|1|1|808080|2|1|ffffff&bottom_bg=0000ff", config.query)
+
+	for name, arguments := range map[string]map[string]interface{}{
+		"with segments": {"top": map[string]interface{}{"gradient": map[string]interface{}{"from": "red", "to": "blue"}, "segments": []interface{}{"0|1|red"}}},
+		"with palette":  {"top": map[string]interface{}{"gradient": map[string]interface{}{"from": "red", "to": "blue"}, "palette": "brand"}},
+		"not an object": {"top": map[string]interface{}{"gradient": "red-blue"}},
+	} {
+		_, err := tool.parseConfig(arguments)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestConfigureLightingTool_RingBrightness(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	client := device.NewClient()
+	client.SetRingBrightness(stateManager.RingBrightness)
+	tool := NewConfigureLightingTool(client, broadcaster, stateManager, nil, nil)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"top":    map[string]interface{}{"segments": []interface{}{"0|15|ff0000"}},
+		"bottom": map[string]interface{}{"segments": []interface{}{"0|15|ff0000"}, "brightness": float64(40)},
+	})
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	require.False(t, result.IsError, text)
+	assert.Contains(t, text, "brightness 40%")
+	assert.Equal(t, "top_init=1&top=0|15|ff0000&bottom_init=1&bottom=0|15|660000", query)
+
+	// The brightness stays with the ring, and the shadow state keeps the
+	// colors as requested
+	snapshot := stateManager.Snapshot()
+	assert.Equal(t, 40, snapshot.BottomBrightness)
+	assert.Equal(t, 0, snapshot.TopBrightness)
+	_, err = client.SendRawQuery(context.Background(), "bottom_init=1&bottom_bg=ffffff")
+	require.NoError(t, err)
+	assert.Equal(t, "bottom_init=1&bottom_bg=666666", query)
+
+	// A ring brightness that cannot be sent is undone
+	server.Close()
+	client.SetRetryPolicy(device.RetryPolicy{MaxAttempts: 1})
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"bottom": map[string]interface{}{"segments": []interface{}{"0|15|ff0000"}, "brightness": float64(10)},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	_, bottom := stateManager.RingBrightness()
+	assert.Equal(t, 40, bottom)
+
+	_, err = tool.parseConfig(map[string]interface{}{"top": map[string]interface{}{"brightness": float64(0)}})
+	assert.Error(t, err)
+}
+
+func TestConfigureLightingTool_Stack(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	tool := NewConfigureLightingTool(device.NewClient(), broadcaster, stateManager, nil, nil)
+	red := map[string]interface{}{"segments": []interface{}{"0|15|ff0000"}}
+	blue := map[string]interface{}{"segments": []interface{}{"0|15|0000ff"}}
+
+	// Without stack, a configuration stays off the stack
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"top": red})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	assert.Equal(t, 0, stateManager.GetEffectStackDepth())
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"top": red, "bottom": red, "stack": true})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Stacked as __config__")
+	top := stateManager.GetCurrentEffect()
+	require.NotNil(t, top)
+	assert.Equal(t, configEffectName, top.Name)
+	assert.True(t, top.Synthetic())
+
+	// Consecutive configurations update the entry on top
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"top": blue, "brightness": float64(100)})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	stack := stateManager.GetEffectStack()
+	require.Len(t, stack, 1)
+	assert.Equal(t, top.InstanceID(), stack[0].InstanceID())
+	assert.Equal(t, "bottom_init=1&bottom=0|15|ff0000&dim=100&top_init=1&top=0|15|0000ff", stack[0].Pattern)
+
+	// stack: true layers a new entry
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"top": red, "stack": true})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	stack = stateManager.GetEffectStack()
+	require.Len(t, stack, 2)
+	assert.Equal(t, "bottom_init=1&bottom=0|15|ff0000&dim=100&top_init=1&top=0|15|ff0000", stack[1].Pattern)
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"top": red, "stack": "yes"})
+	require.NoError(t, err)
+	toolErr, failed := ErrorOf(result)
+	require.True(t, failed)
+	assert.Equal(t, "stack", toolErr.Parameter)
+}
+
+func TestConfigureLightingTool_WhirlState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	tool := NewConfigureLightingTool(device.NewClient(), broadcaster, stateManager, nil, nil)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"top":    map[string]interface{}{"segments": []interface{}{"0|5|ff0000"}, "whirl": float64(200), "counterClockwise": true},
+		"bottom": map[string]interface{}{"segments": []interface{}{"0|5|00ff00"}, "whirl": float64(300), "morph": map[string]interface{}{"brightnessMs": float64(1000), "fadeMs": float64(500)}},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	snapshot := stateManager.Snapshot()
+	assert.Equal(t, 200, snapshot.TopWhirlMs)
+	assert.True(t, snapshot.TopWhirlCounterClockwise)
+	assert.Equal(t, 300, snapshot.BottomWhirlMs)
+	assert.False(t, snapshot.BottomWhirlCounterClockwise)
+	require.NotNil(t, snapshot.BottomMorph)
+
+	// Configuring a ring without a whirl stops it,
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"top": map[string]interface{}{"background": "0000ff"}})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	snapshot = stateManager.Snapshot()
+	assert.Equal(t, 0, snapshot.TopWhirlMs)
+	assert.False(t, snapshot.TopWhirlCounterClockwise)
+	assert.Equal(t, 300, snapshot.BottomWhirlMs)
+
+	// and so does a ring without a morph
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"bottom": map[string]interface{}{"background": "0000ff"}})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	snapshot = stateManager.Snapshot()
+	assert.Nil(t, snapshot.BottomMorph)
+	assert.Equal(t, 0, snapshot.BottomWhirlMs)
+}
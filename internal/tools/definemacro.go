@@ -0,0 +1,245 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/macros"
+)
+
+// ToolCaller calls the server's other MCP tools by name, as a client would
+type ToolCaller interface {
+	HasTool(name string) bool
+	CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error)
+}
+
+// macroTools cannot be macro steps, so a macro never runs another macro
+var macroTools = map[string]bool{
+	"defineMacro": true,
+	"runMacro":    true,
+	"listMacros":  true,
+	"deleteMacro": true,
+}
+
+// DefineMacroTool implements the defineMacro MCP tool
+type DefineMacroTool struct {
+	store  *macros.Store
+	caller ToolCaller
+}
+
+// NewDefineMacroTool creates a new defineMacro tool instance
+func NewDefineMacroTool(store *macros.Store, caller ToolCaller) *DefineMacroTool {
+	return &DefineMacroTool{
+		store:  store,
+		caller: caller,
+	}
+}
+
+// Definition returns the MCP tool definition for defineMacro
+func (t *DefineMacroTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "defineMacro",
+		Description: "Save a named, ordered list of tool calls, such as the calls that set up a demo, saved across restarts. runMacro then makes all of them in one call on the server. Declared params can be used in step arguments as {name} and are filled in by runMacro. Defining an existing name replaces that macro.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Macro name",
+					"examples":    []string{"demo setup", "end of day"},
+				},
+				"steps": map[string]interface{}{
+					"type":        "array",
+					"description": fmt.Sprintf("Tool calls to make in order (1-%d). Macro tools cannot be steps", macros.MaxSteps),
+					"minItems":    1,
+					"maxItems":    macros.MaxSteps,
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"tool": map[string]interface{}{
+								"type":        "string",
+								"description": "Name of the tool to call",
+								"examples":    []string{"configureLighting", "playEffect"},
+							},
+							"arguments": map[string]interface{}{
+								"type":        "object",
+								"description": "Arguments for the tool, as it would be called directly. Strings may contain {param} placeholders; an argument that is only a placeholder takes the parameter's value with its type",
+							},
+							"onError": map[string]interface{}{
+								"type":        "string",
+								"description": "What to do when this step fails: 'abort' stops the macro, 'continue' reports the failure and runs the next step (default 'abort')",
+								"enum":        []string{macros.OnErrorAbort, macros.OnErrorContinue},
+							},
+						},
+						"required": []string{"tool"},
+					},
+				},
+				"params": map[string]interface{}{
+					"type":        "array",
+					"description": "Parameters the steps use as {name} placeholders (optional)",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"name": map[string]interface{}{
+								"type":        "string",
+								"description": "Parameter name: a letter followed by letters, digits or '_'",
+								"examples":    []string{"env", "color"},
+							},
+							"description": map[string]interface{}{
+								"type":        "string",
+								"description": "What the parameter is for (optional)",
+							},
+							"default": map[string]interface{}{
+								"description": "Value used when runMacro gives none; without a default the parameter is required",
+							},
+						},
+						"required": []string{"name"},
+					},
+				},
+				"description": map[string]interface{}{
+					"type":        "string",
+					"description": "What the macro is for (optional)",
+				},
+			},
+			Required: []string{"name", "steps"},
+		},
+	}
+}
+
+// Execute runs the defineMacro tool
+func (t *DefineMacroTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, ok := arguments["name"].(string)
+	if !ok {
+		return invalidArgument("name", "'name' must be a string"), nil
+	}
+	stepList, ok := arguments["steps"].([]interface{})
+	if !ok {
+		return invalidArgument("steps", "'steps' must be an array of tool calls"), nil
+	}
+	steps := make([]macros.Step, len(stepList))
+	for i, value := range stepList {
+		step, err := parseMacroStep(value)
+		if err != nil {
+			return invalidArgument("steps", fmt.Sprintf("step %d: %v", i+1, err)), nil
+		}
+		if !t.caller.HasTool(step.Tool) {
+			return invalidArgument("steps", fmt.Sprintf("step %d: unknown tool '%s'", i+1, step.Tool)), nil
+		}
+		steps[i] = step
+	}
+	var params []macros.Param
+	if value, exists := arguments["params"]; exists {
+		paramList, ok := value.([]interface{})
+		if !ok {
+			return invalidArgument("params", "'params' must be an array of parameters"), nil
+		}
+		for i, value := range paramList {
+			param, err := parseMacroParam(value)
+			if err != nil {
+				return invalidArgument("params", fmt.Sprintf("parameter %d: %v", i+1, err)), nil
+			}
+			params = append(params, param)
+		}
+	}
+	description := ""
+	if value, exists := arguments["description"]; exists {
+		text, ok := value.(string)
+		if !ok {
+			return invalidArgument("description", "'description' must be a string"), nil
+		}
+		description = text
+	}
+
+	macro, replaced, err := t.store.Save(macros.Macro{Name: name, Params: params, Steps: steps, Description: description})
+	if err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+
+	verb := "Saved"
+	if replaced {
+		verb = "Replaced"
+	}
+	lines := make([]string, len(macro.Steps))
+	for i, step := range macro.Steps {
+		lines[i] = fmt.Sprintf("%d. %s", i+1, step.Tool)
+		if step.OnError == macros.OnErrorContinue {
+			lines[i] += " (continues on error)"
+		}
+	}
+	message := fmt.Sprintf("%s macro '%s' with %d step(s)\n%s", verb, macro.Name, len(macro.Steps), strings.Join(lines, "\n"))
+	if len(macro.Params) > 0 {
+		names := make([]string, len(macro.Params))
+		for i, param := range macro.Params {
+			names[i] = param.Name
+			if param.Default == nil {
+				names[i] += " (required)"
+			}
+		}
+		message += "\nParameters: " + strings.Join(names, ", ")
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// parseMacroStep reads one entry of a defineMacro steps array
+func parseMacroStep(value interface{}) (macros.Step, error) {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return macros.Step{}, fmt.Errorf("must be an object with a tool")
+	}
+	tool, ok := fields["tool"].(string)
+	if !ok || strings.TrimSpace(tool) == "" {
+		return macros.Step{}, fmt.Errorf("'tool' must be a non-empty string")
+	}
+	tool = strings.TrimSpace(tool)
+	if macroTools[tool] {
+		return macros.Step{}, fmt.Errorf("'%s' cannot be used in a macro", tool)
+	}
+	step := macros.Step{Tool: tool}
+	if value, exists := fields["arguments"]; exists {
+		arguments, ok := value.(map[string]interface{})
+		if !ok {
+			return macros.Step{}, fmt.Errorf("'arguments' must be an object")
+		}
+		step.Arguments = arguments
+	}
+	if value, exists := fields["onError"]; exists {
+		policy, ok := value.(string)
+		if !ok {
+			return macros.Step{}, fmt.Errorf("'onError' must be a string")
+		}
+		step.OnError = policy
+	}
+	return step, nil
+}
+
+// parseMacroParam reads one entry of a defineMacro params array
+func parseMacroParam(value interface{}) (macros.Param, error) {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return macros.Param{}, fmt.Errorf("must be an object with a name")
+	}
+	name, ok := fields["name"].(string)
+	if !ok {
+		return macros.Param{}, fmt.Errorf("'name' must be a string")
+	}
+	param := macros.Param{Name: name, Default: fields["default"]}
+	if value, exists := fields["description"]; exists {
+		text, ok := value.(string)
+		if !ok {
+			return macros.Param{}, fmt.Errorf("'description' must be a string")
+		}
+		param.Description = text
+	}
+	return param, nil
+}
@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/macros"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeToolCaller records tool calls and fails the tools listed in failing
+type fakeToolCaller struct {
+	calls   []string
+	failing map[string]bool
+}
+
+func (c *fakeToolCaller) HasTool(name string) bool {
+	return name != "missingTool"
+}
+
+func (c *fakeToolCaller) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	c.calls = append(c.calls, fmt.Sprintf("%s %v", name, arguments))
+	if c.failing[name] {
+		return mcp.NewToolResultError(name + " refused"), nil
+	}
+	return mcp.NewToolResultText(name + " done\nmore detail"), nil
+}
+
+func TestMacroTools(t *testing.T) {
+	store := macros.NewStore(filepath.Join(t.TempDir(), "macros.json"))
+	caller := &fakeToolCaller{failing: map[string]bool{}}
+	define := NewDefineMacroTool(store, caller)
+	run := NewRunMacroTool(store, caller)
+	list := NewListMacrosTool(store)
+	remove := NewDeleteMacroTool(store)
+
+	result, err := define.Execute(context.Background(), map[string]interface{}{
+		"name": "demo setup",
+		"steps": []interface{}{
+			map[string]interface{}{"tool": "setBrightness", "arguments": map[string]interface{}{"level": 200.0}},
+			map[string]interface{}{"tool": "playEffect", "arguments": map[string]interface{}{"name": "rainbow"}},
+			map[string]interface{}{"tool": "stopAllEffects"},
+		},
+	})
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	require.False(t, result.IsError, text)
+	assert.Contains(t, text, "Saved macro 'demo setup' with 3 step(s)")
+
+	result, err = run.Execute(context.Background(), map[string]interface{}{"name": "Demo Setup"})
+	require.NoError(t, err)
+	text = result.Content[0].(mcp.TextContent).Text
+	require.False(t, result.IsError, text)
+	assert.Equal(t, []string{"setBrightness map[level:200]", "playEffect map[name:rainbow]", "stopAllEffects map[]"}, caller.calls)
+	assert.Contains(t, text, "2. playEffect: ok - playEffect done")
+	assert.NotContains(t, text, "more detail")
+
+	// The first failing step stops the macro
+	caller.calls = nil
+	caller.failing["playEffect"] = true
+	result, err = run.Execute(context.Background(), map[string]interface{}{"name": "demo setup"})
+	require.NoError(t, err)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.True(t, result.IsError)
+	assert.Len(t, caller.calls, 2)
+	assert.Contains(t, text, "2. playEffect: failed - playEffect refused")
+	assert.Contains(t, text, "1 remaining step(s) not run")
+
+	result, err = list.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	var listed []macros.Macro
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &listed))
+	require.Len(t, listed, 1)
+	assert.Equal(t, "stopAllEffects", listed[0].Steps[2].Tool)
+
+	for name, arguments := range map[string]map[string]interface{}{
+		"unknown tool": {"name": "bad", "steps": []interface{}{map[string]interface{}{"tool": "missingTool"}}},
+		"nested macro": {"name": "nested", "steps": []interface{}{map[string]interface{}{"tool": "runMacro"}}},
+		"no steps":     {"name": "none", "steps": []interface{}{}},
+		"bad step":     {"name": "str", "steps": []interface{}{"playEffect"}},
+		"bad args":     {"name": "args", "steps": []interface{}{map[string]interface{}{"tool": "playEffect", "arguments": "rainbow"}}},
+	} {
+		result, err := define.Execute(context.Background(), arguments)
+		require.NoError(t, err)
+		assert.True(t, result.IsError, name)
+	}
+
+	result, err = remove.Execute(context.Background(), map[string]interface{}{"name": "demo setup"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	result, err = run.Execute(context.Background(), map[string]interface{}{"name": "demo setup"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestMacroTools_Params(t *testing.T) {
+	store := macros.NewStore(filepath.Join(t.TempDir(), "macros.json"))
+	caller := &fakeToolCaller{failing: map[string]bool{"raiseAlert": true}}
+	define := NewDefineMacroTool(store, caller)
+	run := NewRunMacroTool(store, caller)
+
+	result, err := define.Execute(context.Background(), map[string]interface{}{
+		"name": "deployStatus",
+		"params": []interface{}{
+			map[string]interface{}{"name": "env"},
+			map[string]interface{}{"name": "color", "default": "green"},
+		},
+		"steps": []interface{}{
+			map[string]interface{}{"tool": "raiseAlert", "arguments": map[string]interface{}{"message": "{env} deploy"}, "onError": "continue"},
+			map[string]interface{}{"tool": "playEffect", "arguments": map[string]interface{}{"name": "pulse", "params": map[string]interface{}{"color": "{color}"}}},
+		},
+	})
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	require.False(t, result.IsError, text)
+	assert.Contains(t, text, "1. raiseAlert (continues on error)")
+	assert.Contains(t, text, "Parameters: env (required), color")
+
+	// A failing step that continues does not stop the macro
+	result, err = run.Execute(context.Background(), map[string]interface{}{
+		"name":   "deployStatus",
+		"params": map[string]interface{}{"env": "prod", "color": "orange"},
+	})
+	require.NoError(t, err)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.False(t, result.IsError, text)
+	assert.Equal(t, []string{"raiseAlert map[message:prod deploy]", "playEffect map[name:pulse params:map[color:orange]]"}, caller.calls)
+	assert.Contains(t, text, "Finished with 1 failed step(s)")
+
+	result, err = run.Execute(context.Background(), map[string]interface{}{"name": "deployStatus"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "needs a value for env")
+
+	result, err = define.Execute(context.Background(), map[string]interface{}{
+		"name":  "undeclared",
+		"steps": []interface{}{map[string]interface{}{"tool": "playEffect", "arguments": map[string]interface{}{"name": "{effect}"}}},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
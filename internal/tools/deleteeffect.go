@@ -8,6 +8,10 @@ import (
 	"github.com/starspace46/ufo-mcp-go/internal/effects"
 )
 
+// seedEffects are the built-in effects that deleteEffect and importEffects
+// do not remove
+var seedEffects = []string{"rainbow", "policeLights", "breathingGreen", "pipelineDemo", "ipDisplay"}
+
 // DeleteEffectTool implements the deleteEffect MCP tool
 type DeleteEffectTool struct {
 	store *effects.Store
@@ -24,7 +28,7 @@ func NewDeleteEffectTool(store *effects.Store) *DeleteEffectTool {
 func (t *DeleteEffectTool) Definition() mcp.Tool {
 	return mcp.Tool{
 		Name:        "deleteEffect",
-		Description: "Delete a custom lighting effect. Seed effects (built-in effects) cannot be deleted. This operation is permanent.",
+		Description: "Delete a custom lighting effect. Seed effects (built-in effects) cannot be deleted. The deleted version is kept, so rollbackEffect can restore it.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -43,58 +47,25 @@ func (t *DeleteEffectTool) Execute(ctx context.Context, arguments map[string]int
 	// Extract and validate name
 	name, ok := arguments["name"].(string)
 	if !ok || name == "" {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'name' parameter is required and must be a non-empty string",
-				},
-			},
-			IsError: true,
-		}, nil
-	}
-
-	// Check if effect exists
-	effect, exists := t.store.Get(name)
-	if !exists {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error: Effect '%s' not found", name),
-				},
-			},
-			IsError: true,
-		}, nil
+		return missingArgument("name", "'name' parameter is required and must be a non-empty string"), nil
 	}
 
 	// Check if it's a seed effect (seed effects have specific known names)
-	seedEffects := []string{"rainbow", "policeLights", "breathingGreen", "pipelineDemo", "ipDisplay"}
 	for _, seedName := range seedEffects {
 		if name == seedName {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: fmt.Sprintf("Error: Cannot delete seed effect '%s'. Only custom effects can be deleted.", name),
-					},
-				},
-				IsError: true,
-			}, nil
+			return toolError(CodeConflict, fmt.Sprintf("Cannot delete seed effect '%s'. Only custom effects can be deleted.", name)), nil
 		}
 	}
 
+	// Check if effect exists
+	effect, exists := t.store.Get(name)
+	if !exists {
+		return toolError(CodeNotFound, fmt.Sprintf("Effect '%s' not found", name)), nil
+	}
+
 	// Delete the effect
 	if err := t.store.Delete(name); err != nil {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error: Failed to delete effect: %v", err),
-				},
-			},
-			IsError: true,
-		}, nil
+		return toolError(CodeConflict, fmt.Sprintf("Failed to delete effect: %v", err)), nil
 	}
 
 	// Build success message
@@ -102,11 +73,15 @@ func (t *DeleteEffectTool) Execute(ctx context.Context, arguments map[string]int
 	message += "Effect details that were removed:\n"
 	message += fmt.Sprintf("• Description: %s\n", effect.Description)
 	message += fmt.Sprintf("• Pattern: %s\n", effect.Pattern)
-	message += fmt.Sprintf("• Duration: %d seconds", effect.Duration)
+	message += fmt.Sprintf("• Duration: %dms", effect.Duration)
 	if effect.Duration == 0 {
 		message += " (infinite)"
 	}
-	message += "\n\nThis operation is permanent and cannot be undone."
+	if revisions, _ := t.store.Revisions(name); len(revisions) > 0 {
+		message += fmt.Sprintf("\n\nThe deleted version is kept: restore it with rollbackEffect (version %d).", revisions[len(revisions)-1].Version)
+	} else {
+		message += "\n\nThis operation is permanent and cannot be undone."
+	}
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
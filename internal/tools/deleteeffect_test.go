@@ -91,8 +91,8 @@ func TestDeleteEffectTool_Execute_Success(t *testing.T) {
 			if !strings.Contains(textContent.Text, effectName) {
 				t.Errorf("Expected effect name '%s' in response", effectName)
 			}
-			if !strings.Contains(textContent.Text, "permanent") {
-				t.Error("Expected warning about permanent deletion")
+			if !strings.Contains(textContent.Text, "restore it with rollbackEffect (version 1)") {
+				t.Error("Expected the deleted version to be kept for rollbackEffect")
 			}
 
 			// Verify effect was actually deleted from store
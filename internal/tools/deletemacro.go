@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/macros"
+)
+
+// DeleteMacroTool implements the deleteMacro MCP tool
+type DeleteMacroTool struct {
+	store *macros.Store
+}
+
+// NewDeleteMacroTool creates a new deleteMacro tool instance
+func NewDeleteMacroTool(store *macros.Store) *DeleteMacroTool {
+	return &DeleteMacroTool{
+		store: store,
+	}
+}
+
+// Definition returns the MCP tool definition for deleteMacro
+func (t *DeleteMacroTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "deleteMacro",
+		Description: "Delete a macro saved with defineMacro.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the macro to delete",
+				},
+			},
+			Required: []string{"name"},
+		},
+	}
+}
+
+// Execute runs the deleteMacro tool
+func (t *DeleteMacroTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, ok := arguments["name"].(string)
+	if !ok || name == "" {
+		return invalidArgument("name", "'name' must be a non-empty string"), nil
+	}
+	if err := t.store.Delete(name); err != nil {
+		return toolError(CodeNotFound, err.Error()), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("Deleted macro '%s'", name),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/palettes"
+)
+
+// DeletePaletteTool implements the deletePalette MCP tool
+type DeletePaletteTool struct {
+	store *palettes.Store
+}
+
+// NewDeletePaletteTool creates a new deletePalette tool instance
+func NewDeletePaletteTool(store *palettes.Store) *DeletePaletteTool {
+	return &DeletePaletteTool{
+		store: store,
+	}
+}
+
+// Definition returns the MCP tool definition for deletePalette
+func (t *DeletePaletteTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "deletePalette",
+		Description: "Delete a saved color palette. Lighting already sent to the UFO is not changed.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the palette to delete",
+				},
+			},
+			Required: []string{"name"},
+		},
+	}
+}
+
+// Execute runs the deletePalette tool
+func (t *DeletePaletteTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, ok := arguments["name"].(string)
+	if !ok || name == "" {
+		return invalidArgument("name", "'name' must be a non-empty string"), nil
+	}
+	if err := t.store.Delete(name); err != nil {
+		return toolError(CodeNotFound, err.Error()), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("Deleted palette '%s'", name),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/scenes"
+)
+
+// DeleteSceneTool implements the deleteScene MCP tool
+type DeleteSceneTool struct {
+	store *scenes.Store
+}
+
+// NewDeleteSceneTool creates a new deleteScene tool instance
+func NewDeleteSceneTool(store *scenes.Store) *DeleteSceneTool {
+	return &DeleteSceneTool{
+		store: store,
+	}
+}
+
+// Definition returns the MCP tool definition for deleteScene
+func (t *DeleteSceneTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "deleteScene",
+		Description: "Delete a saved scene. What the UFO shows is not changed.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the scene to delete",
+				},
+			},
+			Required: []string{"name"},
+		},
+	}
+}
+
+// Execute runs the deleteScene tool
+func (t *DeleteSceneTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, ok := arguments["name"].(string)
+	if !ok || name == "" {
+		return invalidArgument("name", "'name' must be a non-empty string"), nil
+	}
+	if err := t.store.Delete(name); err != nil {
+		return toolError(CodeNotFound, err.Error()), nil
+	}
+	return sceneResult(fmt.Sprintf("Deleted scene '%s'", name)), nil
+}
@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+)
+
+// DiffStatesTool implements the diffStates MCP tool
+type DiffStatesTool struct {
+	stateManager *state.Manager
+	history      *state.History
+}
+
+// stateRef is a resolved state to compare
+type stateRef struct {
+	Ref  string     `json:"ref"`
+	Time *time.Time `json:"time,omitempty"` // when the state was recorded; omitted for current
+}
+
+// stateDiffResult is the structured part of the diffStates result
+type stateDiffResult struct {
+	From stateRef        `json:"from"`
+	To   stateRef        `json:"to"`
+	Diff state.StateDiff `json:"diff"`
+}
+
+// NewDiffStatesTool creates a new diffStates tool instance
+func NewDiffStatesTool(stateManager *state.Manager, history *state.History) *DiffStatesTool {
+	return &DiffStatesTool{
+		stateManager: stateManager,
+		history:      history,
+	}
+}
+
+// Definition returns the MCP tool definition for diffStates
+func (t *DiffStatesTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "diffStates",
+		Description: "Compare two UFO states and report what changed: LED colors, brightness, logo, running effect and animations. States are 'current', 'persisted' (the last recorded state, which survives restarts), 'previous' (the one before it), a time such as '08:00' or an ISO-8601 timestamp (the state at that time), or a duration such as '2h' (the state that long ago). Returns a summary followed by JSON.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "Earlier state (optional, default 'persisted')",
+					"examples":    []string{"persisted", "08:00", "2h", "2024-03-01T09:00:00+01:00"},
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Later state (optional, default 'current')",
+				},
+			},
+		},
+	}
+}
+
+// Execute runs the diffStates tool
+func (t *DiffStatesTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	refs := map[string]string{"from": "persisted", "to": "current"}
+	for key := range refs {
+		if value, exists := arguments[key]; exists {
+			ref, ok := value.(string)
+			if !ok || strings.TrimSpace(ref) == "" {
+				return invalidArgument(key, fmt.Sprintf("'%s' must be a non-empty string", key)), nil
+			}
+			refs[key] = strings.TrimSpace(ref)
+		}
+	}
+
+	fromRef, fromState, err := t.resolve(refs["from"], time.Now())
+	if err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+	toRef, toState, err := t.resolve(refs["to"], time.Now())
+	if err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+
+	result := stateDiffResult{From: fromRef, To: toRef, Diff: state.Diff(fromState, toState)}
+	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize state diff: "+err.Error()), nil
+	}
+
+	message := fmt.Sprintf("Comparing %s → %s\n\n", describeRef(fromRef), describeRef(toRef))
+	message += result.Diff.Summary()
+	message += "\n\nFull JSON:\n" + string(resultJSON)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// resolve looks up the state a reference names
+func (t *DiffStatesTool) resolve(ref string, now time.Time) (stateRef, state.LedState, error) {
+	switch strings.ToLower(ref) {
+	case "current":
+		return stateRef{Ref: "current"}, *t.stateManager.Snapshot(), nil
+	case "persisted", "latest":
+		entry, ok := t.history.Latest()
+		if !ok {
+			return stateRef{}, state.LedState{}, fmt.Errorf("no state has been recorded yet")
+		}
+		return entryRef("persisted", entry), entry.State, nil
+	case "previous":
+		entries := t.history.Entries()
+		if len(entries) < 2 {
+			return stateRef{}, state.LedState{}, fmt.Errorf("fewer than two states have been recorded")
+		}
+		return entryRef("previous", entries[len(entries)-2]), entries[len(entries)-2].State, nil
+	}
+
+	at, err := parseStateTime(ref, now)
+	if err != nil {
+		return stateRef{}, state.LedState{}, err
+	}
+	entry, ok := t.history.At(at)
+	if !ok {
+		return stateRef{}, state.LedState{}, fmt.Errorf("no state was recorded at or before %s", timezone.ISO(at))
+	}
+	return entryRef(ref, entry), entry.State, nil
+}
+
+// entryRef describes a history entry
+func entryRef(ref string, entry state.HistoryEntry) stateRef {
+	recorded := timezone.In(entry.Time)
+	return stateRef{Ref: ref, Time: &recorded}
+}
+
+// describeRef labels a state reference for the summary
+func describeRef(ref stateRef) string {
+	if ref.Time == nil {
+		return ref.Ref
+	}
+	return fmt.Sprintf("%s (recorded %s)", ref.Ref, timezone.ISO(*ref.Time))
+}
+
+// parseStateTime parses an ISO-8601 timestamp, a clock time today in the
+// configured time zone, or a duration before now
+func parseStateTime(ref string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, ref); err == nil {
+		return t, nil
+	}
+	loc := timezone.Location()
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, ref, loc); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if clock, err := time.ParseInLocation(layout, ref, loc); err == nil {
+			today := now.In(loc)
+			return time.Date(today.Year(), today.Month(), today.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, loc), nil
+		}
+	}
+	if ago, err := time.ParseDuration(strings.TrimSpace(strings.TrimSuffix(ref, "ago"))); err == nil && ago >= 0 {
+		return now.Add(-ago), nil
+	}
+	return time.Time{}, fmt.Errorf("unknown state '%s': use current, persisted, previous, a time like 08:00, an ISO-8601 timestamp or a duration like 2h", ref)
+}
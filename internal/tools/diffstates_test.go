@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffStatesTool_Execute(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	history := state.NewHistory(filepath.Join(t.TempDir(), "state-history.json"), 0)
+	tool := NewDiffStatesTool(stateManager, history)
+
+	// Nothing has been recorded yet
+	result, err := tool.Execute(context.Background(), nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "no state has been recorded")
+
+	_, err = history.Record(*stateManager.Snapshot(), time.Now().Add(-3*time.Hour))
+	require.NoError(t, err)
+	stateManager.UpdateLogo(true)
+	stateManager.UpdateEffect("rainbow")
+
+	// By default the last recorded state is compared with the current one
+	result, err = tool.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "Comparing persisted (recorded ")
+	assert.Contains(t, text, "Logo: off → on")
+	assert.Contains(t, text, "Effect: none → rainbow")
+	assert.Contains(t, text, `"changed": true`)
+
+	// A duration refers to the state that long ago
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"from": "2h ago", "to": "persisted"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "No changes")
+
+	// Before the history starts there is nothing to compare
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"from": "5h"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"from": "yesterday-ish"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "unknown state")
+}
@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+)
+
+// DisableIntegrationTool implements the disableIntegration MCP tool
+type DisableIntegrationTool struct {
+	registry *integrations.Registry
+}
+
+// NewDisableIntegrationTool creates a new disableIntegration tool instance
+func NewDisableIntegrationTool(registry *integrations.Registry) *DisableIntegrationTool {
+	return &DisableIntegrationTool{
+		registry: registry,
+	}
+}
+
+// Definition returns the MCP tool definition for disableIntegration
+func (t *DisableIntegrationTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "disableIntegration",
+		Description: "Pause an integration at runtime without editing config or restarting. It ignores webhook deliveries and skips polls until enabled again, and its alerts are cleared from the UFO.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": fmt.Sprintf("Integration to disable: %s", strings.Join(t.registry.Names(), ", ")),
+				},
+			},
+			Required: []string{"name"},
+		},
+	}
+}
+
+// Execute runs the disableIntegration tool
+func (t *DisableIntegrationTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, ok := arguments["name"].(string)
+	if !ok || name == "" {
+		return missingArgument("name", "'name' parameter is required and must be a non-empty string"), nil
+	}
+
+	if err := t.registry.SetEnabled(ctx, name, false); err != nil {
+		return ToolError{Code: CodeNotFound, Message: err.Error(), Parameter: "name", Hint: "Configured integrations: " + strings.Join(t.registry.Names(), ", ")}.Result(), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("⏸️ Integration '%s' disabled", name),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/devices"
+	"github.com/starspace46/ufo-mcp-go/internal/discovery"
+)
+
+// DiscoverUfosTool implements the discoverUfos MCP tool
+type DiscoverUfosTool struct {
+	scanner  *discovery.Scanner
+	registry *devices.Registry
+}
+
+// discoveryResult is the JSON document returned by discoverUfos
+type discoveryResult struct {
+	Configured string             `json:"configured"` // the UFO address the server is using
+	Devices    []discoveredDevice `json:"devices"`
+}
+
+// discoveredDevice is a discovered UFO with the metadata registered for it
+type discoveredDevice struct {
+	discovery.Device
+	Nickname string `json:"nickname,omitempty"`
+	Location string `json:"location,omitempty"`
+}
+
+// NewDiscoverUfosTool creates a new discoverUfos tool instance
+func NewDiscoverUfosTool(scanner *discovery.Scanner, registry *devices.Registry) *DiscoverUfosTool {
+	return &DiscoverUfosTool{
+		scanner:  scanner,
+		registry: registry,
+	}
+}
+
+// Definition returns the MCP tool definition for discoverUfos
+func (t *DiscoverUfosTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "discoverUfos",
+		Description: "Find Dynatrace UFO devices on the local network using mDNS, SSDP and a probe of every host's /api endpoint. Returns JSON with each UFO's IP, how it was found, its firmware information and any nickname set with setDeviceInfo, plus the address the server is currently configured to use.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"methods": map[string]interface{}{
+					"type":        "array",
+					"description": "Discovery methods to use (optional, default all)",
+					"items": map[string]interface{}{
+						"type": "string",
+						"enum": discovery.Methods,
+					},
+				},
+				"subnet": map[string]interface{}{
+					"type":        "string",
+					"description": "IPv4 subnet to probe in CIDR notation, /22 or smaller (optional, default the server's own subnets)",
+					"examples":    []string{"192.168.1.0/24"},
+				},
+				"timeoutMs": map[string]interface{}{
+					"type":        "integer",
+					"description": "How long to wait for mDNS and SSDP answers in milliseconds (optional, default 2000)",
+					"minimum":     100,
+					"maximum":     30000,
+				},
+			},
+		},
+	}
+}
+
+// Execute runs the discoverUfos tool
+func (t *DiscoverUfosTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	scanner := *t.scanner
+
+	var methods []string
+	if value, exists := arguments["methods"]; exists {
+		list, ok := value.([]interface{})
+		if !ok {
+			return invalidArgument("methods", "'methods' must be an array of strings"), nil
+		}
+		for _, item := range list {
+			method, ok := item.(string)
+			if !ok {
+				return invalidArgument("methods", "'methods' must be an array of strings"), nil
+			}
+			methods = append(methods, method)
+		}
+	}
+
+	if value, exists := arguments["subnet"]; exists {
+		cidr, ok := value.(string)
+		if !ok {
+			return invalidArgument("subnet", "'subnet' must be a string"), nil
+		}
+		_, subnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return invalidArgument("subnet", fmt.Sprintf("invalid subnet: %v", err)), nil
+		}
+		if _, err := discovery.Hosts(subnet); err != nil {
+			return invalidArgument("subnet", err.Error()), nil
+		}
+		scanner.Subnets = []*net.IPNet{subnet}
+	}
+
+	if value, exists := arguments["timeoutMs"]; exists {
+		timeoutMs, ok := wholeNumber(value)
+		if !ok || timeoutMs < 100 || timeoutMs > 30000 {
+			return invalidArgument("timeoutMs", "'timeoutMs' must be between 100 and 30000"), nil
+		}
+		scanner.Timeout = time.Duration(timeoutMs) * time.Millisecond
+	}
+
+	found, err := scanner.Scan(ctx, methods)
+	if err != nil {
+		return toolError(CodeDeviceError, fmt.Sprintf("Discovery failed: %v", err)), nil
+	}
+
+	result := discoveryResult{
+		Configured: os.Getenv("UFO_IP"),
+		Devices:    []discoveredDevice{},
+	}
+	for _, ufo := range found {
+		entry := discoveredDevice{Device: ufo}
+		if known, ok := t.registry.Get(ufo.IP); ok {
+			entry.Nickname = known.Nickname
+			entry.Location = known.Location
+		}
+		result.Devices = append(result.Devices, entry)
+	}
+
+	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize discovered UFOs: "+err.Error()), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: string(resultJSON),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/devices"
+	"github.com/starspace46/ufo-mcp-go/internal/discovery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverUfosTool_Execute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api" {
+			w.Write([]byte(`{"top":["ff0000"],"bottom":["00ff00"]}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", "ufo")
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	scanner := discovery.NewScanner()
+	scanner.Port, _ = strconv.Atoi(port)
+	registry := devices.NewRegistry(filepath.Join(t.TempDir(), "devices.json"))
+	require.NoError(t, registry.Set(devices.Device{Address: "127.0.0.1", Nickname: "bench"}))
+	tool := NewDiscoverUfosTool(scanner, registry)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"methods": []interface{}{"subnet"},
+		"subnet":  "127.0.0.1/32",
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+
+	var found discoveryResult
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &found))
+	assert.Equal(t, "ufo", found.Configured)
+	require.Len(t, found.Devices, 1)
+	assert.Equal(t, "127.0.0.1", found.Devices[0].IP)
+	assert.Equal(t, "subnet", found.Devices[0].Method)
+	assert.Equal(t, "bench", found.Devices[0].Nickname)
+}
+
+func TestDiscoverUfosTool_ValidationErrors(t *testing.T) {
+	tool := NewDiscoverUfosTool(discovery.NewScanner(), devices.NewRegistry(filepath.Join(t.TempDir(), "devices.json")))
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		message   string
+	}{
+		{"bad method", map[string]interface{}{"methods": []interface{}{"bluetooth"}}, "unknown discovery method"},
+		{"bad subnet", map[string]interface{}{"subnet": "192.168.1.0"}, "invalid subnet"},
+		{"large subnet", map[string]interface{}{"subnet": "10.0.0.0/8"}, "too large to scan"},
+		{"bad timeout", map[string]interface{}{"timeoutMs": float64(50)}, "'timeoutMs' must be between"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Execute(context.Background(), tt.arguments)
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.message)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// DisplayNumberTool implements the displayNumber MCP tool, which shows a
+// whole number such as a build number on the rings
+type DisplayNumberTool struct {
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+	engine       *effects.Engine
+	restores     *Restores
+}
+
+// NewDisplayNumberTool creates a new displayNumber tool instance
+func NewDisplayNumberTool(broadcaster *events.Broadcaster, stateManager *state.Manager, engine *effects.Engine, restores *Restores) *DisplayNumberTool {
+	return &DisplayNumberTool{
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		engine:       engine,
+		restores:     restores,
+	}
+}
+
+// Definition returns the MCP tool definition for displayNumber
+func (t *DisplayNumberTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "displayNumber",
+		Description: "Show a whole number, such as a build number or a count, on the rings. 'digits' shows one digit at a time on the top ring as that many lit LEDs (0 as ten), with the bottom ring marking the position; 'binary' shows the whole number at once, lowest bit at LED 0 of the top ring and bits 15 and up on the bottom ring, 0 bits dimmed; 'morse' flashes the digits in Morse code. It runs as an effect on the stack for 'repeat' passes.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: displayProperties(map[string]interface{}{
+				"number": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number to show, 0 or more",
+					"minimum":     0,
+				},
+			}, effects.SchemeDigits),
+			Required: []string{"number"},
+		},
+	}
+}
+
+// Execute runs the displayNumber tool
+func (t *DisplayNumberTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	number, ok := wholeNumber(arguments["number"])
+	if !ok || number < 0 {
+		return invalidArgument("number", "'number' must be a whole number, 0 or more"), nil
+	}
+	opts, repeat, invalid := displayOptions(arguments, effects.SchemeDigits)
+	if invalid != nil {
+		return invalid, nil
+	}
+	steps, err := effects.EncodeNumber(uint64(number), opts)
+	if err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+	return showEncoded(ctx, t.engine, t.broadcaster, t.stateManager, t.restores, strconv.Itoa(number), opts, steps, repeat), nil
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplayNumberTool_Execute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	engine := effects.NewEngine(device.NewClient())
+	defer engine.Shutdown(context.Background())
+	tool := NewDisplayNumberTool(broadcaster, stateManager, engine, NewRestores(engine, broadcaster, stateManager))
+
+	t.Run("Definition", func(t *testing.T) {
+		def := tool.Definition()
+		assert.Equal(t, "displayNumber", def.Name)
+		assert.Equal(t, []string{"number"}, def.InputSchema.Required)
+	})
+
+	t.Run("Binary", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"number": float64(5),
+			"scheme": "binary",
+		})
+		require.NoError(t, err)
+		text := result.Content[0].(mcp.TextContent).Text
+		require.False(t, result.IsError, text)
+		assert.Contains(t, text, "Showing '5' in binary")
+
+		// One frame shows the whole number, so nothing is animated
+		current := stateManager.GetCurrentEffect()
+		require.NotNil(t, current)
+		assert.Equal(t, "display:5", current.Name)
+		assert.Empty(t, effects.StepsFromContext(current.Context))
+		assert.Contains(t, current.Pattern, "top=0|1|FFFFFF")
+		stateManager.PopEffect()
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			arguments map[string]interface{}
+			message   string
+		}{
+			{"Missing", map[string]interface{}{}, "'number'"},
+			{"Negative", map[string]interface{}{"number": float64(-3)}, "'number'"},
+			{"Fraction", map[string]interface{}{"number": 1.5}, "'number'"},
+			{"TooBigForBinary", map[string]interface{}{"number": float64(1 << 31), "scheme": "binary"}, "does not fit"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := tool.Execute(context.Background(), tt.arguments)
+				require.NoError(t, err)
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.message)
+			})
+		}
+	})
+}
@@ -0,0 +1,261 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/color"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+const (
+	// defaultDisplayColor is the color displayText and displayNumber light
+	// LEDs in
+	defaultDisplayColor = "FFFFFF"
+	// defaultDisplayRepeat is how many times the text is shown by default
+	defaultDisplayRepeat = 3
+	// maxDisplayRepeat is the most times the text can be shown
+	maxDisplayRepeat = 100
+)
+
+// DisplayTextTool implements the displayText MCP tool, which spells out
+// short codes on the rings
+type DisplayTextTool struct {
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+	engine       *effects.Engine
+	restores     *Restores
+}
+
+// NewDisplayTextTool creates a new displayText tool instance
+func NewDisplayTextTool(broadcaster *events.Broadcaster, stateManager *state.Manager, engine *effects.Engine, restores *Restores) *DisplayTextTool {
+	return &DisplayTextTool{
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		engine:       engine,
+		restores:     restores,
+	}
+}
+
+// Definition returns the MCP tool definition for displayText
+func (t *DisplayTextTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "displayText",
+		Description: "Spell out a short code, such as a room code, a version or an IP address, on the rings. 'morse' flashes both rings in Morse code; 'digits' shows one digit at a time on the top ring as that many lit LEDs (0 as ten) with separators as pauses; 'binary' shows each character's 7-bit code on the top ring, lowest bit at LED 0. The bottom ring marks which character is showing. It runs as an effect on the stack for 'repeat' passes. Use displayNumber for numbers.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: displayProperties(map[string]interface{}{
+				"text": map[string]interface{}{
+					"type":        "string",
+					"description": "Text to show, up to 40 characters. Morse takes letters, digits, spaces and .,?/-:; digits takes digits and the separators .-:/ and space",
+					"examples":    []string{"B12", "192.168.1.20", "SOS"},
+				},
+			}, effects.SchemeMorse),
+			Required: []string{"text"},
+		},
+	}
+}
+
+// Execute runs the displayText tool
+func (t *DisplayTextTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	text, _ := arguments["text"].(string)
+	if strings.TrimSpace(text) == "" {
+		return invalidArgument("text", "'text' must be a non-empty string"), nil
+	}
+	opts, repeat, invalid := displayOptions(arguments, effects.SchemeMorse)
+	if invalid != nil {
+		return invalid, nil
+	}
+	steps, err := effects.Encode(text, opts)
+	if err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+	return showEncoded(ctx, t.engine, t.broadcaster, t.stateManager, t.restores, strings.TrimSpace(text), opts, steps, repeat), nil
+}
+
+// displayProperties adds the arguments displayText and displayNumber share
+// to properties, with the scheme defaulting to def
+func displayProperties(properties map[string]interface{}, def string) map[string]interface{} {
+	properties["scheme"] = map[string]interface{}{
+		"type":        "string",
+		"description": fmt.Sprintf("How to encode: morse, digits or binary (optional, default %s)", def),
+		"enum":        effects.Schemes,
+		"default":     def,
+	}
+	properties["color"] = map[string]interface{}{
+		"type":        "string",
+		"description": fmt.Sprintf("Color of lit LEDs: hex, #RGB, rgb(r,g,b) or a CSS color name (optional, default %s)", defaultDisplayColor),
+	}
+	properties["unitMs"] = map[string]interface{}{
+		"type":        "integer",
+		"description": fmt.Sprintf("Length of a Morse dot (default %d), or how long digits and binary show each character (default %d), in milliseconds (optional)", effects.DefaultMorseUnitMs, effects.DefaultCharacterMs),
+		"minimum":     50,
+	}
+	properties["repeat"] = map[string]interface{}{
+		"type":        "integer",
+		"description": fmt.Sprintf("How many times to show it; 0 repeats until stopped (optional, default %d)", defaultDisplayRepeat),
+		"minimum":     0,
+		"maximum":     maxDisplayRepeat,
+	}
+	return properties
+}
+
+// displayOptions reads the scheme, color, unitMs and repeat arguments,
+// returning an error result if one is invalid
+func displayOptions(arguments map[string]interface{}, defaultScheme string) (effects.EncodeOptions, int, *mcp.CallToolResult) {
+	opts := effects.EncodeOptions{Scheme: defaultScheme, Color: defaultDisplayColor}
+	if value, exists := arguments["scheme"]; exists {
+		scheme, ok := value.(string)
+		if !ok {
+			return opts, 0, invalidArgument("scheme", fmt.Sprintf("'scheme' must be one of %s", strings.Join(effects.Schemes, ", ")))
+		}
+		opts.Scheme = scheme
+	}
+	if value, exists := arguments["color"]; exists {
+		spec, ok := value.(string)
+		if !ok {
+			return opts, 0, invalidArgument("color", "'color' must be a string")
+		}
+		hex, err := color.Parse(spec)
+		if err != nil {
+			return opts, 0, invalidArgument("color", fmt.Sprintf("invalid color: %v", err))
+		}
+		opts.Color = hex
+	}
+	if value, exists := arguments["unitMs"]; exists {
+		ms, ok := wholeNumber(value)
+		if !ok || ms <= 0 {
+			return opts, 0, invalidArgument("unitMs", "'unitMs' must be a positive whole number of milliseconds")
+		}
+		opts.UnitMs = ms
+	}
+	repeat := defaultDisplayRepeat
+	if value, exists := arguments["repeat"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < 0 || n > maxDisplayRepeat {
+			return opts, 0, invalidArgument("repeat", fmt.Sprintf("'repeat' must be a whole number from 0 to %d", maxDisplayRepeat))
+		}
+		repeat = n
+	}
+	return opts, repeat, nil
+}
+
+// showEncoded plays the steps showing text as an effect on the stack, for
+// repeat passes or, with 0, until stopped
+func showEncoded(ctx context.Context, engine *effects.Engine, broadcaster *events.Broadcaster, stateManager *state.Manager, restores *Restores, text string, opts effects.EncodeOptions, steps []effects.Step, repeat int) *mcp.CallToolResult {
+	passMs := 0
+	for _, step := range steps {
+		passMs += step.DurationMs
+	}
+	duration := passMs * repeat
+	name := "display:" + text
+	if full := stackFull(stateManager); full != nil {
+		return full
+	}
+
+	// A single frame needs no animation
+	pattern := steps[0].Pattern
+	if len(steps) == 1 {
+		steps = nil
+	}
+
+	// A raised alert holds the UFO; the display then waits beneath it
+	alert := activeAlert(stateManager)
+	var warning string
+	if alert == nil {
+		reply, err := engine.ApplyWithReply(ctx, name, pattern, steps)
+		if err != nil {
+			return toolError(CodeDeviceError, fmt.Sprintf("Failed to send effect to UFO: %v", err))
+		}
+		if warning, err = device.CheckReply(reply); err != nil {
+			engine.Stop()
+			if stateManager.GetCurrentEffect() != nil {
+				if restoreErr := restoreTop(ctx, engine, broadcaster, stateManager); restoreErr != nil {
+					slog.WarnContext(ctx, "Failed to restore effect after UFO error", "error", restoreErr)
+				}
+			}
+			return toolError(CodeDeviceError, fmt.Sprintf("'%s' was not shown: %v\n\nPattern sent: %s", text, err, pattern))
+		}
+	}
+
+	startTime := time.Now()
+	effectContext := map[string]interface{}{
+		"instanceId": state.NewInstanceID(),
+		"duration":   duration,
+		"perpetual":  duration == 0,
+		"startTime":  startTime,
+		"text":       text,
+		"scheme":     opts.Scheme,
+		"synthetic":  true,
+	}
+	if len(steps) > 0 {
+		effectContext["steps"] = steps
+	}
+	_, stale := stateManager.PushEffect(name, pattern, effectContext)
+	restoreStale(ctx, stale, engine, broadcaster, stateManager)
+
+	started := events.EffectStarted{
+		Effect:     name,
+		InstanceID: effectContext["instanceId"].(string),
+		Duration:   duration,
+		Pattern:    pattern,
+		Steps:      len(steps),
+		Text:       text,
+		Scheme:     opts.Scheme,
+		Warning:    warning,
+		StackDepth: stateManager.GetEffectStackDepth(),
+	}
+	if alert != nil {
+		started.BeneathAlert = alert.Name
+	}
+	broadcaster.PublishPayload(ctx, started)
+
+	message := fmt.Sprintf("🔤 Showing '%s' in %s\n\n", text, opts.Scheme)
+	if alert != nil {
+		message = fmt.Sprintf("⏳ '%s' queued beneath alert '%s' and will show when the alert is cleared or expires.\n\n", text, alert.Name)
+	}
+	message += fmt.Sprintf("• Instance: %s\n", effectContext["instanceId"])
+	message += fmt.Sprintf("• One pass: %s\n", format.Millis(int64(passMs)))
+	if duration > 0 {
+		message += fmt.Sprintf("• Duration: %d passes, %s\n", repeat, format.Millis(int64(duration)))
+	} else {
+		message += "• Duration: Perpetual (use stopEffect to stop)\n"
+	}
+	message += displayLegend(opts.Scheme)
+	if warning != "" {
+		message += fmt.Sprintf("\n⚠️ Warning: %s. Check that the UFO shows the effect.", warning)
+	}
+
+	if duration > 0 {
+		restores.scheduleCompletion(ctx, effectContext["instanceId"].(string))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}
+}
+
+// displayLegend explains how to read a scheme
+func displayLegend(scheme string) string {
+	switch scheme {
+	case effects.SchemeDigits:
+		return "\nRead it: count the lit LEDs on the top ring (ten means 0); a longer pause is a separator, and the bottom LED moves on with each character."
+	case effects.SchemeBinary:
+		return "\nRead it: bright LEDs are 1 bits and dim ones 0 bits, lowest bit at LED 0."
+	}
+	return "\nRead it: short flashes are dots, long ones dashes; a long pause separates words."
+}
@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplayTextTool_Execute(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	client := device.NewClient()
+	engine := effects.NewEngine(client)
+	defer engine.Shutdown(context.Background())
+	tool := NewDisplayTextTool(broadcaster, stateManager, engine, NewRestores(engine, broadcaster, stateManager))
+	stopTool := NewStopEffectTool(client, broadcaster, stateManager, engine)
+
+	t.Run("Definition", func(t *testing.T) {
+		def := tool.Definition()
+		assert.Equal(t, "displayText", def.Name)
+		assert.Equal(t, []string{"text"}, def.InputSchema.Required)
+		assert.Contains(t, def.InputSchema.Properties, "scheme")
+		assert.Contains(t, def.InputSchema.Properties, "repeat")
+	})
+
+	t.Run("ShowsUntilStopped", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"text":   "192.1",
+			"scheme": "digits",
+			"color":  "red",
+			"unitMs": float64(100),
+			"repeat": float64(0),
+		})
+		require.NoError(t, err)
+		text := result.Content[0].(mcp.TextContent).Text
+		require.False(t, result.IsError, text)
+		assert.Contains(t, text, "Showing '192.1' in digits")
+		assert.Contains(t, text, "Perpetual")
+
+		stack := stateManager.GetEffectStack()
+		require.Len(t, stack, 1)
+		assert.Equal(t, "display:192.1", stack[0].Name)
+		assert.Equal(t, "digits", stack[0].Context["scheme"])
+		assert.NotEmpty(t, effects.StepsFromContext(stack[0].Context))
+
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(queries) >= 2
+		}, 2*time.Second, 10*time.Millisecond)
+		mu.Lock()
+		assert.Contains(t, queries[0], "top=0|1|FF0000")
+		mu.Unlock()
+
+		result, err = stopTool.Execute(context.Background(), map[string]interface{}{})
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		assert.Empty(t, stateManager.GetEffectStack())
+	})
+
+	t.Run("Repeats", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"text":   "E",
+			"unitMs": float64(50),
+			"repeat": float64(2),
+		})
+		require.NoError(t, err)
+		text := result.Content[0].(mcp.TextContent).Text
+		require.False(t, result.IsError, text)
+		// A dot and the closing gap of 7 units, twice
+		assert.Contains(t, text, "2 passes, 800 ms")
+		require.Eventually(t, func() bool {
+			return stateManager.GetEffectStackDepth() == 0
+		}, 3*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			arguments map[string]interface{}
+			message   string
+		}{
+			{"MissingText", map[string]interface{}{}, "'text'"},
+			{"UnknownScheme", map[string]interface{}{"text": "1", "scheme": "semaphore"}, "unknown scheme"},
+			{"NotMorse", map[string]interface{}{"text": "a+b"}, "no Morse code"},
+			{"NotDigits", map[string]interface{}{"text": "B12", "scheme": "digits"}, "digits scheme"},
+			{"BadColor", map[string]interface{}{"text": "1", "color": "plaid"}, "invalid color"},
+			{"ShortUnit", map[string]interface{}{"text": "1", "unitMs": float64(10)}, "at least 50ms"},
+			{"BadRepeat", map[string]interface{}{"text": "1", "repeat": float64(-1)}, "'repeat'"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := tool.Execute(context.Background(), tt.arguments)
+				require.NoError(t, err)
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.message)
+			})
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+)
+
+// EnableIntegrationTool implements the enableIntegration MCP tool
+type EnableIntegrationTool struct {
+	registry *integrations.Registry
+}
+
+// NewEnableIntegrationTool creates a new enableIntegration tool instance
+func NewEnableIntegrationTool(registry *integrations.Registry) *EnableIntegrationTool {
+	return &EnableIntegrationTool{
+		registry: registry,
+	}
+}
+
+// Definition returns the MCP tool definition for enableIntegration
+func (t *EnableIntegrationTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "enableIntegration",
+		Description: "Resume a disabled integration so it processes webhook deliveries and polls again. Its alerts return to the UFO with the next poll or delivery.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": fmt.Sprintf("Integration to enable: %s", strings.Join(t.registry.Names(), ", ")),
+				},
+			},
+			Required: []string{"name"},
+		},
+	}
+}
+
+// Execute runs the enableIntegration tool
+func (t *EnableIntegrationTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, ok := arguments["name"].(string)
+	if !ok || name == "" {
+		return missingArgument("name", "'name' parameter is required and must be a non-empty string"), nil
+	}
+
+	if err := t.registry.SetEnabled(ctx, name, true); err != nil {
+		return ToolError{Code: CodeNotFound, Message: err.Error(), Parameter: "name", Hint: "Configured integrations: " + strings.Join(t.registry.Names(), ", ")}.Result(), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("▶️ Integration '%s' enabled", name),
+			},
+		},
+		IsError: false,
+	}, nil
+}
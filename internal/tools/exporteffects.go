@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+)
+
+// ExportEffectsTool implements the exportEffects MCP tool
+type ExportEffectsTool struct {
+	store *effects.Store
+}
+
+// NewExportEffectsTool creates a new exportEffects tool instance
+func NewExportEffectsTool(store *effects.Store) *ExportEffectsTool {
+	return &ExportEffectsTool{
+		store: store,
+	}
+}
+
+// Definition returns the MCP tool definition for exportEffects
+func (t *ExportEffectsTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "exportEffects",
+		Description: "Export stored effects as a JSON bundle that importEffects on another UFO server reads, or that can be checked into git. Exports the named effects, the effects with a tag or category, or every effect. Returns a summary followed by the bundle.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"names": map[string]interface{}{
+					"type":        "array",
+					"description": "Names of the effects to export (optional, default all)",
+					"items":       map[string]interface{}{"type": "string"},
+				},
+				"tag": map[string]interface{}{
+					"type":        "string",
+					"description": "Export only effects with this tag, when names is not given (optional)",
+				},
+				"category": map[string]interface{}{
+					"type":        "string",
+					"description": "Export only effects in this category, when names is not given (optional)",
+				},
+			},
+		},
+	}
+}
+
+// Execute runs the exportEffects tool
+func (t *ExportEffectsTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var names []string
+	if value, exists := arguments["names"]; exists {
+		list, ok := value.([]interface{})
+		if !ok {
+			return invalidArgument("names", "'names' must be an array of effect names"), nil
+		}
+		for _, item := range list {
+			name, ok := item.(string)
+			if !ok || strings.TrimSpace(name) == "" {
+				return invalidArgument("names", "'names' must contain non-empty strings"), nil
+			}
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+
+	var filter effects.Filter
+	for name, target := range map[string]*string{"tag": &filter.Tag, "category": &filter.Category} {
+		value, exists := arguments[name]
+		if !exists {
+			continue
+		}
+		text, ok := value.(string)
+		if !ok {
+			return invalidArgument(name, fmt.Sprintf("'%s' must be a string", name)), nil
+		}
+		*target = strings.TrimSpace(text)
+	}
+	if len(names) == 0 && (filter.Tag != "" || filter.Category != "") {
+		matched, _ := t.store.Find(filter)
+		if len(matched) == 0 {
+			return toolError(CodeNotFound, "no effects match the tag and category"), nil
+		}
+		for _, effect := range matched {
+			names = append(names, effect.Name)
+		}
+	}
+
+	bundle, err := t.store.Export(names)
+	if err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+	bundleJSON, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize effects: "+err.Error()), nil
+	}
+
+	exported := make([]string, len(bundle.Effects))
+	for i, effect := range bundle.Effects {
+		exported[i] = effect.Name
+	}
+	message := fmt.Sprintf("📦 Exported %d effect(s): %s\n", len(exported), strings.Join(exported, ", "))
+	message += "Pass the bundle to importEffects to load it elsewhere.\n"
+	message += "\nFull JSON:\n" + string(bundleJSON)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
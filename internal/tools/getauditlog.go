@@ -0,0 +1,172 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/audit"
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+)
+
+const (
+	// defaultAuditEntries is how many entries getAuditLog returns by default
+	defaultAuditEntries = 50
+	// maxAuditEntries is the most entries getAuditLog returns
+	maxAuditEntries = 500
+)
+
+// GetAuditLogTool implements the getAuditLog MCP tool
+type GetAuditLogTool struct {
+	logger *audit.Logger
+}
+
+// NewGetAuditLogTool creates a new getAuditLog tool instance
+func NewGetAuditLogTool(logger *audit.Logger) *GetAuditLogTool {
+	return &GetAuditLogTool{
+		logger: logger,
+	}
+}
+
+// Definition returns the MCP tool definition for getAuditLog
+func (t *GetAuditLogTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "getAuditLog",
+		Description: "Search the audit log for compliance reviews: every command sent to the UFO with the tool, arguments and client that caused it, its result and latency, as well as policy decisions, hooks and integrations. Needs the server's --audit-log file. Returns a summary followed by JSON, oldest first.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"kind": map[string]interface{}{
+					"type":        "string",
+					"description": "Only entries of this kind, such as 'device' for commands sent to the UFO (optional)",
+					"examples":    []string{"device", "policy", "hook", "integration"},
+				},
+				"tool": map[string]interface{}{
+					"type":        "string",
+					"description": "Only entries caused by this tool (optional)",
+				},
+				"client": map[string]interface{}{
+					"type":        "string",
+					"description": "Only entries caused by this client (optional)",
+				},
+				"action": map[string]interface{}{
+					"type":        "string",
+					"description": "Only entries whose action contains this text, ignoring case, such as part of a device command (optional)",
+				},
+				"errorsOnly": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only entries that failed (optional, default false)",
+				},
+				"since": map[string]interface{}{
+					"type":        "string",
+					"description": "Only entries at or after this time: an ISO-8601 timestamp, a time like 08:00 today, or a duration before now like 15m (optional)",
+					"examples":    []string{"15m", "08:00", "2025-06-01T09:00:00Z"},
+				},
+				"until": map[string]interface{}{
+					"type":        "string",
+					"description": "Only entries at or before this time, in the same forms as since (optional)",
+				},
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Return only the newest matching entries (1-%d, default %d)", maxAuditEntries, defaultAuditEntries),
+					"minimum":     1,
+					"maximum":     maxAuditEntries,
+				},
+			},
+		},
+	}
+}
+
+// Execute runs the getAuditLog tool
+func (t *GetAuditLogTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	filter := audit.Filter{Limit: defaultAuditEntries}
+	for name, target := range map[string]*string{"kind": &filter.Kind, "tool": &filter.Tool, "client": &filter.Client, "action": &filter.Action} {
+		value, exists := arguments[name]
+		if !exists {
+			continue
+		}
+		text, ok := value.(string)
+		if !ok {
+			return invalidArgument(name, fmt.Sprintf("'%s' must be a string", name)), nil
+		}
+		*target = strings.TrimSpace(text)
+	}
+	if value, exists := arguments["errorsOnly"]; exists {
+		errorsOnly, ok := value.(bool)
+		if !ok {
+			return invalidArgument("errorsOnly", "'errorsOnly' must be a boolean"), nil
+		}
+		filter.ErrorsOnly = errorsOnly
+	}
+	now := time.Now()
+	for name, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value, exists := arguments[name]
+		if !exists {
+			continue
+		}
+		text, ok := value.(string)
+		if !ok {
+			return invalidArgument(name, fmt.Sprintf("'%s' must be a string", name)), nil
+		}
+		at, err := parseStateTime(strings.TrimSpace(text), now)
+		if err != nil {
+			return invalidArgument(name, fmt.Sprintf("'%s' must be an ISO-8601 timestamp, a time like 08:00 or a duration like 15m, got '%s'", name, text)), nil
+		}
+		*target = at
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && filter.Until.Before(filter.Since) {
+		return invalidArgument("until", "'until' is before 'since'"), nil
+	}
+	if value, exists := arguments["limit"]; exists {
+		limit, ok := wholeNumber(value)
+		if !ok || limit < 1 || limit > maxAuditEntries {
+			return invalidArgument("limit", fmt.Sprintf("'limit' must be a whole number from 1 to %d", maxAuditEntries)), nil
+		}
+		filter.Limit = limit
+	}
+
+	entries, err := t.logger.Query(filter)
+	if errors.Is(err, audit.ErrNoFile) {
+		return ToolError{Code: CodeUnavailable, Message: "the audit log is not kept in a file", Hint: "Start the server with --audit-log to search it"}.Result(), nil
+	}
+	if err != nil {
+		return toolError(CodeInternal, fmt.Sprintf("failed to read the audit log: %v", err)), nil
+	}
+
+	entriesJSON, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, fmt.Sprintf("failed to serialize audit entries: %v", err)), nil
+	}
+
+	var message string
+	if len(entries) == 0 {
+		message = "🧾 No matching audit entries"
+	} else {
+		message = fmt.Sprintf("🧾 %d audit entries, oldest first:\n", len(entries))
+		for _, entry := range entries {
+			line := fmt.Sprintf("• %s %s %s", timezone.ISO(entry.Time), entry.Kind, entry.Action)
+			if tool, ok := entry.Data["tool"].(string); ok {
+				line += " via " + tool
+			}
+			if entry.Result != "" {
+				line += " → " + entry.Result
+			}
+			message += line + "\n"
+		}
+	}
+	message += "\nFull JSON:\n" + string(entriesJSON)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
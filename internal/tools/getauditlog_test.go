@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAuditLogTool_Execute(t *testing.T) {
+	logger, err := audit.NewLogger(filepath.Join(t.TempDir(), "audit.jsonl"))
+	require.NoError(t, err)
+	defer logger.Close()
+	logger.Record(audit.Entry{Kind: "device", Action: "dim=10", Result: "OK", Data: map[string]interface{}{"tool": "setBrightness", "latencyMs": 12}})
+	logger.Record(audit.Entry{Kind: "device", Action: "top=0|15|FF0000", Result: "ERROR: device offline", Data: map[string]interface{}{"tool": "configureLighting"}})
+	logger.Record(audit.Entry{Kind: "policy", Action: "sendRawApi", Result: "denied"})
+
+	tool := NewGetAuditLogTool(logger)
+	assert.Equal(t, "getAuditLog", tool.Definition().Name)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "3 audit entries")
+	assert.Contains(t, text, "dim=10 via setBrightness → OK")
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"kind": "device", "errorsOnly": true, "since": "5m"})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "1 audit entries")
+	assert.Contains(t, text, "configureLighting")
+	assert.NotContains(t, text, "dim=10")
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"tool": "runMacro"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "No matching audit entries")
+}
+
+func TestGetAuditLogTool_ValidationErrors(t *testing.T) {
+	logger, err := audit.NewLogger(filepath.Join(t.TempDir(), "audit.jsonl"))
+	require.NoError(t, err)
+	defer logger.Close()
+	tool := NewGetAuditLogTool(logger)
+
+	for _, arguments := range []map[string]interface{}{
+		{"kind": 3},
+		{"errorsOnly": "yes"},
+		{"since": "yesterday-ish"},
+		{"since": "5m", "until": "10m"},
+		{"limit": float64(0)},
+		{"limit": float64(maxAuditEntries + 1)},
+	} {
+		result, err := tool.Execute(context.Background(), arguments)
+		require.NoError(t, err)
+		assert.True(t, result.IsError, "arguments %v", arguments)
+	}
+
+	stderrLogger, err := audit.NewLogger("")
+	require.NoError(t, err)
+	result, err := NewGetAuditLogTool(stderrLogger).Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "--audit-log")
+}
@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+)
+
+// GetDeviceInfoTool implements the getDeviceInfo MCP tool
+type GetDeviceInfoTool struct {
+	client *device.Client
+}
+
+// NewGetDeviceInfoTool creates a new getDeviceInfo tool instance
+func NewGetDeviceInfoTool(client *device.Client) *GetDeviceInfoTool {
+	return &GetDeviceInfoTool{
+		client: client,
+	}
+}
+
+// Definition returns the MCP tool definition for getDeviceInfo
+func (t *GetDeviceInfoTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "getDeviceInfo",
+		Description: "Ask the UFO about itself: firmware version, IP address, WiFi network (SSID), hostname, MAC address, uptime, free memory, and its clock with its NTP sync and drift from the server's clock, as far as its firmware reports them. Warns when the UFO restarted recently. Returns a summary followed by JSON.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+			Required:   []string{},
+		},
+	}
+}
+
+// Execute runs the getDeviceInfo tool
+func (t *GetDeviceInfoTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	info, err := t.client.FetchInfo(ctx)
+	if err != nil {
+		return toolError(CodeDeviceError, fmt.Sprintf("Failed to get device info from UFO at %s: %v", t.client.Address(), err)), nil
+	}
+
+	infoJSON, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize device info: "+err.Error()), nil
+	}
+
+	message := fmt.Sprintf("🛸 UFO at %s\n\n", t.client.Address())
+	message += fmt.Sprintf("• Firmware: %s\n", orUnknown(info.Firmware))
+	message += fmt.Sprintf("• IP: %s\n", orUnknown(info.IP))
+	message += fmt.Sprintf("• WiFi: %s\n", orUnknown(info.SSID))
+	if info.Hostname != "" {
+		message += fmt.Sprintf("• Hostname: %s\n", info.Hostname)
+	}
+	if info.MAC != "" {
+		message += fmt.Sprintf("• MAC: %s\n", info.MAC)
+	}
+	if info.UptimeSeconds != nil {
+		message += fmt.Sprintf("• Uptime: %s\n", info.Uptime())
+	} else {
+		message += "• Uptime: unknown\n"
+	}
+	if info.FreeHeap != nil {
+		message += fmt.Sprintf("• Free memory: %d bytes\n", *info.FreeHeap)
+	}
+	if info.Clock != nil {
+		message += fmt.Sprintf("• Clock: %s", info.Clock.Format(time.RFC3339))
+		if info.ClockSynced != nil {
+			if *info.ClockSynced {
+				message += ", NTP synced"
+			} else {
+				message += ", not synced"
+			}
+		}
+		if drift, ok := info.ClockDrift(); ok {
+			message += ", " + formatDrift(drift)
+		}
+		message += "\n"
+	}
+	if info.RecentlyRebooted() {
+		message += fmt.Sprintf("\n⚠️ The UFO restarted %s ago (booted %s). It comes back dark; the server shows its state again when it notices the restart.\n", info.Uptime(), info.BootedAt.Local().Format(time.Kitchen))
+	}
+	message += "\nFull JSON:\n" + string(infoJSON)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// formatDrift describes the UFO's clock offset, e.g. "1.2s ahead of the server"
+func formatDrift(drift time.Duration) string {
+	switch {
+	case drift.Abs() < time.Second:
+		return "in step with the server"
+	case drift > 0:
+		return fmt.Sprintf("%s ahead of the server", drift.Round(100*time.Millisecond))
+	default:
+		return fmt.Sprintf("%s behind the server", (-drift).Round(100*time.Millisecond))
+	}
+}
+
+// orUnknown labels fields the firmware did not report
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDeviceInfoTool_Execute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/info" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"version":"1.4.2","ip":"192.168.1.50","ssid":"office","uptime":7200}`))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	tool := NewGetDeviceInfoTool(device.NewClient())
+	assert.Equal(t, "getDeviceInfo", tool.Definition().Name)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "Firmware: 1.4.2")
+	assert.Contains(t, text, "IP: 192.168.1.50")
+	assert.Contains(t, text, "WiFi: office")
+	assert.Contains(t, text, "Uptime: 2h0m0s")
+	assert.Contains(t, text, `"uptimeSeconds": 7200`)
+
+	server.Close()
+	client := device.NewClient()
+	client.SetRetryPolicy(device.RetryPolicy{MaxAttempts: 1})
+	result, err = NewGetDeviceInfoTool(client).Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestGetDeviceInfoTool_ClockAndRecentRestart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"uptime":90,"time":%d,"ntp":true}`, time.Now().Add(5*time.Second).Unix())
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	result, err := NewGetDeviceInfoTool(device.NewClient()).Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "NTP synced")
+	assert.Contains(t, text, "ahead of the server")
+	assert.Contains(t, text, "restarted 1m30s ago")
+	assert.Contains(t, text, `"clockDriftMs"`)
+}
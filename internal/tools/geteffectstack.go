@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+)
+
+// GetEffectStackTool implements the getEffectStack MCP tool
+type GetEffectStackTool struct {
+	stateManager *state.Manager
+}
+
+// NewGetEffectStackTool creates a new getEffectStack tool instance
+func NewGetEffectStackTool(stateManager *state.Manager) *GetEffectStackTool {
+	return &GetEffectStackTool{
+		stateManager: stateManager,
+	}
+}
+
+// EffectStackEntry describes one layer of the effect stack
+type EffectStackEntry struct {
+	Position    int                    `json:"position"` // 0 is the bottom of the stack
+	Current     bool                   `json:"current"`  // true for the visible (top) effect
+	Name        string                 `json:"name"`
+	InstanceID  string                 `json:"instanceId,omitempty"`
+	Pattern     string                 `json:"pattern"`
+	StartTime   *time.Time             `json:"startTime,omitempty"`
+	ElapsedMs   int64                  `json:"elapsedMs"`
+	RemainingMs *int64                 `json:"remainingMs,omitempty"` // omitted for perpetual effects
+	Perpetual   bool                   `json:"perpetual"`
+	Paused      bool                   `json:"paused"`
+	Context     map[string]interface{} `json:"context,omitempty"`
+}
+
+// Definition returns the MCP tool definition for getEffectStack
+func (t *GetEffectStackTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "getEffectStack",
+		Description: "Get the full effect stack as JSON, bottom first: each layer's name, instance ID, pattern, context, start time, elapsed and remaining duration, and whether it is paused. The last entry is the effect currently showing.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+		},
+	}
+}
+
+// Execute runs the getEffectStack tool
+func (t *GetEffectStackTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	stackJSON, err := json.MarshalIndent(DescribeEffectStack(t.stateManager.GetEffectStack(), time.Now()), "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize effect stack: "+err.Error()), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: string(stackJSON),
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// DescribeEffectStack converts stack entries into their reported form at now
+func DescribeEffectStack(stack []state.EffectStackItem, now time.Time) []EffectStackEntry {
+	entries := make([]EffectStackEntry, 0, len(stack))
+	for i, item := range stack {
+		entry := EffectStackEntry{
+			Position:   i,
+			Current:    i == len(stack)-1,
+			Name:       item.Name,
+			InstanceID: item.InstanceID(),
+			Pattern:    item.Pattern,
+			ElapsedMs:  item.Elapsed(now).Milliseconds(),
+			Perpetual:  item.Perpetual(),
+			Paused:     item.Paused(),
+			Context:    item.Context,
+		}
+		if start := item.StartTime(); !start.IsZero() {
+			start = timezone.In(start)
+			entry.StartTime = &start
+		}
+		if remaining, timed := item.Remaining(now); timed {
+			ms := remaining.Milliseconds()
+			entry.RemainingMs = &ms
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// ActiveEffect describes the effect currently showing, for progress displays
+type ActiveEffect struct {
+	Active     bool              `json:"active"`
+	StackDepth int               `json:"stackDepth"`
+	Effect     *EffectStackEntry `json:"effect,omitempty"`
+	DurationMs *int              `json:"durationMs,omitempty"` // omitted for perpetual effects
+	Progress   *float64          `json:"progress,omitempty"`   // 0 to 1, omitted for perpetual effects
+}
+
+// DescribeActiveEffect reports the top of the stack with its timing at now
+func DescribeActiveEffect(stack []state.EffectStackItem, now time.Time) ActiveEffect {
+	active := ActiveEffect{StackDepth: len(stack)}
+	if len(stack) == 0 {
+		return active
+	}
+
+	entries := DescribeEffectStack(stack, now)
+	active.Active = true
+	active.Effect = &entries[len(entries)-1]
+
+	top := stack[len(stack)-1]
+	if !top.Perpetual() {
+		duration := top.DurationMs()
+		progress := float64(top.Elapsed(now).Milliseconds()) / float64(duration)
+		if progress > 1 {
+			progress = 1
+		}
+		active.DurationMs = &duration
+		active.Progress = &progress
+	}
+	return active
+}
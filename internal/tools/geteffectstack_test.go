@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEffectStackTool(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	tool := NewGetEffectStackTool(stateManager)
+
+	def := tool.Definition()
+	assert.Equal(t, "getEffectStack", def.Name)
+
+	start := time.Now().Add(-3 * time.Second)
+	stateManager.PushEffect("rainbow", "effect=rainbow", map[string]interface{}{
+		"duration":  0,
+		"perpetual": true,
+		"startTime": start,
+	})
+	stateManager.PushEffect("alert", "effect=alert", map[string]interface{}{
+		"duration":  5000,
+		"perpetual": false,
+		"startTime": start,
+	})
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	var entries []EffectStackEntry
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &entries))
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "rainbow", entries[0].Name)
+	assert.True(t, entries[0].Perpetual)
+	assert.Nil(t, entries[0].RemainingMs)
+	assert.False(t, entries[0].Current)
+
+	assert.Equal(t, "alert", entries[1].Name)
+	assert.True(t, entries[1].Current)
+	require.NotNil(t, entries[1].RemainingMs)
+	assert.InDelta(t, 2000, *entries[1].RemainingMs, 200)
+	assert.InDelta(t, 3000, entries[1].ElapsedMs, 200)
+}
+
+func TestDescribeActiveEffect(t *testing.T) {
+	now := time.Now()
+
+	idle := DescribeActiveEffect(nil, now)
+	assert.False(t, idle.Active)
+	assert.Nil(t, idle.Effect)
+
+	stack := []state.EffectStackItem{
+		{Name: "rainbow", Pattern: "effect=rainbow", Context: map[string]interface{}{"perpetual": true, "startTime": now.Add(-time.Minute)}},
+		{Name: "alert", Pattern: "effect=alert", Context: map[string]interface{}{"duration": 4000, "startTime": now.Add(-time.Second)}},
+	}
+	active := DescribeActiveEffect(stack, now)
+	assert.True(t, active.Active)
+	assert.Equal(t, 2, active.StackDepth)
+	require.NotNil(t, active.Effect)
+	assert.Equal(t, "alert", active.Effect.Name)
+	assert.Equal(t, 1, active.Effect.Position)
+	require.NotNil(t, active.Progress)
+	assert.InDelta(t, 0.25, *active.Progress, 0.01)
+	assert.Equal(t, 4000, *active.DurationMs)
+
+	perpetual := DescribeActiveEffect(stack[:1], now)
+	assert.True(t, perpetual.Effect.Perpetual)
+	assert.Nil(t, perpetual.Progress)
+	assert.Nil(t, perpetual.DurationMs)
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+)
+
+// GetFirmwareVersionTool implements the getFirmwareVersion MCP tool
+type GetFirmwareVersionTool struct {
+	client *device.Client
+}
+
+// NewGetFirmwareVersionTool creates a new getFirmwareVersion tool instance
+func NewGetFirmwareVersionTool(client *device.Client) *GetFirmwareVersionTool {
+	return &GetFirmwareVersionTool{
+		client: client,
+	}
+}
+
+// Definition returns the MCP tool definition for getFirmwareVersion
+func (t *GetFirmwareVersionTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "getFirmwareVersion",
+		Description: "Ask the UFO for its firmware version and whether its update server offers a newer one, along with the progress of an update that is running. Returns a summary followed by JSON.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+			Required:   []string{},
+		},
+	}
+}
+
+// Execute runs the getFirmwareVersion tool
+func (t *GetFirmwareVersionTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	status, err := t.client.FetchFirmware(ctx)
+	if err != nil {
+		return toolError(CodeDeviceError, fmt.Sprintf("Failed to get firmware status from UFO at %s: %v", t.client.Address(), err)), nil
+	}
+
+	statusJSON, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize firmware status: "+err.Error()), nil
+	}
+
+	message := fmt.Sprintf("🛸 UFO at %s\n\n", t.client.Address())
+	message += fmt.Sprintf("• Firmware: %s\n", orUnknown(status.Current))
+	switch {
+	case !status.Supported:
+		message += "• Updates: not supported by this firmware\n"
+	case status.Updating():
+		message += fmt.Sprintf("• Update: %s", status.State)
+		if status.Progress != nil {
+			message += fmt.Sprintf(" (%d%%)", *status.Progress)
+		}
+		message += "\n"
+	case status.UpdateAvailable:
+		message += fmt.Sprintf("• Update available: %s\n", orUnknown(status.Available))
+	default:
+		message += "• Up to date\n"
+	}
+	if status.Message != "" {
+		message += fmt.Sprintf("• Message: %s\n", status.Message)
+	}
+	message += "\nFull JSON:\n" + string(statusJSON)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
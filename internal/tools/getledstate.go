@@ -23,7 +23,7 @@ func NewGetLedStateTool(stateManager *state.Manager) *GetLedStateTool {
 func (t *GetLedStateTool) Definition() mcp.Tool {
 	return mcp.Tool{
 		Name:        "getLedState",
-		Description: "Get the current LED state showing all LED colors, brightness level, logo state, and any running effect. Returns the shadow state maintained by the MCP server.",
+		Description: "Get the current LED state showing all LED colors, brightness level, logo state, and any running effect with its elapsed and remaining time when it is timed. Returns the shadow state maintained by the MCP server.",
 		InputSchema: mcp.ToolInputSchema{
 			Type:       "object",
 			Properties: map[string]interface{}{},
@@ -37,15 +37,7 @@ func (t *GetLedStateTool) Execute(ctx context.Context, arguments map[string]inte
 	// Get the current LED state as JSON
 	ledStateJSON, err := t.stateManager.ToJSON()
 	if err != nil {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Failed to get LED state: " + err.Error(),
-				},
-			},
-			IsError: true,
-		}, nil
+		return toolError(CodeInternal, "Failed to get LED state: "+err.Error()), nil
 	}
 
 	// Return formatted response
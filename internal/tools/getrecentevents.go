@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/timezone"
+)
+
+// defaultRecentEvents is how many events getRecentEvents returns by default
+const defaultRecentEvents = 50
+
+// GetRecentEventsTool implements the getRecentEvents MCP tool
+type GetRecentEventsTool struct {
+	broadcaster *events.Broadcaster
+}
+
+// NewGetRecentEventsTool creates a new getRecentEvents tool instance
+func NewGetRecentEventsTool(broadcaster *events.Broadcaster) *GetRecentEventsTool {
+	return &GetRecentEventsTool{
+		broadcaster: broadcaster,
+	}
+}
+
+// Definition returns the MCP tool definition for getRecentEvents
+func (t *GetRecentEventsTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "getRecentEvents",
+		Description: fmt.Sprintf("List the events the server published recently, such as effects starting and stopping, alerts and the UFO going offline, so a client that connected late can see what happened. The server keeps the last %d events. Returns a summary followed by JSON, oldest first.", events.DefaultHistorySize),
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"types": map[string]interface{}{
+					"type":        "array",
+					"description": "Event types to include (optional, default all)",
+					"items": map[string]interface{}{
+						"type":     "string",
+						"examples": []string{events.EventEffectStarted, events.EventAlertFiring, events.EventDeviceOffline},
+					},
+				},
+				"since": map[string]interface{}{
+					"type":        "string",
+					"description": "Only events at or after this time: an ISO-8601 timestamp, a time like 08:00 today, or a duration before now like 15m (optional)",
+					"examples":    []string{"15m", "08:00", "2025-06-01T09:00:00Z"},
+				},
+				"until": map[string]interface{}{
+					"type":        "string",
+					"description": "Only events at or before this time, in the same forms as since (optional)",
+				},
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Return only the newest matching events (1-%d, default %d)", events.DefaultHistorySize, defaultRecentEvents),
+					"minimum":     1,
+					"maximum":     events.DefaultHistorySize,
+				},
+			},
+		},
+	}
+}
+
+// Execute runs the getRecentEvents tool
+func (t *GetRecentEventsTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	filter := events.HistoryFilter{Limit: defaultRecentEvents}
+	if value, exists := arguments["types"]; exists {
+		list, ok := value.([]interface{})
+		if !ok {
+			return invalidArgument("types", "'types' must be an array of event types"), nil
+		}
+		for _, item := range list {
+			eventType, ok := item.(string)
+			if !ok || strings.TrimSpace(eventType) == "" {
+				return invalidArgument("types", "'types' must contain non-empty strings"), nil
+			}
+			filter.Types = append(filter.Types, strings.TrimSpace(eventType))
+		}
+	}
+	now := time.Now()
+	for name, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value, exists := arguments[name]
+		if !exists {
+			continue
+		}
+		text, ok := value.(string)
+		if !ok {
+			return invalidArgument(name, fmt.Sprintf("'%s' must be a string", name)), nil
+		}
+		at, err := parseStateTime(strings.TrimSpace(text), now)
+		if err != nil {
+			return invalidArgument(name, fmt.Sprintf("'%s' must be an ISO-8601 timestamp, a time like 08:00 or a duration like 15m, got '%s'", name, text)), nil
+		}
+		*target = at
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && filter.Until.Before(filter.Since) {
+		return invalidArgument("until", "'until' is before 'since'"), nil
+	}
+	if value, exists := arguments["limit"]; exists {
+		limit, ok := wholeNumber(value)
+		if !ok || limit < 1 || limit > events.DefaultHistorySize {
+			return invalidArgument("limit", fmt.Sprintf("'limit' must be a whole number from 1 to %d", events.DefaultHistorySize)), nil
+		}
+		filter.Limit = limit
+	}
+
+	recent := t.broadcaster.Recent(filter)
+	eventsJSON, err := json.MarshalIndent(recent, "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize events: "+err.Error()), nil
+	}
+
+	var message string
+	if len(recent) == 0 {
+		message = "📜 No matching events"
+	} else {
+		message = fmt.Sprintf("📜 %d event(s), oldest first:\n", len(recent))
+		for _, event := range recent {
+			message += fmt.Sprintf("• %s %s\n", timezone.ISO(event.Timestamp), event.Type)
+		}
+	}
+	message += "\nFull JSON:\n" + string(eventsJSON)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
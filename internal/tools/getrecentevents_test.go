@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRecentEventsTool_Execute(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	broadcaster.PublishEffectStarted("rainbow", 10)
+	broadcaster.PublishDimChanged(80)
+	broadcaster.PublishEffectStopped("rainbow", "manual")
+
+	tool := NewGetRecentEventsTool(broadcaster)
+	assert.Equal(t, "getRecentEvents", tool.Definition().Name)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "3 event(s)")
+	assert.Contains(t, text, events.EventDimChanged)
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"types": []interface{}{events.EventEffectStarted, events.EventEffectStopped},
+		"since": "5m",
+		"limit": float64(1),
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "1 event(s)")
+	assert.Contains(t, text, `"reason": "manual"`)
+	assert.NotContains(t, text, events.EventDimChanged)
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"until": "1h"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "No matching events")
+}
+
+func TestGetRecentEventsTool_ValidationErrors(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	tool := NewGetRecentEventsTool(broadcaster)
+
+	for _, arguments := range []map[string]interface{}{
+		{"types": "effect_started"},
+		{"types": []interface{}{""}},
+		{"since": "yesterday-ish"},
+		{"since": "5m", "until": "10m"},
+		{"limit": float64(0)},
+		{"limit": float64(events.DefaultHistorySize + 1)},
+	} {
+		result, err := tool.Execute(context.Background(), arguments)
+		require.NoError(t, err)
+		assert.True(t, result.IsError, "expected %v to be rejected", arguments)
+	}
+}
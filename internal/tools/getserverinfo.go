@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/features"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+	"github.com/starspace46/ufo-mcp-go/internal/version"
+)
+
+// GetServerInfoTool implements the getServerInfo MCP tool
+type GetServerInfoTool struct {
+	features *features.Registry
+	client   *device.Client
+	started  time.Time
+}
+
+// NewGetServerInfoTool creates a new getServerInfo tool instance
+func NewGetServerInfoTool(registry *features.Registry, client *device.Client, started time.Time) *GetServerInfoTool {
+	return &GetServerInfoTool{
+		features: registry,
+		client:   client,
+		started:  started,
+	}
+}
+
+// Definition returns the MCP tool definition for getServerInfo
+func (t *GetServerInfoTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "getServerInfo",
+		Description: "Describe this server: its version, uptime, the UFO it controls, and which optional features (the UFO itself, saved palettes and macros, state history, integrations and so on) are available, degraded or unavailable and why. Tools of unavailable features are not offered until they recover. Returns a summary followed by JSON.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+			Required:   []string{},
+		},
+	}
+}
+
+// Execute runs the getServerInfo tool
+func (t *GetServerInfoTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	list := t.features.List()
+	info := map[string]interface{}{
+		"version":       version.Version,
+		"gitCommit":     version.GitCommit,
+		"buildTime":     version.BuildTime,
+		"uptimeSeconds": int64(time.Since(t.started).Seconds()),
+		"ufo":           t.client.Address(),
+		"features":      list,
+	}
+	infoJSON, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize server info: "+err.Error()), nil
+	}
+
+	message := fmt.Sprintf("🛸 UFO MCP server %s, up %s, controlling %s\n", version.Version, format.Duration(time.Since(t.started)), t.client.Address())
+	impaired := t.features.Impaired()
+	if len(impaired) == 0 {
+		message += fmt.Sprintf("\nAll %d features are available\n", len(list))
+	} else {
+		message += fmt.Sprintf("\n%d of %d features are not fully available:\n", len(impaired), len(list))
+		for _, feature := range impaired {
+			icon := "❌"
+			if feature.Status == features.Degraded {
+				icon = "⚠️"
+			}
+			message += fmt.Sprintf("%s %s: %s", icon, feature.Name, feature.Status)
+			if feature.Reason != "" {
+				message += " (" + feature.Reason + ")"
+			}
+			if feature.Status == features.Unavailable && len(feature.Tools) > 0 {
+				message += "; tools not offered: " + strings.Join(feature.Tools, ", ")
+			}
+			message += "\n"
+		}
+	}
+	message += "\nFull JSON:\n" + string(infoJSON)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
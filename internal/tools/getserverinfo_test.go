@@ -0,0 +1,38 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/features"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetServerInfoTool_Execute(t *testing.T) {
+	registry := features.NewRegistry()
+	registry.Register(features.Device, "The UFO itself")
+	registry.Register(features.Macros, "Saved macros", "defineMacro", "runMacro")
+	client := device.NewSimulatedClient(device.NewSimulator())
+
+	tool := NewGetServerInfoTool(registry, client, time.Now().Add(-time.Hour))
+	assert.Equal(t, "getServerInfo", tool.Definition().Name)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "All 2 features are available")
+	assert.Contains(t, text, `"uptimeSeconds": 3600`)
+
+	registry.Set(features.Macros, features.Unavailable, "parsing macros JSON: unexpected end of input")
+	result, err = tool.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "1 of 2 features are not fully available")
+	assert.Contains(t, text, "macros: unavailable (parsing macros JSON: unexpected end of input)")
+	assert.Contains(t, text, "tools not offered: defineMacro, runMacro")
+}
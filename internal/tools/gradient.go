@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/starspace46/ufo-mcp-go/internal/color"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/palettes"
+)
+
+// gradientSchema returns the JSON schema of a ring's gradient argument
+func gradientSchema(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "object",
+		"description": description,
+		"properties": map[string]interface{}{
+			"from": map[string]interface{}{
+				"type":        "string",
+				"description": "Color of the first LED (hex, #RGB, rgb(r,g,b), CSS color name or a palette color such as 'xmas:2')",
+			},
+			"to": map[string]interface{}{
+				"type":        "string",
+				"description": "Color of the last LED",
+			},
+			"start": map[string]interface{}{
+				"type":        "integer",
+				"description": "LED the gradient starts at (optional, default 0). Gradients wrap around the ring",
+				"minimum":     0,
+				"maximum":     device.RingLEDs - 1,
+				"default":     0,
+			},
+			"length": map[string]interface{}{
+				"type":        "integer",
+				"description": fmt.Sprintf("Number of LEDs the gradient spans (optional, default %d). The other LEDs show the background", device.RingLEDs),
+				"minimum":     2,
+				"maximum":     device.RingLEDs,
+				"default":     device.RingLEDs,
+			},
+			"easing": map[string]interface{}{
+				"type":        "string",
+				"description": "How the color changes along the gradient: evenly ('linear'), slowly at first ('easeIn'), slowly at the end ('easeOut') or slowly at both ends ('easeInOut') (optional, default linear)",
+				"enum":        color.Easings,
+				"default":     color.DefaultEasing,
+			},
+		},
+		"required": []string{"from", "to"},
+	}
+}
+
+// parseGradient validates a gradient argument and returns the device
+// segments drawing it, one color per LED
+func parseGradient(value interface{}, store *palettes.Store) ([]string, error) {
+	gradient, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("gradient must be an object with from and to colors")
+	}
+
+	var ends [2]string
+	for i, name := range []string{"from", "to"} {
+		spec, ok := gradient[name].(string)
+		if !ok || spec == "" {
+			return nil, fmt.Errorf("gradient %s must be a color", name)
+		}
+		hex, err := store.Color(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gradient %s color: %v", name, err)
+		}
+		ends[i] = hex
+	}
+
+	start := 0
+	if value, exists := gradient["start"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < 0 || n >= device.RingLEDs {
+			return nil, fmt.Errorf("gradient start must be a whole number between 0 and %d", device.RingLEDs-1)
+		}
+		start = n
+	}
+
+	length := device.RingLEDs
+	if value, exists := gradient["length"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < 2 || n > device.RingLEDs {
+			return nil, fmt.Errorf("gradient length must be a whole number between 2 and %d", device.RingLEDs)
+		}
+		length = n
+	}
+
+	easing := color.DefaultEasing
+	if value, exists := gradient["easing"]; exists {
+		if easing, ok = value.(string); !ok {
+			return nil, fmt.Errorf("gradient easing must be a string")
+		}
+	}
+
+	colors, err := color.Gradient(ends[0], ends[1], length, easing)
+	if err != nil {
+		return nil, err
+	}
+	return gradientSegments(colors, start), nil
+}
+
+// gradientSegments lays colors out one LED each from start, merging
+// neighbouring LEDs of the same color. A gradient that runs past the last
+// LED continues from LED 0, which the device needs as a separate segment.
+func gradientSegments(colors []string, start int) []string {
+	var segments []string
+	for i := 0; i < len(colors); {
+		first := (start + i) % device.RingLEDs
+		length := 1
+		for i+length < len(colors) && colors[i+length] == colors[i] && first+length < device.RingLEDs {
+			length++
+		}
+		segments = append(segments, fmt.Sprintf("%d|%d|%s", first, length, colors[i]))
+		i += length
+	}
+	return segments
+}
@@ -0,0 +1,167 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+)
+
+// ImportEffectsTool implements the importEffects MCP tool
+type ImportEffectsTool struct {
+	store *effects.Store
+}
+
+// NewImportEffectsTool creates a new importEffects tool instance
+func NewImportEffectsTool(store *effects.Store) *ImportEffectsTool {
+	return &ImportEffectsTool{
+		store: store,
+	}
+}
+
+// Definition returns the MCP tool definition for importEffects
+func (t *ImportEffectsTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "importEffects",
+		Description: "Import a bundle of effects written by exportEffects, or a plain array of effects like the effects file. 'merge' adds the bundle to the stored effects, resolving names already taken with onConflict; 'replace' removes stored effects not in the bundle, except the built-in seed effects. Every effect is checked first, so nothing changes when one is invalid. Use dryRun to see what would change.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"bundle": map[string]interface{}{
+					"type":        []string{"object", "array", "string"},
+					"description": "The bundle from exportEffects, as an object or JSON text",
+				},
+				"mode": map[string]interface{}{
+					"type":        "string",
+					"description": "'merge' (default) keeps stored effects; 'replace' removes those not in the bundle, except built-in seed effects",
+					"enum":        []string{"merge", "replace"},
+				},
+				"onConflict": map[string]interface{}{
+					"type":        "string",
+					"description": "When merging, what to do with an effect whose name is taken: 'fail' (default) imports nothing, 'skip' keeps the stored effect, 'overwrite' replaces it, 'rename' imports it as name_2",
+					"enum":        []string{effects.ConflictFail, effects.ConflictSkip, effects.ConflictOverwrite, effects.ConflictRename},
+				},
+				"dryRun": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Report what would change without changing anything (optional, default false)",
+				},
+			},
+			Required: []string{"bundle"},
+		},
+	}
+}
+
+// Execute runs the importEffects tool
+func (t *ImportEffectsTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var data []byte
+	switch value := arguments["bundle"].(type) {
+	case string:
+		data = []byte(value)
+	case map[string]interface{}, []interface{}:
+		data, _ = json.Marshal(value)
+	default:
+		return missingArgument("bundle", "'bundle' is required: the object from exportEffects or its JSON text"), nil
+	}
+	bundle, err := effects.ParseBundle(data)
+	if err != nil {
+		return invalidArgument("bundle", err.Error()), nil
+	}
+	for _, effect := range bundle.Effects {
+		if effect != nil && !isValidEffectName(effect.Name) {
+			return invalidArgument("bundle", fmt.Sprintf("effect name '%s' must contain only letters, numbers, and underscores", effect.Name)), nil
+		}
+	}
+
+	var opts effects.ImportOptions
+	if value, exists := arguments["mode"]; exists {
+		mode, _ := value.(string)
+		switch mode {
+		case "merge":
+		case "replace":
+			opts.Replace = true
+			opts.Keep = seedEffects
+		default:
+			return invalidArgument("mode", "'mode' must be 'merge' or 'replace'"), nil
+		}
+	}
+	if value, exists := arguments["onConflict"]; exists {
+		strategy, ok := value.(string)
+		if !ok {
+			return invalidArgument("onConflict", "'onConflict' must be a string"), nil
+		}
+		opts.OnConflict = strategy
+	}
+	if value, exists := arguments["dryRun"]; exists {
+		dryRun, ok := value.(bool)
+		if !ok {
+			return invalidArgument("dryRun", "'dryRun' must be a boolean"), nil
+		}
+		opts.DryRun = dryRun
+	}
+
+	report, err := t.store.Import(bundle, opts)
+	if err != nil {
+		if report != nil && len(report.Conflicts) > 0 {
+			return ToolError{
+				Code:      CodeConflict,
+				Message:   err.Error() + "; nothing was imported",
+				Parameter: "onConflict",
+				Hint:      "Choose onConflict 'skip', 'overwrite' or 'rename'",
+			}.Result(), nil
+		}
+		if report != nil && len(report.Invalid) > 0 {
+			return ToolError{
+				Code:      CodeInvalidArgument,
+				Message:   err.Error() + "; nothing was imported",
+				Parameter: "bundle",
+				Hint:      "Fix the patterns, checking them with validatePattern, or leave the effects out of the bundle",
+			}.Result(), nil
+		}
+		return invalidArgument("bundle", err.Error()), nil
+	}
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize import report: "+err.Error()), nil
+	}
+
+	message := fmt.Sprintf("📥 Imported %d effect(s)\n", len(report.Added)+len(report.Updated)+len(report.Renamed))
+	if report.DryRun {
+		message = "🔍 Dry run, nothing was changed\n"
+	}
+	for _, line := range []struct {
+		label string
+		names []string
+	}{
+		{"Added", report.Added},
+		{"Updated", report.Updated},
+		{"Skipped", report.Skipped},
+		{"Removed", report.Removed},
+	} {
+		if len(line.names) > 0 {
+			message += fmt.Sprintf("• %s: %s\n", line.label, strings.Join(line.names, ", "))
+		}
+	}
+	if len(report.Renamed) > 0 {
+		renamed := make([]string, 0, len(report.Renamed))
+		for from, to := range report.Renamed {
+			renamed = append(renamed, from+" → "+to)
+		}
+		sort.Strings(renamed)
+		message += fmt.Sprintf("• Renamed: %s\n", strings.Join(renamed, ", "))
+	}
+	message += "\nFull JSON:\n" + string(reportJSON)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
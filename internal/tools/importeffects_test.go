@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+)
+
+func TestExportImportEffects_RoundTrip(t *testing.T) {
+	source := effects.NewStore(t.TempDir() + "/effects.json")
+	source.Add(&effects.Effect{Name: "deployGlow", Description: "Deploying", Pattern: "top=0|15|00FFFF", Category: "status"})
+	source.Add(&effects.Effect{Name: "redAlert", Description: "Alert", Pattern: "top=0|15|FF0000", Category: "alerts"})
+
+	result, _ := NewExportEffectsTool(source).Execute(context.Background(), map[string]interface{}{"category": "status"})
+	if result.IsError {
+		t.Fatalf("exportEffects failed: %v", result.Content)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "Exported 1 effect(s): deployGlow") {
+		t.Errorf("expected only deployGlow exported, got:\n%s", text)
+	}
+	bundleJSON := text[strings.Index(text, "Full JSON:\n")+len("Full JSON:\n"):]
+
+	target := effects.NewStore(t.TempDir() + "/effects.json")
+	target.Add(&effects.Effect{Name: "deployGlow", Description: "Local version", Pattern: "top=0|15|FFFFFF"})
+	tool := NewImportEffectsTool(target)
+
+	result, _ = tool.Execute(context.Background(), map[string]interface{}{"bundle": bundleJSON})
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "effects already exist: deployGlow") {
+		t.Errorf("expected a conflict by default, got %v", result.Content)
+	}
+
+	result, _ = tool.Execute(context.Background(), map[string]interface{}{"bundle": bundleJSON, "onConflict": "rename"})
+	if result.IsError {
+		t.Fatalf("importEffects failed: %v", result.Content)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "Renamed: deployGlow → deployGlow_2") {
+		t.Errorf("expected the rename reported, got:\n%s", text)
+	}
+	if imported, exists := target.Get("deployGlow_2"); !exists || imported.Category != "status" {
+		t.Errorf("expected the imported effect as deployGlow_2, got %+v", imported)
+	}
+}
+
+func TestImportEffectsTool_ReplaceKeepsSeedEffects(t *testing.T) {
+	store := effects.NewStore(t.TempDir() + "/effects.json")
+	store.Add(&effects.Effect{Name: "rainbow", Description: "Seed", Pattern: "top=0|15|FF0000"})
+	store.Add(&effects.Effect{Name: "oldCustom", Description: "Custom", Pattern: "top=0|15|00FF00"})
+
+	result, _ := NewImportEffectsTool(store).Execute(context.Background(), map[string]interface{}{
+		"bundle": map[string]interface{}{
+			"format":  effects.BundleFormat,
+			"version": float64(effects.BundleVersion),
+			"effects": []interface{}{
+				map[string]interface{}{"name": "newCustom", "description": "New", "pattern": "top=0|15|0000FF"},
+			},
+		},
+		"mode": "replace",
+	})
+	if result.IsError {
+		t.Fatalf("importEffects failed: %v", result.Content)
+	}
+	if _, exists := store.Get("oldCustom"); exists {
+		t.Error("expected oldCustom removed")
+	}
+	if _, exists := store.Get("rainbow"); !exists {
+		t.Error("expected the seed effect kept")
+	}
+	if _, exists := store.Get("newCustom"); !exists {
+		t.Error("expected newCustom imported")
+	}
+}
+
+func TestImportEffectsTool_Errors(t *testing.T) {
+	tool := NewImportEffectsTool(effects.NewStore(t.TempDir() + "/effects.json"))
+	for name, args := range map[string]map[string]interface{}{
+		"missing bundle": {},
+		"not a bundle":   {"bundle": `{"name": "x"}`},
+		"bad name":       {"bundle": `[{"name": "bad name", "pattern": "x=1"}]`},
+		"bad mode":       {"bundle": `[{"name": "ok", "pattern": "x=1"}]`, "mode": "append"},
+		"bad strategy":   {"bundle": `[{"name": "ok", "pattern": "x=1"}]`, "onConflict": "ignore"},
+		"bad dryRun":     {"bundle": `[{"name": "ok", "pattern": "x=1"}]`, "dryRun": "yes"},
+		"bad pattern":    {"bundle": `[{"name": "ok", "pattern": "top=15|1|ff0000"}]`},
+	} {
+		result, _ := tool.Execute(context.Background(), args)
+		if !result.IsError {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	result, _ := tool.Execute(context.Background(), map[string]interface{}{"bundle": `[{"name": "ok", "pattern": "top=15|1|ff0000"}]`})
+	toolErr, failed := ErrorOf(result)
+	if !failed || toolErr.Code != CodeInvalidArgument || !strings.Contains(toolErr.Message, "'ok' (invalid pattern") {
+		t.Errorf("expected the invalid pattern reported, got %+v", toolErr)
+	}
+}
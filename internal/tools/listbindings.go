@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+)
+
+// ListBindingsTool implements the listBindings MCP tool
+type ListBindingsTool struct {
+	bindings *integrations.Bindings
+}
+
+// NewListBindingsTool creates a new listBindings tool instance
+func NewListBindingsTool(bindings *integrations.Bindings) *ListBindingsTool {
+	return &ListBindingsTool{
+		bindings: bindings,
+	}
+}
+
+// Definition returns the MCP tool definition for listBindings
+func (t *ListBindingsTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "listBindings",
+		Description: "List poller bindings as JSON, with each binding's rules and the time, extracted value, matched rule and error of its last poll.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+		},
+	}
+}
+
+// Execute runs the listBindings tool
+func (t *ListBindingsTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	bindingsJSON, err := json.MarshalIndent(t.bindings.List(), "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize bindings: "+err.Error()), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: string(bindingsJSON),
+			},
+		},
+		IsError: false,
+	}, nil
+}
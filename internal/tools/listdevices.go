@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/devices"
+)
+
+// ListDevicesTool implements the listDevices MCP tool
+type ListDevicesTool struct {
+	registry *devices.Registry
+}
+
+// deviceEntry is a registered device as returned by listDevices
+type deviceEntry struct {
+	devices.Device
+	Active bool `json:"active"` // whether this server controls it
+}
+
+// NewListDevicesTool creates a new listDevices tool instance
+func NewListDevicesTool(registry *devices.Registry) *ListDevicesTool {
+	return &ListDevicesTool{
+		registry: registry,
+	}
+}
+
+// Definition returns the MCP tool definition for listDevices
+func (t *ListDevicesTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "listDevices",
+		Description: "List known UFOs as JSON with their address, nickname, location and notes. The UFO this server controls is marked active and is always listed.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+		},
+	}
+}
+
+// Execute runs the listDevices tool
+func (t *ListDevicesTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	active := os.Getenv("UFO_IP")
+
+	entries := []deviceEntry{}
+	listedActive := false
+	for _, device := range t.registry.List() {
+		isActive := device.Address == active
+		listedActive = listedActive || isActive
+		entries = append(entries, deviceEntry{Device: device, Active: isActive})
+	}
+	if !listedActive && active != "" {
+		entries = append([]deviceEntry{{Device: devices.Device{Address: active}, Active: true}}, entries...)
+	}
+
+	devicesJSON, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize devices: "+err.Error()), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: string(devicesJSON),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+)
+
+// ListEffectRevisionsTool implements the listEffectRevisions MCP tool
+type ListEffectRevisionsTool struct {
+	store *effects.Store
+}
+
+// NewListEffectRevisionsTool creates a new listEffectRevisions tool instance
+func NewListEffectRevisionsTool(store *effects.Store) *ListEffectRevisionsTool {
+	return &ListEffectRevisionsTool{
+		store: store,
+	}
+}
+
+// Definition returns the MCP tool definition for listEffectRevisions
+func (t *ListEffectRevisionsTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "listEffectRevisions",
+		Description: fmt.Sprintf("List the earlier versions of a stored effect, kept each time updateEffect, deleteEffect, importEffects or rollbackEffect replaced or removed it (the last %d per effect). Without a name, lists the effects that have earlier versions, including deleted ones. Use rollbackEffect to bring a version back.", effects.DefaultMaxRevisions),
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the effect (optional; omit to list the effects with earlier versions)",
+				},
+			},
+		},
+	}
+}
+
+// Execute runs the listEffectRevisions tool
+func (t *ListEffectRevisionsTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	value, exists := arguments["name"]
+	if !exists {
+		return t.listEffects(), nil
+	}
+	name, ok := value.(string)
+	if !ok || name == "" {
+		return invalidArgument("name", "'name' must be a non-empty string when provided"), nil
+	}
+
+	revisions, current := t.store.Revisions(name)
+	if len(revisions) == 0 {
+		if current == 0 {
+			return toolError(CodeNotFound, fmt.Sprintf("Effect '%s' not found and has no earlier versions", name)), nil
+		}
+		return revisionResult(fmt.Sprintf("Effect '%s' is at version %d and has no earlier versions.", name, current)), nil
+	}
+
+	message := fmt.Sprintf("🕘 %d earlier version(s) of '%s'\n", len(revisions), name)
+	if current == 0 {
+		message += "• Deleted; roll back to a version to restore it\n"
+	} else {
+		message += fmt.Sprintf("• v%d: current\n", current)
+	}
+	for i := len(revisions) - 1; i >= 0; i-- {
+		revision := revisions[i]
+		message += fmt.Sprintf("• v%d: %s %s: %s (%s)\n", revision.Version, revision.Reason, format.Time(revision.ReplacedAt), revision.Effect.Description, describeRevisionPattern(revision.Effect))
+	}
+
+	revisionsJSON, err := json.MarshalIndent(map[string]interface{}{
+		"name":           name,
+		"currentVersion": current,
+		"revisions":      revisions,
+	}, "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize revisions: "+err.Error()), nil
+	}
+	message += "\nFull JSON:\n" + string(revisionsJSON)
+	return revisionResult(message), nil
+}
+
+// listEffects lists the effects with earlier versions
+func (t *ListEffectRevisionsTool) listEffects() *mcp.CallToolResult {
+	names := t.store.RevisedEffects()
+	if len(names) == 0 {
+		return revisionResult("No effect has earlier versions yet.")
+	}
+
+	message := fmt.Sprintf("🕘 %d effect(s) with earlier versions\n", len(names))
+	for _, name := range names {
+		revisions, current := t.store.Revisions(name)
+		state := fmt.Sprintf("at v%d", current)
+		if current == 0 {
+			state = "deleted"
+		}
+		message += fmt.Sprintf("• %s: %d earlier version(s), %s\n", name, len(revisions), state)
+	}
+	return revisionResult(message)
+}
+
+// describeRevisionPattern shows what an earlier version of an effect drew:
+// its pattern, or how many steps it cycled through
+func describeRevisionPattern(effect effects.Effect) string {
+	if len(effect.Steps) > 0 {
+		patterns := make([]string, len(effect.Steps))
+		for i, step := range effect.Steps {
+			patterns[i] = step.Pattern
+		}
+		return fmt.Sprintf("%d steps: %s", len(effect.Steps), strings.Join(patterns, " → "))
+	}
+	return effect.Pattern
+}
+
+// revisionResult builds a successful revision tool result
+func revisionResult(message string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}
+}
@@ -4,11 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/starspace46/ufo-mcp-go/internal/effects"
 )
 
+// Page sizes of listEffects, which keep large effect files from filling the
+// client's context
+const (
+	defaultListEffectsLimit = 20
+	maxListEffectsLimit     = 100
+)
+
 // ListEffectsTool implements the listEffects MCP tool
 type ListEffectsTool struct {
 	store *effects.Store
@@ -25,45 +34,130 @@ func NewListEffectsTool(store *effects.Store) *ListEffectsTool {
 func (t *ListEffectsTool) Definition() mcp.Tool {
 	return mcp.Tool{
 		Name:        "listEffects",
-		Description: "List all available lighting effects, including both built-in seed effects and user-defined custom effects. Returns an array of effect objects with name, description, pattern, and duration.",
+		Description: fmt.Sprintf("List the available lighting effects, built-in and custom, ordered by name. Filter by tag, category or search text and page through the results with offset and limit (%d per page by default). The summary names every category and tag with its count so you can narrow the list. Returns a summary followed by JSON of the page.", defaultListEffectsLimit),
 		InputSchema: mcp.ToolInputSchema{
-			Type:       "object",
-			Properties: map[string]interface{}{},
-			Required:   []string{},
+			Type: "object",
+			Properties: map[string]interface{}{
+				"tag": map[string]interface{}{
+					"type":        "string",
+					"description": "Only effects with this tag (optional)",
+					"examples":    []string{"red", "incident", "calm"},
+				},
+				"category": map[string]interface{}{
+					"type":        "string",
+					"description": "Only effects in this category (optional)",
+					"examples":    []string{"alerts", "ambient", "status"},
+				},
+				"search": map[string]interface{}{
+					"type":        "string",
+					"description": "Only effects with this text in the name, description, category or tags, ignoring case (optional)",
+				},
+				"offset": map[string]interface{}{
+					"type":        "number",
+					"description": "Matching effects to skip, for the next page (optional, default 0)",
+					"minimum":     0,
+				},
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Most effects to return (1-%d, default %d)", maxListEffectsLimit, defaultListEffectsLimit),
+					"minimum":     1,
+					"maximum":     maxListEffectsLimit,
+				},
+			},
+			Required: []string{},
 		},
 	}
 }
 
 // Execute runs the listEffects tool
 func (t *ListEffectsTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-	// Get all effects from the store
-	effectsList := t.store.List()
+	filter := effects.Filter{Limit: defaultListEffectsLimit}
+	for name, target := range map[string]*string{"tag": &filter.Tag, "category": &filter.Category, "search": &filter.Search} {
+		value, exists := arguments[name]
+		if !exists {
+			continue
+		}
+		text, ok := value.(string)
+		if !ok {
+			return invalidArgument(name, fmt.Sprintf("'%s' must be a string", name)), nil
+		}
+		*target = strings.TrimSpace(text)
+	}
+	if value, exists := arguments["offset"]; exists {
+		offset, ok := wholeNumber(value)
+		if !ok || offset < 0 {
+			return invalidArgument("offset", "'offset' must be a whole number of at least 0"), nil
+		}
+		filter.Offset = offset
+	}
+	if value, exists := arguments["limit"]; exists {
+		limit, ok := wholeNumber(value)
+		if !ok || limit < 1 || limit > maxListEffectsLimit {
+			return invalidArgument("limit", fmt.Sprintf("'limit' must be a whole number from 1 to %d", maxListEffectsLimit)), nil
+		}
+		filter.Limit = limit
+	}
+
+	effectsList, total := t.store.Find(filter)
 
 	// Convert to JSON for display
 	effectsJSON, err := json.MarshalIndent(effectsList, "", "  ")
 	if err != nil {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Failed to serialize effects: " + err.Error(),
-				},
-			},
-			IsError: true,
-		}, nil
+		return toolError(CodeInternal, "Failed to serialize effects: "+err.Error()), nil
 	}
 
 	// Build a summary message
 	message := "Available UFO Lighting Effects:\n"
 	message += "================================\n\n"
-	
+	categories, tags := t.store.Labels()
+	if len(categories) > 0 {
+		message += "Categories: " + formatLabelCounts(categories) + "\n"
+	}
+	if len(tags) > 0 {
+		message += "Tags: " + formatLabelCounts(tags) + "\n"
+	}
+	if len(categories) > 0 || len(tags) > 0 {
+		message += "\n"
+	}
+
 	for _, effect := range effectsList {
 		message += fmt.Sprintf("• %s - %s\n", effect.Name, effect.Description)
 		message += fmt.Sprintf("  Duration: %d seconds\n", effect.Duration)
-		message += fmt.Sprintf("  Pattern: %s\n\n", effect.Pattern)
+		if effect.Category != "" {
+			message += fmt.Sprintf("  Category: %s\n", effect.Category)
+		}
+		if len(effect.Tags) > 0 {
+			message += fmt.Sprintf("  Tags: %s\n", strings.Join(effect.Tags, ", "))
+		}
+		if len(effect.Params) > 0 {
+			message += fmt.Sprintf("  Parameters: %s\n", formatEffectParams(effect.Params))
+		}
+		if effect.Composite != nil {
+			layers := make([]string, len(effect.Composite.Layers))
+			for i, layer := range effect.Composite.Layers {
+				layers[i] = layer.Effect
+			}
+			message += fmt.Sprintf("  Composite: %s\n", strings.Join(layers, " + "))
+		}
+		if len(effect.Steps) > 0 {
+			message += fmt.Sprintf("  Steps: %d frames, cycled until stopped\n\n", len(effect.Steps))
+		} else {
+			message += fmt.Sprintf("  Pattern: %s\n\n", effect.Pattern)
+		}
 	}
 	
-	message += fmt.Sprintf("Total effects: %d\n\n", len(effectsList))
+	message += fmt.Sprintf("Total effects: %d\n", total)
+	switch {
+	case len(effectsList) == 0 && total > 0:
+		message += fmt.Sprintf("No effects past offset %d\n", filter.Offset)
+	case len(effectsList) < total:
+		message += fmt.Sprintf("Showing %d-%d", filter.Offset+1, filter.Offset+len(effectsList))
+		if next := filter.Offset + len(effectsList); next < total {
+			message += fmt.Sprintf("; use offset %d for the next page", next)
+		}
+		message += "\n"
+	}
+	message += "\n"
 	message += "Full JSON:\n" + string(effectsJSON)
 
 	return &mcp.CallToolResult{
@@ -75,4 +169,20 @@ func (t *ListEffectsTool) Execute(ctx context.Context, arguments map[string]inte
 		},
 		IsError: false,
 	}, nil
-}
\ No newline at end of file
+}
+
+
+// formatLabelCounts lists category or tag names with their effect counts,
+// ordered by name
+func formatLabelCounts(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%d)", name, counts[name])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -95,4 +95,57 @@ func TestListEffectsTool_Execute(t *testing.T) {
 	if !strings.Contains(textContent.Text, "Total effects: 7") {
 		t.Error("Expected total of 7 effects")
 	}
-}
\ No newline at end of file
+}
+
+func TestListEffectsTool_Filters(t *testing.T) {
+	store := effects.NewStore(t.TempDir() + "/effects.json")
+	for _, effect := range []*effects.Effect{
+		{Name: "redAlert", Description: "Flashing red", Pattern: "top=0|15|FF0000", Category: "alerts", Tags: []string{"red", "incident"}},
+		{Name: "amberWarning", Description: "Amber pulse", Pattern: "top=0|15|FFBF00", Category: "alerts", Tags: []string{"amber"}},
+		{Name: "calmBlue", Description: "Slow blue whirl", Pattern: "top=0|15|0000FF", Category: "ambient", Tags: []string{"blue"}},
+	} {
+		if err := store.Add(effect); err != nil {
+			t.Fatalf("adding %s: %v", effect.Name, err)
+		}
+	}
+	tool := NewListEffectsTool(store)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"category": "alerts", "limit": float64(1)})
+	if err != nil || result.IsError {
+		t.Fatalf("Execute failed: %v %v", err, result)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "amberWarning") || strings.Contains(text, "• redAlert") || strings.Contains(text, "• calmBlue") {
+		t.Errorf("expected only the first alert, got:\n%s", text)
+	}
+	if !strings.Contains(text, "Total effects: 2") || !strings.Contains(text, "Showing 1-1; use offset 1 for the next page") {
+		t.Errorf("expected paging details, got:\n%s", text)
+	}
+	if !strings.Contains(text, "Categories: alerts (2), ambient (1)") {
+		t.Errorf("expected category counts, got:\n%s", text)
+	}
+
+	result, _ = tool.Execute(context.Background(), map[string]interface{}{"tag": "blue"})
+	text = result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "• calmBlue") || strings.Contains(text, "• redAlert") {
+		t.Errorf("expected only calmBlue, got:\n%s", text)
+	}
+
+	result, _ = tool.Execute(context.Background(), map[string]interface{}{"search": "PULSE"})
+	text = result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "• amberWarning") || !strings.Contains(text, "Total effects: 1") {
+		t.Errorf("expected a case-insensitive search, got:\n%s", text)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"limit": float64(0)},
+		{"limit": float64(101)},
+		{"offset": float64(-1)},
+		{"tag": 5},
+	} {
+		result, _ = tool.Execute(context.Background(), args)
+		if !result.IsError {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+)
+
+// ListIntegrationsTool implements the listIntegrations MCP tool
+type ListIntegrationsTool struct {
+	registry *integrations.Registry
+}
+
+// NewListIntegrationsTool creates a new listIntegrations tool instance
+func NewListIntegrationsTool(registry *integrations.Registry) *ListIntegrationsTool {
+	return &ListIntegrationsTool{
+		registry: registry,
+	}
+}
+
+// Definition returns the MCP tool definition for listIntegrations
+func (t *ListIntegrationsTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "listIntegrations",
+		Description: "List configured integrations as JSON with their health: whether each is enabled, its last poll or webhook delivery, its last error, how many routes, services, jobs or bindings it has and how many alerts it currently shows on the UFO.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+		},
+	}
+}
+
+// Execute runs the listIntegrations tool
+func (t *ListIntegrationsTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	statusJSON, err := json.MarshalIndent(t.registry.Statuses(), "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize integrations: "+err.Error()), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: string(statusJSON),
+			},
+		},
+		IsError: false,
+	}, nil
+}
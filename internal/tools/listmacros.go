@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/macros"
+)
+
+// ListMacrosTool implements the listMacros MCP tool
+type ListMacrosTool struct {
+	store *macros.Store
+}
+
+// NewListMacrosTool creates a new listMacros tool instance
+func NewListMacrosTool(store *macros.Store) *ListMacrosTool {
+	return &ListMacrosTool{
+		store: store,
+	}
+}
+
+// Definition returns the MCP tool definition for listMacros
+func (t *ListMacrosTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "listMacros",
+		Description: "List macros saved with defineMacro as JSON with their name, description and steps.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+		},
+	}
+}
+
+// Execute runs the listMacros tool
+func (t *ListMacrosTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	macrosJSON, err := json.MarshalIndent(t.store.List(), "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize macros: "+err.Error()), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: string(macrosJSON),
+			},
+		},
+		IsError: false,
+	}, nil
+}
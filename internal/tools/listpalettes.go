@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/palettes"
+)
+
+// ListPalettesTool implements the listPalettes MCP tool
+type ListPalettesTool struct {
+	store *palettes.Store
+}
+
+// NewListPalettesTool creates a new listPalettes tool instance
+func NewListPalettesTool(store *palettes.Store) *ListPalettesTool {
+	return &ListPalettesTool{
+		store: store,
+	}
+}
+
+// Definition returns the MCP tool definition for listPalettes
+func (t *ListPalettesTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "listPalettes",
+		Description: "List saved color palettes as JSON with their name, hex colors and description.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+		},
+	}
+}
+
+// Execute runs the listPalettes tool
+func (t *ListPalettesTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	palettesJSON, err := json.MarshalIndent(t.store.List(), "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize palettes: "+err.Error()), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: string(palettesJSON),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+	"github.com/starspace46/ufo-mcp-go/internal/scenes"
+)
+
+// ListScenesTool implements the listScenes MCP tool
+type ListScenesTool struct {
+	store *scenes.Store
+}
+
+// NewListScenesTool creates a new listScenes tool instance
+func NewListScenesTool(store *scenes.Store) *ListScenesTool {
+	return &ListScenesTool{
+		store: store,
+	}
+}
+
+// Definition returns the MCP tool definition for listScenes
+func (t *ListScenesTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "listScenes",
+		Description: "List the scenes saved with saveScene, with their description, when they were saved and a summary of their lighting, followed by the full JSON.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+		},
+	}
+}
+
+// Execute runs the listScenes tool
+func (t *ListScenesTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	list := t.store.List()
+	if len(list) == 0 {
+		return sceneResult("No scenes saved yet. Use saveScene to save what the UFO shows."), nil
+	}
+
+	message := fmt.Sprintf("🎬 %d scene(s)\n", len(list))
+	for _, scene := range list {
+		message += fmt.Sprintf("\n%s, saved %s\n", scene.Name, format.Time(scene.SavedAt))
+		if scene.Description != "" {
+			message += scene.Description + "\n"
+		}
+		for _, line := range describeLighting(scene.Lighting) {
+			message += "• " + line + "\n"
+		}
+	}
+
+	scenesJSON, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize scenes: "+err.Error()), nil
+	}
+	message += "\nFull JSON:\n" + string(scenesJSON)
+	return sceneResult(message), nil
+}
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+const (
+	// defaultLogoPeriodMs is the length of one logo animation cycle when
+	// not given
+	defaultLogoPeriodMs = 1000
+	// minLogoPeriodMs is the shortest cycle; blinking faster would crowd
+	// out other writes at the default write rate
+	minLogoPeriodMs = 200
+	// maxLogoPeriodMs is the longest cycle
+	maxLogoPeriodMs = 60000
+	// logoPulseFrameMs is the time between the frames of a pulse
+	logoPulseFrameMs = 100
+)
+
+// logoAnimation validates the animation of a configureLighting logo
+// configuration. color1 and color2 are resolved hex colors or empty.
+func logoAnimation(config map[string]interface{}, color1, color2 string) (*state.LogoAnimation, error) {
+	mode, _ := config["animation"].(string)
+	switch mode {
+	case state.LogoBlink, state.LogoPulse:
+		if color1 == "" {
+			color1 = "FFFFFF"
+		}
+		if color2 == "" {
+			color2 = "000000"
+		}
+		if mode == state.LogoPulse {
+			color2 = ""
+		}
+	case state.LogoAlternate:
+		if color1 == "" || color2 == "" {
+			return nil, fmt.Errorf("the alternate animation needs color1 and color2")
+		}
+	default:
+		return nil, fmt.Errorf("animation must be '%s', '%s', '%s' or 'none'", state.LogoBlink, state.LogoPulse, state.LogoAlternate)
+	}
+
+	periodMs := defaultLogoPeriodMs
+	if value, exists := config["periodMs"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < minLogoPeriodMs || n > maxLogoPeriodMs {
+			return nil, fmt.Errorf("periodMs must be a whole number from %d to %d", minLogoPeriodMs, maxLogoPeriodMs)
+		}
+		periodMs = n
+	}
+	return &state.LogoAnimation{Mode: mode, Color1: color1, Color2: color2, PeriodMs: periodMs}, nil
+}
+
+// logoSteps returns the frames of one cycle of a logo animation
+func logoSteps(animation state.LogoAnimation) []effects.Step {
+	if animation.Mode != state.LogoPulse {
+		half := animation.PeriodMs / 2
+		return []effects.Step{
+			{Pattern: "logo=" + animation.Color1, DurationMs: half},
+			{Pattern: "logo=" + animation.Color2, DurationMs: animation.PeriodMs - half},
+		}
+	}
+
+	// Fade from full color down to off and back along a cosine, so the
+	// cycle starts where the first frame shows the requested color
+	frames := animation.PeriodMs / logoPulseFrameMs
+	steps := make([]effects.Step, frames)
+	for i := range steps {
+		level := (1 + math.Cos(2*math.Pi*float64(i)/float64(frames))) / 2
+		steps[i] = effects.Step{
+			Pattern:    "logo=" + device.MixColor("000000", animation.Color1, level),
+			DurationMs: logoPulseFrameMs,
+		}
+	}
+	steps[frames-1].DurationMs += animation.PeriodMs - frames*logoPulseFrameMs
+	return steps
+}
+
+// describeLogoAnimation describes a logo animation for tool replies
+func describeLogoAnimation(animation state.LogoAnimation) string {
+	period := format.Millis(int64(animation.PeriodMs))
+	switch animation.Mode {
+	case state.LogoPulse:
+		return fmt.Sprintf("pulsing #%s every %s", animation.Color1, period)
+	case state.LogoBlink:
+		if animation.Color2 == "000000" {
+			return fmt.Sprintf("blinking #%s every %s", animation.Color1, period)
+		}
+	}
+	return fmt.Sprintf("alternating #%s and #%s every %s", animation.Color1, animation.Color2, period)
+}
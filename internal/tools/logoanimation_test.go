@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogoSteps(t *testing.T) {
+	blink := logoSteps(state.LogoAnimation{Mode: state.LogoBlink, Color1: "FF0000", Color2: "000000", PeriodMs: 1001})
+	require.Len(t, blink, 2)
+	assert.Equal(t, effects.Step{Pattern: "logo=FF0000", DurationMs: 500}, blink[0])
+	assert.Equal(t, effects.Step{Pattern: "logo=000000", DurationMs: 501}, blink[1])
+
+	alternate := logoSteps(state.LogoAnimation{Mode: state.LogoAlternate, Color1: "FF0000", Color2: "0000FF", PeriodMs: 400})
+	assert.Equal(t, "logo=0000FF", alternate[1].Pattern)
+
+	pulse := logoSteps(state.LogoAnimation{Mode: state.LogoPulse, Color1: "FF0000", PeriodMs: 1050})
+	require.Len(t, pulse, 10)
+	assert.Equal(t, "logo=ff0000", pulse[0].Pattern)
+	assert.Equal(t, "logo=000000", pulse[5].Pattern)
+	total := 0
+	for _, step := range pulse {
+		total += step.DurationMs
+	}
+	assert.Equal(t, 1050, total)
+}
+
+func TestLogoAnimation_Validation(t *testing.T) {
+	animation, err := logoAnimation(map[string]interface{}{"animation": "blink"}, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, state.LogoAnimation{Mode: state.LogoBlink, Color1: "FFFFFF", Color2: "000000", PeriodMs: defaultLogoPeriodMs}, *animation)
+
+	for name, config := range map[string]map[string]interface{}{
+		"unknown mode":      {"animation": "strobe"},
+		"period too short":  {"animation": "pulse", "periodMs": float64(50)},
+		"fractional period": {"animation": "pulse", "periodMs": 500.5},
+	} {
+		_, err := logoAnimation(config, "FF0000", "")
+		assert.Error(t, err, name)
+	}
+	_, err = logoAnimation(map[string]interface{}{"animation": "alternate"}, "FF0000", "")
+	assert.Error(t, err, "alternate needs two colors")
+}
+
+func TestConfigureLightingTool_AnimatedLogo(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	engine := effects.NewEngine(device.NewSimulatedClient(device.NewSimulator()))
+	defer engine.StopLogo()
+	tool := NewConfigureLightingTool(device.NewSimulatedClient(device.NewSimulator()), broadcaster, stateManager, engine, nil)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"logo": map[string]interface{}{"animation": "alternate", "color1": "red", "color2": "blue", "periodMs": float64(400)},
+	})
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	require.False(t, result.IsError, text)
+	assert.Contains(t, text, "Logo: alternating #ff0000 and #0000ff every 400 ms")
+	snapshot := stateManager.Snapshot()
+	require.NotNil(t, snapshot.LogoAnimation)
+	assert.Equal(t, state.LogoAlternate, snapshot.LogoAnimation.Mode)
+	assert.True(t, snapshot.LogoOn)
+
+	// A static logo stops the animation
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"logo": map[string]interface{}{"state": "on", "color1": "green"},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Nil(t, stateManager.Snapshot().LogoAnimation)
+
+	for name, logo := range map[string]map[string]interface{}{
+		"animated but off": {"state": "off", "animation": "blink"},
+		"bad color":        {"animation": "pulse", "color1": "notacolor"},
+	} {
+		result, err = tool.Execute(context.Background(), map[string]interface{}{"logo": logo})
+		require.NoError(t, err)
+		assert.True(t, result.IsError, name)
+	}
+}
+
+// logoCounter passes queries to a client, counting those that draw the logo
+type logoCounter struct {
+	client *device.Client
+	logo   atomic.Int32
+}
+
+func (c *logoCounter) SendRawQuery(ctx context.Context, query string) (string, error) {
+	if strings.HasPrefix(query, "logo=") {
+		c.logo.Add(1)
+	}
+	return c.client.SendRawQuery(ctx, query)
+}
+
+func TestStopAllEffectsTool_StopsLogoAnimation(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	client := device.NewSimulatedClient(device.NewSimulator())
+	counter := &logoCounter{client: client}
+	engine := effects.NewEngine(counter)
+	lighting := NewConfigureLightingTool(client, broadcaster, stateManager, engine, nil)
+
+	result, _ := lighting.Execute(context.Background(), map[string]interface{}{
+		"logo": map[string]interface{}{"animation": "blink", "periodMs": float64(200)},
+	})
+	require.False(t, result.IsError)
+	time.Sleep(150 * time.Millisecond)
+	require.Greater(t, counter.logo.Load(), int32(0), "the animation draws the logo")
+
+	require.NoError(t, NewStopAllEffectsTool(broadcaster, stateManager, engine).clear(context.Background()))
+	assert.Nil(t, stateManager.Snapshot().LogoAnimation)
+
+	// Nothing redraws the logo after it was cleared
+	drawn := counter.logo.Load()
+	time.Sleep(250 * time.Millisecond)
+	assert.Equal(t, drawn, counter.logo.Load(), "logo frames after clearing")
+}
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// PauseEffectTool implements the pauseEffect MCP tool
+type PauseEffectTool struct {
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+	engine       *effects.Engine
+}
+
+// NewPauseEffectTool creates a new pauseEffect tool instance
+func NewPauseEffectTool(broadcaster *events.Broadcaster, stateManager *state.Manager, engine *effects.Engine) *PauseEffectTool {
+	return &PauseEffectTool{
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		engine:       engine,
+	}
+}
+
+// Definition returns the MCP tool definition for pauseEffect
+func (t *PauseEffectTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "pauseEffect",
+		Description: "Pause the current effect. Its remaining duration stops counting down and multi-step animations freeze on the current frame until resumeEffect is called.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+		},
+	}
+}
+
+// Execute runs the pauseEffect tool
+func (t *PauseEffectTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	item, err := t.stateManager.PauseEffect()
+	if err != nil {
+		return toolError(CodeConflict, fmt.Sprintf("%v", err)), nil
+	}
+
+	// Freeze multi-step animations on their current frame
+	if t.engine.Running() == item.Name {
+		t.engine.Stop()
+	}
+
+	paused := events.EffectPaused{
+		Effect:     item.Name,
+		InstanceID: item.InstanceID(),
+		StackDepth: t.stateManager.GetEffectStackDepth(),
+	}
+	message := fmt.Sprintf("⏸️ Paused '%s'", item.Name)
+	if remaining, timed := item.Remaining(time.Now()); timed {
+		paused.RemainingMs = remaining.Milliseconds()
+		message += fmt.Sprintf(" with %s remaining", format.Duration(remaining))
+	}
+	t.broadcaster.PublishPayload(ctx, paused)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
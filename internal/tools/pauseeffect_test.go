@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseResumeEffectTools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := device.NewClient()
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	engine := effects.NewEngine(client)
+
+	pauseTool := NewPauseEffectTool(broadcaster, stateManager, engine)
+	resumeTool := NewResumeEffectTool(broadcaster, stateManager, engine)
+
+	t.Run("NothingToPause", func(t *testing.T) {
+		result, err := pauseTool.Execute(context.Background(), map[string]interface{}{})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+	})
+
+	stateManager.PushEffect("timed", "pattern", map[string]interface{}{
+		"duration":  10000,
+		"perpetual": false,
+		"startTime": time.Now().Add(-2 * time.Second),
+	})
+
+	t.Run("Pause", func(t *testing.T) {
+		result, err := pauseTool.Execute(context.Background(), map[string]interface{}{})
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Paused 'timed'")
+
+		current := stateManager.GetCurrentEffect()
+		require.NotNil(t, current)
+		assert.True(t, current.Paused())
+
+		// The countdown is frozen while paused
+		before, _ := current.Remaining(time.Now())
+		after, _ := current.Remaining(time.Now().Add(time.Minute))
+		assert.Equal(t, before, after)
+
+		// Pausing twice is an error
+		result, err = pauseTool.Execute(context.Background(), map[string]interface{}{})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		time.Sleep(50 * time.Millisecond)
+		result, err := resumeTool.Execute(context.Background(), map[string]interface{}{})
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Resumed 'timed'")
+
+		current := stateManager.GetCurrentEffect()
+		require.NotNil(t, current)
+		assert.False(t, current.Paused())
+
+		// Time spent paused is not counted as elapsed
+		elapsed := current.Elapsed(time.Now())
+		assert.Less(t, elapsed, 2050*time.Millisecond)
+		assert.GreaterOrEqual(t, elapsed, 2*time.Second)
+
+		// Resuming a running effect is an error
+		result, err = resumeTool.Execute(context.Background(), map[string]interface{}{})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+	})
+}
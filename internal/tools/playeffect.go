@@ -3,30 +3,46 @@ package tools
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/starspace46/ufo-mcp-go/internal/device"
 	"github.com/starspace46/ufo-mcp-go/internal/effects"
 	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
 	"github.com/starspace46/ufo-mcp-go/internal/state"
 )
 
+// pausePollInterval is how often a paused effect's timer checks for resumption
+const pausePollInterval = 250 * time.Millisecond
+
+// progressInterval is how often a running timed effect publishes its
+// countdown
+const progressInterval = time.Second
+
 // PlayEffectTool implements the playEffect MCP tool
 type PlayEffectTool struct {
 	client       *device.Client
 	broadcaster  *events.Broadcaster
 	store        *effects.Store
 	stateManager *state.Manager
+	engine       *effects.Engine
+	restores     *Restores
 }
 
 // NewPlayEffectTool creates a new playEffect tool instance
-func NewPlayEffectTool(client *device.Client, broadcaster *events.Broadcaster, store *effects.Store, stateManager *state.Manager) *PlayEffectTool {
+func NewPlayEffectTool(client *device.Client, broadcaster *events.Broadcaster, store *effects.Store, stateManager *state.Manager, engine *effects.Engine, restores *Restores) *PlayEffectTool {
 	return &PlayEffectTool{
 		client:       client,
 		broadcaster:  broadcaster,
 		store:        store,
 		stateManager: stateManager,
+		engine:       engine,
+		restores:     restores,
 	}
 }
 
@@ -34,7 +50,7 @@ func NewPlayEffectTool(client *device.Client, broadcaster *events.Broadcaster, s
 func (t *PlayEffectTool) Definition() mcp.Tool {
 	return mcp.Tool{
 		Name:        "playEffect",
-		Description: "Play a lighting effect by name. Effects run for their configured duration or until stopped. Returns immediately while the effect plays.",
+		Description: "Play a lighting effect by name. Effects run for their configured duration or until stopped. Returns immediately while the effect plays. Template effects declare parameters (see listEffects) whose values are passed in 'params'. With 'background' the effect goes beneath the one showing, becoming what the UFO returns to, without interrupting it.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -46,6 +62,18 @@ func (t *PlayEffectTool) Definition() mcp.Tool {
 					"type":        "number",
 					"description": "Override duration in milliseconds (optional, uses effect's default if not specified)",
 				},
+				"params": map[string]interface{}{
+					"type":        "object",
+					"description": "Values for the effect's parameters, substituted for {name} in its pattern (optional, parameters with defaults can be omitted)",
+					"additionalProperties": map[string]interface{}{
+						"type": []string{"string", "number"},
+					},
+					"examples": []interface{}{map[string]interface{}{"color": "FF0000", "speed": 200}},
+				},
+				"background": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Insert the effect beneath the current top of the stack instead of showing it now, e.g. to change the ambient state during an alert (optional, default false)",
+				},
 			},
 			Required: []string{"name"},
 		},
@@ -57,29 +85,13 @@ func (t *PlayEffectTool) Execute(ctx context.Context, arguments map[string]inter
 	// Extract effect name
 	name, ok := arguments["name"].(string)
 	if !ok || name == "" {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'name' parameter is required and must be a non-empty string",
-				},
-			},
-			IsError: true,
-		}, nil
+		return missingArgument("name", "'name' parameter is required and must be a non-empty string"), nil
 	}
 
 	// Get the effect from store
 	effect, exists := t.store.Get(name)
 	if !exists {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error: Effect '%s' not found. Use listEffects to see available effects.", name),
-				},
-			},
-			IsError: true,
-		}, nil
+		return effectNotFound("name", name), nil
 	}
 
 	// Check for duration override
@@ -91,111 +103,149 @@ func (t *PlayEffectTool) Execute(ctx context.Context, arguments map[string]inter
 		case int:
 			duration = v
 		default:
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Error: 'duration' must be a number",
-					},
-				},
-				IsError: true,
-			}, nil
+			return invalidArgument("duration", "'duration' must be a number"), nil
 		}
 
 		// Validate duration
 		if duration < 0 || duration > 3600000 {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Error: 'duration' must be between 0 and 3600000 milliseconds (1 hour)",
-					},
-				},
-				IsError: true,
-			}, nil
+			return invalidArgument("duration", "'duration' must be between 0 and 3600000 milliseconds (1 hour)"), nil
 		}
 	}
 
-	// Send the effect pattern to the UFO
-	query := effect.Pattern
-	if _, err := t.client.SendRawQuery(ctx, query); err != nil {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error: Failed to send effect to UFO: %v", err),
-				},
-			},
-			IsError: true,
-		}, nil
+	// Fill in template parameters
+	var values map[string]string
+	if paramsVal, hasParams := arguments["params"]; hasParams {
+		var err error
+		if values, err = paramValues(paramsVal); err != nil {
+			return invalidArgument("params", err.Error()), nil
+		}
+	}
+	effect, err := effect.Render(values)
+	if err != nil {
+		return invalidArgument("params", err.Error()), nil
+	}
+
+	background := false
+	if value, exists := arguments["background"]; exists {
+		b, ok := value.(bool)
+		if !ok {
+			return invalidArgument("background", "'background' must be a boolean"), nil
+		}
+		background = b
+	}
+
+	if full := stackFull(t.stateManager); full != nil {
+		return full, nil
+	}
+
+	// A background effect goes beneath whatever is showing; on an empty
+	// stack it simply plays
+	var covering *state.EffectStackItem
+	if background {
+		covering = t.stateManager.GetCurrentEffect()
+	}
+
+	// Send the effect to the UFO, animating multi-step effects, unless a
+	// raised alert holds the UFO; the effect then waits beneath it
+	alert := activeAlert(t.stateManager)
+	if alert != nil {
+		covering = nil
+	}
+	var warning string
+	if alert == nil && covering == nil {
+		reply, err := t.engine.ApplyWithReply(ctx, name, effect.Pattern, effect.Steps)
+		if err != nil {
+			return toolError(CodeDeviceError, fmt.Sprintf("Failed to send effect to UFO: %v", err)), nil
+		}
+
+		// The firmware answers 200 even when it rejects a query, so look at
+		// what it said
+		if warning, err = device.CheckReply(reply); err != nil {
+			t.engine.Stop()
+			if t.stateManager.GetCurrentEffect() != nil {
+				if restoreErr := restoreTop(ctx, t.engine, t.broadcaster, t.stateManager); restoreErr != nil {
+					slog.WarnContext(ctx, "Failed to restore effect after UFO error", "error", restoreErr)
+				}
+			}
+			return toolError(CodeDeviceError, fmt.Sprintf("Effect '%s' was not shown: %v\n\nPattern sent: %s", name, err, effect.FirstPattern())), nil
+		}
 	}
 
 	// Push effect onto stack
 	effectContext := map[string]interface{}{
-		"duration":  duration,
-		"perpetual": effect.Perpetual,
-		"startTime": time.Now(),
+		"instanceId": state.NewInstanceID(),
+		"duration":   duration,
+		"perpetual":  effect.Perpetual,
+		"startTime":  time.Now(),
+	}
+	if len(effect.Steps) > 0 {
+		effectContext["steps"] = effect.Steps
+	}
+	if len(values) > 0 {
+		effectContext["params"] = values
+	}
+	var stale bool
+	if covering != nil {
+		_, stale = t.stateManager.InsertEffectBelowTop(name, effect.FirstPattern(), effectContext)
+	} else {
+		_, stale = t.stateManager.PushEffect(name, effect.FirstPattern(), effectContext)
 	}
-	t.stateManager.PushEffect(name, effect.Pattern, effectContext)
+	restoreStale(ctx, stale, t.engine, t.broadcaster, t.stateManager)
 
 	// Emit effect started event
-	t.broadcaster.Publish(events.Event{
-		Type: events.EventEffectStarted,
-		Data: map[string]interface{}{
-			"effect":     name,
-			"duration":   duration,
-			"pattern":    effect.Pattern,
-			"stackDepth": t.stateManager.GetEffectStackDepth(),
-		},
-	})
+	started := events.EffectStarted{
+		Effect:     name,
+		InstanceID: effectContext["instanceId"].(string),
+		Duration:   duration,
+		Pattern:    effect.FirstPattern(),
+		Steps:      len(effect.Steps),
+		Params:     values,
+		Warning:    warning,
+		StackDepth: t.stateManager.GetEffectStackDepth(),
+	}
+	if alert != nil {
+		started.BeneathAlert = alert.Name
+	}
+	if covering != nil {
+		started.Background = true
+		started.Beneath = covering.Name
+	}
+	t.broadcaster.PublishPayload(ctx, started)
 
 	// Build response message
 	message := fmt.Sprintf("✨ Effect '%s' started!\n\n", name)
+	if alert != nil {
+		message = fmt.Sprintf("⏳ Effect '%s' queued beneath alert '%s' and will show when the alert is cleared or expires. Its duration counts down meanwhile.\n\n", name, alert.Name)
+	} else if covering != nil {
+		message = fmt.Sprintf("🎚️ Effect '%s' placed in the background beneath '%s' and will show when that effect ends or is stopped. Its duration counts down meanwhile.\n\n", name, covering.Name)
+	}
+	message += fmt.Sprintf("• Instance: %s\n", effectContext["instanceId"])
 	message += fmt.Sprintf("• Description: %s\n", effect.Description)
+	if len(values) > 0 {
+		message += fmt.Sprintf("• Parameters: %s\n", formatParamValues(values))
+	}
 	if effect.Perpetual {
 		message += "• Duration: Perpetual (runs until stopped)\n"
 	} else if duration > 0 {
-		message += fmt.Sprintf("• Duration: %d ms (%.1f seconds)\n", duration, float64(duration)/1000)
-		message += fmt.Sprintf("• Will stop at: %s\n", time.Now().Add(time.Duration(duration)*time.Millisecond).Format("15:04:05"))
+		message += fmt.Sprintf("• Duration: %s\n", format.Millis(int64(duration)))
+		message += fmt.Sprintf("• Will stop at: %s\n", format.Time(time.Now().Add(time.Duration(duration)*time.Millisecond)))
 	} else {
 		message += "• Duration: Infinite (use stopEffects to stop)\n"
 	}
-	message += fmt.Sprintf("\nPattern sent: %s", effect.Pattern)
+	if alert != nil || covering != nil {
+		message += fmt.Sprintf("\nPattern queued: %s", effect.FirstPattern())
+	} else if len(effect.Steps) > 0 {
+		message += fmt.Sprintf("\nAnimating %d steps, starting with: %s", len(effect.Steps), effect.FirstPattern())
+	} else {
+		message += fmt.Sprintf("\nPattern sent: %s", effect.Pattern)
+	}
+	if warning != "" {
+		message += fmt.Sprintf("\n\n⚠️ Warning: %s. Check that the UFO shows the effect.", warning)
+	}
 
-	// Start a goroutine to handle effect completion for timed effects
+	// Schedule the restore of the effect beneath for timed effects
 	if duration > 0 && !effect.Perpetual {
-		go func() {
-			time.Sleep(time.Duration(duration) * time.Millisecond)
-			
-			// Pop the effect and get the previous one
-			previousEffect := t.stateManager.PopEffect()
-			
-			if previousEffect != nil {
-				// Resume the previous effect
-				t.client.SendRawQuery(context.Background(), previousEffect.Pattern)
-				
-				// Emit effect resumed event
-				t.broadcaster.Publish(events.Event{
-					Type: events.EventEffectResumed,
-					Data: map[string]interface{}{
-						"effect":     previousEffect.Name,
-						"stackDepth": t.stateManager.GetEffectStackDepth(),
-					},
-				})
-			} else {
-				// No previous effect, clear the UFO
-				t.client.SendRawQuery(context.Background(), "top_init=1&bottom_init=1")
-			}
-			
-			// Emit effect completed event
-			t.broadcaster.Publish(events.Event{
-				Type: events.EventEffectCompleted,
-				Data: map[string]interface{}{
-					"effect":     name,
-					"stackDepth": t.stateManager.GetEffectStackDepth(),
-				},
-			})
-		}()
+		t.restores.scheduleCompletion(ctx, effectContext["instanceId"].(string))
 	}
 
 	return &mcp.CallToolResult{
@@ -207,4 +257,41 @@ func (t *PlayEffectTool) Execute(ctx context.Context, arguments map[string]inter
 		},
 		IsError: false,
 	}, nil
-}
\ No newline at end of file
+}
+
+// paramValues converts the playEffect params argument to strings, writing
+// whole numbers without a decimal point
+func paramValues(value interface{}) (map[string]string, error) {
+	params, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'params' must be an object mapping parameter names to values")
+	}
+	values := make(map[string]string, len(params))
+	for name, raw := range params {
+		switch v := raw.(type) {
+		case string:
+			values[name] = v
+		case float64:
+			values[name] = strconv.FormatFloat(v, 'f', -1, 64)
+		case int:
+			values[name] = strconv.Itoa(v)
+		default:
+			return nil, fmt.Errorf("parameter '%s' must be a string or a number", name)
+		}
+	}
+	return values, nil
+}
+
+// formatParamValues lists parameter values as name=value, sorted by name
+func formatParamValues(values map[string]string) string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + "=" + values[name]
+	}
+	return strings.Join(parts, ", ")
+}
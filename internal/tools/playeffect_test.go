@@ -0,0 +1,276 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayEffectTool_Execute_Params(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	require.NoError(t, store.Add(&effects.Effect{
+		Name:        "pulse",
+		Description: "Pulse any color",
+		Pattern:     "top_init=1&top=0|15|{color}&top_morph={speed}|5",
+		Duration:    10000,
+		Params: []effects.Param{
+			{Name: "color"},
+			{Name: "speed", Default: "300"},
+		},
+	}))
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	client := device.NewClient()
+	engine := effects.NewEngine(client)
+	tool := NewPlayEffectTool(client, broadcaster, store, stateManager, engine, NewRestores(engine, broadcaster, stateManager))
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"name":   "pulse",
+		"params": map[string]interface{}{"color": "00FF00", "speed": float64(120)},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	assert.Equal(t, "top_init=1&top=0|15|00FF00&top_morph=120|5", query)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "color=00FF00, speed=120")
+
+	current := stateManager.GetCurrentEffect()
+	require.NotNil(t, current)
+	assert.Equal(t, "top_init=1&top=0|15|00FF00&top_morph=120|5", current.Pattern)
+
+	// A required parameter without a value is reported, not sent
+	query = ""
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"name": "pulse"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "needs a value for color")
+	assert.Empty(t, query)
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"name":   "pulse",
+		"params": map[string]interface{}{"color": true},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestPlayEffectTool_Execute_Background(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	require.NoError(t, store.Add(&effects.Effect{Name: "busy", Description: "Busy", Pattern: "top_init=1&top=0|15|FF0000", Perpetual: true}))
+	require.NoError(t, store.Add(&effects.Effect{Name: "calm", Description: "Calm", Pattern: "top_init=1&top=0|15|0000FF", Perpetual: true}))
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	client := device.NewClient()
+	engine := effects.NewEngine(client)
+	tool := NewPlayEffectTool(client, broadcaster, store, stateManager, engine, NewRestores(engine, broadcaster, stateManager))
+
+	// On an empty stack a background effect simply plays
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"name": "busy", "background": true})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	require.Len(t, queries, 1)
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"name": "calm", "background": true})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "beneath 'busy'")
+	assert.Len(t, queries, 1, "the showing effect must not be interrupted")
+
+	stack := stateManager.GetEffectStack()
+	require.Len(t, stack, 2)
+	assert.Equal(t, "calm", stack[0].Name)
+	assert.Equal(t, "busy", stack[1].Name)
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"name": "calm", "background": "yes"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestPlayEffectTool_Execute_DeviceReplies(t *testing.T) {
+	reply := "OK"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(reply))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	require.NoError(t, store.Add(&effects.Effect{Name: "red", Description: "Red", Pattern: "top_init=1&top=0|15|FF0000", Perpetual: true}))
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	client := device.NewClient()
+	engine := effects.NewEngine(client)
+	tool := NewPlayEffectTool(client, broadcaster, store, stateManager, engine, NewRestores(engine, broadcaster, stateManager))
+
+	// A known firmware error fails the call with the UFO's message
+	reply = "ERROR: invalid segment"
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"name": "red"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "ERROR: invalid segment")
+	assert.Equal(t, 0, stateManager.GetEffectStackDepth(), "a rejected effect must not be pushed")
+
+	// An unrecognized reply plays the effect with a warning
+	reply = "<html>captive portal</html>"
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"name": "red"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "⚠️ Warning: unexpected reply from UFO: <html>captive portal</html>")
+	assert.Equal(t, 1, stateManager.GetEffectStackDepth())
+}
+
+func TestPlayEffectTool_Execute_Shutdown(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	require.NoError(t, store.Add(&effects.Effect{Name: "flash", Description: "Flash", Pattern: "top_init=1&top=0|15|FF0000", Duration: 200}))
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	client := device.NewClient()
+	engine := effects.NewEngine(client)
+	tool := NewPlayEffectTool(client, broadcaster, store, stateManager, engine, NewRestores(engine, broadcaster, stateManager))
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"name": "flash"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	assert.Equal(t, 1, engine.Timers())
+
+	// Shutting down cancels the pending restore; the stack is kept for the
+	// next start and nothing more is sent
+	require.NoError(t, engine.Shutdown(context.Background()))
+	assert.Equal(t, 0, engine.Timers())
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, 1, stateManager.GetEffectStackDepth())
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"top_init=1&top=0|15|FF0000"}, queries)
+}
+
+func TestPlayEffectTool_Execute_StackFull(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	require.NoError(t, store.Add(&effects.Effect{Name: "busy", Description: "Busy", Pattern: "top_init=1&top=0|15|FF0000", Perpetual: true}))
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	require.NoError(t, stateManager.SetStackLimit(1, state.OverflowReject))
+	client := device.NewClient()
+	engine := effects.NewEngine(client)
+	tool := NewPlayEffectTool(client, broadcaster, store, stateManager, engine, NewRestores(engine, broadcaster, stateManager))
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"name": "busy"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+
+	// A refused effect is not sent to the UFO
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"name": "busy"})
+	require.NoError(t, err)
+	toolErr, failed := ErrorOf(result)
+	require.True(t, failed)
+	assert.Equal(t, CodeConflict, toolErr.Code)
+	assert.Len(t, queries, 1)
+	assert.Equal(t, 1, stateManager.GetEffectStackDepth())
+}
+
+func TestPlayEffectTool_Execute_Progress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	require.NoError(t, store.Add(&effects.Effect{Name: "flash", Description: "Flash", Pattern: "top_init=1&top=0|15|FF0000", Duration: 1500}))
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	client := device.NewClient()
+	engine := effects.NewEngine(client)
+	tool := NewPlayEffectTool(client, broadcaster, store, stateManager, engine, NewRestores(engine, broadcaster, stateManager))
+	sub := broadcaster.Subscribe("test")
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"name": "flash"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+
+	// The countdown is published each second until the effect completes
+	var remaining []int
+	timeout := time.After(3 * time.Second)
+	for completed := false; !completed; {
+		select {
+		case event := <-sub.Channel:
+			switch event.Type {
+			case events.EventProgress:
+				assert.Equal(t, "flash", event.Data["effect"])
+				assert.Equal(t, 1500, event.Data["total"])
+				assert.Equal(t, 1500, event.Data["elapsed"].(int)+event.Data["remaining"].(int))
+				remaining = append(remaining, event.Data["remaining"].(int))
+			case events.EventEffectCompleted:
+				completed = true
+			}
+		case <-timeout:
+			t.Fatal("Expected the effect to complete")
+		}
+	}
+	require.Len(t, remaining, 2)
+	assert.Greater(t, remaining[0], remaining[1])
+}
@@ -0,0 +1,277 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+)
+
+const (
+	// defaultPreviewMs is how much of a perpetual effect or a pattern is
+	// previewed when no duration is given
+	defaultPreviewMs = 3000
+	// maxPreviewMs is the longest preview
+	maxPreviewMs = 60000
+	// defaultPreviewIntervalMs is the time between previewed frames
+	defaultPreviewIntervalMs = 100
+	// minPreviewIntervalMs is the shortest time between previewed frames
+	minPreviewIntervalMs = 10
+	// maxPreviewFrames keeps previews small enough to read
+	maxPreviewFrames = 300
+)
+
+// previewColors are the colors the ASCII preview names, by letter
+var previewColors = []struct {
+	letter  byte
+	r, g, b float64
+}{
+	{'r', 255, 0, 0},
+	{'o', 255, 128, 0},
+	{'y', 255, 255, 0},
+	{'g', 0, 255, 0},
+	{'c', 0, 255, 255},
+	{'b', 0, 0, 255},
+	{'p', 128, 0, 255},
+	{'m', 255, 0, 255},
+	{'w', 255, 255, 255},
+}
+
+// PreviewEffectTool implements the previewEffect MCP tool, which shows what
+// an effect or pattern would look like without sending it to the UFO
+type PreviewEffectTool struct {
+	store *effects.Store
+}
+
+// NewPreviewEffectTool creates a new previewEffect tool instance
+func NewPreviewEffectTool(store *effects.Store) *PreviewEffectTool {
+	return &PreviewEffectTool{
+		store: store,
+	}
+}
+
+// Definition returns the MCP tool definition for previewEffect
+func (t *PreviewEffectTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "previewEffect",
+		Description: "Preview what an effect or raw pattern would look like over time without touching the UFO. The rings are simulated from dark, with whirl rotation, morph fading and multi-step frames applied, and sampled every intervalMs. Use it to check a pattern before playing or saving it.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of a saved effect to preview (give this or 'pattern')",
+				},
+				"pattern": map[string]interface{}{
+					"type":        "string",
+					"description": "Raw UFO API query to preview instead of a saved effect (e.g., 'top_init=1&top=0|5|FF0000&top_whirl=100')",
+				},
+				"params": map[string]interface{}{
+					"type":        "object",
+					"description": "Values for a template effect's parameters, as for playEffect (optional)",
+				},
+				"durationMs": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("How much to preview in milliseconds (optional; defaults to the effect's duration, one cycle of its steps, or %d)", defaultPreviewMs),
+					"minimum":     0,
+					"maximum":     maxPreviewMs,
+				},
+				"intervalMs": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("Time between previewed frames in milliseconds (optional, default %d)", defaultPreviewIntervalMs),
+					"minimum":     minPreviewIntervalMs,
+				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"description": "'ascii' for one line per frame with a letter per LED, or 'json' for every LED's hex color (optional, default ascii)",
+					"enum":        []string{"ascii", "json"},
+				},
+			},
+		},
+	}
+}
+
+// Execute runs the previewEffect tool
+func (t *PreviewEffectTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, _ := arguments["name"].(string)
+	pattern, _ := arguments["pattern"].(string)
+	if (name == "") == (pattern == "") {
+		return missingArgument("name", "give either 'name' or 'pattern'"), nil
+	}
+
+	title := "pattern"
+	durationMs := defaultPreviewMs
+	var steps []device.PreviewStep
+	if name != "" {
+		effect, exists := t.store.Get(name)
+		if !exists {
+			return effectNotFound("name", name), nil
+		}
+		var values map[string]string
+		if value, exists := arguments["params"]; exists {
+			var err error
+			if values, err = paramValues(value); err != nil {
+				return invalidArgument("params", err.Error()), nil
+			}
+		}
+		effect, err := effect.Render(values)
+		if err != nil {
+			return invalidArgument("params", err.Error()), nil
+		}
+		title = fmt.Sprintf("effect '%s'", name)
+		durationMs = previewDuration(effect)
+		steps = previewSteps(effect, maxPreviewMs)
+	} else {
+		steps = []device.PreviewStep{{Query: pattern}}
+	}
+
+	if value, exists := arguments["durationMs"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < 0 || n > maxPreviewMs {
+			return invalidArgument("durationMs", fmt.Sprintf("'durationMs' must be a whole number between 0 and %d", maxPreviewMs)), nil
+		}
+		durationMs = n
+	}
+	intervalMs := defaultPreviewIntervalMs
+	if value, exists := arguments["intervalMs"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < minPreviewIntervalMs {
+			return invalidArgument("intervalMs", fmt.Sprintf("'intervalMs' must be a whole number of at least %d", minPreviewIntervalMs)), nil
+		}
+		intervalMs = n
+	}
+	if _, exists := arguments["durationMs"]; !exists {
+		// A long default is cut short rather than refused
+		durationMs = min(durationMs, (maxPreviewFrames-1)*intervalMs)
+	}
+	if frames := durationMs/intervalMs + 1; frames > maxPreviewFrames {
+		return invalidArgument("intervalMs", fmt.Sprintf("%d frames is too many to preview (at most %d); raise 'intervalMs' or shorten 'durationMs'", frames, maxPreviewFrames)), nil
+	}
+	outputFormat := "ascii"
+	if value, exists := arguments["format"]; exists {
+		s, ok := value.(string)
+		if !ok || (s != "ascii" && s != "json") {
+			return invalidArgument("format", "'format' must be 'ascii' or 'json'"), nil
+		}
+		outputFormat = s
+	}
+
+	frames, err := device.Render(steps, durationMs, intervalMs)
+	if err != nil {
+		return toolError(CodeInvalidArgument, fmt.Sprintf("cannot preview %s: %v", title, err)), nil
+	}
+
+	message := fmt.Sprintf("👀 Preview of %s: %d frames over %s, every %s (nothing was sent to the UFO)\n\n",
+		title, len(frames), format.Millis(int64(durationMs)), format.Millis(int64(intervalMs)))
+	if outputFormat == "json" {
+		data, err := json.MarshalIndent(frames, "", "  ")
+		if err != nil {
+			return toolError(CodeInternal, fmt.Sprintf("failed to serialize preview: %v", err)), nil
+		}
+		message += string(data)
+	} else {
+		message += renderASCII(frames)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// previewDuration is how much of an effect to preview by default: one cycle
+// of a multi-step effect, or its duration
+func previewDuration(effect *effects.Effect) int {
+	durationMs := effect.Duration
+	if len(effect.Steps) > 0 {
+		durationMs = 0
+		for _, step := range effect.Steps {
+			durationMs += step.DurationMs
+		}
+	}
+	if durationMs <= 0 || effect.Perpetual && len(effect.Steps) == 0 {
+		durationMs = defaultPreviewMs
+	}
+	return min(durationMs, maxPreviewMs)
+}
+
+// previewSteps returns the queries an effect sends in its first untilMs,
+// cycling its steps as the engine does
+func previewSteps(effect *effects.Effect, untilMs int) []device.PreviewStep {
+	if len(effect.Steps) == 0 {
+		return []device.PreviewStep{{Query: effect.Pattern}}
+	}
+	var steps []device.PreviewStep
+	at := 0
+	for at <= untilMs {
+		for _, step := range effect.Steps {
+			steps = append(steps, device.PreviewStep{AtMs: at, Query: step.Pattern})
+			at += max(step.DurationMs, 1)
+		}
+	}
+	return steps
+}
+
+// renderASCII draws one line per frame, with a letter per LED
+func renderASCII(frames []device.PreviewFrame) string {
+	var b strings.Builder
+	for _, frame := range frames {
+		logo := "off"
+		if frame.LogoOn {
+			logo = "on"
+		}
+		fmt.Fprintf(&b, "%6dms  top %s  bottom %s  dim %d  logo %s\n",
+			frame.AtMs, asciiRing(frame.Top[:]), asciiRing(frame.Bottom[:]), frame.Dim, logo)
+	}
+	b.WriteString("\nLEDs 0-14 left to right: r red, o orange, y yellow, g green, c cyan, b blue, p purple, m magenta, w white, . off; uppercase is bright, lowercase dim")
+	return b.String()
+}
+
+// asciiRing names each LED's color with a letter
+func asciiRing(leds []string) string {
+	out := make([]byte, len(leds))
+	for i, led := range leds {
+		out[i] = asciiColor(led)
+	}
+	return string(out)
+}
+
+// asciiColor names a hex color by the nearest preview color's hue, in
+// uppercase when bright
+func asciiColor(hex string) byte {
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return '?'
+	}
+	r, g, b := float64(n>>16&0xFF), float64(n>>8&0xFF), float64(n&0xFF)
+	peak := math.Max(r, math.Max(g, b))
+	if peak < 32 {
+		return '.'
+	}
+
+	// Compare hues at full brightness so dim colors keep their name
+	scale := 255 / peak
+	best, bestDistance := byte('?'), math.Inf(1)
+	for _, c := range previewColors {
+		distance := math.Pow(r*scale-c.r, 2) + math.Pow(g*scale-c.g, 2) + math.Pow(b*scale-c.b, 2)
+		if distance < bestDistance {
+			best, bestDistance = c.letter, distance
+		}
+	}
+	if peak >= 128 {
+		best -= 'a' - 'A'
+	}
+	return best
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewEffectTool_Execute(t *testing.T) {
+	store := effects.NewStore(t.TempDir() + "/effects.json")
+	require.NoError(t, store.Add(&effects.Effect{
+		Name:        "blink",
+		Description: "Red and blue",
+		Steps: []effects.Step{
+			{Pattern: "top_init=1&top_bg=FF0000", DurationMs: 200},
+			{Pattern: "top_init=1&top_bg=000080", DurationMs: 200},
+		},
+	}))
+	require.NoError(t, store.Add(&effects.Effect{
+		Name:        "tinted",
+		Description: "Template",
+		Pattern:     "bottom_init=1&bottom_bg={color}",
+		Duration:    1000,
+		Params:      []effects.Param{{Name: "color", Default: "00FF00"}},
+	}))
+	tool := NewPreviewEffectTool(store)
+
+	t.Run("Definition", func(t *testing.T) {
+		def := tool.Definition()
+		assert.Equal(t, "previewEffect", def.Name)
+		assert.Contains(t, def.InputSchema.Properties, "name")
+		assert.Contains(t, def.InputSchema.Properties, "pattern")
+		assert.Contains(t, def.InputSchema.Properties, "format")
+	})
+
+	t.Run("ASCIIPattern", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"pattern":    "top_init=1&top=0|3|FF0000&top_whirl=100",
+			"durationMs": 200,
+		})
+		require.NoError(t, err)
+		text := result.Content[0].(mcp.TextContent).Text
+		require.False(t, result.IsError, text)
+		assert.Contains(t, text, "3 frames")
+		assert.Contains(t, text, "top RRR............")
+		assert.Contains(t, text, "top .RRR...........")
+		assert.Contains(t, text, "top ..RRR..........")
+	})
+
+	t.Run("StepsCycle", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"name":       "blink",
+			"intervalMs": 200,
+			"durationMs": 600,
+			"format":     "json",
+		})
+		require.NoError(t, err)
+		text := result.Content[0].(mcp.TextContent).Text
+		require.False(t, result.IsError, text)
+
+		var frames []device.PreviewFrame
+		require.NoError(t, json.Unmarshal([]byte(text[strings.Index(text, "["):]), &frames))
+		require.Len(t, frames, 4)
+		for i, want := range []string{"ff0000", "000080", "ff0000", "000080"} {
+			assert.Equal(t, want, frames[i].Top[0], "frame %d", i)
+		}
+	})
+
+	t.Run("TemplateParams", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"name":   "tinted",
+			"params": map[string]interface{}{"color": "0000FF"},
+		})
+		require.NoError(t, err)
+		text := result.Content[0].(mcp.TextContent).Text
+		require.False(t, result.IsError, text)
+		assert.Contains(t, text, "11 frames")
+		assert.Contains(t, text, "bottom BBBBBBBBBBBBBBB")
+	})
+
+	t.Run("LongDefaultIsCut", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"pattern":    "top_init=1&top_bg=FF0000",
+			"intervalMs": 10,
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "300 frames")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			arguments map[string]interface{}
+			message   string
+		}{
+			{"Neither", map[string]interface{}{}, "either 'name' or 'pattern'"},
+			{"Both", map[string]interface{}{"name": "blink", "pattern": "dim=10"}, "either 'name' or 'pattern'"},
+			{"Unknown", map[string]interface{}{"name": "missing"}, "not found"},
+			{"BadPattern", map[string]interface{}{"pattern": "top=0|99"}, "cannot preview"},
+			{"TooManyFrames", map[string]interface{}{"pattern": "dim=10", "durationMs": 60000, "intervalMs": 10}, "too many"},
+			{"BadFormat", map[string]interface{}{"pattern": "dim=10", "format": "png"}, "'format'"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := tool.Execute(context.Background(), tt.arguments)
+				require.NoError(t, err)
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.message)
+			})
+		}
+	})
+}
+
+func TestAsciiColor(t *testing.T) {
+	for hex, want := range map[string]byte{
+		"FF0000": 'R', "400000": 'r', "000000": '.', "101010": '.',
+		"FFA500": 'O', "ffffff": 'W', "00ff40": 'G', "600060": 'm', "junk": '?',
+	} {
+		assert.Equal(t, string(want), string(asciiColor(hex)), hex)
+	}
+}
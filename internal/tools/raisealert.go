@@ -0,0 +1,295 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// Alert priorities accepted by raiseAlert. Ordinary effects have priority 0,
+// so every raised alert preempts them.
+const (
+	DefaultAlertPriority = 50
+	MaxAlertPriority     = 100
+)
+
+// alertSource identifies raised alerts in alert events
+const alertSource = "mcp"
+
+// RaiseAlertTool implements the raiseAlert MCP tool
+type RaiseAlertTool struct {
+	broadcaster  *events.Broadcaster
+	store        *effects.Store
+	stateManager *state.Manager
+	engine       *effects.Engine
+	restores     *Restores
+}
+
+// NewRaiseAlertTool creates a new raiseAlert tool instance
+func NewRaiseAlertTool(broadcaster *events.Broadcaster, store *effects.Store, stateManager *state.Manager, engine *effects.Engine, restores *Restores) *RaiseAlertTool {
+	return &RaiseAlertTool{
+		broadcaster:  broadcaster,
+		store:        store,
+		stateManager: stateManager,
+		engine:       engine,
+		restores:     restores,
+	}
+}
+
+// Definition returns the MCP tool definition for raiseAlert
+func (t *RaiseAlertTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "raiseAlert",
+		Description: "Raise a named alert on the UFO for incident signaling. The alert preempts ordinary effects and lower priority alerts: effects played while it is active wait beneath it until it expires or is cleared with clearAlert. Raising an alert with an existing name replaces it.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Alert name, used to clear it later (e.g. 'checkout-outage')",
+				},
+				"effect": map[string]interface{}{
+					"type":        "string",
+					"description": "Stored effect to show (optional, e.g. 'alertPulse')",
+				},
+				"color": map[string]interface{}{
+					"type":        "string",
+					"description": "Solid color to show when no effect is given, hex or name (optional, default red)",
+				},
+				"zone": map[string]interface{}{
+					"type":        "string",
+					"description": "Rings to color (optional, default all)",
+					"enum":        []string{"all", "top", "bottom"},
+				},
+				"priority": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("Priority from 1 to %d; alerts never show beneath a lower priority one (optional, default %d)", MaxAlertPriority, DefaultAlertPriority),
+					"minimum":     1,
+					"maximum":     MaxAlertPriority,
+				},
+				"durationMs": map[string]interface{}{
+					"type":        "integer",
+					"description": "Expire the alert after this many milliseconds (optional, default 0: until cleared)",
+					"minimum":     0,
+					"maximum":     86400000,
+				},
+				"reason": map[string]interface{}{
+					"type":        "string",
+					"description": "Why the alert was raised, shown in the effect stack and events (optional)",
+				},
+			},
+			Required: []string{"name"},
+		},
+	}
+}
+
+// Execute runs the raiseAlert tool
+func (t *RaiseAlertTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, ok := arguments["name"].(string)
+	if !ok || name == "" {
+		return missingArgument("name", "'name' parameter is required and must be a non-empty string"), nil
+	}
+
+	var action integrations.Action
+	for key, target := range map[string]*string{"effect": &action.Effect, "color": &action.Color, "zone": &action.Zone} {
+		if value, exists := arguments[key]; exists {
+			text, ok := value.(string)
+			if !ok {
+				return invalidArgument(key, fmt.Sprintf("'%s' must be a string", key)), nil
+			}
+			*target = text
+		}
+	}
+	if action.Effect == "" && action.Color == "" {
+		action.Color = "red"
+	}
+	if err := action.Validate(); err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+
+	priority := DefaultAlertPriority
+	if value, exists := arguments["priority"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < 1 || n > MaxAlertPriority {
+			return invalidArgument("priority", fmt.Sprintf("'priority' must be an integer from 1 to %d", MaxAlertPriority)), nil
+		}
+		priority = n
+	}
+
+	durationMs := 0
+	if value, exists := arguments["durationMs"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < 0 || n > 86400000 {
+			return invalidArgument("durationMs", "'durationMs' must be between 0 and 86400000"), nil
+		}
+		durationMs = n
+	}
+
+	reason := ""
+	if value, exists := arguments["reason"]; exists {
+		if reason, ok = value.(string); !ok {
+			return invalidArgument("reason", "'reason' must be a string"), nil
+		}
+	}
+
+	pattern, steps, err := action.Resolve(t.store)
+	if err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+	effectName := action.Effect
+	if effectName == "" {
+		effectName = "alert:" + name
+	}
+
+	// Raising an alert again replaces it
+	_, replacedTop := t.stateManager.RemoveEffects(func(item state.EffectStackItem) bool {
+		return raisedAlertName(item) == name
+	})
+
+	startTime := time.Now()
+	instanceID := state.NewInstanceID()
+	alertContext := map[string]interface{}{
+		"instanceId":  instanceID,
+		"raisedAlert": name,
+		"priority":    priority,
+		"duration":    durationMs,
+		"perpetual":   durationMs == 0,
+		"startTime":   startTime,
+	}
+	if reason != "" {
+		alertContext["reason"] = reason
+	}
+	if len(steps) > 0 {
+		alertContext["steps"] = steps
+	}
+	showing, stale := t.stateManager.PushEffect(effectName, pattern, alertContext)
+
+	switch {
+	case showing:
+		if err := t.engine.Apply(ctx, effectName, pattern, steps); err != nil {
+			t.broadcaster.PublishRawExecuted(ctx, pattern, fmt.Sprintf("ERROR: %v", err))
+			t.stateManager.RemoveEffects(func(item state.EffectStackItem) bool {
+				return item.StartTime().Equal(startTime)
+			})
+			return toolError(CodeDeviceError, fmt.Sprintf("Failed to send alert to UFO: %v", err)), nil
+		}
+		t.broadcaster.PublishRawExecuted(ctx, pattern, "OK")
+	case replacedTop || stale:
+		// The alert was showing before and is now beneath a higher priority one
+		if err := restoreTop(ctx, t.engine, t.broadcaster, t.stateManager); err != nil {
+			return toolError(CodeDeviceError, err.Error()), nil
+		}
+	}
+
+	t.broadcaster.PublishPayload(ctx, events.AlertFiring{
+		Source:     alertSource,
+		Key:        name,
+		Name:       name,
+		InstanceID: instanceID,
+		Priority:   priority,
+		Showing:    &showing,
+		Reason:     reason,
+	})
+
+	if durationMs > 0 {
+		t.restores.scheduleExpiry(ctx, name, instanceID)
+	}
+
+	message := fmt.Sprintf("🚨 Alert '%s' raised with priority %d", name, priority)
+	if !showing {
+		if top := t.stateManager.GetCurrentEffect(); top != nil {
+			message += fmt.Sprintf(", waiting beneath higher priority alert '%s'", raisedAlertName(*top))
+		}
+	}
+	message += fmt.Sprintf("\n• Instance: %s\n", instanceID)
+	message += fmt.Sprintf("• Showing: %s\n", effectName)
+	if durationMs > 0 {
+		message += fmt.Sprintf("• Expires after %s unless cleared\n", format.Millis(int64(durationMs)))
+	} else {
+		message += "• Active until cleared with clearAlert\n"
+	}
+	message += "Lower priority effects played meanwhile wait beneath it."
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// raisedAlertName returns the name of the alert a stack entry was raised
+// for, or "" for other entries
+func raisedAlertName(item state.EffectStackItem) string {
+	name, _ := item.Context["raisedAlert"].(string)
+	return name
+}
+
+// activeAlert returns the stack entry holding the UFO when it outranks
+// ordinary effects, or nil
+func activeAlert(stateManager *state.Manager) *state.EffectStackItem {
+	top := stateManager.GetCurrentEffect()
+	if top == nil || top.Priority() <= 0 {
+		return nil
+	}
+	return top
+}
+
+// stackFull returns the error result for a tool that would push onto an
+// effect stack with no room, or nil if there is room
+func stackFull(stateManager *state.Manager) *mcp.CallToolResult {
+	if err := stateManager.CheckRoom(); err != nil {
+		return ToolError{Code: CodeConflict, Message: err.Error(), Hint: "Stop effects with stopEffect or stopAllEffects first"}.Result()
+	}
+	return nil
+}
+
+// restoreTop shows the entry now on top of the stack, or clears the UFO when
+// the stack is empty
+func restoreTop(ctx context.Context, engine *effects.Engine, broadcaster *events.Broadcaster, stateManager *state.Manager) error {
+	query, effectName := "top_init=1&bottom_init=1", ""
+	var steps []effects.Step
+	current := stateManager.GetCurrentEffect()
+	if current != nil {
+		query, effectName = current.Pattern, current.Name
+		steps = effects.StepsFromContext(current.Context)
+	}
+
+	if err := engine.Apply(ctx, effectName, query, steps); err != nil {
+		broadcaster.PublishRawExecuted(ctx, query, fmt.Sprintf("ERROR: %v", err))
+		return fmt.Errorf("restoring previous state: %w", err)
+	}
+	broadcaster.PublishRawExecuted(ctx, query, "OK")
+
+	if current != nil {
+		broadcaster.PublishPayload(ctx, events.EffectResumed{
+			Effect:     current.Name,
+			InstanceID: current.InstanceID(),
+			StackDepth: stateManager.GetEffectStackDepth(),
+		})
+	}
+	return nil
+}
+
+// restoreStale shows the entry on top of the stack after pushing an effect
+// dropped the one that was showing for a full stack. The push itself went
+// ahead, so a failure is only logged.
+func restoreStale(ctx context.Context, stale bool, engine *effects.Engine, broadcaster *events.Broadcaster, stateManager *state.Manager) {
+	if !stale {
+		return
+	}
+	if err := restoreTop(ctx, engine, broadcaster, stateManager); err != nil {
+		slog.WarnContext(ctx, "Failed to show the effect left on top of a full stack", "error", err)
+	}
+}
@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRaiseAlertTool_PreemptsEffects(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+	lastQuery := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(queries) == 0 {
+			return ""
+		}
+		return queries[len(queries)-1]
+	}
+
+	store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	require.NoError(t, store.Add(&effects.Effect{Name: "calm", Description: "Calm blue", Pattern: "top_init=1&top=0|15|0000FF", Perpetual: true}))
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	client := device.NewClient()
+	engine := effects.NewEngine(client)
+	restores := NewRestores(engine, broadcaster, stateManager)
+	raise := NewRaiseAlertTool(broadcaster, store, stateManager, engine, restores)
+	clear := NewClearAlertTool(broadcaster, stateManager, engine)
+	play := NewPlayEffectTool(client, broadcaster, store, stateManager, engine, restores)
+	stop := NewStopEffectTool(client, broadcaster, stateManager, engine)
+	text := func(result *mcp.CallToolResult) string { return result.Content[0].(mcp.TextContent).Text }
+
+	result, err := raise.Execute(context.Background(), map[string]interface{}{"name": "outage", "color": "red", "reason": "checkout down"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, text(result))
+	assert.Equal(t, "top_init=1&top_bg=ff0000&bottom_init=1&bottom_bg=ff0000", lastQuery())
+
+	// An ordinary effect waits beneath the alert without reaching the UFO
+	result, err = play.Execute(context.Background(), map[string]interface{}{"name": "calm"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, text(result))
+	assert.Contains(t, text(result), "queued beneath alert")
+	assert.Equal(t, "top_init=1&top_bg=ff0000&bottom_init=1&bottom_bg=ff0000", lastQuery())
+	assert.Equal(t, "alert:outage", stateManager.GetCurrentEffect().Name)
+
+	// A lower priority alert also waits
+	result, err = raise.Execute(context.Background(), map[string]interface{}{"name": "minor", "color": "yellow", "priority": float64(10)})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "waiting beneath higher priority alert 'outage'")
+
+	// stopEffect leaves raised alerts alone
+	result, err = stop.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, text(result), "clearAlert")
+
+	// Clearing the top alert shows the next one
+	result, err = clear.Execute(context.Background(), map[string]interface{}{"name": "outage"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, text(result))
+	assert.Equal(t, "alert:minor", stateManager.GetCurrentEffect().Name)
+	assert.Contains(t, lastQuery(), "ffff00")
+
+	// Clearing all alerts resumes the effect played meanwhile
+	result, err = clear.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "now showing 'calm'")
+	assert.Equal(t, "top_init=1&top=0|15|0000FF", lastQuery())
+
+	result, err = clear.Execute(context.Background(), map[string]interface{}{"name": "outage"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestRaiseAlertTool_Validation(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	engine := effects.NewEngine(device.NewClient())
+	tool := NewRaiseAlertTool(broadcaster, store, stateManager, engine, NewRestores(engine, broadcaster, stateManager))
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+	}{
+		{"missing name", map[string]interface{}{}},
+		{"priority too high", map[string]interface{}{"name": "a", "priority": float64(101)}},
+		{"priority zero", map[string]interface{}{"name": "a", "priority": float64(0)}},
+		{"negative duration", map[string]interface{}{"name": "a", "durationMs": float64(-1)}},
+		{"unknown effect", map[string]interface{}{"name": "a", "effect": "nope"}},
+		{"bad zone", map[string]interface{}{"name": "a", "color": "red", "zone": "middle"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Execute(context.Background(), tt.arguments)
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+		})
+	}
+	assert.Zero(t, stateManager.GetEffectStackDepth())
+}
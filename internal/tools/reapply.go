@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// ReapplyState shows the shadow state on the UFO again after it restarted
+// and came back dark: the effect on top of the stack if there is one,
+// otherwise the rings, their animations and the logo last set. The
+// brightness is restored either way; a logo animation never stopped, so it
+// redraws the logo by itself.
+func ReapplyState(ctx context.Context, engine *effects.Engine, broadcaster *events.Broadcaster, stateManager *state.Manager) error {
+	snapshot := stateManager.Snapshot()
+	dim := fmt.Sprintf("dim=%d", snapshot.Dim)
+
+	if stateManager.GetCurrentEffect() != nil {
+		if err := restoreTop(ctx, engine, broadcaster, stateManager); err != nil {
+			return err
+		}
+		if err := engine.Overlay(ctx, dim); err != nil {
+			broadcaster.PublishRawExecuted(ctx, dim, fmt.Sprintf("ERROR: %v", err))
+			return fmt.Errorf("restoring brightness: %w", err)
+		}
+		broadcaster.PublishRawExecuted(ctx, dim, "OK")
+		return nil
+	}
+
+	query, err := shadowQuery(snapshot, nil)
+	if err != nil {
+		return fmt.Errorf("building the shadow state: %w", err)
+	}
+	query = strings.Trim(query+"&"+dim, "&")
+	if err := engine.Apply(ctx, "", query, nil); err != nil {
+		broadcaster.PublishRawExecuted(ctx, query, fmt.Sprintf("ERROR: %v", err))
+		return fmt.Errorf("restoring the shadow state: %w", err)
+	}
+	broadcaster.PublishRawExecuted(ctx, query, "OK")
+	return nil
+}
+
+// shadowQuery returns the query that draws the shadow state, brightness
+// aside. previous is the state the UFO shows, or nil when it is dark: a ring
+// the query leaves dark would keep a whirl or morph previous has, so the
+// query stops those explicitly, with whirl=0 or, as only initializing a
+// ring ends its morph, with init.
+func shadowQuery(snapshot, previous *state.LedState) (string, error) {
+	var dark device.Frame
+	for i := range dark.Top {
+		dark.Top[i], dark.Bottom[i] = "000000", "000000"
+	}
+	query, err := device.Frame{Top: snapshot.Top, Bottom: snapshot.Bottom}.Query(dark)
+	if err != nil {
+		return "", err
+	}
+	if previous == nil {
+		previous = &state.LedState{}
+	}
+
+	parts := []string{query}
+	for _, ring := range []struct {
+		name          string
+		drawn         bool // the ring is initialized to draw its LEDs
+		whirlMs       int
+		ccw           bool
+		morph         *state.MorphData
+		previousWhirl int
+		previousMorph *state.MorphData
+	}{
+		{"top", snapshot.Top != dark.Top, snapshot.TopWhirlMs, snapshot.TopWhirlCounterClockwise, snapshot.TopMorph, previous.TopWhirlMs, previous.TopMorph},
+		{"bottom", snapshot.Bottom != dark.Bottom, snapshot.BottomWhirlMs, snapshot.BottomWhirlCounterClockwise, snapshot.BottomMorph, previous.BottomWhirlMs, previous.BottomMorph},
+	} {
+		switch {
+		case ring.drawn:
+		case ring.morph == nil && ring.previousMorph != nil:
+			parts = append(parts, fmt.Sprintf("%s_init=1", ring.name))
+		case ring.whirlMs == 0 && ring.previousWhirl > 0:
+			parts = append(parts, fmt.Sprintf("%s_whirl=0", ring.name))
+		}
+		if ring.whirlMs > 0 {
+			whirl := fmt.Sprintf("%s_whirl=%d", ring.name, ring.whirlMs)
+			if ring.ccw {
+				whirl += "|ccw"
+			}
+			parts = append(parts, whirl)
+		}
+		if ring.morph != nil {
+			parts = append(parts, fmt.Sprintf("%s_morph=%s", ring.name, device.ConvertMorphToDevice(&device.MorphConfig{
+				BrightnessMs: ring.morph.BrightnessMs,
+				FadeMs:       ring.morph.FadeMs,
+			})))
+		}
+	}
+	if snapshot.LogoAnimation != nil {
+		parts = append(parts, logoSteps(*snapshot.LogoAnimation)[0].Pattern)
+	} else if snapshot.LogoOn {
+		parts = append(parts, "logo=on")
+	}
+	return strings.Trim(strings.Join(parts, "&"), "&"), nil
+}
@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReapplyState(t *testing.T) {
+	simulator := device.NewSimulator()
+	client := device.NewSimulatedClient(simulator)
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	engine := effects.NewEngine(client)
+	ctx := context.Background()
+
+	// Without an effect, the rings, logo and brightness last set come back
+	top := make([]string, 15)
+	for i := range top {
+		top[i] = "000000"
+	}
+	top[0], top[1] = "ff0000", "ff0000"
+	stateManager.UpdateTopRing(top)
+	stateManager.UpdateLogo(true)
+	stateManager.UpdateBrightness(80)
+	simulator.Restart()
+
+	require.NoError(t, ReapplyState(ctx, engine, broadcaster, stateManager))
+	status, err := client.FetchStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, top, status.Top)
+	assert.Equal(t, 80, *status.Dim)
+	assert.True(t, *status.LogoOn)
+
+	// The effect on top of the stack wins over the rings
+	stateManager.PushEffect("calm", "top_init=1&top_bg=0000ff", map[string]interface{}{"perpetual": true})
+	simulator.Restart()
+
+	require.NoError(t, ReapplyState(ctx, engine, broadcaster, stateManager))
+	status, err = client.FetchStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "0000ff", status.Top[0])
+	assert.Equal(t, 80, *status.Dim)
+}
+
+func TestShadowQuery_WhirlDirection(t *testing.T) {
+	snapshot := state.LedState{TopWhirlMs: 200, TopWhirlCounterClockwise: true, BottomWhirlMs: 300}
+	for i := range snapshot.Top {
+		snapshot.Top[i], snapshot.Bottom[i] = "000000", "000000"
+	}
+
+	query, err := shadowQuery(&snapshot, nil)
+	require.NoError(t, err)
+	assert.Contains(t, query, "top_whirl=200|ccw")
+	assert.Contains(t, query, "bottom_whirl=300")
+	assert.NotContains(t, query, "bottom_whirl=300|ccw")
+}
+
+func TestShadowQuery_ClearsAnimations(t *testing.T) {
+	var target state.LedState
+	for i := range target.Top {
+		target.Top[i], target.Bottom[i] = "000000", "000000"
+	}
+	previous := target
+	previous.TopWhirlMs = 200
+	previous.BottomMorph = &state.MorphData{BrightnessMs: 1000, FadeMs: 500}
+
+	// Dark rings would keep animating: the whirl is stopped and the morph
+	// ring initialized
+	query, err := shadowQuery(&target, &previous)
+	require.NoError(t, err)
+	assert.Equal(t, "top_whirl=0&bottom_init=1", query)
+
+	// A dark UFO has nothing to stop
+	query, err = shadowQuery(&target, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "", query)
+
+	// Drawn rings are initialized anyway
+	target.Top[0] = "ff0000"
+	query, err = shadowQuery(&target, &previous)
+	require.NoError(t, err)
+	assert.Equal(t, "top_init=1&top_bg=000000&top=0|1|FF0000&bottom_init=1", query)
+
+	// The state records what the query stops
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	stateManager.UpdateWhirl("top", 200, true)
+	_, err = stateManager.ApplyQuery("top_whirl=0")
+	require.NoError(t, err)
+	snapshot := stateManager.Snapshot()
+	assert.Equal(t, 0, snapshot.TopWhirlMs)
+	assert.False(t, snapshot.TopWhirlCounterClockwise)
+}
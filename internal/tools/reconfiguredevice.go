@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/devices"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// errUnresolved marks an address whose host name does not resolve
+var errUnresolved = errors.New("host name does not resolve")
+
+// DeviceSwitch describes a change of the UFO the server controls
+type DeviceSwitch struct {
+	From      string   // address before
+	To        string   // address now
+	Addresses []string // IP addresses a host name resolved to
+	Warning   string   // why the state could not be shown on the UFO, if it could not
+}
+
+// ReconfigureDeviceTool implements the reconfigureDevice MCP tool, which
+// points the server at another UFO address without a restart
+type ReconfigureDeviceTool struct {
+	client       *device.Client
+	registry     *devices.Registry
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+	engine       *effects.Engine
+}
+
+// NewReconfigureDeviceTool creates a new reconfigureDevice tool instance
+func NewReconfigureDeviceTool(client *device.Client, registry *devices.Registry, broadcaster *events.Broadcaster, stateManager *state.Manager, engine *effects.Engine) *ReconfigureDeviceTool {
+	return &ReconfigureDeviceTool{
+		client:       client,
+		registry:     registry,
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		engine:       engine,
+	}
+}
+
+// Definition returns the MCP tool definition for reconfigureDevice
+func (t *ReconfigureDeviceTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "reconfigureDevice",
+		Description: "Switch the server to another UFO address, e.g. after the UFO got a new IP from DHCP, without restarting. A host name is resolved again; the UFO there must answer before the switch is made, otherwise the server keeps the current one. Effects, the stack and the shadow state carry over and are shown on the new UFO. Passing the current address reconnects to it.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"address": map[string]interface{}{
+					"type":        "string",
+					"description": "Host name or IP address, optionally with a port, or nickname of the UFO",
+					"examples":    []string{"192.168.1.72", "ufo-kitchen.local", "kitchen"},
+				},
+			},
+			Required: []string{"address"},
+		},
+	}
+}
+
+// Execute runs the reconfigureDevice tool
+func (t *ReconfigureDeviceTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	address, _ := arguments["address"].(string)
+	if strings.TrimSpace(address) == "" {
+		return missingArgument("address", "'address' must be a host name, IP address or nickname"), nil
+	}
+
+	change, err := t.Switch(ctx, address, "reconfigureDevice")
+	if errors.Is(err, errUnresolved) {
+		return ToolError{Code: CodeInvalidArgument, Message: err.Error(), Parameter: "address", Hint: "Use discoverUfos to find the UFOs on the network"}.Result(), nil
+	}
+	if err != nil {
+		return ToolError{Code: CodeDeviceError, Message: err.Error(), Parameter: "address", Hint: "Check that the UFO is powered and on the network"}.Result(), nil
+	}
+
+	message := fmt.Sprintf("🛸 Now controlling the UFO at %s\n\n", change.To)
+	if change.From != change.To {
+		message += fmt.Sprintf("• Previously: %s\n", change.From)
+	} else {
+		message += "• Reconnected to the same address\n"
+	}
+	if len(change.Addresses) > 0 {
+		message += fmt.Sprintf("• Resolves to: %s\n", strings.Join(change.Addresses, ", "))
+	}
+	if change.Warning != "" {
+		message += fmt.Sprintf("\n⚠️ Warning: the current state was not shown on the UFO: %s", change.Warning)
+	} else {
+		message += "• The current state is shown on it\n"
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Switch makes the UFO at address, which may be a nickname, the one the
+// server controls once it answers, then shows the shadow state on it.
+// trigger names what asked for the switch in the event published.
+func (t *ReconfigureDeviceTool) Switch(ctx context.Context, address, trigger string) (DeviceSwitch, error) {
+	address = strings.TrimSpace(address)
+	if resolved, ok := t.registry.Resolve(address); ok {
+		address = resolved
+	}
+	change := DeviceSwitch{From: t.client.Address(), To: address}
+
+	host := address
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		host = h
+	}
+	if net.ParseIP(host) == nil {
+		addresses, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			return change, fmt.Errorf("%w: %s: %v", errUnresolved, host, err)
+		}
+		change.Addresses = addresses
+	}
+	if _, err := t.client.CheckAddress(ctx, address); err != nil {
+		return change, fmt.Errorf("the UFO at %s did not answer, still controlling %s: %w", address, change.From, err)
+	}
+
+	t.client.SetAddress(address)
+	var correction *device.ColorCorrection
+	if info, ok := t.registry.Get(address); ok {
+		correction = info.ColorCorrection
+	}
+	t.client.SetColorCorrection(correction)
+	t.broadcaster.SetDevice(address)
+	// Tools naming the active UFO read it from the environment
+	os.Setenv("UFO_IP", address)
+	slog.InfoContext(ctx, "Switched UFO", "from", change.From, "to", address, "trigger", trigger)
+
+	if err := ReapplyState(ctx, t.engine, t.broadcaster, t.stateManager); err != nil {
+		change.Warning = err.Error()
+		slog.WarnContext(ctx, "Failed to show the state on the new UFO", "error", err)
+	}
+	t.broadcaster.PublishPayload(ctx, events.DeviceReconfigured{
+		From:      change.From,
+		To:        address,
+		Addresses: change.Addresses,
+		Trigger:   trigger,
+	})
+	return change, nil
+}
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/devices"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconfigureDeviceTool_Execute(t *testing.T) {
+	// The UFO in use and the one it is replaced by
+	oldSimulator, newSimulator := device.NewSimulator(), device.NewSimulator()
+	oldServer, newServer := httptest.NewServer(oldSimulator), httptest.NewServer(newSimulator)
+	defer oldServer.Close()
+	defer newServer.Close()
+	oldAddress, newAddress := oldServer.URL[7:], newServer.URL[7:]
+	t.Setenv("UFO_IP", oldAddress)
+
+	client := device.NewClient()
+	registry := devices.NewRegistry(filepath.Join(t.TempDir(), "devices.json"))
+	require.NoError(t, registry.Set(devices.Device{Address: newAddress, Nickname: "kitchen"}))
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	subscriber := broadcaster.SubscribeFiltered("test", events.SubscriptionFilter{Types: []string{events.EventDeviceReconfigured}})
+	stateManager := state.NewManager(broadcaster)
+	stateManager.PushEffect("calm", "top_init=1&top_bg=0000ff", map[string]interface{}{"perpetual": true})
+	tool := NewReconfigureDeviceTool(client, registry, broadcaster, stateManager, effects.NewEngine(client))
+	text := func(result *mcp.CallToolResult) string { return result.Content[0].(mcp.TextContent).Text }
+
+	// An address where no UFO answers keeps the current one
+	closed := httptest.NewServer(nil)
+	closed.Close()
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"address": closed.URL[7:]})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	toolErr, _ := ErrorOf(result)
+	assert.Equal(t, CodeDeviceError, toolErr.Code)
+	assert.Equal(t, oldAddress, client.Address())
+
+	// The nickname picks the new UFO, which then shows the current effect
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"address": "kitchen"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, text(result))
+	assert.Contains(t, text(result), "Previously: "+oldAddress)
+	assert.Equal(t, newAddress, client.Address())
+	assert.Equal(t, newAddress, os.Getenv("UFO_IP"))
+	status, err := client.FetchStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "0000ff", status.Top[0])
+
+	select {
+	case event := <-subscriber.Channel:
+		assert.Equal(t, oldAddress, event.Data["from"])
+		assert.Equal(t, newAddress, event.Data["to"])
+		assert.Equal(t, "reconfigureDevice", event.Data["trigger"])
+		assert.Equal(t, newAddress, event.Device)
+	case <-time.After(time.Second):
+		t.Fatal("expected a device_reconfigured event")
+	}
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+)
+
+// RemoveBindingTool implements the removeBinding MCP tool
+type RemoveBindingTool struct {
+	bindings *integrations.Bindings
+}
+
+// NewRemoveBindingTool creates a new removeBinding tool instance
+func NewRemoveBindingTool(bindings *integrations.Bindings) *RemoveBindingTool {
+	return &RemoveBindingTool{
+		bindings: bindings,
+	}
+}
+
+// Definition returns the MCP tool definition for removeBinding
+func (t *RemoveBindingTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "removeBinding",
+		Description: "Stop a poller binding and clear its light from the UFO.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the binding to remove",
+				},
+			},
+			Required: []string{"name"},
+		},
+	}
+}
+
+// Execute runs the removeBinding tool
+func (t *RemoveBindingTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, ok := arguments["name"].(string)
+	if !ok || name == "" {
+		return missingArgument("name", "'name' parameter is required and must be a non-empty string"), nil
+	}
+
+	if err := t.bindings.Remove(ctx, name); err != nil {
+		return toolError(CodeNotFound, fmt.Sprintf("%v", err)), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("✅ Binding '%s' removed", name),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// RemoveEffectByIDTool implements the removeEffectById MCP tool, which
+// takes one entry out of the effect stack wherever it is
+type RemoveEffectByIDTool struct {
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+	engine       *effects.Engine
+}
+
+// NewRemoveEffectByIDTool creates a new removeEffectById tool instance
+func NewRemoveEffectByIDTool(broadcaster *events.Broadcaster, stateManager *state.Manager, engine *effects.Engine) *RemoveEffectByIDTool {
+	return &RemoveEffectByIDTool{
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		engine:       engine,
+	}
+}
+
+// Definition returns the MCP tool definition for removeEffectById
+func (t *RemoveEffectByIDTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "removeEffectById",
+		Description: "Remove one entry from the effect stack by the instance ID getEffectStack reports, wherever it is on the stack. The entries above it keep their places, and the UFO only changes if the entry was showing, in which case the one beneath resumes or the UFO is cleared. Unlike stopEffect it also removes alerts, for getting rid of a stuck entry.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"instanceId": map[string]interface{}{
+					"type":        "string",
+					"description": "Instance ID of the stack entry to remove",
+				},
+			},
+			Required: []string{"instanceId"},
+		},
+	}
+}
+
+// Execute runs the removeEffectById tool
+func (t *RemoveEffectByIDTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	instanceID, _ := arguments["instanceId"].(string)
+	if instanceID == "" {
+		return missingArgument("instanceId", "'instanceId' parameter is required and must be a non-empty string"), nil
+	}
+
+	notFound := ToolError{Code: CodeNotFound, Message: fmt.Sprintf("no effect with instance ID '%s' is on the stack", instanceID), Hint: "List the entries and their instance IDs with getEffectStack"}
+	position := -1
+	stack := t.stateManager.GetEffectStack()
+	for i, item := range stack {
+		if item.InstanceID() == instanceID {
+			position = i
+		}
+	}
+	if position < 0 {
+		return notFound.Result(), nil
+	}
+	target := stack[position]
+
+	removed, topRemoved := t.stateManager.RemoveEffects(func(item state.EffectStackItem) bool {
+		return item.InstanceID() == instanceID
+	})
+	if removed == 0 {
+		return notFound.Result(), nil
+	}
+	if topRemoved {
+		if err := restoreTop(ctx, t.engine, t.broadcaster, t.stateManager); err != nil {
+			return toolError(CodeDeviceError, err.Error()), nil
+		}
+	}
+
+	// Alerts resolve as clearAlert or their integration would resolve them
+	raised := raisedAlertName(target)
+	source, _ := target.Context["source"].(string)
+	key, isIntegrationAlert := target.Context["alertKey"].(string)
+	switch {
+	case raised != "":
+		t.broadcaster.PublishPayload(ctx, events.AlertResolved{Source: alertSource, Key: raised, Name: raised, InstanceID: instanceID})
+	case isIntegrationAlert && source != "":
+		name, _ := target.Context["alertName"].(string)
+		t.broadcaster.PublishPayload(ctx, events.AlertResolved{Source: source, Key: key, Name: name, InstanceID: instanceID})
+	default:
+		t.broadcaster.PublishPayload(ctx, events.EffectStopped{
+			Effect:     target.Name,
+			InstanceID: instanceID,
+			Manual:     true,
+			StackDepth: t.stateManager.GetEffectStackDepth(),
+		})
+	}
+
+	message := fmt.Sprintf("🗑️ Removed '%s' from position %d of the stack (instance %s, stack depth: %d)\n",
+		target.Name, position, instanceID, t.stateManager.GetEffectStackDepth())
+	current := t.stateManager.GetCurrentEffect()
+	switch {
+	case !topRemoved && current != nil:
+		message += fmt.Sprintf("• '%s' keeps showing", current.Name)
+	case current != nil:
+		message += fmt.Sprintf("• Now showing '%s'", current.Name)
+	default:
+		message += "• The stack is empty and the UFO is cleared"
+	}
+	message += stoppedTiming(target, time.Now())
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
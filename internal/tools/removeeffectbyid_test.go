@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveEffectByIDTool_Execute(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	sub := broadcaster.SubscribeFiltered("test", events.SubscriptionFilter{Types: []string{events.EventAlertResolved, events.EventEffectStopped}})
+	stateManager := state.NewManager(broadcaster)
+	tool := NewRemoveEffectByIDTool(broadcaster, stateManager, effects.NewEngine(device.NewClient()))
+	text := func(result *mcp.CallToolResult) string { return result.Content[0].(mcp.TextContent).Text }
+
+	stateManager.PushEffect("calm", "top_init=1&top_bg=0000ff", nil)
+	stateManager.PushEffect("alert:stuck", "top_init=1&top_bg=ff0000", map[string]interface{}{"raisedAlert": "stuck", "priority": 50})
+	stateManager.PushEffect("alert:build", "top_init=1&top_bg=ffa500", map[string]interface{}{"priority": 60})
+	stack := stateManager.GetEffectStack()
+	for _, item := range stack {
+		_, err := uuid.Parse(item.InstanceID())
+		assert.NoError(t, err, "instance IDs are UUIDs")
+	}
+
+	// A stuck alert beneath another entry is removed without touching the UFO
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"instanceId": stack[1].InstanceID()})
+	require.NoError(t, err)
+	require.False(t, result.IsError, text(result))
+	assert.Contains(t, text(result), "Removed 'alert:stuck' from position 1")
+	assert.Contains(t, text(result), "'alert:build' keeps showing")
+	remaining := stateManager.GetEffectStack()
+	require.Len(t, remaining, 2)
+	assert.Equal(t, stack[0].InstanceID(), remaining[0].InstanceID())
+	assert.Equal(t, stack[2].InstanceID(), remaining[1].InstanceID())
+	mu.Lock()
+	assert.Empty(t, queries)
+	mu.Unlock()
+	event := <-sub.Channel
+	assert.Equal(t, events.EventAlertResolved, event.Type)
+	assert.Equal(t, "stuck", event.Data["name"])
+	assert.Equal(t, stack[1].InstanceID(), event.Data["instanceId"])
+
+	// Removing the top shows the entry beneath
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"instanceId": stack[2].InstanceID()})
+	require.NoError(t, err)
+	require.False(t, result.IsError, text(result))
+	assert.Contains(t, text(result), "Now showing 'calm'")
+	mu.Lock()
+	assert.Equal(t, []string{"top_init=1&top_bg=0000ff"}, queries)
+	mu.Unlock()
+	event = <-sub.Channel
+	assert.Equal(t, events.EventEffectStopped, event.Type)
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"instanceId": stack[2].InstanceID()})
+	require.NoError(t, err)
+	toolErr, failed := ErrorOf(result)
+	require.True(t, failed)
+	assert.Equal(t, CodeNotFound, toolErr.Code)
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	toolErr, failed = ErrorOf(result)
+	require.True(t, failed)
+	assert.Equal(t, CodeMissingArgument, toolErr.Code)
+}
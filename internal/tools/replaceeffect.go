@@ -0,0 +1,211 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// ReplaceEffectTool implements the replaceEffect MCP tool
+type ReplaceEffectTool struct {
+	broadcaster  *events.Broadcaster
+	store        *effects.Store
+	stateManager *state.Manager
+	engine       *effects.Engine
+	restores     *Restores
+}
+
+// NewReplaceEffectTool creates a new replaceEffect tool instance
+func NewReplaceEffectTool(broadcaster *events.Broadcaster, store *effects.Store, stateManager *state.Manager, engine *effects.Engine, restores *Restores) *ReplaceEffectTool {
+	return &ReplaceEffectTool{
+		broadcaster:  broadcaster,
+		store:        store,
+		stateManager: stateManager,
+		engine:       engine,
+		restores:     restores,
+	}
+}
+
+// Definition returns the MCP tool definition for replaceEffect
+func (t *ReplaceEffectTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "replaceEffect",
+		Description: "Swap the running effect for another in one step, without the flash of the previous effect that stopEffect followed by playEffect shows. The stack depth is unchanged. By default the new effect runs for its own duration; timing 'inherit' gives it the time the replaced effect had left. Raised alerts cannot be replaced.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the effect to show instead of the current one",
+				},
+				"duration": map[string]interface{}{
+					"type":        "number",
+					"description": "Override duration in milliseconds (optional, not allowed with timing 'inherit')",
+				},
+				"params": map[string]interface{}{
+					"type":                 "object",
+					"description":          "Values for the effect's parameters (optional, see playEffect)",
+					"additionalProperties": map[string]interface{}{"type": []string{"string", "number"}},
+				},
+				"timing": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"new", "inherit"},
+					"description": "'new' (default) runs the effect for its own duration; 'inherit' keeps the replaced effect's remaining time, or keeps it running until stopped if it was perpetual",
+				},
+			},
+			Required: []string{"name"},
+		},
+	}
+}
+
+// Execute runs the replaceEffect tool
+func (t *ReplaceEffectTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, ok := arguments["name"].(string)
+	if !ok || name == "" {
+		return missingArgument("name", "'name' parameter is required and must be a non-empty string"), nil
+	}
+	effect, exists := t.store.Get(name)
+	if !exists {
+		return effectNotFound("name", name), nil
+	}
+
+	timing := "new"
+	if value, exists := arguments["timing"]; exists {
+		timing, _ = value.(string)
+		if timing != "new" && timing != "inherit" {
+			return invalidArgument("timing", "'timing' must be 'new' or 'inherit'"), nil
+		}
+	}
+	duration := effect.Duration
+	if value, exists := arguments["duration"]; exists {
+		if timing == "inherit" {
+			return invalidArgument("duration", "'duration' cannot be combined with timing 'inherit'"), nil
+		}
+		ms, ok := value.(float64)
+		if !ok || ms < 0 || ms > 3600000 {
+			return invalidArgument("duration", "'duration' must be between 0 and 3600000 milliseconds (1 hour)"), nil
+		}
+		duration = int(ms)
+	}
+
+	var values map[string]string
+	if paramsVal, hasParams := arguments["params"]; hasParams {
+		var err error
+		if values, err = paramValues(paramsVal); err != nil {
+			return invalidArgument("params", err.Error()), nil
+		}
+	}
+	effect, err := effect.Render(values)
+	if err != nil {
+		return invalidArgument("params", err.Error()), nil
+	}
+
+	current := t.stateManager.GetCurrentEffect()
+	if current == nil {
+		return ToolError{Code: CodeConflict, Message: "no effect is running", Hint: "Use playEffect to start one"}.Result(), nil
+	}
+	if alert := activeAlert(t.stateManager); alert != nil {
+		if raised := raisedAlertName(*alert); raised != "" {
+			return ToolError{Code: CodeConflict, Message: fmt.Sprintf("'%s' is showing alert '%s'", alert.Name, raised), Hint: "Use clearAlert to clear it"}.Result(), nil
+		}
+		return toolError(CodeConflict, fmt.Sprintf("'%s' is an alert and clears when it resolves", alert.Name)), nil
+	}
+
+	// Work out the new entry's timing
+	now := time.Now()
+	perpetual := effect.Perpetual
+	if timing == "inherit" {
+		perpetual = current.Perpetual()
+		duration = 0
+		if remaining, timed := current.Remaining(now); timed {
+			duration = int(remaining.Milliseconds())
+			if duration <= 0 {
+				return toolError(CodeConflict, fmt.Sprintf("'%s' has no time left to inherit", current.Name)), nil
+			}
+		}
+	}
+
+	effectContext := map[string]interface{}{
+		"instanceId": state.NewInstanceID(),
+		"duration":   duration,
+		"perpetual":  perpetual,
+		"startTime":  now,
+	}
+	if len(effect.Steps) > 0 {
+		effectContext["steps"] = effect.Steps
+	}
+	if len(values) > 0 {
+		effectContext["params"] = values
+	}
+	replacement := state.EffectStackItem{Name: name, Pattern: effect.FirstPattern(), Context: effectContext}
+
+	// Swap the entry in place, then show it; the replaced effect's timer
+	// finds its entry gone and ends
+	replaced, isTop := t.stateManager.ReplaceEffect(func(item state.EffectStackItem) bool {
+		return item.InstanceID() == current.InstanceID()
+	}, replacement)
+	if !replaced {
+		return toolError(CodeConflict, fmt.Sprintf("'%s' stopped before it could be replaced", current.Name)), nil
+	}
+	if isTop {
+		if err := t.engine.Apply(ctx, name, effect.Pattern, effect.Steps); err != nil {
+			t.stateManager.ReplaceEffect(func(item state.EffectStackItem) bool {
+				return item.InstanceID() == replacement.InstanceID()
+			}, *current)
+			return toolError(CodeDeviceError, fmt.Sprintf("Failed to send effect to UFO: %v", err)), nil
+		}
+	}
+
+	t.broadcaster.PublishPayload(ctx, events.EffectStopped{
+		Effect:     current.Name,
+		InstanceID: current.InstanceID(),
+		ReplacedBy: replacement.InstanceID(),
+		StackDepth: t.stateManager.GetEffectStackDepth(),
+	})
+	t.broadcaster.PublishPayload(ctx, events.EffectStarted{
+		Effect:     name,
+		InstanceID: replacement.InstanceID(),
+		Duration:   duration,
+		Pattern:    effect.FirstPattern(),
+		Steps:      len(effect.Steps),
+		Params:     values,
+		Replaced:   current.InstanceID(),
+		StackDepth: t.stateManager.GetEffectStackDepth(),
+	})
+
+	message := fmt.Sprintf("🔀 Replaced '%s' with '%s' (stack depth: %d)\n\n", current.Name, name, t.stateManager.GetEffectStackDepth())
+	message += fmt.Sprintf("• Instance: %s (replaced %s)\n", replacement.InstanceID(), current.InstanceID())
+	if perpetual {
+		message += "• Duration: Perpetual (runs until stopped)\n"
+	} else if duration > 0 {
+		if timing == "inherit" {
+			message += fmt.Sprintf("• Duration: %s inherited from '%s'\n", format.Millis(int64(duration)), current.Name)
+		} else {
+			message += fmt.Sprintf("• Duration: %s\n", format.Millis(int64(duration)))
+		}
+		message += fmt.Sprintf("• Will stop at: %s\n", format.Time(now.Add(time.Duration(duration)*time.Millisecond)))
+	} else {
+		message += "• Duration: Infinite (use stopEffect to stop)\n"
+	}
+	message += fmt.Sprintf("\nPattern sent: %s", effect.FirstPattern())
+
+	if duration > 0 && !perpetual {
+		t.restores.scheduleCompletion(ctx, replacement.InstanceID())
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceEffectTool_Execute(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	require.NoError(t, store.Add(&effects.Effect{Name: "calm", Description: "Calm", Pattern: "top_init=1&top=0|15|0000FF", Duration: 5000}))
+	require.NoError(t, store.Add(&effects.Effect{Name: "busy", Description: "Busy", Pattern: "top_init=1&top=0|15|FF0000", Perpetual: true}))
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	client := device.NewClient()
+	engine := effects.NewEngine(client)
+	tool := NewReplaceEffectTool(broadcaster, store, stateManager, engine, NewRestores(engine, broadcaster, stateManager))
+
+	t.Run("EmptyStack", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{"name": "calm"})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "playEffect")
+	})
+
+	t.Run("SwapsTopInPlace", func(t *testing.T) {
+		stateManager.PushEffect("base", "bottom_init=1", map[string]interface{}{"instanceId": "base0001", "perpetual": true})
+		stateManager.PushEffect("busy", "top_init=1&top=0|15|FF0000", map[string]interface{}{
+			"instanceId": "busy0001",
+			"duration":   60000,
+			"startTime":  time.Now().Add(-20 * time.Second),
+		})
+		mu.Lock()
+		queries = nil
+		mu.Unlock()
+
+		result, err := tool.Execute(context.Background(), map[string]interface{}{"name": "calm", "timing": "inherit"})
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "inherited from 'busy'")
+
+		stack := stateManager.GetEffectStack()
+		require.Len(t, stack, 2, "replacing must not change the stack depth")
+		assert.Equal(t, "base", stack[0].Name)
+		assert.Equal(t, "calm", stack[1].Name)
+		assert.NotEqual(t, "busy0001", stack[1].InstanceID())
+		assert.InDelta(t, 40000, stack[1].DurationMs(), 1000)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, []string{"top_init=1&top=0|15|0000FF"}, queries, "the effect beneath must not be restored in between")
+	})
+
+	t.Run("InheritPerpetual", func(t *testing.T) {
+		stateManager.PushEffect("busy", "top_init=1&top=0|15|FF0000", map[string]interface{}{"instanceId": "busy0002", "perpetual": true})
+
+		result, err := tool.Execute(context.Background(), map[string]interface{}{"name": "calm", "timing": "inherit"})
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+		assert.True(t, stateManager.GetCurrentEffect().Perpetual())
+	})
+
+	t.Run("RefusesAlerts", func(t *testing.T) {
+		stateManager.PushEffect("alert", "top_init=1", map[string]interface{}{"priority": 10, "raisedAlert": "build", "perpetual": true})
+
+		result, err := tool.Execute(context.Background(), map[string]interface{}{"name": "calm"})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "clearAlert")
+		assert.Equal(t, "alert", stateManager.GetCurrentEffect().Name)
+	})
+
+	t.Run("ValidationErrors", func(t *testing.T) {
+		for _, args := range []map[string]interface{}{
+			{},
+			{"name": "missing"},
+			{"name": "calm", "timing": "later"},
+			{"name": "calm", "timing": "inherit", "duration": float64(1000)},
+		} {
+			result, err := tool.Execute(context.Background(), args)
+			require.NoError(t, err)
+			assert.True(t, result.IsError, "expected an error for %v", args)
+		}
+	})
+}
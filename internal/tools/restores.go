@@ -0,0 +1,228 @@
+package tools
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// Restores ends timed stack entries once their duration has run out, not
+// counting time spent paused, and shows the entry beneath. A single loop
+// handles every timed entry of the stack in the order they are due, so
+// entries running out together are removed and the UFO restored one at a
+// time, and a restore finds its entry by instance ID: an entry stopped or
+// replaced meanwhile is skipped rather than taking another with it. The
+// server makes one for its effect stack and hands it to the tools that
+// start timed entries.
+type Restores struct {
+	engine       *effects.Engine
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+
+	mu      sync.Mutex
+	queue   restoreQueue
+	running bool          // whether the loop is running with Engine.Go
+	stopped bool          // set by Stop; nothing more is scheduled
+	wake    chan struct{} // signals the loop that an earlier restore was queued
+}
+
+// NewRestores creates the restore scheduler of an effect stack
+func NewRestores(engine *effects.Engine, broadcaster *events.Broadcaster, stateManager *state.Manager) *Restores {
+	return &Restores{
+		engine:       engine,
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// pendingRestore is a timed entry waiting in the scheduler
+type pendingRestore struct {
+	instanceID string
+	due        time.Time // when to look at the entry next
+	// ctx carries the values of the call that started the entry, such as
+	// its request ID, for the events of its countdown
+	ctx context.Context
+	// expired runs once the entry has run out and left the stack
+	expired func(ctx context.Context, item state.EffectStackItem)
+}
+
+// restoreQueue is a min-heap of pending restores ordered by when they are due
+type restoreQueue []*pendingRestore
+
+func (q restoreQueue) Len() int           { return len(q) }
+func (q restoreQueue) Less(i, j int) bool { return q[i].due.Before(q[j].due) }
+func (q restoreQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *restoreQueue) Push(x any)        { *q = append(*q, x.(*pendingRestore)) }
+func (q *restoreQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// scheduleCompletion ends the timed effect with instanceID once it runs out,
+// publishing its countdown meanwhile and effect_completed when it ends. ctx
+// carries the request ID of the call that started the effect.
+func (s *Restores) scheduleCompletion(ctx context.Context, instanceID string) {
+	s.schedule(ctx, instanceID, func(ctx context.Context, item state.EffectStackItem) {
+		s.broadcaster.PublishPayload(ctx, events.EffectCompleted{
+			Effect:     item.Name,
+			InstanceID: item.InstanceID(),
+			StackDepth: s.stateManager.GetEffectStackDepth(),
+		})
+	})
+}
+
+// scheduleExpiry resolves the timed alert with instanceID once it runs out,
+// unless it is cleared or replaced first
+func (s *Restores) scheduleExpiry(ctx context.Context, name, instanceID string) {
+	s.schedule(ctx, instanceID, func(ctx context.Context, item state.EffectStackItem) {
+		s.broadcaster.PublishPayload(ctx, events.AlertResolved{
+			Source:     alertSource,
+			Key:        name,
+			Name:       name,
+			InstanceID: item.InstanceID(),
+			Expired:    true,
+		})
+	})
+}
+
+// schedule queues the entry with instanceID, starting the loop if it is not
+// running. The entry is looked at right away, publishing its first progress
+// event.
+func (s *Restores) schedule(ctx context.Context, instanceID string, expired func(ctx context.Context, item state.EffectStackItem)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+
+	heap.Push(&s.queue, &pendingRestore{
+		instanceID: instanceID,
+		due:        time.Now(),
+		ctx:        context.WithoutCancel(ctx),
+		expired:    expired,
+	})
+	if s.running {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+		return
+	}
+	s.running = s.engine.Go(ctx, s.run)
+}
+
+// Stop drops the pending restores and ends the loop, leaving the stack as
+// it is, so no timed entry changes the UFO or the saved stack while the
+// server exits
+func (s *Restores) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	s.queue = nil
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run handles restores as they fall due until none are left, or ctx is
+// cancelled as the server shuts down, leaving the stack as it is. It runs
+// with Engine.Go, so the engine counts it as a timer only while entries wait.
+func (s *Restores) run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.running = false
+			s.mu.Unlock()
+			return
+		}
+		wait := time.Until(s.queue[0].due)
+		if wait <= 0 {
+			next := heap.Pop(&s.queue).(*pendingRestore)
+			s.mu.Unlock()
+			s.handle(ctx, next)
+			continue
+		}
+		s.mu.Unlock()
+
+		timer.Reset(wait)
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			s.running = false
+			s.mu.Unlock()
+			return
+		case <-s.wake:
+			// A stale tick left by Stop only makes the loop look again
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// handle looks at a restore that has fallen due: it is dropped if its entry
+// left the stack, queued again while the entry is paused or still running,
+// and otherwise removes the entry and restores the UFO. Calls made for it
+// carry the values of its own ctx but end when the loop's ctx does.
+func (s *Restores) handle(loopCtx context.Context, next *pendingRestore) {
+	ctx, cancel := context.WithCancel(next.ctx)
+	defer cancel()
+	defer context.AfterFunc(loopCtx, cancel)()
+
+	item := s.stateManager.FindInstance(next.instanceID)
+	if item == nil {
+		return
+	}
+
+	now := time.Now()
+	if item.Paused() {
+		s.requeue(next, now.Add(pausePollInterval))
+		return
+	}
+	remaining, _ := item.Remaining(now)
+	if remaining > 0 {
+		elapsed := int(item.Elapsed(now).Milliseconds())
+		s.broadcaster.PublishPayload(ctx, events.Progress{
+			Effect:     item.Name,
+			InstanceID: next.instanceID,
+			Elapsed:    elapsed,
+			Remaining:  max(item.DurationMs()-elapsed, 0),
+			Total:      item.DurationMs(),
+		})
+		s.requeue(next, now.Add(min(remaining, progressInterval)))
+		return
+	}
+
+	// Remove the entry wherever it is; an alert may have been raised above it
+	removed, topRemoved := s.stateManager.RemoveEffects(func(candidate state.EffectStackItem) bool {
+		return candidate.InstanceID() == next.instanceID
+	})
+	if removed == 0 {
+		return
+	}
+	if topRemoved {
+		restoreTop(ctx, s.engine, s.broadcaster, s.stateManager)
+	}
+	next.expired(ctx, *item)
+}
+
+// requeue puts a restore back in the queue to be looked at again at due
+func (s *Restores) requeue(next *pendingRestore, due time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	next.due = due
+	heap.Push(&s.queue, next)
+}
@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreScheduler_OverlappingTimers(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	require.NoError(t, store.Add(&effects.Effect{Name: "base", Description: "Base", Pattern: "top_init=1&top_bg=0000FF", Perpetual: true}))
+	require.NoError(t, store.Add(&effects.Effect{Name: "long", Description: "Long", Pattern: "top_init=1&top=0|15|FF0000", Duration: 400}))
+	require.NoError(t, store.Add(&effects.Effect{Name: "short", Description: "Short", Pattern: "top_init=1&top=0|15|00FF00", Duration: 200}))
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	sub := broadcaster.SubscribeFiltered("test", events.SubscriptionFilter{Types: []string{events.EventEffectCompleted}})
+	stateManager := state.NewManager(broadcaster)
+	client := device.NewClient()
+	engine := effects.NewEngine(client)
+	tool := NewPlayEffectTool(client, broadcaster, store, stateManager, engine, NewRestores(engine, broadcaster, stateManager))
+	play := func(name string) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{"name": name})
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	}
+
+	// The two timed effects run out at about the same moment; one loop
+	// counts both down
+	play("base")
+	play("long")
+	time.Sleep(200 * time.Millisecond)
+	play("short")
+	assert.Equal(t, 1, engine.Timers())
+
+	completed := map[string]int{}
+	timeout := time.After(2 * time.Second)
+	for len(completed) < 2 {
+		select {
+		case event := <-sub.Channel:
+			completed[event.Data["effect"].(string)]++
+		case <-timeout:
+			t.Fatalf("expected both effects to complete, got %v", completed)
+		}
+	}
+	assert.Equal(t, map[string]int{"long": 1, "short": 1}, completed)
+
+	// Neither restore shows an effect the other removed: the base effect
+	// is left on the stack and on the UFO
+	require.Eventually(t, func() bool { return engine.Timers() == 0 }, time.Second, 10*time.Millisecond)
+	current := stateManager.GetCurrentEffect()
+	require.NotNil(t, current)
+	assert.Equal(t, "base", current.Name)
+	assert.Equal(t, 1, stateManager.GetEffectStackDepth())
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "top_init=1&top_bg=0000FF", queries[len(queries)-1])
+}
+
+func TestRestoreScheduler_SkipsReplacedEntries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	engine := effects.NewEngine(device.NewClient())
+
+	startTime := time.Now()
+	stateManager.PushEffect("first", "top_init=1", map[string]interface{}{"instanceId": "a", "startTime": startTime, "duration": 100})
+	NewRestores(engine, broadcaster, stateManager).scheduleCompletion(context.Background(), "a")
+
+	// An entry taking the stopped one's place with the same start time
+	// keeps running for its own duration
+	stateManager.RemoveEffects(func(item state.EffectStackItem) bool { return item.InstanceID() == "a" })
+	stateManager.PushEffect("second", "top_init=1", map[string]interface{}{"instanceId": "b", "startTime": startTime, "duration": 60000})
+
+	require.Eventually(t, func() bool { return engine.Timers() == 0 }, time.Second, 10*time.Millisecond)
+	assert.NotNil(t, stateManager.FindInstance("b"))
+}
+
+func TestRestores_Stop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	engine := effects.NewEngine(device.NewClient())
+	restores := NewRestores(engine, broadcaster, stateManager)
+
+	stateManager.PushEffect("first", "top_init=1", map[string]interface{}{"instanceId": "a", "startTime": time.Now(), "duration": 100})
+	restores.scheduleCompletion(context.Background(), "a")
+	require.Eventually(t, func() bool { return engine.Timers() == 1 }, time.Second, 10*time.Millisecond)
+
+	// Stopping leaves the entry for the saved stack and schedules nothing more
+	restores.Stop()
+	require.Eventually(t, func() bool { return engine.Timers() == 0 }, time.Second, 10*time.Millisecond)
+	restores.scheduleCompletion(context.Background(), "a")
+	time.Sleep(200 * time.Millisecond)
+	assert.NotNil(t, stateManager.FindInstance("a"))
+	assert.Equal(t, 0, engine.Timers())
+}
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// ResumeReport describes what ResumeEffects did with a saved stack
+type ResumeReport struct {
+	Resumed []string // entries put back on the stack, bottom first
+	Expired []string // timed entries whose duration ran out while the server was down
+	Timers  int      // resumed entries with a countdown
+}
+
+// ResumeEffects puts an effect stack saved before a restart back in place.
+// Timed effects and alerts whose duration ran out while the server was down
+// are dropped, the rest resume their countdown from where it stands now, and
+// the top of the stack is sent to the UFO again so it no longer shows an
+// effect nothing will stop. Sequences hold the step they had reached until
+// their total duration ends.
+func ResumeEffects(ctx context.Context, saved []state.EffectStackItem, engine *effects.Engine, broadcaster *events.Broadcaster, stateManager *state.Manager, restores *Restores) (ResumeReport, error) {
+	var report ResumeReport
+	if len(saved) == 0 {
+		return report, nil
+	}
+
+	now := time.Now()
+	var kept, expired []state.EffectStackItem
+	for _, item := range saved {
+		if err := decodeSteps(item.Context); err != nil {
+			return report, fmt.Errorf("effect '%s': %w", item.Name, err)
+		}
+		if remaining, timed := item.Remaining(now); timed && remaining <= 0 && !item.Paused() {
+			expired = append(expired, item)
+			report.Expired = append(report.Expired, item.Name)
+			continue
+		}
+		kept = append(kept, item)
+		report.Resumed = append(report.Resumed, item.Name)
+	}
+	stateManager.RestoreStack(kept)
+
+	if err := restoreTop(ctx, engine, broadcaster, stateManager); err != nil {
+		return report, err
+	}
+
+	for _, item := range expired {
+		if name := raisedAlertName(item); name != "" {
+			broadcaster.PublishPayload(ctx, events.AlertResolved{
+				Source:     alertSource,
+				Key:        name,
+				Name:       name,
+				InstanceID: item.InstanceID(),
+				Expired:    true,
+			})
+			continue
+		}
+		broadcaster.PublishPayload(ctx, events.EffectCompleted{
+			Effect:     item.Name,
+			InstanceID: item.InstanceID(),
+			StackDepth: stateManager.GetEffectStackDepth(),
+		})
+	}
+
+	// Read the entries back, as restoring gives those saved without an
+	// instance ID one
+	for _, item := range stateManager.GetEffectStack() {
+		if item.Perpetual() {
+			continue
+		}
+		report.Timers++
+		if name := raisedAlertName(item); name != "" {
+			restores.scheduleExpiry(ctx, name, item.InstanceID())
+		} else {
+			restores.scheduleCompletion(ctx, item.InstanceID())
+		}
+	}
+	return report, nil
+}
+
+// decodeSteps converts the multi-step frames of a saved stack entry back to
+// effect steps
+func decodeSteps(context map[string]interface{}) error {
+	value, exists := context["steps"]
+	if !exists {
+		return nil
+	}
+	if _, ok := value.([]effects.Step); ok {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encoding steps: %w", err)
+	}
+	var steps []effects.Step
+	if err := json.Unmarshal(data, &steps); err != nil {
+		return fmt.Errorf("decoding steps: %w", err)
+	}
+	context["steps"] = steps
+	return nil
+}
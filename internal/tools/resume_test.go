@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumeEffects(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+	lastQuery := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(queries) == 0 {
+			return ""
+		}
+		return queries[len(queries)-1]
+	}
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	engine := effects.NewEngine(device.NewClient())
+
+	// The server stopped an hour ago: the alert ran out meanwhile, the timed
+	// effect has 150ms left and the perpetual effect beneath it keeps going
+	stopped := time.Now().Add(-time.Hour)
+	saved := []state.EffectStackItem{
+		{Name: "calm", Pattern: "top_init=1&top_bg=0000ff", Context: map[string]interface{}{"perpetual": true, "startTime": stopped}},
+		{Name: "rainbow", Pattern: "top_init=1&top_bg=ff00ff", Context: map[string]interface{}{
+			"duration":  150,
+			"startTime": time.Now(),
+		}},
+		{Name: "alert:outage", Pattern: "top_init=1&top_bg=ff0000", Context: map[string]interface{}{
+			"duration":    60000,
+			"priority":    50,
+			"raisedAlert": "outage",
+			"startTime":   stopped,
+		}},
+	}
+
+	report, err := ResumeEffects(context.Background(), saved, engine, broadcaster, stateManager, NewRestores(engine, broadcaster, stateManager))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"calm", "rainbow"}, report.Resumed)
+	assert.Equal(t, []string{"alert:outage"}, report.Expired)
+	assert.Equal(t, 1, report.Timers)
+	assert.Equal(t, "rainbow", stateManager.GetCurrentEffect().Name)
+	assert.Equal(t, "top_init=1&top_bg=ff00ff", lastQuery())
+
+	// The resumed timer completes the effect and shows the one beneath it
+	assert.Eventually(t, func() bool {
+		current := stateManager.GetCurrentEffect()
+		return current != nil && current.Name == "calm" && lastQuery() == "top_init=1&top_bg=0000ff"
+	}, 2*time.Second, 20*time.Millisecond)
+
+	// Multi-step frames decoded from JSON become effect steps again
+	item := state.EffectStackItem{Name: "pulse", Context: map[string]interface{}{
+		"steps": []interface{}{map[string]interface{}{"pattern": "top_bg=ff0000", "durationMs": float64(500)}},
+	}}
+	require.NoError(t, decodeSteps(item.Context))
+	assert.Equal(t, []effects.Step{{Pattern: "top_bg=ff0000", DurationMs: 500}}, effects.StepsFromContext(item.Context))
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// ResumeEffectTool implements the resumeEffect MCP tool
+type ResumeEffectTool struct {
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+	engine       *effects.Engine
+}
+
+// NewResumeEffectTool creates a new resumeEffect tool instance
+func NewResumeEffectTool(broadcaster *events.Broadcaster, stateManager *state.Manager, engine *effects.Engine) *ResumeEffectTool {
+	return &ResumeEffectTool{
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		engine:       engine,
+	}
+}
+
+// Definition returns the MCP tool definition for resumeEffect
+func (t *ResumeEffectTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "resumeEffect",
+		Description: "Resume the current effect after pauseEffect. The remaining duration continues counting down from where it was paused.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+		},
+	}
+}
+
+// Execute runs the resumeEffect tool
+func (t *ResumeEffectTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	item, err := t.stateManager.ResumeEffect()
+	if err != nil {
+		return toolError(CodeConflict, fmt.Sprintf("%v", err)), nil
+	}
+
+	// Restart multi-step animations
+	if steps := effects.StepsFromContext(item.Context); len(steps) > 0 {
+		if err := t.engine.Apply(ctx, item.Name, item.Pattern, steps); err != nil {
+			return toolError(CodeDeviceError, fmt.Sprintf("Resumed timer but failed to restart animation: %v", err)), nil
+		}
+	}
+
+	resumed := events.EffectResumed{
+		Effect:     item.Name,
+		InstanceID: item.InstanceID(),
+		Unpaused:   true,
+		StackDepth: t.stateManager.GetEffectStackDepth(),
+	}
+	message := fmt.Sprintf("▶️ Resumed '%s'", item.Name)
+	if remaining, timed := item.Remaining(time.Now()); timed {
+		resumed.RemainingMs = remaining.Milliseconds()
+		message += fmt.Sprintf(" with %s remaining", format.Duration(remaining))
+	}
+	t.broadcaster.PublishPayload(ctx, resumed)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+)
+
+// RollbackEffectTool implements the rollbackEffect MCP tool
+type RollbackEffectTool struct {
+	store *effects.Store
+}
+
+// NewRollbackEffectTool creates a new rollbackEffect tool instance
+func NewRollbackEffectTool(store *effects.Store) *RollbackEffectTool {
+	return &RollbackEffectTool{
+		store: store,
+	}
+}
+
+// Definition returns the MCP tool definition for rollbackEffect
+func (t *RollbackEffectTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "rollbackEffect",
+		Description: "Bring back an earlier version of a stored effect, listed by listEffectRevisions, undoing an updateEffect, deleteEffect or importEffects. A deleted effect is restored. The version it replaces is kept, so a rollback can be rolled back too. Running effects are not changed until played again.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the effect",
+				},
+				"version": map[string]interface{}{
+					"type":        "integer",
+					"description": "Version to bring back, from listEffectRevisions",
+					"minimum":     1,
+				},
+			},
+			Required: []string{"name", "version"},
+		},
+	}
+}
+
+// Execute runs the rollbackEffect tool
+func (t *RollbackEffectTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, ok := arguments["name"].(string)
+	if !ok || name == "" {
+		return missingArgument("name", "'name' parameter is required and must be a non-empty string"), nil
+	}
+	version, ok := wholeNumber(arguments["version"])
+	if !ok || version < 1 {
+		return missingArgument("version", "'version' parameter is required and must be a whole number from 1"), nil
+	}
+
+	_, current := t.store.Revisions(name)
+	effect, err := t.store.Rollback(name, version)
+	if err != nil {
+		return toolError(CodeNotFound, fmt.Sprintf("Failed to roll back effect: %v", err)), nil
+	}
+
+	message := fmt.Sprintf("⏪ Rolled back effect '%s' to version %d\n", name, version)
+	if current == 0 {
+		message = fmt.Sprintf("⏪ Restored deleted effect '%s' from version %d\n", name, version)
+	} else {
+		message += fmt.Sprintf("Version %d was kept; roll back to it to undo this.\n", current)
+	}
+	message += "\nCurrent values:\n"
+	message += fmt.Sprintf("• Description: %s\n", effect.Description)
+	message += fmt.Sprintf("• Pattern: %s\n", describeRevisionPattern(*effect))
+	message += fmt.Sprintf("• Duration: %dms", effect.Duration)
+	if effect.Duration == 0 {
+		message += " (infinite)"
+	}
+	if len(effect.Params) > 0 {
+		message += fmt.Sprintf("\n• Parameters: %s", formatEffectParams(effect.Params))
+	}
+	message += formatEffectLabels(effect)
+	return revisionResult(message), nil
+}
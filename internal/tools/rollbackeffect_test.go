@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+)
+
+func TestRollbackEffectTool_UndoesUpdate(t *testing.T) {
+	store := effects.NewStore(t.TempDir() + "/effects.json")
+	store.Add(&effects.Effect{Name: "deployGlow", Description: "Tuned glow", Pattern: "top=0|15|FF8800"})
+
+	result, _ := NewUpdateEffectTool(store).Execute(context.Background(), map[string]interface{}{
+		"name":    "deployGlow",
+		"pattern": "top=0|15|FFFFFF",
+	})
+	if result.IsError {
+		t.Fatalf("updateEffect failed: %v", result.Content)
+	}
+
+	result, _ = NewListEffectRevisionsTool(store).Execute(context.Background(), map[string]interface{}{"name": "deployGlow"})
+	text := result.Content[0].(mcp.TextContent).Text
+	if result.IsError || !strings.Contains(text, "v2: current") || !strings.Contains(text, "v1: updated") || !strings.Contains(text, "top=0|15|FF8800") {
+		t.Errorf("expected version 1 listed as updated, got:\n%s", text)
+	}
+
+	tool := NewRollbackEffectTool(store)
+	result, _ = tool.Execute(context.Background(), map[string]interface{}{"name": "deployGlow", "version": float64(1)})
+	text = result.Content[0].(mcp.TextContent).Text
+	if result.IsError || !strings.Contains(text, "Rolled back effect 'deployGlow' to version 1") || !strings.Contains(text, "Version 2 was kept") {
+		t.Fatalf("expected the rollback reported, got:\n%s", text)
+	}
+	if effect, _ := store.Get("deployGlow"); effect.Pattern != "top=0|15|FF8800" {
+		t.Errorf("expected the tuned pattern back, got %s", effect.Pattern)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"name": "deployGlow"},
+		{"name": "deployGlow", "version": 1.5},
+		{"name": "deployGlow", "version": float64(3)},
+		{"name": "missing", "version": float64(1)},
+	} {
+		if result, _ := tool.Execute(context.Background(), args); !result.IsError {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestRollbackEffectTool_RestoresDeletedEffect(t *testing.T) {
+	store := effects.NewStore(t.TempDir() + "/effects.json")
+	store.Add(&effects.Effect{Name: "oldAlert", Description: "Alert", Pattern: "top=0|15|FF0000"})
+	if result, _ := NewDeleteEffectTool(store).Execute(context.Background(), map[string]interface{}{"name": "oldAlert"}); result.IsError {
+		t.Fatalf("deleteEffect failed: %v", result.Content)
+	}
+
+	result, _ := NewListEffectRevisionsTool(store).Execute(context.Background(), map[string]interface{}{})
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "oldAlert: 1 earlier version(s), deleted") {
+		t.Errorf("expected the deleted effect listed, got:\n%s", text)
+	}
+
+	result, _ = NewRollbackEffectTool(store).Execute(context.Background(), map[string]interface{}{"name": "oldAlert", "version": float64(1)})
+	if text := result.Content[0].(mcp.TextContent).Text; result.IsError || !strings.Contains(text, "Restored deleted effect 'oldAlert'") {
+		t.Fatalf("expected the effect restored, got:\n%s", text)
+	}
+	if _, exists := store.Get("oldAlert"); !exists {
+		t.Error("expected oldAlert stored again")
+	}
+}
@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/macros"
+)
+
+// RunMacroTool implements the runMacro MCP tool
+type RunMacroTool struct {
+	store   *macros.Store
+	caller  ToolCaller
+	mu      sync.Mutex
+	running string // name of the macro being run, empty when none
+}
+
+// NewRunMacroTool creates a new runMacro tool instance
+func NewRunMacroTool(store *macros.Store, caller ToolCaller) *RunMacroTool {
+	return &RunMacroTool{
+		store:  store,
+		caller: caller,
+	}
+}
+
+// Definition returns the MCP tool definition for runMacro
+func (t *RunMacroTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "runMacro",
+		Description: "Run a macro saved with defineMacro: its tool calls are made in order on the server, one macro at a time, so they are not interleaved with another macro's. params fill in the macro's {name} placeholders. A failing step stops the macro unless the step was defined with onError 'continue'; steps already made are not undone. Each step is subject to the same checks as a direct call.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the macro to run",
+				},
+				"params": map[string]interface{}{
+					"type":        "object",
+					"description": "Values for the macro's parameters by name, e.g. {\"env\": \"prod\", \"color\": \"green\"} (optional for parameters with defaults)",
+				},
+			},
+			Required: []string{"name"},
+		},
+	}
+}
+
+// Execute runs the runMacro tool
+func (t *RunMacroTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, ok := arguments["name"].(string)
+	if !ok || name == "" {
+		return invalidArgument("name", "'name' must be a non-empty string"), nil
+	}
+	macro, ok := t.store.Get(name)
+	if !ok {
+		return toolError(CodeNotFound, fmt.Sprintf("no macro named '%s'", name)), nil
+	}
+	var values map[string]interface{}
+	if value, exists := arguments["params"]; exists {
+		values, ok = value.(map[string]interface{})
+		if !ok {
+			return invalidArgument("params", "'params' must be an object of parameter values"), nil
+		}
+	}
+	steps, err := macro.Render(values)
+	if err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+
+	t.mu.Lock()
+	if t.running != "" {
+		running := t.running
+		t.mu.Unlock()
+		return toolError(CodeConflict, fmt.Sprintf("macro '%s' is still running; try again when it finishes", running)), nil
+	}
+	t.running = macro.Name
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		t.running = ""
+		t.mu.Unlock()
+	}()
+
+	lines := []string{fmt.Sprintf("Running macro '%s'", macro.Name)}
+	stopped := false
+	failures := 0
+	var stopError ToolError
+	for i, step := range steps {
+		if err := ctx.Err(); err != nil {
+			lines = append(lines, fmt.Sprintf("Stopped before step %d: %v", i+1, err))
+			stopped = true
+			stopError = ToolError{Code: CodeInternal, Message: fmt.Sprintf("macro '%s' stopped before step %d: %v", macro.Name, i+1, err)}
+			break
+		}
+		arguments := step.Arguments
+		if arguments == nil {
+			arguments = map[string]interface{}{}
+		}
+		result, err := t.caller.CallTool(ctx, step.Tool, arguments)
+		var text string
+		failed := false
+		stepError := ToolError{Code: CodeInternal}
+		switch {
+		case err != nil:
+			text = err.Error()
+			failed = true
+		case result == nil:
+			text = "no result"
+			failed = true
+		default:
+			text = firstLine(toolResultText(result))
+			stepError, failed = ErrorOf(result)
+		}
+		status := "ok"
+		if failed {
+			status = "failed"
+		}
+		lines = append(lines, fmt.Sprintf("%d. %s: %s - %s", i+1, step.Tool, status, text))
+		if !failed {
+			continue
+		}
+		failures++
+		if step.OnError == macros.OnErrorContinue {
+			continue
+		}
+		stopped = true
+		// Report why the step failed, so clients can act on it as if they
+		// had made the call
+		stopError = stepError
+		stopError.Message = fmt.Sprintf("macro '%s' step %d (%s): %s", macro.Name, i+1, step.Tool, strings.TrimPrefix(text, "Error: "))
+		if skipped := len(steps) - i - 1; skipped > 0 {
+			lines = append(lines, fmt.Sprintf("Stopped; %d remaining step(s) not run", skipped))
+		}
+		break
+	}
+	if failures > 0 && !stopped {
+		lines = append(lines, fmt.Sprintf("Finished with %d failed step(s)", failures))
+	}
+
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: strings.Join(lines, "\n"),
+			},
+		},
+		IsError: stopped,
+	}
+	if stopped {
+		result.Meta = map[string]any{"error": stopError}
+	}
+	return result, nil
+}
+
+// toolResultText returns the text of a tool result
+func toolResultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// firstLine returns the first line of text
+func firstLine(text string) string {
+	text = strings.TrimSpace(text)
+	if line, _, found := strings.Cut(text, "\n"); found {
+		return line
+	}
+	return text
+}
@@ -0,0 +1,406 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+	"github.com/starspace46/ufo-mcp-go/internal/palettes"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// maxSequenceSteps caps the number of steps in one sequence
+const maxSequenceSteps = 50
+
+// minSequenceStepMs is the shortest step, to avoid flooding the UFO
+const minSequenceStepMs = 100
+
+// maxSequenceMs caps the total running time of a sequence, repeats included
+const maxSequenceMs = 3600000
+
+// sequenceStep is a validated step of a sequence
+type sequenceStep struct {
+	label      string          // description for the tool result
+	pattern    string          // query sent when the step starts
+	frames     []effects.Step  // animation frames of a multi-step effect
+	lighting   *lightingConfig // set for configureLighting-style steps
+	durationMs int
+}
+
+// RunSequenceTool implements the runSequence MCP tool
+type RunSequenceTool struct {
+	broadcaster  *events.Broadcaster
+	store        *effects.Store
+	stateManager *state.Manager
+	engine       *effects.Engine
+	lighting     *ConfigureLightingTool
+}
+
+// NewRunSequenceTool creates a new runSequence tool instance
+func NewRunSequenceTool(client *device.Client, broadcaster *events.Broadcaster, store *effects.Store, stateManager *state.Manager, engine *effects.Engine, palettes *palettes.Store) *RunSequenceTool {
+	return &RunSequenceTool{
+		broadcaster:  broadcaster,
+		store:        store,
+		stateManager: stateManager,
+		engine:       engine,
+		lighting:     NewConfigureLightingTool(client, broadcaster, stateManager, engine, palettes),
+	}
+}
+
+// Definition returns the MCP tool definition for runSequence
+func (t *RunSequenceTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "runSequence",
+		Description: "Play an ordered list of steps, each a stored effect or a configureLighting-style configuration shown for durationMs, in one call. The sequence runs as a single effect on the stack: stopEffect cancels it, pauseEffect/resumeEffect suspend it, and progress events are published as each step starts. Returns immediately while the sequence plays.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name shown on the effect stack (default 'sequence')",
+				},
+				"steps": map[string]interface{}{
+					"type":        "array",
+					"description": fmt.Sprintf("Steps to play in order (1-%d). Each step sets exactly one of 'effect' or 'lighting'", maxSequenceSteps),
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"effect": map[string]interface{}{
+								"type":        "string",
+								"description": "Name of a stored effect to show",
+							},
+							"lighting": map[string]interface{}{
+								"type":        "object",
+								"description": "Lighting to show, with the same top, bottom, logo and brightness options as configureLighting",
+							},
+							"durationMs": map[string]interface{}{
+								"type":        "number",
+								"description": fmt.Sprintf("How long the step is shown in milliseconds (at least %d)", minSequenceStepMs),
+							},
+						},
+						"required": []string{"durationMs"},
+					},
+				},
+				"repeat": map[string]interface{}{
+					"type":        "number",
+					"description": "How many times to play the steps (default 1)",
+				},
+			},
+			Required: []string{"steps"},
+		},
+	}
+}
+
+// Execute runs the runSequence tool
+func (t *RunSequenceTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name := "sequence"
+	if value, exists := arguments["name"]; exists {
+		n, ok := value.(string)
+		if !ok || n == "" {
+			return invalidArgument("name", "'name' must be a non-empty string"), nil
+		}
+		name = n
+	}
+
+	repeat := 1
+	if value, exists := arguments["repeat"]; exists {
+		r, ok := value.(float64)
+		if !ok || r < 1 || r != float64(int(r)) {
+			return invalidArgument("repeat", "'repeat' must be a positive whole number"), nil
+		}
+		repeat = int(r)
+	}
+
+	steps, err := t.parseSteps(arguments["steps"])
+	if err != nil {
+		return invalidArgument("steps", err.Error()), nil
+	}
+
+	cycleMs := 0
+	for _, step := range steps {
+		cycleMs += step.durationMs
+	}
+	if cycleMs*repeat > maxSequenceMs {
+		return invalidArgument("repeat", fmt.Sprintf("sequence would run for %dms; the limit is %dms (1 hour)", cycleMs*repeat, maxSequenceMs)), nil
+	}
+	totalMs := cycleMs * repeat
+	if full := stackFull(t.stateManager); full != nil {
+		return full, nil
+	}
+
+	// Show the first step synchronously so device errors reach the caller,
+	// unless a raised alert holds the UFO; the sequence then runs beneath it
+	first := steps[0]
+	alert := activeAlert(t.stateManager)
+	if alert == nil {
+		if err := t.engine.Apply(ctx, name, first.pattern, first.frames); err != nil {
+			return toolError(CodeDeviceError, fmt.Sprintf("Failed to send first step to UFO: %v", err)), nil
+		}
+		if first.lighting != nil {
+			t.lighting.updateState(first.lighting)
+		}
+	}
+
+	startTime := time.Now()
+	instanceID := state.NewInstanceID()
+	_, stale := t.stateManager.PushEffect(name, first.pattern, sequenceContext(map[string]interface{}{
+		"instanceId": instanceID,
+		"duration":   totalMs,
+		"perpetual":  false,
+		"startTime":  startTime,
+		"synthetic":  true,
+	}, first, 0))
+	restoreStale(ctx, stale, t.engine, t.broadcaster, t.stateManager)
+
+	t.broadcaster.PublishPayload(ctx, events.EffectStarted{
+		Effect:     name,
+		InstanceID: instanceID,
+		Duration:   totalMs,
+		Pattern:    first.pattern,
+		Sequence:   len(steps) * repeat,
+		StackDepth: t.stateManager.GetEffectStackDepth(),
+	})
+	t.publishProgress(ctx, name, 0, len(steps)*repeat, 0, totalMs)
+
+	t.engine.Go(ctx, func(ctx context.Context) {
+		t.run(ctx, name, startTime, steps, repeat)
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "🎬 Sequence '%s' started! (instance %s)\n\n", name, instanceID)
+	if alert != nil {
+		fmt.Fprintf(&b, "⏳ Running beneath alert '%s'; steps show once the alert is cleared or expires.\n\n", alert.Name)
+	}
+	for i, step := range steps {
+		fmt.Fprintf(&b, "%d. %s for %s\n", i+1, step.label, format.Millis(int64(step.durationMs)))
+	}
+	if repeat > 1 {
+		fmt.Fprintf(&b, "\nRepeating %d times", repeat)
+	}
+	fmt.Fprintf(&b, "\nTotal: %s, finishing at %s", format.Millis(int64(totalMs)),
+		format.Time(startTime.Add(time.Duration(totalMs)*time.Millisecond)))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: b.String(),
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// parseSteps validates the steps argument and resolves each step to the
+// query it sends
+func (t *RunSequenceTool) parseSteps(value interface{}) ([]sequenceStep, error) {
+	items, ok := value.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("'steps' must be a non-empty array")
+	}
+	if len(items) > maxSequenceSteps {
+		return nil, fmt.Errorf("'steps' has %d entries; the limit is %d", len(items), maxSequenceSteps)
+	}
+
+	steps := make([]sequenceStep, 0, len(items))
+	for i, item := range items {
+		args, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("step %d must be an object", i+1)
+		}
+
+		durationMs, ok := args["durationMs"].(float64)
+		if !ok || durationMs < minSequenceStepMs {
+			return nil, fmt.Errorf("step %d: 'durationMs' must be a number of at least %d", i+1, minSequenceStepMs)
+		}
+		step := sequenceStep{durationMs: int(durationMs)}
+
+		effectName, hasEffect := args["effect"]
+		lighting, hasLighting := args["lighting"]
+		switch {
+		case hasEffect && hasLighting:
+			return nil, fmt.Errorf("step %d: set either 'effect' or 'lighting', not both", i+1)
+		case hasEffect:
+			n, _ := effectName.(string)
+			effect, exists := t.store.Get(n)
+			if !exists {
+				return nil, fmt.Errorf("step %d: effect '%v' not found. Use listEffects to see available effects", i+1, effectName)
+			}
+			step.label = fmt.Sprintf("effect '%s'", n)
+			step.pattern = effect.Pattern
+			step.frames = effect.Steps
+		case hasLighting:
+			lightingArgs, ok := lighting.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("step %d: 'lighting' must be an object", i+1)
+			}
+			config, err := t.lighting.parseConfig(lightingArgs)
+			if err != nil {
+				return nil, fmt.Errorf("step %d: %v", i+1, err)
+			}
+			if config.query == "" {
+				return nil, fmt.Errorf("step %d: 'lighting' configures nothing", i+1)
+			}
+			if len(config.ringBrightness) > 0 {
+				return nil, fmt.Errorf("step %d: ring brightness cannot change during a sequence; set it with configureLighting first", i+1)
+			}
+			step.label = "lighting (" + strings.Join(config.messages, "; ") + ")"
+			step.pattern = config.query
+			step.lighting = config
+		default:
+			return nil, fmt.Errorf("step %d: set 'effect' or 'lighting'", i+1)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// run advances the sequence as its steps run out, not counting time spent
+// paused, then removes it from the stack. It returns early if the sequence
+// is stopped, or when ctx is cancelled as the server shuts down. It runs
+// with Engine.Go; ctx carries the request ID of the runSequence call.
+func (t *RunSequenceTool) run(ctx context.Context, name string, startTime time.Time, steps []sequenceStep, repeat int) {
+	total := len(steps) * repeat
+	current := 0
+	var instanceID string
+	for {
+		item := t.stateManager.FindEffect(startTime)
+		if item == nil {
+			return
+		}
+		instanceID = item.InstanceID()
+		if item.Paused() {
+			if !effects.Sleep(ctx, pausePollInterval) {
+				return
+			}
+			continue
+		}
+
+		elapsed := item.Elapsed(time.Now())
+		index, untilNext := sequencePosition(steps, repeat, elapsed)
+		if index < 0 {
+			break
+		}
+		if index != current {
+			current = index
+			if !t.showStep(ctx, name, startTime, steps[index%len(steps)], index) {
+				return
+			}
+			t.publishProgress(ctx, name, index, total, elapsed, item.DurationMs())
+		}
+		if !effects.Sleep(ctx, untilNext) {
+			return
+		}
+	}
+
+	removed, topRemoved := t.stateManager.RemoveEffects(func(item state.EffectStackItem) bool {
+		return item.StartTime().Equal(startTime)
+	})
+	if removed == 0 {
+		return
+	}
+
+	// Only touch the UFO when the sequence was showing; a buried sequence
+	// leaves the effect above it alone
+	if topRemoved {
+		if previous := t.stateManager.GetCurrentEffect(); previous != nil {
+			t.engine.Apply(ctx, previous.Name, previous.Pattern, effects.StepsFromContext(previous.Context))
+			t.broadcaster.PublishPayload(ctx, events.EffectResumed{
+				Effect:     previous.Name,
+				InstanceID: previous.InstanceID(),
+				StackDepth: t.stateManager.GetEffectStackDepth(),
+			})
+		} else {
+			t.engine.Apply(ctx, "", "top_init=1&bottom_init=1", nil)
+		}
+	}
+
+	t.broadcaster.PublishPayload(ctx, events.EffectCompleted{
+		Effect:     name,
+		InstanceID: instanceID,
+		StackDepth: t.stateManager.GetEffectStackDepth(),
+	})
+}
+
+// showStep records the sequence's new step on its stack entry and shows it
+// if the sequence is on top. It returns false if the sequence was stopped.
+func (t *RunSequenceTool) showStep(ctx context.Context, name string, startTime time.Time, step sequenceStep, index int) bool {
+	item := t.stateManager.FindEffect(startTime)
+	if item == nil {
+		return false
+	}
+	item.Pattern = step.pattern
+	item.Context = sequenceContext(item.Context, step, index)
+
+	replaced, isTop := t.stateManager.ReplaceEffect(func(candidate state.EffectStackItem) bool {
+		return candidate.StartTime().Equal(startTime)
+	}, *item)
+	if !replaced {
+		return false
+	}
+	if isTop {
+		if err := t.engine.Apply(ctx, name, step.pattern, step.frames); err != nil {
+			t.broadcaster.PublishRawExecuted(ctx, step.pattern, fmt.Sprintf("ERROR: %v", err))
+		} else if step.lighting != nil {
+			t.lighting.updateState(step.lighting)
+		}
+	}
+	return true
+}
+
+// publishProgress announces the step a sequence has reached
+func (t *RunSequenceTool) publishProgress(ctx context.Context, name string, index, total int, elapsed time.Duration, totalMs int) {
+	t.broadcaster.PublishPayload(ctx, events.Progress{
+		Effect:  name,
+		Step:    index + 1,
+		Steps:   total,
+		Elapsed: int(elapsed.Milliseconds()),
+		Total:   totalMs,
+	})
+}
+
+// sequenceContext copies a sequence stack entry's context for the step at
+// index, keeping multi-step effect frames so the step resumes correctly
+// after an effect above it is stopped
+func sequenceContext(context map[string]interface{}, step sequenceStep, index int) map[string]interface{} {
+	updated := make(map[string]interface{}, len(context)+2)
+	for k, v := range context {
+		updated[k] = v
+	}
+	updated["sequenceStep"] = index + 1
+	delete(updated, "steps")
+	if len(step.frames) > 0 {
+		updated["steps"] = step.frames
+	}
+	return updated
+}
+
+// sequencePosition returns the index of the step running after elapsed,
+// counting across repeats, and how long until the next step starts. The
+// index is -1 once the sequence has finished.
+func sequencePosition(steps []sequenceStep, repeat int, elapsed time.Duration) (int, time.Duration) {
+	cycle := time.Duration(0)
+	for _, step := range steps {
+		cycle += time.Duration(step.durationMs) * time.Millisecond
+	}
+	if cycle <= 0 || elapsed >= cycle*time.Duration(repeat) {
+		return -1, 0
+	}
+
+	pass := int(elapsed / cycle)
+	offset := elapsed % cycle
+	end := time.Duration(0)
+	for i, step := range steps {
+		end += time.Duration(step.durationMs) * time.Millisecond
+		if offset < end {
+			return pass*len(steps) + i, end - offset
+		}
+	}
+	return -1, 0
+}
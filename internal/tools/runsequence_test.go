@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSequencePosition(t *testing.T) {
+	steps := []sequenceStep{{durationMs: 100}, {durationMs: 300}}
+
+	index, next := sequencePosition(steps, 2, 0)
+	assert.Equal(t, 0, index)
+	assert.Equal(t, 100*time.Millisecond, next)
+
+	index, next = sequencePosition(steps, 2, 150*time.Millisecond)
+	assert.Equal(t, 1, index)
+	assert.Equal(t, 250*time.Millisecond, next)
+
+	// The second pass continues the step count
+	index, _ = sequencePosition(steps, 2, 450*time.Millisecond)
+	assert.Equal(t, 2, index)
+
+	index, _ = sequencePosition(steps, 2, 800*time.Millisecond)
+	assert.Equal(t, -1, index)
+}
+
+func TestRunSequenceTool(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := device.NewClient()
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	engine := effects.NewEngine(client)
+	store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+	require.NoError(t, store.Add(&effects.Effect{Name: "red", Pattern: "top_init=1&top_bg=ff0000"}))
+
+	tool := NewRunSequenceTool(client, broadcaster, store, stateManager, engine, nil)
+
+	t.Run("ValidationErrors", func(t *testing.T) {
+		for _, args := range []map[string]interface{}{
+			{},
+			{"steps": []interface{}{}},
+			{"steps": []interface{}{map[string]interface{}{"effect": "red"}}},
+			{"steps": []interface{}{map[string]interface{}{"effect": "missing", "durationMs": float64(500)}}},
+			{"steps": []interface{}{map[string]interface{}{"durationMs": float64(500)}}},
+			{"steps": []interface{}{map[string]interface{}{"lighting": map[string]interface{}{"brightness": float64(300)}, "durationMs": float64(500)}}},
+			{"steps": []interface{}{map[string]interface{}{"effect": "red", "durationMs": float64(500)}}, "repeat": float64(0)},
+			{"steps": []interface{}{map[string]interface{}{"effect": "red", "durationMs": float64(3000000)}}, "repeat": float64(2)},
+		} {
+			result, err := tool.Execute(context.Background(), args)
+			require.NoError(t, err)
+			assert.True(t, result.IsError, "expected error for %v", args)
+		}
+		assert.Equal(t, 0, stateManager.GetEffectStackDepth())
+	})
+
+	t.Run("RunsStepsInOrder", func(t *testing.T) {
+		sub := broadcaster.Subscribe("sequence-test")
+		defer broadcaster.Unsubscribe("sequence-test")
+
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"name": "countdown",
+			"steps": []interface{}{
+				map[string]interface{}{"effect": "red", "durationMs": float64(100)},
+				map[string]interface{}{"lighting": map[string]interface{}{"brightness": float64(10)}, "durationMs": float64(100)},
+			},
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+
+		current := stateManager.GetCurrentEffect()
+		require.NotNil(t, current)
+		assert.Equal(t, "countdown", current.Name)
+		assert.Equal(t, 200, current.DurationMs())
+
+		// The sequence removes itself and clears the UFO when done
+		deadline := time.Now().Add(2 * time.Second)
+		for stateManager.GetEffectStackDepth() != 0 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		require.Equal(t, 0, stateManager.GetEffectStackDepth())
+		assert.Equal(t, 10, stateManager.Snapshot().Dim)
+
+		mu.Lock()
+		sent := append([]string(nil), queries...)
+		mu.Unlock()
+		assert.Equal(t, []string{"top_init=1&top_bg=ff0000", "dim=10", "top_init=1&bottom_init=1"}, sent)
+
+		var steps []interface{}
+		timeout := time.After(time.Second)
+	collect:
+		for {
+			select {
+			case event := <-sub.Channel:
+				if event.Type == events.EventProgress {
+					steps = append(steps, event.Data["step"])
+				}
+				if event.Type == events.EventEffectCompleted {
+					break collect
+				}
+			case <-timeout:
+				break collect
+			}
+		}
+		assert.Equal(t, []interface{}{1, 2}, steps)
+	})
+
+	t.Run("StoppedSequenceEndsQuietly", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"steps": []interface{}{
+				map[string]interface{}{"effect": "red", "durationMs": float64(100)},
+				map[string]interface{}{"effect": "red", "durationMs": float64(100)},
+			},
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+
+		stateManager.PopEffect()
+		time.Sleep(300 * time.Millisecond)
+		assert.Equal(t, 0, stateManager.GetEffectStackDepth())
+	})
+}
@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/palettes"
+)
+
+// SavePaletteTool implements the savePalette MCP tool
+type SavePaletteTool struct {
+	store *palettes.Store
+}
+
+// NewSavePaletteTool creates a new savePalette tool instance
+func NewSavePaletteTool(store *palettes.Store) *SavePaletteTool {
+	return &SavePaletteTool{
+		store: store,
+	}
+}
+
+// Definition returns the MCP tool definition for savePalette
+func (t *SavePaletteTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "savePalette",
+		Description: "Save a named set of colors, such as brand colors, saved across restarts. configureLighting and composeRing can then use one of its colors as 'name:n' (counting from 1) or fill a ring with the whole palette. Saving under an existing name replaces that palette.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Palette name: letters, digits, '-' and '_'",
+					"examples":    []string{"xmas", "dynatrace-brand"},
+				},
+				"colors": map[string]interface{}{
+					"type":        "array",
+					"description": "Colors in order (hex RRGGBB, #RGB, rgb(r,g,b) or CSS color name)",
+					"items":       map[string]interface{}{"type": "string"},
+					"minItems":    1,
+					"maxItems":    palettes.MaxColors,
+					"examples":    [][]string{{"red", "green", "white"}, {"1496ff", "6f2da8", "b4dc00"}},
+				},
+				"description": map[string]interface{}{
+					"type":        "string",
+					"description": "What the palette is for (optional)",
+				},
+			},
+			Required: []string{"name", "colors"},
+		},
+	}
+}
+
+// Execute runs the savePalette tool
+func (t *SavePaletteTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, ok := arguments["name"].(string)
+	if !ok {
+		return invalidArgument("name", "'name' must be a string"), nil
+	}
+	colorList, ok := arguments["colors"].([]interface{})
+	if !ok {
+		return invalidArgument("colors", "'colors' must be an array of colors"), nil
+	}
+	colors := make([]string, len(colorList))
+	for i, value := range colorList {
+		spec, ok := value.(string)
+		if !ok {
+			return invalidArgument("colors", fmt.Sprintf("color at index %d must be a string", i)), nil
+		}
+		colors[i] = spec
+	}
+	description := ""
+	if value, exists := arguments["description"]; exists {
+		text, ok := value.(string)
+		if !ok {
+			return invalidArgument("description", "'description' must be a string"), nil
+		}
+		description = text
+	}
+
+	palette, replaced, err := t.store.Save(palettes.Palette{Name: name, Colors: colors, Description: description})
+	if err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+
+	verb := "Saved"
+	if replaced {
+		verb = "Replaced"
+	}
+	swatches := make([]string, len(palette.Colors))
+	for i, hex := range palette.Colors {
+		swatches[i] = fmt.Sprintf("%s:%d = #%s", palette.Name, i+1, hex)
+	}
+	message := fmt.Sprintf("🎨 %s palette '%s' with %d color(s)\n%s", verb, palette.Name, len(palette.Colors), strings.Join(swatches, "\n"))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
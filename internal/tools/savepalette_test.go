@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/palettes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaletteTools(t *testing.T) {
+	store := palettes.NewStore(filepath.Join(t.TempDir(), "palettes.json"))
+	save := NewSavePaletteTool(store)
+	list := NewListPalettesTool(store)
+	remove := NewDeletePaletteTool(store)
+
+	result, err := save.Execute(context.Background(), map[string]interface{}{
+		"name":        "xmas",
+		"colors":      []interface{}{"red", "green", "#fff"},
+		"description": "Holiday colors",
+	})
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	require.False(t, result.IsError, text)
+	assert.Contains(t, text, "Saved palette 'xmas' with 3 color(s)")
+	assert.Contains(t, text, "xmas:2 = #008000")
+
+	result, err = save.Execute(context.Background(), map[string]interface{}{"name": "xmas", "colors": []interface{}{"red", "green"}})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Replaced palette 'xmas'")
+
+	result, err = list.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	var listed []palettes.Palette
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &listed))
+	require.Len(t, listed, 1)
+	assert.Equal(t, []string{"ff0000", "008000"}, listed[0].Colors)
+
+	result, err = remove.Execute(context.Background(), map[string]interface{}{"name": "XMAS"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Empty(t, store.List())
+
+	for name, arguments := range map[string]map[string]interface{}{
+		"bad color":     {"name": "bad", "colors": []interface{}{"nope"}},
+		"no colors":     {"name": "none", "colors": []interface{}{}},
+		"bad name":      {"name": "a:b", "colors": []interface{}{"red"}},
+		"colors object": {"name": "obj", "colors": "red"},
+	} {
+		result, err := save.Execute(context.Background(), arguments)
+		require.NoError(t, err)
+		assert.True(t, result.IsError, name)
+	}
+	result, err = remove.Execute(context.Background(), map[string]interface{}{"name": "xmas"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/scenes"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// SaveSceneTool implements the saveScene MCP tool
+type SaveSceneTool struct {
+	store        *scenes.Store
+	stateManager *state.Manager
+}
+
+// NewSaveSceneTool creates a new saveScene tool instance
+func NewSaveSceneTool(store *scenes.Store, stateManager *state.Manager) *SaveSceneTool {
+	return &SaveSceneTool{
+		store:        store,
+		stateManager: stateManager,
+	}
+}
+
+// Definition returns the MCP tool definition for saveScene
+func (t *SaveSceneTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "saveScene",
+		Description: "Save the UFO's complete lighting as a named scene, saved across restarts: every LED of both rings with their whirl and morph, the logo and its animation, and the brightness. applyScene brings it back exactly. Unlike an effect, which is a pattern drawn over what the UFO shows, a scene is a snapshot that replaces everything. Saving under an existing name replaces that scene.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Scene name: letters, digits, '-' and '_'",
+					"examples":    []string{"focus", "demo-day"},
+				},
+				"description": map[string]interface{}{
+					"type":        "string",
+					"description": "What the scene is for (optional)",
+				},
+			},
+			Required: []string{"name"},
+		},
+	}
+}
+
+// Execute runs the saveScene tool
+func (t *SaveSceneTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, ok := arguments["name"].(string)
+	if !ok {
+		return invalidArgument("name", "'name' must be a string"), nil
+	}
+	description := ""
+	if value, exists := arguments["description"]; exists {
+		text, ok := value.(string)
+		if !ok {
+			return invalidArgument("description", "'description' must be a string"), nil
+		}
+		description = text
+	}
+
+	scene, replaced, err := t.store.Save(name, description, *t.stateManager.Snapshot(), time.Now())
+	if err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+
+	verb := "Saved"
+	if replaced {
+		verb = "Replaced"
+	}
+	message := fmt.Sprintf("📸 %s scene '%s'\n", verb, scene.Name)
+	for _, line := range describeLighting(scene.Lighting) {
+		message += "• " + line + "\n"
+	}
+	if current := t.stateManager.GetCurrentEffect(); current != nil {
+		message += fmt.Sprintf("\n⚠️ Effect '%s' is running; the scene holds the lighting under it, not the effect.", current.Name)
+	}
+	return sceneResult(message), nil
+}
+
+// describeLighting summarizes a scene's lighting, one line per part
+func describeLighting(lighting state.LedState) []string {
+	var lines []string
+	topBrightness, bottomBrightness := lighting.RingBrightness()
+	for _, ring := range []struct {
+		name       string
+		leds       [device.RingLEDs]string
+		whirlMs    int
+		ccw        bool
+		morph      *state.MorphData
+		brightness int
+	}{
+		{"Top", lighting.Top, lighting.TopWhirlMs, lighting.TopWhirlCounterClockwise, lighting.TopMorph, topBrightness},
+		{"Bottom", lighting.Bottom, lighting.BottomWhirlMs, lighting.BottomWhirlCounterClockwise, lighting.BottomMorph, bottomBrightness},
+	} {
+		lit := 0
+		for _, led := range ring.leds {
+			if led != "" && led != "000000" {
+				lit++
+			}
+		}
+		parts := []string{"dark"}
+		if lit > 0 {
+			parts = []string{fmt.Sprintf("%d of %d LEDs lit", lit, device.RingLEDs)}
+		}
+		if ring.whirlMs > 0 {
+			direction := "clockwise"
+			if ring.ccw {
+				direction = "counter-clockwise"
+			}
+			parts = append(parts, fmt.Sprintf("whirl %d ms %s", ring.whirlMs, direction))
+		}
+		if ring.morph != nil {
+			parts = append(parts, fmt.Sprintf("morph %d ms on, %d ms fade", ring.morph.BrightnessMs, ring.morph.FadeMs))
+		}
+		if ring.brightness < device.FullRingBrightness {
+			parts = append(parts, fmt.Sprintf("brightness %d%%", ring.brightness))
+		}
+		lines = append(lines, fmt.Sprintf("%s ring: %s", ring.name, strings.Join(parts, ", ")))
+	}
+
+	logo := "off"
+	switch {
+	case lighting.LogoAnimation != nil:
+		logo = describeLogoAnimation(*lighting.LogoAnimation)
+	case lighting.LogoOn:
+		logo = "on"
+	}
+	lines = append(lines, "Logo: "+logo, fmt.Sprintf("Brightness: %d", lighting.Dim))
+	return lines
+}
+
+// sceneResult builds a successful scene tool result
+func sceneResult(message string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/scenes"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSceneTools(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	engine := effects.NewEngine(device.NewSimulatedClient(device.NewSimulator()))
+	store := scenes.NewStore(filepath.Join(t.TempDir(), "scenes.json"))
+
+	_, err := stateManager.ApplyQuery("top_init=1&top=0|5|ff0000&top_whirl=300&bottom_bg=0000ff&dim=90&logo=on")
+	require.NoError(t, err)
+
+	result, err := NewSaveSceneTool(store, stateManager).Execute(context.Background(), map[string]interface{}{
+		"name":        "focus",
+		"description": "Heads-down time",
+	})
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	require.False(t, result.IsError, text)
+	assert.Contains(t, text, "Saved scene 'focus'")
+	assert.Contains(t, text, "Top ring: 5 of 15 LEDs lit, whirl 300 ms")
+	assert.Contains(t, text, "Bottom ring: 15 of 15 LEDs lit")
+	assert.Contains(t, text, "Brightness: 90")
+
+	result, err = NewListScenesTool(store).Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	text = result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "1 scene(s)")
+	assert.Contains(t, text, "Heads-down time")
+
+	// Change everything, then bring the scene back
+	stateManager.SetLighting(state.LedState{Dim: 10})
+	apply := NewApplySceneTool(store, engine, broadcaster, stateManager)
+	result, err = apply.Execute(context.Background(), map[string]interface{}{"name": "focus"})
+	require.NoError(t, err)
+	text = result.Content[0].(mcp.TextContent).Text
+	require.False(t, result.IsError, text)
+	assert.Contains(t, text, "Applied scene 'focus'")
+	assert.Contains(t, text, "top_whirl=300")
+	assert.Contains(t, text, "dim=90")
+
+	snapshot := stateManager.Snapshot()
+	assert.Equal(t, "ff0000", snapshot.Top[4])
+	assert.Equal(t, "0000ff", snapshot.Bottom[14])
+	assert.Equal(t, 300, snapshot.TopWhirlMs)
+	assert.Equal(t, 90, snapshot.Dim)
+	assert.True(t, snapshot.LogoOn)
+
+	// A running effect would draw over the scene
+	stateManager.PushEffect("rainbow", "top=0|15|ff0000", nil)
+	result, err = apply.Execute(context.Background(), map[string]interface{}{"name": "focus"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "effect 'rainbow' is running")
+
+	result, err = apply.Execute(context.Background(), map[string]interface{}{"name": "missing"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	result, err = NewDeleteSceneTool(store).Execute(context.Background(), map[string]interface{}{"name": "focus"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Empty(t, store.List())
+}
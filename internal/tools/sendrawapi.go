@@ -3,23 +3,27 @@ package tools
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/starspace46/ufo-mcp-go/internal/device"
 	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
 )
 
 // SendRawApiTool implements the sendRawApi MCP tool
 type SendRawApiTool struct {
-	client      *device.Client
-	broadcaster *events.Broadcaster
+	client       *device.Client
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
 }
 
 // NewSendRawApiTool creates a new sendRawApi tool instance
-func NewSendRawApiTool(client *device.Client, broadcaster *events.Broadcaster) *SendRawApiTool {
+func NewSendRawApiTool(client *device.Client, broadcaster *events.Broadcaster, stateManager *state.Manager) *SendRawApiTool {
 	return &SendRawApiTool{
-		client:      client,
-		broadcaster: broadcaster,
+		client:       client,
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
 	}
 }
 
@@ -27,7 +31,7 @@ func NewSendRawApiTool(client *device.Client, broadcaster *events.Broadcaster) *
 func (t *SendRawApiTool) Definition() mcp.Tool {
 	return mcp.Tool{
 		Name:        "sendRawApi",
-		Description: "Fire a raw query string exactly as typed in UFO web UI. Use this for custom commands or debugging. The query should not include the leading '?' or '/api' path - just the parameter string (e.g., 'effect=rainbow&dim=100').",
+		Description: "Fire a raw query string exactly as typed in UFO web UI. Use this for custom commands or debugging. The query should not include the leading '?' or '/api' path - just the parameter string (e.g., 'effect=rainbow&dim=100'). The ufo://api-reference resource lists every parameter with its format and valid range. Set applyAndVerify to check afterwards that the UFO actually shows what was sent. The rings, whirl, morph, brightness and logo the query sets are recorded in the shadow state, so getLedState stays accurate. Colors are adjusted by the UFO's color correction, if one is set, unless passthrough is true.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -36,6 +40,16 @@ func (t *SendRawApiTool) Definition() mcp.Tool {
 					"description": "Raw query string to send to UFO /api endpoint (without leading ? or /)",
 					"examples":    []string{"effect=rainbow", "dim=128", "logo=on", "top_init=1&top=ff0000"},
 				},
+				"applyAndVerify": map[string]interface{}{
+					"type":        "boolean",
+					"description": "After sending, read the UFO's state back (or re-send when the firmware does not report it) and fail if the UFO does not reflect the query (optional, default false)",
+					"default":     false,
+				},
+				"passthrough": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Send colors and dim levels exactly as given, skipping the UFO's color correction (optional, default false)",
+					"default":     false,
+				},
 			},
 			Required: []string{"query"},
 		},
@@ -47,74 +61,110 @@ func (t *SendRawApiTool) Execute(ctx context.Context, arguments map[string]inter
 	// Extract query parameter
 	queryArg, exists := arguments["query"]
 	if !exists {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'query' parameter is required",
-				},
-			},
-			IsError: true,
-		}, nil
+		return missingArgument("query", "'query' parameter is required"), nil
 	}
 
 	query, ok := queryArg.(string)
 	if !ok {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'query' parameter must be a string",
-				},
-			},
-			IsError: true,
-		}, nil
+		return invalidArgument("query", "'query' parameter must be a string"), nil
 	}
 
 	// Basic validation - query should not contain suspicious characters
 	if containsSuspiciousChars(query) {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: Query contains potentially unsafe characters",
-				},
-			},
-			IsError: true,
-		}, nil
+		return invalidArgument("query", "Query contains potentially unsafe characters"), nil
 	}
 
-	// Execute the raw query
-	result, err := t.client.SendRawQuery(ctx, query)
+	verify := false
+	if value, exists := arguments["applyAndVerify"]; exists {
+		b, ok := value.(bool)
+		if !ok {
+			return invalidArgument("applyAndVerify", "'applyAndVerify' must be true or false"), nil
+		}
+		verify = b
+	}
+	if value, exists := arguments["passthrough"]; exists {
+		passthrough, ok := value.(bool)
+		if !ok {
+			return invalidArgument("passthrough", "'passthrough' must be true or false"), nil
+		}
+		if passthrough {
+			ctx = device.WithPassthrough(ctx)
+		}
+	}
+
+	// Execute the raw query, as one device transaction with its check when
+	// verifying
+	var result, note string
+	verified := true
+	send := func(ctx context.Context) error {
+		var err error
+		if result, err = t.client.SendRawQuery(ctx, query); err != nil {
+			return err
+		}
+		if verify {
+			note, verified = verifyApplied(ctx, t.client, query)
+		}
+		return nil
+	}
+	var err error
+	if verify {
+		err = t.client.Atomically(ctx, send)
+	} else {
+		err = send(ctx)
+	}
 	if err != nil {
 		// Publish the failed execution event
-		t.broadcaster.PublishRawExecuted(query, fmt.Sprintf("ERROR: %v", err))
+		t.broadcaster.PublishRawExecuted(ctx, query, fmt.Sprintf("ERROR: %v", err))
 
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("UFO communication error: %v", err),
-				},
-			},
-			IsError: true,
-		}, nil
+		return toolError(CodeDeviceError, fmt.Sprintf("UFO communication error: %v", err)), nil
 	}
 
 	// Publish the successful execution event
-	t.broadcaster.PublishRawExecuted(query, result)
+	t.broadcaster.PublishRawExecuted(ctx, query, result)
+
+	message := fmt.Sprintf("Raw API executed successfully.\nQuery: %s\nResponse: %s", query, result)
+	if verify {
+		message += "\n" + note
+	}
+	if verified {
+		// Record what the query drew so the shadow state matches the UFO
+		changed, err := t.stateManager.ApplyQuery(query)
+		switch {
+		case err != nil:
+			message += fmt.Sprintf("\n⚠️ Shadow state not updated: %v", err)
+		case len(changed) > 0:
+			message += "\nShadow state updated: " + strings.Join(changed, ", ")
+		}
+	}
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			mcp.TextContent{
 				Type: "text",
-				Text: fmt.Sprintf("Raw API executed successfully.\nQuery: %s\nResponse: %s", query, result),
+				Text: message,
 			},
 		},
-		IsError: false,
+		IsError: !verified,
 	}, nil
 }
 
+// verifyApplied checks that the UFO reflects a query it was just sent and
+// describes the outcome. It reports false when the UFO does not reflect the
+// query or could not be checked.
+func verifyApplied(ctx context.Context, client *device.Client, query string) (string, bool) {
+	v, err := client.Verify(ctx, query, device.VerifyDelay)
+	if err != nil {
+		return fmt.Sprintf("⚠️ Could not verify the change: %v", err), false
+	}
+	if !v.Verified {
+		return fmt.Sprintf("❌ The UFO does not reflect the request: %s", strings.Join(v.Mismatches, "; ")), false
+	}
+	if v.Method == "readback" {
+		return fmt.Sprintf("✅ Verified: the UFO reports the requested %s", strings.Join(v.Checked, ", ")), true
+	}
+	return "✅ Verified by re-sending: the UFO does not report this state, but accepted the query again", true
+}
+
 // containsSuspiciousChars performs basic validation on the query string
 func containsSuspiciousChars(query string) bool {
 	// Check for potentially dangerous characters or patterns
@@ -5,12 +5,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/starspace46/ufo-mcp-go/internal/device"
 	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
 )
 
 func TestSendRawApiTool_Definition(t *testing.T) {
@@ -18,7 +20,7 @@ func TestSendRawApiTool_Definition(t *testing.T) {
 	broadcaster := events.NewBroadcaster()
 	defer broadcaster.Close()
 
-	tool := NewSendRawApiTool(client, broadcaster)
+	tool := NewSendRawApiTool(client, broadcaster, state.NewManager(broadcaster))
 	def := tool.Definition()
 
 	if def.Name != "sendRawApi" {
@@ -58,7 +60,7 @@ func TestSendRawApiTool_Execute(t *testing.T) {
 	broadcaster := events.NewBroadcaster()
 	defer broadcaster.Close()
 
-	tool := NewSendRawApiTool(client, broadcaster)
+	tool := NewSendRawApiTool(client, broadcaster, state.NewManager(broadcaster))
 
 	tests := []struct {
 		name        string
@@ -146,7 +148,7 @@ func TestSendRawApiTool_EventPublishing(t *testing.T) {
 	// Subscribe to events
 	sub := broadcaster.Subscribe("test")
 
-	tool := NewSendRawApiTool(client, broadcaster)
+	tool := NewSendRawApiTool(client, broadcaster, state.NewManager(broadcaster))
 
 	// Execute a successful query
 	arguments := map[string]interface{}{
@@ -204,3 +206,24 @@ func TestContainsSuspiciousChars(t *testing.T) {
 		})
 	}
 }
+
+func TestSendRawApiTool_UpdatesShadowState(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	tool := NewSendRawApiTool(device.NewSimulatedClient(device.NewSimulator()), broadcaster, stateManager)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"query": "top_init=1&top=0|5|ff0000&top_whirl=300&dim=100",
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected failure: %v %+v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "Shadow state updated: top, topWhirl, dim") {
+		t.Errorf("unexpected response %q", text)
+	}
+	snapshot := stateManager.Snapshot()
+	if snapshot.Top[4] != "ff0000" || snapshot.Top[5] != "000000" || snapshot.TopWhirlMs != 300 || snapshot.Dim != 100 {
+		t.Errorf("shadow state not updated: %+v", snapshot)
+	}
+}
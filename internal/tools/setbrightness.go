@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/starspace46/ufo-mcp-go/internal/device"
@@ -10,6 +11,13 @@ import (
 	"github.com/starspace46/ufo-mcp-go/internal/state"
 )
 
+// fadeStepInterval is the shortest delay between intermediate dim commands
+// while fading; a write rate limit spaces them further apart
+const fadeStepInterval = 50 * time.Millisecond
+
+// maxFadeMs caps how long a brightness fade may take
+const maxFadeMs = 60000
+
 // SetBrightnessTool implements the setBrightness MCP tool
 type SetBrightnessTool struct {
 	client       *device.Client
@@ -30,7 +38,7 @@ func NewSetBrightnessTool(client *device.Client, broadcaster *events.Broadcaster
 func (t *SetBrightnessTool) Definition() mcp.Tool {
 	return mcp.Tool{
 		Name:        "setBrightness",
-		Description: "Set the global brightness level for all UFO LEDs. This affects the intensity of all lighting effects and colors.",
+		Description: "Set the global brightness level for all UFO LEDs. This affects the intensity of all lighting effects and colors. Optionally fade smoothly from the current level over fadeMs milliseconds.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -41,6 +49,13 @@ func (t *SetBrightnessTool) Definition() mcp.Tool {
 					"maximum":     255,
 					"examples":    []int{0, 64, 128, 192, 255},
 				},
+				"fadeMs": map[string]interface{}{
+					"type":        "integer",
+					"description": "Optional fade duration in milliseconds (0-60000). Brightness ramps from the current level to the target instead of jumping",
+					"minimum":     0,
+					"maximum":     maxFadeMs,
+					"examples":    []int{500, 2000, 5000},
+				},
 			},
 			Required: []string{"level"},
 		},
@@ -52,15 +67,7 @@ func (t *SetBrightnessTool) Execute(ctx context.Context, arguments map[string]in
 	// Extract level parameter
 	levelArg, exists := arguments["level"]
 	if !exists {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'level' parameter is required",
-				},
-			},
-			IsError: true,
-		}, nil
+		return missingArgument("level", "'level' parameter is required"), nil
 	}
 
 	// Handle both int and float64 (JSON numbers are float64 by default)
@@ -71,63 +78,120 @@ func (t *SetBrightnessTool) Execute(ctx context.Context, arguments map[string]in
 	case float64:
 		level = int(v)
 	default:
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'level' parameter must be a number",
-				},
-			},
-			IsError: true,
-		}, nil
+		return invalidArgument("level", "'level' parameter must be a number"), nil
 	}
 
 	// Validate level range
 	if level < 0 || level > 255 {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: brightness level must be between 0 and 255",
-				},
-			},
-			IsError: true,
-		}, nil
+		return invalidArgument("level", "brightness level must be between 0 and 255"), nil
+	}
+
+	// Extract optional fade duration
+	var fadeMs int
+	if fadeArg, exists := arguments["fadeMs"]; exists {
+		switch v := fadeArg.(type) {
+		case int:
+			fadeMs = v
+		case float64:
+			fadeMs = int(v)
+		default:
+			return invalidArgument("fadeMs", "'fadeMs' parameter must be a number"), nil
+		}
+
+		if fadeMs < 0 || fadeMs > maxFadeMs {
+			return invalidArgument("fadeMs", fmt.Sprintf("'fadeMs' must be between 0 and %d", maxFadeMs)), nil
+		}
+	}
+
+	// Ramp through intermediate levels before setting the target
+	startLevel := t.stateManager.Snapshot().Dim
+	if fadeMs > 0 && startLevel != level {
+		if err := t.fade(ctx, startLevel, level, fadeMs); err != nil {
+			return toolError(CodeDeviceError, fmt.Sprintf("Failed to fade brightness: %v", err)), nil
+		}
 	}
 
 	// Execute the brightness command
 	err := t.client.SetBrightness(ctx, level)
 	if err != nil {
 		// Publish the failed execution event
-		t.broadcaster.PublishRawExecuted(fmt.Sprintf("dim=%d", level), fmt.Sprintf("ERROR: %v", err))
+		t.broadcaster.PublishRawExecuted(ctx, fmt.Sprintf("dim=%d", level), fmt.Sprintf("ERROR: %v", err))
 		
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Failed to set brightness: %v", err),
-				},
-			},
-			IsError: true,
-		}, nil
+		return toolError(CodeDeviceError, fmt.Sprintf("Failed to set brightness: %v", err)), nil
 	}
 
 	// Update shadow state
 	t.stateManager.UpdateBrightness(level)
 	
 	// Publish the successful execution event
-	t.broadcaster.PublishRawExecuted(fmt.Sprintf("dim=%d", level), "OK")
+	t.broadcaster.PublishRawExecuted(ctx, fmt.Sprintf("dim=%d", level), "OK")
 
 	// Calculate percentage for user-friendly display
 	percentage := int(float64(level) / 255.0 * 100)
 
+	message := fmt.Sprintf("Brightness set to %d/255 (%d%%) successfully", level, percentage)
+	if fadeMs > 0 && startLevel != level {
+		message += fmt.Sprintf(" (faded from %d over %dms)", startLevel, fadeMs)
+	}
+
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			mcp.TextContent{
 				Type: "text",
-				Text: fmt.Sprintf("Brightness set to %d/255 (%d%%) successfully", level, percentage),
+				Text: message,
 			},
 		},
 		IsError: false,
 	}, nil
+}
+
+// fade issues intermediate dim commands between from and to (exclusive of to)
+// spread evenly over fadeMs milliseconds, returning once the target is due
+func (t *SetBrightnessTool) fade(ctx context.Context, from, to, fadeMs int) error {
+	delta := to - from
+	distance := delta
+	if distance < 0 {
+		distance = -distance
+	}
+
+	// One step per interval, no more often than the UFO takes writes, so no
+	// step waits in the write queue, and never more steps than brightness
+	// levels to cross
+	stepInterval := fadeStepInterval
+	if rate := t.client.WriteQueue().RequestsPerSecond; rate > 0 {
+		stepInterval = max(stepInterval, time.Duration(float64(time.Second)/rate))
+	}
+	steps := int(time.Duration(fadeMs) * time.Millisecond / stepInterval)
+	if steps > distance {
+		steps = distance
+	}
+	if steps < 1 {
+		steps = 1
+	}
+	interval := time.Duration(fadeMs) * time.Millisecond / time.Duration(steps)
+
+	// Steps are due at fixed times from the start, so the time a write takes
+	// does not stretch the fade
+	start := time.Now()
+	for i := 1; i <= steps; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Until(start.Add(interval * time.Duration(i)))):
+		}
+
+		// The final level is sent by the caller
+		if i == steps {
+			break
+		}
+
+		intermediate := from + delta*i/steps
+		if err := t.client.SetBrightness(ctx, intermediate); err != nil {
+			t.broadcaster.PublishRawExecuted(ctx, fmt.Sprintf("dim=%d", intermediate), fmt.Sprintf("ERROR: %v", err))
+			return err
+		}
+		t.stateManager.UpdateBrightness(intermediate)
+	}
+
+	return nil
 }
\ No newline at end of file
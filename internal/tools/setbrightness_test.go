@@ -236,4 +236,113 @@ func TestSetBrightnessTool_EventPublishing(t *testing.T) {
 			return
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestSetBrightnessTool_Fade(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	os.Setenv("UFO_IP", server.URL[7:])
+	defer os.Unsetenv("UFO_IP")
+
+	client := device.NewClient()
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+
+	tool := NewSetBrightnessTool(client, broadcaster, stateManager)
+
+	start := time.Now()
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"level":  55.0,
+		"fadeMs": 200.0,
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %v", result.Content)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("expected fade to take at least 200ms, took %v", elapsed)
+	}
+
+	// 200ms at 50ms per step gives 3 intermediate levels plus the target
+	expected := []string{"dim=205", "dim=155", "dim=105", "dim=55"}
+	if len(queries) != len(expected) {
+		t.Fatalf("expected queries %v, got %v", expected, queries)
+	}
+	for i := range expected {
+		if queries[i] != expected[i] {
+			t.Errorf("expected query %d to be %s, got %s", i, expected[i], queries[i])
+		}
+	}
+
+	if level := stateManager.Snapshot().Dim; level != 55 {
+		t.Errorf("expected shadow brightness 55, got %d", level)
+	}
+
+	content := result.Content[0].(mcp.TextContent)
+	if !contains(content.Text, "faded from 255 over 200ms") {
+		t.Errorf("expected fade summary in message, got %s", content.Text)
+	}
+}
+
+func TestSetBrightnessTool_FadeWithRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := device.NewClient()
+	client.SetRateLimit(device.DefaultRequestsPerSecond)
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	tool := NewSetBrightnessTool(client, broadcaster, stateManager)
+
+	// The steps keep to the write rate, so the fade takes about fadeMs
+	start := time.Now()
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"level":  55.0,
+		"fadeMs": 1000.0,
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %v", result.Content)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second || elapsed > 1300*time.Millisecond {
+		t.Errorf("expected the fade to take about 1s, took %v", elapsed)
+	}
+	if level := stateManager.Snapshot().Dim; level != 55 {
+		t.Errorf("expected shadow brightness 55, got %d", level)
+	}
+}
+
+func TestSetBrightnessTool_InvalidFade(t *testing.T) {
+	client := device.NewClient()
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+
+	tool := NewSetBrightnessTool(client, broadcaster, state.NewManager(broadcaster))
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"level":  100,
+		"fadeMs": -5,
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected error for negative fadeMs")
+	}
+}
@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+)
+
+// SetChannelStatusTool implements the setChannelStatus MCP tool, which shows
+// a system's status on its channel of the status board
+type SetChannelStatusTool struct {
+	board *integrations.Board
+}
+
+// NewSetChannelStatusTool creates a new setChannelStatus tool instance
+func NewSetChannelStatusTool(board *integrations.Board) *SetChannelStatusTool {
+	return &SetChannelStatusTool{board: board}
+}
+
+// Definition returns the MCP tool definition for setChannelStatus
+func (t *SetChannelStatusTool) Definition() mcp.Tool {
+	var names, channels []string
+	for _, channel := range t.board.Channels() {
+		names = append(names, channel.Name)
+		text := fmt.Sprintf("'%s' (%s ring, LEDs %d-%d", channel.Name, channel.Ring, channel.Start, (channel.Start+channel.Count-1)%device.RingLEDs)
+		if channel.Description != "" {
+			text += ": " + channel.Description
+		}
+		channels = append(channels, text+")")
+	}
+	severities := append(append([]string(nil), integrations.ChannelSeverities...), integrations.ChannelOff)
+
+	return mcp.Tool{
+		Name:        "setChannelStatus",
+		Description: "Show a system's status on its channel of the status board. Each channel owns a segment of one ring, so several systems share the UFO without overwriting each other; setting one channel leaves the others as they are. Effects and alerts cover the board while they run. Channels: " + strings.Join(channels, ", ") + ".",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"channel": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the channel",
+					"enum":        names,
+				},
+				"severity": map[string]interface{}{
+					"type":        "string",
+					"description": "Status to show in its configured color; 'off' turns the channel dark (optional when a color is given)",
+					"enum":        severities,
+				},
+				"color": map[string]interface{}{
+					"type":        "string",
+					"description": "Color to show instead of the severity's, hex or name (optional)",
+					"examples":    []string{"purple", "00FFFF"},
+				},
+				"message": map[string]interface{}{
+					"type":        "string",
+					"description": "What the status means, kept with the channel and sent in the channel_status event (optional)",
+				},
+			},
+			Required: []string{"channel"},
+		},
+	}
+}
+
+// Execute runs the setChannelStatus tool
+func (t *SetChannelStatusTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	channel, _ := arguments["channel"].(string)
+	if channel == "" {
+		return missingArgument("channel", "'channel' parameter is required and must be a non-empty string"), nil
+	}
+	values := map[string]string{}
+	for _, key := range []string{"severity", "color", "message"} {
+		if value, exists := arguments[key]; exists {
+			text, ok := value.(string)
+			if !ok {
+				return invalidArgument(key, fmt.Sprintf("'%s' must be a string", key)), nil
+			}
+			values[key] = text
+		}
+	}
+	if values["severity"] == "" && values["color"] == "" {
+		return missingArgument("severity", "Give a 'severity' or a 'color'"), nil
+	}
+
+	status, err := t.board.Set(ctx, channel, values["severity"], values["color"], values["message"])
+	if errors.Is(err, integrations.ErrUnknownChannel) {
+		return invalidArgument("channel", err.Error()), nil
+	}
+	if errors.Is(err, integrations.ErrInvalidStatus) {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+	if err != nil {
+		return toolError(CodeDeviceError, err.Error()), nil
+	}
+
+	var message string
+	if status.Severity == integrations.ChannelOff {
+		message = fmt.Sprintf("📋 Channel '%s' is off\n", channel)
+	} else {
+		message = fmt.Sprintf("📋 Channel '%s' shows %s (#%s)\n", channel, status.Severity, status.Color)
+	}
+	if covering := t.board.Covering(channel); covering != "" {
+		message += fmt.Sprintf("• Covered by '%s' for now; the board shows again once it ends\n", covering)
+	}
+	message += "\nBoard:\n"
+	for _, state := range t.board.Channels() {
+		line := fmt.Sprintf("• %s: %s", state.Name, state.Severity)
+		if state.Message != "" {
+			line += " - " + state.Message
+		}
+		message += line + "\n"
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetChannelStatusTool_Execute(t *testing.T) {
+	simulator := device.NewSimulator()
+	client := device.NewSimulatedClient(simulator)
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	subscriber := broadcaster.SubscribeFiltered("test", events.SubscriptionFilter{Types: []string{events.EventChannelStatus}})
+	stateManager := state.NewManager(broadcaster)
+	display := integrations.NewDisplay(broadcaster, effects.NewStore(t.TempDir()+"/effects.json"), stateManager, effects.NewEngine(client))
+	board, err := integrations.NewBoard(integrations.BoardConfig{Channels: []integrations.Channel{
+		{Name: "build", Ring: "top", Start: 0, Count: 5},
+		{Name: "deploy", Ring: "top", Start: 5, Count: 5},
+	}}, display)
+	require.NoError(t, err)
+	tool := NewSetChannelStatusTool(board)
+	text := func(result *mcp.CallToolResult) string { return result.Content[0].(mcp.TextContent).Text }
+
+	assert.Equal(t, []string{"build", "deploy"}, tool.Definition().InputSchema.Properties["channel"].(map[string]interface{})["enum"])
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"channel": "build", "severity": "critical", "message": "main is broken"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, text(result))
+	assert.Contains(t, text(result), "Channel 'build' shows critical")
+	assert.Contains(t, text(result), "• build: critical - main is broken")
+	assert.Contains(t, text(result), "• deploy: off")
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"channel": "deploy", "severity": "ok"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, text(result))
+
+	// Both channels show side by side
+	status, err := client.FetchStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ff0000", status.Top[0])
+	assert.Equal(t, "008000", status.Top[5])
+	assert.Equal(t, "000000", status.Top[10])
+
+	event := <-subscriber.Channel
+	assert.Equal(t, "build", event.Data["channel"])
+	assert.Equal(t, "critical", event.Data["severity"])
+	assert.Equal(t, "off", event.Data["previous"])
+	assert.Equal(t, "main is broken", event.Data["message"])
+
+	for _, tt := range []struct {
+		arguments map[string]interface{}
+		code      string
+	}{
+		{map[string]interface{}{"channel": "nightly", "severity": "ok"}, CodeInvalidArgument},
+		{map[string]interface{}{"channel": "build", "severity": "meh"}, CodeInvalidArgument},
+		{map[string]interface{}{"channel": "build"}, CodeMissingArgument},
+		{map[string]interface{}{"severity": "ok"}, CodeMissingArgument},
+	} {
+		result, err = tool.Execute(context.Background(), tt.arguments)
+		require.NoError(t, err)
+		assert.True(t, result.IsError, tt.arguments)
+		toolErr, _ := ErrorOf(result)
+		assert.Equal(t, tt.code, toolErr.Code, tt.arguments)
+	}
+}
@@ -0,0 +1,196 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/devices"
+)
+
+// SetDeviceInfoTool implements the setDeviceInfo MCP tool
+type SetDeviceInfoTool struct {
+	registry *devices.Registry
+	client   *device.Client // takes color correction changes for the UFO it controls; may be nil
+}
+
+// NewSetDeviceInfoTool creates a new setDeviceInfo tool instance
+func NewSetDeviceInfoTool(registry *devices.Registry, client *device.Client) *SetDeviceInfoTool {
+	return &SetDeviceInfoTool{
+		registry: registry,
+		client:   client,
+	}
+}
+
+// Definition returns the MCP tool definition for setDeviceInfo
+func (t *SetDeviceInfoTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "setDeviceInfo",
+		Description: "Give a UFO a nickname, location and notes, saved across restarts. Only the fields provided are changed; pass an empty string to clear one. The nickname can then be used to pick the device, e.g. 'the kitchen UFO'. A colorCorrection applies gamma and white balance to every color sent to that UFO; pass an empty object to send colors as given.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"device": map[string]interface{}{
+					"type":        "string",
+					"description": "Address or current nickname of the UFO (optional, defaults to the UFO this server controls)",
+					"examples":    []string{"192.168.1.72", "kitchen"},
+				},
+				"nickname": map[string]interface{}{
+					"type":        "string",
+					"description": "Short unique name for the UFO",
+					"examples":    []string{"kitchen", "war room"},
+				},
+				"location": map[string]interface{}{
+					"type":        "string",
+					"description": "Where the UFO hangs",
+					"examples":    []string{"2nd floor kitchen"},
+				},
+				"notes": map[string]interface{}{
+					"type":        "string",
+					"description": "Free-form notes, e.g. firmware quirks or who to ask about it",
+				},
+				"colorCorrection": map[string]interface{}{
+					"type":        "object",
+					"description": "Correction applied to colors before they are sent to this UFO. Raw colors look washed out at low brightness on its LEDs; a gamma around 2.2 fixes that. Omitted fields are left uncorrected.",
+					"properties": map[string]interface{}{
+						"gamma": map[string]interface{}{
+							"type":        "number",
+							"description": "Exponent applied to each color channel (0.1-5)",
+							"examples":    []float64{2.2},
+						},
+						"red": map[string]interface{}{
+							"type":        "number",
+							"description": "White balance scale for red (0-1)",
+						},
+						"green": map[string]interface{}{
+							"type":        "number",
+							"description": "White balance scale for green (0-1)",
+						},
+						"blue": map[string]interface{}{
+							"type":        "number",
+							"description": "White balance scale for blue (0-1)",
+						},
+						"brightnessGamma": map[string]interface{}{
+							"type":        "number",
+							"description": "Exponent applied to dim levels (0.1-5)",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Execute runs the setDeviceInfo tool
+func (t *SetDeviceInfoTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	address := os.Getenv("UFO_IP")
+	if value, exists := arguments["device"]; exists {
+		name, ok := value.(string)
+		if !ok {
+			return invalidArgument("device", "'device' must be a string"), nil
+		}
+		address = strings.TrimSpace(name)
+		if resolved, ok := t.registry.Resolve(name); ok {
+			address = resolved
+		}
+	}
+	if address == "" {
+		return missingArgument("device", "no device given and no UFO is configured"), nil
+	}
+
+	device, _ := t.registry.Get(address)
+	device.Address = address
+	for field, target := range map[string]*string{
+		"nickname": &device.Nickname,
+		"location": &device.Location,
+		"notes":    &device.Notes,
+	} {
+		value, exists := arguments[field]
+		if !exists {
+			continue
+		}
+		text, ok := value.(string)
+		if !ok {
+			return invalidArgument(field, fmt.Sprintf("'%s' must be a string", field)), nil
+		}
+		*target = strings.TrimSpace(text)
+	}
+
+	if value, exists := arguments["colorCorrection"]; exists {
+		correction, err := parseColorCorrection(value)
+		if err != nil {
+			return toolError(CodeInvalidArgument, err.Error()), nil
+		}
+		device.ColorCorrection = correction
+	}
+
+	if err := t.registry.Set(device); err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+	if _, exists := arguments["colorCorrection"]; exists && t.client != nil && address == os.Getenv("UFO_IP") {
+		t.client.SetColorCorrection(device.ColorCorrection)
+	}
+
+	message := fmt.Sprintf("Saved device info for %s", device.Address)
+	if device.Nickname != "" {
+		message += fmt.Sprintf("\nNickname: %s", device.Nickname)
+	}
+	if device.Location != "" {
+		message += fmt.Sprintf("\nLocation: %s", device.Location)
+	}
+	if device.Notes != "" {
+		message += fmt.Sprintf("\nNotes: %s", device.Notes)
+	}
+	if c := device.ColorCorrection; c != nil {
+		message += fmt.Sprintf("\nColor correction: gamma %g, white balance %g/%g/%g, brightness gamma %g",
+			c.Gamma, c.Red, c.Green, c.Blue, c.BrightnessGamma)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// parseColorCorrection reads a colorCorrection argument. An empty object
+// clears the correction.
+func parseColorCorrection(value interface{}) (*device.ColorCorrection, error) {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'colorCorrection' must be an object")
+	}
+	var correction device.ColorCorrection
+	targets := map[string]*float64{
+		"gamma":           &correction.Gamma,
+		"red":             &correction.Red,
+		"green":           &correction.Green,
+		"blue":            &correction.Blue,
+		"brightnessGamma": &correction.BrightnessGamma,
+	}
+	for field, value := range fields {
+		target, known := targets[field]
+		if !known {
+			return nil, fmt.Errorf("unknown colorCorrection field '%s'", field)
+		}
+		number, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("colorCorrection '%s' must be a number", field)
+		}
+		*target = number
+	}
+	if err := correction.Validate(); err != nil {
+		return nil, fmt.Errorf("colorCorrection: %w", err)
+	}
+	if correction.IsZero() {
+		return nil, nil
+	}
+	return &correction, nil
+}
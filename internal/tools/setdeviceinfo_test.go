@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/devices"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDeviceInfoTool_Execute(t *testing.T) {
+	t.Setenv("UFO_IP", "192.168.1.72")
+	registry := devices.NewRegistry(filepath.Join(t.TempDir(), "devices.json"))
+	tool := NewSetDeviceInfoTool(registry, nil)
+
+	// Without a device argument the configured UFO is named
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"nickname": "kitchen",
+		"location": "2nd floor kitchen",
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+
+	// The nickname picks the device, and fields not given are kept
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"device": "the kitchen UFO",
+		"notes":  "firmware 2.1, ask facilities",
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+
+	device, ok := registry.Get("192.168.1.72")
+	require.True(t, ok)
+	assert.Equal(t, "kitchen", device.Nickname)
+	assert.Equal(t, "2nd floor kitchen", device.Location)
+	assert.Equal(t, "firmware 2.1, ask facilities", device.Notes)
+
+	// Nicknames stay unique
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"device":   "192.168.1.80",
+		"nickname": "Kitchen",
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "already used")
+}
+
+func TestSetDeviceInfoTool_ColorCorrection(t *testing.T) {
+	t.Setenv("UFO_IP", "192.168.1.72")
+	registry := devices.NewRegistry(filepath.Join(t.TempDir(), "devices.json"))
+	client := device.NewSimulatedClient(device.NewSimulator())
+	tool := NewSetDeviceInfoTool(registry, client)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"colorCorrection": map[string]interface{}{"gamma": 2.2, "blue": 0.9},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	saved, _ := registry.Get("192.168.1.72")
+	require.NotNil(t, saved.ColorCorrection)
+	assert.Equal(t, 2.2, saved.ColorCorrection.Gamma)
+	assert.Equal(t, saved.ColorCorrection, client.ColorCorrection())
+
+	// Another UFO's correction does not touch the one this server controls
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"device":          "192.168.1.80",
+		"colorCorrection": map[string]interface{}{"gamma": 1.8},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, 2.2, client.ColorCorrection().Gamma)
+
+	for _, invalid := range []interface{}{"2.2", map[string]interface{}{"gamma": 9.0}, map[string]interface{}{"hue": 1.0}} {
+		result, err = tool.Execute(context.Background(), map[string]interface{}{"colorCorrection": invalid})
+		require.NoError(t, err)
+		assert.True(t, result.IsError, "%v", invalid)
+	}
+
+	// An empty object clears the correction
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"colorCorrection": map[string]interface{}{}})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	saved, _ = registry.Get("192.168.1.72")
+	assert.Nil(t, saved.ColorCorrection)
+	assert.Nil(t, client.ColorCorrection())
+}
+
+func TestListDevicesTool_Execute(t *testing.T) {
+	t.Setenv("UFO_IP", "192.168.1.72")
+	registry := devices.NewRegistry(filepath.Join(t.TempDir(), "devices.json"))
+	require.NoError(t, registry.Set(devices.Device{Address: "192.168.1.80", Nickname: "office"}))
+
+	result, err := NewListDevicesTool(registry).Execute(context.Background(), nil)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var entries []deviceEntry
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &entries))
+	require.Len(t, entries, 2)
+	assert.Equal(t, "192.168.1.72", entries[0].Address)
+	assert.True(t, entries[0].Active)
+	assert.Equal(t, "office", entries[1].Nickname)
+	assert.False(t, entries[1].Active)
+}
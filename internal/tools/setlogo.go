@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/color"
 	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
 	"github.com/starspace46/ufo-mcp-go/internal/events"
 	"github.com/starspace46/ufo-mcp-go/internal/state"
 )
@@ -15,14 +17,16 @@ type SetLogoTool struct {
 	client       *device.Client
 	broadcaster  *events.Broadcaster
 	stateManager *state.Manager
+	engine       *effects.Engine // stops logo animations
 }
 
 // NewSetLogoTool creates a new setLogo tool instance
-func NewSetLogoTool(client *device.Client, broadcaster *events.Broadcaster, stateManager *state.Manager) *SetLogoTool {
+func NewSetLogoTool(client *device.Client, broadcaster *events.Broadcaster, stateManager *state.Manager, engine *effects.Engine) *SetLogoTool {
 	return &SetLogoTool{
 		client:       client,
 		broadcaster:  broadcaster,
 		stateManager: stateManager,
+		engine:       engine,
 	}
 }
 
@@ -42,15 +46,13 @@ func (t *SetLogoTool) Definition() mcp.Tool {
 				},
 				"color1": map[string]interface{}{
 					"type":        "string",
-					"description": "First color in hex format (e.g., 'FF0000' for red). Optional - only used when state is 'on'",
-					"pattern":     "^[0-9A-Fa-f]{6}$",
-					"examples":    []string{"FF0000", "00FF00", "0000FF", "8B0000"},
+					"description": "First color as hex (e.g., 'FF0000' or '#F00'), rgb(r,g,b) or CSS color name (e.g., 'red'). Optional - only used when state is 'on'",
+					"examples":    []string{"FF0000", "00FF00", "dodgerblue", "rgb(139,0,0)"},
 				},
 				"color2": map[string]interface{}{
 					"type":        "string",
-					"description": "Second color in hex format (e.g., 'FF6B6B' for light red). Optional - creates gradient with color1",
-					"pattern":     "^[0-9A-Fa-f]{6}$",
-					"examples":    []string{"FF6B6B", "90EE90", "ADD8E6", "FFB6C1"},
+					"description": "Second color as hex, rgb(r,g,b) or CSS color name (e.g., 'FF6B6B' or 'lightpink'). Optional - creates gradient with color1",
+					"examples":    []string{"FF6B6B", "90EE90", "lightblue", "#FB6"},
 				},
 			},
 			Required: []string{"state"},
@@ -63,41 +65,17 @@ func (t *SetLogoTool) Execute(ctx context.Context, arguments map[string]interfac
 	// Extract state parameter
 	stateArg, exists := arguments["state"]
 	if !exists {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'state' parameter is required",
-				},
-			},
-			IsError: true,
-		}, nil
+		return missingArgument("state", "'state' parameter is required"), nil
 	}
 
 	state, ok := stateArg.(string)
 	if !ok {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'state' parameter must be a string",
-				},
-			},
-			IsError: true,
-		}, nil
+		return invalidArgument("state", "'state' parameter must be a string"), nil
 	}
 
 	// Validate state value
 	if state != "on" && state != "off" {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'state' must be either 'on' or 'off'",
-				},
-			},
-			IsError: true,
-		}, nil
+		return invalidArgument("state", "'state' must be either 'on' or 'off'"), nil
 	}
 
 	// Extract optional color parameters
@@ -116,34 +94,22 @@ func (t *SetLogoTool) Execute(ctx context.Context, arguments map[string]interfac
 		pattern := ""
 		
 		if color1 != "" {
-			// Validate color format
-			if !isValidHexColor(color1) {
-				return &mcp.CallToolResult{
-					Content: []mcp.Content{
-						mcp.TextContent{
-							Type: "text",
-							Text: "Error: 'color1' must be a valid 6-character hex color",
-						},
-					},
-					IsError: true,
-				}, nil
+			// Validate color format and convert to hex
+			hex, err := color.Parse(color1)
+			if err != nil {
+				return invalidArgument("color1", fmt.Sprintf("'color1' must be a valid hex color or color name (%v)", err)), nil
 			}
+			color1 = hex
 			pattern = color1
 		}
 		
 		if color2 != "" {
-			// Validate color format
-			if !isValidHexColor(color2) {
-				return &mcp.CallToolResult{
-					Content: []mcp.Content{
-						mcp.TextContent{
-							Type: "text",
-							Text: "Error: 'color2' must be a valid 6-character hex color",
-						},
-					},
-					IsError: true,
-				}, nil
+			// Validate color format and convert to hex
+			hex, err := color.Parse(color2)
+			if err != nil {
+				return invalidArgument("color2", fmt.Sprintf("'color2' must be a valid hex color or color name (%v)", err)), nil
 			}
+			color2 = hex
 			if pattern != "" {
 				// Create alternating pattern like ff0000|ffffff|ff0000|ffffff
 				pattern = fmt.Sprintf("%s|%s|%s|%s", color1, color2, color1, color2)
@@ -158,28 +124,26 @@ func (t *SetLogoTool) Execute(ctx context.Context, arguments map[string]interfac
 		query = fmt.Sprintf("logo=%s", state)
 	}
 
+	// A running logo animation would draw over the new logo
+	if t.engine != nil {
+		t.engine.StopLogo()
+	}
+
 	// Execute the logo command
 	_, err := t.client.SendRawQuery(ctx, query)
 	if err != nil {
 		// Publish the failed execution event
-		t.broadcaster.PublishRawExecuted(query, fmt.Sprintf("ERROR: %v", err))
+		t.broadcaster.PublishRawExecuted(ctx, query, fmt.Sprintf("ERROR: %v", err))
 		
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Failed to set logo: %v", err),
-				},
-			},
-			IsError: true,
-		}, nil
+		return toolError(CodeDeviceError, fmt.Sprintf("Failed to set logo: %v", err)), nil
 	}
 
 	// Update shadow state
 	t.stateManager.UpdateLogo(state == "on")
+	t.stateManager.UpdateLogoAnimation(nil)
 	
 	// Publish the successful execution event
-	t.broadcaster.PublishRawExecuted(query, "OK")
+	t.broadcaster.PublishRawExecuted(ctx, query, "OK")
 
 	// Build response message
 	message := fmt.Sprintf("Logo LED turned %s successfully", state)
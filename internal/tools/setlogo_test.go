@@ -19,7 +19,7 @@ func TestSetLogoTool_Definition(t *testing.T) {
 	broadcaster := events.NewBroadcaster()
 	defer broadcaster.Close()
 
-	tool := NewSetLogoTool(client, broadcaster, state.NewManager(broadcaster))
+	tool := NewSetLogoTool(client, broadcaster, state.NewManager(broadcaster), nil)
 	def := tool.Definition()
 
 	if def.Name != "setLogo" {
@@ -65,7 +65,7 @@ func TestSetLogoTool_Execute(t *testing.T) {
 	broadcaster := events.NewBroadcaster()
 	defer broadcaster.Close()
 
-	tool := NewSetLogoTool(client, broadcaster, state.NewManager(broadcaster))
+	tool := NewSetLogoTool(client, broadcaster, state.NewManager(broadcaster), nil)
 
 	tests := []struct {
 		name           string
@@ -168,7 +168,7 @@ func TestSetLogoTool_EventPublishing(t *testing.T) {
 	defer broadcaster.Close()
 
 	sub := broadcaster.Subscribe("test")
-	tool := NewSetLogoTool(client, broadcaster, state.NewManager(broadcaster))
+	tool := NewSetLogoTool(client, broadcaster, state.NewManager(broadcaster), nil)
 
 	arguments := map[string]interface{}{
 		"state": "on",
@@ -198,4 +198,44 @@ func TestSetLogoTool_EventPublishing(t *testing.T) {
 	case <-time.After(100 * time.Millisecond):
 		t.Error("timeout waiting for event to be published")
 	}
-}
\ No newline at end of file
+}
+
+func TestSetLogoTool_NamedColors(t *testing.T) {
+	var lastQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastQuery = r.URL.RawQuery
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	os.Setenv("UFO_IP", server.URL[7:])
+	defer os.Unsetenv("UFO_IP")
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	tool := NewSetLogoTool(device.NewClient(), broadcaster, state.NewManager(broadcaster), nil)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"state":  "on",
+		"color1": "red",
+		"color2": "#00F",
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %v", result.Content)
+	}
+	if lastQuery != "logo=ff0000|0000ff|ff0000|0000ff" {
+		t.Errorf("expected named colors converted to hex, got '%s'", lastQuery)
+	}
+
+	result, _ = tool.Execute(context.Background(), map[string]interface{}{
+		"state":  "on",
+		"color1": "notacolor",
+	})
+	if !result.IsError {
+		t.Error("expected error for unknown color name")
+	}
+}
@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/color"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// logoLEDs is the number of LEDs behind the Dynatrace logo
+const logoLEDs = 4
+
+// SetPixelsTool implements the setPixels MCP tool, which draws the rings LED
+// by LED and leaves the segment syntax to the server
+type SetPixelsTool struct {
+	client       *device.Client
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+}
+
+// NewSetPixelsTool creates a new setPixels tool instance
+func NewSetPixelsTool(client *device.Client, broadcaster *events.Broadcaster, stateManager *state.Manager) *SetPixelsTool {
+	return &SetPixelsTool{
+		client:       client,
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+	}
+}
+
+// Definition returns the MCP tool definition for setPixels
+func (t *SetPixelsTool) Definition() mcp.Tool {
+	ringSchema := func(name string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "array",
+			"description": fmt.Sprintf("Color of each %s ring LED, starting at LED 0 (exactly %d colors: hex RRGGBB, #RGB, rgb(r,g,b) or CSS color name; 'black' is off)", name, device.RingLEDs),
+			"items":       map[string]interface{}{"type": "string"},
+			"minItems":    device.RingLEDs,
+			"maxItems":    device.RingLEDs,
+		}
+	}
+	return mcp.Tool{
+		Name:        "setPixels",
+		Description: "Draw on the UFO pixel by pixel: give the color of every LED of a ring and the server works out the segments to send. Rings that are not given keep what they show; drawn rings stop rotating.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"top":    ringSchema("top"),
+				"bottom": ringSchema("bottom"),
+				"logo": map[string]interface{}{
+					"type":        "array",
+					"description": fmt.Sprintf("Logo colors (1-%d); fewer colors than LEDs repeat. All black turns the logo off", logoLEDs),
+					"items":       map[string]interface{}{"type": "string"},
+					"minItems":    1,
+					"maxItems":    logoLEDs,
+				},
+			},
+		},
+	}
+}
+
+// Execute runs the setPixels tool
+func (t *SetPixelsTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var queries, summary []string
+	rings := map[string][]string{}
+	for _, ring := range []string{"top", "bottom"} {
+		value, exists := arguments[ring]
+		if !exists {
+			continue
+		}
+		leds, err := pixelColors(ring, value, device.RingLEDs, device.RingLEDs)
+		if err != nil {
+			return toolError(CodeInvalidArgument, err.Error()), nil
+		}
+		background, segments, err := device.CompressRing(leds)
+		if err != nil {
+			return invalidArgument(ring, fmt.Sprintf("%s: %v", ring, err)), nil
+		}
+		rings[ring] = leds
+		queries = append(queries, buildRingPatternCommand(ring, segments, background, 0, false, ""))
+		summary = append(summary, fmt.Sprintf("• %s: %d segments over background #%s", strings.ToUpper(ring[:1])+ring[1:], len(segments), background))
+	}
+
+	var logo []string
+	if value, exists := arguments["logo"]; exists {
+		colors, err := pixelColors("logo", value, 1, logoLEDs)
+		if err != nil {
+			return toolError(CodeInvalidArgument, err.Error()), nil
+		}
+		logo = colors
+		if len(colors) > 1 {
+			for len(logo) < logoLEDs {
+				logo = append(logo, colors[len(logo)%len(colors)])
+			}
+		}
+		queries = append(queries, "logo="+strings.Join(logo, "|"))
+		summary = append(summary, fmt.Sprintf("• Logo: %s", strings.Join(logo, ", ")))
+	}
+
+	if len(queries) == 0 {
+		return missingArgument("top", "give at least one of 'top', 'bottom' or 'logo'"), nil
+	}
+
+	query := strings.Join(queries, "&")
+	if _, err := t.client.SendRawQuery(ctx, query); err != nil {
+		t.broadcaster.PublishRawExecuted(ctx, query, fmt.Sprintf("ERROR: %v", err))
+		return toolError(CodeDeviceError, fmt.Sprintf("Failed to set pixels: %v", err)), nil
+	}
+	t.broadcaster.PublishRawExecuted(ctx, query, "OK")
+
+	if leds, ok := rings["top"]; ok {
+		t.stateManager.UpdateTopRing(leds)
+		t.stateManager.UpdateWhirl("top", 0, false)
+	}
+	if leds, ok := rings["bottom"]; ok {
+		t.stateManager.UpdateBottomRing(leds)
+		t.stateManager.UpdateWhirl("bottom", 0, false)
+	}
+	if logo != nil {
+		on := false
+		for _, c := range logo {
+			on = on || c != "000000"
+		}
+		t.stateManager.UpdateLogo(on)
+	}
+
+	message := "🎨 Pixels set\n\n" + strings.Join(summary, "\n") + "\n\nQuery: " + query
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// pixelColors parses an array of between least and most colors into hex
+func pixelColors(name string, value interface{}, least, most int) ([]string, error) {
+	list, ok := value.([]interface{})
+	if !ok || len(list) < least || len(list) > most {
+		if least == most {
+			return nil, fmt.Errorf("'%s' must be an array of exactly %d colors", name, least)
+		}
+		return nil, fmt.Errorf("'%s' must be an array of %d to %d colors", name, least, most)
+	}
+	colors := make([]string, len(list))
+	for i, item := range list {
+		spec, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s color at index %d must be a string", name, i)
+		}
+		hex, err := color.Parse(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s color at index %d: %v", name, i, err)
+		}
+		colors[i] = hex
+	}
+	return colors, nil
+}
@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pixelRing returns a ring of colors with the given LEDs set to highlight
+func pixelRing(base, highlight string, leds ...int) []interface{} {
+	ring := make([]interface{}, device.RingLEDs)
+	for i := range ring {
+		ring[i] = base
+	}
+	for _, led := range leds {
+		ring[led] = highlight
+	}
+	return ring
+}
+
+func TestSetPixelsTool_Execute(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	tool := NewSetPixelsTool(device.NewClient(), broadcaster, stateManager)
+
+	t.Run("Definition", func(t *testing.T) {
+		def := tool.Definition()
+		assert.Equal(t, "setPixels", def.Name)
+		assert.Contains(t, def.InputSchema.Properties, "top")
+		assert.Contains(t, def.InputSchema.Properties, "bottom")
+		assert.Contains(t, def.InputSchema.Properties, "logo")
+	})
+
+	t.Run("CompressesRings", func(t *testing.T) {
+		queries = nil
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"top":    pixelRing("black", "red", 0, 1, 2, 14),
+			"bottom": pixelRing("#00F", "white", 7),
+			"logo":   []interface{}{"red", "green"},
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+		require.Len(t, queries, 1)
+		assert.Equal(t, "top_init=1&top=14|4|FF0000&top_bg=000000&bottom_init=1&bottom=7|1|FFFFFF&bottom_bg=0000FF&logo=ff0000|008000|ff0000|008000", queries[0])
+
+		led := stateManager.Snapshot()
+		assert.Equal(t, "ff0000", led.Top[14])
+		assert.Equal(t, "000000", led.Top[3])
+		assert.Equal(t, "ffffff", led.Bottom[7])
+		assert.True(t, led.LogoOn)
+	})
+
+	t.Run("ValidationErrors", func(t *testing.T) {
+		queries = nil
+		for _, args := range []map[string]interface{}{
+			{},
+			{"top": []interface{}{"red", "green"}},
+			{"top": pixelRing("black", "nope", 3)},
+			{"bottom": "red"},
+			{"logo": []interface{}{"red", "red", "red", "red", "red"}},
+		} {
+			result, err := tool.Execute(context.Background(), args)
+			require.NoError(t, err)
+			assert.True(t, result.IsError, "expected an error for %v", args)
+		}
+		assert.Empty(t, queries)
+	})
+}
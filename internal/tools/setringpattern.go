@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/color"
 	"github.com/starspace46/ufo-mcp-go/internal/device"
 	"github.com/starspace46/ufo-mcp-go/internal/events"
 	"github.com/starspace46/ufo-mcp-go/internal/state"
@@ -43,23 +44,21 @@ func (t *SetRingPatternTool) Definition() mcp.Tool {
 				},
 				"segments": map[string]interface{}{
 					"type":        "array",
-					"description": "Array of LED segments in format 'LED_INDEX|COUNT|RRGGBB' (e.g., ['0|5|FF0000', '10|3|00FF00'])",
+					"description": "Array of LED segments in format 'LED_INDEX|COUNT|COLOR' (e.g., ['0|5|FF0000', '10|3|green']). COLOR may be hex (RRGGBB or #RGB), rgb(r,g,b) or a CSS color name",
 					"items": map[string]interface{}{
 						"type":        "string",
-						"description": "Segment format: LED_INDEX|COUNT|RRGGBB",
-						"pattern":     "^\\d+\\|\\d+\\|[0-9A-Fa-f]{6}$",
+						"description": "Segment format: LED_INDEX|COUNT|COLOR",
 					},
 					"examples": [][]string{
 						{"0|5|FF0000", "10|5|00FF00"},
-						{"0|15|FF0000"},
-						{"0|3|FF0000", "5|3|00FF00", "10|3|0000FF"},
+						{"0|15|red"},
+						{"0|3|FF0000", "5|3|rgb(0,255,0)", "10|3|dodgerblue"},
 					},
 				},
 				"background": map[string]interface{}{
 					"type":        "string",
-					"description": "Background color for unlit LEDs (hex format RRGGBB, optional)",
-					"pattern":     "^[0-9A-Fa-f]{6}$",
-					"examples":    []string{"000000", "202020", "FFFFFF"},
+					"description": "Background color for unlit LEDs (hex RRGGBB, #RGB, rgb(r,g,b) or CSS color name, optional)",
+					"examples":    []string{"000000", "202020", "navy"},
 				},
 				"whirlMs": map[string]interface{}{
 					"type":        "integer",
@@ -91,41 +90,17 @@ func (t *SetRingPatternTool) Execute(ctx context.Context, arguments map[string]i
 	// Extract ring parameter
 	ringArg, exists := arguments["ring"]
 	if !exists {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'ring' parameter is required",
-				},
-			},
-			IsError: true,
-		}, nil
+		return missingArgument("ring", "'ring' parameter is required"), nil
 	}
 
 	ring, ok := ringArg.(string)
 	if !ok {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'ring' parameter must be a string",
-				},
-			},
-			IsError: true,
-		}, nil
+		return invalidArgument("ring", "'ring' parameter must be a string"), nil
 	}
 
 	// Validate ring value
 	if ring != "top" && ring != "bottom" {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'ring' must be either 'top' or 'bottom'",
-				},
-			},
-			IsError: true,
-		}, nil
+		return invalidArgument("ring", "'ring' must be either 'top' or 'bottom'"), nil
 	}
 
 	// Extract optional segments
@@ -134,41 +109,18 @@ func (t *SetRingPatternTool) Execute(ctx context.Context, arguments map[string]i
 		if segmentsArray, ok := segmentsArg.([]interface{}); ok {
 			for i, segment := range segmentsArray {
 				if segmentStr, ok := segment.(string); ok {
-					// Validate segment format
-					if !isValidSegmentFormat(segmentStr) {
-						return &mcp.CallToolResult{
-							Content: []mcp.Content{
-								mcp.TextContent{
-									Type: "text",
-									Text: fmt.Sprintf("Error: invalid segment format at index %d. Expected format: 'LED_INDEX|COUNT|RRGGBB'", i),
-								},
-							},
-							IsError: true,
-						}, nil
+					// Validate segment format and normalize its color to hex
+					normalized, err := normalizeSegment(segmentStr)
+					if err != nil {
+						return invalidArgument("segments", fmt.Sprintf("invalid segment format at index %d. Expected format: 'LED_INDEX|COUNT|COLOR' (%v)", i, err)), nil
 					}
-					segments = append(segments, segmentStr)
+					segments = append(segments, normalized)
 				} else {
-					return &mcp.CallToolResult{
-						Content: []mcp.Content{
-							mcp.TextContent{
-								Type: "text",
-								Text: fmt.Sprintf("Error: segment at index %d must be a string", i),
-							},
-						},
-						IsError: true,
-					}, nil
+					return invalidArgument("segments", fmt.Sprintf("segment at index %d must be a string", i)), nil
 				}
 			}
 		} else {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Error: 'segments' parameter must be an array",
-					},
-				},
-				IsError: true,
-			}, nil
+			return invalidArgument("segments", "'segments' parameter must be an array"), nil
 		}
 	}
 
@@ -176,28 +128,13 @@ func (t *SetRingPatternTool) Execute(ctx context.Context, arguments map[string]i
 	var background string
 	if bgArg, exists := arguments["background"]; exists {
 		if bgStr, ok := bgArg.(string); ok {
-			if !isValidHexColor(bgStr) {
-				return &mcp.CallToolResult{
-					Content: []mcp.Content{
-						mcp.TextContent{
-							Type: "text",
-							Text: "Error: 'background' must be a valid hex color (RRGGBB format)",
-						},
-					},
-					IsError: true,
-				}, nil
+			bgHex, err := color.Parse(bgStr)
+			if err != nil {
+				return invalidArgument("background", fmt.Sprintf("'background' must be a valid hex color or color name (%v)", err)), nil
 			}
-			background = bgStr
+			background = bgHex
 		} else {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Error: 'background' parameter must be a string",
-					},
-				},
-				IsError: true,
-			}, nil
+			return invalidArgument("background", "'background' parameter must be a string"), nil
 		}
 	}
 
@@ -210,27 +147,11 @@ func (t *SetRingPatternTool) Execute(ctx context.Context, arguments map[string]i
 		case float64:
 			whirlMs = int(v)
 		default:
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Error: 'whirlMs' parameter must be a number",
-					},
-				},
-				IsError: true,
-			}, nil
+			return invalidArgument("whirlMs", "'whirlMs' parameter must be a number"), nil
 		}
 
 		if whirlMs < 0 || whirlMs > 510 {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Error: 'whirlMs' must be between 0 and 510",
-					},
-				},
-				IsError: true,
-			}, nil
+			return invalidArgument("whirlMs", "'whirlMs' must be between 0 and 510"), nil
 		}
 	}
 
@@ -241,15 +162,7 @@ func (t *SetRingPatternTool) Execute(ctx context.Context, arguments map[string]i
 		case bool:
 			counterClockwise = v
 		default:
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Error: 'counterClockwise' parameter must be a boolean",
-					},
-				},
-				IsError: true,
-			}, nil
+			return invalidArgument("counterClockwise", "'counterClockwise' parameter must be a boolean"), nil
 		}
 	}
 
@@ -258,27 +171,11 @@ func (t *SetRingPatternTool) Execute(ctx context.Context, arguments map[string]i
 	if morphArg, exists := arguments["morph"]; exists {
 		if morphStr, ok := morphArg.(string); ok {
 			if !isValidMorphSpec(morphStr) {
-				return &mcp.CallToolResult{
-					Content: []mcp.Content{
-						mcp.TextContent{
-							Type: "text",
-							Text: "Error: 'morphSpec' must be in format 'STAY|SPEED' (e.g., '1000|500')",
-						},
-					},
-					IsError: true,
-				}, nil
+				return invalidArgument("morphSpec", "'morphSpec' must be in format 'STAY|SPEED' (e.g., '1000|500')"), nil
 			}
 			morphSpec = morphStr
 		} else {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Error: 'morphSpec' parameter must be a string",
-					},
-				},
-				IsError: true,
-			}, nil
+			return invalidArgument("morphSpec", "'morphSpec' parameter must be a string"), nil
 		}
 	}
 
@@ -287,17 +184,9 @@ func (t *SetRingPatternTool) Execute(ctx context.Context, arguments map[string]i
 	if err != nil {
 		// Publish the failed execution event
 		command := buildRingPatternCommand(ring, segments, background, whirlMs, counterClockwise, morphSpec)
-		t.broadcaster.PublishRawExecuted(command, fmt.Sprintf("ERROR: %v", err))
+		t.broadcaster.PublishRawExecuted(ctx, command, fmt.Sprintf("ERROR: %v", err))
 		
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Failed to set ring pattern: %v", err),
-				},
-			},
-			IsError: true,
-		}, nil
+		return toolError(CodeDeviceError, fmt.Sprintf("Failed to set ring pattern: %v", err)), nil
 	}
 
 	// Update shadow state
@@ -307,7 +196,7 @@ func (t *SetRingPatternTool) Execute(ctx context.Context, arguments map[string]i
 	
 	// Publish the successful execution event
 	command := buildRingPatternCommand(ring, segments, background, whirlMs, counterClockwise, morphSpec)
-	t.broadcaster.PublishRawExecuted(command, "OK")
+	t.broadcaster.PublishRawExecuted(ctx, command, "OK")
 
 	// Build success message
 	message := fmt.Sprintf("Ring pattern applied to %s ring successfully", ring)
@@ -342,12 +231,23 @@ func (t *SetRingPatternTool) Execute(ctx context.Context, arguments map[string]i
 // Helper functions
 
 func isValidSegmentFormat(segment string) bool {
+	_, err := normalizeSegment(segment)
+	return err == nil
+}
+
+// normalizeSegment validates a LED_INDEX|COUNT|COLOR segment and returns it
+// with the color converted to the hex format expected by the device
+func normalizeSegment(segment string) (string, error) {
 	parts := strings.Split(segment, "|")
 	if len(parts) != 3 {
-		return false
+		return "", fmt.Errorf("expected 3 '|'-separated parts, got %d", len(parts))
+	}
+
+	hex, err := color.Parse(parts[2])
+	if err != nil {
+		return "", err
 	}
-	// Basic validation - could be more thorough
-	return len(parts[2]) == 6 && isValidHexColor(parts[2])
+	return parts[0] + "|" + parts[1] + "|" + hex, nil
 }
 
 func isValidHexColor(color string) bool {
@@ -370,4 +370,42 @@ func TestBuildRingPatternCommand(t *testing.T) {
 			t.Errorf("expected '%s', got '%s'", expected, result)
 		}
 	})
-}
\ No newline at end of file
+}
+
+func TestSetRingPatternTool_NamedColors(t *testing.T) {
+	var lastQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastQuery = r.URL.RawQuery
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	os.Setenv("UFO_IP", server.URL[7:])
+	defer os.Unsetenv("UFO_IP")
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	tool := NewSetRingPatternTool(device.NewClient(), broadcaster, stateManager)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"ring":       "top",
+		"segments":   []interface{}{"0|5|dodgerblue", "5|5|rgb(255,128,0)"},
+		"background": "#111",
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %v", result.Content)
+	}
+
+	expected := "top_init=1&top=0|5|1e90ff|5|5|ff8000&top_bg=111111"
+	if lastQuery != expected {
+		t.Errorf("expected query '%s', got '%s'", expected, lastQuery)
+	}
+	if top := stateManager.Snapshot().Top; top[0] != "1e90ff" || top[14] != "111111" {
+		t.Errorf("shadow state not updated with hex colors: %v", top)
+	}
+}
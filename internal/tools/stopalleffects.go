@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// StopAllEffectsTool implements the stopAllEffects MCP tool, which unwinds
+// the effect stack in one call
+type StopAllEffectsTool struct {
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+	engine       *effects.Engine
+}
+
+// NewStopAllEffectsTool creates a new stopAllEffects tool instance
+func NewStopAllEffectsTool(broadcaster *events.Broadcaster, stateManager *state.Manager, engine *effects.Engine) *StopAllEffectsTool {
+	return &StopAllEffectsTool{
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		engine:       engine,
+	}
+}
+
+// Definition returns the MCP tool definition for stopAllEffects
+func (t *StopAllEffectsTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "stopAllEffects",
+		Description: "Stop every effect on the stack in one call instead of calling stopEffect once per level. With toDepth, the bottom toDepth entries are kept and the one left on top is shown again; otherwise the UFO is cleared. Raised alerts are kept unless includeAlerts is true, and integration alerts are always kept (use disableIntegration for those). Publishes one effects_cleared event listing everything stopped.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"toDepth": map[string]interface{}{
+					"type":        "integer",
+					"description": "How many entries to keep at the bottom of the stack, e.g. 1 to return to the base effect (optional, default 0)",
+					"minimum":     0,
+				},
+				"includeAlerts": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Also clear alerts raised with raiseAlert (optional, default false)",
+				},
+			},
+		},
+	}
+}
+
+// Execute runs the stopAllEffects tool
+func (t *StopAllEffectsTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	toDepth := 0
+	if value, exists := arguments["toDepth"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < 0 {
+			return invalidArgument("toDepth", "'toDepth' must be a whole number of at least 0"), nil
+		}
+		toDepth = n
+	}
+	includeAlerts := false
+	if value, exists := arguments["includeAlerts"]; exists {
+		b, ok := value.(bool)
+		if !ok {
+			return invalidArgument("includeAlerts", "'includeAlerts' must be true or false"), nil
+		}
+		includeAlerts = b
+	}
+
+	stack := t.stateManager.GetEffectStack()
+	if len(stack) <= toDepth {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf("Nothing to stop: the stack has %d entries, at most toDepth %d", len(stack), toDepth),
+				},
+			},
+			IsError: false,
+		}, nil
+	}
+
+	// Pick the entries above toDepth that may be stopped
+	stop := map[string]bool{}
+	var stopped, kept []state.EffectStackItem
+	for _, item := range stack[toDepth:] {
+		_, integrationAlert := item.Context["alertKey"].(string)
+		if integrationAlert || raisedAlertName(item) != "" && !includeAlerts {
+			kept = append(kept, item)
+			continue
+		}
+		stop[item.InstanceID()] = true
+		stopped = append(stopped, item)
+	}
+	if len(stopped) == 0 {
+		return toolError(CodeConflict, fmt.Sprintf("only alerts are above depth %d: %s. Set includeAlerts to clear raised alerts, or use clearAlert", toDepth, stackNames(kept))), nil
+	}
+
+	_, topChanged := t.stateManager.RemoveEffects(func(item state.EffectStackItem) bool {
+		return stop[item.InstanceID()]
+	})
+	top := t.stateManager.GetCurrentEffect()
+	var restoreErr error
+	if topChanged {
+		if top != nil {
+			restoreErr = restoreTop(ctx, t.engine, t.broadcaster, t.stateManager)
+		} else {
+			restoreErr = t.clear(ctx)
+		}
+	}
+
+	now := time.Now()
+	entries := make([]events.ClearedEffect, len(stopped))
+	for i, item := range stopped {
+		entries[i] = events.ClearedEffect{
+			Effect:     item.Name,
+			InstanceID: item.InstanceID(),
+			Alert:      raisedAlertName(item),
+		}
+		if !item.StartTime().IsZero() {
+			entries[i].RanMs = item.Elapsed(now).Milliseconds()
+		}
+	}
+	cleared := events.EffectsCleared{
+		Stopped:    entries,
+		Count:      len(stopped),
+		ToDepth:    toDepth,
+		Kept:       len(kept),
+		StackDepth: t.stateManager.GetEffectStackDepth(),
+		Cleared:    top == nil,
+		Manual:     true,
+	}
+	if top != nil {
+		cleared.Showing = top.Name
+	}
+	t.broadcaster.PublishPayload(ctx, cleared)
+
+	message := fmt.Sprintf("⏹️ Stopped %d effect(s): %s\n\n", len(stopped), stackNames(stopped))
+	switch {
+	case top == nil:
+		message += "• The stack is empty and all LEDs were cleared\n"
+	case topChanged:
+		message += fmt.Sprintf("• Now showing '%s' (stack depth: %d)\n", top.Name, t.stateManager.GetEffectStackDepth())
+	default:
+		message += fmt.Sprintf("• '%s' keeps showing (stack depth: %d)\n", top.Name, t.stateManager.GetEffectStackDepth())
+	}
+	if len(kept) > 0 {
+		message += fmt.Sprintf("• Kept alerts: %s\n", stackNames(kept))
+	}
+	if restoreErr != nil {
+		return toolError(CodeDeviceError, fmt.Sprintf("stopped %d effect(s) but the UFO was not updated: %v", len(stopped), restoreErr)), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// clear turns every LED and the logo off once the stack is empty
+func (t *StopAllEffectsTool) clear(ctx context.Context) error {
+	query := "top_init=1&bottom_init=1&logo=off"
+	t.engine.StopLogo()
+	if err := t.engine.Apply(ctx, "", query, nil); err != nil {
+		t.broadcaster.PublishRawExecuted(ctx, query, fmt.Sprintf("ERROR: %v", err))
+		return fmt.Errorf("clearing UFO: %w", err)
+	}
+	t.broadcaster.PublishRawExecuted(ctx, query, "OK")
+
+	t.stateManager.UpdateTopRing(make([]string, 15))
+	t.stateManager.UpdateBottomRing(make([]string, 15))
+	t.stateManager.UpdateLogo(false)
+	t.stateManager.UpdateLogoAnimation(nil)
+	return nil
+}
+
+// stackNames lists stack entries by name and instance, top first
+func stackNames(items []state.EffectStackItem) string {
+	names := make([]string, 0, len(items))
+	for i := len(items) - 1; i >= 0; i-- {
+		names = append(names, fmt.Sprintf("'%s' (%s)", items[i].Name, items[i].InstanceID()))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopAllEffectsTool_Execute(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	tool := NewStopAllEffectsTool(broadcaster, stateManager, effects.NewEngine(device.NewClient()))
+	subscriber := broadcaster.Subscribe("test")
+
+	sent := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		result := queries
+		queries = nil
+		return result
+	}
+	clearedEvent := func() events.Event {
+		t.Helper()
+		for {
+			select {
+			case event := <-subscriber.Channel:
+				if event.Type == events.EventEffectsCleared {
+					return event
+				}
+			case <-time.After(time.Second):
+				t.Fatal("no effects_cleared event")
+			}
+		}
+	}
+	push := func(name, pattern string, context map[string]interface{}) {
+		context["instanceId"] = name + "-id"
+		stateManager.PushEffect(name, pattern, context)
+	}
+
+	t.Run("EmptyStack", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{})
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Nothing to stop")
+	})
+
+	t.Run("ToDepthRestoresBase", func(t *testing.T) {
+		push("base", "bottom_init=1&bottom_bg=0000ff", map[string]interface{}{"perpetual": true})
+		push("one", "top_init=1&top_bg=ff0000", map[string]interface{}{"perpetual": true})
+		push("two", "top_init=1&top_bg=00ff00", map[string]interface{}{"duration": 60000, "startTime": time.Now()})
+		sent()
+
+		result, err := tool.Execute(context.Background(), map[string]interface{}{"toDepth": float64(1)})
+		require.NoError(t, err)
+		text := result.Content[0].(mcp.TextContent).Text
+		require.False(t, result.IsError, text)
+		assert.Contains(t, text, "Stopped 2 effect(s): 'two' (two-id), 'one' (one-id)")
+		assert.Contains(t, text, "Now showing 'base'")
+
+		assert.Equal(t, 1, stateManager.GetEffectStackDepth())
+		assert.Equal(t, []string{"bottom_init=1&bottom_bg=0000ff"}, sent())
+
+		event := clearedEvent()
+		assert.Equal(t, 2, event.Data["count"])
+		assert.Equal(t, "base", event.Data["showing"])
+		assert.Equal(t, false, event.Data["cleared"])
+	})
+
+	t.Run("KeepsAlerts", func(t *testing.T) {
+		push("page", "top_init=1&top_bg=ff0000", map[string]interface{}{"raisedAlert": "page", "priority": 80, "perpetual": true})
+		push("grafana", "top_init=1&top_bg=ffff00", map[string]interface{}{"source": "grafana", "alertKey": "cpu", "perpetual": true})
+		push("calm", "top_init=1&top_bg=0000ff", map[string]interface{}{"perpetual": true})
+		sent()
+
+		result, err := tool.Execute(context.Background(), map[string]interface{}{})
+		require.NoError(t, err)
+		text := result.Content[0].(mcp.TextContent).Text
+		require.False(t, result.IsError, text)
+		assert.Contains(t, text, "Stopped 2 effect(s): 'calm' (calm-id), 'base' (base-id)")
+		assert.Contains(t, text, "Kept alerts: 'page' (page-id), 'grafana' (grafana-id)")
+		assert.Contains(t, text, "'page' keeps showing")
+
+		// The priority alert stayed on top, so nothing was sent
+		stack := stateManager.GetEffectStack()
+		require.Len(t, stack, 2)
+		assert.Equal(t, "grafana", stack[0].Name)
+		assert.Equal(t, "page", stack[1].Name)
+		assert.Empty(t, sent())
+		assert.Equal(t, 2, clearedEvent().Data["count"])
+
+		// Only alerts are left
+		result, err = tool.Execute(context.Background(), map[string]interface{}{})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "includeAlerts")
+	})
+
+	t.Run("IncludeAlertsClearsDevice", func(t *testing.T) {
+		sent()
+		result, err := tool.Execute(context.Background(), map[string]interface{}{"includeAlerts": true})
+		require.NoError(t, err)
+		text := result.Content[0].(mcp.TextContent).Text
+		require.False(t, result.IsError, text)
+		assert.Contains(t, text, "Stopped 1 effect(s): 'page' (page-id)")
+
+		// The integration alert stays and shows again
+		stack := stateManager.GetEffectStack()
+		require.Len(t, stack, 1)
+		assert.Equal(t, "grafana", stack[0].Name)
+		assert.Equal(t, []string{"top_init=1&top_bg=ffff00"}, sent())
+
+		stateManager.RemoveEffects(func(state.EffectStackItem) bool { return true })
+		push("calm", "top_init=1&top_bg=0000ff", map[string]interface{}{"perpetual": true})
+		result, err = tool.Execute(context.Background(), map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "all LEDs were cleared")
+		assert.Equal(t, []string{"top_init=1&bottom_init=1&logo=off"}, sent())
+	})
+
+	t.Run("InvalidArguments", func(t *testing.T) {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{"toDepth": -1.0})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		result, err = tool.Execute(context.Background(), map[string]interface{}{"includeAlerts": "yes"})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+	})
+}
@@ -3,10 +3,13 @@ package tools
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
 	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
 	"github.com/starspace46/ufo-mcp-go/internal/state"
 )
 
@@ -15,14 +18,16 @@ type StopEffectTool struct {
 	client       *device.Client
 	broadcaster  *events.Broadcaster
 	stateManager *state.Manager
+	engine       *effects.Engine
 }
 
 // NewStopEffectTool creates a new stopEffect tool instance
-func NewStopEffectTool(client *device.Client, broadcaster *events.Broadcaster, stateManager *state.Manager) *StopEffectTool {
+func NewStopEffectTool(client *device.Client, broadcaster *events.Broadcaster, stateManager *state.Manager, engine *effects.Engine) *StopEffectTool {
 	return &StopEffectTool{
 		client:       client,
 		broadcaster:  broadcaster,
 		stateManager: stateManager,
+		engine:       engine,
 	}
 }
 
@@ -30,10 +35,15 @@ func NewStopEffectTool(client *device.Client, broadcaster *events.Broadcaster, s
 func (t *StopEffectTool) Definition() mcp.Tool {
 	return mcp.Tool{
 		Name:        "stopEffect",
-		Description: "Stop the current effect and resume the previous one from the stack. If no previous effect exists, the UFO will be cleared.",
+		Description: "Stop the current effect and resume the previous one from the stack. If no previous effect exists, the UFO will be cleared. Pass an instanceId from getEffectStack to stop a specific entry instead, wherever it is on the stack.",
 		InputSchema: mcp.ToolInputSchema{
-			Type:       "object",
-			Properties: map[string]interface{}{},
+			Type: "object",
+			Properties: map[string]interface{}{
+				"instanceId": map[string]interface{}{
+					"type":        "string",
+					"description": "Instance ID of the stack entry to stop (optional, default the current effect)",
+				},
+			},
 		},
 	}
 }
@@ -54,6 +64,26 @@ func (t *StopEffectTool) Execute(ctx context.Context, arguments map[string]inter
 		}, nil
 	}
 
+	// Pick the entry to stop; a buried one is removed without touching the UFO
+	if value, exists := arguments["instanceId"]; exists {
+		instanceID, ok := value.(string)
+		if !ok || instanceID == "" {
+			return invalidArgument("instanceId", "'instanceId' must be a non-empty string"), nil
+		}
+		target := t.stateManager.FindInstance(instanceID)
+		if target == nil {
+			return toolError(CodeNotFound, fmt.Sprintf("no effect with instance ID '%s' is on the stack", instanceID)), nil
+		}
+		if target.InstanceID() != currentEffect.InstanceID() {
+			return t.stopBuried(ctx, *target, *currentEffect)
+		}
+	}
+
+	// Raised alerts are only cleared explicitly
+	if alert := raisedAlertName(*currentEffect); alert != "" {
+		return ToolError{Code: CodeConflict, Message: fmt.Sprintf("'%s' is showing alert '%s'", currentEffect.Name, alert), Hint: "Use clearAlert to clear it"}.Result(), nil
+	}
+
 	// Pop the current effect and get the previous one
 	previousEffect := t.stateManager.PopEffect()
 	
@@ -61,68 +91,51 @@ func (t *StopEffectTool) Execute(ctx context.Context, arguments map[string]inter
 	if previousEffect != nil {
 		// Resume the previous effect
 		query := previousEffect.Pattern
-		_, err := t.client.SendRawQuery(ctx, query)
+		err := t.engine.Apply(ctx, previousEffect.Name, query, effects.StepsFromContext(previousEffect.Context))
 		if err != nil {
-			t.broadcaster.PublishRawExecuted(query, fmt.Sprintf("ERROR: %v", err))
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: fmt.Sprintf("Failed to resume previous effect: %v", err),
-					},
-				},
-				IsError: true,
-			}, nil
+			t.broadcaster.PublishRawExecuted(ctx, query, fmt.Sprintf("ERROR: %v", err))
+			return toolError(CodeDeviceError, fmt.Sprintf("Failed to resume previous effect: %v", err)), nil
 		}
 		
-		t.broadcaster.PublishRawExecuted(query, "OK")
+		t.broadcaster.PublishRawExecuted(ctx, query, "OK")
 		
 		// Emit effect resumed event
-		t.broadcaster.Publish(events.Event{
-			Type: events.EventEffectResumed,
-			Data: map[string]interface{}{
-				"effect":     previousEffect.Name,
-				"stackDepth": t.stateManager.GetEffectStackDepth(),
-			},
+		t.broadcaster.PublishPayload(ctx, events.EffectResumed{
+			Effect:     previousEffect.Name,
+			InstanceID: previousEffect.InstanceID(),
+			StackDepth: t.stateManager.GetEffectStackDepth(),
 		})
 		
-		message = fmt.Sprintf("⏹️ Stopped '%s' and resumed '%s' (stack depth: %d)", 
-			currentEffect.Name, previousEffect.Name, t.stateManager.GetEffectStackDepth())
+		message = fmt.Sprintf("⏹️ Stopped '%s' and resumed '%s' (stack depth: %d, stopped instance %s)", 
+			currentEffect.Name, previousEffect.Name, t.stateManager.GetEffectStackDepth(), currentEffect.InstanceID())
 	} else {
 		// No previous effect, clear the UFO
 		query := "top_init=1&bottom_init=1&logo=off"
-		_, err := t.client.SendRawQuery(ctx, query)
+		t.engine.StopLogo()
+		err := t.engine.Apply(ctx, "", query, nil)
 		if err != nil {
-			t.broadcaster.PublishRawExecuted(query, fmt.Sprintf("ERROR: %v", err))
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: fmt.Sprintf("Failed to clear UFO: %v", err),
-					},
-				},
-				IsError: true,
-			}, nil
+			t.broadcaster.PublishRawExecuted(ctx, query, fmt.Sprintf("ERROR: %v", err))
+			return toolError(CodeDeviceError, fmt.Sprintf("Failed to clear UFO: %v", err)), nil
 		}
 		
-		t.broadcaster.PublishRawExecuted(query, "OK")
+		t.broadcaster.PublishRawExecuted(ctx, query, "OK")
 		
 		// Update LED state to all black
 		t.stateManager.UpdateTopRing(make([]string, 15))
 		t.stateManager.UpdateBottomRing(make([]string, 15))
 		t.stateManager.UpdateLogo(false)
+		t.stateManager.UpdateLogoAnimation(nil)
 		
-		message = fmt.Sprintf("⏹️ Stopped '%s' and cleared all LEDs (stack empty)", currentEffect.Name)
+		message = fmt.Sprintf("⏹️ Stopped '%s' and cleared all LEDs (stack empty, stopped instance %s)", currentEffect.Name, currentEffect.InstanceID())
 	}
+	message += stoppedTiming(*currentEffect, time.Now())
 	
 	// Emit effect stopped event
-	t.broadcaster.Publish(events.Event{
-		Type: events.EventEffectStopped,
-		Data: map[string]interface{}{
-			"effect":     currentEffect.Name,
-			"manual":     true,
-			"stackDepth": t.stateManager.GetEffectStackDepth(),
-		},
+	t.broadcaster.PublishPayload(ctx, events.EffectStopped{
+		Effect:     currentEffect.Name,
+		InstanceID: currentEffect.InstanceID(),
+		Manual:     true,
+		StackDepth: t.stateManager.GetEffectStackDepth(),
 	})
 	
 	return &mcp.CallToolResult{
@@ -134,4 +147,41 @@ func (t *StopEffectTool) Execute(ctx context.Context, arguments map[string]inter
 		},
 		IsError: false,
 	}, nil
-}
\ No newline at end of file
+}
+
+// stopBuried removes an entry below the top of the stack, leaving the effect
+// on top showing
+func (t *StopEffectTool) stopBuried(ctx context.Context, target, top state.EffectStackItem) (*mcp.CallToolResult, error) {
+	if alert := raisedAlertName(target); alert != "" {
+		return ToolError{Code: CodeConflict, Message: fmt.Sprintf("'%s' is alert '%s'", target.Name, alert), Hint: "Use clearAlert to clear it"}.Result(), nil
+	}
+
+	t.stateManager.RemoveEffects(func(item state.EffectStackItem) bool {
+		return item.InstanceID() == target.InstanceID()
+	})
+	t.broadcaster.PublishPayload(ctx, events.EffectStopped{
+		Effect:     target.Name,
+		InstanceID: target.InstanceID(),
+		Manual:     true,
+		StackDepth: t.stateManager.GetEffectStackDepth(),
+	})
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("⏹️ Stopped '%s' from beneath '%s', which keeps showing (stack depth: %d, stopped instance %s)",
+					target.Name, top.Name, t.stateManager.GetEffectStackDepth(), target.InstanceID()) + stoppedTiming(target, time.Now()),
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// stoppedTiming describes how long a stopped entry ran and when it stopped
+func stoppedTiming(item state.EffectStackItem, now time.Time) string {
+	if item.StartTime().IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("\n• Ran for: %s\n• Stopped at: %s", format.Duration(item.Elapsed(now)), format.Time(now))
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
 	"github.com/starspace46/ufo-mcp-go/internal/events"
 	"github.com/starspace46/ufo-mcp-go/internal/state"
 	"github.com/stretchr/testify/assert"
@@ -32,14 +33,14 @@ func TestStopEffectTool(t *testing.T) {
 	stateManager := state.NewManager(broadcaster)
 
 	// Create tool
-	tool := NewStopEffectTool(client, broadcaster, stateManager)
+	tool := NewStopEffectTool(client, broadcaster, stateManager, effects.NewEngine(client))
 
 	t.Run("Definition", func(t *testing.T) {
 		def := tool.Definition()
 		assert.Equal(t, "stopEffect", def.Name)
 		assert.Contains(t, def.Description, "Stop the current effect")
 		assert.Equal(t, "object", def.InputSchema.Type)
-		assert.Empty(t, def.InputSchema.Properties)
+		assert.Contains(t, def.InputSchema.Properties, "instanceId")
 	})
 
 	t.Run("NoEffectRunning", func(t *testing.T) {
@@ -98,4 +99,29 @@ func TestStopEffectTool(t *testing.T) {
 		}
 		assert.False(t, ledState.LogoOn)
 	})
+
+	t.Run("StopInstance", func(t *testing.T) {
+		// Two pushes of the same effect are told apart by instance ID
+		stateManager.PushEffect("effect1", "pattern1", map[string]interface{}{})
+		stateManager.PushEffect("effect1", "pattern1", map[string]interface{}{})
+		stateManager.PushEffect("effect2", "pattern2", map[string]interface{}{})
+		stack := stateManager.GetEffectStack()
+		require.Len(t, stack, 3)
+		assert.NotEqual(t, stack[0].InstanceID(), stack[1].InstanceID())
+
+		result, err := tool.Execute(context.Background(), map[string]interface{}{"instanceId": stack[0].InstanceID()})
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Stopped 'effect1' from beneath 'effect2'")
+
+		remaining := stateManager.GetEffectStack()
+		require.Len(t, remaining, 2)
+		assert.Equal(t, stack[1].InstanceID(), remaining[0].InstanceID())
+		assert.Equal(t, "effect2", stateManager.GetCurrentEffect().Name)
+
+		result, err = tool.Execute(context.Background(), map[string]interface{}{"instanceId": "nope"})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "no effect with instance ID 'nope'")
+	})
 }
\ No newline at end of file
@@ -0,0 +1,349 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+const (
+	// defaultTestStepMs is how long testDevice shows each frame by default
+	defaultTestStepMs = 150
+	// maxTestStepMs is the longest testDevice shows each frame
+	maxTestStepMs = 2000
+	// testColor is the color testDevice lights LEDs in
+	testColor = "FFFFFF"
+	// slowAverageLatency and slowMaxLatency are the latencies above which
+	// testDevice warns that the UFO answers slowly
+	slowAverageLatency = 250 * time.Millisecond
+	slowMaxLatency     = time.Second
+)
+
+// Verdicts of testDevice
+const (
+	verdictPass = "pass"
+	verdictWarn = "warn"
+	verdictFail = "fail"
+)
+
+// TestDeviceTool implements the testDevice MCP tool, which runs a diagnostic
+// sequence on the UFO and reports how it answered
+type TestDeviceTool struct {
+	client       *device.Client
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+	engine       *effects.Engine
+}
+
+// NewTestDeviceTool creates a new testDevice tool instance
+func NewTestDeviceTool(client *device.Client, broadcaster *events.Broadcaster, stateManager *state.Manager, engine *effects.Engine) *TestDeviceTool {
+	return &TestDeviceTool{
+		client:       client,
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		engine:       engine,
+	}
+}
+
+// Definition returns the MCP tool definition for testDevice
+func (t *TestDeviceTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "testDevice",
+		Description: fmt.Sprintf("Check a UFO, e.g. after mounting it: light each LED of both rings in turn, flash both rings white and blink the logo at full brightness, timing every request and reading back what the UFO reports it shows. Returns a verdict (pass, warn or fail) on connectivity and pixel health, then shows what was showing before. Takes about %s with the default stepMs.", format.Millis(int64(testFrames*defaultTestStepMs))),
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"stepMs": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("How long each frame shows in milliseconds (optional, default %d)", defaultTestStepMs),
+					"minimum":     0,
+					"maximum":     maxTestStepMs,
+				},
+			},
+		},
+	}
+}
+
+// testFrame is one frame of the diagnostic sequence with what the UFO
+// should then report
+type testFrame struct {
+	query string
+	lit   func(led int) bool // which ring LEDs should be lit, nil to not check
+	logo  *bool              // whether the logo should be on, nil to not check
+}
+
+// testFrames is the number of frames in the diagnostic sequence
+const testFrames = device.RingLEDs + 5
+
+// testSequence returns the diagnostic frames: each LED of both rings in
+// turn, both rings white, then the logo blinking twice
+func testSequence() []testFrame {
+	on, off := true, false
+	frames := make([]testFrame, 0, testFrames)
+	for i := 0; i < device.RingLEDs; i++ {
+		frames = append(frames, testFrame{
+			query: fmt.Sprintf("top_init=1&top=%d|1|%s&bottom_init=1&bottom=%d|1|%s", i, testColor, i, testColor),
+			lit:   func(led int) bool { return led == i },
+		})
+	}
+	// The first frame also sets full brightness and turns the logo off
+	frames[0].query = "dim=255&logo=off&" + frames[0].query
+	frames[0].logo = &off
+	frames = append(frames, testFrame{
+		query: fmt.Sprintf("top_init=1&top_bg=%s&bottom_init=1&bottom_bg=%s", testColor, testColor),
+		lit:   func(int) bool { return true },
+	})
+	for range 2 {
+		frames = append(frames,
+			testFrame{query: "top_init=1&bottom_init=1&logo=on", lit: func(int) bool { return false }, logo: &on},
+			testFrame{query: "logo=off", logo: &off},
+		)
+	}
+	return frames
+}
+
+// deviceTestReport is the outcome of testDevice
+type deviceTestReport struct {
+	Verdict        string   `json:"verdict"` // pass, warn or fail
+	Requests       int      `json:"requests"`
+	Failed         int      `json:"failed"`
+	LatencyMinMs   int64    `json:"latencyMinMs"`
+	LatencyAvgMs   int64    `json:"latencyAvgMs"`
+	LatencyMaxMs   int64    `json:"latencyMaxMs"`
+	PixelsReported bool     `json:"pixelsReported"` // whether the firmware reports LED colors
+	PixelsChecked  int      `json:"pixelsChecked,omitempty"`
+	PixelsHealthy  int      `json:"pixelsHealthy,omitempty"`
+	DarkPixels     []string `json:"darkPixels,omitempty"`  // LEDs that did not light, e.g. "top 7"
+	StuckPixels    []string `json:"stuckPixels,omitempty"` // LEDs lit when they should be dark
+	Logo           string   `json:"logo"`                  // ok, failed or unreported
+	Errors         []string `json:"errors,omitempty"`
+	Restored       string   `json:"restored"`
+}
+
+// deviceTest collects the measurements of one testDevice run
+type deviceTest struct {
+	latencies []time.Duration
+	errors    []string
+	reported  bool
+	dark      map[int]bool // pixels that did not light, see pixelNames
+	stuck     map[int]bool // pixels lit when they should be dark
+	logo      string
+}
+
+// Execute runs the testDevice tool
+func (t *TestDeviceTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	stepMs := defaultTestStepMs
+	if value, exists := arguments["stepMs"]; exists {
+		ms, ok := wholeNumber(value)
+		if !ok || ms < 0 || ms > maxTestStepMs {
+			return invalidArgument("stepMs", fmt.Sprintf("'stepMs' must be a whole number of milliseconds from 0 to %d", maxTestStepMs)), nil
+		}
+		stepMs = ms
+	}
+
+	run := &deviceTest{dark: map[int]bool{}, stuck: map[int]bool{}, logo: "unreported"}
+	if _, err := run.fetch(ctx, t.client); err != nil {
+		return toolError(CodeDeviceError, fmt.Sprintf("The UFO at %s did not answer: %v", t.client.Address(), err)), nil
+	}
+
+	// Exact colors, so what the UFO reports can be compared with what was sent
+	testCtx := device.WithPassthrough(ctx)
+	for i, frame := range testSequence() {
+		start := time.Now()
+		err := t.engine.Apply(testCtx, "", frame.query, nil)
+		run.latencies = append(run.latencies, time.Since(start))
+		if err != nil {
+			t.broadcaster.PublishRawExecuted(ctx, frame.query, fmt.Sprintf("ERROR: %v", err))
+			run.errors = append(run.errors, fmt.Sprintf("frame %d: %v", i+1, err))
+		} else {
+			t.broadcaster.PublishRawExecuted(ctx, frame.query, "OK")
+			if status, err := run.fetch(ctx, t.client); err != nil {
+				run.errors = append(run.errors, fmt.Sprintf("reading back frame %d: %v", i+1, err))
+			} else {
+				run.check(frame, status)
+			}
+		}
+		if !effects.Sleep(ctx, time.Duration(stepMs)*time.Millisecond) {
+			break
+		}
+	}
+
+	report := run.report()
+	// Cancelling the call still leaves the UFO as it was
+	restoreCtx := context.WithoutCancel(ctx)
+	if err := ReapplyState(restoreCtx, t.engine, t.broadcaster, t.stateManager); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("restoring: %v", err))
+		report.Verdict = verdictFail
+		report.Restored = "failed"
+	} else if current := t.stateManager.GetCurrentEffect(); current != nil {
+		report.Restored = fmt.Sprintf("effect '%s'", current.Name)
+	} else {
+		report.Restored = "the lighting as it was"
+	}
+	if ctx.Err() != nil {
+		report.Errors = append(report.Errors, "the test was cancelled before it finished")
+		report.Verdict = verdictFail
+	}
+
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize the test report: "+err.Error()), nil
+	}
+	message := describeDeviceTest(t.client.Address(), report) + "\nFull JSON:\n" + string(reportJSON)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// fetch reads the LED state the UFO reports, timing the request
+func (r *deviceTest) fetch(ctx context.Context, client *device.Client) (*device.Status, error) {
+	start := time.Now()
+	status, err := client.FetchStatus(ctx)
+	r.latencies = append(r.latencies, time.Since(start))
+	return status, err
+}
+
+// check compares what the UFO reports after a frame with what it should show
+func (r *deviceTest) check(frame testFrame, status *device.Status) {
+	if frame.lit != nil {
+		for ring, leds := range [][]string{status.Top, status.Bottom} {
+			if leds == nil {
+				continue
+			}
+			r.reported = true
+			for led, color := range leds {
+				lit := color != "000000"
+				switch pixel := ring*device.RingLEDs + led; {
+				case frame.lit(led) && !lit:
+					r.dark[pixel] = true
+				case !frame.lit(led) && lit:
+					r.stuck[pixel] = true
+				}
+			}
+		}
+	}
+	if frame.logo != nil && status.LogoOn != nil {
+		if *status.LogoOn != *frame.logo {
+			r.logo = "failed"
+		} else if r.logo != "failed" {
+			r.logo = "ok"
+		}
+	}
+}
+
+// report summarizes the measurements and gives the verdict
+func (r *deviceTest) report() deviceTestReport {
+	report := deviceTestReport{
+		Requests:       len(r.latencies),
+		Failed:         len(r.errors),
+		PixelsReported: r.reported,
+		DarkPixels:     pixelNames(r.dark),
+		StuckPixels:    pixelNames(r.stuck),
+		Logo:           r.logo,
+		Errors:         r.errors,
+	}
+	var total time.Duration
+	for i, latency := range r.latencies {
+		total += latency
+		if i == 0 || latency.Milliseconds() < report.LatencyMinMs {
+			report.LatencyMinMs = latency.Milliseconds()
+		}
+		report.LatencyMaxMs = max(report.LatencyMaxMs, latency.Milliseconds())
+	}
+	average := total / time.Duration(len(r.latencies))
+	report.LatencyAvgMs = average.Milliseconds()
+	if r.reported {
+		report.PixelsChecked = 2 * device.RingLEDs
+		faulty := maps.Clone(r.dark)
+		maps.Copy(faulty, r.stuck)
+		report.PixelsHealthy = report.PixelsChecked - len(faulty)
+	}
+
+	switch {
+	case report.Failed > 0 || len(report.DarkPixels) > 0 || len(report.StuckPixels) > 0 || report.Logo == "failed":
+		report.Verdict = verdictFail
+	case average > slowAverageLatency || report.LatencyMaxMs > slowMaxLatency.Milliseconds() || !r.reported || report.Logo == "unreported":
+		report.Verdict = verdictWarn
+	default:
+		report.Verdict = verdictPass
+	}
+	return report
+}
+
+// pixelNames names pixels, numbered from LED 0 of the top ring on, e.g.
+// "bottom 3", in order
+func pixelNames(pixels map[int]bool) []string {
+	var names []string
+	for _, pixel := range slices.Sorted(maps.Keys(pixels)) {
+		ring := "top"
+		if pixel >= device.RingLEDs {
+			ring = "bottom"
+		}
+		names = append(names, fmt.Sprintf("%s %d", ring, pixel%device.RingLEDs))
+	}
+	return names
+}
+
+// describeDeviceTest summarizes a testDevice report for people
+func describeDeviceTest(address string, report deviceTestReport) string {
+	var message string
+	switch report.Verdict {
+	case verdictPass:
+		message = fmt.Sprintf("✅ The UFO at %s passed the test\n\n", address)
+	case verdictWarn:
+		message = fmt.Sprintf("⚠️ The UFO at %s passed the test with warnings\n\n", address)
+	default:
+		message = fmt.Sprintf("❌ The UFO at %s failed the test\n\n", address)
+	}
+
+	message += fmt.Sprintf("• Connectivity: %d requests, %d failed; latency min %s, average %s, max %s\n",
+		report.Requests, report.Failed, format.Millis(report.LatencyMinMs), format.Millis(report.LatencyAvgMs), format.Millis(report.LatencyMaxMs))
+	if report.LatencyAvgMs > slowAverageLatency.Milliseconds() || report.LatencyMaxMs > slowMaxLatency.Milliseconds() {
+		message += "  The UFO answers slowly; check its WiFi signal.\n"
+	}
+	switch {
+	case !report.PixelsReported:
+		message += "• Pixels: the firmware does not report LED colors. Check by eye that each LED lit in turn and the white flash was even.\n"
+	case report.PixelsHealthy == report.PixelsChecked:
+		message += fmt.Sprintf("• Pixels: all %d LEDs lit and went dark as asked\n", report.PixelsChecked)
+	default:
+		message += fmt.Sprintf("• Pixels: %d of %d LEDs healthy (about %d%%)\n", report.PixelsHealthy, report.PixelsChecked, report.PixelsHealthy*100/report.PixelsChecked)
+		if len(report.DarkPixels) > 0 {
+			message += fmt.Sprintf("  Did not light: %s\n", strings.Join(report.DarkPixels, ", "))
+		}
+		if len(report.StuckPixels) > 0 {
+			message += fmt.Sprintf("  Lit when they should be dark: %s\n", strings.Join(report.StuckPixels, ", "))
+		}
+	}
+	switch report.Logo {
+	case "ok":
+		message += "• Logo: blinked as asked\n"
+	case "failed":
+		message += "• Logo: did not follow the blink\n"
+	default:
+		message += "• Logo: the firmware does not report it; check by eye that it blinked twice\n"
+	}
+	message += fmt.Sprintf("• Restored: %s\n", report.Restored)
+	for _, problem := range report.Errors {
+		message += fmt.Sprintf("  Error: %s\n", problem)
+	}
+	return message
+}
@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testReport returns the JSON report at the end of a testDevice result
+func testReport(t *testing.T, result *mcp.CallToolResult) deviceTestReport {
+	t.Helper()
+	text := result.Content[0].(mcp.TextContent).Text
+	_, reportJSON, found := strings.Cut(text, "Full JSON:\n")
+	require.True(t, found, text)
+	var report deviceTestReport
+	require.NoError(t, json.Unmarshal([]byte(reportJSON), &report))
+	return report
+}
+
+func TestTestDeviceTool_Execute(t *testing.T) {
+	simulator := device.NewSimulator()
+	client := device.NewSimulatedClient(simulator)
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	engine := effects.NewEngine(client)
+	tool := NewTestDeviceTool(client, broadcaster, stateManager, engine)
+
+	stateManager.PushEffect("calm", "top_init=1&top_bg=0000ff", map[string]interface{}{"perpetual": true})
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"stepMs": 0})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "passed the test")
+	report := testReport(t, result)
+	assert.Equal(t, verdictPass, report.Verdict)
+	assert.Equal(t, 1+2*testFrames, report.Requests)
+	assert.Zero(t, report.Failed)
+	assert.Equal(t, 2*device.RingLEDs, report.PixelsHealthy)
+	assert.Equal(t, "ok", report.Logo)
+	assert.Equal(t, "effect 'calm'", report.Restored)
+
+	// The effect shows again afterwards
+	status, err := client.FetchStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "0000ff", status.Top[0])
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"stepMs": 5000})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestTestDeviceTool_Execute_DeadPixel(t *testing.T) {
+	// A UFO whose top LED 7 never lights
+	simulator := device.NewSimulator()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api" || r.URL.RawQuery != "" {
+			simulator.ServeHTTP(w, r)
+			return
+		}
+		recorder := httptest.NewRecorder()
+		simulator.ServeHTTP(recorder, r)
+		var status map[string]interface{}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+		status["top"].([]interface{})[7] = "000000"
+		json.NewEncoder(w).Encode(status)
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	client := device.NewClient()
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	tool := NewTestDeviceTool(client, broadcaster, stateManager, effects.NewEngine(client))
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"stepMs": 0})
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "failed the test")
+	assert.Contains(t, text, "Did not light: top 7")
+	report := testReport(t, result)
+	assert.Equal(t, verdictFail, report.Verdict)
+	assert.Equal(t, []string{"top 7"}, report.DarkPixels)
+	assert.Empty(t, report.StuckPixels)
+	assert.Equal(t, 2*device.RingLEDs-1, report.PixelsHealthy)
+}
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+)
+
+// defaultTestHoldMs is how long test lighting stays on the UFO by default
+const defaultTestHoldMs = 5000
+
+// maxTestHoldMs caps how long test lighting can stay on the UFO
+const maxTestHoldMs = 60000
+
+// TestIntegrationTool implements the testIntegration MCP tool
+type TestIntegrationTool struct {
+	registry *integrations.Registry
+}
+
+// NewTestIntegrationTool creates a new testIntegration tool instance
+func NewTestIntegrationTool(registry *integrations.Registry) *TestIntegrationTool {
+	return &TestIntegrationTool{
+		registry: registry,
+	}
+}
+
+// Definition returns the MCP tool definition for testIntegration
+func (t *TestIntegrationTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "testIntegration",
+		Description: "Send a synthetic event through a configured integration's full pipeline (mapping, rules, lighting) and report each step, to verify configuration without waiting for a real alert. The test lighting is cleared after holdMs.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": fmt.Sprintf("Integration to test: %s", strings.Join(t.registry.Names(), ", ")),
+				},
+				"input": map[string]interface{}{
+					"type":        "object",
+					"description": "Optional event details. grafana: {labels}. pagerduty: {status, service, title}. jenkins: {job, state}. dynatrace: {severity, title}. weather: {condition, temperature}. bindings: {binding, value}",
+				},
+				"holdMs": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("How long to show the test lighting in milliseconds (default %d, max %d)", defaultTestHoldMs, maxTestHoldMs),
+				},
+			},
+			Required: []string{"name"},
+		},
+	}
+}
+
+// Execute runs the testIntegration tool
+func (t *TestIntegrationTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	name, ok := arguments["name"].(string)
+	if !ok || name == "" {
+		return missingArgument("name", "'name' parameter is required and must be a non-empty string"), nil
+	}
+
+	input, _ := arguments["input"].(map[string]interface{})
+
+	holdMs := defaultTestHoldMs
+	if value, exists := arguments["holdMs"]; exists {
+		ms, ok := value.(float64)
+		if !ok || ms < 0 || ms > maxTestHoldMs {
+			return invalidArgument("holdMs", fmt.Sprintf("'holdMs' must be a number between 0 and %d", maxTestHoldMs)), nil
+		}
+		holdMs = int(ms)
+	}
+
+	report, err := t.registry.Test(ctx, name, input, time.Duration(holdMs)*time.Millisecond)
+	if err != nil {
+		return ToolError{Code: CodeNotFound, Message: err.Error(), Parameter: "name", Hint: "Configured integrations: " + strings.Join(t.registry.Names(), ", ")}.Result(), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: FormatTestReport(report),
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// FormatTestReport renders a test report as one line per pipeline step
+func FormatTestReport(report *integrations.TestReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🧪 Testing integration '%s'\n", report.Integration)
+	for _, step := range report.Steps {
+		mark := "✅"
+		if !step.OK {
+			mark = "❌"
+		}
+		fmt.Fprintf(&b, "%s %s: %s\n", mark, step.Name, step.Detail)
+	}
+
+	if report.Passed {
+		b.WriteString("\nResult: passed")
+	} else {
+		b.WriteString("\nResult: failed")
+	}
+	if report.ClearsAfterMs > 0 {
+		fmt.Fprintf(&b, "\nTest lighting clears in %dms", report.ClearsAfterMs)
+	}
+	return b.String()
+}
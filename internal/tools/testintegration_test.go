@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatTestReport(t *testing.T) {
+	text := FormatTestReport(&integrations.TestReport{
+		Integration: "grafana",
+		Steps: []integrations.TestStep{
+			{Name: "mapping", OK: true, Detail: "firing alert"},
+			{Name: "rules", OK: false, Detail: "no route matches"},
+		},
+	})
+	assert.Contains(t, text, "✅ mapping: firing alert")
+	assert.Contains(t, text, "❌ rules: no route matches")
+	assert.Contains(t, text, "Result: failed")
+}
+
+func TestTestIntegrationTool_Errors(t *testing.T) {
+	tool := NewTestIntegrationTool(integrations.NewRegistry(effects.NewEngine(device.NewClient())))
+
+	for _, args := range []map[string]interface{}{
+		{},
+		{"name": "grafana"},
+		{"name": "grafana", "holdMs": float64(120000)},
+	} {
+		result, err := tool.Execute(context.Background(), args)
+		require.NoError(t, err)
+		assert.True(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+	}
+}
+
+func TestIntegrationManagementTools(t *testing.T) {
+	registry := integrations.NewRegistry(effects.NewEngine(device.NewClient()))
+	list := NewListIntegrationsTool(registry)
+	result, err := list.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "[]", result.Content[0].(mcp.TextContent).Text)
+
+	for _, args := range []map[string]interface{}{{}, {"name": "grafana"}} {
+		result, err = NewEnableIntegrationTool(registry).Execute(context.Background(), args)
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+
+		result, err = NewDisableIntegrationTool(registry).Execute(context.Background(), args)
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+	}
+}
@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Codes of tool errors, telling a client how it might recover
+const (
+	// CodeMissingArgument means a required argument was not given
+	CodeMissingArgument = "missing_argument"
+	// CodeInvalidArgument means an argument has the wrong type or value;
+	// fixing it and calling again may succeed
+	CodeInvalidArgument = "invalid_argument"
+	// CodeNotFound means a named effect, scene, alert or other item does not
+	// exist; the matching list tool shows what does
+	CodeNotFound = "not_found"
+	// CodeConflict means the call clashes with the current state, such as a
+	// name already taken or an alert holding the UFO; it may succeed once
+	// that changes
+	CodeConflict = "conflict"
+	// CodeDeviceError means the UFO could not be reached or rejected the
+	// command; retrying later may succeed
+	CodeDeviceError = "device_error"
+	// CodeUnavailable means the feature is not configured on this server
+	CodeUnavailable = "unavailable"
+	// CodeForbidden means the server's policy or read-only mode does not
+	// allow the call; calling again will not help
+	CodeForbidden = "forbidden"
+	// CodeInternal means the server failed, e.g. to save or serialize data
+	CodeInternal = "internal"
+)
+
+// ToolError describes why a tool call failed. Every tool reports errors
+// this way: the text reads "Error: <message>", followed by the hint when
+// there is one, and the same fields are in the result's _meta under
+// "error" for clients that act on them.
+type ToolError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Parameter string `json:"parameter,omitempty"` // the argument at fault, if any
+	Hint      string `json:"hint,omitempty"`      // how to fix the call, if known
+}
+
+// Result builds the result of the failed tool call
+func (e ToolError) Result() *mcp.CallToolResult {
+	text := "Error: " + e.Message
+	if e.Hint != "" {
+		text += "\n\nHint: " + e.Hint
+	}
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+		IsError: true,
+	}
+	result.Meta = map[string]any{"error": e}
+	return result
+}
+
+// toolError builds the result of a tool call that failed with code
+func toolError(code, message string) *mcp.CallToolResult {
+	return ToolError{Code: code, Message: message}.Result()
+}
+
+// invalidArgument builds the result of a tool call with a bad argument
+func invalidArgument(parameter, message string) *mcp.CallToolResult {
+	return ToolError{Code: CodeInvalidArgument, Message: message, Parameter: parameter}.Result()
+}
+
+// missingArgument builds the result of a tool call without a required
+// argument
+func missingArgument(parameter, message string) *mcp.CallToolResult {
+	return ToolError{Code: CodeMissingArgument, Message: message, Parameter: parameter}.Result()
+}
+
+// ErrorOf returns the error a tool call result reports, if it failed
+func ErrorOf(result *mcp.CallToolResult) (ToolError, bool) {
+	if result == nil || !result.IsError {
+		return ToolError{}, false
+	}
+	if e, ok := result.Meta["error"].(ToolError); ok {
+		return e, true
+	}
+	return ToolError{Code: CodeInternal}, true
+}
+
+// effectNotFound builds the result of a tool call naming an effect, in
+// parameter, that does not exist
+func effectNotFound(parameter, name string) *mcp.CallToolResult {
+	return ToolError{
+		Code:      CodeNotFound,
+		Message:   fmt.Sprintf("Effect '%s' not found", name),
+		Parameter: parameter,
+		Hint:      "Use listEffects to see available effects.",
+	}.Result()
+}
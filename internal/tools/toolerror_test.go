@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/macros"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// refusingCaller fails every tool call with the same error
+type refusingCaller struct {
+	result *mcp.CallToolResult
+}
+
+func (c *refusingCaller) HasTool(name string) bool {
+	return true
+}
+
+func (c *refusingCaller) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	return c.result, nil
+}
+
+func TestToolError_Result(t *testing.T) {
+	result := ToolError{Code: CodeNotFound, Message: "no scene named 'demo'", Parameter: "name", Hint: "Use listScenes to see the saved scenes"}.Result()
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Error: no scene named 'demo'\n\nHint: Use listScenes to see the saved scenes", result.Content[0].(mcp.TextContent).Text)
+
+	// Clients see the fields in _meta
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"_meta":{"error":{"code":"not_found","message":"no scene named 'demo'","parameter":"name","hint":"Use listScenes to see the saved scenes"}}`)
+
+	result = invalidArgument("level", "'level' parameter must be a number")
+	assert.Equal(t, "Error: 'level' parameter must be a number", result.Content[0].(mcp.TextContent).Text)
+	data, err = json.Marshal(result)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "hint")
+}
+
+func TestErrorOf(t *testing.T) {
+	_, failed := ErrorOf(mcp.NewToolResultText("done"))
+	assert.False(t, failed)
+	_, failed = ErrorOf(nil)
+	assert.False(t, failed)
+
+	toolErr, failed := ErrorOf(missingArgument("pattern", "'pattern' parameter is required"))
+	assert.True(t, failed)
+	assert.Equal(t, ToolError{Code: CodeMissingArgument, Message: "'pattern' parameter is required", Parameter: "pattern"}, toolErr)
+
+	// Results built elsewhere still count as failures
+	toolErr, failed = ErrorOf(mcp.NewToolResultError("refused"))
+	assert.True(t, failed)
+	assert.Equal(t, CodeInternal, toolErr.Code)
+}
+
+func TestToolErrors_FromTools(t *testing.T) {
+	result, err := NewValidatePatternTool().Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	toolErr, failed := ErrorOf(result)
+	require.True(t, failed)
+	assert.Equal(t, CodeMissingArgument, toolErr.Code)
+	assert.Equal(t, "pattern", toolErr.Parameter)
+
+	// A macro that stops reports the error of the step that failed
+	store := macros.NewStore(filepath.Join(t.TempDir(), "macros.json"))
+	_, _, err = store.Save(macros.Macro{Name: "demo", Steps: []macros.Step{{Tool: "playEffect", Arguments: map[string]interface{}{"name": "nope"}}}})
+	require.NoError(t, err)
+	caller := &refusingCaller{result: effectNotFound("name", "nope")}
+	result, err = NewRunMacroTool(store, caller).Execute(context.Background(), map[string]interface{}{"name": "demo"})
+	require.NoError(t, err)
+	toolErr, failed = ErrorOf(result)
+	require.True(t, failed)
+	assert.Equal(t, CodeNotFound, toolErr.Code)
+	assert.Equal(t, "name", toolErr.Parameter)
+	assert.Equal(t, "macro 'demo' step 1 (playEffect): Effect 'nope' not found", toolErr.Message)
+}
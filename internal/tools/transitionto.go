@@ -0,0 +1,244 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/format"
+	"github.com/starspace46/ufo-mcp-go/internal/palettes"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+const (
+	// defaultTransitionMs is how long a crossfade takes when not given
+	defaultTransitionMs = 1000
+	// maxTransitionMs is the longest crossfade
+	maxTransitionMs = 60000
+	// defaultFrameMs is the time between intermediate frames; at the
+	// default write rate of 10 per second every frame reaches the UFO
+	defaultFrameMs = 100
+	// minFrameMs is the shortest time between intermediate frames
+	minFrameMs = 50
+	// transitionName is what the engine reports running while a crossfade
+	// is in progress
+	transitionName = "transitionTo"
+)
+
+// TransitionToTool implements the transitionTo MCP tool, which crossfades
+// from what the UFO shows to a configureLighting payload
+type TransitionToTool struct {
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+	engine       *effects.Engine // runs the crossfade as its animation
+	lighting     *ConfigureLightingTool
+}
+
+// transition is a validated transitionTo request
+type transition struct {
+	config   *lightingConfig
+	from, to device.Frame
+	fromDim  int
+	toDim    int
+	frames   int
+	frameMs  int
+}
+
+// NewTransitionToTool creates a new transitionTo tool instance
+func NewTransitionToTool(client *device.Client, broadcaster *events.Broadcaster, stateManager *state.Manager, engine *effects.Engine, palettes *palettes.Store) *TransitionToTool {
+	return &TransitionToTool{
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		engine:       engine,
+		lighting:     NewConfigureLightingTool(client, broadcaster, stateManager, engine, palettes),
+	}
+}
+
+// Definition returns the MCP tool definition for transitionTo
+func (t *TransitionToTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "transitionTo",
+		Description: "Crossfade smoothly from what the UFO shows now to a new lighting configuration instead of switching at once. The target takes the same object as configureLighting (top, bottom, logo, brightness); ring colors and brightness are blended over durationMs, then the target is applied exactly, starting any whirl or morph. Returns immediately while the fade runs; a new transition, an effect or a configureLighting call replaces a running one.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"target": map[string]interface{}{
+					"type":        "object",
+					"description": "Lighting to fade to, as passed to configureLighting",
+					"examples": []map[string]interface{}{
+						{"top": map[string]interface{}{"background": "orange"}, "bottom": map[string]interface{}{"background": "navy"}, "brightness": 80},
+					},
+				},
+				"durationMs": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("How long the crossfade takes in milliseconds (optional, default %d)", defaultTransitionMs),
+					"minimum":     0,
+					"maximum":     maxTransitionMs,
+				},
+				"frameMs": map[string]interface{}{
+					"type":        "integer",
+					"description": fmt.Sprintf("Time between intermediate frames in milliseconds (optional, default %d)", defaultFrameMs),
+					"minimum":     minFrameMs,
+				},
+			},
+			Required: []string{"target"},
+		},
+	}
+}
+
+// Execute runs the transitionTo tool
+func (t *TransitionToTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	plan, err := t.plan(arguments)
+	if err != nil {
+		return toolError(CodeInvalidArgument, fmt.Sprintf("%v", err)), nil
+	}
+
+	// The crossfade runs as the engine's animation, so a new transition or
+	// anything else shown on the UFO takes over from one still fading, and
+	// it ends when the server shuts down
+	if !t.engine.Animate(ctx, transitionName, func(ctx context.Context) {
+		t.run(ctx, plan)
+	}) {
+		return toolError(CodeUnavailable, "The server is shutting down; the transition was not started"), nil
+	}
+
+	durationMs := plan.frames * plan.frameMs
+	message := fmt.Sprintf("🌅 Crossfading over %s in %d frames\n\n", format.Millis(int64(durationMs)), plan.frames)
+	if plan.frames == 0 {
+		message = "🌅 Switching without a crossfade\n\n"
+	}
+	for _, line := range plan.config.messages {
+		message += "• " + line + "\n"
+	}
+	message += fmt.Sprintf("\nFinishing at %s with: %s", format.Time(time.Now().Add(time.Duration(durationMs)*time.Millisecond)), plan.config.query)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// plan validates a request and works out the frames to fade between
+func (t *TransitionToTool) plan(arguments map[string]interface{}) (*transition, error) {
+	target, ok := arguments["target"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'target' must be a configureLighting object")
+	}
+	config, err := t.lighting.parseConfig(target)
+	if err != nil {
+		return nil, err
+	}
+	if config.query == "" {
+		return nil, fmt.Errorf("'target' configures nothing; give top, bottom, logo or brightness")
+	}
+	if len(config.ringBrightness) > 0 {
+		return nil, fmt.Errorf("ring brightness cannot be faded; set it with configureLighting first")
+	}
+
+	durationMs := defaultTransitionMs
+	if value, exists := arguments["durationMs"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < 0 || n > maxTransitionMs {
+			return nil, fmt.Errorf("'durationMs' must be a whole number between 0 and %d", maxTransitionMs)
+		}
+		durationMs = n
+	}
+	frameMs := defaultFrameMs
+	if value, exists := arguments["frameMs"]; exists {
+		n, ok := wholeNumber(value)
+		if !ok || n < minFrameMs {
+			return nil, fmt.Errorf("'frameMs' must be a whole number of at least %d", minFrameMs)
+		}
+		frameMs = n
+	}
+
+	current := t.stateManager.Snapshot()
+	plan := &transition{
+		config:  config,
+		from:    device.Frame{Top: current.Top, Bottom: current.Bottom},
+		fromDim: current.Dim,
+		toDim:   current.Dim,
+		frames:  durationMs / frameMs,
+		frameMs: frameMs,
+	}
+	if config.brightness != nil {
+		plan.toDim = *config.brightness
+	}
+	if plan.to, err = plan.from.Apply(config.query); err != nil {
+		return nil, fmt.Errorf("target cannot be shown: %v", err)
+	}
+	return plan, nil
+}
+
+// run sends the intermediate frames, then the target itself, through the
+// engine so a UFO that stops answering cannot hold a frame past its call
+// timeout
+func (t *TransitionToTool) run(ctx context.Context, plan *transition) {
+	shown := plan.from
+	dim := plan.fromDim
+	for i := 1; i <= plan.frames; i++ {
+		timer := time.NewTimer(time.Duration(plan.frameMs) * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if i == plan.frames {
+			break
+		}
+
+		progress := float64(i) / float64(plan.frames)
+		frame := plan.from.Blend(plan.to, progress)
+		query, err := frame.Query(shown)
+		if err != nil {
+			slog.WarnContext(ctx, "Transition frame failed", "frame", i, "error", err)
+			continue
+		}
+		if level := plan.fromDim + int(math.Round(float64(plan.toDim-plan.fromDim)*progress)); level != dim {
+			if query != "" {
+				query += "&"
+			}
+			query += fmt.Sprintf("dim=%d", level)
+			dim = level
+		}
+		if query == "" {
+			continue
+		}
+		if err := t.engine.Overlay(ctx, query); err != nil {
+			if ctx.Err() == nil {
+				slog.WarnContext(ctx, "Transition frame failed", "frame", i, "error", err)
+			}
+			continue
+		}
+		shown = frame
+	}
+
+	// Finish with the exact target so animations start and the shadow
+	// state matches
+	if err := t.engine.Overlay(ctx, plan.config.query); err != nil {
+		if ctx.Err() == nil {
+			t.broadcaster.PublishRawExecuted(ctx, plan.config.query, fmt.Sprintf("ERROR: %v", err))
+		}
+		return
+	}
+	t.broadcaster.PublishRawExecuted(ctx, plan.config.query, "OK")
+	if plan.to.Top != plan.from.Top {
+		t.stateManager.UpdateTopRing(plan.to.Top[:])
+	}
+	if plan.to.Bottom != plan.from.Bottom {
+		t.stateManager.UpdateBottomRing(plan.to.Bottom[:])
+	}
+	t.lighting.updateState(plan.config)
+}
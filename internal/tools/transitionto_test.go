@@ -0,0 +1,206 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransitionToTool_Execute(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	client := device.NewClient()
+	engine := effects.NewEngine(client)
+	tool := NewTransitionToTool(client, broadcaster, stateManager, engine, nil)
+	sent := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), queries...)
+	}
+
+	t.Run("Definition", func(t *testing.T) {
+		def := tool.Definition()
+		assert.Equal(t, "transitionTo", def.Name)
+		assert.Contains(t, def.InputSchema.Properties, "target")
+		assert.Contains(t, def.InputSchema.Properties, "durationMs")
+		assert.Contains(t, def.InputSchema.Properties, "frameMs")
+		assert.Equal(t, []string{"target"}, def.InputSchema.Required)
+	})
+
+	t.Run("Crossfades", func(t *testing.T) {
+		target := map[string]interface{}{
+			"top":        map[string]interface{}{"background": "FF0000"},
+			"brightness": 200,
+		}
+		config, err := tool.lighting.parseConfig(target)
+		require.NoError(t, err)
+
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"target":     target,
+			"durationMs": 300,
+			"frameMs":    100,
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "3 frames")
+
+		require.Eventually(t, func() bool {
+			q := sent()
+			return len(q) > 0 && q[len(q)-1] == config.query
+		}, 2*time.Second, 10*time.Millisecond)
+
+		// Two intermediate frames, then the target itself
+		q := sent()
+		require.Len(t, q, 3)
+		for _, frame := range q[:2] {
+			assert.True(t, strings.HasPrefix(frame, "top_init=1&top_bg="), frame)
+			assert.Contains(t, frame, "dim=")
+			assert.NotContains(t, frame, "bottom")
+		}
+		assert.NotEqual(t, q[0], q[1])
+
+		require.Eventually(t, func() bool {
+			return stateManager.Snapshot().Dim == 200
+		}, time.Second, 10*time.Millisecond)
+		led := stateManager.Snapshot()
+		assert.Equal(t, "ff0000", strings.ToLower(led.Top[0]))
+	})
+
+	t.Run("NewTransitionReplacesRunning", func(t *testing.T) {
+		mu.Lock()
+		queries = nil
+		mu.Unlock()
+
+		_, err := tool.Execute(context.Background(), map[string]interface{}{
+			"target":     map[string]interface{}{"top": map[string]interface{}{"background": "0000FF"}},
+			"durationMs": 5000,
+		})
+		require.NoError(t, err)
+		result, err := tool.Execute(context.Background(), map[string]interface{}{
+			"target":     map[string]interface{}{"bottom": map[string]interface{}{"background": "00FF00"}},
+			"durationMs": 0,
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "without a crossfade")
+
+		require.Eventually(t, func() bool {
+			return len(sent()) > 0
+		}, time.Second, 10*time.Millisecond)
+		time.Sleep(250 * time.Millisecond)
+		for _, query := range sent() {
+			assert.NotContains(t, query, "top", "replaced transition kept fading")
+		}
+	})
+
+	t.Run("EffectReplacesTransition", func(t *testing.T) {
+		store := effects.NewStore(filepath.Join(t.TempDir(), "effects.json"))
+		require.NoError(t, store.Add(&effects.Effect{Name: "calm", Pattern: "top_init=1&top_bg=00ff00", Perpetual: true}))
+		play := NewPlayEffectTool(client, broadcaster, store, stateManager, engine, NewRestores(engine, broadcaster, stateManager))
+		before := stateManager.Snapshot()
+		mu.Lock()
+		queries = nil
+		mu.Unlock()
+
+		_, err := tool.Execute(context.Background(), map[string]interface{}{
+			"target":     map[string]interface{}{"top": map[string]interface{}{"background": "FF00FF"}, "brightness": 10},
+			"durationMs": 600,
+		})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return len(sent()) > 0
+		}, time.Second, 10*time.Millisecond)
+
+		result, err := play.Execute(context.Background(), map[string]interface{}{"name": "calm"})
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+
+		// Nothing of the crossfade follows the effect
+		time.Sleep(800 * time.Millisecond)
+		q := sent()
+		assert.Equal(t, "top_init=1&top_bg=00ff00", q[len(q)-1])
+		led := stateManager.Snapshot()
+		assert.Equal(t, "calm", led.Effect)
+		assert.Equal(t, before.Dim, led.Dim)
+		assert.Equal(t, before.Top, led.Top)
+		assert.Empty(t, engine.Running())
+	})
+
+	t.Run("ValidationErrors", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			arguments map[string]interface{}
+			message   string
+		}{
+			{"MissingTarget", map[string]interface{}{}, "'target'"},
+			{"EmptyTarget", map[string]interface{}{"target": map[string]interface{}{}}, "configures nothing"},
+			{"ShortFrames", map[string]interface{}{"target": map[string]interface{}{"brightness": 10}, "frameMs": 10}, "'frameMs'"},
+			{"LongDuration", map[string]interface{}{"target": map[string]interface{}{"brightness": 10}, "durationMs": 120000}, "'durationMs'"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := tool.Execute(context.Background(), tt.arguments)
+				require.NoError(t, err)
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.message)
+			})
+		}
+	})
+}
+
+func TestTransitionToTool_DeviceTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	t.Setenv("UFO_IP", server.URL[7:])
+
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	client := device.NewClient()
+	engine := effects.NewEngine(client)
+	engine.SetCallTimeout(100 * time.Millisecond)
+	tool := NewTransitionToTool(client, broadcaster, stateManager, engine, nil)
+
+	// A UFO that stops answering holds the crossfade no longer than the
+	// call timeout
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"target":     map[string]interface{}{"brightness": 10},
+		"durationMs": 0,
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	require.Eventually(t, func() bool {
+		return engine.Running() == ""
+	}, time.Second, 10*time.Millisecond)
+	assert.NotEqual(t, 10, stateManager.Snapshot().Dim)
+}
@@ -0,0 +1,199 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+)
+
+// firmwarePollInterval is how often a running update's progress is read
+const firmwarePollInterval = 2 * time.Second
+
+// firmwareUpdateTimeout is how long an update may take, reboot included,
+// before it is reported as failed
+const firmwareUpdateTimeout = 10 * time.Minute
+
+// TriggerFirmwareUpdateTool implements the triggerFirmwareUpdate MCP tool
+type TriggerFirmwareUpdateTool struct {
+	client       *device.Client
+	broadcaster  *events.Broadcaster
+	stateManager *state.Manager
+	engine       *effects.Engine
+
+	pollInterval time.Duration
+	timeout      time.Duration
+
+	mu       sync.Mutex
+	updating bool // an update started by this tool has not finished
+}
+
+// NewTriggerFirmwareUpdateTool creates a new triggerFirmwareUpdate tool instance
+func NewTriggerFirmwareUpdateTool(client *device.Client, broadcaster *events.Broadcaster, stateManager *state.Manager, engine *effects.Engine) *TriggerFirmwareUpdateTool {
+	return &TriggerFirmwareUpdateTool{
+		client:       client,
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		engine:       engine,
+		pollInterval: firmwarePollInterval,
+		timeout:      firmwareUpdateTimeout,
+	}
+}
+
+// Definition returns the MCP tool definition for triggerFirmwareUpdate
+func (t *TriggerFirmwareUpdateTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "triggerFirmwareUpdate",
+		Description: "Install a firmware update over the air. The UFO goes dark and restarts while it installs, so this is refused while effects are on the effect stack; stop them with stopAllEffects first. Returns as soon as the UFO starts; firmware_update events report the progress until the update is done or failed. Use getFirmwareVersion to see whether an update is available.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"url": map[string]interface{}{
+					"type":        "string",
+					"description": "Firmware image to install instead of the update server's latest (optional, http or https URL)",
+				},
+			},
+		},
+	}
+}
+
+// Execute runs the triggerFirmwareUpdate tool
+func (t *TriggerFirmwareUpdateTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	imageURL := ""
+	if value, exists := arguments["url"]; exists {
+		text, ok := value.(string)
+		if !ok {
+			return invalidArgument("url", "'url' must be a string"), nil
+		}
+		if text != "" {
+			parsed, err := url.Parse(text)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return invalidArgument("url", fmt.Sprintf("'url' must be an http or https URL, got %q", text)), nil
+			}
+		}
+		imageURL = text
+	}
+
+	if depth := t.stateManager.GetEffectStackDepth(); depth > 0 {
+		return toolError(CodeConflict, fmt.Sprintf("%d effect(s) are active; stop them with stopAllEffects before updating the firmware", depth)), nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.updating {
+		return toolError(CodeConflict, "a firmware update is already running"), nil
+	}
+
+	status, err := t.client.FetchFirmware(ctx)
+	if err != nil {
+		return toolError(CodeDeviceError, fmt.Sprintf("failed to read firmware status: %v", err)), nil
+	}
+	if !status.Supported {
+		return toolError(CodeUnavailable, fmt.Sprintf("the firmware on this UFO (%s) does not support over-the-air updates", orUnknown(status.Current))), nil
+	}
+	if status.Updating() {
+		return toolError(CodeConflict, fmt.Sprintf("the UFO is already updating (%s)", status.State)), nil
+	}
+	if !status.UpdateAvailable && imageURL == "" {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf("Firmware %s is up to date; nothing to install", orUnknown(status.Current)),
+				},
+			},
+			IsError: false,
+		}, nil
+	}
+
+	target := status.Available
+	if imageURL != "" {
+		target = imageURL
+	}
+	if err := t.client.StartFirmwareUpdate(ctx, imageURL); err != nil {
+		return toolError(CodeDeviceError, fmt.Sprintf("the UFO did not start the update: %v", err)), nil
+	}
+
+	t.updating = true
+	from := status.Current
+	t.publish(ctx, events.FirmwareUpdate{From: from, To: target, State: "started"})
+	if !t.engine.Go(ctx, func(ctx context.Context) { t.monitor(ctx, from, target) }) {
+		t.updating = false
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: fmt.Sprintf("🛠️ Firmware update started: %s → %s\nThe UFO will restart when it is installed; firmware_update events report the progress.", orUnknown(from), orUnknown(target)),
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// monitor follows a running update, publishing each change of its state or
+// progress, until it is done, fails or times out. The UFO does not answer
+// while it restarts, which is reported as rebooting.
+func (t *TriggerFirmwareUpdateTool) monitor(ctx context.Context, from, target string) {
+	defer func() {
+		t.mu.Lock()
+		t.updating = false
+		t.mu.Unlock()
+	}()
+
+	deadline := time.Now().Add(t.timeout)
+	var last string
+	for effects.Sleep(ctx, t.pollInterval) {
+		update := events.FirmwareUpdate{From: from, To: target}
+		status, err := t.client.FetchFirmware(ctx)
+		switch {
+		case err != nil:
+			update.State = device.FirmwareRebooting
+		case status.State == device.FirmwareIdle && status.Current != from:
+			// Back up on the new version without reporting "done"
+			update.State = device.FirmwareDone
+		case status.State == device.FirmwareIdle:
+			update.State = "pending"
+		default:
+			update.State = status.State
+			update.Progress = status.Progress
+			update.Message = status.Message
+		}
+		if status != nil && update.State == device.FirmwareDone {
+			update.Version = status.Current
+		}
+
+		final := update.State == device.FirmwareDone || update.State == device.FirmwareFailed
+		if !final && time.Now().After(deadline) {
+			update.State = device.FirmwareFailed
+			update.Message = fmt.Sprintf("no result after %s", t.timeout)
+			final = true
+		}
+		key := update.State
+		if update.Progress != nil {
+			key += fmt.Sprint(" ", *update.Progress)
+		}
+		if key != last || final {
+			last = key
+			t.publish(ctx, update)
+		}
+		if final {
+			slog.InfoContext(ctx, "Firmware update finished", "state", update.State, "version", update.Version)
+			return
+		}
+	}
+}
+
+// publish sends a firmware_update event
+func (t *TriggerFirmwareUpdateTool) publish(ctx context.Context, update events.FirmwareUpdate) {
+	t.broadcaster.PublishPayload(ctx, update)
+}
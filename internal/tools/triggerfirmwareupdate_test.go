@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+	"github.com/starspace46/ufo-mcp-go/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerFirmwareUpdateTool_Execute(t *testing.T) {
+	simulator := device.NewSimulator()
+	client := device.NewSimulatedClient(simulator)
+	broadcaster := events.NewBroadcaster()
+	defer broadcaster.Close()
+	stateManager := state.NewManager(broadcaster)
+	engine := effects.NewEngine(client)
+	defer engine.Shutdown(context.Background())
+	tool := NewTriggerFirmwareUpdateTool(client, broadcaster, stateManager, engine)
+	tool.pollInterval = 10 * time.Millisecond
+	version := NewGetFirmwareVersionTool(client)
+	subscriber := broadcaster.Subscribe("test")
+	text := func(result *mcp.CallToolResult) string { return result.Content[0].(mcp.TextContent).Text }
+
+	// Nothing to install yet
+	result, err := tool.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Contains(t, text(result), "up to date")
+
+	simulator.OfferFirmware("2.0")
+	result, err = version.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Update available: 2.0")
+
+	// Refused while an effect is on the stack
+	stateManager.PushEffect("rainbow", "effect=rainbow", nil)
+	result, err = tool.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, text(result), "stopAllEffects")
+	stateManager.PopEffect()
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	require.False(t, result.IsError, text(result))
+	assert.Contains(t, text(result), "simulated → 2.0")
+
+	var states []interface{}
+	deadline := time.After(2 * time.Second)
+	for len(states) == 0 || states[len(states)-1] != device.FirmwareDone {
+		select {
+		case event := <-subscriber.Channel:
+			if event.Type == events.EventFirmwareUpdate {
+				states = append(states, event.Data["state"])
+			}
+		case <-deadline:
+			t.Fatalf("update did not finish, states %v", states)
+		}
+	}
+	// Each change of progress is published, flashing at 50% and 75%
+	assert.Equal(t, []interface{}{"started", device.FirmwareDownloading, device.FirmwareFlashing, device.FirmwareFlashing, device.FirmwareDone}, states)
+
+	result, err = version.Execute(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Firmware: 2.0")
+	assert.Contains(t, text(result), "Up to date")
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{"url": "ftp://example.com/ufo.bin"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
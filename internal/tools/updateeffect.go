@@ -24,7 +24,7 @@ func NewUpdateEffectTool(store *effects.Store) *UpdateEffectTool {
 func (t *UpdateEffectTool) Definition() mcp.Tool {
 	return mcp.Tool{
 		Name:        "updateEffect",
-		Description: "Update an existing custom lighting effect. You can update the description, pattern, and/or duration. The effect name cannot be changed.",
+		Description: "Update an existing custom lighting effect. You can update the description, pattern, duration, template parameters, category and/or tags. The effect name cannot be changed.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -44,6 +44,28 @@ func (t *UpdateEffectTool) Definition() mcp.Tool {
 					"type":        "number",
 					"description": "New duration in milliseconds 0-3600000 (optional, leave unset to keep current)",
 				},
+				"category": map[string]interface{}{
+					"type":        "string",
+					"description": "New category; an empty string removes it (optional, leave unset to keep current)",
+				},
+				"tags": map[string]interface{}{
+					"type":        "array",
+					"description": "New tags, replacing the current ones; an empty array removes them (optional, leave unset to keep current)",
+					"items":       map[string]interface{}{"type": "string"},
+				},
+				"params": map[string]interface{}{
+					"type":        "array",
+					"description": "New parameters for {name} placeholders in the pattern, replacing the current ones; an empty array removes them (optional, leave unset to keep current)",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"name":        map[string]interface{}{"type": "string", "description": "Placeholder name, used as {name} in the pattern"},
+							"description": map[string]interface{}{"type": "string", "description": "What the value controls, e.g. 'hex color'"},
+							"default":     map[string]interface{}{"type": "string", "description": "Value used when playEffect gives none (optional; the parameter is required without one)"},
+						},
+						"required": []string{"name"},
+					},
+				},
 			},
 			Required: []string{"name"},
 		},
@@ -55,29 +77,13 @@ func (t *UpdateEffectTool) Execute(ctx context.Context, arguments map[string]int
 	// Extract and validate name
 	name, ok := arguments["name"].(string)
 	if !ok || name == "" {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: 'name' parameter is required and must be a non-empty string",
-				},
-			},
-			IsError: true,
-		}, nil
+		return missingArgument("name", "'name' parameter is required and must be a non-empty string"), nil
 	}
 
 	// Check if effect exists
 	existingEffect, exists := t.store.Get(name)
 	if !exists {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error: Effect '%s' not found. Use addEffect to create it first.", name),
-				},
-			},
-			IsError: true,
-		}, nil
+		return ToolError{Code: CodeNotFound, Message: fmt.Sprintf("Effect '%s' not found", name), Parameter: "name", Hint: "Use addEffect to create it first."}.Result(), nil
 	}
 
 	// Create updated effect starting with existing values
@@ -86,6 +92,12 @@ func (t *UpdateEffectTool) Execute(ctx context.Context, arguments map[string]int
 		Description: existingEffect.Description,
 		Pattern:     existingEffect.Pattern,
 		Duration:    existingEffect.Duration,
+		Perpetual:   existingEffect.Perpetual,
+		Steps:       existingEffect.Steps,
+		Params:      existingEffect.Params,
+		Category:    existingEffect.Category,
+		Tags:        existingEffect.Tags,
+		Composite:   existingEffect.Composite,
 	}
 
 	// Track what was updated
@@ -95,33 +107,22 @@ func (t *UpdateEffectTool) Execute(ctx context.Context, arguments map[string]int
 	if descVal, hasDesc := arguments["description"]; hasDesc {
 		description, ok := descVal.(string)
 		if !ok || description == "" {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Error: 'description' must be a non-empty string when provided",
-					},
-				},
-				IsError: true,
-			}, nil
+			return invalidArgument("description", "'description' must be a non-empty string when provided"), nil
 		}
 		updatedEffect.Description = description
 		updates = append(updates, "description")
 	}
 
+	// A composite's pattern is computed from its layers
+	if _, hasPattern := arguments["pattern"]; hasPattern && existingEffect.Composite != nil {
+		return toolError(CodeConflict, fmt.Sprintf("Effect '%s' is a composite; change its layers with composeEffect", name)), nil
+	}
+
 	// Update pattern if provided
 	if patternVal, hasPattern := arguments["pattern"]; hasPattern {
 		pattern, ok := patternVal.(string)
 		if !ok || pattern == "" {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Error: 'pattern' must be a non-empty string when provided",
-					},
-				},
-				IsError: true,
-			}, nil
+			return invalidArgument("pattern", "'pattern' must be a non-empty string when provided"), nil
 		}
 		updatedEffect.Pattern = pattern
 		updates = append(updates, "pattern")
@@ -136,58 +137,58 @@ func (t *UpdateEffectTool) Execute(ctx context.Context, arguments map[string]int
 		case int:
 			duration = v
 		default:
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Error: 'duration' must be a number",
-					},
-				},
-				IsError: true,
-			}, nil
+			return invalidArgument("duration", "'duration' must be a number"), nil
 		}
 
 		// Validate duration range
 		if duration < 0 || duration > 3600000 {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Type: "text",
-						Text: "Error: 'duration' must be between 0 and 3600000 milliseconds (1 hour)",
-					},
-				},
-				IsError: true,
-			}, nil
+			return invalidArgument("duration", "'duration' must be between 0 and 3600000 milliseconds (1 hour)"), nil
 		}
 
 		updatedEffect.Duration = duration
 		updates = append(updates, "duration")
 	}
 
+	// Update template parameters if provided
+	if paramsVal, hasParams := arguments["params"]; hasParams {
+		params, err := parseEffectParams(paramsVal)
+		if err != nil {
+			return toolError(CodeInvalidArgument, err.Error()), nil
+		}
+		updatedEffect.Params = params
+		updates = append(updates, "params")
+	}
+
+	// Update the category and tags if provided
+	category, tags, err := parseEffectLabels(arguments)
+	if err != nil {
+		return toolError(CodeInvalidArgument, err.Error()), nil
+	}
+	if _, hasCategory := arguments["category"]; hasCategory {
+		updatedEffect.Category = category
+		updates = append(updates, "category")
+	}
+	if _, hasTags := arguments["tags"]; hasTags {
+		updatedEffect.Tags = tags
+		updates = append(updates, "tags")
+	}
+
 	// Check if any updates were provided
 	if len(updates) == 0 {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: "Error: No updates provided. Specify at least one of: description, pattern, or duration",
-				},
-			},
-			IsError: true,
-		}, nil
+		return toolError(CodeInvalidArgument, "No updates provided. Specify at least one of: description, pattern, duration, params, category, or tags"), nil
+	}
+
+	// Check a changed pattern against the UFO query grammar; effects saved
+	// before patterns were checked can still have other fields updated
+	if _, hasPattern := arguments["pattern"]; hasPattern || arguments["params"] != nil {
+		if err := effects.ValidatePatterns(updatedEffect); err != nil {
+			return invalidArgument("pattern", fmt.Sprintf("Invalid pattern: %v", err)), nil
+		}
 	}
 
 	// Update the effect in the store (Update saves automatically)
 	if err := t.store.Update(updatedEffect); err != nil {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf("Error: Failed to update effect: %v", err),
-				},
-			},
-			IsError: true,
-		}, nil
+		return toolError(CodeInvalidArgument, fmt.Sprintf("Failed to update effect: %v", err)), nil
 	}
 
 	// Build success message
@@ -197,10 +198,14 @@ func (t *UpdateEffectTool) Execute(ctx context.Context, arguments map[string]int
 	message += fmt.Sprintf("• Name: %s\n", updatedEffect.Name)
 	message += fmt.Sprintf("• Description: %s\n", updatedEffect.Description)
 	message += fmt.Sprintf("• Pattern: %s\n", updatedEffect.Pattern)
-	message += fmt.Sprintf("• Duration: %d seconds", updatedEffect.Duration)
+	message += fmt.Sprintf("• Duration: %dms", updatedEffect.Duration)
 	if updatedEffect.Duration == 0 {
 		message += " (infinite)"
 	}
+	if len(updatedEffect.Params) > 0 {
+		message += fmt.Sprintf("\n• Parameters: %s", formatEffectParams(updatedEffect.Params))
+	}
+	message += formatEffectLabels(updatedEffect)
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
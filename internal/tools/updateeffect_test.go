@@ -92,7 +92,7 @@ func TestUpdateEffectTool_Execute_Success(t *testing.T) {
 			arguments: map[string]interface{}{
 				"name":        "testEffect",
 				"description": "Fully updated effect",
-				"pattern":     "top=0|5|FF0000|10|5|00FF00&logo=1",
+				"pattern":     "top=0|5|FF0000|10|5|00FF00&logo=on",
 				"duration":    300,
 			},
 			expectedFields: []string{"description", "pattern", "duration"},
@@ -257,10 +257,10 @@ func TestUpdateEffectTool_Execute_Errors(t *testing.T) {
 			name:        "duration out of range",
 			arguments:   map[string]interface{}{
 				"name":     "existingEffect",
-				"duration": 4000,
+				"duration": 4000000,
 			},
 			expectError: true,
-			expectText:  "must be between 0 and 3600 seconds",
+			expectText:  "must be between 0 and 3600000 milliseconds",
 		},
 		{
 			name:        "no updates provided",
@@ -350,4 +350,41 @@ func TestUpdateEffectTool_PartialUpdates(t *testing.T) {
 	if updatedEffect.Duration != originalEffect.Duration {
 		t.Error("Duration should not have changed")
 	}
-}
\ No newline at end of file
+}
+
+func TestUpdateEffectTool_Labels(t *testing.T) {
+	store := effects.NewStore(t.TempDir() + "/effects.json")
+	add := NewAddEffectTool(store)
+	result, _ := add.Execute(context.Background(), map[string]interface{}{
+		"name":        "deployGlow",
+		"description": "Shown while deploying",
+		"pattern":     "top=0|15|00FFFF",
+		"category":    "status",
+		"tags":        []interface{}{"Cyan", "deploy"},
+	})
+	if result.IsError {
+		t.Fatalf("addEffect failed: %v", result.Content)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "• Tags: cyan, deploy") {
+		t.Errorf("expected the normalized tags in the reply, got:\n%s", text)
+	}
+
+	tool := NewUpdateEffectTool(store)
+	result, _ = tool.Execute(context.Background(), map[string]interface{}{
+		"name":     "deployGlow",
+		"category": "",
+		"tags":     []interface{}{"ci"},
+	})
+	if result.IsError {
+		t.Fatalf("updateEffect failed: %v", result.Content)
+	}
+	effect, _ := store.Get("deployGlow")
+	if effect.Category != "" || len(effect.Tags) != 1 || effect.Tags[0] != "ci" {
+		t.Errorf("expected the category removed and tags replaced, got %q %v", effect.Category, effect.Tags)
+	}
+
+	result, _ = tool.Execute(context.Background(), map[string]interface{}{"name": "deployGlow", "tags": []interface{}{""}})
+	if !result.IsError {
+		t.Error("expected an error for an empty tag")
+	}
+}
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+)
+
+// ValidatePatternTool implements the validatePattern MCP tool, which checks
+// a pattern the way addEffect does without saving or sending it
+type ValidatePatternTool struct{}
+
+// NewValidatePatternTool creates a new validatePattern tool instance
+func NewValidatePatternTool() *ValidatePatternTool {
+	return &ValidatePatternTool{}
+}
+
+// Definition returns the MCP tool definition for validatePattern
+func (t *ValidatePatternTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "validatePattern",
+		Description: fmt.Sprintf("Check a UFO API pattern before saving it with addEffect or sending it with sendRawApi, without changing anything. Checks every parameter (top, bottom, top_init, top_bg, top_whirl, top_morph, the bottom_ ones, dim and logo) and its value: segments are start|count|color with start 0-%d and count 1-%d, colors six digit hex, whirl 0-%d ms optionally |ccw, morph ticks|speed with speed 1-10, dim 0-255, logo on, off or 1-4 colors. {name} placeholders are allowed; their values are checked when the effect is played. Returns the problem, or what the pattern sets as JSON.", device.RingLEDs-1, device.RingLEDs, device.MaxWhirlMs),
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"pattern": map[string]interface{}{
+					"type":        "string",
+					"description": "UFO API pattern string (e.g. 'top_init=1&top=0|5|FF0000&top_whirl=300')",
+				},
+			},
+			Required: []string{"pattern"},
+		},
+	}
+}
+
+// Execute runs the validatePattern tool
+func (t *ValidatePatternTool) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	pattern, ok := arguments["pattern"].(string)
+	if !ok || strings.TrimSpace(pattern) == "" {
+		return missingArgument("pattern", "'pattern' parameter is required and must be a non-empty string"), nil
+	}
+	pattern = strings.TrimSpace(pattern)
+
+	// Declare every placeholder so a template is checked like addEffect
+	// checks an effect whose parameters have no defaults
+	effect := &effects.Effect{Pattern: pattern}
+	for _, name := range effect.Placeholders() {
+		effect.Params = append(effect.Params, effects.Param{Name: name})
+	}
+	if err := effects.ValidatePatterns(effect); err != nil {
+		return invalidArgument("pattern", fmt.Sprintf("Invalid %v", err)), nil
+	}
+
+	message := "✅ Valid pattern\n"
+	if placeholders := effect.Placeholders(); len(placeholders) > 0 {
+		message += fmt.Sprintf("Placeholders: %s; their values are checked when the effect is played\n", strings.Join(placeholders, ", "))
+		return patternResult(message), nil
+	}
+
+	parsed, err := device.ParsePattern(pattern)
+	if err != nil {
+		return invalidArgument("pattern", fmt.Sprintf("Invalid pattern: %v", err)), nil
+	}
+	for _, ring := range []struct {
+		name string
+		ring *device.RingPattern
+	}{
+		{"Top", parsed.Top},
+		{"Bottom", parsed.Bottom},
+	} {
+		if ring.ring != nil {
+			message += fmt.Sprintf("%s ring: %s\n", ring.name, describeRingPattern(ring.ring))
+		}
+	}
+	if parsed.Dim != nil {
+		message += fmt.Sprintf("Brightness: %d\n", *parsed.Dim)
+	}
+	if parsed.Logo != "" {
+		message += fmt.Sprintf("Logo: %s\n", parsed.Logo)
+	}
+
+	parsedJSON, err := json.MarshalIndent(parsed, "", "  ")
+	if err != nil {
+		return toolError(CodeInternal, "Failed to serialize pattern: "+err.Error()), nil
+	}
+	message += "\nFull JSON:\n" + string(parsedJSON)
+	return patternResult(message), nil
+}
+
+// describeRingPattern summarizes what a pattern sets on one ring
+func describeRingPattern(ring *device.RingPattern) string {
+	var parts []string
+	if ring.Init {
+		parts = append(parts, "cleared")
+	}
+	if ring.Background != "" {
+		parts = append(parts, "background #"+ring.Background)
+	}
+	if len(ring.Segments) > 0 {
+		parts = append(parts, fmt.Sprintf("%d segment(s)", len(ring.Segments)))
+	}
+	if ring.Whirl != nil {
+		direction := "clockwise"
+		if ring.Whirl.CounterClockwise {
+			direction = "counter-clockwise"
+		}
+		parts = append(parts, fmt.Sprintf("whirl %d ms %s", ring.Whirl.Ms, direction))
+	}
+	if ring.Morph != nil {
+		parts = append(parts, fmt.Sprintf("morph %d ms on, %d ms fade", ring.Morph.BrightnessMs, ring.Morph.FadeMs))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// patternResult builds a successful validatePattern result
+func patternResult(message string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: false,
+	}
+}
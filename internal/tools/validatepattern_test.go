@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePatternTool_Execute(t *testing.T) {
+	tool := NewValidatePatternTool()
+	assert.Equal(t, "validatePattern", tool.Definition().Name)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"pattern": "top_init=1&top=0|5|FF0000&top_whirl=300|ccw&dim=80",
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "Valid pattern")
+	assert.Contains(t, text, "Top ring: cleared, 1 segment(s), whirl 300 ms counter-clockwise")
+	assert.Contains(t, text, "Brightness: 80")
+	assert.Contains(t, text, `"color": "ff0000"`)
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"pattern": "top_init=1&top=0|15|{color}",
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Placeholders: color")
+
+	for pattern, want := range map[string]string{
+		"top=0|15|FF0000&top_whirl=999": "Error: Invalid pattern: top_whirl=999: whirl must be a whole number of milliseconds from 0 to 510",
+		"effect=rainbow":                "Error: Invalid pattern: effect: not a UFO parameter",
+		"":                              "'pattern' parameter is required",
+	} {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{"pattern": pattern})
+		require.NoError(t, err)
+		assert.True(t, result.IsError, pattern)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, want, pattern)
+	}
+}
@@ -1,9 +1,71 @@
 package version
 
-// Build information - these are set via ldflags during build
+import (
+	"fmt"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+)
+
+// Build information - these are set via ldflags during build, e.g.
+//
+//	go build -ldflags "-X github.com/starspace46/ufo-mcp-go/internal/version.Version=v1.2.3"
+//
+// Values not set that way are filled from the VCS information Go embeds in
+// the binary, so `go build` and `go install` still report a commit.
 var (
-	Version   = "v1.0.0"
-	GitCommit = "dev"
-	BuildTime = "unknown"
+	Version     = "dev"
+	GitCommit   = "unknown"
+	BuildTime   = "unknown"
 	SpecVersion = "2025-03-26"
-)
\ No newline at end of file
+)
+
+// releaseVersion matches tagged module versions such as v1.2.3 or v1.2.3-rc.1
+var releaseVersion = regexp.MustCompile(`^v\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`)
+
+// pseudoVersion matches the versions Go derives for untagged commits
+var pseudoVersion = regexp.MustCompile(`\d{14}-[0-9a-f]{12}$`)
+
+func init() {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	fillFromBuildInfo(info)
+}
+
+// fillFromBuildInfo sets the build information not provided via ldflags
+func fillFromBuildInfo(info *debug.BuildInfo) {
+	if Version == "dev" && releaseVersion.MatchString(info.Main.Version) && !pseudoVersion.MatchString(info.Main.Version) {
+		Version = info.Main.Version
+	}
+
+	var filledCommit, modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if GitCommit == "unknown" && setting.Value != "" {
+				GitCommit = setting.Value
+				if len(GitCommit) > 7 {
+					GitCommit = GitCommit[:7]
+				}
+				filledCommit = true
+			}
+		case "vcs.time":
+			if BuildTime == "unknown" && setting.Value != "" {
+				BuildTime = setting.Value
+			}
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if modified && filledCommit {
+		GitCommit += "-dirty"
+	}
+}
+
+// String returns the build information printed by --version
+func String() string {
+	return fmt.Sprintf("ufo-mcp %s\n  commit:       %s\n  built:        %s\n  MCP spec:     %s\n  go:           %s\n  platform:     %s/%s",
+		Version, GitCommit, BuildTime, SpecVersion, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
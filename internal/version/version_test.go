@@ -0,0 +1,44 @@
+package version
+
+import (
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestFillFromBuildInfo(t *testing.T) {
+	defer func(v, c, b string) { Version, GitCommit, BuildTime = v, c, b }(Version, GitCommit, BuildTime)
+
+	info := &debug.BuildInfo{
+		Main: debug.Module{Version: "v1.4.0"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "0123456789abcdef"},
+			{Key: "vcs.time", Value: "2025-06-01T10:00:00Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+
+	Version, GitCommit, BuildTime = "dev", "unknown", "unknown"
+	fillFromBuildInfo(info)
+	if Version != "v1.4.0" || GitCommit != "0123456-dirty" || BuildTime != "2025-06-01T10:00:00Z" {
+		t.Errorf("unexpected build info: %s %s %s", Version, GitCommit, BuildTime)
+	}
+
+	// Values stamped via ldflags win
+	Version, GitCommit, BuildTime = "v2.0.0", "feedbee", "2025-07-01T00:00:00Z"
+	fillFromBuildInfo(info)
+	if Version != "v2.0.0" || GitCommit != "feedbee" || BuildTime != "2025-07-01T00:00:00Z" {
+		t.Errorf("expected ldflags values kept, got %s %s %s", Version, GitCommit, BuildTime)
+	}
+
+	// Pseudo-versions of untagged commits are not reported as releases
+	Version = "dev"
+	fillFromBuildInfo(&debug.BuildInfo{Main: debug.Module{Version: "v0.0.0-20250601100000-0123456789ab"}})
+	if Version != "dev" {
+		t.Errorf("expected pseudo-version ignored, got %s", Version)
+	}
+
+	if !strings.Contains(String(), "MCP spec:") {
+		t.Errorf("unexpected version string: %s", String())
+	}
+}
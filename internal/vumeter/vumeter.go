@@ -0,0 +1,293 @@
+// Package vumeter shows audio levels posted by an external process, such as
+// a script reading a microphone, as a VU meter on the UFO's rings.
+package vumeter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/device"
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+	"github.com/starspace46/ufo-mcp-go/internal/events"
+)
+
+const (
+	// DefaultFrameInterval is the time between frames; at the default
+	// write rate of 10 per second every frame reaches the UFO
+	DefaultFrameInterval = 100 * time.Millisecond
+	// DefaultIdleTimeout is how long after the last sample the meter stops
+	// and the UFO shows what it showed before
+	DefaultIdleTimeout = 2 * time.Second
+	// DefaultMaxSamplesPerSecond is how many samples are accepted per
+	// second; more are answered with 429 Too Many Requests
+	DefaultMaxSamplesPerSecond = 50
+
+	// maxSampleBytes limits the size of a posted sample
+	maxSampleBytes = 4096
+	// effectName is the name the meter runs under in the effect engine
+	effectName = "vuMeter"
+)
+
+// Meter colors: the first 60% of a ring is green, up to 85% yellow and the
+// rest red, like the scale of an analog meter
+const (
+	colorLow  = "00FF00"
+	colorMid  = "FFFF00"
+	colorHigh = "FF0000"
+	colorOff  = "000000"
+)
+
+// errRateLimited is returned by Submit for samples over the rate limit
+var errRateLimited = fmt.Errorf("more than the allowed samples per second")
+
+// Config tunes the meter. Zero values use the defaults.
+type Config struct {
+	FrameInterval       time.Duration
+	IdleTimeout         time.Duration
+	MaxSamplesPerSecond int
+}
+
+// Sample is one posted audio level. Level drives both rings; Left and Right
+// drive the top and bottom ring for stereo sources.
+type Sample struct {
+	Level *float64 `json:"level,omitempty"`
+	Left  *float64 `json:"left,omitempty"`
+	Right *float64 `json:"right,omitempty"`
+}
+
+// levels validates the sample and returns the top and bottom ring levels
+func (s Sample) levels() (top, bottom float64, err error) {
+	switch {
+	case s.Level != nil && (s.Left != nil || s.Right != nil):
+		return 0, 0, fmt.Errorf("give either level or left and right, not both")
+	case s.Level != nil:
+		top, bottom = *s.Level, *s.Level
+	case s.Left != nil && s.Right != nil:
+		top, bottom = *s.Left, *s.Right
+	default:
+		return 0, 0, fmt.Errorf("a sample needs level, or left and right")
+	}
+	for _, level := range []float64{top, bottom} {
+		if math.IsNaN(level) || level < 0 || level > 100 {
+			return 0, 0, fmt.Errorf("levels must be from 0 to 100")
+		}
+	}
+	return top, bottom, nil
+}
+
+// Meter receives level samples on /vumeter and draws the latest of them on
+// the rings once per frame interval, so a fast sender does not flood the
+// UFO. The shadow state is left alone while the meter shows; when samples
+// stop arriving for the idle timeout, restore shows it again.
+type Meter struct {
+	engine      *effects.Engine
+	broadcaster *events.Broadcaster
+	restore     func(ctx context.Context) error
+	held        func() bool
+	config      Config
+
+	mu          sync.Mutex
+	top, bottom float64
+	pending     bool      // a sample arrived since the last frame
+	active      bool      // the meter is showing
+	lastSample  time.Time // when the latest sample arrived
+	window      time.Time // start of the second samples are counted in
+	count       int       // samples accepted in the current second
+}
+
+// New creates a meter. restore shows the state from before the meter
+// started. held reports whether something more important, such as an
+// alert, holds the UFO; samples are refused while it does. held may be nil.
+func New(config Config, engine *effects.Engine, broadcaster *events.Broadcaster, restore func(ctx context.Context) error, held func() bool) *Meter {
+	if config.FrameInterval <= 0 {
+		config.FrameInterval = DefaultFrameInterval
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = DefaultIdleTimeout
+	}
+	if config.MaxSamplesPerSecond <= 0 {
+		config.MaxSamplesPerSecond = DefaultMaxSamplesPerSecond
+	}
+	return &Meter{
+		engine:      engine,
+		broadcaster: broadcaster,
+		restore:     restore,
+		held:        held,
+		config:      config,
+	}
+}
+
+// Start draws frames in the background until ctx is done
+func (m *Meter) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(m.config.FrameInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				m.tick(ctx, now)
+			}
+		}
+	}()
+}
+
+// Active reports whether the meter is showing
+func (m *Meter) Active() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.active
+}
+
+// Submit records a sample, to be drawn with the next frame
+func (m *Meter) Submit(sample Sample, now time.Time) error {
+	top, bottom, err := sample.levels()
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if now.Sub(m.window) >= time.Second {
+		m.window, m.count = now, 0
+	}
+	if m.count >= m.config.MaxSamplesPerSecond {
+		return errRateLimited
+	}
+	m.count++
+	m.top, m.bottom = top, bottom
+	m.pending = true
+	m.lastSample = now
+	return nil
+}
+
+// tick draws the latest sample, or stops the meter when samples stopped or
+// an alert took over
+func (m *Meter) tick(ctx context.Context, now time.Time) {
+	held := m.held != nil && m.held()
+
+	m.mu.Lock()
+	if m.active && (held || now.Sub(m.lastSample) >= m.config.IdleTimeout) {
+		m.active, m.pending = false, false
+		m.mu.Unlock()
+		m.stop(ctx)
+		return
+	}
+	if !m.pending {
+		m.mu.Unlock()
+		return
+	}
+	starting := !m.active
+	top, bottom := m.top, m.bottom
+	m.active, m.pending = true, false
+	m.mu.Unlock()
+
+	query, err := Frame(top, bottom).Query(device.Frame{})
+	if err != nil {
+		slog.WarnContext(ctx, "VU meter frame failed", "error", err)
+		return
+	}
+	if starting {
+		// Stop the animation of the effect underneath so it does not draw
+		// over the meter; restore starts it again
+		err = m.engine.Apply(ctx, effectName, query, nil)
+		if err == nil {
+			m.publish(ctx, "started")
+		}
+	} else {
+		err = m.engine.Overlay(ctx, query)
+	}
+	if err != nil && ctx.Err() == nil {
+		slog.WarnContext(ctx, "VU meter frame failed", "error", err)
+	}
+}
+
+// stop shows the state from before the meter started
+func (m *Meter) stop(ctx context.Context) {
+	if err := m.restore(ctx); err != nil {
+		slog.WarnContext(ctx, "Failed to restore the UFO after the VU meter", "error", err)
+	}
+	m.publish(ctx, "stopped")
+}
+
+// publish sends a vu_meter event
+func (m *Meter) publish(ctx context.Context, status string) {
+	if m.broadcaster == nil {
+		return
+	}
+	m.broadcaster.PublishPayload(ctx, events.VUMeter{Status: status})
+}
+
+// Frame returns the rings showing the given levels, 0-100: each ring lights
+// its share of LEDs from LED 0, green then yellow then red
+func Frame(top, bottom float64) device.Frame {
+	var frame device.Frame
+	for _, ring := range []struct {
+		leds  *[device.RingLEDs]string
+		level float64
+	}{
+		{&frame.Top, top},
+		{&frame.Bottom, bottom},
+	} {
+		lit := int(math.Round(ring.level / 100 * device.RingLEDs))
+		for i := range ring.leds {
+			switch {
+			case i >= lit:
+				ring.leds[i] = colorOff
+			case i < device.RingLEDs*60/100:
+				ring.leds[i] = colorLow
+			case i < device.RingLEDs*85/100:
+				ring.leds[i] = colorMid
+			default:
+				ring.leds[i] = colorHigh
+			}
+		}
+	}
+	return frame
+}
+
+// ServeHTTP accepts a sample posted as JSON, such as {"level": 42},
+// {"left": 40, "right": 55} or a bare number
+func (m *Meter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSampleBytes))
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+	var sample Sample
+	var level float64
+	if err := json.Unmarshal(body, &level); err == nil {
+		sample.Level = &level
+	} else if err := json.Unmarshal(body, &sample); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid sample: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if m.held != nil && m.held() {
+		http.Error(w, "The UFO is held by an alert", http.StatusConflict)
+		return
+	}
+	if err := m.Submit(sample, time.Now()); err != nil {
+		if err == errRateLimited {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Too many samples", http.StatusTooManyRequests)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,178 @@
+package vumeter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/starspace46/ufo-mcp-go/internal/effects"
+)
+
+// recordingSender records the queries it is asked to send
+type recordingSender struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+func (s *recordingSender) SendRawQuery(ctx context.Context, query string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries = append(s.queries, query)
+	return "", nil
+}
+
+func (s *recordingSender) sent() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.queries...)
+}
+
+func post(meter *Meter, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	meter.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/vumeter", strings.NewReader(body)))
+	return recorder
+}
+
+func TestFrame(t *testing.T) {
+	frame := Frame(100, 0)
+	for i, led := range frame.Top {
+		want := colorLow
+		switch {
+		case i >= 12:
+			want = colorHigh
+		case i >= 9:
+			want = colorMid
+		}
+		if led != want {
+			t.Errorf("top LED %d = %s, want %s", i, led, want)
+		}
+	}
+	for i, led := range frame.Bottom {
+		if led != colorOff {
+			t.Errorf("bottom LED %d = %s, want off", i, led)
+		}
+	}
+
+	frame = Frame(50, 20)
+	if frame.Top[7] != colorLow || frame.Top[8] != colorOff {
+		t.Errorf("50%% should light 8 LEDs, got %v", frame.Top)
+	}
+	if frame.Bottom[2] != colorLow || frame.Bottom[3] != colorOff {
+		t.Errorf("20%% should light 3 LEDs, got %v", frame.Bottom)
+	}
+}
+
+func TestMeter_ServeHTTP(t *testing.T) {
+	meter := New(Config{}, effects.NewEngine(&recordingSender{}), nil, func(context.Context) error { return nil }, nil)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"level", `{"level": 42}`, http.StatusNoContent},
+		{"stereo", `{"left": 40, "right": 55.5}`, http.StatusNoContent},
+		{"bare number", `73`, http.StatusNoContent},
+		{"out of range", `{"level": 101}`, http.StatusBadRequest},
+		{"one channel", `{"left": 40}`, http.StatusBadRequest},
+		{"both forms", `{"level": 10, "left": 40, "right": 40}`, http.StatusBadRequest},
+		{"not JSON", `loud`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := post(meter, tt.body).Code; got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	recorder := httptest.NewRecorder()
+	meter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/vumeter", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want %d", recorder.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestMeter_RateLimit(t *testing.T) {
+	meter := New(Config{MaxSamplesPerSecond: 3}, effects.NewEngine(&recordingSender{}), nil, func(context.Context) error { return nil }, nil)
+	level := 10.0
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := meter.Submit(Sample{Level: &level}, now); err != nil {
+			t.Fatalf("sample %d refused: %v", i, err)
+		}
+	}
+	if err := meter.Submit(Sample{Level: &level}, now); err != errRateLimited {
+		t.Errorf("fourth sample error = %v, want rate limited", err)
+	}
+	if err := meter.Submit(Sample{Level: &level}, now.Add(time.Second)); err != nil {
+		t.Errorf("sample in the next second refused: %v", err)
+	}
+}
+
+func TestMeter_DrawsLatestAndRestores(t *testing.T) {
+	sender := &recordingSender{}
+	var restored int
+	meter := New(Config{IdleTimeout: time.Second}, effects.NewEngine(sender), nil, func(context.Context) error {
+		restored++
+		return nil
+	}, nil)
+	ctx := context.Background()
+	start := time.Now()
+
+	low, high := 10.0, 100.0
+	meter.Submit(Sample{Level: &low}, start)
+	meter.Submit(Sample{Level: &high}, start)
+	meter.tick(ctx, start.Add(100*time.Millisecond))
+	if !meter.Active() {
+		t.Fatal("meter should be active after a sample")
+	}
+	sent := sender.sent()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d frames, want 1 for the latest sample: %v", len(sent), sent)
+	}
+	if !strings.Contains(sent[0], "top_init=1") || !strings.Contains(sent[0], "bottom_init=1") || !strings.Contains(sent[0], colorHigh) {
+		t.Errorf("frame = %s, want both rings at full level", sent[0])
+	}
+
+	// No new sample: nothing is sent until the idle timeout
+	meter.tick(ctx, start.Add(500*time.Millisecond))
+	if len(sender.sent()) != 1 || restored != 0 {
+		t.Errorf("idle tick sent %d frames and restored %d times", len(sender.sent())-1, restored)
+	}
+
+	meter.tick(ctx, start.Add(time.Second))
+	if meter.Active() {
+		t.Error("meter should stop after the idle timeout")
+	}
+	if restored != 1 {
+		t.Errorf("restored %d times, want 1", restored)
+	}
+}
+
+func TestMeter_StopsForAlert(t *testing.T) {
+	var alert bool
+	var restored int
+	meter := New(Config{}, effects.NewEngine(&recordingSender{}), nil, func(context.Context) error {
+		restored++
+		return nil
+	}, func() bool { return alert })
+	ctx := context.Background()
+
+	if code := post(meter, `{"level": 50}`).Code; code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", code, http.StatusNoContent)
+	}
+	meter.tick(ctx, time.Now())
+	alert = true
+	meter.tick(ctx, time.Now())
+	if meter.Active() || restored != 1 {
+		t.Errorf("meter should stop and restore for an alert; active %v, restored %d", meter.Active(), restored)
+	}
+	if code := post(meter, `{"level": 50}`).Code; code != http.StatusConflict {
+		t.Errorf("status during an alert = %d, want %d", code, http.StatusConflict)
+	}
+}
@@ -0,0 +1,332 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/starspace46/ufo-mcp-go/internal/audit"
+	"github.com/starspace46/ufo-mcp-go/internal/integrations"
+)
+
+// maxPayloadBytes limits the size of accepted webhook payloads
+const maxPayloadBytes = 1 << 20
+
+// defaultSignatureHeader is the header GitHub sends the payload signature in
+const defaultSignatureHeader = "X-Hub-Signature-256"
+
+// secretEnv supplies the signing secret when the mapping file has none, so
+// it can be kept out of the file
+const secretEnv = "UFO_WEBHOOK_SECRET"
+
+// Config is the webhook mapping file
+type Config struct {
+	Secret          string `json:"secret,omitempty"`          // HMAC-SHA256 signing secret; unsigned requests are rejected when set
+	SignatureHeader string `json:"signatureHeader,omitempty"` // header carrying "sha256=<hex>", default X-Hub-Signature-256
+	Rules           []Rule `json:"rules"`                     // evaluated in order, first match wins
+}
+
+// Rule maps matching payloads to an effect. Header and Match values are
+// regular expressions anchored to the whole value; Match keys are JSONPath
+// expressions into the payload. Every condition must hold for the rule to
+// match. Params values starting with "$" are JSONPath expressions, so the
+// effect can show data from the payload.
+type Rule struct {
+	Name     string            `json:"name"`
+	Headers  map[string]string `json:"headers,omitempty"`  // e.g. {"X-GitHub-Event": "workflow_run"}
+	Match    map[string]string `json:"match,omitempty"`    // e.g. {"$.workflow_run.conclusion": "failure"}
+	Effect   string            `json:"effect"`             // effect played with playEffect
+	Duration int               `json:"duration,omitempty"` // overrides the effect's duration in milliseconds
+	Params   map[string]string `json:"params,omitempty"`   // values for the effect's parameters
+}
+
+// compiledRule is a rule with its matchers compiled
+type compiledRule struct {
+	Rule
+	headers map[string]*regexp.Regexp
+	match   map[string]*regexp.Regexp
+}
+
+// Player plays an effect from playEffect arguments. The playEffect tool
+// implements it.
+type Player interface {
+	Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error)
+}
+
+// Result describes how a webhook delivery was handled
+type Result struct {
+	Rule    string `json:"rule,omitempty"`    // matching rule; empty when none matched
+	Effect  string `json:"effect,omitempty"`  // effect played
+	Message string `json:"message,omitempty"` // playEffect's response
+}
+
+// Handler receives webhook deliveries on /webhook and plays the effect of
+// the first matching rule
+type Handler struct {
+	rules           []compiledRule
+	secret          string
+	signatureHeader string
+	player          Player
+	audit           *audit.Logger
+}
+
+// Load reads the webhook mapping file. The signing secret falls back to the
+// UFO_WEBHOOK_SECRET environment variable.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading webhook file: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing webhook JSON: %w", err)
+	}
+	if cfg.Secret == "" {
+		cfg.Secret = os.Getenv(secretEnv)
+	}
+	return &cfg, nil
+}
+
+// NewHandler validates the mapping and creates the webhook handler
+func NewHandler(cfg Config, player Player, auditLogger *audit.Logger) (*Handler, error) {
+	rules := make([]compiledRule, 0, len(cfg.Rules))
+	for i, rule := range cfg.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule %d: name is required", i)
+		}
+		if rule.Effect == "" {
+			return nil, fmt.Errorf("rule '%s': effect is required", rule.Name)
+		}
+		if rule.Duration < 0 {
+			return nil, fmt.Errorf("rule '%s': duration must not be negative", rule.Name)
+		}
+		headers, err := compileMatchers(rule.Headers)
+		if err != nil {
+			return nil, fmt.Errorf("rule '%s': header %w", rule.Name, err)
+		}
+		match, err := compileMatchers(rule.Match)
+		if err != nil {
+			return nil, fmt.Errorf("rule '%s': match %w", rule.Name, err)
+		}
+		for path := range rule.Match {
+			if err := integrations.ValidatePath(path); err != nil {
+				return nil, fmt.Errorf("rule '%s': %w", rule.Name, err)
+			}
+		}
+		for name, value := range rule.Params {
+			if !strings.HasPrefix(value, "$") {
+				continue
+			}
+			if err := integrations.ValidatePath(value); err != nil {
+				return nil, fmt.Errorf("rule '%s': param '%s': %w", rule.Name, name, err)
+			}
+		}
+		rules = append(rules, compiledRule{Rule: rule, headers: headers, match: match})
+	}
+
+	signatureHeader := cfg.SignatureHeader
+	if signatureHeader == "" {
+		signatureHeader = defaultSignatureHeader
+	}
+	return &Handler{
+		rules:           rules,
+		secret:          cfg.Secret,
+		signatureHeader: signatureHeader,
+		player:          player,
+		audit:           auditLogger,
+	}, nil
+}
+
+// compileMatchers compiles anchored regular expressions keyed by name
+func compileMatchers(patterns map[string]string) (map[string]*regexp.Regexp, error) {
+	compiled := make(map[string]*regexp.Regexp, len(patterns))
+	for key, pattern := range patterns {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", key, err)
+		}
+		compiled[key] = re
+	}
+	return compiled, nil
+}
+
+// Signed reports whether deliveries are authenticated by their signature,
+// in which case the endpoint does not need a bearer token
+func (h *Handler) Signed() bool {
+	return h.secret != ""
+}
+
+// ServeHTTP handles a webhook delivery. Payloads that match no rule are
+// accepted with 204 No Content so senders do not retry them.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadBytes))
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+	if h.secret != "" && !validSignature(h.secret, body, r.Header.Get(h.signatureHeader)) {
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.Handle(r.Context(), r.Header, payload)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Webhook failed", "rule", result.Rule, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if result.Rule == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// Handle plays the effect of the first rule matching the headers and
+// payload. The result has no rule when nothing matched.
+func (h *Handler) Handle(ctx context.Context, header http.Header, payload interface{}) (*Result, error) {
+	result := &Result{}
+	rule := h.match(header, payload)
+	if rule == nil {
+		return result, nil
+	}
+	result.Rule = rule.Name
+	result.Effect = rule.Effect
+
+	arguments := map[string]interface{}{"name": rule.Effect}
+	if rule.Duration > 0 {
+		arguments["duration"] = float64(rule.Duration)
+	}
+	if len(rule.Params) > 0 {
+		params := make(map[string]interface{}, len(rule.Params))
+		for name, value := range rule.Params {
+			params[name] = resolveParam(payload, value)
+		}
+		arguments["params"] = params
+	}
+
+	played, err := h.player.Execute(ctx, arguments)
+	if err == nil && played != nil {
+		result.Message = resultText(played)
+		if played.IsError {
+			err = fmt.Errorf("%s", strings.TrimPrefix(result.Message, "Error: "))
+		}
+	}
+	h.record(rule, arguments, err)
+	return result, err
+}
+
+// match returns the first rule whose conditions all hold
+func (h *Handler) match(header http.Header, payload interface{}) *compiledRule {
+	for i := range h.rules {
+		rule := &h.rules[i]
+		if rule.matches(header, payload) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// matches reports whether every header and payload condition holds
+func (r *compiledRule) matches(header http.Header, payload interface{}) bool {
+	for name, re := range r.headers {
+		if !re.MatchString(header.Get(name)) {
+			return false
+		}
+	}
+	for path, re := range r.match {
+		value, err := integrations.ExtractPath(payload, path)
+		if err != nil || !re.MatchString(stringify(value)) {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveParam evaluates a parameter that is a JSONPath expression; other
+// values are used as they are. A path missing from the payload gives an
+// empty value.
+func resolveParam(payload interface{}, value string) string {
+	if !strings.HasPrefix(value, "$") {
+		return value
+	}
+	extracted, err := integrations.ExtractPath(payload, value)
+	if err != nil {
+		return ""
+	}
+	return stringify(extracted)
+}
+
+// stringify formats a decoded JSON value for matching
+func stringify(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64, bool:
+		return fmt.Sprint(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// resultText returns the text of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// validSignature checks a "sha256=<hex>" HMAC-SHA256 signature of the body
+func validSignature(secret string, body []byte, header string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(strings.TrimSpace(header)), []byte(expected))
+}
+
+// record writes a delivery to the audit log
+func (h *Handler) record(rule *compiledRule, arguments map[string]interface{}, err error) {
+	if h.audit == nil {
+		return
+	}
+	outcome := "played"
+	if err != nil {
+		outcome = "error: " + err.Error()
+	}
+	h.audit.Record(audit.Entry{
+		Kind:   "webhook",
+		Action: rule.Name,
+		Result: outcome,
+		Data:   arguments,
+	})
+}
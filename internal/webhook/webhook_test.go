@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// fakePlayer records the playEffect arguments it receives
+type fakePlayer struct {
+	calls []map[string]interface{}
+	fail  string
+}
+
+func (p *fakePlayer) Execute(ctx context.Context, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	p.calls = append(p.calls, arguments)
+	if p.fail != "" {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Error: " + p.fail}},
+			IsError: true,
+		}, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "Playing " + arguments["name"].(string)}},
+	}, nil
+}
+
+func githubConfig(secret string) Config {
+	return Config{
+		Secret: secret,
+		Rules: []Rule{
+			{
+				Name:     "build-failed",
+				Headers:  map[string]string{"X-GitHub-Event": "workflow_run"},
+				Match:    map[string]string{"$.workflow_run.conclusion": "failure|timed_out"},
+				Effect:   "buildFailed",
+				Duration: 30000,
+				Params:   map[string]string{"branch": "$.workflow_run.head_branch", "color": "FF0000"},
+			},
+			{
+				Name:    "build-passed",
+				Headers: map[string]string{"X-GitHub-Event": "workflow_run"},
+				Match:   map[string]string{"$.workflow_run.conclusion": "success"},
+				Effect:  "buildPassed",
+			},
+		},
+	}
+}
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func post(handler http.Handler, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler_MapsPayloadToEffect(t *testing.T) {
+	player := &fakePlayer{}
+	handler, err := NewHandler(githubConfig(""), player, nil)
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+
+	failed := `{"action":"completed","workflow_run":{"conclusion":"failure","head_branch":"main"}}`
+	rec := post(handler, failed, map[string]string{"X-GitHub-Event": "workflow_run"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"rule":"build-failed"`) {
+		t.Errorf("response should name the matching rule: %s", rec.Body.String())
+	}
+	if len(player.calls) != 1 {
+		t.Fatalf("expected one playEffect call, got %d", len(player.calls))
+	}
+	call := player.calls[0]
+	if call["name"] != "buildFailed" || call["duration"] != float64(30000) {
+		t.Errorf("unexpected arguments: %v", call)
+	}
+	params := call["params"].(map[string]interface{})
+	if params["branch"] != "main" || params["color"] != "FF0000" {
+		t.Errorf("params should be resolved from the payload: %v", params)
+	}
+
+	passed := `{"workflow_run":{"conclusion":"success"}}`
+	post(handler, passed, map[string]string{"X-GitHub-Event": "workflow_run"})
+	if len(player.calls) != 2 || player.calls[1]["name"] != "buildPassed" {
+		t.Errorf("success should play buildPassed: %v", player.calls)
+	}
+	if _, exists := player.calls[1]["duration"]; exists {
+		t.Error("rules without a duration should use the effect's own")
+	}
+
+	// Other events and conclusions match no rule
+	rec = post(handler, passed, map[string]string{"X-GitHub-Event": "push"})
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("unmatched event should return 204, got %d", rec.Code)
+	}
+	rec = post(handler, `{"workflow_run":{"conclusion":"cancelled"}}`, map[string]string{"X-GitHub-Event": "workflow_run"})
+	if rec.Code != http.StatusNoContent || len(player.calls) != 2 {
+		t.Errorf("unmatched conclusion should play nothing, got %d", rec.Code)
+	}
+}
+
+func TestHandler_Signature(t *testing.T) {
+	player := &fakePlayer{}
+	handler, err := NewHandler(githubConfig("s3cret"), player, nil)
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+	if !handler.Signed() {
+		t.Error("handler with a secret should be signed")
+	}
+
+	body := `{"workflow_run":{"conclusion":"success"}}`
+	headers := map[string]string{"X-GitHub-Event": "workflow_run"}
+	if rec := post(handler, body, headers); rec.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned request should be rejected, got %d", rec.Code)
+	}
+	headers["X-Hub-Signature-256"] = sign("wrong", body)
+	if rec := post(handler, body, headers); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrongly signed request should be rejected, got %d", rec.Code)
+	}
+	if len(player.calls) != 0 {
+		t.Fatal("rejected requests must not play effects")
+	}
+
+	headers["X-Hub-Signature-256"] = sign("s3cret", body)
+	if rec := post(handler, body, headers); rec.Code != http.StatusOK {
+		t.Errorf("signed request should be accepted, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// A custom header for senders other than GitHub
+	cfg := githubConfig("s3cret")
+	cfg.SignatureHeader = "X-Signature"
+	handler, _ = NewHandler(cfg, player, nil)
+	rec := post(handler, body, map[string]string{"X-GitHub-Event": "workflow_run", "X-Signature": sign("s3cret", body)})
+	if rec.Code != http.StatusOK {
+		t.Errorf("custom signature header should be accepted, got %d", rec.Code)
+	}
+}
+
+func TestHandler_Errors(t *testing.T) {
+	player := &fakePlayer{fail: "effect 'buildPassed' not found"}
+	handler, _ := NewHandler(githubConfig(""), player, nil)
+
+	rec := post(handler, `{"workflow_run":{"conclusion":"success"}}`, map[string]string{"X-GitHub-Event": "workflow_run"})
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), "not found") {
+		t.Errorf("playEffect errors should be reported, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := post(handler, `not json`, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid JSON should return 400, got %d", rec.Code)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/webhook", nil)
+	get := httptest.NewRecorder()
+	handler.ServeHTTP(get, req)
+	if get.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET should return 405, got %d", get.Code)
+	}
+}
+
+func TestNewHandler_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+	}{
+		{"missing name", Rule{Effect: "buildFailed"}},
+		{"missing effect", Rule{Name: "r"}},
+		{"negative duration", Rule{Name: "r", Effect: "e", Duration: -1}},
+		{"bad regex", Rule{Name: "r", Effect: "e", Match: map[string]string{"$.a": "("}}},
+		{"bad path", Rule{Name: "r", Effect: "e", Match: map[string]string{"a.b": "x"}}},
+		{"bad param path", Rule{Name: "r", Effect: "e", Params: map[string]string{"p": "$.a["}}},
+	}
+	for _, tt := range tests {
+		if _, err := NewHandler(Config{Rules: []Rule{tt.rule}}, &fakePlayer{}, nil); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestLoad_SecretFromEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhook.json")
+	os.WriteFile(path, []byte(`{"rules":[{"name":"passed","effect":"buildPassed"}]}`), 0644)
+	t.Setenv("UFO_WEBHOOK_SECRET", "from-env")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if cfg.Secret != "from-env" || len(cfg.Rules) != 1 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+}